@@ -1,24 +1,78 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"time"
 
+	"devops-dashboard/internal/config"
 	"devops-dashboard/internal/docker"
 	"devops-dashboard/internal/ui/dashboard"
 )
 
 func main() {
+	doctor := flag.Bool("doctor", false, "check the Docker environment and exit")
+	host := flag.String("host", "", "configured host name or daemon URL, e.g. ssh://user@server")
+	kiosk := flag.Bool("kiosk", false, "read-only wall display: enlarged overview, no destructive keys")
+	kioskInterval := flag.Duration("kiosk-interval", 0, "how long kiosk mode shows each container (default 10s)")
+	sendDigest := flag.Bool("send-digest", false, "send the daily digest of the last 24h now and exit")
+	lowPower := flag.Bool("low-power", false, "longer intervals, no background stats and plain graphs, for small hosts like a Raspberry Pi")
+	flag.Parse()
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Config error: %v", err)
+	}
+	if *kiosk {
+		cfg.Kiosk = true
+	}
+	if *lowPower {
+		cfg.LowPower = true
+	}
+	if *kioskInterval != 0 {
+		if *kioskInterval < time.Second {
+			log.Fatalf("Flag error: --kiosk-interval must be at least 1s, got %s", *kioskInterval)
+		}
+		cfg.KioskInterval = kioskInterval.String()
+	}
+
+	endpoint, err := dashboard.SelectHost(cfg, *host)
+	if err != nil {
+		log.Fatalf("Host error: %v", err)
+	}
+	docker.SetEndpoint(endpoint)
+
+	if *doctor {
+		if !printDiagnostics(os.Stdout, docker.RunDiagnostics()) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *sendDigest {
+		headline, err := dashboard.SendDigest(cfg)
+		if err != nil {
+			log.Fatalf("Digest error: %v", err)
+		}
+		fmt.Println("Sent", headline)
+		return
+	}
+
 	fmt.Println("Starting DevOps Dashboard...")
 
 	// Check Docker
-	err := docker.CheckDockerConnection()
-	if err != nil {
-		log.Fatalf("Docker error: %v", err)
+	if err := docker.CheckDockerConnection(); err != nil {
+		fmt.Fprintf(os.Stderr, "Docker error: %v\n\n", err)
+		printDiagnostics(os.Stderr, docker.RunDiagnostics())
+		os.Exit(1)
 	}
 
 	// Start UI
-	app, err := dashboard.NewDashboardUI()
+	app, err := dashboard.NewDashboardUI(cfg)
 	if err != nil {
 		log.Fatalf("UI error: %v", err)
 	}
@@ -27,3 +81,26 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// printDiagnostics writes a doctor report and reports whether every check passed
+// or only warned
+func printDiagnostics(w io.Writer, checks []docker.Check) bool {
+	symbols := map[docker.CheckStatus]string{
+		docker.CheckOK:   "✔",
+		docker.CheckWarn: "⚠",
+		docker.CheckFail: "✖",
+	}
+
+	ok := true
+	fmt.Fprintln(w, "DockPulse doctor")
+	for _, c := range checks {
+		fmt.Fprintf(w, "  %s %-16s %s\n", symbols[c.Status], c.Name, c.Detail)
+		if c.Fix != "" {
+			fmt.Fprintf(w, "    → %s\n", c.Fix)
+		}
+		if c.Status == docker.CheckFail {
+			ok = false
+		}
+	}
+	return ok
+}
@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: dockpulse.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DockPulse_StreamStats_FullMethodName  = "/dockpulse.v1.DockPulse/StreamStats"
+	DockPulse_StreamEvents_FullMethodName = "/dockpulse.v1.DockPulse/StreamEvents"
+)
+
+// DockPulseClient is the client API for DockPulse service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DockPulse streams what a running dashboard sees across all of its hosts, so other
+// tools can subscribe instead of scraping the daemons themselves.
+type DockPulseClient interface {
+	// StreamStats sends the latest sample of every monitored container at an interval
+	StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerStats], error)
+	// StreamEvents sends daemon events as the dashboard receives them
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type dockPulseClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDockPulseClient(cc grpc.ClientConnInterface) DockPulseClient {
+	return &dockPulseClient{cc}
+}
+
+func (c *dockPulseClient) StreamStats(ctx context.Context, in *StreamStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerStats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DockPulse_ServiceDesc.Streams[0], DockPulse_StreamStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamStatsRequest, ContainerStats]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DockPulse_StreamStatsClient = grpc.ServerStreamingClient[ContainerStats]
+
+func (c *dockPulseClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DockPulse_ServiceDesc.Streams[1], DockPulse_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DockPulse_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// DockPulseServer is the server API for DockPulse service.
+// All implementations must embed UnimplementedDockPulseServer
+// for forward compatibility.
+//
+// DockPulse streams what a running dashboard sees across all of its hosts, so other
+// tools can subscribe instead of scraping the daemons themselves.
+type DockPulseServer interface {
+	// StreamStats sends the latest sample of every monitored container at an interval
+	StreamStats(*StreamStatsRequest, grpc.ServerStreamingServer[ContainerStats]) error
+	// StreamEvents sends daemon events as the dashboard receives them
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedDockPulseServer()
+}
+
+// UnimplementedDockPulseServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDockPulseServer struct{}
+
+func (UnimplementedDockPulseServer) StreamStats(*StreamStatsRequest, grpc.ServerStreamingServer[ContainerStats]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedDockPulseServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedDockPulseServer) mustEmbedUnimplementedDockPulseServer() {}
+func (UnimplementedDockPulseServer) testEmbeddedByValue()                   {}
+
+// UnsafeDockPulseServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DockPulseServer will
+// result in compilation errors.
+type UnsafeDockPulseServer interface {
+	mustEmbedUnimplementedDockPulseServer()
+}
+
+func RegisterDockPulseServer(s grpc.ServiceRegistrar, srv DockPulseServer) {
+	// If the following call pancis, it indicates UnimplementedDockPulseServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DockPulse_ServiceDesc, srv)
+}
+
+func _DockPulse_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DockPulseServer).StreamStats(m, &grpc.GenericServerStream[StreamStatsRequest, ContainerStats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DockPulse_StreamStatsServer = grpc.ServerStreamingServer[ContainerStats]
+
+func _DockPulse_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DockPulseServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DockPulse_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// DockPulse_ServiceDesc is the grpc.ServiceDesc for DockPulse service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DockPulse_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dockpulse.v1.DockPulse",
+	HandlerType: (*DockPulseServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStats",
+			Handler:       _DockPulse_StreamStats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _DockPulse_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dockpulse.proto",
+}
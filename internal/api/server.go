@@ -0,0 +1,237 @@
+package api
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative dockpulse.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/metrics"
+)
+
+// eventBuffer is how far a subscriber may fall behind the events before it is dropped
+const eventBuffer = 256
+
+// StatsSource returns the latest sample of every monitored container
+type StatsSource func(now time.Time) []metrics.Point
+
+// Server streams the stats and events a dashboard collects to gRPC clients
+type Server struct {
+	UnimplementedDockPulseServer
+
+	cfg   *config.API
+	stats StatsSource
+	grpc  *grpc.Server
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber is an open StreamEvents call
+type subscriber struct {
+	req     *StreamEventsRequest
+	events  chan *Event
+	dropped chan struct{} // closed when the subscriber fell too far behind
+}
+
+// New creates a server for the configured endpoint that takes stats from source
+func New(cfg *config.API, source StatsSource) (*Server, error) {
+	s := &Server{cfg: cfg, stats: source, subscribers: make(map[*subscriber]struct{})}
+
+	opts := []grpc.ServerOption{grpc.StreamInterceptor(s.authorize)}
+	if cfg.CertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("api: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	s.grpc = grpc.NewServer(opts...)
+	RegisterDockPulseServer(s.grpc, s)
+	return s, nil
+}
+
+// Serve listens on the configured address and serves until ctx is cancelled
+func (s *Server) Serve(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("api: %w", err)
+	}
+	go func() {
+		<-ctx.Done()
+		s.grpc.Stop()
+	}()
+	if err := s.grpc.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("api: %w", err)
+	}
+	return nil
+}
+
+// authorize rejects calls without the configured token
+func (s *Server) authorize(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	token := os.ExpandEnv(s.cfg.Token)
+	if token == "" {
+		return handler(srv, ss)
+	}
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	for _, v := range md.Get("authorization") {
+		if subtle.ConstantTimeCompare([]byte(v), []byte("Bearer "+token)) == 1 {
+			return handler(srv, ss)
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or wrong bearer token")
+}
+
+// StreamStats sends the latest samples right away and then at every interval
+func (s *Server) StreamStats(req *StreamStatsRequest, stream grpc.ServerStreamingServer[ContainerStats]) error {
+	interval := s.cfg.StatsInterval()
+	if req.Interval != nil {
+		interval = req.Interval.AsDuration()
+		if !req.Interval.IsValid() || interval < time.Second {
+			return status.Error(codes.InvalidArgument, "interval must be at least 1s")
+		}
+	}
+	if err := checkPatterns(req.Containers); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	now := time.Now()
+	for {
+		for _, p := range s.stats(now) {
+			if !matches(req.Containers, p.Name) || !included(req.Hosts, p.Host) {
+				continue
+			}
+			if err := stream.Send(statsMessage(p)); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case now = <-ticker.C:
+		}
+	}
+}
+
+// StreamEvents sends the events published from now on until the client goes away
+func (s *Server) StreamEvents(req *StreamEventsRequest, stream grpc.ServerStreamingServer[Event]) error {
+	sub := &subscriber{req: req, events: make(chan *Event, eventBuffer), dropped: make(chan struct{})}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	defer s.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-sub.dropped:
+			return status.Error(codes.ResourceExhausted, "client fell behind the event stream")
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Publish hands a daemon event to every subscriber that wants it. Subscribers that
+// stopped reading are dropped so they can't hold up the dashboard.
+func (s *Server) Publish(e docker.EventInfo) {
+	event := &Event{
+		Time:       timestamppb.New(e.Time),
+		Type:       e.Type,
+		Action:     e.Action,
+		ActorId:    e.ActorID,
+		Name:       e.Name,
+		Host:       e.Host,
+		Attributes: e.Attributes,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		if !included(sub.req.Types, e.Type) || !included(sub.req.Hosts, e.Host) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			close(sub.dropped)
+			delete(s.subscribers, sub)
+		}
+	}
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, sub)
+}
+
+func statsMessage(p metrics.Point) *ContainerStats {
+	return &ContainerStats{
+		Time:             timestamppb.New(p.Time),
+		Id:               p.ID,
+		Name:             p.Name,
+		Image:            p.Image,
+		Host:             p.Host,
+		CpuPercent:       p.CPUPerc,
+		MemPercent:       p.MemPerc,
+		MemUsageBytes:    p.MemUsage,
+		MemLimitBytes:    p.MemLimit,
+		NetRxBytes:       p.NetRx,
+		NetTxBytes:       p.NetTx,
+		BlockReadBytes:   p.BlockRead,
+		BlockWriteBytes:  p.BlockWrite,
+		ThrottledPeriods: p.Throttled,
+	}
+}
+
+// checkPatterns rejects malformed container name patterns
+func checkPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return status.Errorf(codes.InvalidArgument, "bad container pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a name matches one of the patterns; no patterns match all
+func matches(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// included reports whether a value is in the list; an empty list includes everything
+func included(list []string, value string) bool {
+	return len(list) == 0 || slices.Contains(list, value)
+}
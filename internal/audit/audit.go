@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is one line of the audit log: a container action, or a hook run around one
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	Container string    `json:"container"`
+	ID        string    `json:"id"`
+	Host      string    `json:"host,omitempty"`
+	Hook      string    `json:"hook,omitempty"` // "pre" or "post"; empty for the action itself
+	Command   string    `json:"command,omitempty"`
+	ExitCode  int       `json:"exit_code,omitempty"`
+	Output    string    `json:"output,omitempty"`
+	Error     string    `json:"error,omitempty"` // empty when it succeeded
+}
+
+// Log appends entries to a JSON lines file, which several DockPulse instances can share
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultPath returns the audit log location in the user cache directory
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "audit.log")
+}
+
+// Open returns the log at path; the file is created with the first entry
+func Open(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns where the log is written
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends an entry; a nil Log records nothing
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
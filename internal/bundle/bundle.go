@@ -0,0 +1,79 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultDir returns where bundles are written unless configured otherwise
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "bundles")
+}
+
+// File is one file of a support bundle
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Write stores files as name-YYYYMMDD-HHMMSS.tar.gz in dir, under a directory of the
+// same name so the bundle unpacks cleanly, and returns the path of the archive
+func Write(dir, name string, at time.Time, files []File) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create bundle directory: %w", err)
+	}
+
+	// Bundles taken within the same second get a counter
+	stamp := fmt.Sprintf("%s-%s", name, at.Format("20060102-150405"))
+	base, path := stamp, ""
+	var f *os.File
+	for n := 2; ; n++ {
+		path = filepath.Join(dir, base+".tar.gz")
+		var err error
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("create bundle: %w", err)
+		}
+		base = fmt.Sprintf("%s-%d", stamp, n)
+	}
+
+	zw := gzip.NewWriter(f)
+	tw := tar.NewWriter(zw)
+	err := writeFiles(tw, base, at, files)
+	err = errors.Join(err, tw.Close(), zw.Close(), f.Close())
+	if err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("write bundle: %w", err)
+	}
+	return path, nil
+}
+
+func writeFiles(tw *tar.Writer, base string, at time.Time, files []File) error {
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    base + "/" + file.Name,
+			Mode:    0o644,
+			Size:    int64(len(file.Data)),
+			ModTime: at,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1533 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/moby/term"
+)
+
+// Config holds user settings loaded from the DockPulse config file
+type Config struct {
+	Views  []View             `json:"views"`
+	Theme  string             `json:"theme,omitempty"`
+	Themes map[string]Palette `json:"themes,omitempty"`
+
+	// Keybindings maps action names to space separated keys, e.g. "restart": "r R"
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+
+	// BulkConcurrency is how many containers a bulk action works on at once (default 4)
+	BulkConcurrency int `json:"bulk_concurrency,omitempty"`
+
+	// StderrColor colors stderr lines in the log views, as a color name or #rrggbb;
+	// the theme's warning color by default, "none" shows them like stdout
+	StderrColor string `json:"stderr_color,omitempty"`
+
+	// GraphStyle is "blocks" (default) for sparklines or "braille" for high-resolution
+	// line charts in the statistics screen
+	GraphStyle string `json:"graph_style,omitempty"`
+
+	// HistoryRetention is how long sampled CPU and memory usage is kept on disk, as a Go
+	// duration such as "24h" (the default) or "off" to disable recording
+	HistoryRetention string `json:"history_retention,omitempty"`
+	// HistoryFile overrides where the history is stored
+	HistoryFile string `json:"history_file,omitempty"`
+	// ForecastHorizon warns about containers whose memory usage, going by the history,
+	// will reach their limit within this Go duration: "24h" (the default) or "off"
+	ForecastHorizon string `json:"forecast_horizon,omitempty"`
+
+	// Export pushes container metrics to InfluxDB or a Prometheus remote-write endpoint
+	Export *Export `json:"export,omitempty"`
+
+	// API serves the collected stats and daemon events as gRPC streams
+	API *API `json:"api,omitempty"`
+
+	// Digest sends a daily summary of the history by webhook or email
+	Digest *Digest `json:"digest,omitempty"`
+
+	// LogLevels overrides how log lines are classified as error, warning, info or debug
+	LogLevels *LogLevels `json:"log_levels,omitempty"`
+
+	// LogArchive configures writing container logs to rotating files
+	LogArchive *LogArchive `json:"log_archive,omitempty"`
+
+	// LogShipping sends the logs of selected containers to syslog, Loki or HTTP endpoints
+	LogShipping *LogShipping `json:"log_shipping,omitempty"`
+
+	// ShellHistory is where shell commands are remembered across sessions: "container"
+	// (default) keeps one history per container name, "global" one for all, "off" none
+	ShellHistory string `json:"shell_history,omitempty"`
+	// ShellHistorySize is how many commands a history keeps (default 1000)
+	ShellHistorySize int `json:"shell_history_size,omitempty"`
+	// ShellTerminal opens interactive shells in a new tmux "window" or "pane", or a new
+	// GNU screen window, with a full terminal when DockPulse runs inside one; the
+	// built-in shell is used otherwise
+	ShellTerminal string `json:"shell_terminal,omitempty"`
+	// DetachKeys is the key sequence that leaves a container attached to, like docker's
+	// --detach-keys (default "ctrl-p,ctrl-q")
+	DetachKeys string `json:"detach_keys,omitempty"`
+
+	// Kiosk runs DockPulse as a read-only wall display (also --kiosk)
+	Kiosk bool `json:"kiosk,omitempty"`
+	// KioskInterval is how long kiosk mode shows each container, as a Go duration
+	// (default 10s)
+	KioskInterval string `json:"kiosk_interval,omitempty"`
+
+	// LowPower keeps DockPulse's own CPU use down on small hosts such as a Raspberry Pi:
+	// longer intervals, no background stats of every container and plain graphs (also
+	// --low-power)
+	LowPower bool `json:"low_power,omitempty"`
+
+	// BundleDir is where support bundles are written, the user cache directory by default
+	BundleDir string `json:"bundle_dir,omitempty"`
+	// DownloadDir is where files downloaded from volumes and exported reports are
+	// written, ~/Downloads by default
+	DownloadDir string `json:"download_dir,omitempty"`
+
+	// Trash commits containers to a rescue image before deleting them, so they can be
+	// restored from the "Recently Deleted" screen
+	Trash bool `json:"trash,omitempty"`
+	// TrashFile overrides where deleted containers are recorded
+	TrashFile string `json:"trash_file,omitempty"`
+
+	// NotesFile overrides where the notes attached to containers are kept
+	NotesFile string `json:"notes_file,omitempty"`
+
+	// Protected containers are only stopped, restarted or deleted after typing a
+	// confirmation phrase
+	Protected *Protected `json:"protected,omitempty"`
+	// SecretEnv are parts of env var names, matched ignoring case, whose values are
+	// masked on screen until revealed (default PASSWORD, TOKEN, KEY and SECRET)
+	SecretEnv []string `json:"secret_env,omitempty"`
+	// MutableTags are the image tags the pinning audit reports as moving (default
+	// latest, main, master, develop, dev, edge, nightly and stable)
+	MutableTags []string `json:"mutable_tags,omitempty"`
+
+	// StaleAfterDays is how long a container has to be exited before the cleanup screen
+	// lists it (default 7)
+	StaleAfterDays int `json:"stale_after_days,omitempty"`
+
+	// CrashLoop sets when a container counts as crash-looping
+	CrashLoop *CrashLoop `json:"crash_loop,omitempty"`
+
+	// Probes check containers over HTTP or TCP from the DockPulse host
+	Probes []HealthProbe `json:"probes,omitempty"`
+
+	// Remediation restarts listed containers that keep failing their healthcheck or probes
+	Remediation *Remediation `json:"remediation,omitempty"`
+
+	// StartProfiles start containers group by group, e.g. db, then cache, then app
+	StartProfiles []StartProfile `json:"start_profiles,omitempty"`
+
+	// AutoFollow opens the logs of, or pins, containers matching a rule as soon as they
+	// are created, e.g. the task containers of a deployment
+	AutoFollow []AutoFollow `json:"auto_follow,omitempty"`
+
+	// Plugins are external programs offered as container actions, e.g. a smoke test
+	Plugins []Plugin `json:"plugins,omitempty"`
+
+	// Hooks run shell commands on this machine before and after container actions
+	Hooks []Hook `json:"hooks,omitempty"`
+	// AuditLog is where container actions and the output of their hooks are recorded,
+	// the user cache directory by default; "off" disables it
+	AuditLog string `json:"audit_log,omitempty"`
+
+	// Hosts are the Docker daemons to choose from; the first one is used unless --host
+	// says otherwise. Without hosts, DOCKER_HOST and the other Docker variables apply.
+	Hosts []Host `json:"hosts,omitempty"`
+
+	// Registries are private registries whose repositories and tags can be browsed and
+	// pulled from the Images tab
+	Registries []Registry `json:"registries,omitempty"`
+}
+
+// History returns how long to keep sampled stats, and whether recording is enabled
+func (c *Config) History() (time.Duration, bool) {
+	switch c.HistoryRetention {
+	case "":
+		return defaultHistoryRetention, true
+	case "off":
+		return 0, false
+	}
+	d, _ := time.ParseDuration(c.HistoryRetention)
+	return d, true
+}
+
+// defaultHistoryRetention keeps a day of stats history
+const defaultHistoryRetention = 24 * time.Hour
+
+// Forecast returns how far ahead memory growth is warned about, and whether it is
+func (c *Config) Forecast() (time.Duration, bool) {
+	switch c.ForecastHorizon {
+	case "":
+		return defaultForecastHorizon, true
+	case "off":
+		return 0, false
+	}
+	d, _ := time.ParseDuration(c.ForecastHorizon)
+	return d, true
+}
+
+// defaultForecastHorizon warns about memory limits reached within a day
+const defaultForecastHorizon = 24 * time.Hour
+
+// KioskRotation returns how long kiosk mode shows each container
+func (c *Config) KioskRotation() time.Duration {
+	d, err := time.ParseDuration(c.KioskInterval)
+	if err != nil {
+		return defaultKioskInterval
+	}
+	return d
+}
+
+// StaleAfter returns how long a container has to be exited to count as left behind
+func (c *Config) StaleAfter() time.Duration {
+	days := c.StaleAfterDays
+	if days == 0 {
+		days = defaultStaleAfterDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// defaultStaleAfterDays is how many days an exited container is kept out of cleanup
+const defaultStaleAfterDays = 7
+
+// defaultKioskInterval is how long kiosk mode shows each container by default
+const defaultKioskInterval = 10 * time.Second
+
+// Export configures where container metrics are pushed. Secrets may reference
+// environment variables, e.g. "token": "${INFLUX_TOKEN}".
+type Export struct {
+	// Interval between pushes as a Go duration (default 15s)
+	Interval string `json:"interval,omitempty"`
+
+	InfluxDB   *InfluxDB    `json:"influxdb,omitempty"`
+	Prometheus *RemoteWrite `json:"prometheus,omitempty"`
+
+	interval time.Duration
+}
+
+// InfluxDB is an InfluxDB 2.x bucket (Org, Bucket and Token) or 1.x database (Database,
+// optionally Username and Password)
+type InfluxDB struct {
+	URL      string `json:"url"`
+	Org      string `json:"org,omitempty"`
+	Bucket   string `json:"bucket,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Database string `json:"database,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// RemoteWrite is a Prometheus remote-write endpoint, e.g. Prometheus with
+// --web.enable-remote-write-receiver, Mimir or VictoriaMetrics
+type RemoteWrite struct {
+	URL         string `json:"url"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearer_token,omitempty"`
+}
+
+// defaultExportInterval is how often metrics are pushed
+const defaultExportInterval = 15 * time.Second
+
+// PushInterval returns the time between pushes
+func (e *Export) PushInterval() time.Duration {
+	if e.interval == 0 {
+		return defaultExportInterval
+	}
+	return e.interval
+}
+
+func (e *Export) validate() error {
+	if e.Interval != "" {
+		d, err := time.ParseDuration(e.Interval)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("export: interval must be a duration of at least 1s, got %q", e.Interval)
+		}
+		e.interval = d
+	}
+	if e.InfluxDB == nil && e.Prometheus == nil {
+		return errors.New("export: configure influxdb or prometheus")
+	}
+	if i := e.InfluxDB; i != nil {
+		if !strings.HasPrefix(i.URL, "http://") && !strings.HasPrefix(i.URL, "https://") {
+			return fmt.Errorf("export: influxdb url must start with http:// or https://, got %q", i.URL)
+		}
+		if (i.Bucket == "") == (i.Database == "") {
+			return errors.New("export: set either bucket (InfluxDB 2.x) or database (InfluxDB 1.x) for influxdb")
+		}
+	}
+	if p := e.Prometheus; p != nil {
+		if !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+			return fmt.Errorf("export: prometheus url must start with http:// or https://, got %q", p.URL)
+		}
+		if p.BearerToken != "" && p.Username != "" {
+			return errors.New("export: use either bearer_token or username/password for prometheus")
+		}
+	}
+	return nil
+}
+
+// API is a gRPC endpoint streaming container stats and daemon events to other tools.
+// The token may reference environment variables, e.g. "token": "${DOCKPULSE_TOKEN}".
+type API struct {
+	// Listen is the address to serve on, e.g. "127.0.0.1:7070"
+	Listen string `json:"listen"`
+	// Token is required from clients as "authorization: Bearer <token>" metadata
+	Token string `json:"token,omitempty"`
+	// CertFile and KeyFile serve over TLS instead of plain text
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	// Interval between stats samples for clients that don't ask for one (default 5s)
+	Interval string `json:"interval,omitempty"`
+
+	interval time.Duration
+}
+
+// defaultAPIInterval is how often stats are streamed by default
+const defaultAPIInterval = 5 * time.Second
+
+// StatsInterval returns the default time between streamed stats samples
+func (a *API) StatsInterval() time.Duration {
+	if a.interval == 0 {
+		return defaultAPIInterval
+	}
+	return a.interval
+}
+
+func (a *API) validate() error {
+	if _, _, err := net.SplitHostPort(a.Listen); err != nil {
+		return fmt.Errorf("api: listen must be host:port, got %q", a.Listen)
+	}
+	if (a.CertFile == "") != (a.KeyFile == "") {
+		return errors.New("api: set both cert_file and key_file for TLS")
+	}
+	if a.Interval != "" {
+		d, err := time.ParseDuration(a.Interval)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("api: interval must be a duration of at least 1s, got %q", a.Interval)
+		}
+		a.interval = d
+	}
+	return nil
+}
+
+// Digest is a daily summary of what the history recorded: restarts, image updates, the
+// change in disk usage and alerts. Secrets may reference environment variables, e.g.
+// "password": "${SMTP_PASSWORD}".
+type Digest struct {
+	// At is the local time of day to send it, as "15:04" (default "08:00")
+	At string `json:"at,omitempty"`
+
+	Webhook *Webhook `json:"webhook,omitempty"`
+	SMTP    *SMTP    `json:"smtp,omitempty"`
+
+	hour, minute int
+}
+
+// Webhook receives the digest as a JSON post whose "text" field is the plain text
+// summary, which Slack, Mattermost and Teams incoming webhooks show as is
+type Webhook struct {
+	URL         string            `json:"url"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// SMTP is a mail server to send the digest through. Port 465 uses TLS from the start;
+// other ports upgrade with STARTTLS when the server offers it.
+type SMTP struct {
+	// Address of the server as host:port
+	Address  string   `json:"address"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// defaultDigestHour is when the digest is sent by default
+const defaultDigestHour = 8
+
+// SendAt returns the local time of day the digest is sent
+func (d *Digest) SendAt() (hour, minute int) {
+	if d.At == "" {
+		return defaultDigestHour, 0
+	}
+	return d.hour, d.minute
+}
+
+func (d *Digest) validate() error {
+	if d.At != "" {
+		t, err := time.Parse("15:04", d.At)
+		if err != nil {
+			return fmt.Errorf("digest: at must be a time of day like \"08:00\", got %q", d.At)
+		}
+		d.hour, d.minute = t.Hour(), t.Minute()
+	}
+	if d.Webhook == nil && d.SMTP == nil {
+		return errors.New("digest: configure webhook or smtp")
+	}
+	if w := d.Webhook; w != nil {
+		if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			return fmt.Errorf("digest: webhook url must start with http:// or https://, got %q", w.URL)
+		}
+	}
+	if m := d.SMTP; m != nil {
+		if _, _, err := net.SplitHostPort(m.Address); err != nil {
+			return fmt.Errorf("digest: smtp address must be host:port, got %q", m.Address)
+		}
+		if m.From == "" || len(m.To) == 0 {
+			return errors.New("digest: smtp needs from and to")
+		}
+	}
+	return nil
+}
+
+// LevelPatterns are regular expressions recognising log levels; empty ones keep the
+// built-in pattern
+type LevelPatterns struct {
+	Error string `json:"error,omitempty"`
+	Warn  string `json:"warn,omitempty"`
+	Info  string `json:"info,omitempty"`
+	Debug string `json:"debug,omitempty"`
+}
+
+// LogLevels holds the level patterns for all containers and overrides for containers
+// whose image matches a glob such as "nginx", "nginx:1.*" or "ghcr.io/acme/*"
+type LogLevels struct {
+	LevelPatterns
+	Images map[string]LevelPatterns `json:"images,omitempty"`
+}
+
+func (l *LogLevels) validate() error {
+	check := func(where string, p LevelPatterns) error {
+		for _, pattern := range []struct{ level, re string }{
+			{"error", p.Error}, {"warn", p.Warn}, {"info", p.Info}, {"debug", p.Debug},
+		} {
+			if _, err := regexp.Compile(pattern.re); err != nil {
+				return fmt.Errorf("log_levels: invalid %s pattern%s: %v", pattern.level, where, err)
+			}
+		}
+		return nil
+	}
+
+	if err := check("", l.LevelPatterns); err != nil {
+		return err
+	}
+	for image, p := range l.Images {
+		if _, err := path.Match(image, ""); err != nil {
+			return fmt.Errorf("log_levels: invalid image pattern %q: %v", image, err)
+		}
+		if err := check(fmt.Sprintf(" for image %q", image), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CrashLoop flags containers that die Restarts times within Window
+type CrashLoop struct {
+	Restarts int    `json:"restarts,omitempty"` // default 3
+	Window   string `json:"window,omitempty"`   // Go duration, default 5m
+}
+
+const (
+	defaultCrashLoopRestarts = 3
+	defaultCrashLoopWindow   = 5 * time.Minute
+)
+
+// Threshold returns how many deaths within which window make a crash loop; it works
+// on a nil CrashLoop too
+func (c *CrashLoop) Threshold() (int, time.Duration) {
+	restarts, window := defaultCrashLoopRestarts, defaultCrashLoopWindow
+	if c == nil {
+		return restarts, window
+	}
+	if c.Restarts > 0 {
+		restarts = c.Restarts
+	}
+	if d, err := time.ParseDuration(c.Window); err == nil {
+		window = d
+	}
+	return restarts, window
+}
+
+func (c *CrashLoop) validate() error {
+	if c.Restarts < 0 || c.Restarts == 1 {
+		return fmt.Errorf("crash_loop.restarts must be at least 2, got %d", c.Restarts)
+	}
+	if c.Window != "" {
+		d, err := time.ParseDuration(c.Window)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("crash_loop.window must be a positive duration like \"5m\", got %q", c.Window)
+		}
+	}
+	return nil
+}
+
+// HealthProbe checks a container from the DockPulse host every Interval, with an HTTP
+// GET of URL or a TCP connect to TCP
+type HealthProbe struct {
+	// Container is the container name, or host/name for a container on another host
+	Container    string `json:"container"`
+	URL          string `json:"url,omitempty"`
+	TCP          string `json:"tcp,omitempty"`           // host:port
+	Interval     string `json:"interval,omitempty"`      // Go duration, default 30s
+	Timeout      string `json:"timeout,omitempty"`       // Go duration, default 5s
+	ExpectStatus int    `json:"expect_status,omitempty"` // default any 2xx or 3xx
+	// Failures is how many checks in a row have to fail before an alert (default 1)
+	Failures int `json:"failures,omitempty"`
+
+	interval time.Duration
+	timeout  time.Duration
+}
+
+const (
+	defaultProbeInterval = 30 * time.Second
+	defaultProbeTimeout  = 5 * time.Second
+)
+
+// Every returns how often the probe runs
+func (p *HealthProbe) Every() time.Duration {
+	if p.interval == 0 {
+		return defaultProbeInterval
+	}
+	return p.interval
+}
+
+// Deadline returns how long a check may take before it counts as failed
+func (p *HealthProbe) Deadline() time.Duration {
+	if p.timeout == 0 {
+		return defaultProbeTimeout
+	}
+	return p.timeout
+}
+
+// FailureThreshold returns how many failed checks in a row raise an alert
+func (p *HealthProbe) FailureThreshold() int {
+	if p.Failures == 0 {
+		return 1
+	}
+	return p.Failures
+}
+
+// Target returns the URL or address the probe checks
+func (p *HealthProbe) Target() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return p.TCP
+}
+
+func (p *HealthProbe) validate() error {
+	if p.Container == "" {
+		return errors.New("probe has no container")
+	}
+	if (p.URL == "") == (p.TCP == "") {
+		return fmt.Errorf("probe for %s: set either url or tcp", p.Container)
+	}
+	if p.URL != "" && !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+		return fmt.Errorf("probe for %s: url must start with http:// or https://, got %q", p.Container, p.URL)
+	}
+	if p.TCP != "" {
+		if _, port, err := net.SplitHostPort(p.TCP); err != nil || port == "" {
+			return fmt.Errorf("probe for %s: tcp must be host:port, got %q", p.Container, p.TCP)
+		}
+	}
+	if p.Interval != "" {
+		d, err := time.ParseDuration(p.Interval)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("probe for %s: interval must be a duration of at least 1s, got %q", p.Container, p.Interval)
+		}
+		p.interval = d
+	}
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("probe for %s: timeout must be a positive duration like \"5s\", got %q", p.Container, p.Timeout)
+		}
+		p.timeout = d
+	}
+	if p.ExpectStatus != 0 && (p.ExpectStatus < 100 || p.ExpectStatus > 599) {
+		return fmt.Errorf("probe for %s: expect_status must be an HTTP status, got %d", p.Container, p.ExpectStatus)
+	}
+	if p.ExpectStatus != 0 && p.TCP != "" {
+		return fmt.Errorf("probe for %s: expect_status only applies to url probes", p.Container)
+	}
+	if p.Failures < 0 {
+		return fmt.Errorf("probe for %s: failures must be positive, got %d", p.Container, p.Failures)
+	}
+	return nil
+}
+
+// Remediation restarts a container once its Docker healthcheck, or one of its probes,
+// failed Failures times in a row. Only the listed containers are restarted.
+type Remediation struct {
+	// Containers are the names, or host/name, of the containers to restart
+	Containers []string `json:"containers"`
+	Failures   int      `json:"failures,omitempty"` // default 3
+	// Cooldown is the least time between two restarts of a container, as a Go duration
+	// (default 5m), so one that never recovers isn't restarted over and over
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+const (
+	defaultRemediationFailures = 3
+	defaultRemediationCooldown = 5 * time.Minute
+)
+
+// Policy returns after how many failures in a row a container is restarted, and how
+// long to wait before restarting it again; it works on a nil Remediation too
+func (r *Remediation) Policy() (int, time.Duration) {
+	failures, cooldown := defaultRemediationFailures, defaultRemediationCooldown
+	if r == nil {
+		return failures, cooldown
+	}
+	if r.Failures > 0 {
+		failures = r.Failures
+	}
+	if d, err := time.ParseDuration(r.Cooldown); err == nil {
+		cooldown = d
+	}
+	return failures, cooldown
+}
+
+func (r *Remediation) validate() error {
+	if len(r.Containers) == 0 {
+		return errors.New("remediation: list the containers to restart")
+	}
+	for _, name := range r.Containers {
+		if name == "" {
+			return errors.New("remediation: empty container name")
+		}
+	}
+	if r.Failures < 0 {
+		return fmt.Errorf("remediation.failures must be positive, got %d", r.Failures)
+	}
+	if r.Cooldown != "" {
+		d, err := time.ParseDuration(r.Cooldown)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("remediation.cooldown must be a duration of at least 1m, got %q", r.Cooldown)
+		}
+	}
+	return nil
+}
+
+// Plugin is an external program run for a container from the plugins menu. It runs on
+// this machine with the container in DOCKPULSE_* variables and DOCKER_HOST pointing at
+// its daemon; the output is shown in the dashboard.
+type Plugin struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Command is the program and its arguments; {id}, {name} and {image} are replaced
+	// with those of the container
+	Command []string `json:"command"`
+	// Containers are the container names the plugin is offered for; glob patterns such
+	// as "api-*" work. Empty offers it for every container.
+	Containers []string `json:"containers,omitempty"`
+	Timeout    string   `json:"timeout,omitempty"` // Go duration, default 60s
+	// Refresh runs the command again at this interval while its output is open, which
+	// turns it into a live panel (Go duration)
+	Refresh string `json:"refresh,omitempty"`
+	// Terminal hands the terminal over to the program, e.g. lazydocker or dive, and
+	// brings the dashboard back when it exits; its output is not captured
+	Terminal bool `json:"terminal,omitempty"`
+
+	timeout time.Duration
+	refresh time.Duration
+}
+
+// defaultPluginTimeout is how long a plugin may run by default
+const defaultPluginTimeout = 60 * time.Second
+
+// Deadline returns how long a run may take before it is killed
+func (p *Plugin) Deadline() time.Duration {
+	if p.timeout == 0 {
+		return defaultPluginTimeout
+	}
+	return p.timeout
+}
+
+// Every returns how often an open plugin panel runs again, or 0 to run it once
+func (p *Plugin) Every() time.Duration {
+	return p.refresh
+}
+
+// Matches reports whether the plugin is offered for a container name
+func (p *Plugin) Matches(name string) bool {
+	if len(p.Containers) == 0 {
+		return true
+	}
+	for _, pattern := range p.Containers {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) validate() error {
+	if p.Name == "" {
+		return errors.New("plugin has no name")
+	}
+	if len(p.Command) == 0 || p.Command[0] == "" {
+		return fmt.Errorf("plugin %q has no command", p.Name)
+	}
+	for _, pattern := range p.Containers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("plugin %q: invalid container pattern %q: %v", p.Name, pattern, err)
+		}
+	}
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("plugin %q: timeout must be a positive duration like \"30s\", got %q", p.Name, p.Timeout)
+		}
+		p.timeout = d
+	}
+	if p.Refresh != "" {
+		d, err := time.ParseDuration(p.Refresh)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("plugin %q: refresh must be a duration of at least 1s, got %q", p.Name, p.Refresh)
+		}
+		p.refresh = d
+	}
+	if p.Terminal && (p.Timeout != "" || p.Refresh != "") {
+		return fmt.Errorf("plugin %q: terminal plugins run until they exit and take no timeout or refresh", p.Name)
+	}
+	return nil
+}
+
+// Hook runs shell commands around container actions, e.g. to notify a deploy channel
+// after a restart or snapshot a volume before a delete. Commands run with sh -c (cmd
+// on Windows) and find the container in DOCKPULSE_* variables.
+type Hook struct {
+	// Actions are the actions the hook runs around: start, stop, restart, pause,
+	// unpause or delete
+	Actions []string `json:"actions"`
+	// Containers are the container names the hook applies to; glob patterns such as
+	// "db-*" work. Empty applies it to every container.
+	Containers []string `json:"containers,omitempty"`
+	// Pre runs before the action; when it fails, the action is cancelled
+	Pre string `json:"pre,omitempty"`
+	// Post runs after the action, succeeded or not, which DOCKPULSE_RESULT says
+	Post    string `json:"post,omitempty"`
+	Timeout string `json:"timeout,omitempty"` // Go duration, default 30s
+
+	timeout time.Duration
+}
+
+// HookActions are the container actions hooks can run around
+var HookActions = []string{"start", "stop", "restart", "pause", "unpause", "delete"}
+
+// defaultHookTimeout is how long a hook command may run by default
+const defaultHookTimeout = 30 * time.Second
+
+// Deadline returns how long a hook command may run before it is killed
+func (h *Hook) Deadline() time.Duration {
+	if h.timeout == 0 {
+		return defaultHookTimeout
+	}
+	return h.timeout
+}
+
+// Matches reports whether the hook runs around an action on a container
+func (h *Hook) Matches(action, name string) bool {
+	if !slices.Contains(h.Actions, action) {
+		return false
+	}
+	if len(h.Containers) == 0 {
+		return true
+	}
+	for _, pattern := range h.Containers {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hook) validate(index int) error {
+	if len(h.Actions) == 0 {
+		return fmt.Errorf("hook #%d has no actions", index+1)
+	}
+	for _, a := range h.Actions {
+		if !slices.Contains(HookActions, a) {
+			return fmt.Errorf("hook #%d: unknown action %q (use %s)", index+1, a, strings.Join(HookActions, ", "))
+		}
+	}
+	if h.Pre == "" && h.Post == "" {
+		return fmt.Errorf("hook #%d: set pre, post or both", index+1)
+	}
+	for _, pattern := range h.Containers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("hook #%d: invalid container pattern %q: %v", index+1, pattern, err)
+		}
+	}
+	if h.Timeout != "" {
+		d, err := time.ParseDuration(h.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("hook #%d: timeout must be a positive duration like \"30s\", got %q", index+1, h.Timeout)
+		}
+		h.timeout = d
+	}
+	return nil
+}
+
+// StartProfile is a named startup order. Its groups start one after another; the
+// containers within a group start together.
+type StartProfile struct {
+	Name   string       `json:"name"`
+	Groups []StartGroup `json:"groups"`
+}
+
+// StartGroup is one step of a start profile. The next group only starts once every
+// container of this one satisfies Wait.
+type StartGroup struct {
+	Name       string   `json:"name,omitempty"`
+	Containers []string `json:"containers"`
+	// Wait is "running" (default), "healthy" (running without a healthcheck) or "none"
+	Wait string `json:"wait,omitempty"`
+	// Timeout bounds the wait as a Go duration (default 60s)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Start profile wait conditions
+const (
+	WaitRunning = "running"
+	WaitHealthy = "healthy"
+	WaitNone    = "none"
+)
+
+// defaultStartTimeout is how long a start group waits for its containers by default
+const defaultStartTimeout = 60 * time.Second
+
+// WaitCondition returns the condition the group waits for
+func (g *StartGroup) WaitCondition() string {
+	if g.Wait == "" {
+		return WaitRunning
+	}
+	return g.Wait
+}
+
+// WaitTimeout returns how long the group waits for its containers
+func (g *StartGroup) WaitTimeout() time.Duration {
+	d, err := time.ParseDuration(g.Timeout)
+	if err != nil {
+		return defaultStartTimeout
+	}
+	return d
+}
+
+func (p *StartProfile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("start profile has no name")
+	}
+	if len(p.Groups) == 0 {
+		return fmt.Errorf("start profile %q has no groups", p.Name)
+	}
+	for i, g := range p.Groups {
+		if len(g.Containers) == 0 {
+			return fmt.Errorf("start profile %q: group #%d has no containers", p.Name, i+1)
+		}
+		switch g.Wait {
+		case "", WaitRunning, WaitHealthy, WaitNone:
+		default:
+			return fmt.Errorf("start profile %q: group #%d: wait must be %q, %q or %q, got %q",
+				p.Name, i+1, WaitRunning, WaitHealthy, WaitNone, g.Wait)
+		}
+		if g.Timeout != "" {
+			d, err := time.ParseDuration(g.Timeout)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("start profile %q: group #%d: timeout must be a positive duration like \"2m\", got %q",
+					p.Name, i+1, g.Timeout)
+			}
+		}
+	}
+	return nil
+}
+
+// Protected selects containers that need a typed confirmation for destructive actions.
+// A container is protected when its name or ID is listed or it carries all the labels;
+// an empty label value matches any value.
+type Protected struct {
+	Containers []string          `json:"containers,omitempty"` // names, or IDs of at least 12 characters
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// minProtectedID is the shortest ID prefix that protects a container, the length
+// docker ps shows
+const minProtectedID = 12
+
+// Matches reports whether a container is protected
+func (p *Protected) Matches(id, name string, labels map[string]string) bool {
+	name = strings.TrimPrefix(name, "/")
+	for _, n := range p.Containers {
+		if strings.TrimPrefix(n, "/") == name || (len(n) >= minProtectedID && strings.HasPrefix(id, n)) {
+			return true
+		}
+	}
+	if len(p.Labels) == 0 {
+		return false
+	}
+	for k, v := range p.Labels {
+		got, ok := labels[k]
+		if !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Protected) validate() error {
+	for _, n := range p.Containers {
+		if strings.TrimSpace(n) == "" {
+			return fmt.Errorf("protected.containers must not contain empty names")
+		}
+	}
+	for k := range p.Labels {
+		if k == "" {
+			return fmt.Errorf("protected.labels must not contain an empty key")
+		}
+	}
+	return nil
+}
+
+// AutoFollow picks the new containers to follow by name, labels or both
+type AutoFollow struct {
+	// Name is a glob pattern for the container name, e.g. "api-*"
+	Name string `json:"name,omitempty"`
+	// Labels must all be set on the container; an empty value only needs the key
+	Labels map[string]string `json:"labels,omitempty"`
+	// Action is "logs" (default) to open the container's logs once it starts, or "pin"
+	// to keep it at the top of the container list
+	Action string `json:"action,omitempty"`
+}
+
+// Auto-follow actions
+const (
+	FollowLogs = "logs"
+	FollowPin  = "pin"
+)
+
+// Do returns what happens to a matching container
+func (a *AutoFollow) Do() string {
+	if a.Action == "" {
+		return FollowLogs
+	}
+	return a.Action
+}
+
+// Matches reports whether a new container is followed
+func (a *AutoFollow) Matches(name string, labels map[string]string) bool {
+	if a.Name != "" {
+		if ok, _ := path.Match(a.Name, strings.TrimPrefix(name, "/")); !ok {
+			return false
+		}
+	}
+	for k, v := range a.Labels {
+		got, ok := labels[k]
+		if !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks a rule, also one typed in at runtime
+func (a *AutoFollow) Validate() error {
+	if a.Name == "" && len(a.Labels) == 0 {
+		return errors.New("set a name pattern, labels or both")
+	}
+	if _, err := path.Match(a.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %v", a.Name, err)
+	}
+	for k := range a.Labels {
+		if k == "" {
+			return errors.New("labels must not contain an empty key")
+		}
+	}
+	switch a.Do() {
+	case FollowLogs, FollowPin:
+	default:
+		return fmt.Errorf("unknown action %q (use logs or pin)", a.Action)
+	}
+	return nil
+}
+
+// LogArchive configures where archived container logs are written and when the files
+// are rotated. Rotated files are gzipped.
+type LogArchive struct {
+	// Dir holds the archives; defaults to dockpulse/logs in the user cache directory
+	Dir string `json:"dir,omitempty"`
+	// MaxSize rotates a file once it reaches this size, e.g. "100m" (default 50m)
+	MaxSize string `json:"max_size,omitempty"`
+	// MaxAge rotates a file once it is this old as a Go duration, e.g. "24h" (default)
+	MaxAge string `json:"max_age,omitempty"`
+	// Keep is the number of rotated files kept per container (default 7)
+	Keep int `json:"keep,omitempty"`
+	// Containers are archived from startup, by name
+	Containers []string `json:"containers,omitempty"`
+
+	maxSize int64
+	maxAge  time.Duration
+}
+
+const (
+	defaultArchiveMaxSize = 50 << 20
+	defaultArchiveMaxAge  = 24 * time.Hour
+	defaultArchiveKeep    = 7
+)
+
+// RotateSize returns the size in bytes at which archives are rotated
+func (a *LogArchive) RotateSize() int64 {
+	if a == nil || a.maxSize == 0 {
+		return defaultArchiveMaxSize
+	}
+	return a.maxSize
+}
+
+// RotateAge returns the age at which archives are rotated
+func (a *LogArchive) RotateAge() time.Duration {
+	if a == nil || a.maxAge == 0 {
+		return defaultArchiveMaxAge
+	}
+	return a.maxAge
+}
+
+// KeepFiles returns the number of rotated files kept per container
+func (a *LogArchive) KeepFiles() int {
+	if a == nil || a.Keep == 0 {
+		return defaultArchiveKeep
+	}
+	return a.Keep
+}
+
+func (a *LogArchive) validate() error {
+	if a.MaxSize != "" {
+		n, err := units.RAMInBytes(a.MaxSize)
+		if err != nil || n < 1<<20 {
+			return fmt.Errorf("log_archive: max_size must be a size of at least 1m, got %q", a.MaxSize)
+		}
+		a.maxSize = n
+	}
+	if a.MaxAge != "" {
+		d, err := time.ParseDuration(a.MaxAge)
+		if err != nil || d < time.Minute {
+			return fmt.Errorf("log_archive: max_age must be a duration of at least 1m, got %q", a.MaxAge)
+		}
+		a.maxAge = d
+	}
+	if a.Keep < 0 {
+		return fmt.Errorf("log_archive: keep must not be negative, got %d", a.Keep)
+	}
+	return nil
+}
+
+// Log shipping target types
+const (
+	TargetSyslog = "syslog"
+	TargetLoki   = "loki"
+	TargetHTTP   = "http"
+)
+
+// LogShipping names the targets logs can be shipped to and the rules choosing which
+// containers go where
+type LogShipping struct {
+	Targets []LogTarget `json:"targets"`
+	Rules   []ShipRule  `json:"rules"`
+}
+
+// LogTarget is a destination for container logs. Secrets may reference environment
+// variables, e.g. "password": "${LOKI_PASSWORD}".
+type LogTarget struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // syslog, loki or http
+
+	// URL of the Loki server or HTTP endpoint
+	URL         string            `json:"url,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Password    string            `json:"password,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+
+	// Address of the syslog server as udp://host:port, tcp://host:port or tcp+tls://host:port
+	Address string `json:"address,omitempty"`
+}
+
+// ShipRule sends the logs of matching containers to targets. A container matches when
+// its name is listed or it carries all the labels; an empty label value matches any value.
+type ShipRule struct {
+	Targets    []string          `json:"targets"`
+	Containers []string          `json:"containers,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// Matches reports whether a container is selected by the rule
+func (r *ShipRule) Matches(name string, labels map[string]string) bool {
+	name = strings.TrimPrefix(name, "/")
+	for _, n := range r.Containers {
+		if strings.TrimPrefix(n, "/") == name {
+			return true
+		}
+	}
+	if len(r.Labels) == 0 {
+		return false
+	}
+	for k, v := range r.Labels {
+		got, ok := labels[k]
+		if !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
+
+// TargetsFor returns the names of the targets a container's logs are shipped to
+func (s *LogShipping) TargetsFor(name string, labels map[string]string) []string {
+	var targets []string
+	seen := make(map[string]bool)
+	for i := range s.Rules {
+		if !s.Rules[i].Matches(name, labels) {
+			continue
+		}
+		for _, t := range s.Rules[i].Targets {
+			if !seen[t] {
+				seen[t] = true
+				targets = append(targets, t)
+			}
+		}
+	}
+	return targets
+}
+
+func (s *LogShipping) validate() error {
+	names := make(map[string]bool)
+	for i, t := range s.Targets {
+		if t.Name == "" {
+			return fmt.Errorf("log_shipping: target #%d has no name", i+1)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("log_shipping: duplicate target name %q", t.Name)
+		}
+		names[t.Name] = true
+
+		switch t.Type {
+		case TargetLoki, TargetHTTP:
+			if !strings.HasPrefix(t.URL, "http://") && !strings.HasPrefix(t.URL, "https://") {
+				return fmt.Errorf("log_shipping: target %q: url must start with http:// or https://, got %q", t.Name, t.URL)
+			}
+			if t.BearerToken != "" && t.Username != "" {
+				return fmt.Errorf("log_shipping: target %q: use either bearer_token or username/password", t.Name)
+			}
+		case TargetSyslog:
+			scheme, _, ok := strings.Cut(t.Address, "://")
+			if !ok || (scheme != "udp" && scheme != "tcp" && scheme != "tcp+tls") {
+				return fmt.Errorf("log_shipping: target %q: address must be udp://, tcp:// or tcp+tls://host:port, got %q", t.Name, t.Address)
+			}
+		default:
+			return fmt.Errorf("log_shipping: target %q: type must be %s, %s or %s, got %q",
+				t.Name, TargetSyslog, TargetLoki, TargetHTTP, t.Type)
+		}
+	}
+
+	for i, r := range s.Rules {
+		if len(r.Targets) == 0 {
+			return fmt.Errorf("log_shipping: rule #%d has no targets", i+1)
+		}
+		if len(r.Containers) == 0 && len(r.Labels) == 0 {
+			return fmt.Errorf("log_shipping: rule #%d selects no containers; set containers or labels", i+1)
+		}
+		for _, t := range r.Targets {
+			if !names[t] {
+				return fmt.Errorf("log_shipping: rule #%d refers to unknown target %q", i+1, t)
+			}
+		}
+	}
+	return nil
+}
+
+// Shell history modes
+const (
+	ShellHistoryContainer = "container"
+	ShellHistoryGlobal    = "global"
+	ShellHistoryOff       = "off"
+)
+
+// Where shell_terminal opens interactive shells
+const (
+	ShellTerminalWindow = "window"
+	ShellTerminalPane   = "pane"
+)
+
+// Graph styles for the statistics screen
+const (
+	GraphBlocks  = "blocks"
+	GraphBraille = "braille"
+)
+
+// Registry is a private registry speaking the Docker Registry HTTP API v2, e.g.
+// registry:2, Harbor, Nexus or GitLab's container registry
+type Registry struct {
+	Name string `json:"name"`
+	// URL is https://host[:port], or http://host[:port] for registries without TLS
+	URL string `json:"url"`
+	// Username and Password are sent to the registry and, for pulls, to the daemon;
+	// ${VARS} are expanded
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Insecure accepts a self-signed certificate; the daemon needs the registry in its
+	// insecure-registries as well to pull from it
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// Host returns the registry as it appears in image names, e.g. "registry.local:5000"
+func (r *Registry) Host() string {
+	_, host, _ := strings.Cut(r.URL, "://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+func (r *Registry) validate() error {
+	if r.Name == "" {
+		return errors.New("registry has no name")
+	}
+	if !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+		return fmt.Errorf("registry %q: url must start with http:// or https://, got %q", r.Name, r.URL)
+	}
+	if r.Host() == "" {
+		return fmt.Errorf("registry %q: url %q has no host", r.Name, r.URL)
+	}
+	return nil
+}
+
+// Host is a named Docker daemon
+type Host struct {
+	Name string `json:"name"`
+	// URL is unix:///path, tcp://host:port or ssh://[user@]host[:port][/socket]
+	URL string `json:"url"`
+
+	// SSHKey is a private key file for ssh:// hosts; without it ssh uses the agent and ~/.ssh/config
+	SSHKey string `json:"ssh_key,omitempty"`
+	// SSHAgent is the ssh-agent socket to use instead of SSH_AUTH_SOCK, or "none"
+	SSHAgent string `json:"ssh_agent,omitempty"`
+
+	// TLSCA verifies the daemon of a tcp:// host; TLSCert and TLSKey authenticate to it (mTLS)
+	TLSCA   string `json:"tls_ca,omitempty"`
+	TLSCert string `json:"tls_cert,omitempty"`
+	TLSKey  string `json:"tls_key,omitempty"`
+}
+
+// FindHost returns the host with the given name
+func (c *Config) FindHost(name string) (*Host, bool) {
+	for i := range c.Hosts {
+		if c.Hosts[i].Name == name {
+			return &c.Hosts[i], true
+		}
+	}
+	return nil, false
+}
+
+// Palette is a custom UI theme; colors are tview names or #rrggbb values.
+// Empty fields fall back to the dark preset.
+type Palette struct {
+	Background string `json:"background,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Muted      string `json:"muted,omitempty"`
+	Border     string `json:"border,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Accent     string `json:"accent,omitempty"`
+	Highlight  string `json:"highlight,omitempty"`
+	Success    string `json:"success,omitempty"`
+	Warning    string `json:"warning,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Info       string `json:"info,omitempty"`
+	Secondary  string `json:"secondary,omitempty"`
+	Selection  string `json:"selection,omitempty"`
+}
+
+// View is a named container filter; all non-empty conditions must match
+type View struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	State       string            `json:"state,omitempty"`
+	NamePattern string            `json:"name_pattern,omitempty"`
+	Image       string            `json:"image,omitempty"`
+
+	nameRegex *regexp.Regexp
+}
+
+// MatchesName reports whether a container name passes the view's name pattern
+func (v *View) MatchesName(name string) bool {
+	if v.nameRegex == nil {
+		return true
+	}
+	return v.nameRegex.MatchString(name)
+}
+
+// Path returns the config file location, honouring DOCKPULSE_CONFIG
+func Path() string {
+	if p := os.Getenv("DOCKPULSE_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "config.json")
+}
+
+// Load reads the config file; a missing file yields an empty config
+func Load() (*Config, error) {
+	return LoadFile(Path())
+}
+
+// LoadFile reads and validates the config at the given path
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	if len(c.Views) > 9 {
+		return fmt.Errorf("at most 9 views are supported, got %d", len(c.Views))
+	}
+
+	if c.BulkConcurrency < 0 {
+		return fmt.Errorf("bulk_concurrency must be positive, got %d", c.BulkConcurrency)
+	}
+
+	switch c.ShellHistory {
+	case "", ShellHistoryContainer, ShellHistoryGlobal, ShellHistoryOff:
+	default:
+		return fmt.Errorf("shell_history must be %q, %q or %q, got %q",
+			ShellHistoryContainer, ShellHistoryGlobal, ShellHistoryOff, c.ShellHistory)
+	}
+	switch c.ShellTerminal {
+	case "", ShellTerminalWindow, ShellTerminalPane:
+	default:
+		return fmt.Errorf("shell_terminal must be %q or %q, got %q", ShellTerminalWindow, ShellTerminalPane, c.ShellTerminal)
+	}
+	if c.DetachKeys != "" {
+		if _, err := term.ToBytes(c.DetachKeys); err != nil {
+			return fmt.Errorf("detach_keys must be keys like \"ctrl-p,ctrl-q\": %w", err)
+		}
+	}
+	if c.StaleAfterDays < 0 {
+		return fmt.Errorf("stale_after_days must be positive, got %d", c.StaleAfterDays)
+	}
+	if c.ShellHistorySize < 0 {
+		return fmt.Errorf("shell_history_size must be positive, got %d", c.ShellHistorySize)
+	}
+
+	switch c.GraphStyle {
+	case "", GraphBlocks, GraphBraille:
+	default:
+		return fmt.Errorf("graph_style must be %q or %q, got %q", GraphBlocks, GraphBraille, c.GraphStyle)
+	}
+
+	if c.HistoryRetention != "" && c.HistoryRetention != "off" {
+		d, err := time.ParseDuration(c.HistoryRetention)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("history_retention must be a positive duration like \"24h\" or \"off\", got %q", c.HistoryRetention)
+		}
+	}
+
+	if c.ForecastHorizon != "" && c.ForecastHorizon != "off" {
+		d, err := time.ParseDuration(c.ForecastHorizon)
+		if err != nil || d < time.Hour {
+			return fmt.Errorf("forecast_horizon must be a duration of at least 1h or \"off\", got %q", c.ForecastHorizon)
+		}
+	}
+
+	if c.KioskInterval != "" {
+		d, err := time.ParseDuration(c.KioskInterval)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("kiosk_interval must be a duration of at least 1s, got %q", c.KioskInterval)
+		}
+	}
+
+	if c.Export != nil {
+		if err := c.Export.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.API != nil {
+		if err := c.API.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Digest != nil {
+		if err := c.Digest.validate(); err != nil {
+			return err
+		}
+		if _, ok := c.History(); !ok {
+			return errors.New("digest: needs the history, which history_retention \"off\" disables")
+		}
+	}
+
+	if c.LogLevels != nil {
+		if err := c.LogLevels.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.LogArchive != nil {
+		if err := c.LogArchive.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.LogShipping != nil {
+		if err := c.LogShipping.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Protected != nil {
+		if err := c.Protected.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.CrashLoop != nil {
+		if err := c.CrashLoop.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.Remediation != nil {
+		if err := c.Remediation.validate(); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.Probes {
+		if err := c.Probes[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	profiles := make(map[string]bool)
+	for i := range c.StartProfiles {
+		p := &c.StartProfiles[i]
+		if err := p.validate(); err != nil {
+			return err
+		}
+		if profiles[p.Name] {
+			return fmt.Errorf("start profile %q is defined twice", p.Name)
+		}
+		profiles[p.Name] = true
+	}
+
+	plugins := make(map[string]bool)
+	for i := range c.Plugins {
+		p := &c.Plugins[i]
+		if err := p.validate(); err != nil {
+			return err
+		}
+		if plugins[p.Name] {
+			return fmt.Errorf("plugin %q is defined twice", p.Name)
+		}
+		plugins[p.Name] = true
+	}
+
+	for i := range c.Hooks {
+		if err := c.Hooks[i].validate(i); err != nil {
+			return err
+		}
+	}
+
+	for i := range c.AutoFollow {
+		if err := c.AutoFollow[i].Validate(); err != nil {
+			return fmt.Errorf("auto_follow #%d: %w", i+1, err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, h := range c.Hosts {
+		if h.Name == "" {
+			return fmt.Errorf("host #%d has no name", i+1)
+		}
+		if names[h.Name] {
+			return fmt.Errorf("host %q is defined twice", h.Name)
+		}
+		names[h.Name] = true
+
+		scheme, _, ok := strings.Cut(h.URL, "://")
+		if !ok {
+			return fmt.Errorf("host %q: url %q has no scheme", h.Name, h.URL)
+		}
+		switch scheme {
+		case "unix", "tcp", "npipe", "ssh":
+		default:
+			return fmt.Errorf("host %q: unsupported scheme %q (use unix, tcp, npipe or ssh)", h.Name, scheme)
+		}
+		if (h.SSHKey != "" || h.SSHAgent != "") && scheme != "ssh" {
+			return fmt.Errorf("host %q: ssh_key and ssh_agent only apply to ssh:// urls", h.Name)
+		}
+		if (h.TLSCA != "" || h.TLSCert != "" || h.TLSKey != "") && scheme != "tcp" {
+			return fmt.Errorf("host %q: tls_ca, tls_cert and tls_key only apply to tcp:// urls", h.Name)
+		}
+		if (h.TLSCert == "") != (h.TLSKey == "") {
+			return fmt.Errorf("host %q: tls_cert and tls_key must be set together", h.Name)
+		}
+	}
+
+	registries := make(map[string]bool)
+	for i := range c.Registries {
+		r := &c.Registries[i]
+		if err := r.validate(); err != nil {
+			return err
+		}
+		if registries[r.Name] {
+			return fmt.Errorf("registry %q is defined twice", r.Name)
+		}
+		registries[r.Name] = true
+	}
+
+	for i := range c.Views {
+		v := &c.Views[i]
+		if v.Name == "" {
+			return fmt.Errorf("view #%d has no name", i+1)
+		}
+		if v.NamePattern != "" {
+			re, err := regexp.Compile(v.NamePattern)
+			if err != nil {
+				return fmt.Errorf("view %q: bad name_pattern: %w", v.Name, err)
+			}
+			v.nameRegex = re
+		}
+	}
+
+	return nil
+}
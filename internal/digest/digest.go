@@ -0,0 +1,415 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// Period is how much history one digest covers
+const Period = 24 * time.Hour
+
+// sendTimeout bounds a delivery so a slow server doesn't hold the next digest up
+const sendTimeout = 30 * time.Second
+
+// Summary is what happened to the fleet over one period
+type Summary struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Restarts []Restarts   `json:"restarts"`
+	Updates  []Update     `json:"image_updates"`
+	Disk     []DiskChange `json:"disk"`
+	Alerts   []Alerts     `json:"alerts"`
+}
+
+// Restarts is how often a container was started again after running before
+type Restarts struct {
+	Container string `json:"container"`
+	Count     int    `json:"count"`
+}
+
+// Update is a container recreated from another image than the one before
+type Update struct {
+	Container string    `json:"container"`
+	Time      time.Time `json:"time"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+}
+
+// DiskChange is the disk usage of a host at the end of the period and at its start;
+// Before is zero for a host first recorded during the period
+type DiskChange struct {
+	Host   string             `json:"host"`
+	Before history.DiskSample `json:"before"`
+	After  history.DiskSample `json:"after"`
+}
+
+// Delta returns how much the disk usage grew, negative when it shrank
+func (c DiskChange) Delta() int64 {
+	if c.Before.Time.IsZero() {
+		return 0
+	}
+	return c.After.Total() - c.Before.Total()
+}
+
+// Alerts is how often an alert of one kind was raised and for which containers
+type Alerts struct {
+	Kind       string   `json:"kind"`
+	Severity   string   `json:"severity"`
+	Count      int      `json:"count"`
+	Containers []string `json:"containers,omitempty"`
+}
+
+// Build summarises the history between from and to
+func Build(store *history.Store, from, to time.Time) (Summary, error) {
+	s := Summary{From: from, To: to}
+
+	// Earlier events tell whether the first start of the period is a restart
+	timelines, err := store.Timelines(time.Unix(0, 0))
+	if err != nil {
+		return s, fmt.Errorf("read timelines: %w", err)
+	}
+	hosts := make(map[string]bool)
+	for key := range timelines {
+		hosts[key.Host] = true
+	}
+	for key, events := range timelines {
+		name := key.Name
+		if len(hosts) > 1 {
+			name = key.Host + "/" + key.Name
+		}
+		restarts, updates := scanTimeline(name, events, from, to)
+		if restarts > 0 {
+			s.Restarts = append(s.Restarts, Restarts{name, restarts})
+		}
+		s.Updates = append(s.Updates, updates...)
+	}
+	slices.SortFunc(s.Restarts, func(a, b Restarts) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Container, b.Container)
+	})
+	slices.SortFunc(s.Updates, func(a, b Update) int { return a.Time.Compare(b.Time) })
+
+	after, err := store.DiskAt(to)
+	if err != nil {
+		return s, fmt.Errorf("read disk usage: %w", err)
+	}
+	before, err := store.DiskAt(from)
+	if err != nil {
+		return s, fmt.Errorf("read disk usage: %w", err)
+	}
+	for host, sample := range after {
+		if sample.Time.Before(from) {
+			continue // not recorded during the period
+		}
+		s.Disk = append(s.Disk, DiskChange{host, before[host], sample})
+	}
+	slices.SortFunc(s.Disk, func(a, b DiskChange) int { return strings.Compare(a.Host, b.Host) })
+
+	alerts, err := store.Alerts(from, to)
+	if err != nil {
+		return s, fmt.Errorf("read alerts: %w", err)
+	}
+	s.Alerts = groupAlerts(alerts)
+	return s, nil
+}
+
+// scanTimeline counts the restarts and image updates of one container within the
+// period. A start counts as a restart when the container ran before without being
+// created anew; docker restart, restart policies and a stop and start all do that.
+func scanTimeline(name string, events []history.Event, from, to time.Time) (int, []Update) {
+	var restarts int
+	var updates []Update
+	var last, image string // last create or start, and the image of the last create
+	for _, e := range events {
+		in := !e.Time.Before(from) && e.Time.Before(to)
+		switch e.Action {
+		case "create":
+			if in && image != "" && e.Image != image {
+				updates = append(updates, Update{name, e.Time, image, e.Image})
+			}
+			image = e.Image
+		case "start":
+			if in && last == "start" {
+				restarts++
+			}
+		default:
+			continue
+		}
+		last = e.Action
+	}
+	return restarts, updates
+}
+
+// groupAlerts counts alerts by kind, critical ones first; recoveries are left out
+func groupAlerts(alerts []history.Alert) []Alerts {
+	var groups []Alerts
+	for _, a := range alerts {
+		if a.Severity == history.SeverityInfo {
+			continue
+		}
+		i := slices.IndexFunc(groups, func(g Alerts) bool { return g.Kind == a.Kind })
+		if i < 0 {
+			groups = append(groups, Alerts{Kind: a.Kind, Severity: a.Severity})
+			i = len(groups) - 1
+		}
+		groups[i].Count++
+		if a.Container != "" && !slices.Contains(groups[i].Containers, a.Container) {
+			groups[i].Containers = append(groups[i].Containers, a.Container)
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b Alerts) int {
+		if (a.Severity == history.SeverityCritical) != (b.Severity == history.SeverityCritical) {
+			if a.Severity == history.SeverityCritical {
+				return -1
+			}
+			return 1
+		}
+		return b.Count - a.Count
+	})
+	return groups
+}
+
+// Headline sums the digest up in one line, for the mail subject
+func (s Summary) Headline() string {
+	var restarts, alerts int
+	for _, r := range s.Restarts {
+		restarts += r.Count
+	}
+	for _, a := range s.Alerts {
+		alerts += a.Count
+	}
+	return fmt.Sprintf("DockPulse daily digest: %s, %s, %s",
+		plural(restarts, "restart"), plural(len(s.Updates), "image update"), plural(alerts, "alert"))
+}
+
+// Text renders the digest as plain text, readable in a mail client and in chat
+func (s Summary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s – %s\n", s.Headline(), s.From.Format("Mon 2 Jan 15:04"), s.To.Format("Mon 2 Jan 15:04"))
+
+	var restarts int
+	for _, r := range s.Restarts {
+		restarts += r.Count
+	}
+	fmt.Fprintf(&b, "\nRestarts: %d\n", restarts)
+	for _, r := range s.Restarts {
+		fmt.Fprintf(&b, "  %s ×%d\n", r.Container, r.Count)
+	}
+
+	fmt.Fprintf(&b, "\nImage updates: %d\n", len(s.Updates))
+	for _, u := range s.Updates {
+		fmt.Fprintf(&b, "  %s %s: %s → %s\n", u.Time.Format("15:04"), u.Container, u.From, u.To)
+	}
+
+	b.WriteString("\nDisk usage:\n")
+	if len(s.Disk) == 0 {
+		b.WriteString("  not recorded\n")
+	}
+	for _, c := range s.Disk {
+		fmt.Fprintf(&b, "  %s: %s", c.Host, formatSize(c.After.Total()))
+		if c.Before.Time.IsZero() {
+			b.WriteString(" (first recorded)\n")
+			continue
+		}
+		fmt.Fprintf(&b, " (%s", formatDelta(c.Delta()))
+		if gap := s.From.Sub(c.Before.Time); gap > time.Hour {
+			fmt.Fprintf(&b, " since %s", c.Before.Time.Format("Mon 2 Jan 15:04"))
+		}
+		b.WriteString(")")
+		var parts []string
+		for _, part := range []struct {
+			label         string
+			before, after int64
+		}{
+			{"images", c.Before.Images, c.After.Images},
+			{"containers", c.Before.Containers, c.After.Containers},
+			{"volumes", c.Before.Volumes, c.After.Volumes},
+			{"build cache", c.Before.BuildCache, c.After.BuildCache},
+		} {
+			if delta := part.after - part.before; delta != 0 {
+				parts = append(parts, fmt.Sprintf("%s %s", part.label, formatDelta(delta)))
+			}
+		}
+		if len(parts) > 0 {
+			b.WriteString(": " + strings.Join(parts, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	var alerts int
+	for _, a := range s.Alerts {
+		alerts += a.Count
+	}
+	fmt.Fprintf(&b, "\nAlerts: %d\n", alerts)
+	for _, a := range s.Alerts {
+		fmt.Fprintf(&b, "  %s ×%d", a.Kind, a.Count)
+		if len(a.Containers) > 0 {
+			b.WriteString(": " + strings.Join(a.Containers, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func formatSize(size int64) string {
+	return docker.FormatBytes(uint64(max(size, 0)))
+}
+
+func formatDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + formatSize(-delta)
+	}
+	return "+" + formatSize(delta)
+}
+
+// Next returns when the digest is due next after now
+func Next(cfg *config.Digest, now time.Time) time.Time {
+	hour, minute := cfg.SendAt()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Send delivers the digest to the webhook and by mail, whichever are configured
+func Send(ctx context.Context, cfg *config.Digest, s Summary) error {
+	var errs []error
+	if cfg.Webhook != nil {
+		if err := postWebhook(ctx, cfg.Webhook, s); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		}
+	}
+	if cfg.SMTP != nil {
+		if err := sendMail(ctx, cfg.SMTP, s); err != nil {
+			errs = append(errs, fmt.Errorf("smtp: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// postWebhook posts the text along with the summary as JSON
+func postWebhook(ctx context.Context, w *config.Webhook, s Summary) error {
+	body, err := json.Marshal(struct {
+		Text   string  `json:"text"`
+		Digest Summary `json:"digest"`
+	}{s.Text(), s})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DockPulse")
+	for k, v := range w.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if w.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+os.ExpandEnv(w.BearerToken))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// sendMail mails the text, over TLS on port 465 and with STARTTLS elsewhere when the
+// server offers it
+func sendMail(ctx context.Context, m *config.SMTP, s Summary) error {
+	host, port, err := net.SplitHostPort(m.Address)
+	if err != nil {
+		return err
+	}
+	tlsConfig := &tls.Config{ServerName: host}
+
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	var conn net.Conn
+	if port == "465" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", m.Address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", m.Address)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(sendTimeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && port != "465" {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", os.ExpandEnv(m.Username), os.ExpandEnv(m.Password), host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.From); err != nil {
+		return err
+	}
+	for _, to := range m.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n",
+		m.From, strings.Join(m.To, ", "), mime.QEncoding.Encode("utf-8", s.Headline()), time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(w, "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	if _, err := io.WriteString(w, strings.ReplaceAll(s.Text(), "\n", "\r\n")); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
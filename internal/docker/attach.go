@@ -0,0 +1,135 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+)
+
+// DefaultDetachKeys is the key sequence that detaches from a container, as in docker attach
+const DefaultDetachKeys = "ctrl-p,ctrl-q"
+
+// AttachResult is how an attach session ended
+type AttachResult struct {
+	Detached bool // the detach keys were pressed and the container still runs
+	ExitCode int  // the main process's exit code when it didn't
+}
+
+// Attach connects the terminal to the main process of a running container, unlike exec
+// which starts a new one: its output shows up on stdout and, when the container keeps
+// stdin open (docker run -i), keystrokes go to its stdin. The terminal is raw until the
+// detach keys are pressed or the process exits. An empty detachKeys uses
+// DefaultDetachKeys.
+func Attach(containerID, detachKeys string) (*AttachResult, error) {
+	if detachKeys == "" {
+		detachKeys = DefaultDetachKeys
+	}
+	escape, err := term.ToBytes(detachKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid detach keys %q: %w", detachKeys, err)
+	}
+
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, fmt.Errorf("%s is not running", strings.TrimPrefix(inspect.Name, "/"))
+	}
+	tty, stdin := inspect.Config.Tty, inspect.Config.OpenStdin
+
+	// Reads from a /dev/tty of our own can be interrupted when the session ends, so no
+	// keystroke meant for the dashboard is lost to a reader left behind
+	in, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		in = os.Stdin
+	} else {
+		defer in.Close()
+	}
+
+	resp, err := cli.ContainerAttach(ctx, containerID, types.ContainerAttachOptions{
+		Stream:     true,
+		Stdin:      stdin,
+		Stdout:     true,
+		Stderr:     true,
+		DetachKeys: detachKeys,
+	})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer resp.Close()
+
+	state, err := term.MakeRaw(in.Fd())
+	if err != nil {
+		return nil, fmt.Errorf("switching the terminal to raw mode: %w", err)
+	}
+	defer term.RestoreTerminal(in.Fd(), state)
+
+	if size, err := term.GetWinsize(in.Fd()); err == nil && tty {
+		cli.ContainerResize(ctx, containerID, types.ResizeOptions{Height: uint(size.Height), Width: uint(size.Width)})
+	}
+
+	output := make(chan struct{})
+	go func() {
+		if tty {
+			io.Copy(os.Stdout, resp.Reader)
+		} else {
+			stdcopy.StdCopy(crlfWriter{os.Stdout}, crlfWriter{os.Stderr}, resp.Reader)
+		}
+		close(output)
+	}()
+
+	go func() {
+		if stdin {
+			// The daemon watches stdin for the detach keys and ends the stream
+			io.Copy(resp.Conn, in)
+			return
+		}
+		// Without stdin only the detach keys are read, and the connection is closed here
+		var detached term.EscapeError
+		if _, err := io.Copy(io.Discard, term.NewEscapeProxy(in, escape)); errors.As(err, &detached) {
+			resp.Close()
+		}
+	}()
+
+	<-output
+	in.SetReadDeadline(time.Now())
+
+	after, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if after.State.Running {
+		return &AttachResult{Detached: true}, nil
+	}
+	return &AttachResult{ExitCode: after.State.ExitCode}, nil
+}
+
+// crlfWriter ends lines with \r\n, which a raw terminal no longer does for output that
+// doesn't come from a pseudo-terminal
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// bindSizeBudget bounds how long the size of a bind-mounted directory is added up
+const bindSizeBudget = 2 * time.Second
+
+// BindCheck is what was found at the host path of a bind mount
+type BindCheck struct {
+	Checked bool // false for daemons on other machines, whose paths can't be looked at
+	Exists  bool
+	Dir     bool
+	Mode    os.FileMode
+	Size    int64 // for directories the files below it
+	Partial bool  // the directory was too large to add up in time; Size is a lower bound
+	Err     error // e.g. permission denied; Exists is then unknown
+}
+
+// CheckBindSource looks at the host path of a bind mount of a container. Only daemons
+// on this machine can be checked.
+func CheckBindSource(containerID, source string) BindCheck {
+	if !endpointFor(containerID).IsLocal() {
+		return BindCheck{}
+	}
+
+	check := BindCheck{Checked: true}
+	info, err := os.Stat(source)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return check
+	case err != nil:
+		check.Err = err
+		return check
+	}
+	check.Exists, check.Dir, check.Mode = true, info.IsDir(), info.Mode()
+	if !info.IsDir() {
+		check.Size = info.Size()
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bindSizeBudget)
+	defer cancel()
+	filepath.WalkDir(source, func(_ string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			check.Partial = true
+			return filepath.SkipAll
+		}
+		if err != nil {
+			check.Partial = true // unreadable entries are left out
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			if info, err := entry.Info(); err == nil {
+				check.Size += info.Size()
+			}
+		}
+		return nil
+	})
+	return check
+}
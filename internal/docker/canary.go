@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// ShareAliases connects a container to the user-defined networks of another one under
+// the same network aliases, e.g. the compose service name. Docker's DNS then answers
+// with both containers, so clients resolving the alias are spread over them. It returns
+// the aliases shared.
+func ShareAliases(fromID, toID string) ([]string, error) {
+	cli, err := clientFor(fromID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	from, err := cli.ContainerInspect(ctx, fromID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	to, err := cli.ContainerInspect(ctx, toID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	var shared []string
+	for netName, ep := range from.NetworkSettings.Networks {
+		if ep == nil {
+			continue
+		}
+		// The short ID and the name resolve to the container itself, not the service
+		var aliases []string
+		for _, a := range ep.Aliases {
+			if a != from.ID[:12] && a != strings.TrimPrefix(from.Name, "/") {
+				aliases = append(aliases, a)
+			}
+		}
+		if len(aliases) == 0 {
+			continue
+		}
+
+		// Aliases can only be set when connecting, so an existing endpoint is replaced
+		if _, ok := to.NetworkSettings.Networks[netName]; ok {
+			if err := cli.NetworkDisconnect(ctx, netName, toID, false); err != nil {
+				return shared, fmt.Errorf("failed to leave %s: %w", netName, decodeError(err))
+			}
+		}
+		if err := cli.NetworkConnect(ctx, netName, toID, &network.EndpointSettings{Aliases: aliases}); err != nil {
+			return shared, fmt.Errorf("failed to join %s: %w", netName, decodeError(err))
+		}
+		for _, a := range aliases {
+			if !slices.Contains(shared, a) {
+				shared = append(shared, a)
+			}
+		}
+	}
+	return shared, nil
+}
+
+// RenameContainer gives a container a new name
+func RenameContainer(containerID, name string) error {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	return decodeError(cli.ContainerRename(context.Background(), containerID, name))
+}
+
+// HostPorts returns the host ports a container's ports are published on, e.g.
+// "80/tcp" -> "49153"; a port published several times keeps the first
+func HostPorts(containerID string) (map[string]string, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	ports := make(map[string]string)
+	if inspect.NetworkSettings == nil {
+		return ports, nil
+	}
+	for port, bindings := range inspect.NetworkSettings.Ports {
+		if len(bindings) > 0 && bindings[0].HostPort != "" {
+			ports[string(port)] = bindings[0].HostPort
+		}
+	}
+	return ports, nil
+}
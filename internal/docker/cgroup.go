@@ -0,0 +1,161 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// cgroupFiles are the interface files shown for cgroup v2, in display order
+var cgroupFiles = []string{
+	"cpu.max", "cpu.weight", "cpu.stat", "cpuset.cpus.effective",
+	"memory.current", "memory.max", "memory.high", "memory.swap.current", "memory.swap.max", "memory.events",
+	"pids.current", "pids.max",
+	"io.max", "io.pressure", "cpu.pressure", "memory.pressure",
+}
+
+// cgroupV1Files are the closest cgroup v1 equivalents, below their controller directory
+var cgroupV1Files = []string{
+	"cpu/cpu.cfs_quota_us", "cpu/cpu.cfs_period_us", "cpu/cpu.shares", "cpu/cpu.stat", "cpuset/cpuset.effective_cpus",
+	"memory/memory.usage_in_bytes", "memory/memory.limit_in_bytes", "memory/memory.max_usage_in_bytes", "memory/memory.failcnt",
+	"pids/pids.current", "pids/pids.max",
+	"blkio/blkio.throttle.read_bps_device", "blkio/blkio.throttle.write_bps_device",
+}
+
+// CgroupValue is the content of one cgroup interface file, a line per entry
+type CgroupValue struct {
+	File  string
+	Lines []string
+}
+
+// CgroupInfo is what the kernel enforces and counts for a container, read from its cgroup
+type CgroupInfo struct {
+	Version int
+	Path    string // below the cgroup root, "" when read from inside the container
+	Host    bool   // read from the host's cgroup filesystem rather than from inside
+	Values  []CgroupValue
+}
+
+// ReadCgroup reads the cgroup interface files of a running container: from the host's
+// /sys/fs/cgroup for local daemons on Linux, and from the container's own
+// /sys/fs/cgroup otherwise
+func ReadCgroup(containerID string) (*CgroupInfo, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.Platform == "windows" {
+		return nil, fmt.Errorf("cgroups only apply to Linux containers")
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, fmt.Errorf("%s is not running", strings.TrimPrefix(inspect.Name, "/"))
+	}
+
+	if endpointFor(containerID).IsLocal() {
+		if info, ok := readHostCgroup(inspect.ID, inspect.State.Pid); ok {
+			return info, nil
+		}
+	}
+	return readContainerCgroup(containerID)
+}
+
+// readHostCgroup reads the cgroup of a process from the host. It gives up when the
+// process doesn't sit in a cgroup named after the container, e.g. when the daemon runs
+// in a VM and the PID means something else here.
+func readHostCgroup(id string, pid int) (*CgroupInfo, bool) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, false
+	}
+
+	// v2 has a single "0::/path" line, v1 a "N:controllers:/path" line per hierarchy,
+	// and hybrid hosts both, with the controllers on v1
+	var unified string
+	dirs := make(map[string]string) // controller -> path
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 || !strings.Contains(parts[2], id) {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			unified = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			dirs[controller] = parts[2]
+		}
+	}
+	if _, ok := dirs["memory"]; !ok {
+		if unified == "" {
+			return nil, false
+		}
+		info := &CgroupInfo{Version: 2, Path: unified, Host: true}
+		info.Values = readCgroupFiles(cgroupFiles, func(file string) string {
+			return path.Join("/sys/fs/cgroup", unified, file)
+		})
+		return info, true
+	}
+
+	info := &CgroupInfo{Version: 1, Path: dirs["memory"], Host: true}
+	info.Values = readCgroupFiles(cgroupV1Files, func(file string) string {
+		controller := path.Dir(file)
+		return path.Join("/sys/fs/cgroup", controller, dirs[controller], path.Base(file))
+	})
+	return info, true
+}
+
+// readCgroupFiles reads the files that exist, leaving out the others and empty ones
+func readCgroupFiles(files []string, locate func(string) string) []CgroupValue {
+	var values []CgroupValue
+	for _, file := range files {
+		data, err := os.ReadFile(locate(file))
+		if text := strings.TrimSpace(string(data)); err == nil && text != "" {
+			values = append(values, CgroupValue{File: file, Lines: strings.Split(text, "\n")})
+		}
+	}
+	return values
+}
+
+// readContainerCgroup reads the cgroup files a container sees at /sys/fs/cgroup, which
+// with a private cgroup namespace are its own
+func readContainerCgroup(containerID string) (*CgroupInfo, error) {
+	files := append([]string{"cgroup.controllers"}, cgroupFiles...)
+	files = append(files, cgroupV1Files...)
+	// grep -H prints "file:line" for every line of the files that exist
+	out, err := ExecCommand(containerID, "cd /sys/fs/cgroup && grep -H . "+strings.Join(files, " "))
+	var exit *ExitError
+	if err != nil && !errors.As(err, &exit) {
+		return nil, fmt.Errorf("reading /sys/fs/cgroup in the container: %w", err)
+	}
+
+	lines := make(map[string][]string)
+	for _, line := range strings.Split(out, "\n") {
+		file, value, ok := strings.Cut(line, ":")
+		if ok {
+			lines[file] = append(lines[file], value)
+		}
+	}
+	info := &CgroupInfo{Version: 1}
+	files = cgroupV1Files
+	if _, ok := lines["cgroup.controllers"]; ok {
+		info.Version, files = 2, cgroupFiles
+	}
+	for _, file := range files {
+		if l, ok := lines[file]; ok {
+			info.Values = append(info.Values, CgroupValue{File: file, Lines: l})
+		}
+	}
+	if len(info.Values) == 0 {
+		return nil, fmt.Errorf("no cgroup files readable in the container (it needs a shell and grep)")
+	}
+	return info, nil
+}
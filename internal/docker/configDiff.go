@@ -0,0 +1,182 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sections of a config diff, in the order they are shown
+const (
+	DiffImage   = "Image"
+	DiffEnv     = "Env"
+	DiffLabels  = "Labels"
+	DiffMounts  = "Mounts"
+	DiffPorts   = "Ports"
+	DiffRuntime = "Runtime"
+)
+
+// DiffSections lists the sections of a config diff in order
+var DiffSections = []string{DiffImage, DiffEnv, DiffLabels, DiffMounts, DiffPorts, DiffRuntime}
+
+// ContainerConfig is what a config diff compares, each section as key -> value
+type ContainerConfig struct {
+	Name     string
+	Sections map[string]map[string]string
+}
+
+// ConfigDiffEntry is one key of a section on both sides; a side without the key has
+// its Has flag unset
+type ConfigDiffEntry struct {
+	Section     string
+	Key         string
+	Left, Right string
+	HasLeft     bool
+	HasRight    bool
+}
+
+// Same reports whether both sides have the key with the same value
+func (e ConfigDiffEntry) Same() bool {
+	return e.HasLeft && e.HasRight && e.Left == e.Right
+}
+
+// GetContainerConfig reads the settings of a container a config diff compares: its
+// image and tags, env vars, labels, mounts, published ports and runtime settings
+func GetContainerConfig(containerID string) (ContainerConfig, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return ContainerConfig{}, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return ContainerConfig{}, decodeError(err)
+	}
+
+	cfg := ContainerConfig{
+		Name:     strings.TrimPrefix(inspect.Name, "/"),
+		Sections: make(map[string]map[string]string),
+	}
+	for _, s := range DiffSections {
+		cfg.Sections[s] = make(map[string]string)
+	}
+
+	image := cfg.Sections[DiffImage]
+	image["reference"] = inspect.Config.Image
+	image["id"] = shortImageID(inspect.Image)
+	if img, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image); err == nil {
+		image["tags"] = strings.Join(img.RepoTags, ", ")
+		image["digests"] = strings.Join(img.RepoDigests, ", ")
+		image["created"] = img.Created
+	}
+
+	for _, kv := range inspect.Config.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		cfg.Sections[DiffEnv][k] = v
+	}
+	for k, v := range inspect.Config.Labels {
+		cfg.Sections[DiffLabels][k] = v
+	}
+
+	for _, m := range inspect.Mounts {
+		source := m.Source
+		if m.Type == "volume" && m.Name != "" {
+			source = m.Name
+		}
+		mode := "rw"
+		if !m.RW {
+			mode = "ro"
+		}
+		cfg.Sections[DiffMounts][m.Destination] = fmt.Sprintf("%s %s (%s)", m.Type, source, mode)
+	}
+
+	for port, bindings := range inspect.HostConfig.PortBindings {
+		var hosts []string
+		for _, b := range bindings {
+			host := b.HostPort
+			if b.HostIP != "" {
+				host = b.HostIP + ":" + host
+			}
+			if host == "" {
+				host = "random"
+			}
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		cfg.Sections[DiffPorts][string(port)] = strings.Join(hosts, ", ")
+	}
+	for port := range inspect.Config.ExposedPorts {
+		if _, ok := cfg.Sections[DiffPorts][string(port)]; !ok {
+			cfg.Sections[DiffPorts][string(port)] = "exposed only"
+		}
+	}
+
+	runtime := cfg.Sections[DiffRuntime]
+	runtime["entrypoint"] = strings.Join(inspect.Config.Entrypoint, " ")
+	runtime["command"] = strings.Join(inspect.Config.Cmd, " ")
+	runtime["user"] = inspect.Config.User
+	runtime["working dir"] = inspect.Config.WorkingDir
+	runtime["restart policy"] = string(inspect.HostConfig.RestartPolicy.Name)
+	runtime["network mode"] = string(inspect.HostConfig.NetworkMode)
+	var networks []string
+	for name := range inspect.NetworkSettings.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	runtime["networks"] = strings.Join(networks, ", ")
+	runtime["memory limit"] = formatLimit(inspect.HostConfig.Memory)
+	runtime["cpus"] = fmt.Sprintf("%g", float64(inspect.HostConfig.NanoCPUs)/1e9)
+	runtime["log driver"] = inspect.HostConfig.LogConfig.Type
+	runtime["privileged"] = fmt.Sprint(inspect.HostConfig.Privileged)
+	runtime["read-only rootfs"] = fmt.Sprint(inspect.HostConfig.ReadonlyRootfs)
+	if inspect.Config.Healthcheck != nil {
+		runtime["healthcheck"] = strings.Join(inspect.Config.Healthcheck.Test, " ")
+	}
+	return cfg, nil
+}
+
+// shortImageID shortens an image ID the way docker images shows it
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	return id[:min(12, len(id))]
+}
+
+// formatLimit prints a memory limit, "none" when unlimited
+func formatLimit(bytes int64) string {
+	if bytes <= 0 {
+		return "none"
+	}
+	return FormatBytes(uint64(bytes))
+}
+
+// DiffConfigs lines up the settings of two containers key by key, section by section,
+// with the keys of each section sorted
+func DiffConfigs(left, right ContainerConfig) []ConfigDiffEntry {
+	var entries []ConfigDiffEntry
+	for _, section := range DiffSections {
+		l, r := left.Sections[section], right.Sections[section]
+		keys := make(map[string]bool)
+		for k := range l {
+			keys[k] = true
+		}
+		for k := range r {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			e := ConfigDiffEntry{Section: section, Key: k}
+			e.Left, e.HasLeft = l[k]
+			e.Right, e.HasRight = r[k]
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// probeScript checks a destination from inside a container with whatever tools the
+// image has. It prints one "KEY value" line per finding: DNS (the resolved address,
+// empty when resolution failed, ? without a resolver tool), TOOL, MS, SEC or NS (the
+// latency in that unit) and RESULT OK or RESULT FAIL <reason>.
+const probeScript = `h=__HOST__; p=__PORT__
+now() { t=$(date +%s%N 2>/dev/null); case "$t" in *N*) ;; *) echo "$t" ;; esac; }
+if [ -n "__RESOLVE__" ]; then
+  if command -v getent >/dev/null 2>&1; then
+    echo "DNS $(getent hosts "$h" | awk 'NR==1 {print $1}')"
+  elif command -v nslookup >/dev/null 2>&1; then
+    echo "DNS $(nslookup "$h" 2>/dev/null | sed -n '/^Name/,$p' | grep -oE '([0-9]{1,3}\.){3}[0-9]{1,3}' | head -n 1)"
+  else
+    echo "DNS ?"
+  fi
+fi
+if [ -z "$p" ]; then
+  if command -v ping >/dev/null 2>&1; then
+    echo "TOOL ping"
+    out=$(ping -c 3 -W 2 "$h" 2>&1); rc=$?
+    echo "$out" | grep 'min/avg' | sed 's/.*= *//' | awk -F/ '{print "MS " $2}'
+    if [ $rc -eq 0 ]; then echo "RESULT OK"; else echo "RESULT FAIL $(echo "$out" | tail -n 1)"; fi
+  else
+    echo "TOOL none"
+  fi
+  exit 0
+fi
+t0=$(now)
+if command -v nc >/dev/null 2>&1 && nc -h 2>&1 | grep -q -- '-z'; then
+  echo "TOOL nc"
+  if out=$(nc -z -w 3 "$h" "$p" 2>&1); then r="OK"; else r="FAIL ${out:-connection failed}"; fi
+elif command -v curl >/dev/null 2>&1; then
+  echo "TOOL curl"
+  s=$(curl -s -o /dev/null --connect-timeout 3 -m 5 -w '%{time_connect}' "http://$h:$p/" 2>/dev/null); rc=$?
+  case $rc in
+    6) r="FAIL could not resolve host" ;;
+    7) r="FAIL connection refused" ;;
+    28) r="FAIL timed out" ;;
+    *) r="OK"; echo "SEC $s"; t0= ;;
+  esac
+elif command -v bash >/dev/null 2>&1; then
+  echo "TOOL bash"
+  to=; command -v timeout >/dev/null 2>&1 && to="timeout 3"
+  if out=$($to bash -c "exec 3<>/dev/tcp/$h/$p" 2>&1); then r="OK"; else r="FAIL ${out:-connection failed}"; fi
+elif command -v wget >/dev/null 2>&1; then
+  echo "TOOL wget"
+  out=$(wget -q -T 3 -O /dev/null "http://$h:$p/" 2>&1); rc=$?
+  if [ $rc -eq 0 ] || ! echo "$out" | grep -qiE 'refused|timed out|can.t connect|unable to|no route|bad address|unreachable'; then r="OK"; else r="FAIL $out"; fi
+else
+  echo "TOOL none"
+  exit 0
+fi
+t1=$(now)
+if [ -n "$t0" ] && [ -n "$t1" ]; then echo "NS $((t1 - t0))"; fi
+echo "RESULT $r"
+exit 0
+`
+
+// ProbeResult is what a connectivity check from inside a container found
+type ProbeResult struct {
+	Host     string
+	Port     string // empty for a ping
+	Resolved string // address the host resolved to; empty when the host is an IP
+	DNSError bool   // the host name didn't resolve
+	NoDNS    bool   // the image has no tool to resolve names with
+	Tool     string // ping, nc, curl, bash or wget; empty when the image has none
+	OK       bool
+	Reason   string        // why the check failed
+	Latency  time.Duration // round trip for ping, connect time otherwise; 0 when unknown
+}
+
+// Probe checks from inside a running container whether host (and port, when set) can
+// be reached, using the tools found in the image
+func Probe(containerID string, shell Shell, host, port string) (*ProbeResult, error) {
+	if host == "" || strings.ContainsAny(host, " '\"$`\\;|&<>") {
+		return nil, fmt.Errorf("invalid host %q", host)
+	}
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid port %q", port)
+		}
+	}
+
+	resolve := "yes"
+	if net.ParseIP(host) != nil {
+		resolve = ""
+	}
+	script := strings.NewReplacer("__HOST__", "'"+host+"'", "__PORT__", "'"+port+"'", "__RESOLVE__", resolve).Replace(probeScript)
+	output, err := ExecCommandWith(containerID, script, ExecOptions{Shell: shell})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProbeResult{Host: host, Port: port}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		switch key {
+		case "DNS":
+			switch value {
+			case "?":
+				result.NoDNS = true
+			case "":
+				result.DNSError = true
+			default:
+				result.Resolved = value
+			}
+		case "TOOL":
+			if value != "none" {
+				result.Tool = value
+			}
+		case "MS", "SEC":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				unit := time.Millisecond
+				if key == "SEC" {
+					unit = time.Second
+				}
+				result.Latency = time.Duration(f * float64(unit))
+			}
+		case "NS":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				result.Latency = time.Duration(n)
+			}
+		case "RESULT":
+			status, reason, _ := strings.Cut(value, " ")
+			result.OK, result.Reason = status == "OK", strings.TrimSpace(reason)
+		}
+	}
+	if !result.OK {
+		result.Latency = 0 // the time until a failure says nothing about the path
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DaemonProfile is what a daemon's setup allows the dashboard to do
+type DaemonProfile struct {
+	OSType        string // linux or windows
+	Rootless      bool
+	CgroupVersion string // "1" or "2"; empty on Windows
+	CgroupDriver  string // cgroupfs or systemd
+}
+
+// Windows reports whether the daemon runs Windows containers
+func (p DaemonProfile) Windows() bool {
+	return p.OSType == "windows"
+}
+
+// HasStats reports whether the daemon can report container usage; rootless daemons
+// need cgroup v2 for it
+func (p DaemonProfile) HasStats() bool {
+	return !p.Rootless || p.CgroupVersion == "2"
+}
+
+// CanLimit reports whether resource limits can be changed; rootless daemons need
+// cgroup v2 delegated through systemd, and Windows ignores updates of them
+func (p DaemonProfile) CanLimit() bool {
+	if p.Windows() {
+		return false
+	}
+	return !p.Rootless || (p.CgroupVersion == "2" && p.CgroupDriver == "systemd")
+}
+
+// Describe summarises the profile, e.g. "rootless, cgroup v2 (systemd)"
+func (p DaemonProfile) Describe() string {
+	if p.Windows() {
+		return "windows containers"
+	}
+	mode := "rootful"
+	if p.Rootless {
+		mode = "rootless"
+	}
+	return fmt.Sprintf("%s, cgroup v%s (%s)", mode, p.CgroupVersion, p.CgroupDriver)
+}
+
+// profiles caches the profile of every daemon by address; rootless mode and the cgroup
+// setup only change with a reboot
+var profiles sync.Map
+
+// LoadProfile asks a daemon for its profile, once per daemon
+func LoadProfile(e Endpoint) (DaemonProfile, error) {
+	if p, ok := CachedProfile(e); ok {
+		return p, nil
+	}
+
+	cli, err := newClient(e)
+	if err != nil {
+		return DaemonProfile{}, decodeError(err)
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return DaemonProfile{}, decodeError(err)
+	}
+	p := DaemonProfile{
+		OSType:        info.OSType,
+		Rootless:      isRootless(info.SecurityOptions),
+		CgroupVersion: info.CgroupVersion,
+		CgroupDriver:  info.CgroupDriver,
+	}
+	profiles.Store(e.HostURL(), p)
+	return p, nil
+}
+
+// CachedProfile returns the profile of a daemon if it was loaded already
+func CachedProfile(e Endpoint) (DaemonProfile, bool) {
+	p, ok := profiles.Load(e.HostURL())
+	if !ok {
+		return DaemonProfile{}, false
+	}
+	return p.(DaemonProfile), true
+}
+
+// ProfileOf returns the profile of the daemon a container runs on, if it was loaded
+func ProfileOf(containerID string) (DaemonProfile, bool) {
+	return CachedProfile(endpointFor(containerID))
+}
+
+// onWindows reports whether a container runs on a Windows daemon, asking the daemon
+// the first time; when it can't be asked the container is taken for a Linux one
+func onWindows(containerID string) bool {
+	p, err := LoadProfile(endpointFor(containerID))
+	return err == nil && p.Windows()
+}
+
+// isRootless reports whether a daemon's security options mark it as rootless
+func isRootless(securityOptions []string) bool {
+	for _, opt := range securityOptions {
+		if opt == "name=rootless" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// Compose labels used to resolve depends_on between services
+const (
+	composeProjectLabel   = "com.docker.compose.project"
+	composeServiceLabel   = "com.docker.compose.service"
+	composeDependsOnLabel = "com.docker.compose.depends_on"
+)
+
+// DependencyLevels groups containers so that every container only depends on containers
+// in earlier groups. Dependencies come from compose depends_on, links, volumes_from and
+// container network mode; only dependencies within the given set are considered.
+// Containers caught in a cycle are placed in a final group.
+func DependencyLevels(containerIDs []string) ([][]string, error) {
+	ctx := context.Background()
+	inspects := make([]types.ContainerJSON, 0, len(containerIDs))
+	for _, id := range containerIDs {
+		// In the all hosts view the selection can span daemons
+		cli, err := clientFor(id)
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		inspect, err := cli.ContainerInspect(ctx, id)
+		cli.Close()
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		inspects = append(inspects, inspect)
+	}
+
+	// Resolve names, short IDs and compose services to positions in the selection
+	byRef := make(map[string]int)
+	for i, c := range inspects {
+		byRef[c.ID] = i
+		byRef[c.ID[:12]] = i
+		byRef[strings.TrimPrefix(c.Name, "/")] = i
+		if c.Config != nil {
+			if project, service := c.Config.Labels[composeProjectLabel], c.Config.Labels[composeServiceLabel]; service != "" {
+				byRef["compose:"+project+"/"+service] = i
+			}
+		}
+	}
+
+	deps := make([]map[int]bool, len(inspects))
+	for i, c := range inspects {
+		deps[i] = make(map[int]bool)
+		for _, ref := range dependencyRefs(c) {
+			if j, ok := byRef[ref]; ok && j != i {
+				deps[i][j] = true
+			}
+		}
+	}
+
+	var levels [][]string
+	done := make([]bool, len(inspects))
+	remaining := len(inspects)
+	for remaining > 0 {
+		var level []int
+		for i := range inspects {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for j := range deps[i] {
+				if !done[j] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				level = append(level, i)
+			}
+		}
+
+		// A cycle: nothing is ready, so take the rest in selection order
+		if len(level) == 0 {
+			for i := range inspects {
+				if !done[i] {
+					level = append(level, i)
+				}
+			}
+		}
+
+		ids := make([]string, 0, len(level))
+		for _, i := range level {
+			done[i] = true
+			ids = append(ids, containerIDs[i])
+		}
+		remaining -= len(level)
+		levels = append(levels, ids)
+	}
+
+	return levels, nil
+}
+
+// dependencyRefs returns the names, IDs or compose services a container depends on
+func dependencyRefs(c types.ContainerJSON) []string {
+	var refs []string
+
+	if c.HostConfig != nil {
+		// Links look like "/db:/web/db"
+		for _, link := range c.HostConfig.Links {
+			name, _, _ := strings.Cut(link, ":")
+			refs = append(refs, strings.TrimPrefix(name, "/"))
+		}
+		// volumes_from entries look like "data" or "data:ro"
+		for _, from := range c.HostConfig.VolumesFrom {
+			name, _, _ := strings.Cut(from, ":")
+			refs = append(refs, name)
+		}
+		if c.HostConfig.NetworkMode.IsContainer() {
+			refs = append(refs, c.HostConfig.NetworkMode.ConnectedContainer())
+		}
+	}
+
+	// Compose writes depends_on as "service:condition:restart,..."
+	if c.Config != nil {
+		project := c.Config.Labels[composeProjectLabel]
+		for _, dep := range strings.Split(c.Config.Labels[composeDependsOnLabel], ",") {
+			if service, _, _ := strings.Cut(strings.TrimSpace(dep), ":"); service != "" {
+				refs = append(refs, "compose:"+project+"/"+service)
+			}
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// DeployContainer is a container of a deployment being watched
+type DeployContainer struct {
+	ID       string
+	Name     string
+	Service  string // compose service, "" outside compose
+	Image    string
+	ImageID  string
+	Created  time.Time
+	State    string // running, exited, restarting, ...
+	Health   string // starting, healthy or unhealthy; "" without a healthcheck
+	Restarts int
+	ExitCode int
+}
+
+// DeployContainers returns the containers of the current host carrying every label of
+// selector, stopped ones included, oldest first. An empty label value only needs the
+// key.
+func DeployContainers(selector map[string]string) ([]DeployContainer, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	args := filters.NewArgs()
+	for k, v := range selector {
+		if v == "" {
+			args.Add("label", k)
+		} else {
+			args.Add("label", k+"="+v)
+		}
+	}
+	ctx := context.Background()
+	list, err := cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	var containers []DeployContainer
+	for _, c := range list {
+		inspect, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			continue // removed since the list
+		}
+		dc := DeployContainer{
+			ID:       c.ID,
+			Name:     strings.TrimPrefix(inspect.Name, "/"),
+			Service:  c.Labels[composeServiceLabel],
+			Image:    inspect.Config.Image,
+			ImageID:  strings.TrimPrefix(inspect.Image, "sha256:"),
+			Created:  time.Unix(c.Created, 0),
+			Restarts: inspect.RestartCount,
+		}
+		if created, err := time.Parse(time.RFC3339Nano, inspect.Created); err == nil {
+			dc.Created = created
+		}
+		if s := inspect.State; s != nil {
+			dc.State = s.Status
+			dc.ExitCode = s.ExitCode
+			if s.Health != nil {
+				dc.Health = s.Health.Status
+			}
+		}
+		containers = append(containers, dc)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].Created.Before(containers[j].Created)
+	})
+	return containers, nil
+}
+
+// ComposeProjects returns the compose projects with containers on the current host,
+// sorted by name
+func ComposeProjects() ([]string, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	list, err := cli.ContainerList(context.Background(), types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", composeProjectLabel)),
+	})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	seen := make(map[string]bool)
+	var projects []string
+	for _, c := range list {
+		if p := c.Labels[composeProjectLabel]; !seen[p] {
+			seen[p] = true
+			projects = append(projects, p)
+		}
+	}
+	sort.Strings(projects)
+	return projects, nil
+}
+
+// ComposeProjectSelector returns the label selector matching a compose project
+func ComposeProjectSelector(project string) map[string]string {
+	return map[string]string{composeProjectLabel: project}
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// DiskUsage is the disk space a daemon's objects take, in bytes, like docker system df
+type DiskUsage struct {
+	Images     int64
+	Containers int64 // writable layers
+	Volumes    int64 // local volumes only; other drivers don't report a size
+	BuildCache int64
+}
+
+// Total returns the space taken by all objects
+func (u DiskUsage) Total() int64 {
+	return u.Images + u.Containers + u.Volumes + u.BuildCache
+}
+
+// DiskUsageOn returns the disk usage of a daemon. The daemon has to walk every layer
+// and volume for this, so it can take a while.
+func DiskUsageOn(e Endpoint) (DiskUsage, error) {
+	cli, err := newClient(e)
+	if err != nil {
+		return DiskUsage{}, decodeError(err)
+	}
+	defer cli.Close()
+
+	df, err := cli.DiskUsage(context.Background(), types.DiskUsageOptions{})
+	if err != nil {
+		return DiskUsage{}, decodeError(err)
+	}
+
+	usage := DiskUsage{Images: df.LayersSize}
+	for _, c := range df.Containers {
+		usage.Containers += c.SizeRw
+	}
+	for _, v := range df.Volumes {
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			usage.Volumes += v.UsageData.Size
+		}
+	}
+	for _, b := range df.BuildCache {
+		usage.BuildCache += b.Size
+	}
+	return usage, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package docker
+
+import "errors"
+
+// diskSpace is not implemented on this platform
+func diskSpace(path string) (free, total uint64, err error) {
+	return 0, 0, errors.New("not supported on this platform")
+}
@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmbeddedDNS is the resolver Docker runs for containers on user-defined networks
+const EmbeddedDNS = "127.0.0.11"
+
+// DNSConfig is how a container resolves names
+type DNSConfig struct {
+	Nameservers []string
+	Search      []string
+	Options     []string
+
+	// Set with --dns, --dns-search and --dns-option (or the compose equivalents)
+	ConfiguredServers []string
+	ConfiguredSearch  []string
+	ConfiguredOptions []string
+}
+
+// GetDNSConfig reads the resolv.conf of a container, running or not, and the DNS
+// settings it was created with
+func GetDNSConfig(containerID string) (*DNSConfig, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	config := &DNSConfig{}
+	if inspect.HostConfig != nil {
+		config.ConfiguredServers = inspect.HostConfig.DNS
+		config.ConfiguredSearch = inspect.HostConfig.DNSSearch
+		config.ConfiguredOptions = inspect.HostConfig.DNSOptions
+	}
+
+	reader, _, err := cli.CopyFromContainer(ctx, containerID, "/etc/resolv.conf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /etc/resolv.conf: %w", decodeError(err))
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	if _, err := tr.Next(); err != nil {
+		return nil, fmt.Errorf("failed to read /etc/resolv.conf: %w", err)
+	}
+	scanner := bufio.NewScanner(io.LimitReader(tr, 1<<20))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			config.Nameservers = append(config.Nameservers, fields[1])
+		case "search", "domain":
+			config.Search = append(config.Search, fields[1:]...)
+		case "options":
+			config.Options = append(config.Options, fields[1:]...)
+		}
+	}
+	return config, scanner.Err()
+}
+
+// lookupScript resolves a name inside a container with getent or nslookup, printing
+// TOOL, one ADDR line per address and NS, the time taken
+const lookupScript = `n=__NAME__
+now() { t=$(date +%s%N 2>/dev/null); case "$t" in *N*) ;; *) echo "$t" ;; esac; }
+t0=$(now)
+if command -v getent >/dev/null 2>&1; then
+  echo "TOOL getent"
+  out=$(getent ahosts "$n" 2>/dev/null || getent hosts "$n" 2>/dev/null)
+  echo "$out" | awk 'NF {print "ADDR " $1}' | sort -u
+elif command -v nslookup >/dev/null 2>&1; then
+  echo "TOOL nslookup"
+  nslookup "$n" 2>/dev/null | sed -n '/^Name/,$p' | grep -oE '([0-9]{1,3}\.){3}[0-9]{1,3}|([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}' | sort -u | sed 's/^/ADDR /'
+else
+  echo "TOOL none"
+  exit 0
+fi
+t1=$(now)
+if [ -n "$t0" ] && [ -n "$t1" ]; then echo "NS $((t1 - t0))"; fi
+exit 0
+`
+
+// Lookup is the result of resolving a name inside a container
+type Lookup struct {
+	Name      string
+	Addresses []string // empty when the name didn't resolve
+	Tool      string   // getent or nslookup; empty when the image has neither
+	Took      time.Duration
+}
+
+// LookupName resolves a name the way a running container does
+func LookupName(containerID string, shell Shell, name string) (*Lookup, error) {
+	if name == "" || strings.ContainsAny(name, " '\"$`\\;|&<>") {
+		return nil, fmt.Errorf("invalid name %q", name)
+	}
+
+	script := strings.ReplaceAll(lookupScript, "__NAME__", "'"+name+"'")
+	output, err := ExecCommandWith(containerID, script, ExecOptions{Shell: shell})
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := &Lookup{Name: name}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		switch key {
+		case "TOOL":
+			if value != "none" {
+				lookup.Tool = value
+			}
+		case "ADDR":
+			lookup.Addresses = append(lookup.Addresses, value)
+		case "NS":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				lookup.Took = time.Duration(n)
+			}
+		}
+	}
+	return lookup, nil
+}
@@ -3,13 +3,16 @@ package docker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 type ContainerInfo struct {
@@ -20,6 +23,8 @@ type ContainerInfo struct {
 	Created string
 	Ports   string
 	State   string
+	Labels  map[string]string
+	Host    string // endpoint name, set when listing several hosts
 }
 
 type ContainerStats struct {
@@ -29,42 +34,83 @@ type ContainerStats struct {
 	NetIO    string
 	BlockIO  string
 	PIDs     string
+
+	MemUsed  uint64
+	MemLimit uint64 // host memory when the container has no limit
+
+	// Cumulative byte counters since the container started, read at Read
+	NetRx      uint64
+	NetTx      uint64
+	BlockRead  uint64
+	BlockWrite uint64
+	Read       time.Time
+
+	// CPU quota enforcement: ThrottledPerc covers the last second, the rest the whole
+	// lifetime of the container. All zero without a CPU limit.
+	ThrottledPerc    float64
+	ThrottledPeriods uint64
+	TotalPeriods     uint64
+	ThrottledTime    time.Duration
 }
 
-// getClient creates a new Docker client
+// getClient creates a new Docker client for the current endpoint
 func getClient() (*client.Client, error) {
-	return client.NewClientWithOpts(
-		client.FromEnv,
-		client.WithAPIVersionNegotiation(),
-	)
+	return newClient(CurrentEndpoint())
 }
 
 // CheckDockerConnection verifies Docker daemon is accessible
 func CheckDockerConnection() error {
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+	cli, err := getClient()
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	_, err = cli.Ping(context.Background())
 	if err != nil {
-		return fmt.Errorf("docker not running: %v", err)
+		return fmt.Errorf("docker not running: %w", decodeError(err))
 	}
 	return nil
 }
 
+// Ping checks that the daemon answers within timeout
+func Ping(timeout time.Duration) error {
+	cli, err := getClient()
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err = cli.Ping(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &Error{
+			Kind:    ErrDaemonUnreachable,
+			Message: "The Docker daemon did not answer in time.",
+			Hint:    "It may be restarting or overloaded; check systemctl status docker.",
+			Err:     err,
+		}
+	}
+	return decodeError(err)
+}
+
 // ListContainers returns all containers (running and stopped)
 func ListContainers() ([]ContainerInfo, error) {
-	cli, err := getClient()
+	return ListContainersOn(CurrentEndpoint())
+}
+
+// ListContainersOn returns all containers on a specific daemon
+func ListContainersOn(e Endpoint) ([]ContainerInfo, error) {
+	cli, err := newClient(e)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	var result []ContainerInfo
@@ -88,6 +134,7 @@ func ListContainers() ([]ContainerInfo, error) {
 			Created: created,
 			Ports:   ports,
 			State:   c.State,
+			Labels:  c.Labels,
 		}
 		result = append(result, info)
 	}
@@ -97,21 +144,21 @@ func ListContainers() ([]ContainerInfo, error) {
 
 // StartContainer starts a stopped container
 func StartContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
-	return cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+	return decodeError(cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{}))
 }
 
 // StopContainer stops a running container
 func StopContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
@@ -120,14 +167,27 @@ func StopContainer(containerID string) error {
 	stopOptions := container.StopOptions{
 		Timeout: &timeout,
 	}
-	return cli.ContainerStop(ctx, containerID, stopOptions)
+	return decodeError(cli.ContainerStop(ctx, containerID, stopOptions))
+}
+
+// SignalContainer sends a signal, e.g. "HUP", "SIGUSR1" or "10", to the main process of
+// a container through the API, so reloading nginx needs no shell in the image
+func SignalContainer(containerID, signal string) error {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	return decodeError(cli.ContainerKill(ctx, containerID, signal))
 }
 
 // RestartContainer restarts a container
 func RestartContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
@@ -136,74 +196,293 @@ func RestartContainer(containerID string) error {
 	stopOptions := container.StopOptions{
 		Timeout: &timeout,
 	}
-	return cli.ContainerRestart(ctx, containerID, stopOptions)
+	return decodeError(cli.ContainerRestart(ctx, containerID, stopOptions))
 }
 
 // RemoveContainer removes a container (force removes if running)
 func RemoveContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
-	return cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{
+	return decodeError(cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{
 		Force:         true,
 		RemoveVolumes: true,
-	})
+	}))
 }
 
 // StreamLogs streams container logs
-func StreamLogs(containerID string) (io.ReadCloser, error) {
-	cli, err := getClient()
+func StreamLogs(containerID string) (*LogStream, error) {
+	return StreamLogsSince(context.Background(), containerID, time.Time{})
+}
+
+// StreamLogsSince streams logs written after since, or the last 500 lines when since
+// is zero; the stream ends when ctx is cancelled
+func StreamLogsSince(ctx context.Context, containerID string, since time.Time) (*LogStream, error) {
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
-	return cli.ContainerLogs(context.Background(), containerID, types.ContainerLogsOptions{
+	// Only containers without a TTY multiplex stdout and stderr
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
 		Timestamps: true,
 		Tail:       "500", // Last 500 lines
+	}
+	if !since.IsZero() {
+		options.Since = since.Format(time.RFC3339Nano)
+		options.Tail = ""
+	}
+
+	logs, err := cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return &LogStream{ReadCloser: logs, TTY: inspect.Config.Tty}, nil
+}
+
+// StreamLogText follows the log lines written after since, stdout and stderr merged as
+// plain text with an RFC 3339 timestamp in front of each line. A zero since starts with
+// new lines. The stream ends when the container stops or ctx is cancelled.
+func StreamLogText(ctx context.Context, containerID string, since time.Time) (io.ReadCloser, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: true,
+		Tail:       "0",
+	}
+	if !since.IsZero() {
+		options.Since = since.Format(time.RFC3339Nano)
+		options.Tail = ""
+	}
+
+	logs, err := cli.ContainerLogs(ctx, containerID, options)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	// Without a TTY both streams arrive multiplexed with a header per frame
+	if inspect.Config.Tty {
+		return logs, nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, logs)
+		logs.Close()
+		pw.CloseWithError(err)
+	}()
+	return &demuxedLogs{PipeReader: pr, logs: logs}, nil
+}
+
+// RecentLogs returns the last tail lines of a container's log, each with its timestamp,
+// without following it
+func RecentLogs(ctx context.Context, containerID string, tail int) ([]LogLine, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	logs, err := cli.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       fmt.Sprint(tail),
 	})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	stream := &LogStream{ReadCloser: logs, TTY: inspect.Config.Tty}
+	defer stream.Close()
+
+	var lines []LogLine
+	err = stream.Lines(func(line LogLine) {
+		lines = append(lines, line)
+	})
+	return lines, err
+}
+
+// SplitLogLine separates the timestamp StreamLogText puts in front of a line from the
+// message; ok is false when the line has no timestamp
+func SplitLogLine(line string) (at time.Time, msg string, ok bool) {
+	stamp, msg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return time.Time{}, strings.TrimRight(line, "\r\n"), false
+	}
+	return at, msg, true
+}
+
+// demuxedLogs closes the daemon stream together with the demultiplexed side
+type demuxedLogs struct {
+	*io.PipeReader
+	logs io.Closer
+}
+
+func (d *demuxedLogs) Close() error {
+	d.logs.Close()
+	return d.PipeReader.Close()
 }
 
 // GetStats retrieves live container statistics
 func GetStats(containerID string) (*ContainerStats, error) {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	stats, err := cli.ContainerStats(ctx, containerID, false)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer stats.Body.Close()
 
 	var v types.StatsJSON
 	if err := json.NewDecoder(stats.Body).Decode(&v); err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
+	return statsFromJSON(v, stats.OSType == "windows"), nil
+}
+
+// StreamStats follows the stats of a container over one long-lived request until ctx is
+// cancelled. The daemon sends a sample about every second; a consumer that falls behind
+// only gets the latest one. Both channels close when the stream ends; an error, if any,
+// is sent first.
+func StreamStats(ctx context.Context, containerID string) (<-chan *ContainerStats, <-chan error) {
+	out := make(chan *ContainerStats, 1)
+	errs := make(chan error, 1)
+
+	cli, err := clientFor(containerID)
+	if err != nil {
+		errs <- decodeError(err)
+		close(out)
+		close(errs)
+		return out, errs
+	}
+
+	go func() {
+		defer cli.Close()
+		defer close(errs)
+		defer close(out)
+
+		stats, err := cli.ContainerStats(ctx, containerID, true)
+		if err != nil {
+			errs <- decodeError(err)
+			return
+		}
+		defer stats.Body.Close()
+
+		decoder := json.NewDecoder(stats.Body)
+		for {
+			var v types.StatsJSON
+			if err := decoder.Decode(&v); err != nil {
+				if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+					errs <- decodeError(err)
+				}
+				return
+			}
+			// The first sample has no previous one to compute CPU usage against; Windows
+			// reports no system usage, only when the previous sample was read
+			windows := stats.OSType == "windows"
+			if (windows && v.PreRead.IsZero()) || (!windows && v.PreCPUStats.SystemUsage == 0) {
+				continue
+			}
+
+			sample := statsFromJSON(v, windows)
+			// Replace an unread sample rather than blocking the stream
+			select {
+			case <-out:
+			default:
+			}
+			select {
+			case out <- sample:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, errs
+}
+
+// memoryInUse is the memory usage docker stats shows: without the inactive page cache,
+// which the kernel reclaims before it runs out. cgroup v1 reports the cache as
+// total_inactive_file, cgroup v2 as inactive_file.
+func memoryInUse(m types.MemoryStats) uint64 {
+	inactive, ok := m.Stats["total_inactive_file"]
+	if !ok {
+		inactive = m.Stats["inactive_file"]
+	}
+	if inactive < m.Usage {
+		return m.Usage - inactive
+	}
+	return m.Usage
+}
+
+// statsFromJSON turns a raw stats sample into display values. Windows daemons send
+// no cgroup fields: CPU time is in 100ns units against the wall clock, memory is the
+// private working set without a limit, and disk I/O comes as storage stats.
+func statsFromJSON(v types.StatsJSON, windows bool) *ContainerStats {
 
 	// Calculate CPU percentage
 	cpuDelta := float64(v.CPUStats.CPUUsage.TotalUsage - v.PreCPUStats.CPUUsage.TotalUsage)
 	systemDelta := float64(v.CPUStats.SystemUsage - v.PreCPUStats.SystemUsage)
+	// cgroup v2 leaves the per-CPU usage empty
+	cpus := float64(v.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(v.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if windows {
+		// Every processor adds one 100ns interval per 100ns of wall clock
+		systemDelta = float64(v.Read.Sub(v.PreRead).Nanoseconds()/100) * float64(v.NumProcs)
+		cpus = 1
+	}
 	cpuPercent := 0.0
 	if systemDelta > 0 && cpuDelta > 0 {
-		cpuPercent = (cpuDelta / systemDelta) * float64(len(v.CPUStats.CPUUsage.PercpuUsage)) * 100.0
+		cpuPercent = (cpuDelta / systemDelta) * cpus * 100.0
 	}
 
 	// Calculate memory usage
-	memUsage := float64(v.MemoryStats.Usage)
+	memUsed := memoryInUse(v.MemoryStats)
+	if windows {
+		memUsed = v.MemoryStats.PrivateWorkingSet
+	}
+	memUsage := float64(memUsed)
 	memLimit := float64(v.MemoryStats.Limit)
 	memPercent := 0.0
+	memText := FormatBytes(uint64(memUsage))
 	if memLimit > 0 {
 		memPercent = (memUsage / memLimit) * 100.0
+		memText += " / " + FormatBytes(uint64(memLimit))
 	}
 
 	// Calculate network I/O
@@ -216,35 +495,62 @@ func GetStats(containerID string) (*ContainerStats, error) {
 	// Calculate block I/O
 	var blockRead, blockWrite uint64
 	for _, bio := range v.BlkioStats.IoServiceBytesRecursive {
-		if bio.Op == "Read" {
+		// cgroup v1 reports "Read"/"Write", cgroup v2 "read"/"write"
+		if strings.EqualFold(bio.Op, "read") {
 			blockRead += bio.Value
-		} else if bio.Op == "Write" {
+		} else if strings.EqualFold(bio.Op, "write") {
 			blockWrite += bio.Value
 		}
 	}
+	if windows {
+		blockRead, blockWrite = v.StorageStats.ReadSizeBytes, v.StorageStats.WriteSizeBytes
+	}
+
+	// One-shot requests sample twice and streams include the previous sample, so this is
+	// the throttling over the last second
+	throttling, prev := v.CPUStats.ThrottlingData, v.PreCPUStats.ThrottlingData
+	throttledPercent := 0.0
+	if throttling.Periods > prev.Periods && throttling.ThrottledPeriods >= prev.ThrottledPeriods {
+		throttledPercent = float64(throttling.ThrottledPeriods-prev.ThrottledPeriods) /
+			float64(throttling.Periods-prev.Periods) * 100.0
+	}
 
 	return &ContainerStats{
 		CPUPerc:  fmt.Sprintf("%.2f%%", cpuPercent),
-		MemUsage: fmt.Sprintf("%s / %s", formatBytes(uint64(memUsage)), formatBytes(uint64(memLimit))),
+		MemUsage: memText,
 		MemPerc:  fmt.Sprintf("%.2f%%", memPercent),
-		NetIO:    fmt.Sprintf("↓ %s / ↑ %s", formatBytes(netRx), formatBytes(netTx)),
-		BlockIO:  fmt.Sprintf("↓ %s / ↑ %s", formatBytes(blockRead), formatBytes(blockWrite)),
+		NetIO:    fmt.Sprintf("↓ %s / ↑ %s", FormatBytes(netRx), FormatBytes(netTx)),
+		BlockIO:  fmt.Sprintf("↓ %s / ↑ %s", FormatBytes(blockRead), FormatBytes(blockWrite)),
 		PIDs:     fmt.Sprintf("%d", v.PidsStats.Current),
-	}, nil
+
+		MemUsed:  memUsed,
+		MemLimit: v.MemoryStats.Limit,
+
+		NetRx:      netRx,
+		NetTx:      netTx,
+		BlockRead:  blockRead,
+		BlockWrite: blockWrite,
+		Read:       v.Read,
+
+		ThrottledPerc:    throttledPercent,
+		ThrottledPeriods: throttling.ThrottledPeriods,
+		TotalPeriods:     throttling.Periods,
+		ThrottledTime:    time.Duration(throttling.ThrottledTime),
+	}
 }
 
 // InspectContainer returns detailed container information
 func InspectContainer(containerID string) (string, error) {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return "", err
+		return "", decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	inspect, err := cli.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return "", err
+		return "", decodeError(err)
 	}
 
 	// Format the inspection data
@@ -324,30 +630,30 @@ func InspectContainer(containerID string) (string, error) {
 
 // PauseContainer pauses a running container
 func PauseContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
-	return cli.ContainerPause(ctx, containerID)
+	return decodeError(cli.ContainerPause(ctx, containerID))
 }
 
 // UnpauseContainer unpauses a paused container
 func UnpauseContainer(containerID string) error {
-	cli, err := getClient()
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
-	return cli.ContainerUnpause(ctx, containerID)
+	return decodeError(cli.ContainerUnpause(ctx, containerID))
 }
 
-// Helper function to format bytes
-func formatBytes(bytes uint64) string {
+// FormatBytes formats a byte count with binary units, e.g. 1.50 GB
+func FormatBytes(bytes uint64) string {
 	const unit = 1024
 	if bytes < unit {
 		return fmt.Sprintf("%d B", bytes)
@@ -383,14 +689,14 @@ func formatPorts(ports []types.Port) string {
 func GetDockerInfo() (string, error) {
 	cli, err := getClient()
 	if err != nil {
-		return "", err
+		return "", decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	info, err := cli.Info(ctx)
 	if err != nil {
-		return "", err
+		return "", decodeError(err)
 	}
 
 	result := fmt.Sprintf(`Docker System Information:
@@ -9,12 +9,12 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
-	"github.com/docker/docker/client"
 )
 
 // NetworkInfo contains detailed network information
@@ -121,16 +121,16 @@ type ProcessMetrics struct {
 
 // GetVolumeDetails retrieves detailed volume information
 func GetVolumeDetails(containerID string) ([]VolumeDetail, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	inspect, err := cli.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	var volumes []VolumeDetail
@@ -168,24 +168,32 @@ func GetVolumeDetails(containerID string) ([]VolumeDetail, error) {
 	return volumes, nil
 }
 
+// statOr returns a memory stat under its cgroup v1 name, or else its cgroup v2 name
+func statOr(stats map[string]uint64, v1, v2 string) uint64 {
+	if value, ok := stats[v1]; ok {
+		return value
+	}
+	return stats[v2]
+}
+
 // GetPerformanceMetrics retrieves comprehensive performance metrics
 func GetPerformanceMetrics(containerID string) (*PerformanceMetrics, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	stats, err := cli.ContainerStats(ctx, containerID, false)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer stats.Body.Close()
 
 	var containerStats types.StatsJSON
 	if err := json.NewDecoder(stats.Body).Decode(&containerStats); err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	metrics := &PerformanceMetrics{
@@ -205,17 +213,19 @@ func GetPerformanceMetrics(containerID string) (*PerformanceMetrics, error) {
 		},
 	}
 
-	// Memory Metrics
+	// Memory Metrics; cgroup v2 names the page cache "file" and the RSS "anon", and
+	// doesn't report swap or the peak usage
+	memStats := containerStats.MemoryStats.Stats
 	metrics.MemoryStats = MemoryMetrics{
 		Usage:           containerStats.MemoryStats.Usage,
 		MaxUsage:        containerStats.MemoryStats.MaxUsage,
 		Limit:           containerStats.MemoryStats.Limit,
-		Cache:           containerStats.MemoryStats.Stats["cache"],
-		RSS:             containerStats.MemoryStats.Stats["rss"],
-		Swap:            containerStats.MemoryStats.Stats["swap"],
-		WorkingSet:      containerStats.MemoryStats.Stats["working_set"],
-		PageFaults:      containerStats.MemoryStats.Stats["pgfault"],
-		MajorPageFaults: containerStats.MemoryStats.Stats["pgmajfault"],
+		Cache:           statOr(memStats, "cache", "file"),
+		RSS:             statOr(memStats, "rss", "anon"),
+		Swap:            memStats["swap"],
+		WorkingSet:      memoryInUse(containerStats.MemoryStats),
+		PageFaults:      memStats["pgfault"],
+		MajorPageFaults: memStats["pgmajfault"],
 	}
 
 	// Network Metrics
@@ -230,23 +240,39 @@ func GetPerformanceMetrics(containerID string) (*PerformanceMetrics, error) {
 		metrics.NetworkStats.TxDropped += netStats.TxDropped
 	}
 
-	// Block I/O Metrics
+	// Block I/O Metrics; cgroup v1 reports "Read"/"Write", cgroup v2 "read"/"write"
+	// and no operation counts
 	for _, ioStat := range containerStats.BlkioStats.IoServiceBytesRecursive {
-		if ioStat.Op == "Read" {
+		if strings.EqualFold(ioStat.Op, "read") {
 			metrics.BlockIOStats.ReadBytes += ioStat.Value
-		} else if ioStat.Op == "Write" {
+		} else if strings.EqualFold(ioStat.Op, "write") {
 			metrics.BlockIOStats.WriteBytes += ioStat.Value
 		}
 	}
 
 	for _, ioStat := range containerStats.BlkioStats.IoServicedRecursive {
-		if ioStat.Op == "Read" {
+		if strings.EqualFold(ioStat.Op, "read") {
 			metrics.BlockIOStats.ReadOps += ioStat.Value
-		} else if ioStat.Op == "Write" {
+		} else if strings.EqualFold(ioStat.Op, "write") {
 			metrics.BlockIOStats.WriteOps += ioStat.Value
 		}
 	}
 
+	// Windows has no cgroups: memory comes as commit and private working set, disk
+	// I/O as storage stats
+	if stats.OSType == "windows" {
+		mem, storage := containerStats.MemoryStats, containerStats.StorageStats
+		metrics.MemoryStats.Usage = mem.Commit
+		metrics.MemoryStats.MaxUsage = mem.CommitPeak
+		metrics.MemoryStats.WorkingSet = mem.PrivateWorkingSet
+		metrics.BlockIOStats = BlockIOMetrics{
+			ReadBytes:  storage.ReadSizeBytes,
+			WriteBytes: storage.WriteSizeBytes,
+			ReadOps:    storage.ReadCountNormalized,
+			WriteOps:   storage.WriteCountNormalized,
+		}
+	}
+
 	// Process Metrics
 	metrics.ProcessStats.ProcessCount = int(containerStats.PidsStats.Current)
 
@@ -268,9 +294,9 @@ func (h *HijackedStream) Close() error {
 
 // ExecCommandStream executes a command and returns output stream
 func ExecCommandStream(containerID string, cmd []string) (io.ReadCloser, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	ctx := context.Background()
@@ -283,12 +309,12 @@ func ExecCommandStream(containerID string, cmd []string) (io.ReadCloser, error)
 
 	execID, err := cli.ContainerExecCreate(ctx, containerID, execConfig)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	resp, err := cli.ContainerExecAttach(ctx, execID.ID, types.ExecStartCheck{})
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	// ✅ Wrap resp.Close() so it matches func() error signature
@@ -305,16 +331,16 @@ func ExecCommandStream(containerID string, cmd []string) (io.ReadCloser, error)
 
 // GetProcessList returns list of processes in container
 func GetProcessList(containerID string) ([]ProcessInfo, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	processes, err := cli.ContainerTop(ctx, containerID, []string{})
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	var processList []ProcessInfo
@@ -337,6 +363,29 @@ func GetProcessList(containerID string) ([]ProcessInfo, error) {
 	return processList, nil
 }
 
+// ProcessTable returns the processes of a running container as ps prints them
+func ProcessTable(containerID string) (string, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	top, err := cli.ContainerTop(context.Background(), containerID, []string{})
+	if err != nil {
+		return "", decodeError(err)
+	}
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(top.Titles, "\t"))
+	for _, proc := range top.Processes {
+		fmt.Fprintln(tw, strings.Join(proc, "\t"))
+	}
+	tw.Flush()
+	return b.String(), nil
+}
+
 type ProcessInfo struct {
 	PID     string
 	User    string
@@ -351,9 +400,9 @@ type ProcessInfo struct {
 
 // GetContainerLogs retrieves logs with options
 func GetContainerLogs(containerID string, since time.Time, tail string) (io.ReadCloser, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	ctx := context.Background()
@@ -369,7 +418,7 @@ func GetContainerLogs(containerID string, since time.Time, tail string) (io.Read
 
 	logs, err := cli.ContainerLogs(ctx, containerID, options)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	return logs, nil
@@ -377,13 +426,21 @@ func GetContainerLogs(containerID string, since time.Time, tail string) (io.Read
 
 // GetNetworkConnections retrieves active network connections
 func GetNetworkConnections(containerID string) ([]NetworkConnection, error) {
+	if onWindows(containerID) {
+		output, err := ExecCommand(containerID, "netstat -ano")
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		return windowsConnections(output), nil
+	}
+
 	// Execute netstat inside container
 	output, err := ExecCommand(containerID, "netstat -tunp")
 	if err != nil {
 		// Try ss if netstat is not available
 		output, err = ExecCommand(containerID, "ss -tunp")
 		if err != nil {
-			return nil, err
+			return nil, decodeError(err)
 		}
 	}
 
@@ -405,6 +462,30 @@ func GetNetworkConnections(containerID string) ([]NetworkConnection, error) {
 	return connections, nil
 }
 
+// windowsConnections parses the output of Windows' netstat -ano: proto, local and
+// foreign address, a state for TCP only, and the PID
+func windowsConnections(output string) []NetworkConnection {
+	var connections []NetworkConnection
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || (fields[0] != "TCP" && fields[0] != "UDP") {
+			continue // blank lines and headers
+		}
+		conn := NetworkConnection{
+			Proto:      strings.ToLower(fields[0]),
+			LocalAddr:  fields[1],
+			RemoteAddr: fields[2],
+			State:      "UNKNOWN",
+			PID:        fields[len(fields)-1],
+		}
+		if len(fields) >= 5 {
+			conn.State = fields[3]
+		}
+		connections = append(connections, conn)
+	}
+	return connections
+}
+
 type NetworkConnection struct {
 	Proto      string
 	LocalAddr  string
@@ -422,16 +503,16 @@ func getConnectionState(fields []string) string {
 
 // CheckContainerHealth performs comprehensive health check
 func CheckContainerHealth(containerID string) (map[string]string, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	inspect, err := cli.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	health := make(map[string]string)
@@ -457,7 +538,10 @@ func CheckContainerHealth(containerID string) (map[string]string, error) {
 	stats, err := GetPerformanceMetrics(containerID)
 	if err == nil {
 		cpuPercent := calculateCPUPercentage(stats)
-		memPercent := float64(stats.MemoryStats.Usage) / float64(stats.MemoryStats.Limit) * 100
+		memPercent := 0.0 // Windows reports no limit
+		if stats.MemoryStats.Limit > 0 {
+			memPercent = float64(stats.MemoryStats.WorkingSet) / float64(stats.MemoryStats.Limit) * 100
+		}
 
 		health["cpu_usage"] = fmt.Sprintf("%.2f%%", cpuPercent)
 		health["memory_usage"] = fmt.Sprintf("%.2f%%", memPercent)
@@ -505,9 +589,9 @@ func calculateCPUPercentage(metrics *PerformanceMetrics) float64 {
 
 // CreateSnapshot creates a container snapshot
 func CreateSnapshot(containerID string, imageName string) error {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
@@ -521,31 +605,31 @@ func CreateSnapshot(containerID string, imageName string) error {
 	}
 
 	_, err = cli.ContainerCommit(ctx, containerID, commitOptions)
-	return err
+	return decodeError(err)
 }
 
 // PruneContainers removes stopped containers
 func PruneContainers() error {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := getClient()
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	_, err = cli.ContainersPrune(ctx, filters.Args{})
-	return err
+	return decodeError(err)
 }
 
 // PruneVolumes removes unused volumes
 func PruneVolumes() error {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := getClient()
 	if err != nil {
-		return err
+		return decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	_, err = cli.VolumesPrune(ctx, filters.Args{})
-	return err
+	return decodeError(err)
 }
@@ -0,0 +1,379 @@
+package docker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types/versions"
+)
+
+// CheckStatus is the outcome of a diagnostic check
+type CheckStatus int
+
+const (
+	CheckOK CheckStatus = iota
+	CheckWarn
+	CheckFail
+)
+
+// Check is one result of RunDiagnostics, with a suggested fix for warnings and failures
+type Check struct {
+	Name   string
+	Status CheckStatus
+	Detail string
+	Fix    string
+}
+
+// minAPIVersion is the oldest daemon API every DockPulse feature works against (Docker 20.10)
+const minAPIVersion = "1.41"
+
+// Free space thresholds for the filesystem holding the Docker root dir
+const (
+	diskWarnPercent = 15
+	diskFailPercent = 5
+)
+
+// RunDiagnostics checks the Docker environment: which daemon is used, socket
+// permissions, ssh and TLS settings, API version compatibility, disk pressure and
+// rootless-mode quirks
+func RunDiagnostics() []Check {
+	e := CurrentEndpoint()
+	host := e.HostURL()
+	checks := []Check{checkHost(e)}
+
+	socket, isLocal := strings.CutPrefix(host, "unix://")
+	if isLocal {
+		checks = append(checks, checkSocket(socket))
+	}
+	if e.IsSSH() {
+		checks = append(checks, checkSSH(e)...)
+	}
+	if e.UsesTLS() {
+		checks = append(checks, checkTLS(e)...)
+	}
+
+	cli, err := getClient()
+	if err != nil {
+		return append(checks, failed("Daemon", err))
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := cli.ServerVersion(ctx)
+	if err != nil {
+		return append(checks, failed("Daemon", err))
+	}
+	checks = append(checks,
+		Check{Name: "Daemon", Status: CheckOK,
+			Detail: fmt.Sprintf("Docker %s on %s/%s", version.Version, version.Os, version.Arch)},
+		checkAPIVersion(version.APIVersion, cli.ClientVersion()))
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return append(checks, failed("Daemon info", err))
+	}
+	if isLocal {
+		checks = append(checks, checkDisk(info.DockerRootDir))
+	}
+	checks = append(checks, checkRootless(info.SecurityOptions, info.CgroupVersion, info.CgroupDriver, isLocal)...)
+	for _, warning := range info.Warnings {
+		checks = append(checks, Check{Name: "Daemon warning", Status: CheckWarn,
+			Detail: strings.TrimPrefix(warning, "WARNING: ")})
+	}
+
+	return checks
+}
+
+// failed turns an error into a failed check, using the decoded message and hint when known
+func failed(name string, err error) Check {
+	c := Check{Name: name, Status: CheckFail, Detail: err.Error()}
+	var de *Error
+	if errors.As(decodeError(err), &de) {
+		c.Detail, c.Fix = de.Message, de.Hint
+	}
+	return c
+}
+
+// checkHost reports which daemon is used and catches setups the SDK doesn't pick up on its
+// own: docker contexts and rootless sockets
+func checkHost(e Endpoint) Check {
+	host := e.HostURL()
+	c := Check{Name: "Docker host", Status: CheckOK, Detail: host}
+	if e.Host != "" {
+		if e.Name != e.Host {
+			c.Detail += fmt.Sprintf(" (host %q from config)", e.Name)
+		}
+		return c
+	}
+	if os.Getenv("DOCKER_HOST") != "" {
+		c.Detail += " (from DOCKER_HOST)"
+		return c
+	}
+
+	if name := currentDockerContext(); name != "" && name != "default" {
+		c.Status = CheckWarn
+		c.Detail += fmt.Sprintf(" (docker context %q is ignored)", name)
+		c.Fix = fmt.Sprintf("export DOCKER_HOST=$(docker context inspect %s -f '{{.Endpoints.docker.Host}}')", name)
+		return c
+	}
+
+	rootless := filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "docker.sock")
+	if _, err := os.Stat(strings.TrimPrefix(host, "unix://")); err != nil && os.Getenv("XDG_RUNTIME_DIR") != "" {
+		if _, err := os.Stat(rootless); err == nil {
+			c.Status = CheckWarn
+			c.Detail += " (missing, but a rootless daemon socket exists)"
+			c.Fix = "export DOCKER_HOST=unix://" + rootless
+		}
+	}
+	return c
+}
+
+// currentDockerContext returns the context selected with docker context use, if any
+func currentDockerContext() string {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if json.Unmarshal(data, &cfg) != nil {
+		return ""
+	}
+	return cfg.CurrentContext
+}
+
+// checkSSH verifies what an ssh:// host needs locally: the ssh binary and the key file
+func checkSSH(e Endpoint) []Check {
+	if !SSHAvailable() {
+		return []Check{{Name: "SSH", Status: CheckFail, Detail: "the ssh command is not installed",
+			Fix: "Install an OpenSSH client; ssh:// hosts are reached through it."}}
+	}
+
+	checks := []Check{{Name: "SSH", Status: CheckOK, Detail: "ssh client found"}}
+	if e.SSHKey != "" {
+		c := Check{Name: "SSH key", Status: CheckOK, Detail: e.SSHKey}
+		if _, err := os.Stat(expandHome(e.SSHKey)); err != nil {
+			c.Status = CheckFail
+			c.Detail += " does not exist"
+			c.Fix = "Fix ssh_key for this host in the config."
+		}
+		checks = append(checks, c)
+	} else if e.SSHAgent == "" && os.Getenv("SSH_AUTH_SOCK") == "" {
+		checks = append(checks, Check{Name: "SSH agent", Status: CheckWarn,
+			Detail: "SSH_AUTH_SOCK is not set and no ssh_key is configured",
+			Fix:    "Start ssh-agent and ssh-add your key, set ssh_key, or rely on IdentityFile in ~/.ssh/config."})
+	}
+	return checks
+}
+
+// certExpiryWarning is how long before expiry a certificate is flagged
+const certExpiryWarning = 14 * 24 * time.Hour
+
+// checkTLS loads the configured TLS files the way the client will and checks expiry dates
+func checkTLS(e Endpoint) []Check {
+	var checks []Check
+
+	if e.TLSCA != "" {
+		c := Check{Name: "TLS CA", Detail: e.TLSCA}
+		data, err := os.ReadFile(expandHome(e.TLSCA))
+		if err != nil {
+			c.Status, c.Detail, c.Fix = CheckFail, err.Error(), "Fix tls_ca for this host in the config."
+		} else if certs := parseCertificates(data); len(certs) == 0 {
+			c.Status, c.Detail, c.Fix = CheckFail, e.TLSCA+" contains no PEM certificate", "Point tls_ca at the CA's PEM certificate (ca.pem)."
+		} else {
+			c = certificateCheck(c, certs[0])
+		}
+		checks = append(checks, c)
+	}
+
+	if e.TLSCert != "" {
+		c := Check{Name: "TLS client cert", Detail: e.TLSCert}
+		pair, err := tls.LoadX509KeyPair(expandHome(e.TLSCert), expandHome(e.TLSKey))
+		if err != nil {
+			c.Status, c.Detail, c.Fix = CheckFail, err.Error(), "Check that tls_cert and tls_key are a matching PEM certificate and key."
+		} else if leaf, err := x509.ParseCertificate(pair.Certificate[0]); err == nil {
+			c = certificateCheck(c, leaf)
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// parseCertificates returns every certificate in a PEM bundle
+func parseCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+}
+
+// certificateCheck fills in a check from a certificate's subject and validity period
+func certificateCheck(c Check, cert *x509.Certificate) Check {
+	c.Status = CheckOK
+	c.Detail = fmt.Sprintf("%s, valid until %s", cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02"))
+	switch {
+	case time.Now().After(cert.NotAfter), time.Now().Before(cert.NotBefore):
+		c.Status = CheckFail
+		c.Detail = fmt.Sprintf("%s is not valid now (%s to %s)", cert.Subject.CommonName,
+			cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"))
+		c.Fix = "Renew the certificate."
+	case time.Until(cert.NotAfter) < certExpiryWarning:
+		c.Status = CheckWarn
+		c.Fix = "The certificate expires soon; renew it."
+	}
+	return c
+}
+
+// checkSocket verifies the daemon socket exists and that we may connect to it
+func checkSocket(path string) Check {
+	c := Check{Name: "Socket", Detail: path}
+	if _, err := os.Stat(path); err != nil {
+		c.Status = CheckFail
+		c.Detail += " does not exist"
+		c.Fix = "Start Docker (e.g. sudo systemctl start docker), or set DOCKER_HOST if it listens elsewhere."
+		return c
+	}
+
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	switch {
+	case err == nil:
+		conn.Close()
+		c.Status = CheckOK
+		c.Detail += " is accessible"
+	case errors.Is(err, os.ErrPermission):
+		c.Status = CheckFail
+		c.Detail += ": permission denied"
+		c.Fix = "sudo usermod -aG docker $USER, then log out and back in (or run newgrp docker)."
+	case errors.Is(err, syscall.ECONNREFUSED):
+		c.Status = CheckFail
+		c.Detail += ": nothing is listening"
+		c.Fix = "The daemon is not running or crashed; check systemctl status docker."
+	default:
+		c.Status = CheckFail
+		c.Detail += ": " + err.Error()
+	}
+	return c
+}
+
+// checkAPIVersion compares the negotiated API version with what DockPulse was built for
+func checkAPIVersion(server, negotiated string) Check {
+	c := Check{Name: "API version", Status: CheckOK,
+		Detail: fmt.Sprintf("daemon %s, using %s (built for %s)", server, negotiated, api.DefaultVersion)}
+	if versions.LessThan(server, minAPIVersion) {
+		c.Status = CheckWarn
+		c.Detail += fmt.Sprintf("; older than %s, some actions may fail", minAPIVersion)
+		c.Fix = "Upgrade Docker to 20.10 or newer."
+	}
+	return c
+}
+
+// checkDisk reports free space on the filesystem holding the Docker root dir
+func checkDisk(rootDir string) Check {
+	c := Check{Name: "Disk space", Status: CheckOK}
+	free, total, err := diskSpace(rootDir)
+	if err != nil || total == 0 {
+		c.Status = CheckWarn
+		c.Detail = fmt.Sprintf("could not check %s", rootDir)
+		if err != nil {
+			c.Detail += ": " + err.Error()
+		}
+		return c
+	}
+
+	percent := free * 100 / total
+	c.Detail = fmt.Sprintf("%s free of %s (%d%%) on %s", FormatBytes(free), FormatBytes(total), percent, rootDir)
+	switch {
+	case percent < diskFailPercent:
+		c.Status = CheckFail
+	case percent < diskWarnPercent:
+		c.Status = CheckWarn
+	}
+	if c.Status != CheckOK {
+		c.Fix = "Free space with docker system prune (add --volumes to include unused volumes)."
+	}
+	return c
+}
+
+// DiskPressure checks free space on the filesystem holding the Docker root dir, as the
+// diagnostics do; ok is false for remote daemons, whose disks can't be seen from here
+func DiskPressure() (c Check, ok bool) {
+	if !strings.HasPrefix(CurrentEndpoint().HostURL(), "unix://") {
+		return Check{}, false
+	}
+	cli, err := getClient()
+	if err != nil {
+		return failed("Disk space", err), true
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return failed("Disk space", err), true
+	}
+	return checkDisk(info.DockerRootDir), true
+}
+
+// checkRootless flags the limitations of a rootless daemon that affect the dashboard
+func checkRootless(securityOptions []string, cgroupVersion, cgroupDriver string, isLocal bool) []Check {
+	if !isRootless(securityOptions) {
+		return nil
+	}
+
+	checks := []Check{{Name: "Rootless", Status: CheckOK, Detail: "daemon runs in rootless mode"}}
+	switch {
+	case cgroupVersion != "2":
+		checks = append(checks, Check{Name: "Rootless cgroups", Status: CheckWarn,
+			Detail: "cgroup v1: live stats and resource limits are unavailable",
+			Fix:    "Boot with systemd.unified_cgroup_hierarchy=1 to use cgroup v2."})
+	case cgroupDriver != "systemd":
+		checks = append(checks, Check{Name: "Rootless cgroups", Status: CheckWarn,
+			Detail: fmt.Sprintf("cgroup driver %q: resource limits may be ignored", cgroupDriver),
+			Fix:    "Enable cgroup delegation: Delegate=cpu cpuset io memory pids in user@.service."})
+	}
+
+	if isLocal {
+		if data, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start"); err == nil {
+			if start, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && start > 0 {
+				checks = append(checks, Check{Name: "Rootless ports", Status: CheckWarn,
+					Detail: fmt.Sprintf("host ports below %d can't be published", start),
+					Fix:    "sudo sysctl net.ipv4.ip_unprivileged_port_start=0, or publish ports above it."})
+			}
+		}
+	}
+	return checks
+}
@@ -0,0 +1,164 @@
+package docker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/docker/client"
+)
+
+// Endpoint is the daemon the package talks to. The zero value uses DOCKER_HOST and the
+// other standard Docker environment variables.
+type Endpoint struct {
+	Name string
+	Host string // unix://, tcp:// or ssh://[user@]host[:port][/socket]
+
+	// ssh:// only. SSHKey is a private key file; SSHAgent is the agent socket to use instead
+	// of SSH_AUTH_SOCK, or "none" to use the key alone. Both default to ~/.ssh/config.
+	SSHKey   string
+	SSHAgent string
+
+	// tcp:// only. PEM files for verifying the daemon (TLSCA) and for client certificate
+	// authentication (TLSCert and TLSKey); any of them switches the connection to TLS.
+	TLSCA   string
+	TLSCert string
+	TLSKey  string
+}
+
+var (
+	endpointMu sync.RWMutex
+	endpoint   Endpoint
+	routes     map[string]Endpoint // container ID -> daemon, filled by ListContainersOnHosts
+)
+
+// SetEndpoint points all further calls at another daemon
+func SetEndpoint(e Endpoint) {
+	endpointMu.Lock()
+	defer endpointMu.Unlock()
+	endpoint = e
+	routes = nil
+}
+
+// CurrentEndpoint returns the daemon calls currently go to
+func CurrentEndpoint() Endpoint {
+	endpointMu.RLock()
+	defer endpointMu.RUnlock()
+	return endpoint
+}
+
+// clientFor creates a client for the daemon a container was listed on, so container
+// actions from the all hosts view reach the right host
+func clientFor(containerID string) (*client.Client, error) {
+	return newClient(endpointFor(containerID))
+}
+
+// endpointFor returns the daemon a container was listed on
+func endpointFor(containerID string) Endpoint {
+	endpointMu.RLock()
+	defer endpointMu.RUnlock()
+	if e, ok := routes[containerID]; ok {
+		return e
+	}
+	return endpoint
+}
+
+// HostURLFor returns the address of the daemon a container was listed on, for programs
+// that should reach the same daemon through DOCKER_HOST
+func HostURLFor(containerID string) string {
+	return endpointFor(containerID).HostURL()
+}
+
+// HostURL returns the daemon address, falling back to DOCKER_HOST and the platform default
+func (e Endpoint) HostURL() string {
+	if e.Host != "" {
+		return e.Host
+	}
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host
+	}
+	return client.DefaultDockerHost
+}
+
+// IsSSH reports whether the daemon is reached through ssh
+func (e Endpoint) IsSSH() bool {
+	return strings.HasPrefix(e.HostURL(), "ssh://")
+}
+
+// IsLocal reports whether the daemon runs on this machine, so its host paths are ours
+func (e Endpoint) IsLocal() bool {
+	host := e.HostURL()
+	return strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
+// UsesTLS reports whether TLS settings are configured for the endpoint
+func (e Endpoint) UsesTLS() bool {
+	return e.TLSCA != "" || e.TLSCert != "" || e.TLSKey != ""
+}
+
+// newClient creates a client for an endpoint; ssh:// hosts are tunnelled through the ssh
+// binary running docker system dial-stdio on the remote side, like the Docker CLI does
+func newClient(e Endpoint) (*client.Client, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	if e.IsSSH() {
+		helper, err := connhelper.GetConnectionHelperWithSSHOpts(e.HostURL(), sshFlags(e))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			client.WithHost(helper.Host),
+			client.WithDialContext(helper.Dialer))
+	} else if e.Host != "" {
+		opts = append(opts, client.WithHost(e.Host))
+	}
+
+	if e.UsesTLS() {
+		opts = append(opts, client.WithTLSClientConfig(expandHome(e.TLSCA), expandHome(e.TLSCert), expandHome(e.TLSKey)))
+	}
+
+	return client.NewClientWithOpts(opts...)
+}
+
+// sshFlags returns the ssh options for an endpoint. Every API call opens a new ssh
+// connection, so connections are multiplexed over one master to keep calls fast, and
+// prompts are disabled because they would corrupt the terminal UI.
+func sshFlags(e Endpoint) []string {
+	flags := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if e.SSHKey != "" {
+		flags = append(flags, "-i", expandHome(e.SSHKey), "-o", "IdentitiesOnly=yes")
+	}
+	if e.SSHAgent != "" {
+		flags = append(flags, "-o", "IdentityAgent="+expandHome(e.SSHAgent))
+	}
+	// OpenSSH on Windows has no connection multiplexing
+	if runtime.GOOS != "windows" {
+		if dir, err := os.UserCacheDir(); err == nil && os.MkdirAll(dir, 0o700) == nil {
+			flags = append(flags,
+				"-o", "ControlMaster=auto",
+				"-o", "ControlPath="+filepath.Join(dir, "dockpulse-ssh-%C"),
+				"-o", "ControlPersist=60s")
+		}
+	}
+	return flags
+}
+
+// SSHAvailable reports whether the ssh binary needed for ssh:// hosts is installed
+func SSHAvailable() bool {
+	_, err := exec.LookPath("ssh")
+	return err == nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
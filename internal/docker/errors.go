@@ -0,0 +1,228 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// Error kinds; test for them with errors.Is
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrDaemonUnreachable = errors.New("docker daemon unreachable")
+	ErrTLS               = errors.New("tls failure")
+	ErrNoShell           = errors.New("no shell")
+)
+
+// Error is a Docker SDK error decoded into a kind, a readable message and a suggested fix
+type Error struct {
+	Kind    error  // one of the Err* kinds
+	Message string // what went wrong, in plain words
+	Hint    string // what the user can do about it; may be empty
+	Err     error  // the original SDK error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap exposes both the kind and the original error to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+var (
+	portInUsePattern = regexp.MustCompile(`(?:Bind for|listen \w+) \S*:(\d+)(?: failed)?: (?:port is already allocated|bind: address already in use)`)
+	nameInUsePattern = regexp.MustCompile(`container name "/?([^"]+)" is already in use by container "([0-9a-f]+)"`)
+)
+
+// decodeError turns an SDK error into an *Error when its cause is recognised, and
+// returns it unchanged otherwise
+func decodeError(err error) error {
+	var decoded *Error
+	if err == nil || errors.As(err, &decoded) {
+		return err
+	}
+
+	msg := err.Error()
+	lower := strings.ToLower(msg)
+	e := &Error{Err: err}
+
+	switch {
+	case strings.Contains(msg, "command [ssh "):
+		decodeSSHError(e, lower)
+	case strings.Contains(lower, "x509:") || strings.Contains(lower, "tls:") || strings.Contains(lower, "tls config") ||
+		strings.Contains(lower, "https client") || strings.Contains(lower, "https server"):
+		decodeTLSError(e, lower)
+	case strings.Contains(lower, "permission denied while trying to connect"):
+		e.Kind = ErrPermissionDenied
+		e.Message = "You don't have permission to use the Docker socket."
+		e.Hint = "Add your user to the docker group (sudo usermod -aG docker $USER) and log in again, or run DockPulse with sudo."
+	case client.IsErrConnectionFailed(err) || strings.Contains(lower, "cannot connect to the docker daemon") ||
+		strings.Contains(lower, "connection refused") || strings.Contains(lower, "error during connect"):
+		e.Kind = ErrDaemonUnreachable
+		e.Message = "The Docker daemon is not reachable."
+		e.Hint = "Start Docker (e.g. sudo systemctl start docker) or check that DOCKER_HOST points to a running daemon."
+	case strings.Contains(lower, "oci runtime exec failed") &&
+		(strings.Contains(lower, "no such file or directory") || strings.Contains(lower, "executable file not found")):
+		e.Kind = ErrNoShell
+		e.Message = "The shell or command does not exist in the container."
+		e.Hint = noShellHint
+	case portInUsePattern.MatchString(msg):
+		port := portInUsePattern.FindStringSubmatch(msg)[1]
+		e.Kind = ErrConflict
+		if owner := portOwner(port); owner != "" {
+			e.Message = fmt.Sprintf("Port %s is already in use by container '%s'.", port, owner)
+			e.Hint = fmt.Sprintf("Stop '%s' or publish a different host port.", owner)
+		} else {
+			e.Message = fmt.Sprintf("Port %s is already in use on the host.", port)
+			e.Hint = "Stop the process listening on it or publish a different host port."
+		}
+	case nameInUsePattern.MatchString(msg):
+		m := nameInUsePattern.FindStringSubmatch(msg)
+		e.Kind = ErrConflict
+		e.Message = fmt.Sprintf("The name '%s' is already used by container %s.", m[1], shortID(m[2]))
+		e.Hint = "Remove or rename that container, or choose another name."
+	case errdefs.IsNotFound(err):
+		e.Kind = ErrNotFound
+		switch {
+		case strings.Contains(lower, "no such container"):
+			e.Message = "The container no longer exists."
+			e.Hint = "It was probably removed outside DockPulse; the list refreshes automatically."
+		case strings.Contains(lower, "no such image"), strings.Contains(lower, "manifest unknown"),
+			strings.Contains(lower, "repository does not exist"):
+			e.Message = "The image could not be found."
+			e.Hint = "Check the image name and tag, and run docker login if the registry is private."
+		default:
+			e.Message = "The requested object could not be found."
+		}
+	case errdefs.IsConflict(err):
+		e.Kind = ErrConflict
+		switch {
+		case strings.Contains(lower, "is not running"):
+			e.Message = "The container is not running."
+			e.Hint = "Start it first."
+		case strings.Contains(lower, "is paused"):
+			e.Message = "The container is paused."
+			e.Hint = "Unpause it first."
+		case strings.Contains(lower, "being used by") || strings.Contains(lower, "in use"):
+			e.Message = "The object is still in use."
+			e.Hint = "Stop or remove whatever is using it first."
+		default:
+			e.Message = "The request conflicts with the current state of the container."
+		}
+	case errdefs.IsForbidden(err), errdefs.IsUnauthorized(err):
+		e.Kind = ErrPermissionDenied
+		e.Message = "The Docker daemon refused the request."
+		e.Hint = "Check registry credentials (docker login) or the daemon's authorization plugins."
+	default:
+		return err
+	}
+
+	return e
+}
+
+// decodeSSHError explains why the ssh tunnel to a remote daemon failed, based on ssh's stderr
+func decodeSSHError(e *Error, lower string) {
+	switch {
+	case strings.Contains(lower, "permission denied (publickey"), strings.Contains(lower, "too many authentication failures"):
+		e.Kind = ErrPermissionDenied
+		e.Message = "SSH authentication to the Docker host failed."
+		e.Hint = "Load your key with ssh-add, or set ssh_key (and ssh_agent) for this host in the config."
+	case strings.Contains(lower, "host key verification failed"):
+		e.Kind = ErrPermissionDenied
+		e.Message = "The SSH host key of the Docker host is not trusted yet."
+		e.Hint = "Connect once with ssh to check and accept the key, then retry."
+	case strings.Contains(lower, "permission denied while trying to connect"):
+		e.Kind = ErrPermissionDenied
+		e.Message = "The SSH user may not use the Docker socket on the remote host."
+		e.Hint = "Add that user to the docker group on the remote host."
+	case strings.Contains(lower, "not found"):
+		e.Kind = ErrDaemonUnreachable
+		e.Message = "The docker command is not installed on the SSH host."
+		e.Hint = "Install Docker 18.09 or later there; DockPulse runs docker system dial-stdio over ssh."
+	default:
+		e.Kind = ErrDaemonUnreachable
+		e.Message = "Could not reach the Docker host over SSH."
+		e.Hint = "Check the host and port in the ssh:// URL, and that sshd and Docker are running there."
+	}
+}
+
+// decodeTLSError explains certificate problems with tcp:// daemons
+func decodeTLSError(e *Error, lower string) {
+	e.Kind = ErrTLS
+	switch {
+	case strings.Contains(lower, "tls config"):
+		e.Message = "A TLS file for this host could not be loaded."
+		e.Hint = "Check that tls_ca, tls_cert and tls_key point to readable PEM files that belong together."
+	case strings.Contains(lower, "unknown authority"):
+		e.Message = "The daemon's certificate is not signed by a trusted CA."
+		e.Hint = "Set tls_ca to the CA certificate that signed the daemon's certificate."
+	case strings.Contains(lower, "certificate is valid for"), strings.Contains(lower, "doesn't contain any ip sans"):
+		e.Message = "The daemon's certificate does not match the host name in the URL."
+		e.Hint = "Connect with a name listed in the certificate, or reissue it with this host in its SANs."
+	case strings.Contains(lower, "legacy common name"):
+		e.Message = "The daemon's certificate only names the host in its Common Name."
+		e.Hint = "Reissue it with the host name or IP in subjectAltName; Common Names are no longer accepted."
+	case strings.Contains(lower, "expired or is not yet valid"):
+		e.Message = "A certificate has expired or is not valid yet."
+		e.Hint = "Renew the certificate, and check that the clocks on both machines are right."
+	case strings.Contains(lower, "bad certificate"), strings.Contains(lower, "certificate required"),
+		strings.Contains(lower, "unknown certificate"):
+		e.Kind = ErrPermissionDenied
+		e.Message = "The daemon rejected the client certificate."
+		e.Hint = "Set tls_cert and tls_key to a client certificate signed by the CA the daemon trusts (--tlscacert)."
+	case strings.Contains(lower, "http response to https client"):
+		e.Message = "The daemon does not speak TLS on this port."
+		e.Hint = "Remove the TLS settings for this host, or use the daemon's TLS port (usually 2376)."
+	case strings.Contains(lower, "http request to an https server"):
+		e.Message = "The daemon requires TLS."
+		e.Hint = "Set tls_ca, tls_cert and tls_key for this host."
+	default:
+		e.Message = "The TLS handshake with the daemon failed."
+		e.Hint = "Check the tls_ca, tls_cert and tls_key settings for this host."
+	}
+}
+
+// portOwner returns the name of the container publishing a host port, if any
+func portOwner(port string) string {
+	want, err := strconv.Atoi(port)
+	if err != nil {
+		return ""
+	}
+	cli, err := getClient()
+	if err != nil {
+		return ""
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, c := range containers {
+		for _, p := range c.Ports {
+			if int(p.PublicPort) == want && len(c.Names) > 0 {
+				return strings.TrimPrefix(c.Names[0], "/")
+			}
+		}
+	}
+	return ""
+}
+
+// shortID truncates a container ID to the 12 characters docker ps shows
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
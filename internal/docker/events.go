@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// EventInfo is a single event from the Docker daemon
+type EventInfo struct {
+	Time       time.Time
+	Type       string
+	Action     string
+	ActorID    string
+	Name       string
+	Attributes map[string]string
+	Host       string // endpoint name the event came from
+}
+
+// StreamEvents subscribes to daemon events until ctx is cancelled
+func StreamEvents(ctx context.Context) (<-chan EventInfo, <-chan error) {
+	return StreamEventsFrom(ctx, CurrentEndpoint())
+}
+
+// StreamEventsFrom subscribes to the events of a specific daemon until ctx is cancelled
+func StreamEventsFrom(ctx context.Context, e Endpoint) (<-chan EventInfo, <-chan error) {
+	out := make(chan EventInfo)
+	errs := make(chan error, 1)
+
+	cli, err := newClient(e)
+	if err != nil {
+		errs <- decodeError(err)
+		close(out)
+		return out, errs
+	}
+
+	messages, msgErrs := cli.Events(ctx, types.EventsOptions{})
+
+	go func() {
+		defer cli.Close()
+		defer close(out)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-msgErrs:
+				if err != nil && ctx.Err() == nil {
+					errs <- decodeError(err)
+				}
+				return
+			case msg := <-messages:
+				event := EventInfo{
+					Time:       time.Unix(0, msg.TimeNano),
+					Type:       string(msg.Type),
+					Action:     msg.Action,
+					ActorID:    msg.Actor.ID,
+					Name:       msg.Actor.Attributes["name"],
+					Attributes: msg.Actor.Attributes,
+					Host:       e.Name,
+				}
+				if msg.TimeNano == 0 {
+					event.Time = time.Unix(msg.Time, 0)
+				}
+
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, errs
+}
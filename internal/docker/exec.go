@@ -9,17 +9,42 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
+// ExecOptions controls how ExecCommandWith runs a command
+type ExecOptions struct {
+	User       string   // user or uid[:gid]; the container's user when empty
+	WorkingDir string   // the container's working directory when empty
+	Env        []string // KEY=value pairs added to the container's environment
+	Privileged bool
+	TTY        bool  // allocate a pseudo-terminal; stdout and stderr arrive merged
+	Shell      Shell // runs the command; /bin/sh when zero
+}
+
+// ExitError reports a command that ran but exited with a non-zero code
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
 // ExecCommand executes a single command in a container and returns the output
 func ExecCommand(containerID, command string) (string, error) {
+	return ExecCommandWith(containerID, command, ExecOptions{})
+}
+
+// ExecCommandWith executes a single command in a container with opts and returns the
+// output, stdout and stderr merged
+func ExecCommandWith(containerID, command string, opts ExecOptions) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Docker client: %w", err)
+		return "", fmt.Errorf("failed to create Docker client: %w", decodeError(err))
 	}
 	defer cli.Close()
 
@@ -31,42 +56,55 @@ func ExecCommand(containerID, command string) (string, error) {
 
 	// Create exec configuration
 	execConfig := types.ExecConfig{
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		Env:          opts.Env,
+		Privileged:   opts.Privileged,
+		Tty:          opts.TTY,
 		AttachStdout: true,
 		AttachStderr: true,
-		Cmd:          []string{"/bin/sh", "-c", command},
+		Cmd:          shellFor(containerID, opts.Shell, command),
 	}
 
 	// Create exec instance
 	execIDResp, err := cli.ContainerExecCreate(ctx, containerID, execConfig)
 	if err != nil {
-		return "", fmt.Errorf("failed to create exec: %w", err)
+		return "", fmt.Errorf("failed to create exec: %w", decodeError(err))
 	}
 
 	// Attach to exec instance
-	resp, err := cli.ContainerExecAttach(ctx, execIDResp.ID, types.ExecStartCheck{})
+	resp, err := cli.ContainerExecAttach(ctx, execIDResp.ID, types.ExecStartCheck{Tty: opts.TTY})
 	if err != nil {
-		return "", fmt.Errorf("failed to attach to exec: %w", err)
+		return "", fmt.Errorf("failed to attach to exec: %w", decodeError(err))
 	}
 	defer resp.Close()
 
-	// Read output
+	// Read output; without a TTY stdout and stderr are multiplexed with a header per frame
 	var buf bytes.Buffer
-	_, err = io.Copy(&buf, resp.Reader)
+	if opts.TTY {
+		_, err = io.Copy(&buf, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(&buf, &buf, resp.Reader)
+	}
 	if err != nil && err != io.EOF {
 		return "", fmt.Errorf("failed to read output: %w", err)
 	}
+	output := buf.String()
+	if opts.TTY {
+		output = strings.ReplaceAll(output, "\r\n", "\n")
+	}
 
 	// Check exit code
 	inspectResp, err := cli.ContainerExecInspect(ctx, execIDResp.ID)
 	if err != nil {
-		return buf.String(), fmt.Errorf("command executed but failed to inspect: %w", err)
+		return output, fmt.Errorf("command executed but failed to inspect: %w", decodeError(err))
 	}
 
 	if inspectResp.ExitCode != 0 {
-		return buf.String(), fmt.Errorf("command exited with code %d", inspectResp.ExitCode)
+		return output, &ExitError{Code: inspectResp.ExitCode}
 	}
 
-	return buf.String(), nil
+	return output, nil
 }
 
 // ExecCommandWithTimeout executes a command with a custom timeout
@@ -74,26 +112,26 @@ func ExecCommandWithTimeout(containerID, command string, timeout time.Duration)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return "", fmt.Errorf("failed to create Docker client: %w", err)
+		return "", fmt.Errorf("failed to create Docker client: %w", decodeError(err))
 	}
 	defer cli.Close()
 
 	execConfig := types.ExecConfig{
 		AttachStdout: true,
 		AttachStderr: true,
-		Cmd:          []string{"/bin/sh", "-c", command},
+		Cmd:          shellFor(containerID, Shell{}, command),
 	}
 
 	execIDResp, err := cli.ContainerExecCreate(ctx, containerID, execConfig)
 	if err != nil {
-		return "", fmt.Errorf("failed to create exec: %w", err)
+		return "", fmt.Errorf("failed to create exec: %w", decodeError(err))
 	}
 
 	resp, err := cli.ContainerExecAttach(ctx, execIDResp.ID, types.ExecStartCheck{})
 	if err != nil {
-		return "", fmt.Errorf("failed to attach to exec: %w", err)
+		return "", fmt.Errorf("failed to attach to exec: %w", decodeError(err))
 	}
 	defer resp.Close()
 
@@ -111,9 +149,9 @@ func ExecCommandWithTimeout(containerID, command string, timeout time.Duration)
 func ExecInteractive(containerID string, command string) (io.Reader, io.Writer, io.Closer, error) {
 	ctx := context.Background()
 
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to create Docker client: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create Docker client: %w", decodeError(err))
 	}
 
 	execConfig := types.ExecConfig{
@@ -121,13 +159,13 @@ func ExecInteractive(containerID string, command string) (io.Reader, io.Writer,
 		AttachStdout: true,
 		AttachStderr: true,
 		Tty:          true,
-		Cmd:          []string{"/bin/sh", "-c", command},
+		Cmd:          shellFor(containerID, Shell{}, command),
 	}
 
 	execIDResp, err := cli.ContainerExecCreate(ctx, containerID, execConfig)
 	if err != nil {
 		cli.Close()
-		return nil, nil, nil, fmt.Errorf("failed to create exec: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create exec: %w", decodeError(err))
 	}
 
 	resp, err := cli.ContainerExecAttach(ctx, execIDResp.ID, types.ExecStartCheck{
@@ -135,7 +173,7 @@ func ExecInteractive(containerID string, command string) (io.Reader, io.Writer,
 	})
 	if err != nil {
 		cli.Close()
-		return nil, nil, nil, fmt.Errorf("failed to attach to exec: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to attach to exec: %w", decodeError(err))
 	}
 
 	// Return reader, writer, and the connection as closer
@@ -144,30 +182,39 @@ func ExecInteractive(containerID string, command string) (io.Reader, io.Writer,
 
 // ListProcesses lists running processes in a container
 func ListProcesses(containerID string) (string, error) {
-	return ExecCommand(containerID, "ps aux")
+	return ExecCommand(containerID, commandFor(containerID, "ps aux", "tasklist"))
 }
 
 // GetEnvironmentVariables gets all environment variables from a container
 func GetEnvironmentVariables(containerID string) (string, error) {
-	return ExecCommand(containerID, "env | sort")
+	return ExecCommand(containerID, commandFor(containerID, "env | sort", "set"))
 }
 
 // GetFileSystem gets filesystem information
 func GetFileSystem(containerID string) (string, error) {
-	return ExecCommand(containerID, "df -h")
+	return ExecCommand(containerID, commandFor(containerID, "df -h", `dir C:\`))
+}
+
+// commandFor picks the command for the OS of a container: unix runs with /bin/sh,
+// windows with cmd
+func commandFor(containerID, unix, windows string) string {
+	if onWindows(containerID) {
+		return windows
+	}
+	return unix
 }
 
 func GetNetworkInfo(containerID string) (*NetworkInfo, error) {
-	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	cli, err := clientFor(containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 	defer cli.Close()
 
 	ctx := context.Background()
 	inspect, err := cli.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return nil, err
+		return nil, decodeError(err)
 	}
 
 	networkInfo := &NetworkInfo{
@@ -0,0 +1,43 @@
+package docker
+
+import "sync"
+
+// HostResult is the outcome of listing containers on one host
+type HostResult struct {
+	Endpoint   Endpoint
+	Containers []ContainerInfo
+	Err        error
+}
+
+// ListContainersOnHosts lists the containers of every endpoint concurrently. Results keep
+// the order of endpoints, each container is tagged with its host name, and container
+// actions are routed to the host a container was listed on until the next call or
+// SetEndpoint.
+func ListContainersOnHosts(endpoints []Endpoint) []HostResult {
+	results := make([]HostResult, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func(i int, e Endpoint) {
+			defer wg.Done()
+			containers, err := ListContainersOn(e)
+			for j := range containers {
+				containers[j].Host = e.Name
+			}
+			results[i] = HostResult{Endpoint: e, Containers: containers, Err: err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	next := make(map[string]Endpoint)
+	for _, r := range results {
+		for _, c := range r.Containers {
+			next[c.ID] = r.Endpoint
+		}
+	}
+	endpointMu.Lock()
+	routes = next
+	endpointMu.Unlock()
+
+	return results
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// SaveImage writes images with their tags to a tar file, like docker save -o, gzipped
+// when path ends in .gz or .tgz. read is called with the bytes read from the daemon so
+// far, which come to about the images' size. Nothing is left at path when it fails.
+func SaveImage(ctx context.Context, refs []string, path string, read func(int64)) error {
+	cli, err := getClient()
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	body, err := cli.ImageSave(ctx, refs)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer body.Close()
+
+	// Written next to the destination first, so a cancelled save leaves no broken tar
+	part := path + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return err
+	}
+	var w io.WriteCloser = f
+	if strings.HasSuffix(path, ".gz") || strings.HasSuffix(path, ".tgz") {
+		w = gzip.NewWriter(f)
+	}
+	_, err = io.Copy(w, &countingReader{r: body, read: read})
+	if w != f {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(part, path)
+	}
+	if err != nil {
+		os.Remove(part)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("saving to %s: %w", path, decodeError(err))
+	}
+	return nil
+}
+
+// LoadImage imports the images of a tar written by docker save, plain or compressed,
+// like docker load -i, and returns the references it loaded. read is called with the
+// bytes sent to the daemon so far, out of the file's size.
+func LoadImage(ctx context.Context, path string, read func(int64)) ([]string, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	resp, err := cli.ImageLoad(ctx, &countingReader{r: f, read: read}, true)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer resp.Body.Close()
+
+	// The daemon answers with JSON messages such as {"stream":"Loaded image: app:1\n"}
+	var loaded []string
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return loaded, fmt.Errorf("reading the daemon's answer: %w", decodeError(err))
+		}
+		if msg.Error != nil {
+			return loaded, errors.New(msg.Error.Message)
+		}
+		for _, prefix := range []string{"Loaded image: ", "Loaded image ID: "} {
+			if ref, ok := strings.CutPrefix(strings.TrimSpace(msg.Stream), prefix); ok {
+				loaded = append(loaded, ref)
+			}
+		}
+	}
+	if len(loaded) == 0 {
+		return nil, fmt.Errorf("%s holds no images", path)
+	}
+	return loaded, nil
+}
@@ -0,0 +1,338 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Kinds of layer changes
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// maxJSONBlob is the largest non-layer file of a saved image kept in memory, enough for
+// manifests and image configs
+const maxJSONBlob = 4 << 20
+
+// LayerChange is a file a layer adds, modifies or removes
+type LayerChange struct {
+	Path string
+	Kind string
+	Size int64 // bytes the layer stores, or for removals the bytes removed
+}
+
+// ImageLayer is one layer of an image and the files it changes
+type ImageLayer struct {
+	Digest  string
+	Command string // the build step that created it
+	Size    int64  // bytes of the files it stores
+	Changes []LayerChange
+}
+
+// WastedFile is a path whose bytes are stored in more than one layer, or stored and
+// then removed again, so the image carries them without using them
+type WastedFile struct {
+	Path   string
+	Layers int // how many layers store or remove it
+	Wasted int64
+}
+
+// ImageAnalysis is what the layers of an image contain and how much of it is wasted
+type ImageAnalysis struct {
+	Layers      []ImageLayer
+	TotalSize   int64 // bytes of the files of all layers
+	Wasted      int64
+	WastedFiles []WastedFile // most wasted first
+}
+
+// Efficiency is the share of the stored bytes the final filesystem uses, from 0 to 1
+func (a *ImageAnalysis) Efficiency() float64 {
+	if a.TotalSize == 0 {
+		return 1
+	}
+	return 1 - float64(a.Wasted)/float64(a.TotalSize)
+}
+
+// layerEntry is a file, or a whiteout removing one, in a layer tar
+type layerEntry struct {
+	path     string
+	size     int64
+	whiteout bool // path was removed
+	opaque   bool // everything below path in lower layers was removed
+}
+
+// AnalyzeImage reads an image with docker save and works out what every layer adds,
+// modifies and removes. read is called with the number of bytes read so far, as the
+// whole image is streamed.
+func AnalyzeImage(ctx context.Context, imageID string, read func(int64)) (*ImageAnalysis, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	body, err := cli.ImageSave(ctx, []string{imageID})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer body.Close()
+	return analyzeSaved(&countingReader{r: body, read: read})
+}
+
+// analyzeSaved analyzes an image in the docker save format, legacy or OCI
+func analyzeSaved(r io.Reader) (*ImageAnalysis, error) {
+	// Layer tars and manifests come in any order, so both are collected first
+	layers := make(map[string][]layerEntry)
+	blobs := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading the saved image: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		br := bufio.NewReaderSize(tr, 512)
+		head, _ := br.Peek(512)
+		if isArchive(head) {
+			entries, err := readLayer(br, head)
+			if err != nil {
+				return nil, fmt.Errorf("reading layer %s: %w", hdr.Name, err)
+			}
+			layers[hdr.Name] = entries
+		} else if hdr.Size <= maxJSONBlob {
+			data, err := io.ReadAll(br)
+			if err != nil {
+				return nil, fmt.Errorf("reading the saved image: %w", err)
+			}
+			blobs[hdr.Name] = data
+		}
+	}
+
+	var manifest []struct {
+		Config string
+		Layers []string
+	}
+	if err := json.Unmarshal(blobs["manifest.json"], &manifest); err != nil || len(manifest) == 0 {
+		return nil, fmt.Errorf("the saved image has no usable manifest.json")
+	}
+	var config struct {
+		History []struct {
+			CreatedBy  string `json:"created_by"`
+			EmptyLayer bool   `json:"empty_layer"`
+		} `json:"history"`
+	}
+	json.Unmarshal(blobs[manifest[0].Config], &config)
+
+	// Build steps that didn't create a layer, such as ENV, have no tar
+	var commands []string
+	for _, h := range config.History {
+		if !h.EmptyLayer {
+			commands = append(commands, cleanCommand(h.CreatedBy))
+		}
+	}
+
+	a := &ImageAnalysis{}
+	ordered := make([][]layerEntry, len(manifest[0].Layers))
+	for i, name := range manifest[0].Layers {
+		ordered[i] = layers[name] // empty layers are not archives and stay nil
+		layer := ImageLayer{Digest: layerDigest(name)}
+		if i < len(commands) {
+			layer.Command = commands[i]
+		}
+		a.Layers = append(a.Layers, layer)
+	}
+	a.compare(ordered)
+	return a, nil
+}
+
+// compare replays the layers in order to find what each changes and what is wasted
+func (a *ImageAnalysis) compare(layers [][]layerEntry) {
+	type stored struct {
+		size  int64
+		layer int
+	}
+	current := make(map[string]stored)
+	touched := make(map[string]*WastedFile)
+	waste := func(p string, size int64) {
+		w := touched[p]
+		if w == nil {
+			w = &WastedFile{Path: p, Layers: 1}
+			touched[p] = w
+		}
+		w.Layers++
+		w.Wasted += size
+		a.Wasted += size
+	}
+
+	// remove takes paths out of the filesystem, the wasted bytes of lower layers with them
+	remove := func(i int, match func(string) bool) int64 {
+		var removed int64
+		for p, s := range current {
+			if s.layer < i && match(p) {
+				waste(p, s.size)
+				removed += s.size
+				delete(current, p)
+			}
+		}
+		return removed
+	}
+
+	for i, entries := range layers {
+		layer := &a.Layers[i]
+
+		// Removals apply to the layers below, so they go first
+		for _, e := range entries {
+			switch {
+			case e.opaque:
+				prefix := e.path + "/"
+				if size := remove(i, func(p string) bool { return strings.HasPrefix(p, prefix) }); size > 0 {
+					layer.Changes = append(layer.Changes, LayerChange{Path: prefix + "*", Kind: ChangeRemoved, Size: size})
+				}
+			case e.whiteout:
+				prefix := e.path + "/"
+				size := remove(i, func(p string) bool { return p == e.path || strings.HasPrefix(p, prefix) })
+				layer.Changes = append(layer.Changes, LayerChange{Path: e.path, Kind: ChangeRemoved, Size: size})
+			}
+		}
+
+		for _, e := range entries {
+			if e.whiteout || e.opaque {
+				continue
+			}
+			kind := ChangeAdded
+			if old, ok := current[e.path]; ok {
+				kind = ChangeModified
+				waste(e.path, old.size)
+			}
+			current[e.path] = stored{size: e.size, layer: i}
+			layer.Size += e.size
+			layer.Changes = append(layer.Changes, LayerChange{Path: e.path, Kind: kind, Size: e.size})
+		}
+
+		sort.Slice(layer.Changes, func(x, y int) bool {
+			if layer.Changes[x].Size != layer.Changes[y].Size {
+				return layer.Changes[x].Size > layer.Changes[y].Size
+			}
+			return layer.Changes[x].Path < layer.Changes[y].Path
+		})
+		a.TotalSize += layer.Size
+	}
+
+	for _, w := range touched {
+		if w.Wasted > 0 {
+			a.WastedFiles = append(a.WastedFiles, *w)
+		}
+	}
+	sort.Slice(a.WastedFiles, func(i, j int) bool {
+		if a.WastedFiles[i].Wasted != a.WastedFiles[j].Wasted {
+			return a.WastedFiles[i].Wasted > a.WastedFiles[j].Wasted
+		}
+		return a.WastedFiles[i].Path < a.WastedFiles[j].Path
+	})
+}
+
+// readLayer lists the files and whiteouts of a layer tar, gzipped or not
+func readLayer(r io.Reader, head []byte) ([]layerEntry, error) {
+	if bytes.HasPrefix(head, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var entries []layerEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p := strings.TrimSuffix(strings.TrimPrefix(path.Clean("/"+hdr.Name), "/"), "/")
+		dir, base := path.Split(p)
+		dir = strings.TrimSuffix(dir, "/")
+		switch {
+		case base == ".wh..wh..opq":
+			entries = append(entries, layerEntry{path: dir, opaque: true})
+		case strings.HasPrefix(base, ".wh."):
+			entries = append(entries, layerEntry{path: path.Join(dir, strings.TrimPrefix(base, ".wh.")), whiteout: true})
+		case hdr.Typeflag == tar.TypeDir:
+			// Directories take no space of their own
+		default:
+			entries = append(entries, layerEntry{path: p, size: hdr.Size})
+		}
+	}
+}
+
+// isArchive reports whether a file of a saved image is a layer: a tar or gzipped tar
+func isArchive(head []byte) bool {
+	if bytes.HasPrefix(head, []byte{0x1f, 0x8b}) {
+		return true
+	}
+	return len(head) >= 262 && string(head[257:262]) == "ustar"
+}
+
+// layerDigest shortens the name of a layer in a saved image to a 12 character ID, from
+// "<id>/layer.tar" in the legacy layout or "blobs/sha256/<digest>" in the OCI one
+func layerDigest(name string) string {
+	id := path.Base(name)
+	if id == "layer.tar" {
+		id = path.Base(path.Dir(name))
+	}
+	return id[:min(12, len(id))]
+}
+
+// cleanCommand makes a build step readable, e.g. "/bin/sh -c #(nop) COPY file:… in /"
+// becomes "COPY file:… in /" and BuildKit's "RUN /bin/sh -c make # buildkit" "RUN make"
+func cleanCommand(createdBy string) string {
+	cmd := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(createdBy), "# buildkit"))
+	if rest, ok := strings.CutPrefix(cmd, "RUN /bin/sh -c "); ok {
+		return "RUN " + rest
+	}
+	cmd, shell := strings.CutPrefix(cmd, "/bin/sh -c ")
+	if rest, ok := strings.CutPrefix(cmd, "#(nop)"); ok {
+		return strings.TrimSpace(rest)
+	}
+	if shell {
+		return "RUN " + cmd
+	}
+	return cmd
+}
+
+// countingReader reports how many bytes were read through it
+type countingReader struct {
+	r    io.Reader
+	n    int64
+	read func(int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.read != nil && n > 0 {
+		c.read(c.n)
+	}
+	return n, err
+}
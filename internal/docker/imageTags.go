@@ -0,0 +1,79 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// UntaggedImage is how ListImages names images without a tag
+const UntaggedImage = "<none>:<none>"
+
+// SplitImageRef splits "registry:5000/app:1.2" into "registry:5000/app" and "1.2"; the
+// tag is "" when the reference has none
+func SplitImageRef(ref string) (repository, tag string) {
+	ref, _, _ = strings.Cut(ref, "@")
+	i := strings.LastIndex(ref, ":")
+	if i < 0 || strings.Contains(ref[i+1:], "/") {
+		return ref, ""
+	}
+	return ref[:i], ref[i+1:]
+}
+
+// ImageUsers maps image IDs to the names of the containers, running or not, created
+// from them, which keep the images from being deleted
+func ImageUsers() (map[string][]string, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	users := make(map[string][]string)
+	for _, c := range containers {
+		id := strings.TrimPrefix(c.ImageID, "sha256:")
+		name := c.ID[:12]
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		users[id] = append(users[id], name)
+	}
+	return users, nil
+}
+
+// TagImage gives the image source refers to another reference, like docker tag
+func TagImage(source, target string) error {
+	cli, err := getClient()
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	return decodeError(cli.ImageTag(context.Background(), source, target))
+}
+
+// RemoveImageRef removes one reference of an image, like docker rmi without --force:
+// the image itself is deleted with its last reference, which reports deleted
+func RemoveImageRef(ref string) (deleted bool, err error) {
+	cli, err := getClient()
+	if err != nil {
+		return false, decodeError(err)
+	}
+	defer cli.Close()
+
+	items, err := cli.ImageRemove(context.Background(), ref, types.ImageRemoveOptions{PruneChildren: true})
+	if err != nil {
+		return false, decodeError(err)
+	}
+	for _, item := range items {
+		if item.Deleted != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,282 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContainerDetails is the typed result of a container inspection
+type ContainerDetails struct {
+	ID            string
+	Name          string
+	Image         string
+	ImageID       string
+	Command       string
+	Created       string
+	Hostname      string
+	WorkingDir    string
+	User          string
+	Status        string
+	Running       bool
+	Paused        bool
+	Restarting    bool
+	OOMKilled     bool
+	PID           int
+	ExitCode      int
+	StartedAt     string
+	FinishedAt    string
+	RestartPolicy string
+	RestartCount  int
+	MemoryLimit   int64
+	CPUShares     int64
+	NanoCPUs      int64
+	NetworkMode   string
+	Env           []EnvVar
+	Mounts        []MountInfo
+	Networks      []NetworkEndpoint
+	Ports         []PortMapping
+	Labels        map[string]string
+}
+
+// EnvVar is a single environment variable
+type EnvVar struct {
+	Key   string
+	Value string
+}
+
+// MountInfo describes a volume or bind mount of a container
+type MountInfo struct {
+	Type        string
+	Name        string
+	Source      string
+	Destination string
+	Mode        string
+	RW          bool
+}
+
+// NetworkEndpoint describes the container's attachment to one network
+type NetworkEndpoint struct {
+	Network    string
+	IPAddress  string
+	Gateway    string
+	MacAddress string
+	Aliases    []string
+}
+
+// InspectJSON returns the inspection data of a container as the daemon reports it,
+// indented
+func InspectJSON(containerID string) ([]byte, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	_, raw, err := cli.ContainerInspectWithRaw(context.Background(), containerID, true)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return raw, nil
+	}
+	return out.Bytes(), nil
+}
+
+// InspectStructured returns the inspection data of a container as typed fields
+func InspectStructured(containerID string) (*ContainerDetails, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	details := &ContainerDetails{
+		ID:            inspect.ID,
+		Name:          strings.TrimPrefix(inspect.Name, "/"),
+		ImageID:       strings.TrimPrefix(inspect.Image, "sha256:"),
+		Created:       inspect.Created,
+		RestartCount:  inspect.RestartCount,
+		Env:           []EnvVar{},
+		Labels:        map[string]string{},
+		RestartPolicy: "no",
+	}
+
+	if inspect.Path != "" {
+		details.Command = strings.TrimSpace(inspect.Path + " " + strings.Join(inspect.Args, " "))
+	}
+
+	if inspect.Config != nil {
+		details.Image = inspect.Config.Image
+		details.Hostname = inspect.Config.Hostname
+		details.WorkingDir = inspect.Config.WorkingDir
+		details.User = inspect.Config.User
+		details.Env = parseEnv(inspect.Config.Env)
+		if inspect.Config.Labels != nil {
+			details.Labels = inspect.Config.Labels
+		}
+	}
+
+	if inspect.State != nil {
+		details.Status = inspect.State.Status
+		details.Running = inspect.State.Running
+		details.Paused = inspect.State.Paused
+		details.Restarting = inspect.State.Restarting
+		details.OOMKilled = inspect.State.OOMKilled
+		details.PID = inspect.State.Pid
+		details.ExitCode = inspect.State.ExitCode
+		details.StartedAt = inspect.State.StartedAt
+		details.FinishedAt = inspect.State.FinishedAt
+	}
+
+	if inspect.HostConfig != nil {
+		details.MemoryLimit = inspect.HostConfig.Memory
+		details.CPUShares = inspect.HostConfig.CPUShares
+		details.NanoCPUs = inspect.HostConfig.NanoCPUs
+		details.NetworkMode = string(inspect.HostConfig.NetworkMode)
+		if name := string(inspect.HostConfig.RestartPolicy.Name); name != "" {
+			details.RestartPolicy = name
+		}
+	}
+
+	for _, m := range inspect.Mounts {
+		details.Mounts = append(details.Mounts, MountInfo{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Mode:        m.Mode,
+			RW:          m.RW,
+		})
+	}
+
+	if inspect.NetworkSettings != nil {
+		for name, ep := range inspect.NetworkSettings.Networks {
+			if ep == nil {
+				continue
+			}
+			details.Networks = append(details.Networks, NetworkEndpoint{
+				Network:    name,
+				IPAddress:  ep.IPAddress,
+				Gateway:    ep.Gateway,
+				MacAddress: ep.MacAddress,
+				Aliases:    ep.Aliases,
+			})
+		}
+		sort.Slice(details.Networks, func(i, j int) bool {
+			return details.Networks[i].Network < details.Networks[j].Network
+		})
+
+		for port, bindings := range inspect.NetworkSettings.Ports {
+			if len(bindings) == 0 {
+				details.Ports = append(details.Ports, PortMapping{
+					ContainerPort: port.Port(),
+					Protocol:      port.Proto(),
+				})
+				continue
+			}
+			for _, b := range bindings {
+				details.Ports = append(details.Ports, PortMapping{
+					HostIP:        b.HostIP,
+					HostPort:      b.HostPort,
+					ContainerPort: port.Port(),
+					Protocol:      port.Proto(),
+				})
+			}
+		}
+		sort.Slice(details.Ports, func(i, j int) bool {
+			return details.Ports[i].ContainerPort < details.Ports[j].ContainerPort
+		})
+	}
+
+	return details, nil
+}
+
+// IPAddress returns the first IP address the container has on any network
+func (c *ContainerDetails) IPAddress() string {
+	for _, n := range c.Networks {
+		if n.IPAddress != "" {
+			return n.IPAddress
+		}
+	}
+	return ""
+}
+
+// parseEnv splits KEY=value pairs, keeping their order
+func parseEnv(env []string) []EnvVar {
+	vars := make([]EnvVar, 0, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		vars = append(vars, EnvVar{Key: key, Value: value})
+	}
+	return vars
+}
+
+// Env var origins reported by CompareEnvWithImage
+const (
+	EnvFromImage  = "image"
+	EnvOverridden = "overridden"
+	EnvAdded      = "added"
+)
+
+// EnvDiff describes a container env var relative to its image's defaults
+type EnvDiff struct {
+	Key        string
+	Value      string
+	ImageValue string
+	Origin     string
+}
+
+// CompareEnvWithImage reports for each env var of a container whether it comes
+// from the image unchanged, overrides an image default or was added at run time
+func CompareEnvWithImage(containerID string) ([]EnvDiff, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.Config == nil {
+		return nil, nil
+	}
+
+	image, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", inspect.Config.Image, decodeError(err))
+	}
+
+	defaults := make(map[string]string)
+	if image.Config != nil {
+		for _, e := range parseEnv(image.Config.Env) {
+			defaults[e.Key] = e.Value
+		}
+	}
+
+	var diff []EnvDiff
+	for _, e := range parseEnv(inspect.Config.Env) {
+		d := EnvDiff{Key: e.Key, Value: e.Value, Origin: EnvAdded}
+		if def, ok := defaults[e.Key]; ok {
+			d.ImageValue = def
+			d.Origin = EnvFromImage
+			if def != e.Value {
+				d.Origin = EnvOverridden
+			}
+		}
+		diff = append(diff, d)
+	}
+	return diff, nil
+}
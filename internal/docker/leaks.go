@@ -0,0 +1,186 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// Kinds of leaked resources
+const (
+	LeakImage     = "image"
+	LeakVolume    = "volume"
+	LeakNetwork   = "network"
+	LeakContainer = "container"
+)
+
+// builtinNetworks are created by Docker itself and can't be removed
+var builtinNetworks = map[string]bool{
+	"bridge": true, "host": true, "none": true, "ingress": true, "docker_gwbridge": true,
+}
+
+// Leak is a resource nothing uses any more
+type Leak struct {
+	Kind   string
+	ID     string // what RemoveLeak removes it by
+	Name   string
+	Detail string
+	Size   int64     // bytes freed by removing it, 0 when unknown
+	Since  time.Time // when it was created or, for containers, exited; zero when unknown
+}
+
+// FindLeaks looks for dangling images, volumes no container mounts, networks no
+// container is connected to, and containers that exited more than staleAfter ago
+func FindLeaks(staleAfter time.Duration) ([]Leak, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	var leaks []Leak
+
+	images, err := cli.ImageList(ctx, types.ImageListOptions{Filters: filters.NewArgs(filters.Arg("dangling", "true"))})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	for _, img := range images {
+		id := strings.TrimPrefix(img.ID, "sha256:")
+		leaks = append(leaks, Leak{
+			Kind:   LeakImage,
+			ID:     id,
+			Name:   id[:12],
+			Detail: "dangling, " + FormatBytes(uint64(img.Size)),
+			Size:   img.Size,
+			Since:  time.Unix(img.Created, 0),
+		})
+	}
+
+	volumes, err := cli.VolumeList(ctx, volume.ListOptions{Filters: filters.NewArgs(filters.Arg("dangling", "true"))})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	for _, v := range volumes.Volumes {
+		created, _ := time.Parse(time.RFC3339, v.CreatedAt)
+		detail := "not mounted by any container"
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			detail += ", " + FormatBytes(uint64(v.UsageData.Size))
+		}
+		leak := Leak{Kind: LeakVolume, ID: v.Name, Name: v.Name, Detail: detail, Since: created}
+		if v.UsageData != nil {
+			leak.Size = max(v.UsageData.Size, 0)
+		}
+		leaks = append(leaks, leak)
+	}
+
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	connected := make(map[string]bool)
+	for _, c := range containers {
+		if c.NetworkSettings == nil {
+			continue
+		}
+		for name, ep := range c.NetworkSettings.Networks {
+			connected[name] = true
+			if ep != nil {
+				connected[ep.NetworkID] = true
+			}
+		}
+	}
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	for _, n := range networks {
+		if builtinNetworks[n.Name] || connected[n.Name] || connected[n.ID] || n.Scope == "swarm" {
+			continue
+		}
+		leaks = append(leaks, Leak{
+			Kind:   LeakNetwork,
+			ID:     n.ID,
+			Name:   n.Name,
+			Detail: n.Driver + " network without containers",
+			Since:  n.Created,
+		})
+	}
+
+	now := time.Now()
+	for _, c := range containers {
+		if c.State != "exited" && c.State != "dead" {
+			continue
+		}
+		inspect, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			continue // removed in the meantime
+		}
+		finished, err := time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+		if err != nil || finished.IsZero() || now.Sub(finished) < staleAfter {
+			continue
+		}
+		name := strings.TrimPrefix(inspect.Name, "/")
+		leaks = append(leaks, Leak{
+			Kind:   LeakContainer,
+			ID:     c.ID,
+			Name:   name,
+			Detail: fmt.Sprintf("exited with code %d, image %s", inspect.State.ExitCode, c.Image),
+			Size:   c.SizeRw,
+			Since:  finished,
+		})
+	}
+
+	sort.SliceStable(leaks, func(i, j int) bool {
+		if leaks[i].Kind != leaks[j].Kind {
+			return leaks[i].Kind < leaks[j].Kind
+		}
+		return leaks[i].Since.Before(leaks[j].Since)
+	})
+	return leaks, nil
+}
+
+// RemoveLeak removes a leaked image, volume or network. Containers go through
+// RemoveContainer instead, so that the callers' safeguards apply.
+func RemoveLeak(l Leak) error {
+	cli, err := getClient()
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	switch l.Kind {
+	case LeakImage:
+		_, err = cli.ImageRemove(ctx, l.ID, types.ImageRemoveOptions{PruneChildren: true})
+	case LeakVolume:
+		err = cli.VolumeRemove(ctx, l.ID, false)
+	case LeakNetwork:
+		err = cli.NetworkRemove(ctx, l.ID)
+	default:
+		return fmt.Errorf("can't remove a %s here", l.Kind)
+	}
+	return decodeError(err)
+}
+
+// CleanupScore rates how tidy a daemon is from 100 (nothing leaked) down to 0. Every
+// leak costs points, stale containers and volumes most since they may hold data, and
+// every GiB that could be reclaimed costs more.
+func CleanupScore(leaks []Leak) int {
+	cost := map[string]float64{LeakContainer: 4, LeakVolume: 4, LeakImage: 2, LeakNetwork: 1}
+	var penalty float64
+	var size int64
+	for _, l := range leaks {
+		penalty += cost[l.Kind]
+		size += l.Size
+	}
+	penalty += 5 * float64(size) / (1 << 30)
+	return max(0, 100-int(penalty+0.5))
+}
@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LogSource describes whether docker logs can read a container's log driver, and where
+// the logs go when it can't
+type LogSource struct {
+	Driver   string
+	Readable bool   // docker logs can read the driver, itself or from the daemon's cache
+	Cached   bool   // only the daemon's dual logging cache is read, which keeps recent lines
+	Hint     string // where else to find the logs; "" for drivers docker logs reads
+	Command  string // a command that reads them there, "" when there is none
+}
+
+// ContainerLogSource returns how a container's logs can be read
+func ContainerLogSource(containerID string) (LogSource, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return LogSource{}, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return LogSource{}, decodeError(err)
+	}
+	config := inspect.HostConfig.LogConfig
+	return DescribeLogSource(config.Type, config.Config, strings.TrimPrefix(inspect.Name, "/")), nil
+}
+
+// DescribeLogSource classifies a log driver with its log-opts. Since Docker 20.10 the
+// daemon keeps a local copy of what other drivers ship, so docker logs works for them
+// unless cache-disabled is set; that copy is rotated at a few MB.
+func DescribeLogSource(driver string, options map[string]string, name string) LogSource {
+	src := LogSource{Driver: driver}
+	switch driver {
+	case "", "json-file", "local", "journald":
+		src.Readable = true
+	case "none":
+		src.Hint = "Logging is disabled for this container (--log-driver none); recreate it with another driver to keep its output"
+		return src
+	default:
+		src.Readable = options["cache-disabled"] != "true"
+		src.Cached = src.Readable
+	}
+
+	option := func(key, fallback string) string {
+		if v := options[key]; v != "" {
+			return v
+		}
+		return fallback
+	}
+	switch driver {
+	case "journald":
+		// Only fills in the fallback; docker logs reads the journal itself
+		src.Hint = "Logs are in the journal of the daemon's host"
+		src.Command = fmt.Sprintf("journalctl CONTAINER_NAME=%s -f", name)
+		return src
+	case "awslogs":
+		group := option("awslogs-group", "<group>")
+		src.Hint = "Logs are shipped to CloudWatch log group " + group
+		src.Command = "aws logs tail " + group + " --follow"
+		if region := options["awslogs-region"]; region != "" {
+			src.Hint += " in " + region
+			src.Command += " --region " + region
+		}
+	case "gcplogs":
+		src.Hint = "Logs are shipped to Google Cloud Logging"
+		src.Command = fmt.Sprintf(`gcloud logging read 'jsonPayload.container.name="/%s"' --freshness=1h`, name)
+		if project := options["gcp-project"]; project != "" {
+			src.Hint += " in project " + project
+			src.Command += " --project " + project
+		}
+	case "syslog":
+		src.Hint = "Logs are sent to syslog at " + option("syslog-address", "the host's local syslog")
+		if options["syslog-address"] == "" {
+			src.Command = "journalctl -t " + option("tag", name) + " -f"
+		}
+	case "gelf":
+		src.Hint = "Logs are sent to the GELF endpoint " + option("gelf-address", "configured in the daemon")
+	case "fluentd":
+		src.Hint = "Logs are sent to fluentd at " + option("fluentd-address", "localhost:24224")
+	case "splunk":
+		src.Hint = "Logs are sent to Splunk at " + option("splunk-url", "the configured HEC endpoint")
+		if index := options["splunk-index"]; index != "" {
+			src.Hint += ", index " + index
+		}
+	case "etwlogs":
+		src.Hint = "Logs are written to Event Tracing for Windows on the daemon's host"
+	case "json-file", "local", "":
+	default:
+		src.Hint = "Logs are handled by the " + driver + " logging driver"
+	}
+
+	switch {
+	case !src.Readable && src.Hint != "":
+		src.Hint += "; the daemon's local copy is turned off (cache-disabled)"
+	case src.Cached:
+		src.Hint += "; docker logs only shows the daemon's local copy of recent lines"
+	}
+	return src
+}
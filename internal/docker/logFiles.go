@@ -0,0 +1,183 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// logTruncateImage is what the helper container that empties a log file runs when the
+// file can't be written from here
+const logTruncateImage = "busybox"
+
+// LogFile describes where a container's log driver keeps its logs
+type LogFile struct {
+	Driver  string
+	Options map[string]string // the driver's log-opts, e.g. max-size and max-file
+	Path    string            // on the daemon's machine; json-file only
+	Size    int64             // the current file and the rotated ones next to it
+	Files   int
+	Err     error // why Size couldn't be read; nil with Files 0 when there is no file
+}
+
+// ContainerLogFile returns a container's log driver and, for json-file logs on a
+// daemon on this machine, how much disk the log files take. Reading them usually needs
+// root, as the daemon's directory is only open to root.
+func ContainerLogFile(containerID string) (LogFile, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return LogFile{}, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return LogFile{}, decodeError(err)
+	}
+	log := LogFile{
+		Driver:  inspect.HostConfig.LogConfig.Type,
+		Options: inspect.HostConfig.LogConfig.Config,
+		Path:    inspect.LogPath,
+	}
+	switch {
+	case log.Path == "":
+		return log, nil
+	case !endpointFor(containerID).IsLocal():
+		log.Err = errors.New("the daemon runs on another machine")
+		return log, nil
+	}
+
+	// Rotated files are named after the current one: x-json.log.1, x-json.log.2.gz, ...
+	files, _ := filepath.Glob(log.Path + ".*")
+	for _, f := range append([]string{log.Path}, files...) {
+		info, err := os.Stat(f)
+		if err != nil {
+			if f == log.Path {
+				log.Err = logFileError(err)
+				return log, nil
+			}
+			continue
+		}
+		log.Size += info.Size()
+		log.Files++
+	}
+	return log, nil
+}
+
+// logFileError explains why a log file on this machine can't be read
+func logFileError(err error) error {
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		return errors.New("the daemon's directory needs root to read")
+	case errors.Is(err, os.ErrNotExist):
+		return errors.New("the daemon's directory isn't visible from here")
+	}
+	return err
+}
+
+// TruncateLogFile empties the json-file log of a container, like truncate -s 0 on the
+// file docker inspect reports as LogPath. The daemon appends to the file, so it carries
+// on writing at the start; rotated files are left alone. Without write access to the
+// file, a short-lived busybox container mounting its directory empties it. It returns
+// the bytes freed, or -1 when the size wasn't readable.
+func TruncateLogFile(containerID string) (int64, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return 0, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return 0, decodeError(err)
+	}
+	if driver := inspect.HostConfig.LogConfig.Type; driver != "json-file" {
+		return 0, fmt.Errorf("only json-file logs can be truncated, this container logs to %s", driver)
+	}
+	if inspect.LogPath == "" {
+		return 0, errors.New("the daemon reports no log file for this container")
+	}
+
+	if endpointFor(containerID).IsLocal() {
+		if info, err := os.Stat(inspect.LogPath); err == nil {
+			if err := os.Truncate(inspect.LogPath, 0); err == nil {
+				return info.Size(), nil
+			}
+		}
+	}
+
+	if p, err := LoadProfile(endpointFor(containerID)); err == nil && p.Windows() {
+		return 0, errors.New("log files of Windows daemons can only be truncated on the host itself")
+	}
+	if err := ensureImage(ctx, cli, logTruncateImage); err != nil {
+		return 0, err
+	}
+	dir, file := path.Split(inspect.LogPath)
+	created, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:  logTruncateImage,
+			Cmd:    []string{"sh", "-c", `wc -c < "$0" && : > "$0"`, "/logs/" + file},
+			Labels: map[string]string{"dockpulse.log-truncate": inspect.ID},
+		},
+		&container.HostConfig{Binds: []string{dir + ":/logs"}, NetworkMode: "none", LogConfig: helperLogs},
+		nil, nil, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create a container to truncate the log: %w", decodeError(err))
+	}
+	defer cli.ContainerRemove(ctx, created.ID, types.ContainerRemoveOptions{Force: true})
+
+	output, code, err := runToCompletion(ctx, cli, created.ID)
+	if err != nil {
+		return 0, err
+	}
+	if code != 0 {
+		return 0, fmt.Errorf("truncating the log failed with exit code %d: %s", code, strings.TrimSpace(output))
+	}
+	var freed int64 = -1
+	fmt.Sscan(output, &freed)
+	return freed, nil
+}
+
+// helperLogs is the log config of the helper containers runToCompletion reads back; the
+// daemon's default driver may not keep logs to read, like none, syslog or awslogs
+var helperLogs = container.LogConfig{Type: "json-file"}
+
+// runToCompletion starts a created container, waits up to a minute for it to exit and
+// returns what it printed with its exit code. The container has to log with helperLogs.
+func runToCompletion(ctx context.Context, cli *client.Client, id string) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	if err := cli.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+		return "", 0, decodeError(err)
+	}
+	statusCh, errCh := cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	var code int64
+	select {
+	case err := <-errCh:
+		return "", 0, decodeError(err)
+	case status := <-statusCh:
+		code = status.StatusCode
+	}
+
+	logs, err := cli.ContainerLogs(ctx, id, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", code, decodeError(err)
+	}
+	defer logs.Close()
+	var out bytes.Buffer
+	stdcopy.StdCopy(&out, &out, logs)
+	return out.String(), code, nil
+}
@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Streams a log line can come from
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
+// maxLogLine is where an unterminated line is cut so a stream without newlines can't
+// grow a line without bound
+const maxLogLine = 1024 * 1024
+
+// LogLine is one line of container output without its trailing newline
+type LogLine struct {
+	Stream string // Stdout or Stderr; always Stdout for TTY containers
+	Text   string
+}
+
+// LogStream is a followed container log. Without a TTY the daemon multiplexes stdout
+// and stderr into frames with an 8 byte header each; Lines takes them apart.
+type LogStream struct {
+	io.ReadCloser
+	TTY bool
+}
+
+// Lines calls fn for every line until the stream ends
+func (s *LogStream) Lines(fn func(LogLine)) error {
+	if s.TTY {
+		scanner := bufio.NewScanner(s)
+		scanner.Buffer(make([]byte, 64*1024), maxLogLine)
+		for scanner.Scan() {
+			fn(LogLine{Stream: Stdout, Text: strings.TrimSuffix(scanner.Text(), "\r")})
+		}
+		return scanner.Err()
+	}
+
+	stdout := &lineWriter{stream: Stdout, fn: fn}
+	stderr := &lineWriter{stream: Stderr, fn: fn}
+	_, err := stdcopy.StdCopy(stdout, stderr, s)
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// lineWriter splits the frames of one stream into lines; frames don't have to end at
+// a line boundary
+type lineWriter struct {
+	stream string
+	fn     func(LogLine)
+	buf    []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) >= maxLogLine {
+		w.flush()
+	}
+	return len(p), nil
+}
+
+// flush emits a pending partial line
+func (w *lineWriter) flush() {
+	if len(w.buf) > 0 {
+		w.emit(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	w.fn(LogLine{Stream: w.stream, Text: strings.TrimSuffix(string(line), "\r")})
+}
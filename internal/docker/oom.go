@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// MemoryPressure describes how close a container is to its memory limit and how often it
+// ran out of memory
+type MemoryPressure struct {
+	Limit        int64       // configured memory limit in bytes, 0 when unlimited
+	OOMKilled    bool        // the last exit was an OOM kill
+	OOMEvents    []time.Time // OOM events since the container was created
+	RestartCount int
+}
+
+// GetMemoryPressure returns the memory limit and OOM history of a container. The daemon
+// only keeps recent events in memory, so the history can miss OOMs from long ago or from
+// before a daemon restart.
+func GetMemoryPressure(containerID string) (*MemoryPressure, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	pressure := &MemoryPressure{RestartCount: inspect.RestartCount}
+	if inspect.HostConfig != nil {
+		pressure.Limit = inspect.HostConfig.Memory
+	}
+	if inspect.State != nil {
+		pressure.OOMKilled = inspect.State.OOMKilled
+	}
+
+	// A bounded query: the stream ends with io.EOF once events up to now are sent
+	messages, errs := cli.Events(ctx, types.EventsOptions{
+		Since: inspect.Created,
+		Until: strconv.FormatInt(time.Now().Unix(), 10),
+		Filters: filters.NewArgs(
+			filters.Arg("type", "container"),
+			filters.Arg("container", inspect.ID),
+			filters.Arg("event", "oom"),
+		),
+	})
+	for {
+		select {
+		case msg := <-messages:
+			pressure.OOMEvents = append(pressure.OOMEvents, time.Unix(0, msg.TimeNano))
+		case err := <-errs:
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, decodeError(err)
+			}
+			return pressure, nil
+		}
+	}
+}
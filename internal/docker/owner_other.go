@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package docker
+
+import "os"
+
+// fileOwner is not implemented on this platform
+func fileOwner(info os.FileInfo) (uid, gid int64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin
+
+package docker
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the user and group that own a file
+func fileOwner(info os.FileInfo) (uid, gid int64, ok bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int64(st.Uid), int64(st.Gid), true
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ImagePin is how a running container refers to its image
+type ImagePin struct {
+	ContainerID string
+	Name        string
+	Ref         string // the image reference the container was created from
+	ImageID     string
+	Digest      string // registry digest of the image it runs; "" when built or loaded here
+	Pinned      bool   // Ref names a digest or an image ID, so it can't move
+	Mutable     bool   // Ref uses a tag that usually moves, e.g. latest
+}
+
+// PinnedRef returns the reference that pins the image the container runs, e.g.
+// "nginx@sha256:...", or "" when the image has no registry digest
+func (p ImagePin) PinnedRef() string {
+	if p.Digest == "" {
+		return ""
+	}
+	repository, _ := SplitImageRef(p.Ref)
+	return repository + "@" + p.Digest
+}
+
+// ImagePins reports how the running containers refer to their images, sorted with the
+// mutable references first. mutable are the tags that count as moving; a reference
+// without a tag means latest.
+func ImagePins(mutable []string) ([]ImagePin, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	digests := make(map[string][]string) // image ID -> repo digests
+	var pins []ImagePin
+	for _, c := range containers {
+		inspect, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			continue // gone since the list
+		}
+		pin := ImagePin{
+			ContainerID: c.ID,
+			Name:        strings.TrimPrefix(inspect.Name, "/"),
+			Ref:         inspect.Config.Image,
+			ImageID:     strings.TrimPrefix(inspect.Image, "sha256:"),
+		}
+
+		repoDigests, ok := digests[inspect.Image]
+		if !ok {
+			if image, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image); err == nil {
+				repoDigests = image.RepoDigests
+			}
+			digests[inspect.Image] = repoDigests
+		}
+
+		repository, tag := SplitImageRef(pin.Ref)
+		switch {
+		case strings.Contains(pin.Ref, "@") || strings.HasPrefix(pin.Ref, "sha256:") || strings.HasPrefix(pin.ImageID, pin.Ref):
+			pin.Pinned = true
+		case tag == "" || slices.Contains(mutable, tag):
+			pin.Mutable = true
+		}
+		for _, rd := range repoDigests {
+			repo, digest, _ := strings.Cut(rd, "@")
+			if familiarName(repo) == familiarName(repository) {
+				pin.Digest = digest
+				break
+			}
+		}
+		pins = append(pins, pin)
+	}
+
+	sort.SliceStable(pins, func(i, j int) bool {
+		if pins[i].Mutable != pins[j].Mutable {
+			return pins[i].Mutable
+		}
+		return pins[i].Name < pins[j].Name
+	})
+	return pins, nil
+}
+
+// familiarName shortens Docker Hub repositories the way docker prints them, so
+// "docker.io/library/nginx" and "nginx" compare equal
+func familiarName(repository string) string {
+	repository = strings.TrimPrefix(repository, "docker.io/")
+	repository = strings.TrimPrefix(repository, "index.docker.io/")
+	return strings.TrimPrefix(repository, "library/")
+}
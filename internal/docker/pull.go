@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// PullImage pulls an image like docker pull, sending username and password to its
+// registry when set. progress is called with the bytes downloaded so far and the size of
+// the layers known so far, which grows as the pull finds more layers.
+func PullImage(ctx context.Context, ref, username, password string, progress func(current, total int64)) error {
+	cli, err := getClient()
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	var options types.ImagePullOptions
+	if username != "" {
+		repository, _ := SplitImageRef(ref)
+		server, _, _ := strings.Cut(repository, "/")
+		options.RegistryAuth, err = registry.EncodeAuthConfig(registry.AuthConfig{
+			Username:      username,
+			Password:      password,
+			ServerAddress: server,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	reader, err := cli.ImagePull(ctx, ref, options)
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", ref, decodeError(err))
+	}
+	defer reader.Close()
+
+	type layer struct{ current, total int64 }
+	layers := make(map[string]layer)
+	dec := json.NewDecoder(reader)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to pull %s: %w", ref, decodeError(err))
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("failed to pull %s: %s", ref, msg.Error.Message)
+		}
+		switch {
+		case msg.Status == "Downloading" && msg.Progress != nil:
+			layers[msg.ID] = layer{msg.Progress.Current, msg.Progress.Total}
+		case msg.Status == "Download complete" || msg.Status == "Already exists":
+			l := layers[msg.ID]
+			layers[msg.ID] = layer{l.total, l.total}
+		default:
+			continue
+		}
+		var current, total int64
+		for _, l := range layers {
+			current += l.current
+			total += l.total
+		}
+		progress(current, total)
+	}
+}
+
+// LocalDigests maps the tags of a repository present locally to the manifest digests
+// their image was pulled as; images built or loaded here have none
+func LocalDigests(repository string) (map[string][]string, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	images, err := cli.ImageList(context.Background(), types.ImageListOptions{})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	digests := make(map[string][]string)
+	for _, img := range images {
+		var pulled []string
+		for _, rd := range img.RepoDigests {
+			if repo, digest, ok := strings.Cut(rd, "@"); ok && repo == repository {
+				pulled = append(pulled, digest)
+			}
+		}
+		for _, ref := range img.RepoTags {
+			if repo, tag := SplitImageRef(ref); repo == repository {
+				digests[tag] = pulled
+			}
+		}
+	}
+	return digests, nil
+}
@@ -0,0 +1,405 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// ContainerSpec holds the settings that can be edited when recreating a container
+type ContainerSpec struct {
+	Image string
+	Env   []string // KEY=value
+	Ports []string // docker run -p syntax, e.g. "8080:80/tcp"
+	Binds []string // docker run -v syntax, e.g. "data:/var/lib/data:rw"
+
+	Capabilities *CapabilitySet // nil keeps the container's
+}
+
+// GetContainerSpec returns the editable settings of an existing container
+func GetContainerSpec(containerID string) (*ContainerSpec, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	return specFromInspect(inspect), nil
+}
+
+// specFromInspect extracts the editable settings from a container's configuration
+func specFromInspect(inspect types.ContainerJSON) *ContainerSpec {
+	spec := &ContainerSpec{
+		Image: inspect.Config.Image,
+		Env:   inspect.Config.Env,
+		Ports: formatPortBindings(inspect.HostConfig.PortBindings),
+		Binds: append([]string{}, inspect.HostConfig.Binds...),
+	}
+
+	// Anonymous and named volumes mounted without -v would be replaced by fresh
+	// empty volumes, so carry them over as explicit binds
+	bound := make(map[string]bool)
+	for _, b := range spec.Binds {
+		if parts := splitBind(b, inspect.Platform == "windows"); len(parts) >= 2 {
+			bound[parts[1]] = true
+		}
+	}
+	for _, m := range inspect.HostConfig.Mounts {
+		bound[m.Target] = true
+	}
+	for _, m := range inspect.Mounts {
+		if m.Type == "volume" && m.Name != "" && !bound[m.Destination] {
+			bind := m.Name + ":" + m.Destination
+			if !m.RW {
+				bind += ":ro"
+			}
+			spec.Binds = append(spec.Binds, bind)
+		}
+	}
+
+	return spec
+}
+
+// formatPortBindings turns port bindings back into docker run -p syntax
+func formatPortBindings(bindings nat.PortMap) []string {
+	var ports []string
+	for port, hosts := range bindings {
+		for _, h := range hosts {
+			spec := port.Port() + "/" + port.Proto()
+			if h.HostPort != "" {
+				spec = h.HostPort + ":" + spec
+			}
+			if h.HostIP != "" && h.HostIP != "0.0.0.0" {
+				spec = h.HostIP + ":" + spec
+			}
+			ports = append(ports, spec)
+		}
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// RecreateContainer replaces a container with a new one using the same configuration
+// and the given spec. The old container is kept under a backup name until the new
+// one has started, and restored if anything fails. It returns the new container ID.
+func RecreateContainer(containerID string, spec ContainerSpec) (string, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+	wasRunning := inspect.State != nil && inspect.State.Running
+	// Stopping it would delete it, leaving nothing to restore if the new one fails
+	if inspect.HostConfig.AutoRemove {
+		return "", fmt.Errorf("%s was started with --rm and would be deleted when stopped; recreate it by hand", name)
+	}
+	image := imageConfig(ctx, cli, inspect)
+
+	if spec.Image != inspect.Config.Image {
+		if err := ensureImage(ctx, cli, spec.Image); err != nil {
+			return "", decodeError(err)
+		}
+	}
+
+	timeout := 10
+	if err := cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
+		return "", fmt.Errorf("failed to stop %s: %w", name, decodeError(err))
+	}
+
+	backup := fmt.Sprintf("%s-old-%d", name, time.Now().Unix())
+	if err := cli.ContainerRename(ctx, containerID, backup); err != nil {
+		return "", fmt.Errorf("failed to rename %s: %w", name, decodeError(err))
+	}
+
+	restore := func(cause error) error {
+		cli.ContainerRename(ctx, containerID, name)
+		if wasRunning {
+			cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+		}
+		return cause
+	}
+
+	newID, err := createFromInspect(ctx, cli, inspect, image, name, spec)
+	if err != nil {
+		return "", restore(err)
+	}
+
+	if wasRunning {
+		if err := cli.ContainerStart(ctx, newID, types.ContainerStartOptions{}); err != nil {
+			cli.ContainerRemove(ctx, newID, types.ContainerRemoveOptions{Force: true})
+			return "", restore(fmt.Errorf("failed to start new container: %w", decodeError(err)))
+		}
+	}
+
+	if err := cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{}); err != nil {
+		return newID, fmt.Errorf("new container started, but removing the old one (%s) failed: %w", backup, decodeError(err))
+	}
+
+	return newID, nil
+}
+
+// createFromInspect creates a container from an existing container's configuration
+// with the spec applied, and connects it to the same networks. With the config of the
+// image the container was created from, settings that only repeat that image's defaults
+// are left out, so a new image brings its own PATH, command and the like.
+func createFromInspect(ctx context.Context, cli *client.Client, inspect types.ContainerJSON, image *container.Config, name string, spec ContainerSpec) (string, error) {
+	config := *inspect.Config
+	hostConfig := *inspect.HostConfig
+
+	config.Image = spec.Image
+	config.Env = spec.Env
+	if image != nil {
+		withoutImageDefaults(&config, image)
+	}
+	// The hostname defaults to the short container ID; let Docker pick a new one
+	if config.Hostname == inspect.ID[:12] {
+		config.Hostname = ""
+	}
+
+	exposed, bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return "", fmt.Errorf("invalid port mapping: %w", err)
+	}
+	ports := config.ExposedPorts
+	config.ExposedPorts = nat.PortSet{}
+	for port := range ports {
+		config.ExposedPorts[port] = struct{}{}
+	}
+	for port := range exposed {
+		config.ExposedPorts[port] = struct{}{}
+	}
+	hostConfig.PortBindings = bindings
+	hostConfig.Binds = spec.Binds
+	if caps := spec.Capabilities; caps != nil {
+		hostConfig.Privileged = caps.Privileged
+		hostConfig.CapAdd = caps.Add
+		hostConfig.CapDrop = caps.Drop
+	}
+
+	// Only one network can be given at create time; the rest are connected afterwards
+	endpoints := make(map[string]*network.EndpointSettings)
+	mode := hostConfig.NetworkMode
+	primary := mode.NetworkName()
+	// Containers sharing the host's or another container's stack can't join other networks
+	canConnect := !mode.IsHost() && !mode.IsNone() && !mode.IsContainer()
+	for netName, ep := range inspect.NetworkSettings.Networks {
+		if ep == nil {
+			continue
+		}
+		endpoints[netName] = &network.EndpointSettings{
+			IPAMConfig: ep.IPAMConfig,
+			Links:      ep.Links,
+			Aliases:    withoutAlias(ep.Aliases, inspect.ID[:12]),
+			DriverOpts: ep.DriverOpts,
+		}
+	}
+
+	var netConfig *network.NetworkingConfig
+	if ep, ok := endpoints[primary]; ok {
+		netConfig = &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{primary: ep}}
+	}
+
+	created, err := cli.ContainerCreate(ctx, &config, &hostConfig, netConfig, nil, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container: %w", decodeError(err))
+	}
+
+	for netName, ep := range endpoints {
+		if netName == primary || !canConnect {
+			continue
+		}
+		if err := cli.NetworkConnect(ctx, netName, created.ID, ep); err != nil {
+			cli.ContainerRemove(ctx, created.ID, types.ContainerRemoveOptions{Force: true})
+			return "", fmt.Errorf("failed to connect to network %s: %w", netName, decodeError(err))
+		}
+	}
+
+	return created.ID, nil
+}
+
+// imageConfig returns the config of the image a container was created from, or nil
+// when the image is gone and the container's settings can't be told from its defaults
+func imageConfig(ctx context.Context, cli *client.Client, inspect types.ContainerJSON) *container.Config {
+	img, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil || img.Config == nil {
+		return nil
+	}
+	return img.Config
+}
+
+// withoutImageDefaults drops the settings of config that equal the image's, like
+// runCommand leaves them out of the docker run command
+func withoutImageDefaults(config, image *container.Config) {
+	imageEnv := make(map[string]bool, len(image.Env))
+	for _, e := range image.Env {
+		imageEnv[e] = true
+	}
+	var env []string
+	for _, e := range config.Env {
+		if !imageEnv[e] {
+			env = append(env, e)
+		}
+	}
+	config.Env = env
+
+	// Overriding the entrypoint drops the image command, so the command then stays
+	if reflect.DeepEqual([]string(config.Entrypoint), []string(image.Entrypoint)) {
+		config.Entrypoint = nil
+		if reflect.DeepEqual([]string(config.Cmd), []string(image.Cmd)) {
+			config.Cmd = nil
+		}
+	}
+	if config.User == image.User {
+		config.User = ""
+	}
+	if config.WorkingDir == image.WorkingDir {
+		config.WorkingDir = ""
+	}
+	if config.StopSignal == image.StopSignal {
+		config.StopSignal = ""
+	}
+	if reflect.DeepEqual([]string(config.Shell), []string(image.Shell)) {
+		config.Shell = nil
+	}
+	if reflect.DeepEqual(config.Healthcheck, image.Healthcheck) {
+		config.Healthcheck = nil
+	}
+
+	labels := make(map[string]string, len(config.Labels))
+	for key, value := range config.Labels {
+		if v, ok := image.Labels[key]; !ok || v != value {
+			labels[key] = value
+		}
+	}
+	config.Labels = labels
+	ports := nat.PortSet{}
+	for port := range config.ExposedPorts {
+		if _, ok := image.ExposedPorts[port]; !ok {
+			ports[port] = struct{}{}
+		}
+	}
+	config.ExposedPorts = ports
+	volumes := make(map[string]struct{}, len(config.Volumes))
+	for path := range config.Volumes {
+		if _, ok := image.Volumes[path]; !ok {
+			volumes[path] = struct{}{}
+		}
+	}
+	config.Volumes = volumes
+}
+
+// ensureImage pulls an image unless it is already present locally
+func ensureImage(ctx context.Context, cli *client.Client, image string) error {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, image); err == nil {
+		return nil
+	}
+	return pullImage(ctx, cli, image)
+}
+
+// pullImage pulls an image and waits for the pull to finish
+func pullImage(ctx context.Context, cli *client.Client, image string) error {
+	reader, err := cli.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to pull %s: %w", image, decodeError(err))
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(io.Discard, reader)
+	return decodeError(err)
+}
+
+// withoutAlias drops one alias from a list of network aliases
+func withoutAlias(aliases []string, alias string) []string {
+	var result []string
+	for _, a := range aliases {
+		if a != alias {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// CloneContainer creates and starts a copy of a container under a new name. The copy
+// does not take over static IPs, network aliases or compose project labels, so it runs
+// next to the original without receiving its traffic. It returns the new container ID.
+func CloneContainer(containerID, name string, spec ContainerSpec) (string, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", decodeError(err)
+	}
+
+	if spec.Image != inspect.Config.Image {
+		if err := ensureImage(ctx, cli, spec.Image); err != nil {
+			return "", decodeError(err)
+		}
+	}
+
+	inspect.Config.MacAddress = ""
+	labels := map[string]string{"dockpulse.cloned-from": strings.TrimPrefix(inspect.Name, "/")}
+	for key, value := range inspect.Config.Labels {
+		if !strings.HasPrefix(key, "com.docker.compose.") {
+			labels[key] = value
+		}
+	}
+	inspect.Config.Labels = labels
+	for _, ep := range inspect.NetworkSettings.Networks {
+		if ep != nil {
+			ep.IPAMConfig = nil
+			ep.Aliases = nil
+		}
+	}
+
+	newID, err := createFromInspect(ctx, cli, inspect, imageConfig(ctx, cli, inspect), name, spec)
+	if err != nil {
+		return "", decodeError(err)
+	}
+
+	if err := cli.ContainerStart(ctx, newID, types.ContainerStartOptions{}); err != nil {
+		cli.ContainerRemove(ctx, newID, types.ContainerRemoveOptions{Force: true})
+		return "", fmt.Errorf("failed to start clone: %w", decodeError(err))
+	}
+
+	return newID, nil
+}
+
+// splitBind splits a bind into source, destination and mode. On Windows drive letters
+// such as C:\data stay with their path.
+func splitBind(bind string, windows bool) []string {
+	var parts []string
+	for _, part := range strings.Split(bind, ":") {
+		if n := len(parts); windows && n > 0 && len(parts[n-1]) == 1 && (strings.HasPrefix(part, `\`) || strings.HasPrefix(part, "/")) {
+			parts[n-1] += ":" + part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// rescueRepository is the repository rescue images are committed to
+const rescueRepository = "dockpulse-rescue"
+
+// Rescue is a container saved before deletion: a commit of its filesystem and the
+// configuration to recreate it with
+type Rescue struct {
+	Name     string
+	Endpoint Endpoint // daemon the container ran on
+	Image    string   // image the container ran
+	Ref      string   // rescue image
+	Config   []byte   // docker inspect output
+}
+
+var rescueNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// rescueRef names the rescue image of a container, e.g. dockpulse-rescue/web-1:20240131-154500
+func rescueRef(name string, at time.Time) string {
+	repo := strings.Trim(rescueNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if repo == "" {
+		repo = "container"
+	}
+	return fmt.Sprintf("%s/%s:%s", rescueRepository, repo, at.Format("20060102-150405"))
+}
+
+// RescueContainer commits a container to a timestamped rescue image and returns what
+// is needed to restore it. Running containers are paused for the commit.
+func RescueContainer(containerID string) (*Rescue, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, raw, err := cli.ContainerInspectWithRaw(ctx, containerID, false)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	name := strings.TrimPrefix(inspect.Name, "/")
+	ref := rescueRef(name, time.Now())
+	_, err = cli.ContainerCommit(ctx, containerID, types.ContainerCommitOptions{
+		Reference: ref,
+		Comment:   "DockPulse rescue of " + name,
+		Pause:     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit %s: %w", name, decodeError(err))
+	}
+
+	return &Rescue{
+		Name:     name,
+		Endpoint: endpointFor(containerID),
+		Image:    inspect.Config.Image,
+		Ref:      ref,
+		Config:   raw,
+	}, nil
+}
+
+// RemoveRescuedContainer removes a container after RescueContainer. Unlike
+// RemoveContainer it keeps anonymous volumes, which the restored container mounts again.
+func RemoveRescuedContainer(containerID string) error {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	return decodeError(cli.ContainerRemove(context.Background(), containerID, types.ContainerRemoveOptions{Force: true}))
+}
+
+// RestoreContainer recreates a rescued container on e from its rescue image and saved
+// configuration, and starts it if it was running when deleted. It returns the new
+// container ID.
+func RestoreContainer(e Endpoint, ref string, config []byte) (string, error) {
+	var inspect types.ContainerJSON
+	if err := json.Unmarshal(config, &inspect); err != nil {
+		return "", fmt.Errorf("invalid saved configuration: %w", err)
+	}
+	if inspect.ContainerJSONBase == nil || inspect.Config == nil || inspect.NetworkSettings == nil {
+		return "", fmt.Errorf("invalid saved configuration: incomplete inspect output")
+	}
+
+	cli, err := newClient(e)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err != nil {
+		return "", fmt.Errorf("rescue image %s: %w", ref, decodeError(err))
+	}
+
+	spec := specFromInspect(inspect)
+	spec.Image = ref
+	name := strings.TrimPrefix(inspect.Name, "/")
+	newID, err := createFromInspect(ctx, cli, inspect, nil, name, *spec)
+	if err != nil {
+		return "", decodeError(err)
+	}
+
+	if inspect.State != nil && inspect.State.Running {
+		if err := cli.ContainerStart(ctx, newID, types.ContainerStartOptions{}); err != nil {
+			return newID, fmt.Errorf("restored %s, but starting it failed: %w", name, decodeError(err))
+		}
+	}
+	return newID, nil
+}
+
+// RemoveRescueImage deletes a rescue image from e
+func RemoveRescueImage(e Endpoint, ref string) error {
+	cli, err := newClient(e)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	_, err = cli.ImageRemove(context.Background(), ref, types.ImageRemoveOptions{})
+	return decodeError(err)
+}
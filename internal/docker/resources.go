@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// ImageInfo is a summary of a local image
+type ImageInfo struct {
+	ID         string
+	Tags       []string
+	Size       string
+	SizeBytes  int64
+	Created    string
+	Containers int64
+}
+
+// VolumeInfo is a summary of a named volume
+type VolumeInfo struct {
+	Name       string
+	Driver     string
+	Mountpoint string
+	Scope      string
+	Created    string
+	Labels     map[string]string
+}
+
+// NetworkSummary is a summary of a Docker network
+type NetworkSummary struct {
+	ID         string
+	Name       string
+	Driver     string
+	Scope      string
+	Subnets    []string
+	Internal   bool
+	Containers int
+}
+
+// ListImages returns all local images, newest first
+func ListImages() ([]ImageInfo, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	images, err := cli.ImageList(context.Background(), types.ImageListOptions{All: false})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	sort.Slice(images, func(i, j int) bool { return images[i].Created > images[j].Created })
+
+	var result []ImageInfo
+	for _, img := range images {
+		tags := img.RepoTags
+		if len(tags) == 0 {
+			tags = []string{"<none>:<none>"}
+		}
+		result = append(result, ImageInfo{
+			ID:         strings.TrimPrefix(img.ID, "sha256:"),
+			Tags:       tags,
+			Size:       FormatBytes(uint64(img.Size)),
+			SizeBytes:  img.Size,
+			Created:    time.Unix(img.Created, 0).Format("2006-01-02 15:04:05"),
+			Containers: img.Containers,
+		})
+	}
+
+	return result, nil
+}
+
+// ListVolumes returns all volumes sorted by name
+func ListVolumes() ([]VolumeInfo, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	resp, err := cli.VolumeList(context.Background(), volume.ListOptions{})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	var result []VolumeInfo
+	for _, v := range resp.Volumes {
+		created := v.CreatedAt
+		if t, err := time.Parse(time.RFC3339, v.CreatedAt); err == nil {
+			created = t.Format("2006-01-02 15:04:05")
+		}
+		result = append(result, VolumeInfo{
+			Name:       v.Name,
+			Driver:     v.Driver,
+			Mountpoint: v.Mountpoint,
+			Scope:      v.Scope,
+			Created:    created,
+			Labels:     v.Labels,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// ListNetworks returns all networks sorted by name
+func ListNetworks() ([]NetworkSummary, error) {
+	cli, err := getClient()
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	var result []NetworkSummary
+	for _, n := range networks {
+		var subnets []string
+		for _, cfg := range n.IPAM.Config {
+			if cfg.Subnet != "" {
+				subnets = append(subnets, cfg.Subnet)
+			}
+		}
+		result = append(result, NetworkSummary{
+			ID:         n.ID,
+			Name:       n.Name,
+			Driver:     n.Driver,
+			Scope:      n.Scope,
+			Subnets:    subnets,
+			Internal:   n.Internal,
+			Containers: len(n.Containers),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
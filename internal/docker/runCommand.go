@@ -0,0 +1,377 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// defaultShmSize is the /dev/shm size docker gives containers that don't ask for one
+const defaultShmSize = 64 << 20
+
+// RunCommand works out the docker run command that creates a container configured like
+// this one. Settings the image already provides, such as its env and command, are left
+// out. Networks beyond the first are joined with docker network connect, so the result
+// is one line per command with the run options on continuation lines. masked is the same
+// command with every env value passed through mask, for display.
+func RunCommand(containerID string, mask func(key, value string) string) (command, masked string, err error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return "", "", decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", "", decodeError(err)
+	}
+	if inspect.Config == nil || inspect.HostConfig == nil {
+		return "", "", fmt.Errorf("no configuration for %s", containerID)
+	}
+
+	// Without the image (e.g. deleted) every setting is written out
+	image := &container.Config{}
+	if img, _, err := cli.ImageInspectWithRaw(ctx, inspect.Image); err == nil && img.Config != nil {
+		image = img.Config
+	}
+	command = runCommand(inspect, image, nil)
+	if mask == nil {
+		return command, command, nil
+	}
+	return command, runCommand(inspect, image, mask), nil
+}
+
+// runCommand builds the docker run command of a container, leaving out what the image
+// config sets. A non-nil mask replaces env values.
+func runCommand(inspect types.ContainerJSON, image *container.Config, mask func(key, value string) string) string {
+	cfg, host := inspect.Config, inspect.HostConfig
+	name := strings.TrimPrefix(inspect.Name, "/")
+	var opts [][]string
+	add := func(args ...string) { opts = append(opts, args) }
+
+	add("--name", name)
+	switch {
+	case cfg.OpenStdin && cfg.Tty:
+		add("-it")
+	case cfg.OpenStdin:
+		add("-i")
+	case cfg.Tty:
+		add("-t")
+	}
+	if host.AutoRemove {
+		add("--rm")
+	}
+	if p := host.RestartPolicy; p.Name != "" && p.Name != "no" {
+		policy := p.Name
+		if p.Name == "on-failure" && p.MaximumRetryCount > 0 {
+			policy += ":" + strconv.Itoa(p.MaximumRetryCount)
+		}
+		add("--restart", policy)
+	}
+
+	// A container sharing another's network stack takes its hostname too
+	if cfg.Hostname != "" && cfg.Hostname != inspect.ID[:min(12, len(inspect.ID))] && !host.NetworkMode.IsContainer() {
+		add("--hostname", cfg.Hostname)
+	}
+	if cfg.Domainname != "" {
+		add("--domainname", cfg.Domainname)
+	}
+	if cfg.User != image.User {
+		add("--user", cfg.User)
+	}
+	if cfg.WorkingDir != image.WorkingDir && cfg.WorkingDir != "" {
+		add("--workdir", cfg.WorkingDir)
+	}
+
+	imageEnv := make(map[string]bool, len(image.Env))
+	for _, e := range image.Env {
+		imageEnv[e] = true
+	}
+	for _, e := range cfg.Env {
+		if imageEnv[e] {
+			continue
+		}
+		if key, value, ok := strings.Cut(e, "="); ok && mask != nil {
+			e = key + "=" + mask(key, value)
+		}
+		add("-e", e)
+	}
+
+	spec := specFromInspect(inspect)
+	for _, p := range spec.Ports {
+		add("-p", p)
+	}
+	if host.PublishAllPorts {
+		add("-P")
+	}
+	for _, b := range spec.Binds {
+		add("-v", b)
+	}
+	for _, m := range host.Mounts {
+		mount := []string{"type=" + string(m.Type)}
+		if m.Source != "" {
+			mount = append(mount, "source="+m.Source)
+		}
+		mount = append(mount, "target="+m.Target)
+		if m.ReadOnly {
+			mount = append(mount, "readonly")
+		}
+		add("--mount", strings.Join(mount, ","))
+	}
+	for _, path := range sortedKeys(host.Tmpfs) {
+		if opts := host.Tmpfs[path]; opts != "" {
+			add("--tmpfs", path+":"+opts)
+		} else {
+			add("--tmpfs", path)
+		}
+	}
+	for _, v := range host.VolumesFrom {
+		add("--volumes-from", v)
+	}
+
+	// run joins one network, the others are connected afterwards
+	var connects [][]string
+	mode := string(host.NetworkMode)
+	if mode != "" && mode != "default" && mode != "bridge" {
+		add("--network", mode)
+	}
+	if shared := mode == "host" || mode == "none" || host.NetworkMode.IsContainer(); !shared && inspect.NetworkSettings != nil {
+		networks := sortedKeys(inspect.NetworkSettings.Networks)
+		primary := mode
+		if mode == "" || mode == "default" {
+			primary = "bridge"
+			if len(networks) > 0 && !slices.Contains(networks, primary) {
+				primary = networks[0]
+				add("--network", primary)
+			}
+		}
+		for _, n := range networks {
+			if n == primary {
+				opts = append(opts, endpointOptions(inspect, n, "--network-alias")...)
+				continue
+			}
+			connect := []string{"docker", "network", "connect"}
+			for _, o := range endpointOptions(inspect, n, "--alias") {
+				connect = append(connect, o...)
+			}
+			connects = append(connects, append(connect, n, name))
+		}
+	}
+	for _, h := range host.ExtraHosts {
+		add("--add-host", h)
+	}
+	for _, dns := range host.DNS {
+		add("--dns", dns)
+	}
+	for _, s := range host.DNSSearch {
+		add("--dns-search", s)
+	}
+	for _, o := range host.DNSOptions {
+		add("--dns-option", o)
+	}
+
+	if host.Memory > 0 {
+		add("--memory", strconv.FormatInt(host.Memory, 10))
+	}
+	if host.MemoryReservation > 0 {
+		add("--memory-reservation", strconv.FormatInt(host.MemoryReservation, 10))
+	}
+	if host.MemorySwap != 0 {
+		add("--memory-swap", strconv.FormatInt(host.MemorySwap, 10))
+	}
+	if host.NanoCPUs > 0 {
+		add("--cpus", strconv.FormatFloat(float64(host.NanoCPUs)/1e9, 'f', -1, 64))
+	}
+	if host.CPUShares > 0 {
+		add("--cpu-shares", strconv.FormatInt(host.CPUShares, 10))
+	}
+	if host.CpusetCpus != "" {
+		add("--cpuset-cpus", host.CpusetCpus)
+	}
+	if host.PidsLimit != nil && *host.PidsLimit > 0 {
+		add("--pids-limit", strconv.FormatInt(*host.PidsLimit, 10))
+	}
+	if host.ShmSize > 0 && host.ShmSize != defaultShmSize {
+		add("--shm-size", strconv.FormatInt(host.ShmSize, 10))
+	}
+	for _, u := range host.Ulimits {
+		add("--ulimit", u.String())
+	}
+	for _, dev := range host.Devices {
+		device := dev.PathOnHost
+		if dev.PathInContainer != "" && dev.PathInContainer != dev.PathOnHost {
+			device += ":" + dev.PathInContainer
+		}
+		if dev.CgroupPermissions != "" && dev.CgroupPermissions != "rwm" {
+			device += ":" + dev.CgroupPermissions
+		}
+		add("--device", device)
+	}
+
+	if host.Privileged {
+		add("--privileged")
+	}
+	for _, c := range host.CapAdd {
+		add("--cap-add", c)
+	}
+	for _, c := range host.CapDrop {
+		add("--cap-drop", c)
+	}
+	for _, s := range host.SecurityOpt {
+		add("--security-opt", s)
+	}
+	if host.ReadonlyRootfs {
+		add("--read-only")
+	}
+	if host.Init != nil && *host.Init {
+		add("--init")
+	}
+	if host.PidMode != "" {
+		add("--pid", string(host.PidMode))
+	}
+	if host.IpcMode != "" && host.IpcMode != "private" && host.IpcMode != "shareable" {
+		add("--ipc", string(host.IpcMode))
+	}
+	for _, key := range sortedKeys(host.Sysctls) {
+		add("--sysctl", key+"="+host.Sysctls[key])
+	}
+
+	if cfg.StopSignal != "" && cfg.StopSignal != image.StopSignal {
+		add("--stop-signal", cfg.StopSignal)
+	}
+	if cfg.StopTimeout != nil {
+		add("--stop-timeout", strconv.Itoa(*cfg.StopTimeout))
+	}
+	if hc := cfg.Healthcheck; hc != nil && !reflect.DeepEqual(hc, image.Healthcheck) {
+		opts = append(opts, healthOptions(hc)...)
+	}
+	if log := host.LogConfig; log.Type != "" && (log.Type != "json-file" || len(log.Config) > 0) {
+		add("--log-driver", log.Type)
+		for _, key := range sortedKeys(log.Config) {
+			add("--log-opt", key+"="+log.Config[key])
+		}
+	}
+	for _, key := range sortedKeys(cfg.Labels) {
+		if v, ok := image.Labels[key]; !ok || v != cfg.Labels[key] {
+			add("--label", key+"="+cfg.Labels[key])
+		}
+	}
+
+	// Overriding the entrypoint drops the image command, so the command is then always
+	// written out. --entrypoint takes a single word, the rest goes before the command.
+	cmd := []string(cfg.Cmd)
+	if !reflect.DeepEqual([]string(cfg.Entrypoint), []string(image.Entrypoint)) {
+		switch {
+		case len(cfg.Entrypoint) == 0:
+			add("--entrypoint", "")
+		default:
+			add("--entrypoint", cfg.Entrypoint[0])
+			cmd = append(append([]string{}, cfg.Entrypoint[1:]...), cmd...)
+		}
+	} else if reflect.DeepEqual([]string(cfg.Cmd), []string(image.Cmd)) {
+		cmd = nil
+	}
+
+	lines := []string{"docker run -d"}
+	for _, o := range opts {
+		lines = append(lines, "  "+quoteArgs(o))
+	}
+	lines = append(lines, "  "+quoteArgs(append([]string{cfg.Image}, cmd...)))
+	command := strings.Join(lines, " \\\n")
+	for _, c := range connects {
+		command += "\n" + quoteArgs(c)
+	}
+	return command
+}
+
+// healthOptions turns a healthcheck back into docker run flags
+func healthOptions(hc *container.HealthConfig) [][]string {
+	if len(hc.Test) > 0 && hc.Test[0] == "NONE" {
+		return [][]string{{"--no-healthcheck"}}
+	}
+	var opts [][]string
+	if len(hc.Test) > 1 {
+		test := hc.Test[1]
+		if hc.Test[0] == "CMD" {
+			test = quoteArgs(hc.Test[1:])
+		}
+		opts = append(opts, []string{"--health-cmd", test})
+	}
+	durations := []struct {
+		flag  string
+		value fmt.Stringer
+		set   bool
+	}{
+		{"--health-interval", hc.Interval, hc.Interval > 0},
+		{"--health-timeout", hc.Timeout, hc.Timeout > 0},
+		{"--health-start-period", hc.StartPeriod, hc.StartPeriod > 0},
+	}
+	for _, d := range durations {
+		if d.set {
+			opts = append(opts, []string{d.flag, d.value.String()})
+		}
+	}
+	if hc.Retries > 0 {
+		opts = append(opts, []string{"--health-retries", strconv.Itoa(hc.Retries)})
+	}
+	return opts
+}
+
+// quoteArgs joins arguments for a POSIX shell, quoting only the ones that need it
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = quoteArg(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func quoteArg(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@%+", r))
+	}) < 0
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// endpointOptions returns the aliases and fixed address a container has on a network.
+// Docker adds the container name and short ID as aliases itself, so they are left out.
+func endpointOptions(inspect types.ContainerJSON, network, aliasFlag string) [][]string {
+	ep := inspect.NetworkSettings.Networks[network]
+	if ep == nil {
+		return nil
+	}
+	var opts [][]string
+	for _, alias := range ep.Aliases {
+		if alias != strings.TrimPrefix(inspect.Name, "/") && !strings.HasPrefix(inspect.ID, alias) {
+			opts = append(opts, []string{aliasFlag, alias})
+		}
+	}
+	if ep.IPAMConfig != nil && ep.IPAMConfig.IPv4Address != "" {
+		opts = append(opts, []string{"--ip", ep.IPAMConfig.IPv4Address})
+	}
+	return opts
+}
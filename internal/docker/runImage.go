@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+)
+
+// invalidNameChars are the characters Docker doesn't allow in container and volume names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// ImageRunSpec suggests a name and settings for a new container from an image's
+// config: the ports it exposes, published on random host ports, and its volumes as
+// named volumes after the container, e.g. "postgres-data:/var/lib/postgresql/data", so
+// the data outlives the container. The image's own env applies without being listed.
+func ImageRunSpec(imageID string) (string, *ContainerSpec, error) {
+	cli, err := getClient()
+	if err != nil {
+		return "", nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	img, _, err := cli.ImageInspectWithRaw(context.Background(), imageID)
+	if err != nil {
+		return "", nil, decodeError(err)
+	}
+
+	spec := &ContainerSpec{Image: shortImageID(img.ID)}
+	name := spec.Image
+	for _, tag := range img.RepoTags {
+		if tag != UntaggedImage {
+			spec.Image = tag
+			repo, _ := SplitImageRef(tag)
+			name = invalidNameChars.ReplaceAllString(path.Base(repo), "-")
+			break
+		}
+	}
+	if img.Config == nil {
+		return name, spec, nil
+	}
+
+	for port := range img.Config.ExposedPorts {
+		spec.Ports = append(spec.Ports, string(port))
+	}
+	sort.Strings(spec.Ports)
+
+	seen := make(map[string]bool)
+	for dest := range img.Config.Volumes {
+		base := invalidNameChars.ReplaceAllString(strings.Trim(path.Base(dest), "/"), "-")
+		volume := name + "-" + base
+		// Two volumes ending in the same directory name would share the data
+		for i := 2; seen[volume]; i++ {
+			volume = fmt.Sprintf("%s-%s-%d", name, base, i)
+		}
+		seen[volume] = true
+		spec.Binds = append(spec.Binds, volume+":"+dest)
+	}
+	sort.Strings(spec.Binds)
+	return name, spec, nil
+}
+
+// RunImage creates and starts a new container from spec, pulling the image if it is
+// missing. A volume given only as a container path gets an anonymous volume, as with
+// docker run -v /path. It returns the new container ID.
+func RunImage(name string, spec ContainerSpec) (string, error) {
+	cli, err := getClient()
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	if err := ensureImage(ctx, cli, spec.Image); err != nil {
+		return "", decodeError(err)
+	}
+
+	exposed, bindings, err := nat.ParsePortSpecs(spec.Ports)
+	if err != nil {
+		return "", fmt.Errorf("invalid port mapping: %w", err)
+	}
+	config := &container.Config{
+		Image:        spec.Image,
+		Env:          spec.Env,
+		ExposedPorts: exposed,
+	}
+	hostConfig := &container.HostConfig{PortBindings: bindings}
+	for _, bind := range spec.Binds {
+		if strings.Contains(bind, ":") {
+			hostConfig.Binds = append(hostConfig.Binds, bind)
+			continue
+		}
+		if config.Volumes == nil {
+			config.Volumes = make(map[string]struct{})
+		}
+		config.Volumes[bind] = struct{}{}
+	}
+
+	created, err := cli.ContainerCreate(ctx, config, hostConfig, nil, nil, name)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	if err := cli.ContainerStart(ctx, created.ID, types.ContainerStartOptions{}); err != nil {
+		cli.ContainerRemove(ctx, created.ID, types.ContainerRemoveOptions{Force: true})
+		return "", fmt.Errorf("failed to start %s: %w", name, decodeError(err))
+	}
+	return created.ID, nil
+}
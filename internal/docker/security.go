@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Capabilities lists the Linux capabilities in bit order, as /proc/<pid>/status numbers them
+var Capabilities = []string{
+	"CHOWN", "DAC_OVERRIDE", "DAC_READ_SEARCH", "FOWNER", "FSETID", "KILL", "SETGID",
+	"SETUID", "SETPCAP", "LINUX_IMMUTABLE", "NET_BIND_SERVICE", "NET_BROADCAST",
+	"NET_ADMIN", "NET_RAW", "IPC_LOCK", "IPC_OWNER", "SYS_MODULE", "SYS_RAWIO",
+	"SYS_CHROOT", "SYS_PTRACE", "SYS_PACCT", "SYS_ADMIN", "SYS_BOOT", "SYS_NICE",
+	"SYS_RESOURCE", "SYS_TIME", "SYS_TTY_CONFIG", "MKNOD", "LEASE", "AUDIT_WRITE",
+	"AUDIT_CONTROL", "SETFCAP", "MAC_OVERRIDE", "MAC_ADMIN", "SYSLOG", "WAKE_ALARM",
+	"BLOCK_SUSPEND", "AUDIT_READ", "PERFMON", "BPF", "CHECKPOINT_RESTORE",
+}
+
+// DefaultCapabilities are the capabilities Docker grants a container that doesn't add
+// or drop any
+var DefaultCapabilities = []string{
+	"CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "SETGID", "SETUID", "SETPCAP",
+	"NET_BIND_SERVICE", "NET_RAW", "SYS_CHROOT", "MKNOD", "AUDIT_WRITE", "SETFCAP",
+}
+
+// Seccomp profiles of a container
+const (
+	SeccompDefault    = "default"
+	SeccompUnconfined = "unconfined"
+	SeccompCustom     = "custom"
+)
+
+// SecurityProfile is how a container is confined: its capabilities, seccomp profile
+// and AppArmor profile as configured and, when it runs, as its main process sees them
+type SecurityProfile struct {
+	Privileged      bool
+	CapAdd          []string // as configured, without the CAP_ prefix
+	CapDrop         []string
+	Effective       []string // what the configuration grants, in bit order
+	Seccomp         string   // one of the Seccomp constants
+	AppArmor        string   // "" when the host has no AppArmor
+	NoNewPrivileges bool
+	ReadOnly        bool
+	User            string
+
+	// Read from /proc/1 inside the container; nil or empty when it isn't running or the
+	// image has no shell
+	Bounding       []string // the most any process in the container can have
+	Running        []string // what the main process has; none for non-root users
+	RunningSeccomp string   // "disabled", "strict" or "filter"
+	RunningLSM     string   // e.g. "docker-default (enforce)"
+}
+
+// CapabilitySet replaces the capabilities of a container when it is recreated
+type CapabilitySet struct {
+	Privileged bool
+	Add        []string
+	Drop       []string
+}
+
+// GetSecurityProfile reads how a container is confined
+func GetSecurityProfile(containerID string) (*SecurityProfile, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.Platform == "windows" {
+		return nil, fmt.Errorf("capabilities, seccomp and AppArmor only apply to Linux containers")
+	}
+
+	host := inspect.HostConfig
+	p := &SecurityProfile{
+		Privileged: host.Privileged,
+		CapAdd:     normalizeCaps(host.CapAdd),
+		CapDrop:    normalizeCaps(host.CapDrop),
+		Seccomp:    SeccompDefault,
+		AppArmor:   inspect.AppArmorProfile,
+		ReadOnly:   host.ReadonlyRootfs,
+		User:       inspect.Config.User,
+	}
+	p.Effective = EffectiveCapabilities(p.Privileged, p.CapAdd, p.CapDrop)
+	if p.Privileged {
+		p.Seccomp = SeccompUnconfined
+	}
+	for _, opt := range host.SecurityOpt {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok {
+			key, value, _ = strings.Cut(opt, ":") // the old key:value form
+		}
+		switch key {
+		case "seccomp":
+			p.Seccomp = SeccompCustom
+			if value == SeccompUnconfined {
+				p.Seccomp = SeccompUnconfined
+			}
+		case "no-new-privileges":
+			p.NoNewPrivileges = value == "" || value == "true"
+		}
+	}
+
+	if inspect.State != nil && inspect.State.Running {
+		if status, err := ExecCommand(containerID, "cat /proc/1/status"); err == nil {
+			p.Bounding, p.Running, p.RunningSeccomp = parseProcStatus(status)
+		}
+		if lsm, err := ExecCommand(containerID, "cat /proc/1/attr/current"); err == nil {
+			p.RunningLSM = strings.TrimRight(strings.TrimSpace(lsm), "\x00")
+		}
+	}
+	return p, nil
+}
+
+// EffectiveCapabilities works out the capabilities Docker grants for a configuration,
+// in bit order. Like Docker, "ALL" in add grants everything not dropped, "ALL" in drop
+// grants only what is added, and otherwise the added ones win over the dropped ones.
+func EffectiveCapabilities(privileged bool, add, drop []string) []string {
+	if privileged {
+		return slices.Clone(Capabilities)
+	}
+	add, drop = normalizeCaps(add), normalizeCaps(drop)
+	addAll, dropAll := slices.Contains(add, "ALL"), slices.Contains(drop, "ALL")
+	var caps []string
+	for _, c := range Capabilities {
+		var granted bool
+		switch {
+		case addAll:
+			granted = !slices.Contains(drop, c)
+		case dropAll:
+			granted = slices.Contains(add, c)
+		default:
+			granted = slices.Contains(add, c) || slices.Contains(DefaultCapabilities, c) && !slices.Contains(drop, c)
+		}
+		if granted {
+			caps = append(caps, c)
+		}
+	}
+	return caps
+}
+
+// CapabilityChanges returns the --cap-add and --cap-drop lists that grant exactly want.
+// When most defaults go, everything is dropped and only want is added back.
+func CapabilityChanges(want []string) (add, drop []string) {
+	want = normalizeCaps(want)
+	for _, c := range DefaultCapabilities {
+		if !slices.Contains(want, c) {
+			drop = append(drop, c)
+		}
+	}
+	if len(drop) > len(DefaultCapabilities)/2 {
+		return want, []string{"ALL"}
+	}
+	for _, c := range want {
+		if !slices.Contains(DefaultCapabilities, c) {
+			add = append(add, c)
+		}
+	}
+	return add, drop
+}
+
+// normalizeCaps upper-cases capability names and strips the CAP_ prefix Docker accepts
+func normalizeCaps(caps []string) []string {
+	var out []string
+	for _, c := range caps {
+		out = append(out, strings.TrimPrefix(strings.ToUpper(c), "CAP_"))
+	}
+	return out
+}
+
+// parseProcStatus reads the bounding and effective capabilities and the seccomp mode
+// from the contents of /proc/<pid>/status
+func parseProcStatus(status string) (bounding, effective []string, seccomp string) {
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "CapBnd":
+			bounding = capsFromMask(value)
+		case "CapEff":
+			effective = capsFromMask(value)
+		case "Seccomp":
+			seccomp = map[string]string{"0": "disabled", "1": "strict", "2": "filter"}[value]
+		}
+	}
+	return bounding, effective, seccomp
+}
+
+// capsFromMask names the capabilities set in a hex mask such as "00000000a80425fb"
+func capsFromMask(hex string) []string {
+	mask, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return nil
+	}
+	caps := []string{}
+	for bit, c := range Capabilities {
+		if mask&(1<<bit) != 0 {
+			caps = append(caps, c)
+		}
+	}
+	return caps
+}
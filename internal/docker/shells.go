@@ -0,0 +1,126 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+)
+
+// Shell is a shell commands can be run with in a container
+type Shell struct {
+	Name string // bash, ash, sh or busybox; pwsh, powershell or cmd on Windows
+	Path string
+}
+
+// Command returns the command line running script with the shell; the zero Shell is /bin/sh
+func (s Shell) Command(script string) []string {
+	switch {
+	case s.Path == "":
+		return []string{"/bin/sh", "-c", script}
+	case s.Name == "busybox":
+		return []string{s.Path, "sh", "-c", script}
+	case s.Name == "cmd":
+		return []string{s.Path, "/S", "/C", script}
+	case s.Name == "pwsh" || s.Name == "powershell":
+		return []string{s.Path, "-NoLogo", "-NoProfile", "-Command", script}
+	}
+	return []string{s.Path, "-c", script}
+}
+
+// windowsShell runs commands in Windows containers, which have no /bin/sh; even
+// nanoserver images ship cmd
+var windowsShell = Shell{"cmd", `C:\Windows\System32\cmd.exe`}
+
+// defaultShell is the shell commands run with when none was chosen
+func defaultShell(containerID string) Shell {
+	if onWindows(containerID) {
+		return windowsShell
+	}
+	return Shell{}
+}
+
+// shellFor returns the command line running script in a container with shell, or
+// with the default shell of the container when shell is the zero Shell
+func shellFor(containerID string, shell Shell, script string) []string {
+	if shell.Path == "" {
+		shell = defaultShell(containerID)
+	}
+	return shell.Command(script)
+}
+
+// shellCandidates are looked for in order of preference
+var shellCandidates = []Shell{
+	{"bash", "/bin/bash"},
+	{"bash", "/usr/bin/bash"},
+	{"ash", "/bin/ash"},
+	{"sh", "/bin/sh"},
+	{"sh", "/usr/bin/sh"},
+	{"busybox", "/bin/busybox"},
+	{"busybox", "/busybox/busybox"}, // distroless :debug images
+}
+
+// windowsShellCandidates are looked for instead in Windows containers
+var windowsShellCandidates = []Shell{
+	{"pwsh", `C:\Program Files\PowerShell\pwsh.exe`},
+	{"pwsh", `C:\Program Files\PowerShell\7\pwsh.exe`},
+	{"powershell", `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`},
+	windowsShell,
+}
+
+const noShellHint = "The image is probably distroless. Use its debug variant if there is one, " +
+	"or run a debug container that shares its namespaces: " +
+	"docker run -it --rm --pid=container:<name> --network=container:<name> busybox sh"
+
+// DetectShells returns the shells present in a container, preferred first and one per
+// name. It checks the filesystem instead of running anything, so it also works on
+// stopped containers; a container without any shell gives an ErrNoShell error.
+func DetectShells(containerID string) ([]Shell, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	candidates, names := shellCandidates, "bash, ash, sh or busybox"
+	if onWindows(containerID) {
+		candidates, names = windowsShellCandidates, "pwsh, powershell or cmd"
+	}
+
+	var found []Shell
+	seen := make(map[string]bool)
+	for _, shell := range candidates {
+		if seen[shell.Name] {
+			continue
+		}
+		stat, err := cli.ContainerStatPath(ctx, containerID, shell.Path)
+		if err != nil {
+			// A missing file is expected; anything else, like a removed container, is not
+			if errdefs.IsNotFound(err) && !strings.Contains(strings.ToLower(err.Error()), "no such container") {
+				continue
+			}
+			return nil, decodeError(err)
+		}
+		if stat.Mode.IsDir() {
+			continue
+		}
+		seen[shell.Name] = true
+		found = append(found, shell)
+	}
+
+	if len(found) == 0 {
+		return nil, &Error{
+			Kind:    ErrNoShell,
+			Message: fmt.Sprintf("The container has no shell: none of %s exists.", names),
+			Hint:    noShellHint,
+			Err:     errors.New("no shell found in container"),
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+)
+
+// ContainerSize is the disk space a container takes
+type ContainerSize struct {
+	RW    int64 // writable layer
+	Image int64 // the image layers underneath, shared with other containers of the image
+}
+
+// ListContainerSizesOn returns the sizes of all containers on a daemon by container ID.
+// The daemon has to walk every writable layer for this, so it can take a while.
+func ListContainerSizesOn(e Endpoint) (map[string]ContainerSize, error) {
+	cli, err := newClient(e)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true, Size: true})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	sizes := make(map[string]ContainerSize, len(containers))
+	for _, c := range containers {
+		sizes[c.ID] = ContainerSize{RW: c.SizeRw, Image: max(c.SizeRootFs-c.SizeRw, 0)}
+	}
+	return sizes, nil
+}
@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// TerminalCommand returns the docker CLI command line opening an interactive shell in
+// a container on its daemon, for a real terminal outside the TUI. The zero Shell uses
+// the container's default shell.
+func TerminalCommand(containerID string, shell Shell) ([]string, error) {
+	cli, err := exec.LookPath("docker")
+	if err != nil {
+		return nil, errors.New("the docker CLI is needed to open shells in a terminal and was not found on PATH")
+	}
+	if shell.Path == "" {
+		shell = defaultShell(containerID)
+	}
+	if shell.Path == "" {
+		shell = Shell{"sh", "/bin/sh"}
+	}
+
+	e := endpointFor(containerID)
+	args := []string{cli, "--host", e.HostURL()}
+	if e.UsesTLS() {
+		args = append(args, "--tls")
+		if e.TLSCA != "" {
+			args = append(args, "--tlsverify", "--tlscacert", expandHome(e.TLSCA))
+		}
+		if e.TLSCert != "" {
+			args = append(args, "--tlscert", expandHome(e.TLSCert), "--tlskey", expandHome(e.TLSKey))
+		}
+	}
+	args = append(args, "exec", "-it", containerID, shell.Path)
+	if shell.Name == "busybox" {
+		args = append(args, "sh")
+	}
+	return args, nil
+}
@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+)
+
+// ResourceLimits are cgroup limits applied to running containers; zero leaves a limit unchanged
+type ResourceLimits struct {
+	Memory   int64 // bytes
+	NanoCPUs int64 // 1e9 = one CPU
+}
+
+// ParseResourceLimits parses docker-style values such as "512m" and "1.5"; empty strings are skipped
+func ParseResourceLimits(memory, cpus string) (ResourceLimits, error) {
+	var limits ResourceLimits
+
+	if memory = strings.TrimSpace(memory); memory != "" {
+		bytes, err := units.RAMInBytes(memory)
+		if err != nil {
+			return limits, fmt.Errorf("invalid memory limit %q: %w", memory, err)
+		}
+		limits.Memory = bytes
+	}
+
+	if cpus = strings.TrimSpace(cpus); cpus != "" {
+		n, err := strconv.ParseFloat(cpus, 64)
+		if err != nil || n <= 0 {
+			return limits, fmt.Errorf("invalid CPU limit %q", cpus)
+		}
+		limits.NanoCPUs = int64(n * 1e9)
+	}
+
+	if limits.Memory == 0 && limits.NanoCPUs == 0 {
+		return limits, fmt.Errorf("no limit given")
+	}
+	return limits, nil
+}
+
+// UpdateResources changes the memory and CPU limits of a container without restarting it
+func UpdateResources(containerID string, limits ResourceLimits) error {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	if p, err := LoadProfile(endpointFor(containerID)); err == nil && !p.CanLimit() {
+		if p.Windows() {
+			return fmt.Errorf("the limits of a running container can't be changed on a Windows daemon")
+		}
+		return fmt.Errorf("this rootless daemon (cgroup v%s, %s driver) doesn't enforce resource limits", p.CgroupVersion, p.CgroupDriver)
+	}
+	_, err = cli.ContainerUpdate(context.Background(), containerID, container.UpdateConfig{
+		Resources: container.Resources{
+			Memory:   limits.Memory,
+			NanoCPUs: limits.NanoCPUs,
+		},
+	})
+	return decodeError(err)
+}
+
+// PullLatestImage pulls the image reference a container was created from and reports
+// whether it now points to a different image than the one the container runs
+func PullLatestImage(containerID string) (bool, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return false, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return false, decodeError(err)
+	}
+
+	ref := inspect.Config.Image
+	if strings.HasPrefix(ref, "sha256:") {
+		return false, fmt.Errorf("created from an image ID, not a tag")
+	}
+
+	if err := pullImage(ctx, cli, ref); err != nil {
+		return false, decodeError(err)
+	}
+
+	image, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return false, decodeError(err)
+	}
+	return image.ID != inspect.Image, nil
+}
@@ -0,0 +1,183 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// overflowID is the ID the kernel shows for host IDs a user namespace doesn't map
+const overflowID = 65534
+
+// IDRange is one line of /proc/<pid>/uid_map or gid_map: Count IDs starting at Inside
+// in the container are the ones starting at Host on the host
+type IDRange struct {
+	Inside int64
+	Host   int64
+	Count  int64
+}
+
+// IDMap translates between container and host IDs
+type IDMap []IDRange
+
+// ToHost returns the host ID of a container ID, false when the namespace doesn't map it
+func (m IDMap) ToHost(id int64) (int64, bool) {
+	for _, r := range m {
+		if id >= r.Inside && id < r.Inside+r.Count {
+			return r.Host + id - r.Inside, true
+		}
+	}
+	return 0, false
+}
+
+// ToContainer returns the container ID of a host ID, false when it isn't mapped, in
+// which case processes in the container see it as nobody (65534)
+func (m IDMap) ToContainer(id int64) (int64, bool) {
+	for _, r := range m {
+		if id >= r.Host && id < r.Host+r.Count {
+			return r.Inside + id - r.Host, true
+		}
+	}
+	return overflowID, false
+}
+
+// Identity reports whether the map leaves every ID as it is, i.e. there is no user
+// namespace of its own
+func (m IDMap) Identity() bool {
+	for _, r := range m {
+		if r.Inside != r.Host {
+			return false
+		}
+	}
+	return len(m) > 0
+}
+
+// ProcessIDs is a process of a container with its host user and group
+type ProcessIDs struct {
+	PID     string
+	Command string
+	HostUID int64
+	HostGID int64
+}
+
+// BindOwner is who owns the host path of a bind mount
+type BindOwner struct {
+	Source      string
+	Destination string
+	Checked     bool // false for daemons on other machines or unreadable paths
+	UID         int64
+	GID         int64
+}
+
+// UserMapping is how the users of a container map to host users, which decides what
+// the container may do with bind-mounted host files
+type UserMapping struct {
+	User       string // the configured user, "" for the image default
+	GroupAdd   []string
+	UsernsMode string // "host" opts out of a daemon-wide userns-remap
+	UIDMap     IDMap  // nil when it couldn't be read
+	GIDMap     IDMap
+	Processes  []ProcessIDs
+	Binds      []BindOwner
+}
+
+// GetUserMapping reads the UID and GID maps of a running container, from /proc on the
+// host for local daemons and from inside the container otherwise, with the host users
+// of its processes and the owners of its bind mounts
+func GetUserMapping(containerID string) (*UserMapping, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	ctx := context.Background()
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.Platform == "windows" {
+		return nil, fmt.Errorf("user namespaces only apply to Linux containers")
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, fmt.Errorf("%s is not running", strings.TrimPrefix(inspect.Name, "/"))
+	}
+
+	m := &UserMapping{
+		User:       inspect.Config.User,
+		GroupAdd:   inspect.HostConfig.GroupAdd,
+		UsernsMode: string(inspect.HostConfig.UsernsMode),
+	}
+	local := endpointFor(containerID).IsLocal()
+	m.UIDMap = readIDMap(containerID, inspect.State.Pid, "uid_map", local)
+	m.GIDMap = readIDMap(containerID, inspect.State.Pid, "gid_map", local)
+
+	// ps runs on the daemon's host, so it reports host IDs
+	if top, err := cli.ContainerTop(ctx, containerID, []string{"-o", "pid,uid,gid,comm"}); err == nil {
+		col := make(map[string]int)
+		for i, title := range top.Titles {
+			col[title] = i
+		}
+		for _, proc := range top.Processes {
+			if len(proc) < len(top.Titles) {
+				continue
+			}
+			uid, _ := strconv.ParseInt(proc[col["UID"]], 10, 64)
+			gid, _ := strconv.ParseInt(proc[col["GID"]], 10, 64)
+			m.Processes = append(m.Processes, ProcessIDs{
+				PID:     proc[col["PID"]],
+				Command: strings.Join(proc[col["COMMAND"]:], " "),
+				HostUID: uid,
+				HostGID: gid,
+			})
+		}
+	}
+
+	for _, mount := range inspect.Mounts {
+		if mount.Type != "bind" {
+			continue
+		}
+		b := BindOwner{Source: mount.Source, Destination: mount.Destination}
+		if local {
+			if info, err := os.Stat(mount.Source); err == nil {
+				b.UID, b.GID, b.Checked = fileOwner(info)
+			}
+		}
+		m.Binds = append(m.Binds, b)
+	}
+	return m, nil
+}
+
+// readIDMap reads a uid_map or gid_map of a container's main process, nil when neither
+// the host nor the container can show it
+func readIDMap(containerID string, pid int, file string, local bool) IDMap {
+	if local && pid > 0 {
+		if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/%s", pid, file)); err == nil {
+			return parseIDMap(string(data))
+		}
+	}
+	if out, err := ExecCommand(containerID, "cat /proc/1/"+file); err == nil {
+		return parseIDMap(out)
+	}
+	return nil
+}
+
+// parseIDMap parses lines such as "0 100000 65536"
+func parseIDMap(text string) IDMap {
+	var m IDMap
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		inside, err1 := strconv.ParseInt(fields[0], 10, 64)
+		host, err2 := strconv.ParseInt(fields[1], 10, 64)
+		count, err3 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 == nil && err2 == nil && err3 == nil {
+			m = append(m, IDRange{Inside: inside, Host: host, Count: count})
+		}
+	}
+	return m
+}
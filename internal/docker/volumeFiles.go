@@ -0,0 +1,340 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// volumeBrowserImage lists volumes, and is what the helper container of a volume nobody
+// mounts runs. That helper is only created, never started: copying files out of a
+// container works without that.
+const volumeBrowserImage = "busybox"
+
+// volumeListScript prints one "mode size mtime path" line per entry of the volume, the
+// mode in hex as stat's %f gives it, parents before their contents. head ends find
+// early on volumes with more entries than are listed.
+const volumeListScript = `cd /volume && find . -mindepth 1 -exec stat -c '%f %s %Y %n' {} + 2>/dev/null | head -n "$0"`
+
+// maxVolumeFiles caps how many entries of a volume are listed
+const maxVolumeFiles = 50000
+
+// VolumeFile is a file or directory inside a volume
+type VolumeFile struct {
+	Path    string // relative to the volume root, slash separated
+	Dir     bool
+	Size    int64 // 0 for directories
+	Mode    os.FileMode
+	ModTime time.Time
+}
+
+// VolumeBrowser reads the contents of a named volume through a container that mounts it
+type VolumeBrowser struct {
+	Volume    string
+	Via       string // the container used, or "temporary container"
+	endpoint  Endpoint
+	container string
+	root      string // where the volume is mounted in the container
+	temporary bool
+	windows   bool // no busybox to list with; the listing is read from an archive
+}
+
+// OpenVolume prepares a volume of the current host for browsing. It borrows a container
+// that already mounts the volume, running or not, and otherwise creates a stopped
+// helper container that Close removes again.
+func OpenVolume(name string) (*VolumeBrowser, error) {
+	e := CurrentEndpoint()
+	cli, err := newClient(e)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	p, err := LoadProfile(e)
+	windows := err == nil && p.Windows()
+
+	ctx := context.Background()
+	users, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("volume", name)),
+	})
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	for _, c := range users {
+		for _, m := range c.Mounts {
+			if m.Type == "volume" && m.Name == name {
+				via := c.ID[:12]
+				if len(c.Names) > 0 {
+					via = strings.TrimPrefix(c.Names[0], "/")
+				}
+				return &VolumeBrowser{Volume: name, Via: via, endpoint: e, container: c.ID, root: m.Destination, windows: windows}, nil
+			}
+		}
+	}
+
+	// There is no small image to borrow for Windows, whose base images must match the
+	// host's build
+	if windows {
+		return nil, fmt.Errorf("no container mounts volume %s; Windows daemons can only browse volumes a container mounts", name)
+	}
+
+	if err := ensureImage(ctx, cli, volumeBrowserImage); err != nil {
+		return nil, err
+	}
+	created, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:  volumeBrowserImage,
+			Cmd:    []string{"true"},
+			Labels: map[string]string{"dockpulse.volume-browser": name},
+		},
+		&container.HostConfig{Binds: []string{name + ":/volume:ro"}},
+		nil, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a container for volume %s: %w", name, decodeError(err))
+	}
+	return &VolumeBrowser{Volume: name, Via: "temporary container", endpoint: e, container: created.ID, root: "/volume", temporary: true}, nil
+}
+
+// Files lists everything in the volume, parents before their contents. A short-lived
+// busybox container mounting the volume read-only lists it, so only the names and sizes
+// travel, not the contents. The listing stops after maxVolumeFiles entries; truncated
+// reports whether it did.
+func (v *VolumeBrowser) Files(ctx context.Context) (files []VolumeFile, truncated bool, err error) {
+	if v.windows {
+		return v.archiveFiles(ctx)
+	}
+	cli, err := newClient(v.endpoint)
+	if err != nil {
+		return nil, false, decodeError(err)
+	}
+	defer cli.Close()
+
+	if err := ensureImage(ctx, cli, volumeBrowserImage); err != nil {
+		return nil, false, err
+	}
+	created, err := cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:  volumeBrowserImage,
+			Cmd:    []string{"sh", "-c", volumeListScript, strconv.Itoa(maxVolumeFiles + 1)},
+			Labels: map[string]string{"dockpulse.volume-browser": v.Volume},
+		},
+		&container.HostConfig{Binds: []string{v.Volume + ":/volume:ro"}, NetworkMode: "none", LogConfig: helperLogs},
+		nil, nil, "")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create a container to list volume %s: %w", v.Volume, decodeError(err))
+	}
+	defer cli.ContainerRemove(context.Background(), created.ID, types.ContainerRemoveOptions{Force: true})
+
+	output, code, err := runToCompletion(ctx, cli, created.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if code != 0 {
+		return nil, false, fmt.Errorf("listing volume %s failed with exit code %d: %s", v.Volume, code, strings.TrimSpace(output))
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		file, ok := parseVolumeLine(line)
+		if !ok {
+			continue // e.g. a name with a newline in it
+		}
+		if len(files) == maxVolumeFiles {
+			return files, true, nil
+		}
+		files = append(files, file)
+	}
+	return files, false, nil
+}
+
+// parseVolumeLine reads a line of volumeListScript
+func parseVolumeLine(line string) (VolumeFile, bool) {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) != 4 || !strings.HasPrefix(fields[3], "./") {
+		return VolumeFile{}, false
+	}
+	raw, err1 := strconv.ParseUint(fields[0], 16, 32)
+	size, err2 := strconv.ParseInt(fields[1], 10, 64)
+	mtime, err3 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return VolumeFile{}, false
+	}
+	file := VolumeFile{
+		Path:    strings.TrimPrefix(fields[3], "./"),
+		Mode:    unixMode(uint32(raw)),
+		ModTime: time.Unix(mtime, 0),
+	}
+	file.Dir = file.Mode.IsDir()
+	if !file.Dir {
+		file.Size = size
+	}
+	return file, true
+}
+
+// unixMode converts a raw st_mode to an os.FileMode
+func unixMode(raw uint32) os.FileMode {
+	mode := os.FileMode(raw & 0o777)
+	switch raw & 0o170000 {
+	case 0o040000:
+		mode |= os.ModeDir
+	case 0o120000:
+		mode |= os.ModeSymlink
+	case 0o010000:
+		mode |= os.ModeNamedPipe
+	case 0o140000:
+		mode |= os.ModeSocket
+	case 0o020000:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case 0o060000:
+		mode |= os.ModeDevice
+	}
+	if raw&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if raw&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if raw&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// archiveFiles lists a volume from the tar headers of an archive of it, for Windows
+// daemons that have no busybox. The daemon sends the file contents along, so large
+// volumes take a while.
+func (v *VolumeBrowser) archiveFiles(ctx context.Context) (files []VolumeFile, truncated bool, err error) {
+	cli, err := newClient(v.endpoint)
+	if err != nil {
+		return nil, false, decodeError(err)
+	}
+	defer cli.Close()
+
+	reader, _, err := cli.CopyFromContainer(ctx, v.container, v.root)
+	if err != nil {
+		return nil, false, decodeError(err)
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, false, nil
+		}
+		if err != nil {
+			return files, false, fmt.Errorf("failed to read volume %s: %w", v.Volume, err)
+		}
+
+		// Entries are named after the mount point's last element, e.g. volume/data/x
+		rel := strings.Trim(hdr.Name, "/")
+		if i := strings.Index(rel, "/"); i >= 0 {
+			rel = rel[i+1:]
+		} else {
+			continue // the root itself
+		}
+		file := VolumeFile{
+			Path:    rel,
+			Dir:     hdr.Typeflag == tar.TypeDir,
+			Mode:    hdr.FileInfo().Mode(),
+			ModTime: hdr.ModTime,
+		}
+		if !file.Dir {
+			file.Size = hdr.Size
+		}
+		files = append(files, file)
+		if len(files) >= maxVolumeFiles {
+			return files, true, nil
+		}
+	}
+}
+
+// containerPath joins a slash separated path to a mount point, with backslashes when
+// the mount point is a Windows path such as C:\data
+func containerPath(root, rel string) string {
+	if !strings.Contains(root, `\`) && !strings.Contains(root, ":") {
+		return path.Join(root, rel)
+	}
+	return strings.TrimRight(root, `\/`) + `\` + strings.ReplaceAll(rel, "/", `\`)
+}
+
+// Download copies a file of the volume into dir and returns where it was written.
+// Directories are written as a tar archive.
+func (v *VolumeBrowser) Download(file VolumeFile, dir string) (string, error) {
+	cli, err := newClient(v.endpoint)
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer cli.Close()
+
+	reader, _, err := cli.CopyFromContainer(context.Background(), v.container, containerPath(v.root, file.Path))
+	if err != nil {
+		return "", decodeError(err)
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := path.Base(file.Path)
+	if file.Dir {
+		name += ".tar"
+	}
+	dest := freePath(filepath.Join(dir, name))
+
+	src := io.Reader(reader)
+	if !file.Dir {
+		tr := tar.NewReader(reader)
+		if _, err := tr.Next(); err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		src = tr
+	}
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return "", fmt.Errorf("failed to download %s: %w", file.Path, err)
+	}
+	return dest, out.Close()
+}
+
+// Close removes the helper container, if one was created
+func (v *VolumeBrowser) Close() error {
+	if !v.temporary {
+		return nil
+	}
+	cli, err := newClient(v.endpoint)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+	return decodeError(cli.ContainerRemove(context.Background(), v.container, types.ContainerRemoveOptions{Force: true}))
+}
+
+// freePath returns p, or p with a number before the extension if p already exists
+func freePath(p string) string {
+	ext := filepath.Ext(p)
+	base := strings.TrimSuffix(p, ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			return p
+		}
+		p = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
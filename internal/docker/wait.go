@@ -0,0 +1,151 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// readyPollInterval is how often WaitReady inspects the container
+const readyPollInterval = time.Second
+
+// WaitReady waits until a container runs and, when healthy is set, until its
+// healthcheck passes. Containers without a healthcheck are ready once running. It
+// fails when the container exits or turns unhealthy, and when ctx ends first.
+func WaitReady(ctx context.Context, containerID string, healthy bool) error {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return decodeError(err)
+	}
+	defer cli.Close()
+
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		inspect, err := cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return waitError(ctx, healthy)
+			}
+			return decodeError(err)
+		}
+		if ready, err := readyState(inspect, healthy); ready || err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return waitError(ctx, healthy)
+		case <-ticker.C:
+		}
+	}
+}
+
+// readyState reports whether an inspected container is ready, or why it never will be
+func readyState(inspect types.ContainerJSON, healthy bool) (bool, error) {
+	state := inspect.State
+	if state == nil {
+		return false, nil
+	}
+	if !state.Running {
+		if state.Status == "exited" || state.Status == "dead" {
+			return false, fmt.Errorf("container exited with code %d", state.ExitCode)
+		}
+		return false, nil
+	}
+	if !healthy || state.Health == nil {
+		return true, nil
+	}
+
+	switch state.Health.Status {
+	case types.Healthy:
+		return true, nil
+	case types.Unhealthy:
+		reason := "healthcheck failed"
+		if n := len(state.Health.Log); n > 0 {
+			if output := strings.TrimSpace(state.Health.Log[n-1].Output); output != "" {
+				reason += ": " + output
+			}
+		}
+		return false, fmt.Errorf("container is unhealthy, %s", reason)
+	}
+	return false, nil
+}
+
+// Health is the state of a container's Docker healthcheck
+type Health struct {
+	Status        string // starting, healthy or unhealthy
+	FailingStreak int    // checks failed in a row
+	Output        string // of the last check
+}
+
+// GetHealth returns the healthcheck state of a container, nil when it has no healthcheck
+func GetHealth(ctx context.Context, containerID string) (*Health, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	if inspect.State == nil || inspect.State.Health == nil {
+		return nil, nil
+	}
+	h := inspect.State.Health
+	health := &Health{Status: h.Status, FailingStreak: h.FailingStreak}
+	if n := len(h.Log); n > 0 {
+		health.Output = strings.TrimSpace(h.Log[n-1].Output)
+	}
+	return health, nil
+}
+
+// waitError explains why WaitReady gave up
+func waitError(ctx context.Context, healthy bool) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		if healthy {
+			return fmt.Errorf("timed out waiting for the container to become healthy")
+		}
+		return fmt.Errorf("timed out waiting for the container to run")
+	}
+	return ctx.Err()
+}
+
+// ExitStatus is how a container stopped
+type ExitStatus struct {
+	Code      int64
+	OOMKilled bool
+}
+
+// WaitExit blocks until a container stops, on the daemon's wait endpoint rather than by
+// polling, and returns how it ended. It gives up when ctx ends.
+func WaitExit(ctx context.Context, containerID string) (*ExitStatus, error) {
+	cli, err := clientFor(containerID)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+	defer cli.Close()
+
+	statusCh, errCh := cli.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return nil, decodeError(err)
+	case status := <-statusCh:
+		if status.Error != nil {
+			return nil, errors.New(status.Error.Message)
+		}
+		exit := &ExitStatus{Code: status.StatusCode}
+		// Gone already when it was started with --rm
+		if inspect, err := cli.ContainerInspect(ctx, containerID); err == nil && inspect.State != nil {
+			exit.OOMKilled = inspect.State.OOMKilled
+		}
+		return exit, nil
+	}
+}
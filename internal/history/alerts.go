@@ -0,0 +1,85 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Alerts live in a single bucket keyed like samples
+var alertsBucket = []byte("alerts")
+
+// Severities of alerts
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info" // recoveries and expected exits
+)
+
+// Alert is a warning the dashboard raised, e.g. a crash loop or a failing probe
+type Alert struct {
+	Time      time.Time `json:"-"`
+	Severity  string    `json:"severity"`
+	Kind      string    `json:"kind"`
+	Container string    `json:"container,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// RecordAlert stores an alert
+func (s *Store) RecordAlert(a Alert) error {
+	value, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(alertsBucket)
+		if err != nil {
+			return err
+		}
+		at := a.Time
+		for b.Get(timeKey(at)) != nil {
+			at = at.Add(time.Nanosecond)
+		}
+		return b.Put(timeKey(at), value)
+	})
+}
+
+// Alerts returns the alerts raised from from until before to, oldest first
+func (s *Store) Alerts(from, to time.Time) ([]Alert, error) {
+	var alerts []Alert
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(alertsBucket)
+		if b == nil {
+			return nil
+		}
+		end := timeKey(to)
+		c := b.Cursor()
+		for k, v := c.Seek(timeKey(from)); k != nil && string(k) < string(end); k, v = c.Next() {
+			var a Alert
+			if err := json.Unmarshal(v, &a); err != nil {
+				continue
+			}
+			a.Time = keyTime(k)
+			alerts = append(alerts, a)
+		}
+		return nil
+	})
+	return alerts, err
+}
+
+// pruneAlerts deletes alerts older than the uptime retention
+func pruneAlerts(tx *bolt.Tx, now time.Time) error {
+	b := tx.Bucket(alertsBucket)
+	if b == nil {
+		return nil
+	}
+	cutoff := timeKey(now.Add(-uptimeRetention))
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && string(k) < string(cutoff); k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Disk usage lives in one bucket per host, keyed like samples
+var diskBucket = []byte("disk")
+
+// DiskSample is the disk space a daemon's objects took at one point in time, in bytes
+type DiskSample struct {
+	Time       time.Time `json:"-"`
+	Images     int64     `json:"images"`
+	Containers int64     `json:"containers"`
+	Volumes    int64     `json:"volumes"`
+	BuildCache int64     `json:"build_cache"`
+}
+
+// Total returns the space taken by all objects
+func (d DiskSample) Total() int64 {
+	return d.Images + d.Containers + d.Volumes + d.BuildCache
+}
+
+// RecordDisk stores the disk usage of host
+func (s *Store) RecordDisk(host string, sample DiskSample) error {
+	value, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(diskBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists([]byte(host))
+		if err != nil {
+			return err
+		}
+		return b.Put(timeKey(sample.Time), value)
+	})
+}
+
+// DiskAt returns the last disk usage of every host recorded at or before at
+func (s *Store) DiskAt(at time.Time) (map[string]DiskSample, error) {
+	samples := make(map[string]DiskSample)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(diskBucket)
+		if root == nil {
+			return nil
+		}
+		key := timeKey(at)
+		return root.ForEachBucket(func(host []byte) error {
+			c := root.Bucket(host).Cursor()
+			k, v := c.Seek(key)
+			switch {
+			case k == nil:
+				k, v = c.Last()
+			case string(k) > string(key):
+				k, v = c.Prev()
+			}
+			var sample DiskSample
+			if k == nil || json.Unmarshal(v, &sample) != nil {
+				return nil
+			}
+			sample.Time = keyTime(k)
+			samples[string(host)] = sample
+			return nil
+		})
+	})
+	return samples, err
+}
+
+// pruneDisk deletes disk usage older than the uptime retention
+func pruneDisk(tx *bolt.Tx, now time.Time) error {
+	return pruneNested(tx.Bucket(diskBucket), timeKey(now.Add(-uptimeRetention)))
+}
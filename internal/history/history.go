@@ -0,0 +1,180 @@
+package history
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Samples live in one bucket per container, keyed by big endian unix nanoseconds so a
+// cursor walks them in time order
+var samplesBucket = []byte("samples")
+
+// Sample is the CPU and memory usage of a container at one point in time
+type Sample struct {
+	Time time.Time
+	CPU  float64
+	Mem  float64
+}
+
+// Store persists container stats samples in a local bolt database
+type Store struct {
+	db        *bolt.DB
+	retention time.Duration
+}
+
+// DefaultPath returns the history file location in the user cache directory
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "history.db")
+}
+
+// Open opens or creates the history file. It fails quickly when another DockPulse
+// holds the file open.
+func Open(path string, retention time.Duration) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("history file %s is in use by another DockPulse", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(samplesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise history %s: %w", path, err)
+	}
+
+	s := &Store{db: db, retention: retention}
+	return s, s.Prune()
+}
+
+// Close closes the history file
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Retention returns how long samples are kept
+func (s *Store) Retention() time.Duration {
+	return s.retention
+}
+
+// Record stores one sample per container, keyed by container ID, in a single transaction
+func (s *Store) Record(at time.Time, samples map[string]Sample) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(samplesBucket)
+		for id, sample := range samples {
+			b, err := root.CreateBucketIfNotExists([]byte(id))
+			if err != nil {
+				return err
+			}
+			if err := b.Put(timeKey(at), encodeSample(sample)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query returns the samples of a container between from and to, oldest first
+func (s *Store) Query(containerID string, from, to time.Time) ([]Sample, error) {
+	var samples []Sample
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(samplesBucket).Bucket([]byte(containerID))
+		if b == nil {
+			return nil
+		}
+		c := b.Cursor()
+		end := timeKey(to)
+		for k, v := c.Seek(timeKey(from)); k != nil && string(k) <= string(end); k, v = c.Next() {
+			sample := decodeSample(v)
+			sample.Time = keyTime(k)
+			samples = append(samples, sample)
+		}
+		return nil
+	})
+	return samples, err
+}
+
+// Prune deletes samples older than the retention period, and containers left without
+// any, as well as state changes, lifecycle events, disk usage and alerts past the uptime
+// retention
+func (s *Store) Prune() error {
+	cutoff := timeKey(time.Now().Add(-s.retention))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(samplesBucket)
+
+		var empty [][]byte
+		err := root.ForEachBucket(func(id []byte) error {
+			c := root.Bucket(id).Cursor()
+			for k, _ := c.First(); k != nil && string(k) < string(cutoff); k, _ = c.First() {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+			if k, _ := c.First(); k == nil {
+				empty = append(empty, append([]byte(nil), id...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range empty {
+			if err := root.DeleteBucket(id); err != nil {
+				return err
+			}
+		}
+		if err := pruneStates(tx, time.Now()); err != nil {
+			return err
+		}
+		if err := pruneTimeline(tx, time.Now()); err != nil {
+			return err
+		}
+		if err := pruneDisk(tx, time.Now()); err != nil {
+			return err
+		}
+		return pruneAlerts(tx, time.Now())
+	})
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+// encodeSample packs the usage values as two float32s, which is precise enough for percentages
+func encodeSample(s Sample) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint32(value[:4], math.Float32bits(float32(s.CPU)))
+	binary.BigEndian.PutUint32(value[4:], math.Float32bits(float32(s.Mem)))
+	return value
+}
+
+func decodeSample(value []byte) Sample {
+	if len(value) < 8 {
+		return Sample{}
+	}
+	return Sample{
+		CPU: float64(math.Float32frombits(binary.BigEndian.Uint32(value[:4]))),
+		Mem: float64(math.Float32frombits(binary.BigEndian.Uint32(value[4:]))),
+	}
+}
@@ -0,0 +1,151 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Lifecycle events live in one bucket per host and container name, keyed like samples,
+// so a recreated container continues the timeline of the one it replaced
+var timelineBucket = []byte("timeline")
+
+// Event is something that happened to a container, as reported by the daemon
+type Event struct {
+	Time   time.Time `json:"-"`
+	ID     string    `json:"id"`
+	Action string    `json:"action"` // Docker event action, e.g. "die" or "health_status: unhealthy"
+	Image  string    `json:"image,omitempty"`
+	Detail string    `json:"detail,omitempty"` // e.g. the exit code of a die event
+}
+
+// ContainerKey names a container across recreations: its host and name
+type ContainerKey struct {
+	Host string
+	Name string
+}
+
+func timelineKey(host, name string) []byte {
+	return []byte(host + "/" + name)
+}
+
+// RecordEvent adds an event to the timeline of the container called name on host
+func (s *Store) RecordEvent(host, name string, e Event) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(timelineBucket)
+		if err != nil {
+			return err
+		}
+		b, err := root.CreateBucketIfNotExists(timelineKey(host, name))
+		if err != nil {
+			return err
+		}
+		// Events of the same nanosecond, e.g. from two daemons' clocks, get the next free key
+		at := e.Time
+		for b.Get(timeKey(at)) != nil {
+			at = at.Add(time.Nanosecond)
+		}
+		return b.Put(timeKey(at), value)
+	})
+}
+
+// Timeline returns the events of the container called name on host since from, oldest
+// first
+func (s *Store) Timeline(host, name string, from time.Time) ([]Event, error) {
+	var events []Event
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(timelineBucket)
+		if root == nil {
+			return nil
+		}
+		if b := root.Bucket(timelineKey(host, name)); b != nil {
+			events = readEvents(b, from)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// Timelines returns the events of every container since from, by host and container
+// name, oldest first
+func (s *Store) Timelines(from time.Time) (map[ContainerKey][]Event, error) {
+	timelines := make(map[ContainerKey][]Event)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(timelineBucket)
+		if root == nil {
+			return nil
+		}
+		return root.ForEachBucket(func(key []byte) error {
+			// Names can't contain a slash, hosts might
+			i := bytes.LastIndexByte(key, '/')
+			if i < 0 {
+				return nil
+			}
+			events := readEvents(root.Bucket(key), from)
+			if len(events) > 0 {
+				timelines[ContainerKey{Host: string(key[:i]), Name: string(key[i+1:])}] = events
+			}
+			return nil
+		})
+	})
+	return timelines, err
+}
+
+// readEvents decodes the events of a timeline from from on
+func readEvents(b *bolt.Bucket, from time.Time) []Event {
+	var events []Event
+	c := b.Cursor()
+	for k, v := c.Seek(timeKey(from)); k != nil; k, v = c.Next() {
+		var e Event
+		if err := json.Unmarshal(v, &e); err != nil {
+			continue
+		}
+		e.Time = keyTime(k)
+		events = append(events, e)
+	}
+	return events
+}
+
+// pruneTimeline deletes events older than the uptime retention and the timelines left
+// empty
+func pruneTimeline(tx *bolt.Tx, now time.Time) error {
+	return pruneNested(tx.Bucket(timelineBucket), timeKey(now.Add(-uptimeRetention)))
+}
+
+// pruneNested deletes the keys before cutoff in every bucket of root, and the buckets
+// left empty
+func pruneNested(root *bolt.Bucket, cutoff []byte) error {
+	if root == nil {
+		return nil
+	}
+
+	var empty [][]byte
+	err := root.ForEachBucket(func(key []byte) error {
+		c := root.Bucket(key).Cursor()
+		for k, _ := c.First(); k != nil && string(k) < string(cutoff); k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		if k, _ := c.First(); k == nil {
+			empty = append(empty, append([]byte(nil), key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range empty {
+		if err := root.DeleteBucket(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// State changes live in one bucket per container, keyed like samples; the names bucket
+// keeps the name and host of every container, which outlive the container itself
+var (
+	statesBucket = []byte("states")
+	namesBucket  = []byte("names")
+)
+
+// uptimeRetention is how long state changes are kept, regardless of the stats retention,
+// so monthly reports work with the default 24h of stats
+const uptimeRetention = 35 * 24 * time.Hour
+
+// State is whether a container runs
+type State byte
+
+const (
+	Down    State = 0
+	Up      State = 1
+	Removed State = 2 // the container was deleted; its tracking ends
+)
+
+// StateChange is the state of a container from a point in time on
+type StateChange struct {
+	ID    string
+	Name  string // empty keeps the recorded name and host
+	Host  string
+	State State
+}
+
+// containerName is the value stored in the names bucket
+type containerName struct {
+	Name string `json:"name"`
+	Host string `json:"host,omitempty"`
+}
+
+// Availability is how long a container was up within a time window
+type Availability struct {
+	ID      string
+	Name    string
+	Host    string
+	Up      time.Duration
+	Tracked time.Duration // the part of the window the container existed and was followed
+	Outages int           // times it went down; being removed isn't one
+}
+
+// Percent returns the share of the tracked time the container was up
+func (a Availability) Percent() float64 {
+	if a.Tracked <= 0 {
+		return 0
+	}
+	return 100 * float64(a.Up) / float64(a.Tracked)
+}
+
+// RecordStates stores the states of containers as of at, skipping the ones already in
+// that state
+func (s *Store) RecordStates(at time.Time, changes []StateChange) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(statesBucket)
+		if err != nil {
+			return err
+		}
+		names, err := tx.CreateBucketIfNotExists(namesBucket)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			b, err := root.CreateBucketIfNotExists([]byte(change.ID))
+			if err != nil {
+				return err
+			}
+			if state, ok := stateAt(b, at); ok && state == change.State {
+				continue
+			}
+			if err := b.Put(timeKey(at), []byte{byte(change.State)}); err != nil {
+				return err
+			}
+			if change.Name == "" {
+				continue // keep the name already known
+			}
+			name, err := json.Marshal(containerName{Name: change.Name, Host: change.Host})
+			if err != nil {
+				return err
+			}
+			if err := names.Put([]byte(change.ID), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// stateAt returns the state of a container at a point in time, from its last change
+// before or at it
+func stateAt(b *bolt.Bucket, at time.Time) (State, bool) {
+	c := b.Cursor()
+	key := timeKey(at)
+	k, v := c.Seek(key)
+	switch {
+	case k == nil:
+		k, v = c.Last()
+	case string(k) > string(key):
+		k, v = c.Prev()
+	}
+	if k == nil || len(v) == 0 {
+		return 0, false
+	}
+	return State(v[0]), true
+}
+
+// Tracked returns the latest state of every container recorded on a host
+func (s *Store) Tracked(host string) (map[string]State, error) {
+	tracked := make(map[string]State)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root, names := tx.Bucket(statesBucket), tx.Bucket(namesBucket)
+		if root == nil || names == nil {
+			return nil
+		}
+		return root.ForEachBucket(func(id []byte) error {
+			var name containerName
+			if json.Unmarshal(names.Get(id), &name) != nil || name.Host != host {
+				return nil
+			}
+			if _, v := root.Bucket(id).Cursor().Last(); len(v) > 0 {
+				tracked[string(id)] = State(v[0])
+			}
+			return nil
+		})
+	})
+	return tracked, err
+}
+
+// Availability adds up how long every container was up between from and to. A
+// container is tracked from its first recorded state and until it was removed.
+func (s *Store) Availability(from, to time.Time) ([]Availability, error) {
+	var report []Availability
+	err := s.db.View(func(tx *bolt.Tx) error {
+		root, names := tx.Bucket(statesBucket), tx.Bucket(namesBucket)
+		if root == nil || names == nil {
+			return nil
+		}
+		return root.ForEachBucket(func(id []byte) error {
+			a := availability(root.Bucket(id), from, to)
+			if a.Tracked <= 0 {
+				return nil
+			}
+			var name containerName
+			json.Unmarshal(names.Get(id), &name)
+			a.ID, a.Name, a.Host = string(id), name.Name, name.Host
+			report = append(report, a)
+			return nil
+		})
+	})
+	return report, err
+}
+
+// availability walks the state changes of one container across a window
+func availability(b *bolt.Bucket, from, to time.Time) Availability {
+	var a Availability
+	state, known := stateAt(b, from)
+	since := from
+
+	// finish ends the current stretch at t
+	finish := func(t time.Time) {
+		if !known || state == Removed || !t.After(since) {
+			return
+		}
+		a.Tracked += t.Sub(since)
+		if state == Up {
+			a.Up += t.Sub(since)
+		}
+	}
+
+	c := b.Cursor()
+	end := timeKey(to)
+	for k, v := c.Seek(timeKey(from)); k != nil && string(k) <= string(end); k, v = c.Next() {
+		if len(v) == 0 {
+			continue
+		}
+		at := keyTime(k)
+		finish(at)
+		next := State(v[0])
+		if known && state == Up && next == Down {
+			a.Outages++
+		}
+		state, known, since = next, true, at
+	}
+	finish(to)
+	return a
+}
+
+// pruneStates deletes state changes older than the uptime retention, keeping the last
+// one before it as the state the remaining history starts from. Containers removed
+// before the cutoff are forgotten.
+func pruneStates(tx *bolt.Tx, now time.Time) error {
+	root, names := tx.Bucket(statesBucket), tx.Bucket(namesBucket)
+	if root == nil {
+		return nil
+	}
+	cutoff := timeKey(now.Add(-uptimeRetention))
+
+	var gone [][]byte
+	err := root.ForEachBucket(func(id []byte) error {
+		c := root.Bucket(id).Cursor()
+		for {
+			if k, _ := c.First(); k == nil || string(k) >= string(cutoff) {
+				break
+			}
+			if next, _ := c.Next(); next == nil || string(next) >= string(cutoff) {
+				break // the first change is the state at the cutoff
+			}
+			c.First()
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		if k, v := c.Last(); k == nil || (string(k) < string(cutoff) && len(v) > 0 && State(v[0]) == Removed) {
+			gone = append(gone, append([]byte(nil), id...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range gone {
+		if err := root.DeleteBucket(id); err != nil {
+			return err
+		}
+		if names != nil {
+			if err := names.Delete(id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
@@ -0,0 +1,161 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/audit"
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/plugin"
+)
+
+// maxOutput caps how much of a hook's output is recorded in the audit log
+const maxOutput = 64 << 10
+
+// Runner runs the configured hooks around container actions and records the actions
+// and hook runs in the audit log
+type Runner struct {
+	hooks []config.Hook
+	log   *audit.Log // nil when the audit log is off
+}
+
+// New returns a runner for the configured hooks that records to log
+func New(hooks []config.Hook, log *audit.Log) *Runner {
+	return &Runner{hooks: hooks, log: log}
+}
+
+// Around runs the pre hooks of an action on a container, the action itself when they
+// all succeeded, and then its post hooks. A failed pre hook cancels the action and is
+// returned as its error; post hooks can't change the outcome. A nil Runner only runs fn.
+func (r *Runner) Around(action string, t plugin.Target, fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+	if err := r.Pre(action, t); err != nil {
+		r.record(action, t, "", err)
+		return err
+	}
+	err := fn()
+	r.record(action, t, "", err)
+	r.Post(action, t, err)
+	return err
+}
+
+// Pre runs the pre hooks of an action and returns the first failure
+func (r *Runner) Pre(action string, t plugin.Target) error {
+	if r == nil {
+		return nil
+	}
+	for i := range r.hooks {
+		h := &r.hooks[i]
+		if h.Pre == "" || !h.Matches(action, t.Name) {
+			continue
+		}
+		if err := r.run(h, h.Pre, "pre", action, t, nil); err != nil {
+			return fmt.Errorf("%s cancelled, pre hook failed: %w", action, err)
+		}
+	}
+	return nil
+}
+
+// Post runs the post hooks of an action that ended with outcome
+func (r *Runner) Post(action string, t plugin.Target, outcome error) {
+	if r == nil {
+		return
+	}
+	for i := range r.hooks {
+		h := &r.hooks[i]
+		if h.Post != "" && h.Matches(action, t.Name) {
+			r.run(h, h.Post, "post", action, t, outcome)
+		}
+	}
+}
+
+// Record adds an action that ran without hooks to the audit log, for callers that run
+// Pre and Post themselves around several steps
+func (r *Runner) Record(action string, t plugin.Target, outcome error) {
+	if r != nil {
+		r.record(action, t, "", outcome)
+	}
+}
+
+// run runs one hook command and records it
+func (r *Runner) run(h *config.Hook, command, stage, action string, t plugin.Target, outcome error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Deadline())
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	result, message := "ok", ""
+	if outcome != nil {
+		result, message = "failed", outcome.Error()
+	}
+	cmd.Env = append(os.Environ(), plugin.Environment(t)...)
+	cmd.Env = append(cmd.Env,
+		"DOCKPULSE_ACTION="+action,
+		"DOCKPULSE_HOOK="+stage,
+		"DOCKPULSE_RESULT="+result,
+		"DOCKPULSE_ERROR="+message)
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("timed out after %s", h.Deadline())
+	case errors.As(err, &exitErr):
+		err = fmt.Errorf("exit code %d", exitErr.ExitCode())
+		if line := lastLine(out.String()); line != "" {
+			err = fmt.Errorf("exit code %d: %s", exitErr.ExitCode(), line)
+		}
+	}
+
+	output := out.String()
+	if len(output) > maxOutput {
+		output = output[:maxOutput] + "\n… output cut"
+	}
+	entry := r.entry(action, t, stage, err)
+	entry.Command, entry.Output = command, output
+	if exitErr != nil {
+		entry.ExitCode = exitErr.ExitCode()
+	}
+	r.log.Record(entry)
+	return err
+}
+
+// record adds an action, or the hook stage of one, to the audit log
+func (r *Runner) record(action string, t plugin.Target, stage string, err error) {
+	r.log.Record(r.entry(action, t, stage, err))
+}
+
+func (r *Runner) entry(action string, t plugin.Target, stage string, err error) audit.Entry {
+	e := audit.Entry{
+		Time:      time.Now(),
+		Action:    action,
+		Container: t.Name,
+		ID:        t.ID,
+		Host:      t.Host,
+		Hook:      stage,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// lastLine returns the last non-empty line of a hook's output, which usually says why
+// it failed
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,428 @@
+package logarchive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+// retryInterval is how long a worker waits after its log stream ended, e.g. because the
+// container stopped, before it follows the logs again
+const retryInterval = 10 * time.Second
+
+// States of an archive
+const (
+	StateArchiving = "archiving"
+	StateWaiting   = "waiting" // the container is stopped or the daemon unreachable
+	StateFailed    = "failed"
+)
+
+// Options controls where archives are written and when they are rotated
+type Options struct {
+	Dir     string
+	MaxSize int64         // rotate once the file reaches this many bytes
+	MaxAge  time.Duration // rotate once the first line in the file is this old
+	Keep    int           // rotated files kept per container
+}
+
+// Status describes the archive of one container
+type Status struct {
+	Name      string // archive name, the container name made safe for file names
+	ID        string
+	State     string
+	File      string
+	Size      int64 // of the current file
+	Lines     int   // written since archiving started
+	Rotations int
+	LastLine  time.Time
+	Err       error
+}
+
+// Archiver continuously appends the logs of selected containers to files named after
+// them, rotating and gzipping the files as they grow
+type Archiver struct {
+	ctx     context.Context
+	opts    Options
+	mu      sync.Mutex
+	workers map[string]*worker
+}
+
+type worker struct {
+	stop   context.CancelFunc
+	done   chan struct{}
+	status Status
+
+	// Only used by the worker goroutine
+	file   *os.File
+	opened time.Time
+}
+
+// DefaultDir returns the archive location in the user cache directory
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "logs")
+}
+
+// New creates an archiver; all archiving stops when ctx is cancelled
+func New(ctx context.Context, opts Options) *Archiver {
+	return &Archiver{
+		ctx:     ctx,
+		opts:    opts,
+		workers: make(map[string]*worker),
+	}
+}
+
+// Dir returns the directory archives are written to
+func (a *Archiver) Dir() string {
+	return a.opts.Dir
+}
+
+// Start archives the logs of a container under name. A container that replaced another
+// one with the same name, e.g. after docker compose up, continues its archive.
+func (a *Archiver) Start(id, name string) {
+	name = FileName(name)
+	a.mu.Lock()
+	old, ok := a.workers[name]
+	a.mu.Unlock()
+	if ok {
+		if old.status.ID == id {
+			return
+		}
+		a.Stop(name)
+	}
+
+	ctx, stop := context.WithCancel(a.ctx)
+	w := &worker{
+		stop: stop,
+		done: make(chan struct{}),
+		status: Status{
+			Name:  name,
+			ID:    id,
+			State: StateWaiting,
+			File:  filepath.Join(a.opts.Dir, name+".log"),
+		},
+	}
+	a.mu.Lock()
+	a.workers[name] = w
+	a.mu.Unlock()
+	go a.run(ctx, w)
+}
+
+// Stop ends archiving under name and waits until the file is closed
+func (a *Archiver) Stop(name string) {
+	name = FileName(name)
+	a.mu.Lock()
+	w, ok := a.workers[name]
+	delete(a.workers, name)
+	a.mu.Unlock()
+	if ok {
+		w.stop()
+		<-w.done
+	}
+}
+
+// Get returns the status of the archive under name
+func (a *Archiver) Get(name string) (Status, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	w, ok := a.workers[FileName(name)]
+	if !ok {
+		return Status{}, false
+	}
+	return w.status, true
+}
+
+// Statuses returns the status of every archive, ordered by name
+func (a *Archiver) Statuses() []Status {
+	a.mu.Lock()
+	statuses := make([]Status, 0, len(a.workers))
+	for _, w := range a.workers {
+		statuses = append(statuses, w.status)
+	}
+	a.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// run follows the container logs until the archive is stopped, resuming after the last
+// archived line whenever the stream ends
+func (a *Archiver) run(ctx context.Context, w *worker) {
+	defer close(w.done)
+	defer w.closeFile()
+
+	since := lastTimestamp(w.status.File)
+	for {
+		err := a.follow(ctx, w, &since)
+		if ctx.Err() != nil {
+			return
+		}
+
+		a.mu.Lock()
+		w.status.Err = err
+		switch {
+		case err == nil || errors.Is(err, docker.ErrDaemonUnreachable):
+			w.status.State = StateWaiting
+		default:
+			w.status.State = StateFailed
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// follow appends log lines to the archive until the stream ends; since is advanced to
+// the timestamp of every line written
+func (a *Archiver) follow(ctx context.Context, w *worker, since *time.Time) error {
+	if err := a.open(w); err != nil {
+		return err
+	}
+
+	from := *since
+	if !from.IsZero() {
+		from = from.Add(time.Nanosecond)
+	}
+	logs, err := docker.StreamLogText(ctx, w.status.ID, from)
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+
+	a.mu.Lock()
+	w.status.State, w.status.Err = StateArchiving, nil
+	a.mu.Unlock()
+
+	reader := bufio.NewReader(logs)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if err := a.write(w, line, since); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// write appends one line, rotating the file first when it is due
+func (a *Archiver) write(w *worker, line string, since *time.Time) error {
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	a.mu.Lock()
+	size := w.status.Size
+	a.mu.Unlock()
+	if size > 0 && (size+int64(len(line)) > a.opts.MaxSize || time.Since(w.opened) >= a.opts.MaxAge) {
+		if err := a.rotate(w); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write %s: %w", w.status.File, err)
+	}
+
+	at, _, ok := docker.SplitLogLine(line)
+	if ok {
+		*since = at
+	}
+	a.mu.Lock()
+	w.status.Size += int64(len(line))
+	w.status.Lines++
+	if ok {
+		w.status.LastLine = at
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// open opens the current archive file for appending if it isn't open yet
+func (a *Archiver) open(w *worker) error {
+	if w.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(a.opts.Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create log archive directory: %w", err)
+	}
+	f, err := os.OpenFile(w.status.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", w.status.File, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open %s: %w", w.status.File, err)
+	}
+
+	w.file = f
+	w.opened = time.Now()
+	if first, ok := firstTimestamp(w.status.File); ok {
+		w.opened = first
+	}
+	a.mu.Lock()
+	w.status.Size = info.Size()
+	a.mu.Unlock()
+	return nil
+}
+
+// rotate moves the current file aside as name-YYYYMMDD-HHMMSS.log.gz, starts a new one
+// and drops the oldest rotated files beyond Keep
+func (a *Archiver) rotate(w *worker) error {
+	w.closeFile()
+
+	// Names must stay unique when a busy container rotates twice within a second
+	var rotated string
+	for at := time.Now(); ; at = at.Add(time.Second) {
+		rotated = filepath.Join(a.opts.Dir, fmt.Sprintf("%s-%s.log", w.status.Name, at.Format("20060102-150405")))
+		if _, err := os.Stat(rotated + ".gz"); os.IsNotExist(err) {
+			break
+		}
+	}
+	if err := os.Rename(w.status.File, rotated); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", w.status.File, err)
+	}
+	if err := compress(rotated); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", rotated, err)
+	}
+	a.prune(w.status.Name)
+
+	a.mu.Lock()
+	w.status.Rotations++
+	a.mu.Unlock()
+	return a.open(w)
+}
+
+// rotatedPattern matches the suffix of rotated archives
+var rotatedPattern = regexp.MustCompile(`^-\d{8}-\d{6}\.log\.gz$`)
+
+// prune removes the oldest rotated files of an archive beyond Keep
+func (a *Archiver) prune(name string) {
+	matches, _ := filepath.Glob(filepath.Join(a.opts.Dir, name+"-*.log.gz"))
+	var rotated []string
+	for _, m := range matches {
+		// Globbing "web-*" also finds the archives of "web-api"
+		if rotatedPattern.MatchString(strings.TrimPrefix(filepath.Base(m), name)) {
+			rotated = append(rotated, m)
+		}
+	}
+	sort.Strings(rotated)
+	for len(rotated) > a.opts.Keep {
+		os.Remove(rotated[0])
+		rotated = rotated[1:]
+	}
+}
+
+func (w *worker) closeFile() {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+}
+
+// compress gzips path to path.gz and removes path
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// unsafeChars are replaced in file names
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// FileName turns a container name, possibly qualified with its host, into an archive name
+func FileName(name string) string {
+	name = unsafeChars.ReplaceAllString(strings.TrimPrefix(name, "/"), "_")
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
+
+// firstTimestamp returns the time of the first line in an archive file
+func firstTimestamp(path string) (time.Time, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(f).ReadString('\n')
+	at, _, ok := docker.SplitLogLine(line)
+	return at, ok
+}
+
+// lastTimestamp returns the time of the last line in an archive file, so archiving
+// resumes where it stopped when DockPulse was last running; zero without one
+func lastTimestamp(path string) time.Time {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+
+	const tailSize = 64 * 1024
+	info, err := f.Stat()
+	if err != nil {
+		return time.Time{}
+	}
+	offset := max(info.Size()-tailSize, 0)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return time.Time{}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(tail), "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if t, _, ok := docker.SplitLogLine(lines[i]); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,52 @@
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// httpTarget posts entries as a JSON array to any HTTP endpoint, e.g. a Vector,
+// Fluent Bit or Logstash http input
+type httpTarget struct {
+	cfg config.LogTarget
+}
+
+func newHTTP(cfg config.LogTarget) *httpTarget {
+	return &httpTarget{cfg: cfg}
+}
+
+func (h *httpTarget) Name() string {
+	return h.cfg.Name
+}
+
+type httpEntry struct {
+	Time      string `json:"time"`
+	Container string `json:"container"`
+	ID        string `json:"id"`
+	Image     string `json:"image"`
+	Host      string `json:"host"`
+	Message   string `json:"message"`
+}
+
+func (h *httpTarget) Send(ctx context.Context, entries []Entry) error {
+	batch := make([]httpEntry, len(entries))
+	for i, e := range entries {
+		batch[i] = httpEntry{
+			Time:      e.Time.UTC().Format(time.RFC3339Nano),
+			Container: e.Container,
+			ID:        e.ID,
+			Image:     e.Image,
+			Host:      e.Host,
+			Message:   e.Line,
+		}
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	return post(ctx, h.cfg, h.cfg.URL, "application/json", body)
+}
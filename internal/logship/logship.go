@@ -0,0 +1,78 @@
+package logship
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// sendTimeout bounds a single delivery so a slow target can't stall its queue forever
+const sendTimeout = 15 * time.Second
+
+// Entry is one log line of a container
+type Entry struct {
+	Time      time.Time
+	Line      string
+	Container string
+	ID        string
+	Image     string
+	Host      string
+}
+
+// Target delivers batches of log entries to an external system
+type Target interface {
+	Name() string
+	Send(ctx context.Context, entries []Entry) error
+}
+
+// NewTarget creates the target described by cfg
+func NewTarget(cfg config.LogTarget) Target {
+	switch cfg.Type {
+	case config.TargetLoki:
+		return newLoki(cfg)
+	case config.TargetSyslog:
+		return newSyslog(cfg)
+	default:
+		return newHTTP(cfg)
+	}
+}
+
+var httpClient = &http.Client{Timeout: sendTimeout}
+
+// post sends body with the target's credentials and extra headers; non-2xx answers
+// become errors carrying the response text
+func post(ctx context.Context, cfg config.LogTarget, url, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "DockPulse")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	switch {
+	case cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+os.ExpandEnv(cfg.BearerToken))
+	case cfg.Username != "":
+		req.SetBasicAuth(os.ExpandEnv(cfg.Username), os.ExpandEnv(cfg.Password))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package logship
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"devops-dashboard/internal/config"
+)
+
+// lokiPushPath is the push API of Loki
+const lokiPushPath = "/loki/api/v1/push"
+
+// loki pushes entries to the Loki push API, one stream per container
+type loki struct {
+	cfg config.LogTarget
+	url string
+}
+
+func newLoki(cfg config.LogTarget) *loki {
+	url := strings.TrimRight(cfg.URL, "/")
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url += lokiPushPath
+	}
+	return &loki{cfg: cfg, url: url}
+}
+
+func (l *loki) Name() string {
+	return l.cfg.Name
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (l *loki) Send(ctx context.Context, entries []Entry) error {
+	var streams []*lokiStream
+	byContainer := make(map[string]*lokiStream)
+	for _, e := range entries {
+		s, ok := byContainer[e.Host+"/"+e.ID]
+		if !ok {
+			s = &lokiStream{Stream: map[string]string{"job": "dockpulse"}}
+			// Loki rejects empty label values
+			for name, value := range map[string]string{"container": e.Container, "image": e.Image, "host": e.Host} {
+				if value != "" {
+					s.Stream[name] = value
+				}
+			}
+			byContainer[e.Host+"/"+e.ID] = s
+			streams = append(streams, s)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.Time.UnixNano(), 10), e.Line})
+	}
+
+	body, err := json.Marshal(map[string][]*lokiStream{"streams": streams})
+	if err != nil {
+		return err
+	}
+	return post(ctx, l.cfg, l.url, "application/json", body)
+}
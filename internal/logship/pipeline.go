@@ -0,0 +1,194 @@
+package logship
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+const (
+	batchSize     = 500              // entries per delivery at most
+	flushInterval = 2 * time.Second  // between deliveries of a partial batch
+	queueSize     = 10000            // entries buffered per target; more are dropped
+	retryInterval = 10 * time.Second // before following the logs again after the stream ended
+)
+
+// Source is a container whose logs are shipped to the named targets
+type Source struct {
+	ID      string
+	Name    string
+	Image   string
+	Host    string
+	Targets []string
+}
+
+// Pipeline follows the logs of the sources it is given and delivers them to their
+// targets in batches. Each target has its own queue, so a target that is down only
+// delays and, once its queue is full, drops its own entries.
+type Pipeline struct {
+	ctx       context.Context
+	queues    map[string]*queue
+	report    func(target string, err error)
+	mu        sync.Mutex
+	followers map[string]*follower // by container ID
+}
+
+type queue struct {
+	target  Target
+	entries chan Entry
+}
+
+type follower struct {
+	source Source
+	stop   context.CancelFunc
+}
+
+// NewPipeline starts delivering to targets until ctx is cancelled. report is called
+// from the delivery goroutine when a target starts failing and, with a nil error, when
+// it works again.
+func NewPipeline(ctx context.Context, targets []Target, report func(target string, err error)) *Pipeline {
+	p := &Pipeline{
+		ctx:       ctx,
+		queues:    make(map[string]*queue),
+		report:    report,
+		followers: make(map[string]*follower),
+	}
+	for _, t := range targets {
+		q := &queue{target: t, entries: make(chan Entry, queueSize)}
+		p.queues[t.Name()] = q
+		go p.deliver(q)
+	}
+	return p
+}
+
+// Sync makes sources the shipped set: following starts for new containers and stops
+// for containers that disappeared or no longer match a rule
+func (p *Pipeline) Sync(sources []Source) {
+	wanted := make(map[string]Source)
+	for _, s := range sources {
+		if len(s.Targets) > 0 {
+			wanted[s.ID] = s
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, f := range p.followers {
+		if s, ok := wanted[id]; !ok || !slices.Equal(s.Targets, f.source.Targets) {
+			f.stop()
+			delete(p.followers, id)
+		}
+	}
+	for id, s := range wanted {
+		if _, ok := p.followers[id]; !ok {
+			ctx, stop := context.WithCancel(p.ctx)
+			p.followers[id] = &follower{source: s, stop: stop}
+			go p.follow(ctx, s)
+		}
+	}
+}
+
+// follow reads the logs of a source from now on, resuming after the last line whenever
+// the stream ends, e.g. because the container was stopped and started again
+func (p *Pipeline) follow(ctx context.Context, s Source) {
+	since := time.Now()
+	for {
+		logs, err := docker.StreamLogText(ctx, s.ID, since)
+		if err == nil {
+			since = p.read(logs, s, since)
+			logs.Close()
+		} else if errors.Is(err, docker.ErrNotFound) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// read queues every line of logs and returns the time of the last one
+func (p *Pipeline) read(logs io.Reader, s Source, since time.Time) time.Time {
+	reader := bufio.NewReader(logs)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			at, msg, ok := docker.SplitLogLine(line)
+			if ok {
+				since = at.Add(time.Nanosecond)
+			} else {
+				at = time.Now()
+			}
+			entry := Entry{Time: at, Line: msg, Container: s.Name, ID: s.ID, Image: s.Image, Host: s.Host}
+			for _, name := range s.Targets {
+				if q, ok := p.queues[name]; ok {
+					select {
+					case q.entries <- entry:
+					default: // the target has been failing for a while
+					}
+				}
+			}
+		}
+		if err != nil {
+			return since
+		}
+	}
+}
+
+// deliver sends the queued entries of a target in batches. A failed batch is kept and
+// retried with the next delivery while newer entries wait in the queue.
+func (p *Pipeline) deliver(q *queue) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var (
+		batch   []Entry
+		failing bool
+	)
+	send := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(p.ctx, sendTimeout)
+		err := q.target.Send(ctx, batch)
+		cancel()
+		if p.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			batch = batch[:0]
+		}
+		if (err != nil) != failing {
+			failing = err != nil
+			p.report(q.target.Name(), err)
+		}
+	}
+
+	for {
+		// While a full batch waits for a failing target, new entries stay in the queue
+		in := q.entries
+		if len(batch) >= batchSize {
+			in = nil
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return
+		case e := <-in:
+			batch = append(batch, e)
+			if len(batch) >= batchSize && !failing {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		}
+	}
+}
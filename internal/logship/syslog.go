@@ -0,0 +1,100 @@
+package logship
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	"devops-dashboard/internal/config"
+)
+
+// syslogPriority is facility user (1) with severity informational (6)
+const syslogPriority = 1*8 + 6
+
+// syslog sends entries as RFC 5424 messages over UDP, TCP or TCP with TLS. TCP uses
+// octet counting framing (RFC 6587) so multi-line messages survive.
+type syslog struct {
+	cfg     config.LogTarget
+	network string
+	addr    string
+	tls     bool
+	conn    net.Conn
+}
+
+func newSyslog(cfg config.LogTarget) *syslog {
+	scheme, addr, _ := strings.Cut(cfg.Address, "://")
+	s := &syslog{cfg: cfg, network: scheme, addr: addr}
+	if scheme == "tcp+tls" {
+		s.network, s.tls = "tcp", true
+	}
+	return s
+}
+
+func (s *syslog) Name() string {
+	return s.cfg.Name
+}
+
+// Send writes every entry; a broken connection is dropped and redialled on the next batch
+func (s *syslog) Send(ctx context.Context, entries []Entry) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		s.conn.SetWriteDeadline(deadline)
+	}
+
+	for _, e := range entries {
+		msg := formatSyslog(e)
+		if s.network == "tcp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := s.conn.Write([]byte(msg)); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslog) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if s.tls {
+		host, _, _ := net.SplitHostPort(s.addr)
+		return (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, s.network, s.addr)
+	}
+	return dialer.DialContext(ctx, s.network, s.addr)
+}
+
+// formatSyslog renders an entry as <PRI>1 TIMESTAMP HOSTNAME APP-NAME - - - MSG
+func formatSyslog(e Entry) string {
+	return fmt.Sprintf("<%d>1 %s %s %s - - - %s",
+		syslogPriority, e.Time.UTC().Format(time.RFC3339Nano),
+		syslogField(e.Host, 255), syslogField(e.Container, 48), e.Line)
+}
+
+// syslogField makes a header field valid: printable ASCII without spaces, limited in
+// length, "-" when empty
+func syslogField(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsPrint(r) || r == ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
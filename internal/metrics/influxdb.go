@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"devops-dashboard/internal/config"
+)
+
+// influxMeasurement is the measurement all container points are written to
+const influxMeasurement = "docker_container"
+
+// influxDB writes points in line protocol to the InfluxDB 2.x or 1.x write API
+type influxDB struct {
+	cfg config.InfluxDB
+	url string
+}
+
+func newInfluxDB(cfg config.InfluxDB) *influxDB {
+	base := strings.TrimRight(cfg.URL, "/")
+	query := url.Values{"precision": {"s"}}
+	endpoint := base + "/api/v2/write"
+	if cfg.Database != "" {
+		endpoint = base + "/write"
+		query.Set("db", cfg.Database)
+	} else {
+		query.Set("org", cfg.Org)
+		query.Set("bucket", cfg.Bucket)
+	}
+	return &influxDB{cfg: cfg, url: endpoint + "?" + query.Encode()}
+}
+
+func (i *influxDB) Name() string {
+	return "InfluxDB"
+}
+
+func (i *influxDB) Push(ctx context.Context, points []Point) error {
+	var b strings.Builder
+	for _, p := range points {
+		fmt.Fprintf(&b, "%s,container=%s,id=%s,image=%s,host=%s ",
+			influxMeasurement, escapeTag(p.Name), escapeTag(p.ID), escapeTag(p.Image), escapeTag(p.Host))
+		fmt.Fprintf(&b, "cpu_percent=%g,mem_percent=%g,mem_usage=%di,mem_limit=%di,"+
+			"net_rx=%di,net_tx=%di,blk_read=%di,blk_write=%di,throttled_periods=%di %d\n",
+			p.CPUPerc, p.MemPerc, p.MemUsage, p.MemLimit,
+			p.NetRx, p.NetTx, p.BlockRead, p.BlockWrite, p.Throttled, p.Time.Unix())
+	}
+
+	return post(ctx, i.url, []byte(b.String()),
+		map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		func(req *http.Request) {
+			switch {
+			case i.cfg.Token != "":
+				req.Header.Set("Authorization", "Token "+secret(i.cfg.Token))
+			case i.cfg.Username != "":
+				req.SetBasicAuth(secret(i.cfg.Username), secret(i.cfg.Password))
+			}
+		})
+}
+
+// escapeTag escapes the characters line protocol gives a meaning in tag keys and values;
+// empty values are not allowed, so they become "none"
+func escapeTag(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(s)
+}
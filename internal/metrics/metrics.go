@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// pushTimeout bounds a single push so a slow backend can't pile up requests
+const pushTimeout = 10 * time.Second
+
+// Point is the state of one container at one point in time
+type Point struct {
+	Time       time.Time
+	ID         string
+	Name       string
+	Image      string
+	Host       string
+	CPUPerc    float64
+	MemPerc    float64
+	MemUsage   uint64
+	MemLimit   uint64
+	NetRx      uint64
+	NetTx      uint64
+	BlockRead  uint64
+	BlockWrite uint64
+	Throttled  uint64 // CPU periods throttled since the container started
+}
+
+// Sink receives batches of points
+type Sink interface {
+	Name() string
+	Push(ctx context.Context, points []Point) error
+}
+
+// NewSinks creates the sinks configured in the export section
+func NewSinks(cfg *config.Export) []Sink {
+	var sinks []Sink
+	if cfg.InfluxDB != nil {
+		sinks = append(sinks, newInfluxDB(*cfg.InfluxDB))
+	}
+	if cfg.Prometheus != nil {
+		sinks = append(sinks, newRemoteWrite(*cfg.Prometheus))
+	}
+	return sinks
+}
+
+var httpClient = &http.Client{Timeout: pushTimeout}
+
+// post sends a request body and turns non-2xx answers into errors with the response text
+func post(ctx context.Context, url string, body []byte, headers map[string]string, setAuth func(*http.Request)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	setAuth(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// secret expands environment variables so credentials can stay out of the config file
+func secret(s string) string {
+	return os.ExpandEnv(s)
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/golang/snappy"
+
+	"devops-dashboard/internal/config"
+)
+
+// remoteWrite pushes points to a Prometheus remote-write endpoint as a snappy compressed
+// protobuf WriteRequest (remote write 1.0)
+type remoteWrite struct {
+	cfg config.RemoteWrite
+}
+
+func newRemoteWrite(cfg config.RemoteWrite) *remoteWrite {
+	return &remoteWrite{cfg: cfg}
+}
+
+func (r *remoteWrite) Name() string {
+	return "Prometheus"
+}
+
+func (r *remoteWrite) Push(ctx context.Context, points []Point) error {
+	var req []byte
+	for _, p := range points {
+		labels := map[string]string{
+			"container": p.Name,
+			"id":        p.ID,
+			"image":     p.Image,
+			"host":      p.Host,
+			"job":       "dockpulse",
+		}
+		ts := p.Time.UnixMilli()
+		for _, m := range []struct {
+			name  string
+			value float64
+		}{
+			{"dockpulse_container_cpu_percent", p.CPUPerc},
+			{"dockpulse_container_memory_percent", p.MemPerc},
+			{"dockpulse_container_memory_usage_bytes", float64(p.MemUsage)},
+			{"dockpulse_container_memory_limit_bytes", float64(p.MemLimit)},
+			{"dockpulse_container_network_receive_bytes_total", float64(p.NetRx)},
+			{"dockpulse_container_network_transmit_bytes_total", float64(p.NetTx)},
+			{"dockpulse_container_blkio_read_bytes_total", float64(p.BlockRead)},
+			{"dockpulse_container_blkio_write_bytes_total", float64(p.BlockWrite)},
+			{"dockpulse_container_cpu_throttled_periods_total", float64(p.Throttled)},
+		} {
+			labels["__name__"] = m.name
+			req = appendMessage(req, 1, timeSeries(labels, m.value, ts))
+		}
+	}
+
+	return post(ctx, r.cfg.URL, snappy.Encode(nil, req),
+		map[string]string{
+			"Content-Type":                      "application/x-protobuf",
+			"Content-Encoding":                  "snappy",
+			"X-Prometheus-Remote-Write-Version": "0.1.0",
+			"User-Agent":                        "DockPulse",
+		},
+		func(req *http.Request) {
+			switch {
+			case r.cfg.BearerToken != "":
+				req.Header.Set("Authorization", "Bearer "+secret(r.cfg.BearerToken))
+			case r.cfg.Username != "":
+				req.SetBasicAuth(secret(r.cfg.Username), secret(r.cfg.Password))
+			}
+		})
+}
+
+// timeSeries encodes a prometheus.TimeSeries with one sample. Labels must be sorted by name.
+func timeSeries(labels map[string]string, value float64, timestampMs int64) []byte {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ts []byte
+	for _, name := range names {
+		if labels[name] == "" {
+			continue // an empty label is the same as no label
+		}
+		var label []byte
+		label = appendBytes(label, 1, []byte(name))
+		label = appendBytes(label, 2, []byte(labels[name]))
+		ts = appendMessage(ts, 1, label)
+	}
+
+	var sample []byte
+	sample = binary.AppendUvarint(sample, 1<<3|1) // field 1, fixed64
+	sample = binary.LittleEndian.AppendUint64(sample, math.Float64bits(value))
+	sample = binary.AppendUvarint(sample, 2<<3|0) // field 2, varint
+	sample = binary.AppendUvarint(sample, uint64(timestampMs))
+	return appendMessage(ts, 2, sample)
+}
+
+// appendBytes appends a length-delimited protobuf field
+func appendBytes(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// appendMessage appends an embedded protobuf message
+func appendMessage(b []byte, field int, msg []byte) []byte {
+	return appendBytes(b, field, msg)
+}
@@ -0,0 +1,128 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compose labels that identify a service across recreations of its containers
+const (
+	composeProject = "com.docker.compose.project"
+	composeService = "com.docker.compose.service"
+)
+
+// Note is free text attached to a container
+type Note struct {
+	Text    string    `json:"text"`
+	Updated time.Time `json:"updated"`
+}
+
+// Key identifies the container a note belongs to, so the note survives the container
+// being recreated: the compose project and service when the labels name them,
+// otherwise the container name. host names the daemon, so the same service on two
+// hosts gets two notes.
+func Key(host, name string, labels map[string]string) string {
+	key := "name:" + strings.TrimPrefix(name, "/")
+	if service, ok := Service(labels); ok {
+		key = "compose:" + service
+	}
+	if host != "" {
+		key = host + "@" + key
+	}
+	return key
+}
+
+// Service returns the compose project/service the labels name, if they do
+func Service(labels map[string]string) (string, bool) {
+	project, service := labels[composeProject], labels[composeService]
+	if project == "" || service == "" {
+		return "", false
+	}
+	return project + "/" + service, true
+}
+
+// Store keeps the notes in a JSON file. Each change reads and rewrites the whole file,
+// so several DockPulse instances can share it.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultPath returns the notes file location in the user config directory, since
+// notes are written by hand and shouldn't go away with the cache
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "notes.json")
+}
+
+// Open returns the store at path; the file is created with the first note
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// All returns every note by key
+func (s *Store) All() (map[string]Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Set replaces the note under key; empty text removes it
+func (s *Store) Set(key, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notes, err := s.load()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		delete(notes, key)
+	} else {
+		notes[key] = Note{Text: text, Updated: time.Now()}
+	}
+	return s.save(notes)
+}
+
+func (s *Store) load() (map[string]Note, error) {
+	notes := make(map[string]Note)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return notes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read notes: %w", err)
+	}
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, fmt.Errorf("parse notes %s: %w", s.path, err)
+	}
+	return notes, nil
+}
+
+// save replaces the file atomically so a crash can't lose the other notes
+func (s *Store) save(notes map[string]Note) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create notes directory: %w", err)
+	}
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write notes: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write notes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// maxOutput caps how much output of a run is kept
+const maxOutput = 1 << 20
+
+// Target is the container a plugin runs for
+type Target struct {
+	ID         string
+	Name       string
+	Image      string
+	State      string
+	Host       string // configured host name, or this machine's for the default daemon
+	DockerHost string // daemon address, passed on as DOCKER_HOST
+}
+
+// Result is the outcome of one run
+type Result struct {
+	Output    string // stdout and stderr merged
+	Truncated bool   // the output was cut at maxOutput
+	ExitCode  int
+	Duration  time.Duration
+}
+
+// Run runs a plugin for a container and waits for it to exit. A non-zero exit code is
+// part of the result; errors are for programs that couldn't start or timed out.
+func Run(ctx context.Context, p *config.Plugin, t Target) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Deadline())
+	defer cancel()
+
+	args := expand(p.Command, t)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), Environment(t)...)
+	var out limitedBuffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+
+	start := time.Now()
+	err := cmd.Run()
+	r := Result{Output: out.String(), Truncated: out.truncated, Duration: time.Since(start)}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return r, fmt.Errorf("%s timed out after %s", p.Name, p.Deadline())
+	case ctx.Err() != nil:
+		return r, ctx.Err()
+	case errors.As(err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		return r, fmt.Errorf("failed to run %s: %w", p.Name, err)
+	}
+	return r, nil
+}
+
+// RunInTerminal runs a terminal plugin on this process's terminal and waits for it to
+// exit; the caller has to hand the terminal over first. Interrupts go to the plugin,
+// not to DockPulse. A non-zero exit code is returned, not an error.
+func RunInTerminal(p *config.Plugin, t Target) (int, error) {
+	args := expand(p.Command, t)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), Environment(t)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	// Ctrl-C reaches the whole foreground process group; only the plugin should stop
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode(), nil
+	case err != nil:
+		return 0, fmt.Errorf("failed to run %s: %w", p.Name, err)
+	}
+	return 0, nil
+}
+
+// expand replaces the container placeholders in a command line
+func expand(command []string, t Target) []string {
+	r := strings.NewReplacer("{id}", t.ID, "{name}", t.Name, "{image}", t.Image)
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = r.Replace(arg)
+	}
+	return args
+}
+
+// Environment describes the container to a program DockPulse runs for it
+func Environment(t Target) []string {
+	return []string{
+		"DOCKPULSE_CONTAINER_ID=" + t.ID,
+		"DOCKPULSE_CONTAINER_NAME=" + t.Name,
+		"DOCKPULSE_CONTAINER_IMAGE=" + t.Image,
+		"DOCKPULSE_CONTAINER_STATE=" + t.State,
+		"DOCKPULSE_HOST=" + t.Host,
+		"DOCKER_HOST=" + t.DockerHost,
+	}
+}
+
+// limitedBuffer keeps the first maxOutput bytes written to it and drops the rest, so a
+// chatty plugin can't fill the memory
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
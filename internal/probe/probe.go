@@ -0,0 +1,91 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	At      time.Time
+	Up      bool
+	Latency time.Duration // until the response headers or the connection; 0 when down
+	Status  int           // HTTP status; 0 for tcp probes and failed requests
+	Err     error         // why the check failed
+}
+
+// Check runs a probe once from this machine
+func Check(ctx context.Context, p *config.HealthProbe) Result {
+	ctx, cancel := context.WithTimeout(ctx, p.Deadline())
+	defer cancel()
+
+	if p.URL != "" {
+		return checkHTTP(ctx, p.URL, p.ExpectStatus)
+	}
+	return checkTCP(ctx, p.TCP)
+}
+
+// client doesn't follow redirects, so a probe can expect a 301 or 302, and accepts
+// self-signed certificates, which internal services often use
+var client = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+	Transport: &http.Transport{
+		Proxy:             http.ProxyFromEnvironment,
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	},
+}
+
+func checkHTTP(ctx context.Context, url string, expect int) Result {
+	result := Result{At: time.Now()}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req.Header.Set("User-Agent", "DockPulse-probe")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	latency := time.Since(start)
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	switch {
+	case expect != 0 && resp.StatusCode != expect:
+		result.Err = fmt.Errorf("status %d, expected %d", resp.StatusCode, expect)
+	case expect == 0 && resp.StatusCode >= 400:
+		result.Err = fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		result.Up, result.Latency = true, latency
+	}
+	return result
+}
+
+func checkTCP(ctx context.Context, addr string) Result {
+	result := Result{At: time.Now()}
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Up, result.Latency = true, time.Since(start)
+	conn.Close()
+	return result
+}
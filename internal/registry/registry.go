@@ -0,0 +1,320 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/config"
+)
+
+// requestTimeout bounds every call to the registry, token requests included
+const requestTimeout = 30 * time.Second
+
+// pageSize is how many repositories or tags are asked for at once
+const pageSize = 1000
+
+// manifestTypes are the manifests a digest is asked for, indexes first, so the digest is
+// the one docker pull records for multi-platform images
+var manifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Client reads a registry's catalog, tags and manifest digests
+type Client struct {
+	cfg  *config.Registry
+	base string
+	http *http.Client
+
+	mu     sync.Mutex
+	basic  bool              // the registry asked for basic auth
+	tokens map[string]string // scope -> bearer token
+}
+
+// New returns a client for a configured registry
+func New(cfg *config.Registry) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &Client{
+		cfg:    cfg,
+		base:   strings.TrimSuffix(cfg.URL, "/"),
+		http:   &http.Client{Timeout: requestTimeout, Transport: transport},
+		tokens: make(map[string]string),
+	}
+}
+
+// Catalog lists the repositories of the registry, sorted by name
+func (c *Client) Catalog(ctx context.Context) ([]string, error) {
+	var all []string
+	next := fmt.Sprintf("/v2/_catalog?n=%d", pageSize)
+	for next != "" {
+		var page struct {
+			Repositories []string `json:"repositories"`
+		}
+		var err error
+		if next, err = c.getJSON(ctx, next, "registry:catalog:*", &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Repositories...)
+	}
+	slices.Sort(all)
+	return all, nil
+}
+
+// Tags lists the tags of a repository
+func (c *Client) Tags(ctx context.Context, repository string) ([]string, error) {
+	var all []string
+	next := fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, pageSize)
+	for next != "" {
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		var err error
+		if next, err = c.getJSON(ctx, next, pullScope(repository), &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Tags...)
+	}
+	return all, nil
+}
+
+// Digest returns the content digest of a tag's manifest, "sha256:..."
+func (c *Client) Digest(ctx context.Context, repository, tag string) (string, error) {
+	path := fmt.Sprintf("/v2/%s/manifests/%s", repository, tag)
+	resp, err := c.do(ctx, http.MethodHead, path, pullScope(repository), manifestTypes)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if digest := resp.Header.Get("Docker-Content-Digest"); digest != "" {
+		return digest, nil
+	}
+
+	// Not every registry sends the digest; it is the hash of the manifest as served
+	resp, err = c.do(ctx, http.MethodGet, path, pullScope(repository), manifestTypes)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func pullScope(repository string) string {
+	return "repository:" + repository + ":pull"
+}
+
+// getJSON decodes the answer to a GET and returns the path of the next page, if any
+func (c *Client) getJSON(ctx context.Context, path, scope string, v any) (string, error) {
+	resp, err := c.do(ctx, http.MethodGet, path, scope, []string{"application/json"})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nextPage(resp.Header.Get("Link")), nil
+}
+
+// nextPage reads a Link header such as `</v2/_catalog?last=b&n=100>; rel="next"`
+func nextPage(link string) string {
+	target, params, ok := strings.Cut(link, ";")
+	if !ok || !strings.Contains(params, `rel="next"`) {
+		return ""
+	}
+	target = strings.Trim(strings.TrimSpace(target), "<>")
+	if u, err := url.Parse(target); err == nil && u.IsAbs() {
+		return u.RequestURI()
+	}
+	return target
+}
+
+// do sends a request, answering a 401 with the credentials or token the registry asks
+// for; answers other than 2xx become errors
+func (c *Client) do(ctx context.Context, method, path, scope string, accept []string) (*http.Response, error) {
+	resp, err := c.send(ctx, method, path, scope, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := c.authenticate(ctx, challenge, scope); err != nil {
+			return nil, err
+		}
+		if resp, err = c.send(ctx, method, path, scope, accept); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, statusError(resp)
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path, scope string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "DockPulse")
+	for _, a := range accept {
+		req.Header.Add("Accept", a)
+	}
+
+	c.mu.Lock()
+	token, basic := c.tokens[scope], c.basic
+	c.mu.Unlock()
+	switch {
+	case token != "":
+		req.Header.Set("Authorization", "Bearer "+token)
+	case basic:
+		req.SetBasicAuth(c.Credentials())
+	}
+	return c.http.Do(req)
+}
+
+// authenticate gets what a WWW-Authenticate challenge asks for: basic credentials, or a
+// bearer token for the scope from the registry's token server
+func (c *Client) authenticate(ctx context.Context, challenge, scope string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.cfg.Username == "" {
+			return fmt.Errorf("%s needs a username and password", c.cfg.Host())
+		}
+		c.mu.Lock()
+		c.basic = true
+		c.mu.Unlock()
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("%s answered 401 Unauthorized without a usable challenge (%q)", c.cfg.Host(), challenge)
+	}
+
+	attrs := challengeParams(params)
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || attrs["realm"] == "" {
+		return fmt.Errorf("%s sent a bearer challenge without a realm", c.cfg.Host())
+	}
+	query := realm.Query()
+	if service := attrs["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "DockPulse")
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.Credentials())
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("token from %s: %w", realm.Host, statusError(resp))
+	}
+
+	var answer struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return fmt.Errorf("reading the token from %s: %w", realm.Host, err)
+	}
+	token := answer.Token
+	if token == "" {
+		token = answer.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("%s sent no token", realm.Host)
+	}
+	c.mu.Lock()
+	c.tokens[scope] = token
+	c.mu.Unlock()
+	return nil
+}
+
+// challengeParams parses `realm="https://auth",service="registry",scope="..."`
+func challengeParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s != "" {
+		key, rest, ok := strings.Cut(strings.TrimLeft(s, " ,"), "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+		s = rest
+	}
+	return params
+}
+
+// Credentials returns the configured username and password with ${VARS} expanded
+func (c *Client) Credentials() (username, password string) {
+	return os.ExpandEnv(c.cfg.Username), os.ExpandEnv(c.cfg.Password)
+}
+
+// statusError turns an unsuccessful answer into an error, with the registry's message
+// when it sent one
+func statusError(resp *http.Response) error {
+	var body struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(data, &body) == nil && len(body.Errors) > 0 {
+		var msgs []string
+		for _, e := range body.Errors {
+			msgs = append(msgs, strings.ToLower(e.Message))
+		}
+		return fmt.Errorf("%s: %s", resp.Status, strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errors.New(resp.Status + ": the registry doesn't serve this (is it a v2 registry?)")
+	}
+	if msg := strings.TrimSpace(string(data)); msg != "" && len(msg) < 200 {
+		return fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+	return errors.New(resp.Status)
+}
@@ -0,0 +1,118 @@
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is a deleted container that can be restored from its rescue image
+type Entry struct {
+	Name    string          `json:"name"`
+	Host    string          `json:"host,omitempty"` // configured host name, empty for the default daemon
+	Image   string          `json:"image"`          // image the container ran
+	Rescue  string          `json:"rescue_image"`   // commit of the container taken before deletion
+	Deleted time.Time       `json:"deleted"`
+	Config  json.RawMessage `json:"config"` // docker inspect output at deletion
+}
+
+// Store keeps the entries in a JSON file. Each change reads and rewrites the whole
+// file, so several DockPulse instances can share it.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultPath returns the trash file location in the user cache directory
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "trash.json")
+}
+
+// Open returns the store at path; the file is created with the first entry
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// List returns the entries, most recently deleted first
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Deleted.After(entries[j].Deleted) })
+	return entries, nil
+}
+
+// Add records a deleted container
+func (s *Store) Add(e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	return s.save(append(entries, e))
+}
+
+// Remove forgets the entry of a rescue image
+func (s *Store) Remove(rescue string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Rescue != rescue {
+			kept = append(kept, e)
+		}
+	}
+	return s.save(kept)
+}
+
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trash: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse trash %s: %w", s.path, err)
+	}
+	return entries, nil
+}
+
+// save replaces the file atomically so a crash can't leave half an index behind
+func (s *Store) save(entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create trash directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write trash: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write trash: %w", err)
+	}
+	return nil
+}
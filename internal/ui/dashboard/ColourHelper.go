@@ -1,6 +1,15 @@
 package dashboard
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+)
 
 // Color constants for consistent UI theming
 var (
@@ -28,7 +37,7 @@ var (
 	ColorMediumPurple  = tcell.NewRGBColor(147, 112, 219)
 	ColorDarkSlateGray = tcell.NewRGBColor(47, 79, 79)
 
-	// Status colors
+	// Status colors (follow the active theme)
 	ColorSuccess = tcell.NewRGBColor(0, 255, 0)   // Green
 	ColorError   = tcell.NewRGBColor(255, 0, 0)   // Red
 	ColorWarning = tcell.NewRGBColor(255, 165, 0) // Orange
@@ -36,3 +45,235 @@ var (
 	ColorRunning = tcell.NewRGBColor(0, 255, 0)   // Green
 	ColorStopped = tcell.NewRGBColor(255, 0, 0)   // Red
 )
+
+// Theme is a named palette; every color is usable both as a tview tag and a tcell color
+type Theme struct {
+	Name string
+	config.Palette
+}
+
+var (
+	darkTheme = Theme{Name: "dark", Palette: config.Palette{
+		Background: "black",
+		Text:       "white",
+		Muted:      "gray",
+		Border:     "dodgerblue",
+		Title:      "white",
+		Accent:     "cyan",
+		Highlight:  "yellow",
+		Success:    "lime",
+		Warning:    "orange",
+		Error:      "red",
+		Info:       "dodgerblue",
+		Secondary:  "magenta",
+		Selection:  "dodgerblue",
+	}}
+
+	lightTheme = Theme{Name: "light", Palette: config.Palette{
+		Background: "#f5f5f5",
+		Text:       "#1e1e1e",
+		Muted:      "#6e6e6e",
+		Border:     "#3465a4",
+		Title:      "#1e1e1e",
+		Accent:     "#005f87",
+		Highlight:  "#af5f00",
+		Success:    "#008700",
+		Warning:    "#d75f00",
+		Error:      "#d70000",
+		Info:       "#0057ae",
+		Secondary:  "#8700af",
+		Selection:  "#87afd7",
+	}}
+
+	highContrastTheme = Theme{Name: "high-contrast", Palette: config.Palette{
+		Background: "black",
+		Text:       "white",
+		Muted:      "silver",
+		Border:     "white",
+		Title:      "yellow",
+		Accent:     "aqua",
+		Highlight:  "yellow",
+		Success:    "lime",
+		Warning:    "yellow",
+		Error:      "red",
+		Info:       "aqua",
+		Secondary:  "fuchsia",
+		Selection:  "blue",
+	}}
+)
+
+var (
+	themeMu     sync.RWMutex
+	activeTheme = darkTheme
+)
+
+// currentTheme returns the theme in use
+func currentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return activeTheme
+}
+
+// loadThemes returns the built-in presets followed by custom palettes from the config
+func loadThemes(cfg *config.Config) ([]Theme, error) {
+	themes := []Theme{darkTheme, lightTheme, highContrastTheme}
+
+	names := make([]string, 0, len(cfg.Themes))
+	for name := range cfg.Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := Theme{Name: name, Palette: mergePalette(darkTheme.Palette, cfg.Themes[name])}
+		for _, color := range paletteColors(t.Palette) {
+			if tcell.GetColor(color) == tcell.ColorDefault {
+				return nil, fmt.Errorf("theme %q: unknown color %q", name, color)
+			}
+		}
+		themes = append(themes, t)
+	}
+
+	return themes, nil
+}
+
+// stderrColorSetting is the stderr_color of the config
+var stderrColorSetting string
+
+// setStderrColor checks and installs the stderr_color of the config
+func setStderrColor(cfg *config.Config) error {
+	c := cfg.StderrColor
+	if c != "" && c != "none" && tcell.GetColor(c) == tcell.ColorDefault {
+		return fmt.Errorf("stderr_color: unknown color %q", c)
+	}
+	stderrColorSetting = c
+	return nil
+}
+
+// stderrColor returns the color for stderr lines, or "" when they aren't colored
+func stderrColor() string {
+	switch stderrColorSetting {
+	case "":
+		return currentTheme().Warning
+	case "none":
+		return ""
+	}
+	return stderrColorSetting
+}
+
+// mergePalette fills the empty entries of a custom palette from base
+func mergePalette(base, custom config.Palette) config.Palette {
+	pick := func(b, c string) string {
+		if c != "" {
+			return c
+		}
+		return b
+	}
+	return config.Palette{
+		Background: pick(base.Background, custom.Background),
+		Text:       pick(base.Text, custom.Text),
+		Muted:      pick(base.Muted, custom.Muted),
+		Border:     pick(base.Border, custom.Border),
+		Title:      pick(base.Title, custom.Title),
+		Accent:     pick(base.Accent, custom.Accent),
+		Highlight:  pick(base.Highlight, custom.Highlight),
+		Success:    pick(base.Success, custom.Success),
+		Warning:    pick(base.Warning, custom.Warning),
+		Error:      pick(base.Error, custom.Error),
+		Info:       pick(base.Info, custom.Info),
+		Secondary:  pick(base.Secondary, custom.Secondary),
+		Selection:  pick(base.Selection, custom.Selection),
+	}
+}
+
+func paletteColors(p config.Palette) []string {
+	return []string{p.Background, p.Text, p.Muted, p.Border, p.Title, p.Accent,
+		p.Highlight, p.Success, p.Warning, p.Error, p.Info, p.Secondary, p.Selection}
+}
+
+// findTheme returns the index of the named theme, or 0 (dark) if it doesn't exist
+func findTheme(themes []Theme, name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	for i, t := range themes {
+		if t.Name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown theme %q", name)
+}
+
+// applyTheme makes t the active theme for all primitives created from now on
+func applyTheme(t Theme) {
+	themeMu.Lock()
+	activeTheme = t
+	themeMu.Unlock()
+
+	tview.Styles.PrimitiveBackgroundColor = tcell.GetColor(t.Background)
+	tview.Styles.ContrastBackgroundColor = tcell.GetColor(t.Selection)
+	tview.Styles.MoreContrastBackgroundColor = tcell.GetColor(t.Muted)
+	tview.Styles.BorderColor = tcell.GetColor(t.Border)
+	tview.Styles.TitleColor = tcell.GetColor(t.Title)
+	tview.Styles.GraphicsColor = tcell.GetColor(t.Border)
+	tview.Styles.PrimaryTextColor = tcell.GetColor(t.Text)
+	tview.Styles.SecondaryTextColor = tcell.GetColor(t.Highlight)
+	tview.Styles.TertiaryTextColor = tcell.GetColor(t.Success)
+	tview.Styles.InverseTextColor = tcell.GetColor(t.Background)
+	tview.Styles.ContrastSecondaryTextColor = tcell.GetColor(t.Accent)
+
+	ColorSuccess = tcell.GetColor(t.Success)
+	ColorError = tcell.GetColor(t.Error)
+	ColorWarning = tcell.GetColor(t.Warning)
+	ColorInfo = tcell.GetColor(t.Info)
+	ColorRunning = tcell.GetColor(t.Success)
+	ColorStopped = tcell.GetColor(t.Error)
+}
+
+// cycleTheme switches to the next theme and restyles the widgets already on screen
+func (d *Dashboard) cycleTheme() {
+	d.themeIndex = (d.themeIndex + 1) % len(d.themes)
+	t := d.themes[d.themeIndex]
+	applyTheme(t)
+
+	restyle(d.mainFlex, t)
+	d.renderTabBar()
+	d.updateStatusBar()
+	d.updateList()
+	if tab := d.tabs[d.currentTab]; tab.onShow != nil {
+		tab.onShow()
+	}
+}
+
+// restyle recursively applies the theme's base colors to an existing primitive tree
+func restyle(p tview.Primitive, t Theme) {
+	bg := tcell.GetColor(t.Background)
+	text := tcell.GetColor(t.Text)
+
+	switch v := p.(type) {
+	case *tview.Flex:
+		v.SetBackgroundColor(bg)
+		for i := 0; i < v.GetItemCount(); i++ {
+			restyle(v.GetItem(i), t)
+		}
+	case *tview.Pages:
+		v.SetBackgroundColor(bg)
+		for _, name := range v.GetPageNames(false) {
+			restyle(v.GetPage(name), t)
+		}
+	case *tview.List:
+		v.SetBackgroundColor(bg)
+		v.SetMainTextColor(text).
+			SetSecondaryTextColor(tcell.GetColor(t.Muted)).
+			SetSelectedBackgroundColor(tcell.GetColor(t.Selection)).
+			SetSelectedTextColor(bg)
+		v.SetTitleColor(tcell.GetColor(t.Title))
+	case *tview.TextView:
+		v.SetBackgroundColor(bg)
+		v.SetTextColor(text)
+		v.SetTitleColor(tcell.GetColor(t.Title))
+	case *tview.Table:
+		v.SetBackgroundColor(bg)
+		v.SetTitleColor(tcell.GetColor(t.Title))
+	}
+}
@@ -1,10 +1,15 @@
 package dashboard
 
 import (
+	"cmp"
+	"context"
 	"fmt"
-	"io"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -19,16 +24,21 @@ type LogFilter struct {
 	caseSensitive bool
 	useRegex      bool
 	highlightOnly bool
+	fields        fieldFilter // conditions on the fields of JSON lines
+	compactJSON   bool        // show JSON lines as level, message and key=value
 }
 
 func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containerID string, containers []docker.ContainerInfo) {
 	containerName := containerID[:12]
+	image := ""
 	for _, c := range containers {
 		if c.ID == containerID {
 			containerName = c.Name
+			image = c.Image
 			break
 		}
 	}
+	levels := levelMatcherFor(image)
 
 	filter := &LogFilter{
 		searchTerm:    "",
@@ -36,16 +46,17 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		caseSensitive: false,
 		useRegex:      false,
 		highlightOnly: false,
+		compactJSON:   true,
 	}
 
 	logView := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true).
 		SetWrap(true).
-		SetChangedFunc(func() { app.Draw() })
+		SetRegions(true)
 
 	logView.SetBorder(true).
-		SetTitle(fmt.Sprintf(" 📜 Advanced Logs: %s ", containerName)).
+		SetTitle(logTitle("Advanced Logs", containerName, docker.LogSource{})).
 		SetBorderPadding(1, 1, 2, 2).
 		SetBorderColor(tcell.ColorTeal)
 
@@ -58,6 +69,15 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		SetBorderColor(tcell.ColorDodgerBlue).
 		SetBorderPadding(0, 0, 1, 1)
 
+	fieldInput := tview.NewInputField().
+		SetLabel("🧩 Fields: ").
+		SetPlaceholder("level=error request_id=abc status>=500 msg~timeout sort:-duration").
+		SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+
+	fieldInput.SetBorder(true).
+		SetBorderColor(tcell.ColorDodgerBlue).
+		SetBorderPadding(0, 0, 1, 1)
+
 	filterStatus := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
@@ -67,11 +87,13 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 			"[black:cyan] Level: %s [-:-:-] "+
 				"[black:yellow] Case: %s [-:-:-] "+
 				"[black:magenta] Regex: %s [-:-:-] "+
-				"[black:lime] Filter: %s [-:-:-]",
+				"[black:lime] Filter: %s [-:-:-] "+
+				"[black:aqua] JSON: %s [-:-:-]",
 			filter.logLevel,
 			map[bool]string{true: "ON", false: "OFF"}[filter.caseSensitive],
 			map[bool]string{true: "ON", false: "OFF"}[filter.useRegex],
-			map[bool]string{true: "ON", false: "OFF"}[filter.highlightOnly])
+			map[bool]string{true: "ON", false: "OFF"}[filter.highlightOnly],
+			map[bool]string{true: "COMPACT", false: "RAW"}[filter.compactJSON])
 		filterStatus.SetText(status)
 	}
 	updateFilterStatus()
@@ -80,13 +102,18 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 	controlPanel.SetText(
-		"[white][[lime]Enter[white]] Search   " +
-			"[white][[cyan]F2[white]] Level   " +
-			"[white][[yellow]F3[white]] Case   " +
-			"[white][[magenta]F4[white]] Regex   " +
-			"[white][[blue]F5[white]] Filter   " +
-			"[white][[orange]F6[white]] Export   " +
-			"[white][[yellow]Backspace/ESC[white]] Back")
+		"[-][[lime]Enter[-]] Search   " +
+			"[-][[cyan]F2[-]] Level   " +
+			"[-][[yellow]F3[-]] Case   " +
+			"[-][[magenta]F4[-]] Regex   " +
+			"[-][[blue]F5[-]] Filter   " +
+			"[-][[orange]F6[-]] Export   " +
+			"[-][[aqua]F7[-]] JSON   " +
+			"[-][[aqua]f[-]] Fields   " +
+			"[-][[aqua]↑/↓ Enter[-]] Expand line   " +
+			"[-][[aqua]m/M[-]] Bookmark/List   " +
+			"[-][[aqua]t[-]] Jump to time   " +
+			"[-][[yellow]Backspace/ESC[-]] Back")
 
 	statsPanel := tview.NewTextView().
 		SetDynamicColors(true)
@@ -95,9 +122,13 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		SetBorderColor(tcell.ColorLime).
 		SetBorderPadding(0, 0, 1, 1)
 
+	inputs := tview.NewFlex().
+		AddItem(searchInput, 0, 1, false).
+		AddItem(fieldInput, 0, 1, false)
+
 	topPanel := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(searchInput, 3, 0, false).
+		AddItem(inputs, 3, 0, false).
 		AddItem(filterStatus, 1, 0, false)
 
 	rightPanel := tview.NewFlex().
@@ -114,147 +145,404 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		AddItem(mainPanel, 0, 1, true).
 		AddItem(controlPanel, 1, 0, false)
 
-	var rawLogs string
-	var filteredLines []string
-	var totalLines, matchedLines, errorCount, warnCount int
+	// Lines are parsed once into a bounded buffer. New lines are filtered as they arrive
+	// and appended to the view; only a filter change or sorting redraws everything.
+	buffer := newLogBuffer(advancedLogLines)
+	var shown []*logLine // the lines in the view, in view order, at most advancedLogLines
+	var matchedLines, errorCount, warnCount, jsonCount int
+	var selected *logLine
+	var marks []*logLine // bookmarked lines still in the buffer, in stream order
+	logView.SetMaxLines(advancedLogLines)
 
 	updateStats := func() {
 		statsText := fmt.Sprintf(
-			"[::b][cyan]Total Lines:[-:-:-]\n[white]%d[-]\n\n"+
-				"[::b][yellow]Matched:[-:-:-]\n[white]%d[-]\n\n"+
-				"[::b][red]Errors:[-:-:-]\n[white]%d[-]\n\n"+
-				"[::b][orange]Warnings:[-:-:-]\n[white]%d[-]\n\n"+
+			"[::b][cyan]Total Lines:[-:-:-]\n[-]%d[-] [gray]of %d kept[-]\n\n"+
+				"[::b][yellow]Matched:[-:-:-]\n[-]%d[-]\n\n"+
+				"[::b][red]Errors:[-:-:-]\n[-]%d[-]\n\n"+
+				"[::b][orange]Warnings:[-:-:-]\n[-]%d[-]\n\n"+
+				"[::b][aqua]JSON:[-:-:-]\n[-]%d[-]\n\n"+
 				"[gray]Updated:\n%s[-]",
-			totalLines, matchedLines, errorCount, warnCount,
+			buffer.len(), advancedLogLines, matchedLines, errorCount, warnCount, jsonCount,
 			time.Now().Format("15:04:05"))
 		statsPanel.SetText(statsText)
 	}
 
-	applyFilter := func() {
-		if rawLogs == "" {
+	// The search is compiled once per filter change rather than once per line
+	var matchSearch func(string) bool
+	var highlight func(string) string
+	compileSearch := func() {
+		matchSearch, highlight = nil, func(s string) string { return s }
+		if filter.searchTerm == "" {
+			return
+		}
+		term := filter.searchTerm
+		if filter.useRegex {
+			if !filter.caseSensitive {
+				term = "(?i)" + term
+			}
+			re, err := regexp.Compile(term)
+			matchSearch = func(line string) bool { return err == nil && re.MatchString(line) }
+			return
+		}
+
+		quoted := regexp.QuoteMeta(term)
+		if !filter.caseSensitive {
+			quoted = "(?i)" + quoted
+		}
+		re := regexp.MustCompile(quoted)
+		matchSearch = re.MatchString
+		highlight = func(s string) string {
+			return re.ReplaceAllStringFunc(s, func(match string) string {
+				return fmt.Sprintf("[black:yellow]%s[-:-:-]", match)
+			})
+		}
+	}
+	compileSearch()
+
+	// render filters one line; ok is false when the line is hidden
+	render := func(l *logLine) (string, bool) {
+		l.matched = false
+		if filter.logLevel != "ALL" && l.level != filter.logLevel {
+			return "", false
+		}
+		if !filter.fields.matches(l.json) {
+			return "", false
+		}
+
+		shownText := l.raw
+		if l.json != nil && filter.compactJSON {
+			shownText = l.json.compact()
+		}
+
+		if matchSearch != nil {
+			if !matchSearch(l.raw) {
+				if filter.highlightOnly {
+					return "", false
+				}
+			} else {
+				l.matched = true
+				shownText = highlight(shownText)
+			}
+		} else {
+			l.matched = true
+		}
+
+		switch l.level {
+		case "ERROR":
+			shownText = "[red]" + shownText + "[-]"
+		case "WARN":
+			shownText = "[orange]" + shownText + "[-]"
+		case "INFO":
+			shownText = "[cyan]" + shownText + "[-]"
+		case "DEBUG":
+			shownText = "[gray]" + shownText + "[-]"
+		default:
+			if color := stderrColor(); l.stderr && color != "" {
+				shownText = fmt.Sprintf("[%s]%s[-]", color, shownText)
+			}
+		}
+		if slices.Contains(marks, l) {
+			shownText = bookmarkMarker + shownText
+		}
+		// Every line is its own region so it can be selected and expanded
+		return fmt.Sprintf("[\"%d\"]%s[\"\"]\n", l.seq, shownText), true
+	}
+
+	// highlightSelected marks the selected line in the view, if it is still shown
+	highlightSelected := func() {
+		if selected == nil {
+			logView.Highlight()
 			return
 		}
+		logView.Highlight(strconv.FormatUint(selected.seq, 10))
+		logView.ScrollToHighlight()
+	}
 
-		lines := strings.Split(rawLogs, "\n")
-		totalLines = len(lines)
-		filteredLines = []string{}
+	// applyFilter redraws the view from the whole buffer
+	applyFilter := func() {
+		compileSearch()
+		type rendered struct {
+			line *logLine
+			text string
+		}
+		var lines []rendered
 		matchedLines = 0
-		errorCount = 0
-		warnCount = 0
+		buffer.each(func(l *logLine) {
+			text, ok := render(l)
+			if l.matched {
+				matchedLines++
+			}
+			if ok {
+				lines = append(lines, rendered{l, text})
+			}
+		})
 
-		for _, line := range lines {
-			lowerLine := strings.ToLower(line)
-			if strings.Contains(lowerLine, "error") || strings.Contains(lowerLine, "err") {
-				errorCount++
+		if filter.fields.sortField != "" {
+			sort.SliceStable(lines, func(a, b int) bool {
+				return filter.fields.less(lines[a].line.json, lines[b].line.json)
+			})
+		}
+
+		var b strings.Builder
+		shown = shown[:0]
+		for _, r := range lines {
+			b.WriteString(r.text)
+			shown = append(shown, r.line)
+		}
+		logView.SetText(b.String())
+		if selected != nil && !slices.Contains(shown, selected) {
+			selected = nil
+		}
+		highlightSelected()
+		updateStats()
+	}
+
+	// addLines parses new lines into the buffer and appends the visible ones to the view
+	addLines := func(raw []docker.LogLine) {
+		var b strings.Builder
+		for _, line := range raw {
+			text := line.Text
+			l := &logLine{raw: text, stderr: line.Stream == docker.Stderr}
+			if j, ok := parseJSONLine(text); ok {
+				l.json = j
+				// A level field is more reliable than words in the line
+				l.level = j.level()
 			}
-			if strings.Contains(lowerLine, "warn") || strings.Contains(lowerLine, "warning") {
-				warnCount++
+			if l.level == "" {
+				l.level = levels.level(text)
 			}
 
-			if filter.logLevel != "ALL" {
-				levelMatch := false
-				switch filter.logLevel {
+			if old := buffer.add(l); old != nil {
+				// The oldest line goes first, so a bookmark on it is the first one
+				if len(marks) > 0 && marks[0] == old {
+					marks = marks[1:]
+				}
+				if old.json != nil {
+					jsonCount--
+				}
+				if old.matched {
+					matchedLines--
+				}
+				switch old.level {
 				case "ERROR":
-					levelMatch = strings.Contains(lowerLine, "error") || strings.Contains(lowerLine, "err")
+					errorCount--
 				case "WARN":
-					levelMatch = strings.Contains(lowerLine, "warn") || strings.Contains(lowerLine, "warning")
-				case "INFO":
-					levelMatch = strings.Contains(lowerLine, "info")
-				case "DEBUG":
-					levelMatch = strings.Contains(lowerLine, "debug")
-				}
-				if !levelMatch {
-					continue
+					warnCount--
 				}
 			}
+			if l.json != nil {
+				jsonCount++
+			}
+			switch l.level {
+			case "ERROR":
+				errorCount++
+			case "WARN":
+				warnCount++
+			}
 
-			if filter.searchTerm != "" {
-				matched := false
-				searchLine := line
-				searchTerm := filter.searchTerm
+			if text, ok := render(l); ok {
+				b.WriteString(text)
+				shown = append(shown, l)
+			}
+			if l.matched {
+				matchedLines++
+			}
+		}
 
-				if !filter.caseSensitive {
-					searchLine = strings.ToLower(searchLine)
-					searchTerm = strings.ToLower(searchTerm)
-				}
+		if filter.fields.sortField != "" {
+			// New lines can land anywhere in a sorted view
+			applyFilter()
+			return
+		}
+		if len(shown) > advancedLogLines {
+			shown = append(shown[:0], shown[len(shown)-advancedLogLines:]...)
+		}
+		if b.Len() > 0 {
+			fmt.Fprint(logView, b.String())
+		}
+		if selected != nil {
+			highlightSelected()
+		}
+		updateStats()
+	}
 
-				if filter.useRegex {
-					re, err := regexp.Compile(searchTerm)
-					if err == nil {
-						matched = re.MatchString(searchLine)
-					}
-				} else {
-					matched = strings.Contains(searchLine, searchTerm)
-				}
+	// selectLine moves the line cursor to shown[i]; an index out of range clears it
+	selectLine := func(i int) {
+		if i < 0 || i >= len(shown) {
+			selected = nil
+		} else {
+			selected = shown[i]
+		}
+		highlightSelected()
+	}
 
-				if !matched && filter.highlightOnly {
-					continue
-				}
+	// selectedIndex returns the position of the selected line in the view, or -1
+	selectedIndex := func() int {
+		if selected == nil {
+			return -1
+		}
+		return slices.Index(shown, selected)
+	}
 
-				if matched {
-					matchedLines++
-					if !filter.useRegex {
-						highlightTerm := filter.searchTerm
-						if !filter.caseSensitive {
-							re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(highlightTerm))
-							line = re.ReplaceAllStringFunc(line, func(match string) string {
-								return fmt.Sprintf("[black:yellow]%s[-:-:-]", match)
-							})
-						} else {
-							line = strings.ReplaceAll(line, highlightTerm,
-								fmt.Sprintf("[black:yellow]%s[-:-:-]", highlightTerm))
-						}
-					}
-				}
-			} else {
-				matchedLines++
+	// expandLine shows the selected line with its JSON pretty-printed
+	expandLine := func() {
+		if selected == nil {
+			return
+		}
+		text := fmt.Sprintf("[gray]%s[-]", tview.Escape(selected.raw))
+		if j := selected.json; j != nil {
+			text = j.pretty()
+			if prefix := strings.TrimSpace(j.prefix); prefix != "" {
+				text = fmt.Sprintf("[gray]%s[-]\n\n%s", tview.Escape(prefix), text)
 			}
+		}
 
-			if strings.Contains(lowerLine, "error") || strings.Contains(lowerLine, "err") {
-				line = "[red]" + line + "[-]"
-			} else if strings.Contains(lowerLine, "warn") {
-				line = "[orange]" + line + "[-]"
-			} else if strings.Contains(lowerLine, "info") {
-				line = "[cyan]" + line + "[-]"
-			} else if strings.Contains(lowerLine, "debug") {
-				line = "[gray]" + line + "[-]"
+		detail := tview.NewTextView().
+			SetDynamicColors(true).
+			SetScrollable(true).
+			SetWrap(true).
+			SetText(text)
+		detail.SetBorder(true).
+			SetTitle(" 🔎 Log Line (ESC to close) ").
+			SetBorderPadding(1, 1, 2, 2).
+			SetBorderColor(tcell.ColorAqua)
+		detail.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter || event.Rune() == 'q' {
+				setRoot(app, flex)
+				app.SetFocus(logView)
+				return nil
 			}
+			return event
+		})
+		showOverlay(app, flex, detail, 100, 30)
+	}
+
+	// bookmarkOrder sorts bookmarks in stream order
+	bookmarkOrder := func(a, b *logLine) int {
+		return cmp.Compare(a.seq, b.seq)
+	}
+
+	// toggleMark bookmarks the selected line, or the last one shown
+	toggleMark := func() {
+		l := selected
+		if l == nil && len(shown) > 0 {
+			l = shown[len(shown)-1]
+		}
+		if l == nil {
+			return
+		}
+		pos, marked := slices.BinarySearchFunc(marks, l, bookmarkOrder)
+		if marked {
+			marks = slices.Delete(marks, pos, pos+1)
+		} else {
+			marks = slices.Insert(marks, pos, l)
+		}
+		applyFilter()
+		if marked {
+			filterStatus.SetText(fmt.Sprintf("[black:aqua] Bookmark removed, %d left [-:-:-]", len(marks)))
+		} else {
+			filterStatus.SetText("[black:aqua] 🔖 Bookmarked (M to list) [-:-:-]")
+		}
+	}
 
-			filteredLines = append(filteredLines, line)
+	restore := func() {
+		setRoot(app, flex)
+		app.SetFocus(logView)
+	}
+
+	listMarks := func() {
+		if len(marks) == 0 {
+			filterStatus.SetText("[black:yellow] No bookmarks yet, m marks the selected line [-:-:-]")
+			return
+		}
+		labels := make([]string, len(marks))
+		for i, l := range marks {
+			labels[i] = bookmarkLabel(l.raw)
 		}
+		showBookmarks(app, flex, labels, func(i int) {
+			i = slices.Index(shown, marks[i])
+			if i < 0 {
+				filterStatus.SetText("[black:yellow] The bookmarked line is hidden by the filter [-:-:-]")
+				return
+			}
+			selectLine(i)
+		}, func(i int) {
+			marks = slices.Delete(marks, i, i+1)
+			applyFilter()
+		}, restore)
+	}
 
-		logView.SetText(strings.Join(filteredLines, "\n"))
-		updateStats()
+	jumpTime := func() {
+		promptJumpTime(app, flex, func(at time.Time) {
+			i := firstLineAt(len(shown), func(i int) string { return shown[i].raw }, at)
+			if i < 0 {
+				filterStatus.SetText(fmt.Sprintf("[black:yellow] No shown lines at or after %s [-:-:-]", at.Format("2006-01-02 15:04:05")))
+				return
+			}
+			selectLine(i)
+		}, restore)
+	}
+
+	// Streaming stops when the view is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	back := func() {
+		cancel()
+		setRoot(app, mainView)
+	}
+
+	// Lines are handed to the UI in batches: while a batch waits to be drawn, new lines
+	// join it instead of queueing another redraw
+	var (
+		pendingMu sync.Mutex
+		pending   []docker.LogLine
+	)
+	flush := func() {
+		pendingMu.Lock()
+		lines := pending
+		pending = nil
+		pendingMu.Unlock()
+		addLines(lines)
 	}
 
 	go func() {
-		reader, err := docker.StreamLogs(containerID)
-		if err != nil {
+		src, srcErr := docker.ContainerLogSource(containerID)
+		if srcErr == nil {
 			app.QueueUpdateDraw(func() {
-				logView.SetText(fmt.Sprintf("[red]Failed to load logs:\n%s[-]", err.Error()))
+				logView.SetTitle(logTitle("Advanced Logs", containerName, src))
+				if !src.Readable {
+					logView.SetText(logSourceNote(src))
+				}
 			})
-			return
+			if !src.Readable {
+				return
+			}
 		}
-		defer reader.Close()
 
-		buf := make([]byte, 4096)
-		for {
-			n, err := reader.Read(buf)
-			if n > 0 {
-				rawLogs += string(buf[:n])
-				app.QueueUpdateDraw(func() {
-					applyFilter()
-				})
+		err := followLogs(ctx, containerID, func(line docker.LogLine) {
+			pendingMu.Lock()
+			pending = append(pending, line)
+			first := len(pending) == 1
+			pendingMu.Unlock()
+			if first {
+				app.QueueUpdateDraw(flush)
 			}
-			if err != nil {
-				if err != io.EOF {
-					app.QueueUpdateDraw(func() {
-						logView.SetText(logView.GetText(false) +
-							fmt.Sprintf("\n[red]Error reading logs: %s[-]", err.Error()))
-					})
-				}
-				break
+		}, func(status string) {
+			title := logTitle("Advanced Logs", containerName, src)
+			if status == logOffline {
+				title += "[red](Docker offline, resuming when back)[-] "
 			}
+			app.QueueUpdateDraw(func() {
+				logView.SetTitle(title)
+			})
+		})
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				text := fmt.Sprintf("[red]Failed to load logs:\n%s[-]", errorText(err))
+				if src.Hint != "" {
+					text += "\n\n" + logSourceNote(src)
+				}
+				logView.SetText(text)
+			})
 		}
 	}()
 
@@ -266,10 +554,53 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		}
 	})
 
+	fieldInput.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			fields, err := parseFieldFilter(fieldInput.GetText())
+			if err != nil {
+				fieldInput.SetLabelColor(tcell.ColorRed)
+				filterStatus.SetText(fmt.Sprintf("[red]%s[-]", tview.Escape(err.Error())))
+				return
+			}
+			fieldInput.SetLabelColor(tview.Styles.SecondaryTextColor)
+			filter.fields = fields
+			updateFilterStatus()
+			applyFilter()
+			app.SetFocus(logView)
+		case tcell.KeyEscape:
+			app.SetFocus(logView)
+		}
+	})
+
 	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
-			app.SetRoot(mainView, true)
+			if selected != nil {
+				selectLine(-1)
+				return nil
+			}
+			back()
+			return nil
+		case tcell.KeyUp:
+			if i := selectedIndex(); i < 0 {
+				selectLine(len(shown) - 1)
+			} else if i > 0 {
+				selectLine(i - 1)
+			}
+			return nil
+		case tcell.KeyDown:
+			if i := selectedIndex(); i >= 0 {
+				selectLine(min(i+1, len(shown)-1))
+			}
+			return nil
+		case tcell.KeyEnter:
+			expandLine()
+			return nil
+		case tcell.KeyF7:
+			filter.compactJSON = !filter.compactJSON
+			updateFilterStatus()
+			applyFilter()
 			return nil
 		case tcell.KeyF2:
 			levels := []string{"ALL", "ERROR", "WARN", "INFO", "DEBUG"}
@@ -298,9 +629,8 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 			applyFilter()
 			return nil
 		case tcell.KeyF6:
-			showMessage(app, mainView, "📋 Export Logs",
-				fmt.Sprintf("Logs exported to: ./logs/%s_%s.log\n\nTotal lines: %d\nMatched lines: %d",
-					containerName, time.Now().Format("20060102_150405"), totalLines, matchedLines))
+			showToast(app, toastSuccess, fmt.Sprintf("Logs exported to ./logs/%s_%s.log (%d lines, %d matched)",
+				containerName, time.Now().Format("20060102_150405"), buffer.len(), matchedLines))
 			return nil
 		}
 
@@ -311,17 +641,31 @@ func ShowAdvancedLogs(app *tview.Application, mainView tview.Primitive, containe
 		case 'c', 'C':
 			filter.searchTerm = ""
 			searchInput.SetText("")
+			filter.fields = fieldFilter{}
+			fieldInput.SetText("")
 			applyFilter()
 			return nil
+		case 'f', 'F':
+			app.SetFocus(fieldInput)
+			return nil
+		case 'm':
+			toggleMark()
+			return nil
+		case 'M':
+			listMarks()
+			return nil
+		case 't', 'T':
+			jumpTime()
+			return nil
 		case 'q', 'Q':
-			app.SetRoot(mainView, true)
+			back()
 			return nil
 		}
 
 		return event
 	})
 
-	app.SetRoot(flex, true)
+	setRoot(app, flex)
 	app.SetFocus(logView)
 }
 
@@ -330,12 +674,12 @@ func showMessage(app *tview.Application, mainView tview.Primitive, title, messag
 		SetText(message).
 		AddButtons([]string{"OK"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 		})
 	modal.SetTitle(" " + title + " ").
 		SetBorder(true).
 		SetBorderColor(tcell.ColorDodgerBlue)
-	app.SetRoot(modal, true)
+	setRoot(app, modal)
 }
 
 func showConfirmation(app *tview.Application, mainView tview.Primitive, message string, onConfirm func()) {
@@ -346,10 +690,10 @@ func showConfirmation(app *tview.Application, mainView tview.Primitive, message
 			if buttonLabel == "Yes" {
 				onConfirm()
 			}
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 		})
 	modal.SetTitle(" ⚠️ Confirm ").
 		SetBorder(true).
 		SetBorderColor(tcell.ColorOrange)
-	app.SetRoot(modal, true)
+	setRoot(app, modal)
 }
@@ -0,0 +1,58 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/history"
+)
+
+// alertLogSize is how many alerts are kept for the HTML report
+const alertLogSize = 200
+
+// alert is a warning or recovery written to the Events tab
+type alert struct {
+	at        time.Time
+	severity  string // history.SeverityCritical, SeverityWarning or SeverityInfo
+	kind      string // e.g. "crash loop" or "probe down"
+	container string
+	detail    string
+}
+
+// alertColor returns the theme color alerts of a severity are written in
+func alertColor(t Theme, severity string) string {
+	switch severity {
+	case history.SeverityCritical:
+		return t.Error
+	case history.SeverityWarning:
+		return t.Warning
+	case history.SeverityInfo:
+		return t.Success
+	}
+	return t.Muted
+}
+
+// logAlert writes an alert to the Events tab, keeps it for the HTML report and records
+// it in the history for the digest; it must be called on the UI goroutine. Alerts about
+// the dashboard itself have no container.
+func (d *Dashboard) logAlert(at time.Time, severity, kind, container, detail string) {
+	t := currentTheme()
+	subject := ""
+	if container != "" {
+		subject = " " + tview.Escape(container) + ":"
+	}
+	fmt.Fprintf(d.eventsView, "[%s]%s[-] [%s::b]%s[-:-:-]%s %s\n",
+		t.Muted, at.Format("15:04:05"), alertColor(t, severity), kind, subject, tview.Escape(detail))
+	d.eventsView.ScrollToEnd()
+
+	if d.history != nil {
+		go d.history.RecordAlert(history.Alert{Time: at, Severity: severity, Kind: kind, Container: container, Detail: detail})
+	}
+
+	d.alerts = append(d.alerts, alert{at, severity, kind, container, detail})
+	if len(d.alerts) > alertLogSize {
+		d.alerts = d.alerts[len(d.alerts)-alertLogSize:]
+	}
+}
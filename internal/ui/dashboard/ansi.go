@@ -0,0 +1,36 @@
+package dashboard
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ansiSequence matches the escape sequences terminal programs write: CSI sequences such
+// as colors and cursor movement, OSC strings such as window titles, and two byte escapes
+var ansiSequence = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// renderANSI turns command output into text for a view with dynamic colors: SGR colors
+// become color tags, other escape sequences are dropped and the rest is escaped so
+// brackets in the output aren't taken for tags. Without colors, SGR is dropped too.
+func renderANSI(output string, colors bool) string {
+	output = strings.ReplaceAll(output, "\r", "")
+
+	var b strings.Builder
+	last := 0
+	for _, m := range ansiSequence.FindAllStringIndex(output, -1) {
+		b.WriteString(tview.Escape(output[last:m[0]]))
+		if seq := output[m[0]:m[1]]; colors && strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+			b.WriteString(seq)
+		}
+		last = m[1]
+	}
+	b.WriteString(tview.Escape(output[last:]))
+
+	if !colors {
+		return b.String()
+	}
+	// Colors left on at the end of the output must not leak into what follows
+	return tview.TranslateANSI(b.String()) + "[-:-:-]"
+}
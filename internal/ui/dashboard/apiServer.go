@@ -0,0 +1,42 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/api"
+	"devops-dashboard/internal/docker"
+)
+
+// startAPI serves the stats and events the dashboard collects to gRPC clients while it
+// runs
+func (d *Dashboard) startAPI() {
+	if d.cfg.API == nil {
+		return
+	}
+	srv, err := api.New(d.cfg.API, d.metricPoints)
+	if err != nil {
+		d.flashStatus(fmt.Sprintf("[%s]gRPC API not started: %s[-]", currentTheme().Error, tview.Escape(errorSummary(err))))
+		return
+	}
+	d.api = srv
+
+	go func() {
+		if err := srv.Serve(d.refreshCtx); err != nil {
+			d.app.QueueUpdateDraw(func() {
+				d.flashStatus(fmt.Sprintf("[%s]gRPC API stopped: %s[-]", currentTheme().Error, tview.Escape(errorSummary(err))))
+			})
+		}
+	}()
+}
+
+// publishEvent passes a daemon event on to API subscribers, named by the same host
+// label as the stats
+func (d *Dashboard) publishEvent(event docker.EventInfo) {
+	if d.api == nil {
+		return
+	}
+	event.Host = hostLabel(docker.ContainerInfo{Host: event.Host})
+	d.api.Publish(event)
+}
@@ -0,0 +1,47 @@
+package dashboard
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"devops-dashboard/internal/docker"
+)
+
+// attachContainer suspends the dashboard and attaches the terminal to the main process
+// of a container, for REPLs and other programs that talk on PID 1's stdin and stdout.
+// Detaching leaves the container running; when it exits instead, the last output stays
+// on screen until Enter.
+func (d *Dashboard) attachContainer(container docker.ContainerInfo) {
+	if container.State != "running" {
+		d.flashStatus(fmt.Sprintf("[%s]%s is not running[-]", currentTheme().Warning, container.Name))
+		return
+	}
+	keys := d.cfg.DetachKeys
+	if keys == "" {
+		keys = docker.DefaultDetachKeys
+	}
+
+	var (
+		result *docker.AttachResult
+		err    error
+	)
+	d.app.Suspend(func() {
+		fmt.Printf("Attached to the main process of %s. Detach with %s; it keeps running.\n", container.Name, keys)
+		fmt.Printf("Programs waiting for input show nothing until they get some, so try Enter.\n\n")
+		result, err = docker.Attach(container.ID, keys)
+		if err == nil && !result.Detached {
+			fmt.Printf("\n%s exited with code %d. Press Enter to return to DockPulse.", container.Name, result.ExitCode)
+			bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+
+	switch {
+	case err != nil:
+		showError(d.app, d.mainFlex, "📎 Attach", err)
+	case result.Detached:
+		showToast(d.app, toastInfo, fmt.Sprintf("Detached from %s, which keeps running", container.Name))
+	default:
+		showToast(d.app, toastWarning, fmt.Sprintf("%s exited with code %d", container.Name, result.ExitCode))
+	}
+}
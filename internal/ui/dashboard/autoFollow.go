@@ -0,0 +1,274 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// FollowRules are the auto-follow rules in effect and the containers they caught
+type FollowRules struct {
+	mu      sync.Mutex
+	rules   []config.AutoFollow
+	session *config.AutoFollow // typed in with the auto-follow prompt, nil when there is none
+	paused  bool
+	pending map[string]bool // created containers whose logs open when they first start
+	pinned  map[string]bool // containers kept at the top of the list
+}
+
+// NewFollowRules returns the rules from the config
+func NewFollowRules(rules []config.AutoFollow) *FollowRules {
+	return &FollowRules{rules: rules, pending: make(map[string]bool), pinned: make(map[string]bool)}
+}
+
+// Record notes a container event and returns what to do about it now: FollowLogs when a
+// followed container starts for the first time, FollowPin when a pinned one is created,
+// "" otherwise
+func (f *FollowRules) Record(event docker.EventInfo) string {
+	if event.Type != "container" {
+		return ""
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch event.Action {
+	case "create":
+		if f.paused {
+			return ""
+		}
+		rule := f.match(event.Name, event.Attributes)
+		switch {
+		case rule == nil:
+		case rule.Do() == config.FollowPin:
+			f.pinned[event.ActorID] = true
+			return config.FollowPin
+		default:
+			// A created container has no logs yet, so they open on its start
+			f.pending[event.ActorID] = true
+		}
+	case "start":
+		if f.pending[event.ActorID] {
+			delete(f.pending, event.ActorID)
+			return config.FollowLogs
+		}
+	case "destroy":
+		delete(f.pending, event.ActorID)
+		delete(f.pinned, event.ActorID)
+	}
+	return ""
+}
+
+// match returns the first rule a container matches, the session rule first. f.mu must
+// be held.
+func (f *FollowRules) match(name string, labels map[string]string) *config.AutoFollow {
+	if f.session != nil && f.session.Matches(name, labels) {
+		return f.session
+	}
+	for i := range f.rules {
+		if f.rules[i].Matches(name, labels) {
+			return &f.rules[i]
+		}
+	}
+	return nil
+}
+
+// Pinned reports whether a container is kept at the top of the list
+func (f *FollowRules) Pinned(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pinned[id]
+}
+
+// PinFirst moves the pinned containers to the top of a sorted list, keeping the order
+// within both parts
+func (f *FollowRules) PinFirst(containers []docker.ContainerInfo, visible []int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.pinned) == 0 {
+		return
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		return f.pinned[containers[visible[i]].ID] && !f.pinned[containers[visible[j]].ID]
+	})
+}
+
+// Set replaces the session rule, nil removing it, and resumes following
+func (f *FollowRules) Set(rule *config.AutoFollow) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.session = rule
+	f.paused = false
+}
+
+// Stop pauses every rule for the rest of the session and drops the pins
+func (f *FollowRules) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.session = nil
+	f.paused = true
+	clear(f.pending)
+	clear(f.pinned)
+}
+
+// Status describes the rules in effect
+func (f *FollowRules) Status() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.paused {
+		return "Paused until a rule is set"
+	}
+	var rules []string
+	if f.session != nil {
+		rules = append(rules, describeFollowRule(*f.session)+" (this session)")
+	}
+	for _, r := range f.rules {
+		rules = append(rules, describeFollowRule(r))
+	}
+	if len(rules) == 0 {
+		return "No rules"
+	}
+	status := strings.Join(rules, "\n")
+	if len(f.pinned) > 0 {
+		status += fmt.Sprintf("\n%d containers pinned", len(f.pinned))
+	}
+	return status
+}
+
+// describeFollowRule summarises a rule, e.g. "logs of api-* with role=worker"
+func describeFollowRule(r config.AutoFollow) string {
+	var labels []string
+	for k, v := range r.Labels {
+		if v == "" {
+			labels = append(labels, k)
+		} else {
+			labels = append(labels, k+"="+v)
+		}
+	}
+	sort.Strings(labels)
+
+	what := "logs of"
+	if r.Do() == config.FollowPin {
+		what = "pin"
+	}
+	switch {
+	case r.Name == "":
+		return fmt.Sprintf("%s containers with %s", what, strings.Join(labels, ", "))
+	case len(labels) == 0:
+		return fmt.Sprintf("%s %s", what, r.Name)
+	}
+	return fmt.Sprintf("%s %s with %s", what, r.Name, strings.Join(labels, ", "))
+}
+
+// parseFollowLabels reads labels typed as "key=value, key2", where a bare key only needs
+// the label to be set
+func parseFollowLabels(text string) map[string]string {
+	labels := make(map[string]string)
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels
+}
+
+// followNew acts on a container caught by an auto-follow rule; it must be called on the
+// UI goroutine after the list was updated. Logs only open when no screen is open, so a
+// form being filled in isn't replaced.
+func (d *Dashboard) followNew(event docker.EventInfo, action string) {
+	name := event.Name
+	if d.allHostsMode() {
+		name = event.Host + "/" + name
+	}
+
+	d.mu.RLock()
+	containers := d.containers
+	row := -1
+	for i, r := range d.rows {
+		if r.containerIndex >= 0 && containers[r.containerIndex].ID == event.ActorID {
+			row = i
+		}
+	}
+	d.mu.RUnlock()
+	if row >= 0 {
+		d.list.SetCurrentItem(row)
+	}
+
+	switch {
+	case action == config.FollowPin:
+		showToast(d.app, toastInfo, fmt.Sprintf("📍 Pinned new container %s", name))
+	case !screens.active():
+		showLogs(d.app, d.mainFlex, event.ActorID, containers)
+		showToast(d.app, toastInfo, fmt.Sprintf("Following new container %s", name))
+	default:
+		showToast(d.app, toastInfo, fmt.Sprintf("New container %s started; %s opens its logs", name, d.keys.KeyLabel(actionLogs)))
+	}
+}
+
+// showAutoFollow sets a rule for this session on top of the auto_follow config, or
+// pauses following
+func (d *Dashboard) showAutoFollow() {
+	t := currentTheme()
+
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	status.SetText(fmt.Sprintf("[%s::b]Following:[-:-:-]\n[%s]%s[-]",
+		t.Accent, t.Muted, tview.Escape(d.follow.Status())))
+
+	actions := []string{config.FollowLogs, config.FollowPin}
+	form := tview.NewForm().
+		AddInputField("Name", "", 30, nil, nil).
+		AddInputField("Labels", "", 30, nil, nil).
+		AddDropDown("Action", []string{"open logs on start", "pin to the top"}, 0, nil)
+	form.GetFormItemByLabel("Name").(*tview.InputField).SetPlaceholder("glob, e.g. api-*")
+	form.GetFormItemByLabel("Labels").(*tview.InputField).SetPlaceholder("key=value, key")
+
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(d.list)
+	}
+	form.AddButton("Follow", func() {
+		choice, _ := form.GetFormItemByLabel("Action").(*tview.DropDown).GetCurrentOption()
+		rule := config.AutoFollow{
+			Name:   strings.TrimSpace(form.GetFormItemByLabel("Name").(*tview.InputField).GetText()),
+			Labels: parseFollowLabels(form.GetFormItemByLabel("Labels").(*tview.InputField).GetText()),
+			Action: actions[max(choice, 0)],
+		}
+		if err := rule.Validate(); err != nil {
+			status.SetText(fmt.Sprintf("[%s]%s[-]", t.Error, tview.Escape(err.Error())))
+			return
+		}
+		d.follow.Set(&rule)
+		back()
+		d.flashStatus(fmt.Sprintf("[%s]Auto-follow: %s[-]", t.Success, tview.Escape(describeFollowRule(rule))))
+	})
+	form.AddButton("Stop", func() {
+		d.follow.Stop()
+		back()
+		d.updateList()
+		d.flashStatus(fmt.Sprintf("[%s]Auto-follow paused for this session[-]", t.Muted))
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(status, 5, 0, false).
+		AddItem(form, 0, 1, true)
+	flex.SetBorder(true).
+		SetTitle(" 👀 Follow New Containers ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	showOverlay(d.app, d.mainFlex, flex, 70, 18)
+}
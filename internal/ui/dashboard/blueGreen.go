@@ -0,0 +1,433 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/probe"
+)
+
+// Blue/green step states
+const (
+	stepPending = iota
+	stepRunning
+	stepDone
+	stepFailed
+)
+
+// blueGreenChecks is how many probe checks in a row the green container has to pass
+const blueGreenChecks = 5
+
+// blueGreenReadyTimeout bounds the wait for the green container to run and turn healthy
+const blueGreenReadyTimeout = 2 * time.Minute
+
+// showBlueGreen guides running a second version of a container next to the current
+// one: start a green copy with the new image, check it with a probe, then stop blue
+// and hand green its name. Sharing blue's network aliases makes green a canary that
+// gets part of the traffic before the switch. r rolls back at any point.
+func (d *Dashboard) showBlueGreen(blue docker.ContainerInfo) {
+	t := currentTheme()
+
+	var (
+		steps     [3]int
+		greenID   string
+		greenName string
+		aliases   []string // blue's aliases green shares, empty without canary traffic
+		target    string   // what the check step probes
+		promoted  bool
+		busy      bool
+	)
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	stepView := tview.NewTextView().
+		SetDynamicColors(true)
+	stepView.SetBorder(true).
+		SetTitle(" Steps ").
+		SetBorderColor(tcell.GetColor(t.Border)).
+		SetBorderPadding(1, 1, 1, 1)
+
+	logView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWordWrap(true)
+	logView.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔵🟢 Blue/Green: %s ", tview.Escape(blue.Name))).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] 1 [-:-:-] Start green   [black:green] 2 [-:-:-] Check   [black:green] 3 [-:-:-] Promote   [black:yellow] r [-:-:-] Roll back   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(stepView, 36, 0, false).
+			AddItem(logView, 0, 1, true), 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	logf := func(color, format string, args ...any) {
+		fmt.Fprintf(logView, "[%s]%s[-] [%s]%s[-]\n", t.Muted, time.Now().Format("15:04:05"), color, tview.Escape(fmt.Sprintf(format, args...)))
+		logView.ScrollToEnd()
+	}
+
+	// render must be called on the UI goroutine
+	render := func() {
+		green := fmt.Sprintf("[%s]not started[-]", t.Muted)
+		if greenID != "" {
+			green = fmt.Sprintf("[%s::b]%s[-:-:-] %s", t.Success, tview.Escape(greenName), greenID[:12])
+		}
+		blueState := "running"
+		if promoted {
+			blueState = "stopped"
+		}
+		header.SetText(fmt.Sprintf(" [%s::b]Blue:[-:-:-] %s [%s](%s, %s)[-]   [%s::b]Green:[-:-:-] %s\n [%s]Traffic: %s[-]",
+			t.Info, tview.Escape(blue.Name), t.Muted, tview.Escape(blue.Image), blueState, t.Success, green,
+			t.Muted, blueGreenTraffic(greenID != "", promoted, aliases)))
+
+		names := []string{"Start green with the new image", "Check green with a probe", "Promote: stop blue, green takes its name"}
+		var b strings.Builder
+		for i, name := range names {
+			mark, color := "○", t.Muted
+			switch steps[i] {
+			case stepRunning:
+				mark, color = "⏳", t.Warning
+			case stepDone:
+				mark, color = "✓", t.Success
+			case stepFailed:
+				mark, color = "✗", t.Error
+			}
+			fmt.Fprintf(&b, "[%s]%s %d  %s[-]\n\n", color, mark, i+1, name)
+		}
+		stepView.SetText(b.String())
+	}
+
+	restore := func() {
+		setRoot(d.app, flex)
+		d.app.SetFocus(logView)
+	}
+
+	// run does one step in the background, keeping others from starting meanwhile
+	run := func(step int, work func() error) {
+		busy = true
+		steps[step] = stepRunning
+		render()
+		go func() {
+			err := work()
+			d.app.QueueUpdateDraw(func() {
+				busy = false
+				if err != nil {
+					steps[step] = stepFailed
+					logf(t.Error, "%s", errorSummary(err))
+				} else {
+					steps[step] = stepDone
+				}
+				render()
+				d.updateList()
+			})
+		}()
+	}
+
+	startGreen := func() {
+		if greenID != "" {
+			showToast(d.app, toastInfo, greenName+" is already running; roll back to start over")
+			return
+		}
+		go func() {
+			spec, err := docker.GetContainerSpec(blue.ID)
+			d.app.QueueUpdateDraw(func() {
+				if err != nil {
+					showError(d.app, flex, "Error", err)
+					return
+				}
+				// Green runs next to blue, so its ports go to random host ports unless remapped
+				for i, port := range spec.Ports {
+					parts := strings.Split(port, ":")
+					spec.Ports[i] = parts[len(parts)-1]
+				}
+				form := newSpecForm(fmt.Sprintf(" 🟢 Green copy of %s ", blue.Name), blue.Name+"-green", *spec)
+				form.AddCheckbox("Canary traffic", true, nil)
+				form.AddButton("Start", func() {
+					name := strings.TrimSpace(form.name.GetText())
+					newSpec := form.spec()
+					if name == "" || newSpec.Image == "" {
+						showMessage(d.app, form, "Blue/Green", "A name and an image are required.")
+						return
+					}
+					canary := form.GetFormItemByLabel("Canary traffic").(*tview.Checkbox).IsChecked()
+					restore()
+					logf(t.Text, "Starting %s from %s", name, newSpec.Image)
+					run(0, func() error {
+						id, err := docker.CloneContainer(blue.ID, name, newSpec)
+						if err != nil {
+							return err
+						}
+						var shared []string
+						if canary {
+							shared, err = docker.ShareAliases(blue.ID, id)
+						}
+						d.app.QueueUpdateDraw(func() {
+							greenID, greenName, aliases = id, name, shared
+							logf(t.Success, "Started %s (%s)", name, id[:12])
+							switch {
+							case err != nil:
+								logf(t.Warning, "Sharing blue's network aliases failed, green gets no traffic yet: %s", errorSummary(err))
+							case len(shared) > 0:
+								logf(t.Success, "Green answers for %s next to blue, taking part of the traffic", strings.Join(shared, ", "))
+							case canary:
+								logf(t.Warning, "Blue has no network aliases to share (default bridge or no compose service), so green gets no traffic until promoted")
+							}
+						})
+						return nil
+					})
+				})
+				form.AddButton("Cancel", restore)
+				form.SetCancelFunc(restore)
+				setRoot(d.app, form)
+			})
+		}()
+	}
+
+	// check waits for green to run and turn healthy, then probes it a few times in a row
+	check := func(probeTarget string) {
+		target = probeTarget
+		p := &config.HealthProbe{Container: greenName}
+		if strings.Contains(probeTarget, "://") {
+			p.URL = probeTarget
+		} else {
+			p.TCP = probeTarget
+		}
+		id := greenID
+		logf(t.Text, "Waiting up to %s for %s to run and pass its healthcheck", blueGreenReadyTimeout, greenName)
+		run(1, func() error {
+			ctx, cancel := context.WithTimeout(d.refreshCtx, blueGreenReadyTimeout)
+			defer cancel()
+			if err := docker.WaitReady(ctx, id, true); err != nil {
+				return fmt.Errorf("green is not ready: %w", err)
+			}
+			if probeTarget == "" {
+				d.app.QueueUpdateDraw(func() { logf(t.Warning, "Ready; no probe target, so the route itself wasn't checked") })
+				return nil
+			}
+			failed := 0
+			for i := range blueGreenChecks {
+				r := probe.Check(d.refreshCtx, p)
+				d.app.QueueUpdateDraw(func() {
+					if r.Up {
+						logf(t.Success, "Check %d/%d: up in %s %s", i+1, blueGreenChecks, r.Latency.Round(time.Millisecond), probeStatus(r))
+					} else {
+						logf(t.Error, "Check %d/%d: down: %s", i+1, blueGreenChecks, errorSummary(r.Err))
+					}
+				})
+				if !r.Up {
+					failed++
+				}
+				time.Sleep(time.Second)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks of %s failed", failed, blueGreenChecks, probeTarget)
+			}
+			d.app.QueueUpdateDraw(func() { logf(t.Success, "Green passed all %d checks", blueGreenChecks) })
+			return nil
+		})
+	}
+
+	promptCheck := func() {
+		if greenID == "" {
+			showToast(d.app, toastWarning, "Start green first (1)")
+			return
+		}
+		go func() {
+			def := target
+			if def == "" {
+				def = d.greenProbeTarget(blue, greenID)
+			}
+			d.app.QueueUpdateDraw(func() {
+				input := tview.NewInputField().
+					SetLabel("Probe: ").
+					SetText(def).
+					SetPlaceholder("http://localhost:49153/health or host:port, empty to only wait for health").
+					SetFieldWidth(0)
+				input.SetBorder(true).
+					SetTitle(" 🩺 Check green (Enter to run, ESC to cancel) ").
+					SetBorderPadding(0, 0, 1, 1).
+					SetBorderColor(tcell.GetColor(t.Info))
+				input.SetDoneFunc(func(key tcell.Key) {
+					restore()
+					if key == tcell.KeyEnter {
+						check(strings.TrimSpace(input.GetText()))
+					}
+				})
+				showOverlay(d.app, flex, input, 80, 3)
+			})
+		}()
+	}
+
+	promote := func() {
+		if greenID == "" {
+			showToast(d.app, toastWarning, "Start green first (1)")
+			return
+		}
+		if promoted {
+			showToast(d.app, toastInfo, "Already promoted")
+			return
+		}
+		message := fmt.Sprintf("Stop '%s' and rename '%s' to '%s'?\n\nBlue is kept, stopped, as '%s-blue' for a roll back.",
+			blue.Name, greenName, blue.Name, blue.Name)
+		if steps[1] != stepDone {
+			message += "\n\n⚠ Green hasn't passed the check step."
+		}
+		guardProtected(d.app, flex, "stop", []docker.ContainerInfo{blue}, func() {
+			showConfirmation(d.app, flex, message, func() {
+				restore()
+				id, name := greenID, greenName
+				run(2, func() error {
+					if err := docker.StopContainer(blue.ID); err != nil {
+						return fmt.Errorf("failed to stop blue: %w", err)
+					}
+					if err := docker.RenameContainer(blue.ID, blue.Name+"-blue"); err != nil {
+						return fmt.Errorf("blue is stopped, but renaming it failed: %w", err)
+					}
+					if err := docker.RenameContainer(id, blue.Name); err != nil {
+						return fmt.Errorf("blue is stopped, but renaming green failed: %w", err)
+					}
+					d.app.QueueUpdateDraw(func() {
+						promoted, greenName = true, blue.Name
+						logf(t.Success, "Promoted: %s now runs as %s, blue kept as %s-blue", name, blue.Name, blue.Name)
+					})
+					return nil
+				})
+			})
+		})
+	}
+
+	rollback := func() {
+		if greenID == "" {
+			showToast(d.app, toastInfo, "Nothing to roll back")
+			return
+		}
+		message := fmt.Sprintf("Remove '%s' and keep '%s' running?", greenName, blue.Name)
+		if promoted {
+			message = fmt.Sprintf("Remove the promoted green container and start '%s' again under its name?", blue.Name)
+		}
+		showConfirmation(d.app, flex, message, func() {
+			restore()
+			id, wasPromoted := greenID, promoted
+			busy = true
+			logf(t.Warning, "Rolling back")
+			go func() {
+				err := docker.RemoveContainer(id)
+				if err == nil && wasPromoted {
+					if err = docker.RenameContainer(blue.ID, blue.Name); err == nil {
+						err = docker.StartContainer(blue.ID)
+					}
+				}
+				d.app.QueueUpdateDraw(func() {
+					busy = false
+					d.updateList()
+					if err != nil {
+						logf(t.Error, "Roll back failed: %s", errorSummary(err))
+						return
+					}
+					greenID, greenName, aliases, target, promoted = "", "", nil, "", false
+					steps = [3]int{}
+					logf(t.Success, "Rolled back: %s serves alone again", blue.Name)
+					render()
+				})
+			}()
+		})
+	}
+
+	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack {
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		}
+		if busy && event.Rune() != 0 {
+			showToast(d.app, toastInfo, "Wait for the current step to finish")
+			return nil
+		}
+		switch event.Rune() {
+		case '1':
+			startGreen()
+			return nil
+		case '2':
+			promptCheck()
+			return nil
+		case '3':
+			promote()
+			return nil
+		case 'r':
+			rollback()
+			return nil
+		}
+		return event
+	})
+
+	logf(t.Text, "Blue is %s on %s. Start green with the new image (1), check it (2), then promote it (3).", blue.Name, blue.Image)
+	render()
+	restore()
+}
+
+// blueGreenTraffic describes who gets the traffic at the current step
+func blueGreenTraffic(started, promoted bool, aliases []string) string {
+	switch {
+	case promoted:
+		return "green only, under blue's name"
+	case started && len(aliases) > 0:
+		return "split between blue and green via " + strings.Join(aliases, ", ")
+	case started:
+		return "blue only; green is reachable on its own ports"
+	}
+	return "blue only"
+}
+
+// probeStatus shows the HTTP status of a probe result, "" for tcp probes
+func probeStatus(r probe.Result) string {
+	if r.Status == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(HTTP %d)", r.Status)
+}
+
+// greenProbeTarget suggests what to probe on green: blue's configured probe moved to
+// the host port green publishes the same container port on, or else green's first
+// published port on this machine
+func (d *Dashboard) greenProbeTarget(blue docker.ContainerInfo, greenID string) string {
+	greenPorts, err := docker.HostPorts(greenID)
+	if err != nil || len(greenPorts) == 0 {
+		return ""
+	}
+	bluePorts, _ := docker.HostPorts(blue.ID)
+	for _, s := range d.probes.For(blue) {
+		target := s.probe.Target()
+		for port, host := range bluePorts {
+			if green, ok := greenPorts[port]; ok && strings.Contains(target, ":"+host) {
+				return strings.Replace(target, ":"+host, ":"+green, 1)
+			}
+		}
+	}
+
+	var first string
+	for port := range greenPorts {
+		if first == "" || port < first {
+			first = port
+		}
+	}
+	if strings.HasSuffix(first, "/udp") {
+		return ""
+	}
+	return "localhost:" + greenPorts[first]
+}
@@ -0,0 +1,282 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// bulkExecOutputWidth is how much of a command's output the result table shows
+const bulkExecOutputWidth = 80
+
+// bulkExecResult is the outcome of a command in one container
+type bulkExecResult struct {
+	name    string
+	status  string
+	code    int
+	output  string
+	err     error // failure to run the command, not a non-zero exit
+	hasCode bool
+}
+
+// showBulkExecForm asks for the command to run in the selected containers
+func showBulkExecForm(app *tview.Application, mainView tview.Primitive, onRun func(command string)) {
+	form := tview.NewForm().
+		AddInputField("Command", "", 50, nil, nil)
+	form.AddButton("Run", func() {
+		command := strings.TrimSpace(form.GetFormItemByLabel("Command").(*tview.InputField).GetText())
+		if command == "" {
+			showMessage(app, form, "Missing Command", "Enter the command to run, e.g. cat /app/VERSION")
+			return
+		}
+		onRun(command)
+	})
+	form.AddButton("Cancel", func() {
+		setRoot(app, mainView)
+	})
+	form.SetCancelFunc(func() {
+		setRoot(app, mainView)
+	})
+	form.SetBorder(true).
+		SetTitle(" 💻 Run Command (via /bin/sh -c) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(ColorOrange)
+
+	showOverlay(app, mainView, form, 70, 9)
+}
+
+// firstOutputLine is the trimmed output of a command as shown in the result table:
+// its first non-empty line, marked when more follows
+func firstOutputLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	first := strings.TrimSpace(lines[0])
+	if len(lines) > 1 {
+		first += fmt.Sprintf(" (+%d lines)", len(lines)-1)
+	}
+	return truncateString(first, bulkExecOutputWidth)
+}
+
+// performBulkExec runs command in all given containers in parallel and lists exit code
+// and output per container. Enter shows the full output of a container.
+func performBulkExec(app *tview.Application, mainView tview.Primitive, containerIDs []string, names map[string]string, command string, bulkMode *BulkOperationMode, updateList func()) {
+	t := currentTheme()
+	concurrency := bulkMode.Concurrency()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 💻 Running ").
+		SetBorderColor(ColorYellow).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] Enter [-:-:-] Full output   [black:red] c [-:-:-] Cancel remaining   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var mu sync.Mutex
+	results := make(map[string]*bulkExecResult, len(containerIDs))
+	var ordered []*bulkExecResult
+	for _, id := range containerIDs {
+		name := names[id]
+		if name == "" {
+			name = id[:12]
+		}
+		result := &bulkExecResult{name: name, status: bulkPending}
+		results[id] = result
+		ordered = append(ordered, result)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].name < ordered[j].name })
+
+	// render must be called on the UI goroutine
+	render := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for col, h := range []string{"CONTAINER", "EXIT CODE", "OUTPUT"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		var succeeded, failed, pending int
+		for i, r := range ordered {
+			code, codeColor := r.status, t.Muted
+			output, outputColor := firstOutputLine(r.output), tview.Styles.PrimaryTextColor
+			switch {
+			case r.status == bulkRunning:
+				code, codeColor = "⏳", t.Warning
+			case r.err != nil:
+				code, codeColor = "error", t.Error
+				output, outputColor = errorSummary(r.err), tcell.GetColor(t.Error)
+				failed++
+			case r.hasCode && r.code == 0:
+				code, codeColor = "0", t.Success
+				succeeded++
+			case r.hasCode:
+				code, codeColor = fmt.Sprintf("%d", r.code), t.Error
+				failed++
+			case r.status == bulkPending:
+				pending++
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(r.name).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 1, tview.NewTableCell(code).SetTextColor(tcell.GetColor(codeColor)).SetAlign(tview.AlignRight))
+			table.SetCell(i+1, 2, tview.NewTableCell(output).SetTextColor(outputColor).SetExpansion(1))
+		}
+
+		header.SetText(fmt.Sprintf(
+			" [%s]$ %s[-] in %d containers (%d at a time)\n"+
+				" [%s]✓ Exit 0: %d[-]   [%s]✗ Failed: %d[-]   [%s]Pending: %d[-]",
+			t.Accent, tview.Escape(command), len(ordered), concurrency,
+			t.Success, succeeded, t.Error, failed, t.Muted, pending))
+	}
+	render()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := false
+
+	leave := func() {
+		cancel()
+		bulkMode.Clear()
+		bulkMode.Toggle() // Exit bulk mode
+		updateList()
+		setRoot(app, mainView)
+	}
+
+	table.SetSelectedFunc(func(row, _ int) {
+		if row < 1 || row > len(ordered) {
+			return
+		}
+		mu.Lock()
+		r := *ordered[row-1]
+		mu.Unlock()
+		if r.status == bulkPending || r.status == bulkRunning {
+			return
+		}
+		showBulkExecOutput(app, flex, r)
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			// Commands already running finish in the background
+			leave()
+			return nil
+		case event.Rune() == 'c' && !finished:
+			cancel()
+			footer.SetText("[black:yellow] Cancelling: waiting for running commands... [-:-:-]")
+			return nil
+		}
+		return event
+	})
+
+	setRoot(app, flex)
+	app.SetFocus(table)
+
+	go func() {
+		defer cancel()
+
+		run := func(id string) (string, error) {
+			output, err := docker.ExecCommandWith(id, command, docker.ExecOptions{})
+			mu.Lock()
+			r := results[id]
+			r.output = output
+			var exit *docker.ExitError
+			switch {
+			case errors.As(err, &exit):
+				r.code, r.hasCode = exit.Code, true
+			case err != nil:
+				r.err = err
+			default:
+				r.hasCode = true
+			}
+			mu.Unlock()
+			return "", nil
+		}
+		wave := make([]bulkStep, 0, len(containerIDs))
+		for _, id := range containerIDs {
+			wave = append(wave, bulkStep{id: id, op: "exec", run: run})
+		}
+
+		runBulkWave(ctx, wave, concurrency, func(step bulkStep, starting bool, _ string, _ error) {
+			mu.Lock()
+			if starting {
+				results[step.id].status = bulkRunning
+			} else {
+				results[step.id].status = bulkDone
+			}
+			mu.Unlock()
+			app.QueueUpdateDraw(render)
+		}, func(string) bool { return false })
+
+		mu.Lock()
+		for _, r := range ordered {
+			if r.status == bulkPending {
+				r.status = bulkCancelled
+			}
+		}
+		mu.Unlock()
+
+		app.QueueUpdateDraw(func() {
+			finished = true
+			render()
+			table.SetTitle(" ✅ Complete ")
+			table.SetBorderColor(ColorGreen)
+			footer.SetText("[black:green] Enter [-:-:-] Full output   [black:red] q/ESC [-:-:-] Back")
+		})
+	}()
+}
+
+// showBulkExecOutput shows the whole output of a command in one container on top of view
+func showBulkExecOutput(app *tview.Application, view tview.Primitive, r bulkExecResult) {
+	t := currentTheme()
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+
+	body := tview.Escape(strings.TrimRight(r.output, "\n"))
+	if r.err != nil {
+		body = fmt.Sprintf("[%s]%s[-]", t.Error, tview.Escape(errorText(r.err)))
+	} else if body == "" {
+		body = fmt.Sprintf("[%s](no output)[-]", t.Muted)
+	}
+	text.SetText(body)
+	title := fmt.Sprintf(" %s: exit code %d (ESC to close) ", r.name, r.code)
+	if r.err != nil {
+		title = fmt.Sprintf(" %s: failed (ESC to close) ", r.name)
+	}
+	text.SetBorder(true).
+		SetTitle(title).
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			setRoot(app, view)
+			return nil
+		}
+		return event
+	})
+
+	showOverlay(app, view, text, 100, 25)
+}
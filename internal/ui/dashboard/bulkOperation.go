@@ -1,7 +1,9 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
@@ -10,19 +12,39 @@ import (
 	"devops-dashboard/internal/docker"
 )
 
+// defaultBulkConcurrency is how many containers a bulk action handles at once
+const defaultBulkConcurrency = 4
+
 // BulkOperationMode manages multi-container selection
 type BulkOperationMode struct {
 	enabled     bool
 	selectedIDs map[string]bool
+	concurrency int
 	mu          sync.RWMutex
 }
 
 func NewBulkOperationMode() *BulkOperationMode {
 	return &BulkOperationMode{
 		selectedIDs: make(map[string]bool),
+		concurrency: defaultBulkConcurrency,
 	}
 }
 
+// SetConcurrency sets the number of parallel workers (0 keeps the default)
+func (b *BulkOperationMode) SetConcurrency(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n > 0 {
+		b.concurrency = n
+	}
+}
+
+func (b *BulkOperationMode) Concurrency() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.concurrency
+}
+
 func (b *BulkOperationMode) Toggle() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -48,6 +70,28 @@ func (b *BulkOperationMode) ToggleContainer(id string) {
 	}
 }
 
+// Select adds containers to the selection
+func (b *BulkOperationMode) Select(ids ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ids {
+		b.selectedIDs[id] = true
+	}
+}
+
+// Invert toggles the selection of each given container
+func (b *BulkOperationMode) Invert(ids []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ids {
+		if b.selectedIDs[id] {
+			delete(b.selectedIDs, id)
+		} else {
+			b.selectedIDs[id] = true
+		}
+	}
+}
+
 func (b *BulkOperationMode) IsSelected(id string) bool {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -86,9 +130,13 @@ func ShowBulkActionsMenu(app *tview.Application, mainView tview.Primitive, bulkM
 
 	// Get selected container names
 	selectedNames := []string{}
+	selected := []docker.ContainerInfo{}
+	namesByID := make(map[string]string)
 	for _, container := range containers {
 		if bulkMode.IsSelected(container.ID) {
-			selectedNames = append(selectedNames, container.Name)
+			selected = append(selected, container)
+			selectedNames = append(selectedNames, qualifiedName(container))
+			namesByID[container.ID] = qualifiedName(container)
 		}
 	}
 
@@ -101,37 +149,105 @@ func ShowBulkActionsMenu(app *tview.Application, mainView tview.Primitive, bulkM
 
 	menu.AddItem("🟢 Start All", "Start all selected containers", '1', func() {
 		confirmBulkAction(app, mainView, "Start", selectedNames, func() {
-			performBulkAction(app, mainView, selectedIDs, "start", bulkMode, updateList)
+			performBulkAction(app, mainView, selectedIDs, selected, "start", nil, bulkMode, updateList)
 		})
 	})
 
 	menu.AddItem("🔴 Stop All", "Stop all selected containers", '2', func() {
-		confirmBulkAction(app, mainView, "Stop", selectedNames, func() {
-			performBulkAction(app, mainView, selectedIDs, "stop", bulkMode, updateList)
+		guardProtected(app, mainView, "stop", selected, func() {
+			confirmBulkAction(app, mainView, "Stop", selectedNames, func() {
+				performBulkAction(app, mainView, selectedIDs, selected, "stop", nil, bulkMode, updateList)
+			})
 		})
 	})
 
 	menu.AddItem("🔄 Restart All", "Restart all selected containers", '3', func() {
-		confirmBulkAction(app, mainView, "Restart", selectedNames, func() {
-			performBulkAction(app, mainView, selectedIDs, "restart", bulkMode, updateList)
+		guardProtected(app, mainView, "restart", selected, func() {
+			confirmBulkAction(app, mainView, "Restart", selectedNames, func() {
+				performBulkAction(app, mainView, selectedIDs, selected, "restart", nil, bulkMode, updateList)
+			})
 		})
 	})
 
 	menu.AddItem("🗑️  Delete All", "Remove all selected containers", '4', func() {
-		confirmBulkAction(app, mainView, "Delete", selectedNames, func() {
-			performBulkAction(app, mainView, selectedIDs, "delete", bulkMode, updateList)
+		guardProtected(app, mainView, "delete", selected, func() {
+			confirmBulkAction(app, mainView, "Delete", selectedNames, func() {
+				performBulkAction(app, mainView, selectedIDs, selected, "delete", nil, bulkMode, updateList)
+			})
 		})
 	})
 
 	menu.AddItem("📋 Export Logs", "Save logs from all selected containers", '5', func() {
-		showMessage(app, mainView, "Export Logs",
-			fmt.Sprintf("Exporting logs from %d containers...\n\nLogs will be saved to: ./container-logs/", len(selectedIDs)))
+		showToast(app, toastInfo, fmt.Sprintf("Exporting logs from %d containers to ./container-logs/", len(selectedIDs)))
 		go exportBulkLogs(app, mainView, selectedIDs, containers)
-		app.SetRoot(mainView, true)
+		setRoot(app, mainView)
+	})
+
+	menu.AddItem("⏸️  Pause All", "Freeze all selected containers", '6', func() {
+		confirmBulkAction(app, mainView, "Pause", selectedNames, func() {
+			performBulkAction(app, mainView, selectedIDs, selected, "pause", nil, bulkMode, updateList)
+		})
+	})
+
+	menu.AddItem("▶️  Unpause All", "Resume all selected containers", '7', func() {
+		confirmBulkAction(app, mainView, "Unpause", selectedNames, func() {
+			performBulkAction(app, mainView, selectedIDs, selected, "unpause", nil, bulkMode, updateList)
+		})
+	})
+
+	menu.AddItem("⬇️  Pull Latest Images", "Pull each container's image tag and report which have updates", '8', func() {
+		confirmBulkAction(app, mainView, "Pull latest images for", selectedNames, func() {
+			performBulkAction(app, mainView, selectedIDs, selected, "pull", nil, bulkMode, updateList)
+		})
+	})
+
+	// Rootless daemons without cgroup v2 delegation accept limits but don't enforce them
+	if p, ok := docker.CachedProfile(docker.CurrentEndpoint()); !ok || p.CanLimit() {
+		menu.AddItem("📏 Apply Resource Limits", "Set memory / CPU limits on all selected containers", '9', func() {
+			showBulkLimitsForm(app, mainView, func(limits docker.ResourceLimits) {
+				confirmBulkAction(app, mainView, "Apply limits to", selectedNames, func() {
+					performBulkAction(app, mainView, selectedIDs, selected, "limits", func(id string) (string, error) {
+						return "", docker.UpdateResources(id, limits)
+					}, bulkMode, updateList)
+				})
+			})
+		})
+	}
+
+	menu.AddItem("💻 Run Command", "Run a command in all selected containers and compare the results", 'e', func() {
+		showBulkExecForm(app, mainView, func(command string) {
+			performBulkExec(app, mainView, selectedIDs, namesByID, command, bulkMode, updateList)
+		})
+	})
+
+	var flex *tview.Flex // the whole menu screen, for overlays to return to
+	menu.AddItem("📡 Send Signal", "Send SIGHUP or another signal to the main process of all selected containers", 's', func() {
+		showSignalMenu(app, flex, fmt.Sprintf("%d containers", len(selectedIDs)), func(signal string) {
+			send := func() {
+				confirmBulkAction(app, mainView, "Send SIG"+signal+" to", selectedNames, func() {
+					performBulkAction(app, mainView, selectedIDs, selected, "SIG"+signal, func(id string) (string, error) {
+						return "", docker.SignalContainer(id, signal)
+					}, bulkMode, updateList)
+				})
+			}
+			if signalStops(signal) {
+				guardProtected(app, mainView, "kill", selected, send)
+			} else {
+				send()
+			}
+		})
+	})
+
+	menu.AddItem("📊 Compare Stats", fmt.Sprintf("CPU / memory of %d-%d containers on one time axis", minCompare, maxCompare), 'c', func() {
+		showStatsComparison(app, mainView, selected)
+	})
+
+	menu.AddItem("🔍 Diff Config", "Env vars, labels, mounts, image and ports of 2 containers side by side", 'f', func() {
+		showConfigDiff(app, mainView, selected)
 	})
 
 	menu.AddItem("❌ Cancel", "Go back to main view", 'q', func() {
-		app.SetRoot(mainView, true)
+		setRoot(app, mainView)
 	})
 
 	// Show selected container list
@@ -141,7 +257,7 @@ func ShowBulkActionsMenu(app *tview.Application, mainView tview.Primitive, bulkM
 
 	selectedList := "[yellow]Selected Containers:[-]\n\n"
 	for i, name := range selectedNames {
-		selectedList += fmt.Sprintf("[cyan]%d.[-] [white]%s[-]\n", i+1, name)
+		selectedList += fmt.Sprintf("[cyan]%d.[-] [-]%s[-]\n", i+1, name)
 	}
 	infoText.SetText(selectedList)
 	infoText.SetBorder(true).
@@ -152,9 +268,9 @@ func ShowBulkActionsMenu(app *tview.Application, mainView tview.Primitive, bulkM
 	footer := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	footer.SetText("[black:green] 1-5 [-:-:-] Actions   [black:red] q/ESC [-:-:-] Cancel")
+	footer.SetText("[black:green] 1-9/e/s/c [-:-:-] Actions   [black:red] q/ESC [-:-:-] Cancel")
 
-	flex := tview.NewFlex().
+	flex = tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(tview.NewFlex().
 			AddItem(menu, 0, 2, true).
@@ -163,13 +279,13 @@ func ShowBulkActionsMenu(app *tview.Application, mainView tview.Primitive, bulkM
 
 	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q' {
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		}
 		return event
 	})
 
-	app.SetRoot(flex, true)
+	setRoot(app, flex)
 	app.SetFocus(menu)
 }
 
@@ -185,7 +301,11 @@ func confirmBulkAction(app *tview.Application, mainView tview.Primitive, action
 		}
 		message += fmt.Sprintf("... and %d more\n", len(containerNames)-3)
 	}
-	message += "\n[red]This action cannot be undone![-]"
+	warning := "This action cannot be undone!"
+	if action == "Delete" {
+		warning = deleteWarning()
+	}
+	message += "\n[red]" + warning + "[-]"
 
 	modal := tview.NewModal().
 		SetText(message).
@@ -194,89 +314,354 @@ func confirmBulkAction(app *tview.Application, mainView tview.Primitive, action
 			if buttonLabel == "Yes" {
 				onConfirm()
 			} else {
-				app.SetRoot(mainView, true)
+				setRoot(app, mainView)
 			}
 		})
 	modal.SetTitle(" ⚠️  Confirm Bulk Action ").
 		SetBorder(true).
 		SetBorderColor(ColorRed)
 
-	app.SetRoot(modal, true)
+	setRoot(app, modal)
 }
 
-func performBulkAction(app *tview.Application, mainView tview.Primitive, containerIDs []string, action string, bulkMode *BulkOperationMode, updateList func()) {
-	// Progress view
-	progressView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetScrollable(true)
+// Bulk item states shown in the progress table
+const (
+	bulkPending   = "pending"
+	bulkRunning   = "running"
+	bulkDone      = "done"
+	bulkFailed    = "failed"
+	bulkCancelled = "cancelled"
+)
+
+// bulkItem tracks one container of a running bulk action
+type bulkItem struct {
+	id        string
+	name      string
+	status    string
+	op        string // Docker call in progress
+	remaining int    // steps left before the item is done
+	note      string // result details, e.g. whether a newer image was pulled
+	err       error
+}
 
-	progressView.SetBorder(true).
+// bulkFunc performs one operation on a container and returns an optional note
+type bulkFunc func(id string) (string, error)
+
+// bulkStep is one Docker call of a bulk action
+type bulkStep struct {
+	id  string
+	op  string
+	run bulkFunc
+}
+
+// bulkOperations are the Docker calls behind the built-in bulk actions
+var bulkOperations = map[string]bulkFunc{
+	"start":   func(id string) (string, error) { return "", docker.StartContainer(id) },
+	"stop":    func(id string) (string, error) { return "", docker.StopContainer(id) },
+	"delete":  func(id string) (string, error) { return "", removeContainer(id) },
+	"pause":   func(id string) (string, error) { return "", docker.PauseContainer(id) },
+	"unpause": func(id string) (string, error) { return "", docker.UnpauseContainer(id) },
+	"pull": func(id string) (string, error) {
+		updated, err := docker.PullLatestImage(id)
+		if err != nil {
+			return "", err
+		}
+		if !updated {
+			return "image is up to date", nil
+		}
+		return "newer image pulled, recreate to use it", nil
+	},
+}
+
+// performBulkAction runs a bulk action with live per-container progress. run is only
+// needed for actions that are not one of the bulkOperations.
+func performBulkAction(app *tview.Application, mainView tview.Primitive, containerIDs []string, selected []docker.ContainerInfo, action string, run bulkFunc, bulkMode *BulkOperationMode, updateList func()) {
+	t := currentTheme()
+	concurrency := bulkMode.Concurrency()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
 		SetTitle(fmt.Sprintf(" ⚙️  Processing: %s ", action)).
 		SetBorderColor(ColorYellow).
-		SetBorderPadding(1, 1, 2, 2)
+		SetBorderPadding(0, 0, 1, 1)
 
-	app.SetRoot(progressView, true)
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:red] c/ESC [-:-:-] Cancel remaining")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	names := make(map[string]string, len(selected))
+	for _, c := range selected {
+		names[c.ID] = qualifiedName(c)
+	}
+
+	var mu sync.Mutex
+	items := make(map[string]*bulkItem, len(containerIDs))
+	var ordered []*bulkItem
+	for _, id := range containerIDs {
+		name := names[id]
+		if name == "" {
+			name = id[:12]
+		}
+		item := &bulkItem{id: id, name: name, status: bulkPending}
+		items[id] = item
+		ordered = append(ordered, item)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].name < ordered[j].name })
+
+	statusColors := map[string]string{
+		bulkPending:   t.Muted,
+		bulkRunning:   t.Warning,
+		bulkDone:      t.Success,
+		bulkFailed:    t.Error,
+		bulkCancelled: t.Muted,
+	}
+
+	// render must be called on the UI goroutine
+	render := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for col, h := range []string{"CONTAINER", "STATUS", "DETAILS"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		counts := make(map[string]int)
+		for i, item := range ordered {
+			counts[item.status]++
+
+			status := item.status
+			if item.status == bulkRunning {
+				status = "⏳ " + item.op
+			}
+			details, detailsColor := item.note, t.Info
+			if item.err != nil {
+				details, detailsColor = errorSummary(item.err), t.Error
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(item.name).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 1, tview.NewTableCell(status).SetTextColor(tcell.GetColor(statusColors[item.status])))
+			table.SetCell(i+1, 2, tview.NewTableCell(details).SetTextColor(tcell.GetColor(detailsColor)).SetExpansion(1))
+		}
+
+		header.SetText(fmt.Sprintf(
+			" [%s]%s %d containers[-] (%d at a time)\n"+
+				" [%s]✓ Done: %d[-]   [%s]✗ Failed: %d[-]   [%s]⏳ Running: %d[-]   [%s]Pending: %d[-]   Cancelled: %d",
+			t.Accent, action, len(ordered), concurrency,
+			t.Success, counts[bulkDone], t.Error, counts[bulkFailed],
+			t.Warning, counts[bulkRunning], t.Muted, counts[bulkPending], counts[bulkCancelled]))
+	}
+	render()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := false
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if finished {
+			bulkMode.Clear()
+			bulkMode.Toggle() // Exit bulk mode
+			updateList()
+			setRoot(app, mainView)
+			return nil
+		}
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'c' || event.Rune() == 'C' {
+			cancel()
+			footer.SetText("[black:yellow] Cancelling: waiting for running operations... [-:-:-]")
+			return nil
+		}
+		return event
+	})
+
+	setRoot(app, flex)
+	app.SetFocus(table)
 
 	// Perform actions in background
 	go func() {
-		total := len(containerIDs)
-		success := 0
-		failed := 0
-
-		for i, id := range containerIDs {
-			app.QueueUpdateDraw(func() {
-				progressView.SetText(fmt.Sprintf(
-					"[cyan]Progress: %d/%d[-]\n\n"+
-						"[green]✓ Success: %d[-]\n"+
-						"[red]✗ Failed: %d[-]\n\n"+
-						"[yellow]Processing container %d...[-]",
-					i+1, total, success, failed, i+1))
-			})
+		defer cancel()
+
+		phases := bulkPhases(containerIDs, action, run)
+		hookSteps(phases, action, selected)
+		mu.Lock()
+		for _, wave := range phases {
+			for _, step := range wave {
+				items[step.id].remaining++
+			}
+		}
+		mu.Unlock()
 
-			var err error
-			switch action {
-			case "start":
-				err = docker.StartContainer(id)
-			case "stop":
-				err = docker.StopContainer(id)
-			case "restart":
-				err = docker.RestartContainer(id)
-			case "delete":
-				err = docker.RemoveContainer(id)
+		for _, wave := range phases {
+			if ctx.Err() != nil {
+				break
 			}
+			runBulkWave(ctx, wave, concurrency, func(step bulkStep, starting bool, note string, err error) {
+				mu.Lock()
+				item := items[step.id]
+				item.note = note
+				switch {
+				case starting:
+					item.status = bulkRunning
+					item.op = step.op
+				case err != nil:
+					item.status = bulkFailed
+					item.err = err
+				default:
+					item.remaining--
+					item.status = bulkPending
+					if item.remaining == 0 {
+						item.status = bulkDone
+					}
+				}
+				mu.Unlock()
+				app.QueueUpdateDraw(render)
+			}, func(id string) bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return items[id].status == bulkFailed
+			})
+		}
 
-			if err == nil {
-				success++
-			} else {
-				failed++
+		mu.Lock()
+		for _, item := range ordered {
+			if item.status == bulkPending || item.status == bulkRunning {
+				item.status = bulkCancelled
 			}
 		}
+		mu.Unlock()
 
 		// Show final results
 		app.QueueUpdateDraw(func() {
-			resultText := fmt.Sprintf(
-				"[::b][cyan]Bulk Operation Complete![-:-:-]\n\n"+
-					"[green]✓ Successful: %d[-]\n"+
-					"[red]✗ Failed: %d[-]\n"+
-					"[yellow]Total: %d[-]\n\n"+
-					"Press any key to continue...",
-				success, failed, total)
-
-			progressView.SetText(resultText)
-			progressView.SetTitle(" ✅ Complete ")
-			progressView.SetBorderColor(ColorGreen)
-
-			progressView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-				bulkMode.Clear()
-				bulkMode.Toggle() // Exit bulk mode
-				updateList()
-				app.SetRoot(mainView, true)
-				return nil
-			})
+			finished = true
+			render()
+			table.SetTitle(" ✅ Complete ")
+			table.SetBorderColor(ColorGreen)
+			footer.SetText("[black:green] Press any key to continue [-:-:-]")
 		})
 	}()
 }
 
+// runBulkWave runs independent steps on up to concurrency workers. report is called
+// before and after each step; steps of containers for which skip returns true, and all
+// steps not started before ctx is cancelled, are left out.
+func runBulkWave(ctx context.Context, wave []bulkStep, concurrency int, report func(step bulkStep, starting bool, note string, err error), skip func(id string) bool) {
+	jobs := make(chan bulkStep)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for step := range jobs {
+				// Don't start a container whose stop already failed
+				if ctx.Err() != nil || skip(step.id) {
+					continue
+				}
+				report(step, true, "", nil)
+				note, err := step.run(step.id)
+				report(step, false, note, err)
+			}
+		}()
+	}
+
+	for _, step := range wave {
+		select {
+		case jobs <- step:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// bulkPhases splits a bulk action into waves by container dependencies. Containers
+// within a wave are independent and may run in parallel; waves run one after another.
+// Dependencies are started first and stopped last, so a restart stops everything in
+// reverse dependency order before starting it again in forward order. Actions that
+// don't change the running state (pulls, limits) ignore dependencies.
+func bulkPhases(containerIDs []string, action string, run bulkFunc) [][]bulkStep {
+	levels, err := docker.DependencyLevels(containerIDs)
+	if err != nil {
+		levels = [][]string{containerIDs}
+	}
+
+	reversed := make([][]string, len(levels))
+	for i, level := range levels {
+		reversed[len(levels)-1-i] = level
+	}
+
+	var phases [][]bulkStep
+	add := func(levels [][]string, op string) {
+		fn := run
+		if builtin, ok := bulkOperations[op]; ok {
+			fn = builtin
+		}
+		for _, level := range levels {
+			wave := make([]bulkStep, 0, len(level))
+			for _, id := range level {
+				wave = append(wave, bulkStep{id: id, op: op, run: fn})
+			}
+			phases = append(phases, wave)
+		}
+	}
+
+	switch action {
+	case "start", "unpause":
+		add(levels, action)
+	case "stop", "delete", "pause":
+		add(reversed, action)
+	case "restart":
+		add(reversed, "stop")
+		add(levels, "start")
+	default:
+		add([][]string{containerIDs}, action)
+	}
+	return phases
+}
+
+// showBulkLimitsForm asks for the memory and CPU limits to apply to the selection
+func showBulkLimitsForm(app *tview.Application, mainView tview.Primitive, onApply func(docker.ResourceLimits)) {
+	form := tview.NewForm().
+		AddInputField("Memory", "", 20, nil, nil).
+		AddInputField("CPUs", "", 20, nil, nil)
+	form.AddButton("Apply", func() {
+		memory := form.GetFormItemByLabel("Memory").(*tview.InputField).GetText()
+		cpus := form.GetFormItemByLabel("CPUs").(*tview.InputField).GetText()
+		limits, err := docker.ParseResourceLimits(memory, cpus)
+		if err != nil {
+			showMessage(app, form, "Invalid Limits", err.Error()+"\n\nExamples: memory 512m or 2g, CPUs 0.5 or 2")
+			return
+		}
+		onApply(limits)
+	})
+	form.AddButton("Cancel", func() {
+		setRoot(app, mainView)
+	})
+	form.SetCancelFunc(func() {
+		setRoot(app, mainView)
+	})
+	form.SetBorder(true).
+		SetTitle(" 📏 Resource Limits (leave empty to keep) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(ColorOrange)
+
+	showOverlay(app, mainView, form, 50, 11)
+}
+
 func exportBulkLogs(app *tview.Application, mainView tview.Primitive, containerIDs []string, containers []docker.ContainerInfo) {
 	// This would save logs to files
 	// Implementation depends on your requirements
@@ -299,8 +684,5 @@ func exportBulkLogs(app *tview.Application, mainView tview.Primitive, containerI
 		_ = name // Use the name for filename
 	}
 
-	app.QueueUpdateDraw(func() {
-		showMessage(app, mainView, "Success",
-			fmt.Sprintf("Successfully exported logs from %d containers!\n\nLocation: ./container-logs/", len(containerIDs)))
-	})
+	showToast(app, toastSuccess, fmt.Sprintf("Exported logs from %d containers to ./container-logs/", len(containerIDs)))
 }
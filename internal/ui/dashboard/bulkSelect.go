@@ -0,0 +1,95 @@
+package dashboard
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// isVisible reports whether a container passes the active saved view, label filter and
+// health strip filter
+func (d *Dashboard) isVisible(c docker.ContainerInfo) bool {
+	return d.grouping.Matches(c) && viewMatches(d.currentView(), c) && d.healthMatches(c)
+}
+
+// visibleIDs returns the IDs of the listed containers that satisfy match
+func (d *Dashboard) visibleIDs(match func(docker.ContainerInfo) bool) []string {
+	d.mu.RLock()
+	containers := d.containers
+	d.mu.RUnlock()
+
+	var ids []string
+	for _, c := range containers {
+		if d.isVisible(c) && match(c) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// bulkSelect applies one of the selection helpers, entering bulk mode if needed
+func (d *Dashboard) bulkSelect(action string) {
+	if !d.bulkMode.IsEnabled() {
+		d.bulkMode.Toggle()
+	}
+
+	all := func(docker.ContainerInfo) bool { return true }
+	switch action {
+	case actionBulkSelectAll:
+		d.bulkMode.Select(d.visibleIDs(all)...)
+	case actionBulkInvert:
+		d.bulkMode.Invert(d.visibleIDs(all))
+	case actionBulkSelectRunning:
+		d.bulkMode.Select(d.visibleIDs(func(c docker.ContainerInfo) bool { return c.State == "running" })...)
+	case actionBulkSelectStopped:
+		d.bulkMode.Select(d.visibleIDs(func(c docker.ContainerInfo) bool { return c.State != "running" })...)
+	}
+
+	d.updateList()
+	d.showBulkModeInfo()
+}
+
+// showBulkRegexPrompt asks for a pattern and selects visible containers whose name or image matches
+func (d *Dashboard) showBulkRegexPrompt() {
+	t := currentTheme()
+	input := tview.NewInputField().
+		SetLabel("Regex: ").
+		SetPlaceholder("name or image, e.g. ^api-|redis").
+		SetFieldWidth(0)
+	input.SetBorder(true).
+		SetTitle(" 🎯 Select by name / image (Enter to select, ESC to cancel) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			setRoot(d.app, d.mainFlex)
+			return
+		}
+
+		re, err := regexp.Compile(input.GetText())
+		if err != nil {
+			input.SetLabel(fmt.Sprintf("[%s]Invalid:[-] ", t.Error))
+			return
+		}
+
+		ids := d.visibleIDs(func(c docker.ContainerInfo) bool {
+			return re.MatchString(c.Name) || re.MatchString(c.Image)
+		})
+		if !d.bulkMode.IsEnabled() {
+			d.bulkMode.Toggle()
+		}
+		d.bulkMode.Select(ids...)
+
+		setRoot(d.app, d.mainFlex)
+		d.updateList()
+		d.showBulkModeInfo()
+		d.flashStatus(fmt.Sprintf("[%s]Selected %d containers matching[-] %s", t.Success, len(ids), tview.Escape(re.String())))
+	})
+
+	showOverlay(d.app, d.mainFlex, input, 70, 3)
+}
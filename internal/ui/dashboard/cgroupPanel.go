@@ -0,0 +1,145 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// cgroupRefresh is how often the cgroup panel rereads the kernel's values
+const cgroupRefresh = 2 * time.Second
+
+// cgroupUnlimited is the smallest value cgroup v1 uses for "no limit", a page-aligned
+// 2^63-1 on most hosts
+const cgroupUnlimited = 1 << 62
+
+// cgroupStatKeys are the counters of cpu.stat worth showing; the rest is in docker stats
+var cgroupStatKeys = map[string]bool{
+	"usage_usec": true, "nr_periods": true, "nr_throttled": true, "throttled_usec": true, "throttled_time": true,
+}
+
+// cgroupText renders what the kernel enforces for a container, with byte counts made
+// readable and "max" spelled out, for the narrow right panel of the stats screen
+func cgroupText(info *docker.CgroupInfo) string {
+	t := currentTheme()
+	var b strings.Builder
+
+	source := "inside the container"
+	if info.Host {
+		source = "host /sys/fs/cgroup"
+	}
+	fmt.Fprintf(&b, "[%s]cgroup v%d, %s[-]\n", t.Muted, info.Version, source)
+	if info.Path != "" {
+		fmt.Fprintf(&b, "[%s]%s[-]\n", t.Muted, tview.Escape(info.Path))
+	}
+
+	for _, v := range info.Values {
+		fmt.Fprintf(&b, "\n[%s::b]%s[-:-:-]\n", t.Highlight, tview.Escape(v.File))
+		for _, line := range v.Lines {
+			text, warn := cgroupLine(v.File, line)
+			if text == "" {
+				continue
+			}
+			if warn {
+				text = fmt.Sprintf("[%s]%s[-]", t.Warning, text)
+			}
+			b.WriteString(" " + text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// cgroupLine formats one line of a cgroup file, reporting whether it deserves attention:
+// throttling, OOM kills, hitting a limit or pressure above 10%. It returns "" for lines
+// not worth the space.
+func cgroupLine(file, line string) (string, bool) {
+	name := file[strings.LastIndex(file, "/")+1:]
+	fields := strings.Fields(line)
+
+	switch {
+	case strings.HasSuffix(name, ".pressure"):
+		// "some avg10=0.00 avg60=0.00 avg300=0.00 total=123"
+		if len(fields) < 4 {
+			break
+		}
+		avg10, _ := strconv.ParseFloat(strings.TrimPrefix(fields[1], "avg10="), 64)
+		text := fmt.Sprintf("%-4s %s%% %s%% %s%%", fields[0],
+			strings.TrimPrefix(fields[1], "avg10="), strings.TrimPrefix(fields[2], "avg60="), strings.TrimPrefix(fields[3], "avg300="))
+		return tview.Escape(text), avg10 > 10
+
+	case name == "cpu.stat" || name == "memory.events":
+		if len(fields) != 2 {
+			break
+		}
+		if name == "cpu.stat" && !cgroupStatKeys[fields[0]] {
+			return "", false
+		}
+		n, _ := strconv.ParseInt(fields[1], 10, 64)
+		warn := n > 0 && (fields[0] == "nr_throttled" || fields[0] == "oom_kill" || fields[0] == "max")
+		return tview.Escape(fmt.Sprintf("%-15s %s", fields[0], fields[1])), warn
+
+	case name == "cpu.max":
+		// "quota period", the quota being "max" without a limit
+		if len(fields) == 2 {
+			if fields[0] == "max" {
+				return "unlimited", false
+			}
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && period > 0 {
+				return fmt.Sprintf("%s / %s µs = %.2f CPUs", fields[0], fields[1], quota/period), false
+			}
+		}
+
+	case name == "cpu.cfs_quota_us" && line == "-1":
+		return "unlimited", false
+
+	case line == "max":
+		return "unlimited", false
+
+	case name == "memory.failcnt":
+		n, _ := strconv.ParseInt(line, 10, 64)
+		return line, n > 0
+
+	case strings.HasPrefix(name, "memory.") && !strings.Contains(name, "events"):
+		n, err := strconv.ParseUint(line, 10, 64)
+		switch {
+		case err != nil:
+		case n >= cgroupUnlimited:
+			return "unlimited", false
+		default:
+			return fmt.Sprintf("%s (%s)", docker.FormatBytes(n), line), false
+		}
+	}
+	return tview.Escape(line), false
+}
+
+// pollCgroup rereads the cgroup of a container into view until ctx is cancelled
+func pollCgroup(ctx context.Context, app *tview.Application, view *tview.TextView, containerID string) {
+	ticker := time.NewTicker(cgroupRefresh)
+	defer ticker.Stop()
+	for {
+		info, err := docker.ReadCgroup(containerID)
+		if ctx.Err() != nil {
+			return
+		}
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				view.SetText(fmt.Sprintf("[%s]%s[-]", currentTheme().Error, tview.Escape(errorSummary(err))))
+				return
+			}
+			view.SetText(cgroupText(info))
+		})
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// leakIcons mark the kind of a leaked resource in the cleanup table
+var leakIcons = map[string]string{
+	docker.LeakContainer: "📦",
+	docker.LeakVolume:    "💾",
+	docker.LeakImage:     "🖼",
+	docker.LeakNetwork:   "🌐",
+}
+
+// formatAge prints how long ago something happened in its largest unit, e.g. 12d or 5h
+func formatAge(since time.Time) string {
+	if since.IsZero() {
+		return "-"
+	}
+	d := time.Since(since)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+// scoreColor colors a cleanup score
+func scoreColor(score int) string {
+	t := currentTheme()
+	switch {
+	case score >= 90:
+		return t.Success
+	case score >= 60:
+		return t.Warning
+	}
+	return t.Error
+}
+
+// showCleanup lists the resources left behind on the current host: dangling images,
+// volumes and networks nothing uses, and long exited containers. d removes the
+// selected one; containers go through the same safeguards as deleting from the list.
+func (d *Dashboard) showCleanup() {
+	t := currentTheme()
+	staleDays := int(d.cfg.StaleAfter().Hours() / 24)
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 🧹 Cleanup ").
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:red] d [-:-:-] Remove   [black:green] +/- [-:-:-] Stale after days   [black:green] r [-:-:-] Rescan   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var leaks []docker.Leak
+
+	// render must be called on the UI goroutine
+	render := func(err error) {
+		table.Clear()
+		for col, h := range []string{"KIND", "NAME", "AGE", "DETAILS"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		if err != nil {
+			header.SetText(fmt.Sprintf(" [%s]Scan failed[-]", t.Error))
+			table.SetCell(1, 0, tview.NewTableCell(errorSummary(err)).SetTextColor(tcell.GetColor(t.Error)).SetSelectable(false))
+			return
+		}
+
+		counts := make(map[string]int)
+		var size int64
+		for i, l := range leaks {
+			counts[l.Kind]++
+			size += l.Size
+			table.SetCell(i+1, 0, tview.NewTableCell(leakIcons[l.Kind]+" "+l.Kind).SetTextColor(tcell.GetColor(t.Accent)))
+			table.SetCell(i+1, 1, tview.NewTableCell(l.Name).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 2, tview.NewTableCell(formatAge(l.Since)).SetTextColor(tcell.GetColor(t.Muted)).SetAlign(tview.AlignRight))
+			table.SetCell(i+1, 3, tview.NewTableCell(l.Detail).SetTextColor(tcell.GetColor(t.Muted)).SetExpansion(1))
+		}
+		if len(leaks) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("Nothing to clean up").SetTextColor(tcell.GetColor(t.Success)).SetSelectable(false))
+		}
+
+		score := docker.CleanupScore(leaks)
+		header.SetText(fmt.Sprintf(
+			" [%s::b]Cleanup score: %d/100[-:-:-]   %s reclaimable\n"+
+				" %d exited containers older than %d days   %d volumes   %d images   %d networks",
+			scoreColor(score), score, docker.FormatBytes(uint64(size)),
+			counts[docker.LeakContainer], staleDays, counts[docker.LeakVolume], counts[docker.LeakImage], counts[docker.LeakNetwork]))
+	}
+
+	// Only the latest scan is shown when the stale days change quickly
+	generation := 0
+	scan := func() {
+		header.SetText(fmt.Sprintf(" [%s]Scanning...[-]", t.Muted))
+		generation++
+		current := generation
+		staleAfter := time.Duration(staleDays) * 24 * time.Hour
+		go func() {
+			found, err := docker.FindLeaks(staleAfter)
+			d.app.QueueUpdateDraw(func() {
+				if current != generation {
+					return
+				}
+				leaks = found
+				render(err)
+			})
+		}()
+	}
+	render(nil)
+	scan()
+
+	remove := func(l docker.Leak) {
+		run := func() {
+			go func() {
+				var err error
+				if l.Kind == docker.LeakContainer {
+					c := docker.ContainerInfo{ID: l.ID, Name: l.Name}
+					err = hooked("delete", c, func() error { return removeContainer(l.ID) })
+				} else {
+					err = docker.RemoveLeak(l)
+				}
+				d.app.QueueUpdateDraw(func() {
+					if err != nil {
+						showError(d.app, flex, "Error", err)
+						return
+					}
+					showToast(d.app, toastSuccess, fmt.Sprintf("Removed %s %s", l.Kind, l.Name))
+					scan()
+				})
+			}()
+		}
+
+		if l.Kind != docker.LeakContainer {
+			showConfirmation(d.app, flex, fmt.Sprintf("Remove %s %s?\n\nThis action cannot be undone!", l.Kind, l.Name), run)
+			return
+		}
+		container := docker.ContainerInfo{ID: l.ID, Name: l.Name}
+		d.mu.RLock()
+		for _, c := range d.containers {
+			if c.ID == l.ID {
+				container = c
+			}
+		}
+		d.mu.RUnlock()
+		guardProtected(d.app, flex, "delete", []docker.ContainerInfo{container}, func() {
+			showConfirmation(d.app, flex, fmt.Sprintf("Delete container '%s'?\n\n%s", l.Name, deleteWarning()), run)
+		})
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			d.updateList()
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		case event.Rune() == 'd' || event.Key() == tcell.KeyDelete:
+			row, _ := table.GetSelection()
+			if row >= 1 && row <= len(leaks) {
+				remove(leaks[row-1])
+			}
+			return nil
+		case event.Rune() == '+':
+			staleDays++
+			scan()
+			return nil
+		case event.Rune() == '-':
+			staleDays = max(1, staleDays-1)
+			scan()
+			return nil
+		case event.Rune() == 'r':
+			scan()
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
@@ -0,0 +1,77 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// copyToClipboard posts text to the system clipboard through the terminal (OSC 52),
+// which also works over SSH. It is sent with the next screen update.
+func copyToClipboard(app *tview.Application, text string) {
+	app.QueueUpdateDraw(func() {
+		previous := app.GetAfterDrawFunc()
+		app.SetAfterDrawFunc(func(screen tcell.Screen) {
+			if previous != nil {
+				previous(screen)
+			}
+			screen.SetClipboard([]byte(text))
+			app.SetAfterDrawFunc(previous)
+		})
+	})
+}
+
+// truncateString shortens s to at most max runes, marking the cut with an ellipsis
+func truncateString(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// copyContainerField copies the ID, name, image or IP address of a container
+func (d *Dashboard) copyContainerField(container docker.ContainerInfo, action string) {
+	what, value := "", ""
+	switch action {
+	case actionCopyID:
+		what, value = "ID", container.ID
+	case actionCopyName:
+		what, value = "name", container.Name
+	case actionCopyImage:
+		what, value = "image", container.Image
+	case actionCopyIP:
+		// The IP is not part of the list data, so look it up
+		go func() {
+			details, err := docker.InspectStructured(container.ID)
+			d.app.QueueUpdateDraw(func() {
+				switch {
+				case err != nil:
+					d.flashStatus(fmt.Sprintf("[%s]✗ Failed to inspect %s:[-] %v", currentTheme().Error, container.Name, err))
+				case details.IPAddress() == "":
+					d.flashStatus(fmt.Sprintf("[%s]%s has no IP address[-]", currentTheme().Warning, container.Name))
+				default:
+					d.copyValue("IP address", details.IPAddress())
+				}
+			})
+		}()
+		return
+	default:
+		return
+	}
+	d.copyValue(what, value)
+}
+
+// copyValue copies value and confirms it in the status bar
+func (d *Dashboard) copyValue(what, value string) {
+	copyToClipboard(d.app, value)
+	d.flashStatus(fmt.Sprintf("[%s]✓ Copied %s:[-] %s", currentTheme().Success, what, tview.Escape(truncateString(value, 60))))
+}
+
+// flashStatus shows a message in the status bar until its next refresh
+func (d *Dashboard) flashStatus(msg string) {
+	d.statusBar.SetText(" " + msg)
+}
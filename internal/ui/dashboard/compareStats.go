@@ -0,0 +1,311 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+const (
+	minCompare       = 2
+	maxCompare       = 5
+	compareInterval  = 2 * time.Second
+	compareSamples   = 240 // samples kept, enough for the widest time axis
+	compareMinWidth  = 20  // columns of the shared time axis on narrow terminals
+	compareTickEvery = 15  // columns between time axis labels
+)
+
+// compareColors tells the compared containers apart, one theme color each
+func compareColors(t Theme) []string {
+	return []string{t.Accent, t.Secondary, t.Highlight, t.Success, t.Info}
+}
+
+// compareSeries is the sample history of one compared container. Samples at the same
+// index were taken in the same round, so all series share one time axis; a failed
+// sample is NaN and leaves a gap.
+type compareSeries struct {
+	container docker.ContainerInfo
+	cpu       []float64
+	mem       []float64
+	err       error
+}
+
+func (s *compareSeries) add(cpu, mem float64) {
+	s.cpu = append(s.cpu, cpu)
+	s.mem = append(s.mem, mem)
+	if len(s.cpu) > compareSamples {
+		s.cpu = s.cpu[1:]
+		s.mem = s.mem[1:]
+	}
+}
+
+// showStatsComparison stacks the CPU and memory sparklines of 2-5 containers on a shared
+// time axis and scale, so it is easy to see which one spikes first
+func showStatsComparison(app *tview.Application, mainView tview.Primitive, containers []docker.ContainerInfo) {
+	if len(containers) < minCompare || len(containers) > maxCompare {
+		showMessage(app, mainView, "📊 Compare Stats",
+			fmt.Sprintf("Select %d to %d containers to compare (%d selected).", minCompare, maxCompare, len(containers)))
+		return
+	}
+
+	series := make([]*compareSeries, len(containers))
+	for i, c := range containers {
+		series[i] = &compareSeries{container: c}
+	}
+	var mu sync.Mutex
+	t := currentTheme()
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(false)
+	view.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📊 Compare Stats (%d containers, every %s) ", len(containers), compareInterval)).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Success))
+
+	controlBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	controls := fmt.Sprintf("[-][[%[1]s]ESC/q[-]] Back   [[%[2]s]r[-]] Reset   [[%[1]s]p[-]] Pause", t.Highlight, t.Accent)
+	controlBar.SetText(controls)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, true).
+		AddItem(controlBar, 1, 0, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	paused := false
+
+	render := func() {
+		_, _, width, _ := view.GetInnerRect()
+		mu.Lock()
+		text := renderComparison(series, width)
+		mu.Unlock()
+		view.SetText(text)
+	}
+
+	// Each container is streamed; every interval the latest sample of each is recorded,
+	// so a round lines up on the time axis
+	latest := make([]*docker.ContainerStats, len(series))
+	for i, sr := range series {
+		go func(i int, id string) {
+			samples, errs := docker.StreamStats(ctx, id)
+			for stats := range samples {
+				mu.Lock()
+				latest[i] = stats
+				mu.Unlock()
+			}
+			err := <-errs
+			mu.Lock()
+			series[i].err = err
+			mu.Unlock()
+		}(i, sr.container.ID)
+	}
+
+	sample := func() {
+		mu.Lock()
+		for i, s := range series {
+			cpu, mem := math.NaN(), math.NaN()
+			if stats := latest[i]; stats != nil {
+				fmt.Sscanf(stats.CPUPerc, "%f%%", &cpu)
+				fmt.Sscanf(stats.MemPerc, "%f%%", &mem)
+			}
+			latest[i] = nil
+			s.add(cpu, mem)
+		}
+		mu.Unlock()
+		app.QueueUpdateDraw(render)
+	}
+
+	go func() {
+		ticker := time.NewTicker(compareInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !paused {
+					sample()
+				}
+			}
+		}
+	}()
+
+	back := func() {
+		cancel()
+		setRoot(app, mainView)
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q', 'Q':
+			back()
+			return nil
+		case 'r', 'R':
+			mu.Lock()
+			for _, s := range series {
+				s.cpu, s.mem = nil, nil
+			}
+			mu.Unlock()
+			render()
+			return nil
+		case 'p', 'P':
+			paused = !paused
+			if paused {
+				controlBar.SetText(fmt.Sprintf("[-][[%s]⏸ PAUSED[-]]   [[%[2]s]p[-]] Resume   [[%[2]s]ESC/q[-]] Back", t.Error, t.Highlight))
+			} else {
+				controlBar.SetText(controls)
+			}
+			return nil
+		}
+
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
+			back()
+			return nil
+		}
+		return event
+	})
+
+	view.SetText(fmt.Sprintf("[%s]⏳ Collecting stats...[-]", t.Warning))
+	setRoot(app, flex)
+	app.SetFocus(view)
+}
+
+// renderComparison draws the CPU and memory sections with a time axis under each, as
+// many samples wide as fit in width
+func renderComparison(series []*compareSeries, width int) string {
+	nameWidth := 0
+	for _, s := range series {
+		if n := len([]rune(qualifiedName(s.container))); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	if nameWidth > 24 {
+		nameWidth = 24
+	}
+	// Name, current value and the peak age take the rest of the line
+	columns := fitWidth(width-nameWidth-8-12, compareMinWidth, compareSamples)
+	t := currentTheme()
+	colors := compareColors(t)
+
+	var b strings.Builder
+	section := func(title string, values func(*compareSeries) []float64) {
+		// One scale for every row so the bars are comparable
+		max := 0.0
+		for _, s := range series {
+			for _, v := range values(s) {
+				if v > max {
+					max = v
+				}
+			}
+		}
+
+		fmt.Fprintf(&b, "[::b]%s[-:-:-] [%s](scale 0–%.1f%%)[-]\n", title, t.Muted, max)
+		for i, s := range series {
+			data := values(s)
+			if len(data) > columns {
+				data = data[len(data)-columns:]
+			}
+			current := "   n/a"
+			if len(data) > 0 && !math.IsNaN(data[len(data)-1]) {
+				current = fmt.Sprintf("%5.1f%%", data[len(data)-1])
+			}
+			peak := ""
+			if at := peakAge(data); at >= 0 {
+				peak = fmt.Sprintf("peak -%ds", at*int(compareInterval/time.Second))
+			}
+			fmt.Fprintf(&b, "[%s]%-*s[-] %s [%s]%s[-] [%s]%s[-]\n",
+				colors[i], nameWidth, tview.Escape(truncateString(qualifiedName(s.container), nameWidth)),
+				current, colors[i], comparisonSparkline(data, max, columns), t.Muted, peak)
+		}
+		// Name, value and the spaces between them
+		fmt.Fprintf(&b, "[%s]%s[-]\n\n", t.Muted, timeAxis(strings.Repeat(" ", nameWidth+8), columns))
+	}
+
+	section("CPU", func(s *compareSeries) []float64 { return s.cpu })
+	section("Memory", func(s *compareSeries) []float64 { return s.mem })
+
+	for i, s := range series {
+		if s.err != nil {
+			fmt.Fprintf(&b, "[%s]%s:[-] [%s]%s[-]\n", colors[i], tview.Escape(qualifiedName(s.container)), t.Error, tview.Escape(errorSummary(s.err)))
+		}
+	}
+	return b.String()
+}
+
+// comparisonSparkline draws data right aligned against a shared maximum; gaps are blank
+func comparisonSparkline(data []float64, max float64, width int) string {
+	if max == 0 {
+		max = 1
+	}
+	blocks := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+	var b strings.Builder
+	if pad := width - len(data); pad > 0 {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	for _, v := range data {
+		if math.IsNaN(v) {
+			b.WriteRune(' ')
+			continue
+		}
+		index := int(v / max * float64(len(blocks)-1))
+		if index < 0 {
+			index = 0
+		}
+		if index >= len(blocks) {
+			index = len(blocks) - 1
+		}
+		b.WriteRune(blocks[index])
+	}
+	return b.String()
+}
+
+// peakAge returns how many samples ago the series peaked, or -1 without samples
+func peakAge(data []float64) int {
+	peak, at := -1.0, -1
+	for i, v := range data {
+		if !math.IsNaN(v) && v > peak {
+			peak, at = v, i
+		}
+	}
+	if at < 0 {
+		return -1
+	}
+	return len(data) - 1 - at
+}
+
+// timeAxis labels the columns of the shared sparklines with their age, e.g. -120s ... now
+func timeAxis(indent string, columns int) string {
+	axis := []rune(strings.Repeat("─", columns))
+	var labels strings.Builder
+	col := 0
+	// Ticks are counted from the right so "now" sits under the latest sample
+	for tick := columns % compareTickEvery; tick <= columns; tick += compareTickEvery {
+		label := "now"
+		if age := columns - tick; age > 0 {
+			label = fmt.Sprintf("-%ds", age*int(compareInterval/time.Second))
+		}
+		if tick < columns {
+			axis[tick] = '┬'
+		}
+		if pad := tick - col; pad > 0 {
+			labels.WriteString(strings.Repeat(" ", pad))
+			col += pad
+		}
+		labels.WriteString(label)
+		col += len(label)
+	}
+	return indent + string(axis) + "\n" + indent + labels.String()
+}
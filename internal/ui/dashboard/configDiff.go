@@ -0,0 +1,207 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// showConfigDiff compares the settings of two containers side by side, e.g. staging and
+// prod on different hosts: image and tags, env vars, labels, mounts, published ports
+// and runtime settings. Only differences are listed until a shows everything.
+func showConfigDiff(app *tview.Application, mainView tview.Primitive, containers []docker.ContainerInfo) {
+	if len(containers) != 2 {
+		showMessage(app, mainView, "🔍 Diff Config",
+			fmt.Sprintf("Select exactly 2 containers to diff (%d selected).", len(containers)))
+		return
+	}
+	t := currentTheme()
+	left, right := qualifiedName(containers[0]), qualifiedName(containers[1])
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔍 Config Diff: %s ↔ %s ", tview.Escape(left), tview.Escape(right))).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] a [-:-:-] All / differences   [black:green] r [-:-:-] Reveal secrets   [black:green] y [-:-:-] Copy report   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var (
+		entries []docker.ConfigDiffEntry
+		all     bool
+		reveal  bool
+	)
+
+	// value shows one side of an entry, masking secret env values
+	value := func(e docker.ConfigDiffEntry, v string, has bool) string {
+		if !has {
+			return "(not set)"
+		}
+		if e.Section == docker.DiffEnv {
+			v = maskEnv(e.Key, v, reveal)
+		}
+		if v == "" {
+			return `""`
+		}
+		return v
+	}
+
+	// render must be called on the UI goroutine
+	render := func() {
+		table.Clear()
+		for col, h := range []string{"KEY", left, right} {
+			table.SetCell(0, col, tview.NewTableCell(tview.Escape(h)).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		counts := make(map[string]int)
+		row := 1
+		section := ""
+		for _, e := range entries {
+			if e.Same() {
+				if !all {
+					continue
+				}
+			} else {
+				counts[e.Section]++
+			}
+			if e.Section != section {
+				section = e.Section
+				table.SetCell(row, 0, tview.NewTableCell(section).
+					SetTextColor(tcell.GetColor(t.Accent)).
+					SetAttributes(tcell.AttrBold).
+					SetSelectable(false))
+				row++
+			}
+
+			leftColor, rightColor := t.Muted, t.Muted
+			switch {
+			case e.Same():
+			case !e.HasLeft:
+				leftColor, rightColor = t.Error, t.Success
+			case !e.HasRight:
+				leftColor, rightColor = t.Success, t.Error
+			default:
+				leftColor, rightColor = t.Warning, t.Warning
+			}
+			table.SetCell(row, 0, tview.NewTableCell("  "+tview.Escape(e.Key)).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(row, 1, tview.NewTableCell(tview.Escape(value(e, e.Left, e.HasLeft))).SetTextColor(tcell.GetColor(leftColor)).SetExpansion(1).SetMaxWidth(60))
+			table.SetCell(row, 2, tview.NewTableCell(tview.Escape(value(e, e.Right, e.HasRight))).SetTextColor(tcell.GetColor(rightColor)).SetExpansion(1).SetMaxWidth(60))
+			row++
+		}
+
+		var total int
+		var parts []string
+		for _, s := range docker.DiffSections {
+			if n := counts[s]; n > 0 {
+				total += n
+				parts = append(parts, fmt.Sprintf("%s %d", strings.ToLower(s), n))
+			}
+		}
+		if total == 0 {
+			header.SetText(fmt.Sprintf(" [%s::b]No differences[-:-:-] in image, env, labels, mounts, ports or runtime settings", t.Success))
+			if !all {
+				table.SetCell(1, 0, tview.NewTableCell("Identical; a lists every setting").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+			}
+			return
+		}
+		shown := "differences only"
+		if all {
+			shown = "all settings"
+		}
+		header.SetText(fmt.Sprintf(" [%s::b]%d differences[-:-:-]: %s\n [%s]Showing %s; secret env values are masked unless revealed[-]",
+			t.Warning, total, strings.Join(parts, ", "), t.Muted, shown))
+	}
+
+	header.SetText(fmt.Sprintf(" [%s]Reading both containers...[-]", t.Muted))
+	go func() {
+		a, errA := docker.GetContainerConfig(containers[0].ID)
+		b, errB := docker.GetContainerConfig(containers[1].ID)
+		app.QueueUpdateDraw(func() {
+			for _, err := range []error{errA, errB} {
+				if err != nil {
+					header.SetText(fmt.Sprintf(" [%s]Reading the containers failed:[-] %s", t.Error, tview.Escape(errorSummary(err))))
+					return
+				}
+			}
+			entries = docker.DiffConfigs(a, b)
+			render()
+		})
+	}()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 || event.Rune() == 'q':
+			setRoot(app, mainView)
+			return nil
+		case event.Rune() == 'a':
+			all = !all
+			render()
+			return nil
+		case event.Rune() == 'r':
+			reveal = !reveal
+			render()
+			return nil
+		case event.Rune() == 'y':
+			if entries != nil {
+				copyToClipboard(app, configDiffReport(left, right, entries, reveal))
+				showToast(app, toastSuccess, "Diff report copied")
+			}
+			return nil
+		}
+		return event
+	})
+
+	setRoot(app, flex)
+	app.SetFocus(table)
+}
+
+// configDiffReport writes the differences as plain text, one section after another
+func configDiffReport(left, right string, entries []docker.ConfigDiffEntry, reveal bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Config diff: %s <> %s\n", left, right)
+	section := ""
+	for _, e := range entries {
+		if e.Same() {
+			continue
+		}
+		if e.Section != section {
+			section = e.Section
+			fmt.Fprintf(&b, "\n[%s]\n", section)
+		}
+		side := func(v string, has bool) string {
+			if !has {
+				return "(not set)"
+			}
+			if e.Section == docker.DiffEnv {
+				return maskEnv(e.Key, v, reveal)
+			}
+			return v
+		}
+		fmt.Fprintf(&b, "%s\n  %s: %s\n  %s: %s\n", e.Key, left, side(e.Left, e.HasLeft), right, side(e.Right, e.HasRight))
+	}
+	if section == "" {
+		b.WriteString("\nNo differences\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+const (
+	pingInterval = 5 * time.Second  // between checks while the daemon is reachable
+	pingTimeout  = 3 * time.Second  // before a check counts as failed
+	maxBackoff   = 30 * time.Second // cap on the delay between reconnect attempts
+)
+
+// connection tracks whether the Docker daemon is reachable; the dashboard runs it and
+// any view streaming from the daemon can wait on it to resume
+var connection = newConnectionWatchdog()
+
+// connectionState is a snapshot of the watchdog for rendering
+type connectionState struct {
+	online    bool
+	err       error
+	downSince time.Time // when the daemon became unreachable
+	attempt   int       // failed reconnect attempts so far
+	retryAt   time.Time // when the next attempt runs
+}
+
+// connectionWatchdog pings the daemon and retries with exponential backoff while it is down
+type connectionWatchdog struct {
+	mu    sync.Mutex
+	state connectionState
+	back  chan struct{} // closed when the daemon becomes reachable again
+}
+
+func newConnectionWatchdog() *connectionWatchdog {
+	return &connectionWatchdog{
+		state: connectionState{online: true},
+		back:  make(chan struct{}),
+	}
+}
+
+// Online reports whether the last check reached the daemon
+func (w *connectionWatchdog) Online() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state.online
+}
+
+// State returns a snapshot of the connection state
+func (w *connectionWatchdog) State() connectionState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// WaitOnline blocks until the daemon is reachable or ctx is done
+func (w *connectionWatchdog) WaitOnline(ctx context.Context) error {
+	w.mu.Lock()
+	if w.state.online {
+		w.mu.Unlock()
+		return nil
+	}
+	back := w.back
+	w.mu.Unlock()
+
+	select {
+	case <-back:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run checks the daemon until ctx is done. onChange is called after every failed check,
+// once a second while waiting to retry, and when the daemon comes back.
+func (w *connectionWatchdog) run(ctx context.Context, onChange func(connectionState)) {
+	for {
+		err := docker.Ping(pingTimeout)
+
+		w.mu.Lock()
+		wasOnline := w.state.online
+		if err == nil {
+			if !wasOnline {
+				close(w.back)
+				w.back = make(chan struct{})
+			}
+			w.state = connectionState{online: true}
+		} else {
+			if wasOnline {
+				w.state.downSince = time.Now()
+			}
+			w.state.online = false
+			w.state.err = err
+			w.state.retryAt = time.Now().Add(backoff(w.state.attempt))
+			w.state.attempt++
+		}
+		state := w.state
+		w.mu.Unlock()
+
+		if err == nil {
+			if !wasOnline {
+				onChange(state)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pingInterval):
+			}
+			continue
+		}
+
+		// Count down to the next attempt so the banner stays current
+		for time.Now().Before(state.retryAt) {
+			onChange(state)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+// backoff returns the delay before reconnect attempt n: 1s, 2s, 4s, ... up to maxBackoff
+func backoff(attempt int) time.Duration {
+	if attempt > 5 {
+		return maxBackoff
+	}
+	if d := time.Second << attempt; d < maxBackoff {
+		return d
+	}
+	return maxBackoff
+}
+
+// startConnectionWatchdog runs the watchdog and keeps the offline banner in sync with it
+func (d *Dashboard) startConnectionWatchdog() {
+	go connection.run(d.refreshCtx, func(state connectionState) {
+		d.app.QueueUpdateDraw(func() {
+			d.renderConnection(state)
+		})
+	})
+}
+
+// renderConnection shows or hides the offline banner; coming back online refreshes
+// everything that went stale
+func (d *Dashboard) renderConnection(state connectionState) {
+	t := currentTheme()
+	if !state.online {
+		retryIn := time.Until(state.retryAt).Round(time.Second)
+		if retryIn < 0 {
+			retryIn = 0
+		}
+		d.offlineBanner.SetText(fmt.Sprintf(
+			"[%s:%s:b] ⚠ Docker daemon offline for %s [-:-:-][%s] %s │ retry #%d in %s │ showing last known data │ %s diagnostics",
+			t.Background, t.Error, time.Since(state.downSince).Round(time.Second),
+			t.Warning, errorSummary(state.err), state.attempt, retryIn, d.keys.KeyLabel(actionDiagnostics)))
+		d.mainFlex.ResizeItem(d.offlineBanner, 1, 0)
+		return
+	}
+
+	d.mainFlex.ResizeItem(d.offlineBanner, 0, 0)
+	d.updateList()
+	d.updateStatusBar()
+	d.flashStatus(fmt.Sprintf("[%s]✓ Reconnected to Docker[-]", t.Success))
+	if tab := d.tabs[d.currentTab]; tab.onShow != nil {
+		tab.onShow()
+	}
+}
@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// otherHost is the destination choice for a host typed in by hand
+const otherHost = "Other host…"
+
+// showConnectivityTest checks from inside a container whether another container, or a
+// host, can be reached: a ping without a port, a TCP connect with one
+func showConnectivityTest(app *tview.Application, mainView tview.Primitive, containerID, containerName string, containers []docker.ContainerInfo, shell docker.Shell) {
+	var targets []docker.ContainerInfo
+	var options []string
+	for _, c := range containers {
+		if c.ID != containerID && c.State == "running" {
+			targets = append(targets, c)
+			options = append(options, c.Name)
+		}
+	}
+	options = append(options, otherHost)
+
+	hostInput := tview.NewInputField().
+		SetLabel("Host: ").
+		SetPlaceholder("name or IP, for Other host").
+		SetFieldWidth(40)
+	portInput := tview.NewInputField().
+		SetLabel("Port: ").
+		SetPlaceholder("empty to ping").
+		SetAcceptanceFunc(tview.InputFieldInteger).
+		SetFieldWidth(8)
+	destination := tview.NewDropDown().
+		SetLabel("Destination: ").
+		SetOptions(options, nil).
+		SetCurrentOption(0)
+
+	form := tview.NewForm().
+		AddFormItem(destination).
+		AddFormItem(hostInput).
+		AddFormItem(portInput)
+	form.AddButton("Test", func() {
+		index, _ := destination.GetCurrentOption()
+		host := strings.TrimSpace(hostInput.GetText())
+		var target *docker.ContainerInfo
+		if index >= 0 && index < len(targets) {
+			target = &targets[index]
+			host = target.Name
+		}
+		if host == "" {
+			return
+		}
+		port := strings.TrimSpace(portInput.GetText())
+
+		modal := tview.NewModal().
+			SetText(fmt.Sprintf("Testing %s → %s...\n\nPlease wait...", containerName, hostPort(host, port)))
+		modal.SetBorder(true).SetTitle(" ⏳ Connectivity ")
+		setRoot(app, modal)
+
+		go func() {
+			report := runConnectivityTest(containerID, shell, host, port, target)
+			app.QueueUpdateDraw(func() {
+				showMessage(app, mainView, "🔗 Connectivity", report)
+			})
+		}()
+	})
+	form.AddButton("Cancel", func() {
+		setRoot(app, mainView)
+	})
+	form.SetCancelFunc(func() {
+		setRoot(app, mainView)
+	})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔗 Connectivity Test from %s ", containerName)).
+		SetBorderColor(ColorCyan)
+
+	setRoot(app, form)
+}
+
+// hostPort joins a host and an optional port
+func hostPort(host, port string) string {
+	if port == "" {
+		return host
+	}
+	return host + ":" + port
+}
+
+// runConnectivityTest probes a destination and describes the outcome. A container whose
+// name doesn't resolve is tried again by IP, which tells DNS problems from network ones.
+func runConnectivityTest(containerID string, shell docker.Shell, host, port string, target *docker.ContainerInfo) string {
+	t := currentTheme()
+	result, err := docker.Probe(containerID, shell, host, port)
+	if err != nil {
+		return fmt.Sprintf("[%s]Test failed:[-] %s", t.Error, errorSummary(err))
+	}
+
+	var b strings.Builder
+	b.WriteString(describeProbe(result))
+
+	if target != nil && result.DNSError {
+		details, err := docker.InspectStructured(target.ID)
+		if err == nil {
+			for _, n := range details.Networks {
+				if n.IPAddress == "" {
+					continue
+				}
+				byIP, err := docker.Probe(containerID, shell, n.IPAddress, port)
+				if err != nil {
+					break
+				}
+				fmt.Fprintf(&b, "\n\n[%s]By IP on %s:[-]\n%s", t.Muted, n.Network, describeProbe(byIP))
+				if byIP.OK {
+					fmt.Fprintf(&b, "\n\n[%s]The name doesn't resolve but the IP is reachable: containers on the default bridge network can't resolve each other by name; put both on a user-defined network.[-]", t.Warning)
+				}
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// describeProbe renders one probe result
+func describeProbe(r *docker.ProbeResult) string {
+	t := currentTheme()
+	var lines []string
+
+	switch {
+	case r.NoDNS:
+		lines = append(lines, fmt.Sprintf("DNS: [%s]no getent or nslookup in the image[-]", t.Muted))
+	case r.DNSError:
+		lines = append(lines, fmt.Sprintf("DNS: [%s]✗ %s doesn't resolve[-]", t.Error, r.Host))
+	case r.Resolved != "":
+		lines = append(lines, fmt.Sprintf("DNS: [%s]✓ %s → %s[-]", t.Success, r.Host, r.Resolved))
+	}
+
+	check := "TCP " + hostPort(r.Host, r.Port)
+	if r.Port == "" {
+		check = "Ping " + r.Host
+	}
+	switch {
+	case r.Tool == "":
+		lines = append(lines, fmt.Sprintf("%s: [%s]no ping, nc, curl, bash or wget in the image to test with[-]", check, t.Warning))
+	case r.OK:
+		latency := ""
+		if r.Latency > 0 {
+			latency = fmt.Sprintf(" in %s", r.Latency.Round(10*time.Microsecond))
+		}
+		lines = append(lines, fmt.Sprintf("%s: [%s]✓ reachable%s[-] [%s](%s)[-]", check, t.Success, latency, t.Muted, r.Tool))
+	default:
+		lines = append(lines, fmt.Sprintf("%s: [%s]✗ %s[-] [%s](%s)[-]", check, t.Error, tview.Escape(r.Reason), t.Muted, r.Tool))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,101 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/notes"
+)
+
+// openNotes opens the notes file and loads the notes; a file that can't be read only
+// leaves the notes empty until one is saved
+func (d *Dashboard) openNotes(cfg *config.Config) {
+	path := cfg.NotesFile
+	if path == "" {
+		path = notes.DefaultPath()
+	}
+	d.notes = notes.Open(path)
+	d.noteTexts, _ = d.notes.All()
+}
+
+// noteKey is the key of a container's note, see notes.Key
+func noteKey(c docker.ContainerInfo) string {
+	return notes.Key(hostLabel(c), c.Name, c.Labels)
+}
+
+// noteFor returns the note attached to a container, "" when there is none
+func (d *Dashboard) noteFor(c docker.ContainerInfo) string {
+	return d.noteTexts[noteKey(c)].Text
+}
+
+// noteDetails shows the note of a container in the details panel
+func (d *Dashboard) noteDetails(c docker.ContainerInfo) string {
+	note := d.noteFor(c)
+	if note == "" {
+		return ""
+	}
+	t := currentTheme()
+	return fmt.Sprintf("\n\n[%s::b]📝 Note:[-:-:-] [%s](%s ago)[-]\n%s",
+		t.Highlight, t.Muted, formatAge(d.noteTexts[noteKey(c)].Updated), tview.Escape(note))
+}
+
+// remindNote puts the note of a container in a toast before it is stopped, restarted
+// or deleted, so warnings like "don't restart during business hours" are seen
+func (d *Dashboard) remindNote(c docker.ContainerInfo) {
+	if note := d.noteFor(c); note != "" {
+		showToast(d.app, toastWarning, fmt.Sprintf("📝 %s: %s", c.Name, truncateString(strings.Join(strings.Fields(note), " "), 120)))
+	}
+}
+
+// showNoteEditor edits the note of a container. Notes follow the compose service, or
+// the name for other containers, so they survive the container being recreated.
+func (d *Dashboard) showNoteEditor(c docker.ContainerInfo) {
+	key := noteKey(c)
+	text := tview.NewTextArea().
+		SetLabel("Note").
+		SetText(d.noteTexts[key].Text, true).
+		SetPlaceholder("e.g. don't restart during business hours, owned by the payments team").
+		SetSize(8, 0)
+
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+	}
+	form := tview.NewForm().
+		AddFormItem(text)
+	form.AddButton("Save", func() {
+		note := strings.TrimSpace(text.GetText())
+		if err := d.notes.Set(key, note); err != nil {
+			showError(d.app, form, "Note Not Saved", err)
+			return
+		}
+		// Reload so notes saved by another instance show up too
+		if all, err := d.notes.All(); err == nil {
+			d.noteTexts = all
+		}
+		back()
+		d.updateList()
+		if note == "" {
+			d.flashStatus(fmt.Sprintf("[%s]Note removed from %s[-]", currentTheme().Success, tview.Escape(c.Name)))
+			return
+		}
+		d.flashStatus(fmt.Sprintf("[%s]Note saved for %s[-]", currentTheme().Success, tview.Escape(c.Name)))
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	scope := "container " + c.Name
+	if service, ok := notes.Service(c.Labels); ok {
+		scope = "service " + service
+	}
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📝 Note: %s (empty to remove) ", tview.Escape(scope))).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+
+	showOverlay(d.app, d.mainFlex, form, 80, 16)
+}
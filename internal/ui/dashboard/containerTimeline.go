@@ -0,0 +1,186 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// healthStatusAction prefixes the health events, e.g. "health_status: unhealthy"
+const healthStatusAction = "health_status"
+
+// timelineActions are the container events the timeline keeps, with their icon and
+// wording; kill, exec and attach events only add noise
+var timelineActions = map[string][2]string{
+	"create":           {"✨", "created"},
+	"start":            {"▶", "started"},
+	"restart":          {"🔄", "restarted"},
+	"die":              {"⏹", "exited"},
+	"oom":              {"💥", "out of memory"},
+	"stop":             {"⏸", "stopped"},
+	"pause":            {"⏸", "paused"},
+	"unpause":          {"▶", "unpaused"},
+	"update":           {"🛠", "resources updated"},
+	"rename":           {"✏", "renamed"},
+	"destroy":          {"🗑", "removed"},
+	healthStatusAction: {"❤", "health"},
+}
+
+// timelineRanges are selected with 1-4 in the timeline; events are kept as long as
+// the uptime history
+var timelineRanges = []historyRange{
+	{"6h", 6 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// recordTimeline keeps a container event for the lifecycle timeline
+func (d *Dashboard) recordTimeline(event docker.EventInfo) {
+	action, detail, _ := strings.Cut(event.Action, ": ")
+	if _, ok := timelineActions[action]; d.history == nil || event.Type != "container" || !ok || event.Name == "" {
+		return
+	}
+	switch action {
+	case "die":
+		if code := event.Attributes["exitCode"]; code != "" {
+			detail = "exit code " + code
+		}
+	case "rename":
+		detail = "from " + strings.TrimPrefix(event.Attributes["oldName"], "/")
+	}
+	d.history.RecordEvent(hostLabel(docker.ContainerInfo{Host: event.Host}), event.Name, history.Event{
+		Time:   event.Time,
+		ID:     event.ActorID,
+		Action: action,
+		Image:  event.Attributes["image"],
+		Detail: detail,
+	})
+}
+
+// showTimeline shows what happened to a container over time: creations, starts,
+// exits, restarts, OOM kills, health flips and image updates, across recreations of
+// the container under the same name
+func (d *Dashboard) showTimeline(container docker.ContainerInfo) {
+	if d.history == nil {
+		msg := "The timeline is kept with the stats history, which is disabled (history_retention is \"off\")."
+		if d.historyErr != nil {
+			msg = fmt.Sprintf("The timeline is kept with the stats history, which is unavailable:\n\n%s", d.historyErr)
+		}
+		showMessage(d.app, d.mainFlex, "🧭 Timeline", msg)
+		return
+	}
+	d.showRangeScreen(timelineRanges, 1, func(view *tview.TextView, r historyRange) {
+		view.SetTitle(fmt.Sprintf(" 🧭 Timeline: %s (last %s) ", qualifiedName(container), r.label))
+
+		events, err := d.history.Timeline(hostLabel(container), container.Name, time.Now().Add(-r.period))
+		if err != nil {
+			view.SetText(fmt.Sprintf("[%s]Error:[-] %s", currentTheme().Error, tview.Escape(err.Error())))
+			return
+		}
+		view.SetText(renderTimeline(events))
+		view.ScrollToEnd()
+	})
+}
+
+// renderTimeline lists events by day, oldest first, after a summary of what went wrong.
+// A container created from another image than the one before it shows as an image update.
+func renderTimeline(events []history.Event) string {
+	t := currentTheme()
+	if len(events) == 0 {
+		return fmt.Sprintf("[%s]No events recorded in this range yet.[-]\n\n", t.Warning) +
+			fmt.Sprintf("[%s]DockPulse records container events while it is open.[-]", t.Muted)
+	}
+
+	var restarts, crashes, ooms, unhealthy, updates int
+	var b strings.Builder
+	var day, image, id string
+	for _, e := range events {
+		at := e.Time.Local()
+		if d := at.Format("Mon 2 Jan 2006"); d != day {
+			if day != "" {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "[%s::b]%s[-:-:-]\n", t.Accent, d)
+			day = d
+		}
+
+		style := timelineActions[e.Action]
+		color := t.Muted
+		text := style[1]
+		switch e.Action {
+		case "create":
+			color = t.Info
+			switch {
+			case image != "" && e.Image != image:
+				updates++
+				color = t.Highlight
+				text = fmt.Sprintf("image updated: %s → %s", tview.Escape(image), tview.Escape(e.Image))
+				style[0] = "⬆"
+			case id != "" && e.ID != id:
+				text = "recreated from " + tview.Escape(e.Image)
+			default:
+				text += " from " + tview.Escape(e.Image)
+			}
+			image = e.Image
+		case "start", "unpause":
+			color = t.Success
+		case "restart":
+			restarts++
+			color = t.Warning
+		case "die":
+			if e.Detail != "" && e.Detail != "exit code 0" {
+				crashes++
+				color = t.Error
+			}
+		case "oom":
+			ooms++
+			color = t.Error
+		case healthStatusAction:
+			text = e.Detail
+			color = t.Success
+			if e.Detail == "unhealthy" {
+				unhealthy++
+				color = t.Error
+			}
+		}
+		if e.Detail != "" && e.Action != healthStatusAction {
+			text += " · " + tview.Escape(e.Detail)
+		}
+		id = e.ID
+
+		fmt.Fprintf(&b, "  [%s]%s[-]  %s [%s]%s[-]  [%s]%s[-]\n",
+			t.Muted, at.Format("15:04:05"), style[0], color, text, t.Muted, shortID(e.ID))
+	}
+
+	summary := fmt.Sprintf("[%s]%d events[-]", t.Muted, len(events))
+	for _, count := range []struct {
+		n     int
+		label string
+		color string
+	}{
+		{crashes, "non-zero exits", t.Error},
+		{ooms, "OOM kills", t.Error},
+		{unhealthy, "times unhealthy", t.Error},
+		{restarts, "restarts", t.Warning},
+		{updates, "image updates", t.Highlight},
+	} {
+		if count.n > 0 {
+			summary += fmt.Sprintf(" · [%s::b]%d[-:-:-] %s", count.color, count.n, count.label)
+		}
+	}
+	return summary + "\n\n" + b.String()
+}
+
+// shortID shortens a container ID the way the list shows it
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
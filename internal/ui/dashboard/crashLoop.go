@@ -0,0 +1,168 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// crashHistorySize is how many exits are remembered per container
+const crashHistorySize = 20
+
+// containerExit is one death of a container, from a die event
+type containerExit struct {
+	at   time.Time
+	code int // -1 when the event didn't carry one
+}
+
+// CrashTracker follows die events to keep the recent exits of every container and to
+// flag containers that die too often
+type CrashTracker struct {
+	mu       sync.Mutex
+	restarts int
+	window   time.Duration
+	exits    map[string][]containerExit // container ID -> oldest first
+	looping  map[string]bool            // containers already alerted about
+	killed   map[string]bool            // containers being stopped or restarted by someone
+}
+
+// NewCrashTracker flags containers that die restarts times within window
+func NewCrashTracker(restarts int, window time.Duration) *CrashTracker {
+	return &CrashTracker{
+		restarts: restarts,
+		window:   window,
+		exits:    make(map[string][]containerExit),
+		looping:  make(map[string]bool),
+		killed:   make(map[string]bool),
+	}
+}
+
+// Record follows a container event and reports whether it put the container into a
+// crash loop. Only the first die event of a loop reports true, so a loop alerts once.
+// Containers dying after a kill, as docker stop and docker restart do, don't count.
+func (c *CrashTracker) Record(event docker.EventInfo) bool {
+	if event.Type != "container" {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch event.Action {
+	case "destroy":
+		delete(c.exits, event.ActorID)
+		delete(c.looping, event.ActorID)
+		delete(c.killed, event.ActorID)
+		return false
+	case "kill":
+		c.killed[event.ActorID] = true
+		return false
+	case "die":
+		if c.killed[event.ActorID] {
+			delete(c.killed, event.ActorID)
+			return false
+		}
+	default:
+		return false
+	}
+
+	code := -1
+	if n, err := strconv.Atoi(event.Attributes["exitCode"]); err == nil {
+		code = n
+	}
+	exits := append(c.exits[event.ActorID], containerExit{at: event.Time, code: code})
+	if len(exits) > crashHistorySize {
+		exits = exits[len(exits)-crashHistorySize:]
+	}
+	c.exits[event.ActorID] = exits
+
+	if c.recentLocked(event.ActorID, event.Time) < c.restarts {
+		c.looping[event.ActorID] = false
+		return false
+	}
+	if c.looping[event.ActorID] {
+		return false
+	}
+	c.looping[event.ActorID] = true
+	return true
+}
+
+// recentLocked counts the exits within the window before now; c.mu must be held
+func (c *CrashTracker) recentLocked(id string, now time.Time) int {
+	n := 0
+	for _, e := range c.exits[id] {
+		if now.Sub(e.at) <= c.window {
+			n++
+		}
+	}
+	return n
+}
+
+// InLoop reports whether a container died often enough within the window up to now
+func (c *CrashTracker) InLoop(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recentLocked(id, time.Now()) >= c.restarts
+}
+
+// Summary describes a crash loop for an alert, e.g. "4 restarts in 5m, last exit code 1"
+func (c *CrashTracker) Summary(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exits := c.exits[id]
+	if len(exits) == 0 {
+		return ""
+	}
+	summary := fmt.Sprintf("%d restarts in %s", c.recentLocked(id, time.Now()), formatWindow(c.window))
+	if last := exits[len(exits)-1]; last.code >= 0 {
+		summary += fmt.Sprintf(", last exit code %d", last.code)
+	}
+	return summary
+}
+
+// ExitCodes lists the remembered exit codes of a container, oldest first; "?" marks
+// exits without a code
+func (c *CrashTracker) ExitCodes(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exits := c.exits[id]
+	if len(exits) == 0 {
+		return ""
+	}
+	codes := make([]string, len(exits))
+	for i, e := range exits {
+		codes[i] = "?"
+		if e.code >= 0 {
+			codes[i] = strconv.Itoa(e.code)
+		}
+	}
+	return strings.Join(codes, " ")
+}
+
+// formatWindow prints a window without zero units, e.g. 5m instead of 5m0s
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// crashLoopAlert warns about a container that entered a crash loop; it must be called
+// on the UI goroutine
+func (d *Dashboard) crashLoopAlert(event docker.EventInfo) {
+	name := event.Name
+	if d.allHostsMode() {
+		name = event.Host + "/" + name
+	}
+	summary := d.crashes.Summary(event.ActorID)
+	showToast(d.app, toastWarning, fmt.Sprintf("%s is crash-looping: %s", name, summary))
+	d.logAlert(event.Time, history.SeverityCritical, "crash loop", name, summary)
+}
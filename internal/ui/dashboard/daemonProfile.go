@@ -0,0 +1,51 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"devops-dashboard/internal/docker"
+)
+
+// loadDaemonProfiles learns in the background how the current daemon, and every
+// configured host, is set up, so what a rootless daemon can't do is left out
+func (d *Dashboard) loadDaemonProfiles() {
+	endpoints := append([]docker.Endpoint{docker.CurrentEndpoint()}, d.hostEndpoints()...)
+	go func() {
+		for _, e := range endpoints {
+			docker.LoadProfile(e)
+		}
+		d.app.QueueUpdateDraw(func() {
+			d.updateSystemInfo()
+		})
+	}()
+}
+
+// rootlessNote renders the system info line of a rootless current daemon
+func rootlessNote() string {
+	p, ok := docker.CachedProfile(docker.CurrentEndpoint())
+	if !ok || !p.Rootless {
+		return ""
+	}
+	t := currentTheme()
+	note := fmt.Sprintf("[%s::b]Rootless:[-:-:-] cgroup v%s", t.Warning, p.CgroupVersion)
+	switch {
+	case !p.HasStats():
+		note += fmt.Sprintf(" [%s](no stats)[-]", t.Muted)
+	case !p.CanLimit():
+		note += fmt.Sprintf(" [%s](no limits)[-]", t.Muted)
+	}
+	return note + "\n"
+}
+
+// statsUnavailable explains why a container shows no stats, or returns "" when its
+// daemon reports them
+func statsUnavailable(containerID string) string {
+	p, ok := docker.ProfileOf(containerID)
+	if !ok || p.HasStats() {
+		return ""
+	}
+	t := currentTheme()
+	return fmt.Sprintf("[%s]Live stats are unavailable:[-] the daemon runs rootless on cgroup v1, "+
+		"which gives it no access to container usage.\n\n[%s]Boot with systemd.unified_cgroup_hierarchy=1 to use cgroup v2; F2 has details.[-]",
+		t.Warning, t.Muted)
+}
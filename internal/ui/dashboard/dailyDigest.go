@@ -0,0 +1,111 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/digest"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// diskSampleInterval is how often the disk usage of the daemons is recorded for the
+// digest; docker system df is too slow on big hosts to run more often
+const diskSampleInterval = time.Hour
+
+// startDigest records the disk usage of the daemons and sends the daily digest at the
+// configured time while the dashboard runs
+func (d *Dashboard) startDigest() {
+	if d.cfg.Digest == nil || d.history == nil {
+		return
+	}
+
+	go func() {
+		recordDisk(d.history, d.digestEndpoints())
+		ticker := time.NewTicker(diskSampleInterval)
+		defer ticker.Stop()
+		timer := time.NewTimer(time.Until(digest.Next(d.cfg.Digest, time.Now())))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+				recordDisk(d.history, d.digestEndpoints())
+			case now := <-timer.C:
+				timer.Reset(time.Until(digest.Next(d.cfg.Digest, now)))
+				recordDisk(d.history, d.digestEndpoints())
+				summary, err := sendDigest(d.refreshCtx, d.cfg, d.history, now)
+				if d.refreshCtx.Err() != nil {
+					return
+				}
+				d.app.QueueUpdateDraw(func() {
+					if err != nil {
+						d.logAlert(time.Now(), history.SeverityCritical, "digest failed", "", errorSummary(err))
+						return
+					}
+					d.logAlert(time.Now(), history.SeverityInfo, "digest sent", "", summary.Headline())
+				})
+			}
+		}
+	}()
+}
+
+// digestEndpoints returns the daemons whose disk usage goes into the digest: every host
+// in all-hosts mode, the current one otherwise
+func (d *Dashboard) digestEndpoints() []docker.Endpoint {
+	if d.allHostsMode() {
+		return d.hostEndpoints()
+	}
+	return []docker.Endpoint{docker.CurrentEndpoint()}
+}
+
+// recordDisk stores the disk usage of every endpoint; unreachable ones are skipped
+func recordDisk(store *history.Store, endpoints []docker.Endpoint) {
+	now := time.Now()
+	for _, e := range endpoints {
+		usage, err := docker.DiskUsageOn(e)
+		if err != nil {
+			continue
+		}
+		store.RecordDisk(hostLabel(docker.ContainerInfo{Host: e.Name}), history.DiskSample{
+			Time:       now,
+			Images:     usage.Images,
+			Containers: usage.Containers,
+			Volumes:    usage.Volumes,
+			BuildCache: usage.BuildCache,
+		})
+	}
+}
+
+// sendDigest summarises the period of history up to now and delivers it
+func sendDigest(ctx context.Context, cfg *config.Config, store *history.Store, now time.Time) (digest.Summary, error) {
+	summary, err := digest.Build(store, now.Add(-digest.Period), now)
+	if err != nil {
+		return summary, err
+	}
+	return summary, digest.Send(ctx, cfg.Digest, summary)
+}
+
+// SendDigest sends the digest of the last day right away, for --send-digest. It needs
+// the history file, so it can't run next to a dashboard holding it open.
+func SendDigest(cfg *config.Config) (string, error) {
+	if cfg.Digest == nil {
+		return "", fmt.Errorf("no digest configured; add \"digest\" to %s", config.Path())
+	}
+	store, err := openHistory(cfg)
+	if err != nil {
+		return "", err
+	}
+	if store == nil {
+		return "", fmt.Errorf("the digest needs the history, which history_retention \"off\" disables")
+	}
+	defer store.Close()
+
+	recordDisk(store, []docker.Endpoint{docker.CurrentEndpoint()})
+	summary, err := sendDigest(context.Background(), cfg, store, time.Now())
+	return summary.Headline(), err
+}
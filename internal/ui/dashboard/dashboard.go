@@ -9,27 +9,84 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"devops-dashboard/internal/api"
+	"devops-dashboard/internal/config"
 	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+	"devops-dashboard/internal/logarchive"
+	"devops-dashboard/internal/notes"
 )
 
 type Dashboard struct {
-	app           *tview.Application
-	containers    []docker.ContainerInfo
-	selectedIndex int
-	statsCtx      context.Context
-	statsCancel   context.CancelFunc
-	refreshCtx    context.Context
-	refreshCancel context.CancelFunc
-	mu            sync.RWMutex
-	list          *tview.List
-	detailsText   *tview.TextView
-	statsText     *tview.TextView
-	systemInfo    *tview.TextView
-	bulkMode      *BulkOperationMode
-	statsHistory  *StatsHistory
-	mainFlex      *tview.Flex
+	app            *tview.Application
+	cfg            *config.Config
+	activeView     *config.View
+	healthFilter   string // health strip segment filtering the list, "" for none
+	containers     []docker.ContainerInfo
+	listedAt       time.Time // when the containers were fetched from the daemon
+	selectedIndex  int
+	statsCtx       context.Context
+	statsCancel    context.CancelFunc
+	refreshCtx     context.Context
+	refreshCancel  context.CancelFunc
+	eventsCancel   context.CancelFunc
+	allHosts       bool                // list containers from every configured host
+	hostResults    []docker.HostResult // per-host outcome of the last all hosts listing
+	mu             sync.RWMutex
+	list           *tview.List
+	detailsText    *tview.TextView
+	statsText      *tview.TextView
+	systemInfo     *tview.TextView
+	bulkMode       *BulkOperationMode
+	grouping       *LabelGrouping
+	rows           []listRow
+	statsHistory   *StatsHistory
+	ioRates        *IORates
+	statsCollector *StatsCollector
+	sizes          *SizeCache
+	logFiles       *LogFileCache
+	crashes        *CrashTracker
+	ooms           *OOMTracker
+	exitWatches    *ExitWatches
+	follow         *FollowRules
+	probes         *ProbeBoard
+	recent         *RecentContainers
+	notes          *notes.Store
+	noteTexts      map[string]notes.Note // by notes.Key, only used on the UI goroutine
+	remediation    *Remediator
+	trends         *TrendCache
+	listSort       listSort
+	history        *history.Store // nil when disabled or unavailable
+	historyErr     error
+	archiver       *logarchive.Archiver
+	api            *api.Server // nil unless the API is configured
+	mainFlex       *tview.Flex
+	containersView *tview.Flex
+	health         *healthStrip
+	sidePanel      *tview.Flex
+	layout         layoutMode
+	pages          *tview.Pages
+	tabs           []tabPage
+	currentTab     int
+	tabBar         *tview.TextView
+	statusBar      *tview.TextView
+	offlineBanner  *tview.TextView
+	eventsView     *tview.TextView
+	alerts         []alert // recent alerts of the Events tab, oldest first; UI goroutine only
+	themes         []Theme
+	themeIndex     int
+	keys           *KeyMap
 }
 
+// listRow maps a list item to a container or, for grouped lists, a group header
+type listRow struct {
+	containerIndex int // -1 for group headers
+	group          string
+}
+
+// sidePanelSamples is how many samples the side panel keeps, enough for its widest graphs
+const sidePanelSamples = 120
+
 type StatsHistory struct {
 	cpuHistory []float64
 	memHistory []float64
@@ -38,48 +95,48 @@ type StatsHistory struct {
 
 func NewStatsHistory() *StatsHistory {
 	return &StatsHistory{
-		cpuHistory: make([]float64, 0, 30),
-		memHistory: make([]float64, 0, 30),
+		cpuHistory: make([]float64, 0, sidePanelSamples),
+		memHistory: make([]float64, 0, sidePanelSamples),
 	}
 }
 
 func (sh *StatsHistory) AddCPU(value float64) {
 	sh.mu.Lock()
 	defer sh.mu.Unlock()
-	sh.cpuHistory = append(sh.cpuHistory, value)
-	if len(sh.cpuHistory) > 30 {
-		sh.cpuHistory = sh.cpuHistory[1:]
-	}
+	sh.cpuHistory = appendSample(sh.cpuHistory, value, sidePanelSamples)
 }
 
 func (sh *StatsHistory) AddMem(value float64) {
 	sh.mu.Lock()
 	defer sh.mu.Unlock()
-	sh.memHistory = append(sh.memHistory, value)
-	if len(sh.memHistory) > 30 {
-		sh.memHistory = sh.memHistory[1:]
-	}
+	sh.memHistory = appendSample(sh.memHistory, value, sidePanelSamples)
 }
 
-func (sh *StatsHistory) GetCPUGraph() string {
+// GetCPUGraph renders the latest CPU samples as a sparkline of up to width columns
+func (sh *StatsHistory) GetCPUGraph(width int) string {
 	sh.mu.RLock()
 	defer sh.mu.RUnlock()
 	if len(sh.cpuHistory) == 0 {
 		return ""
 	}
-	return createMiniGraph(sh.cpuHistory, 30)
+	return createMiniGraph(sh.cpuHistory, width)
 }
 
-func (sh *StatsHistory) GetMemGraph() string {
+// GetMemGraph renders the latest memory samples as a sparkline of up to width columns
+func (sh *StatsHistory) GetMemGraph(width int) string {
 	sh.mu.RLock()
 	defer sh.mu.RUnlock()
 	if len(sh.memHistory) == 0 {
 		return ""
 	}
-	return createMiniGraph(sh.memHistory, 30)
+	return createMiniGraph(sh.memHistory, width)
 }
 
+// createMiniGraph draws the last width values of data as a sparkline scaled to their maximum
 func createMiniGraph(data []float64, width int) string {
+	if len(data) > width {
+		data = data[len(data)-width:]
+	}
 	if len(data) == 0 {
 		return ""
 	}
@@ -112,23 +169,72 @@ func createMiniGraph(data []float64, width int) string {
 	return graph
 }
 
-func NewDashboardUI() (*tview.Application, error) {
+func NewDashboardUI(cfg *config.Config) (*tview.Application, error) {
+	themes, err := loadThemes(cfg)
+	if err != nil {
+		return nil, err
+	}
+	themeIndex, err := findTheme(themes, cfg.Theme)
+	if err != nil {
+		return nil, err
+	}
+	applyTheme(themes[themeIndex])
+	setLogLevels(cfg)
+	setShellHistory(cfg)
+	setShellTerminal(cfg)
+	setSecretEnv(cfg)
+	setTrash(cfg)
+	setHooks(cfg)
+	setProtected(cfg)
+	if err := setStderrColor(cfg); err != nil {
+		return nil, err
+	}
+
+	keys, err := NewKeyMap(cfg.Keybindings)
+	if err != nil {
+		return nil, err
+	}
+
 	d := &Dashboard{
 		app:          tview.NewApplication(),
+		cfg:          cfg,
 		bulkMode:     NewBulkOperationMode(),
+		grouping:     NewLabelGrouping(),
 		statsHistory: NewStatsHistory(),
+		ioRates:      NewIORates(sidePanelSamples),
+		themes:       themes,
+		themeIndex:   themeIndex,
+		keys:         keys,
 	}
 
+	d.bulkMode.SetConcurrency(cfg.BulkConcurrency)
+	d.crashes = NewCrashTracker(cfg.CrashLoop.Threshold())
+	d.ooms = NewOOMTracker()
+	d.exitWatches = NewExitWatches()
+	d.follow = NewFollowRules(cfg.AutoFollow)
+	d.probes = NewProbeBoard(cfg.Probes)
+	d.recent = &RecentContainers{}
+	d.openNotes(cfg)
+	d.remediation = NewRemediator(cfg.Remediation)
+	d.trends = NewTrendCache()
+	installToasts(d.app)
+
 	d.statsCtx, d.statsCancel = context.WithCancel(context.Background())
 	d.refreshCtx, d.refreshCancel = context.WithCancel(context.Background())
+	d.statsCollector = NewStatsCollector(d.refreshCtx)
+	d.sizes = NewSizeCache()
+	d.logFiles = NewLogFileCache()
+	// A locked or unreadable history file only disables the history screen
+	d.history, d.historyErr = openHistory(cfg)
+	d.archiver = newArchiver(d.refreshCtx, cfg)
 
 	// Container list
 	d.list = tview.NewList().ShowSecondaryText(true)
 	d.list.SetBorder(true).
-		SetTitle(" 🐳 Docker Containers ").
 		SetTitleAlign(tview.AlignCenter).
 		SetBorderPadding(1, 1, 2, 2).
 		SetBorderColor(tcell.ColorDodgerBlue)
+	d.updateListTitle()
 
 	// Details panel
 	d.detailsText = tview.NewTextView().
@@ -149,35 +255,6 @@ func NewDashboardUI() (*tview.Application, error) {
 		SetBorderPadding(0, 0, 1, 1).
 		SetBorderColor(tcell.ColorLime)
 
-	// Actions panel with VISIBLE shortcuts
-	actionsText := tview.NewTextView().
-		SetDynamicColors(true).
-		SetText(
-			"[::b][yellow]Container Actions:[-:-:-]\n\n" +
-				"[white][[lime]l[white]] View Logs\n" +
-				"[white][[cyan]L[white]] Advanced Logs\n" +
-				"[white][[lime]s[white]] Start/Stop\n" +
-				"[white][[lime]r[white]] Restart\n" +
-				"[white][[cyan]t[white]] Real-time Stats\n" +
-				"[white][[blue]i[white]] Inspect\n" +
-				"[white][[magenta]e[white]] Shell Menu\n" +
-				"[white][[orange]h[white]] Health Check\n" +
-				"[white][[red]d[white]] Delete\n\n" +
-				"[::b][cyan]Bulk Operations:[-:-:-]\n" +
-				"[white][[magenta]b[white]] Bulk Mode\n" +
-				"[white][[yellow]SPACE[white]] Select\n" +
-				"[white][[cyan]a[white]] Bulk Actions\n" +
-				"[white][[orange]x[white]] Export Logs\n\n" +
-				"[::b][dodgerblue]Navigation:[-:-:-]\n" +
-				"[white][[lime]↑/↓[white]] Navigate\n" +
-				"[white][[lime]F5[white]] Refresh\n" +
-				"[white][[yellow]Backspace[white]] Back\n" +
-				"[white][[red]q[white]] Quit")
-	actionsText.SetBorder(true).
-		SetTitle(" ⚡ Actions ").
-		SetBorderPadding(0, 0, 1, 1).
-		SetBorderColor(tcell.ColorOrange)
-
 	// System info
 	d.systemInfo = tview.NewTextView().
 		SetDynamicColors(true)
@@ -186,40 +263,57 @@ func NewDashboardUI() (*tview.Application, error) {
 		SetBorderPadding(0, 0, 1, 1).
 		SetBorderColor(tcell.ColorTeal)
 
-	// Layout
-	rightTopPanel := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(d.detailsText, 0, 1, false).
-		AddItem(d.statsText, 14, 0, false)
-
-	rightPanel := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(rightTopPanel, 0, 2, false).
-		AddItem(actionsText, 28, 0, false).
-		AddItem(d.systemInfo, 8, 0, false)
+	// Layout; the panels are arranged by adaptLayout once the terminal size is known
+	d.containersView = tview.NewFlex()
+	d.sidePanel = tview.NewFlex()
+	d.adaptLayout(wideLayoutWidth)
+	d.app.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		width, _ := screen.Size()
+		d.adaptLayout(width)
+		return false
+	})
 
-	d.mainFlex = tview.NewFlex().
-		AddItem(d.list, 0, 2, true).
-		AddItem(rightPanel, 65, 0, false)
+	d.mainFlex = d.buildLayout(d.withHealthStrip(d.containersView))
 
 	if err := d.updateList(); err != nil {
 		return nil, fmt.Errorf("failed to fetch containers: %v", err)
 	}
 
+	d.loadDaemonProfiles()
 	d.startStatsWorker()
 	d.startRefreshWorker()
+	// Low-power mode leaves out the background stats of every container
+	if !cfg.LowPower {
+		d.startListStatsWorker()
+	}
+	d.startSizesWorker()
+	d.startHistoryRecorder()
+	d.startMetricsExport()
+	d.startAPI()
+	d.startForecasts()
+	d.startHealthProbes()
+	d.startRemediation()
+	d.startLogArchiver()
+	d.startLogShipping()
+	d.startEventsWorker()
+	d.startConnectionWatchdog()
+	d.startHealthStrip()
+	d.startDigest()
 	d.setupKeyHandlers()
 
 	d.list.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
 		d.mu.Lock()
-		if index >= 0 && index < len(d.containers) {
-			d.selectedIndex = index
+		if index >= 0 && index < len(d.rows) && d.rows[index].containerIndex >= 0 {
+			d.selectedIndex = d.rows[index].containerIndex
 		}
 		d.mu.Unlock()
 	})
 
-	d.app.SetRoot(d.mainFlex, true)
+	setRoot(d.app, d.mainFlex)
 	d.app.SetFocus(d.list)
+	if cfg.Kiosk {
+		d.showOverview()
+	}
 
 	return d.app, nil
 }
@@ -230,18 +324,49 @@ func (d *Dashboard) setupKeyHandlers() {
 		containerCount := len(d.containers)
 		d.mu.RUnlock()
 
-		if event.Rune() == 'q' || event.Rune() == 'Q' {
-			d.cleanup()
-			d.app.Stop()
+		if event.Rune() >= '0' && event.Rune() <= '9' {
+			d.selectView(int(event.Rune() - '0'))
 			return nil
 		}
 
-		if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
+		action := d.keys.Action(event)
+		if !d.kioskAllows(action) {
+			return nil
+		}
+		switch action {
+		case actionTheme:
+			d.cycleTheme()
+			return nil
+		case actionBack:
 			if d.bulkMode.IsEnabled() {
 				d.bulkMode.Toggle()
 				d.updateList()
 			}
 			return nil
+		case actionRefresh:
+			d.updateList()
+			return nil
+		case actionSort:
+			d.cycleSort()
+			return nil
+		case actionBulkSelectAll, actionBulkInvert, actionBulkSelectRunning, actionBulkSelectStopped:
+			d.bulkSelect(action)
+			return nil
+		case actionBulkSelectRegex:
+			d.showBulkRegexPrompt()
+			return nil
+		case actionLogArchive:
+			d.showLogArchive()
+			return nil
+		case actionStartProfile:
+			d.showStartProfiles()
+			return nil
+		case actionLabels:
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+			ShowLabelBrowser(d.app, d.mainFlex, containers, d.grouping, func() { d.updateList() })
+			return nil
 		}
 
 		if containerCount == 0 {
@@ -249,88 +374,121 @@ func (d *Dashboard) setupKeyHandlers() {
 		}
 
 		currentIndex := d.list.GetCurrentItem()
-		if currentIndex < 0 || currentIndex >= containerCount {
+		d.mu.Lock()
+		if currentIndex < 0 || currentIndex >= len(d.rows) {
+			d.mu.Unlock()
 			return event
 		}
-
-		d.mu.Lock()
-		d.selectedIndex = currentIndex
+		row := d.rows[currentIndex]
+		if row.containerIndex < 0 {
+			d.mu.Unlock()
+			// Group headers collapse/expand on Enter or the select key
+			if event.Key() == tcell.KeyEnter || action == actionBulkSelect {
+				d.grouping.ToggleCollapsed(row.group)
+				d.updateList()
+				return nil
+			}
+			return event
+		}
+		d.selectedIndex = row.containerIndex
 		container := d.containers[d.selectedIndex]
 		d.mu.Unlock()
 
-		switch event.Rune() {
-		case 'l':
-			showLogs(d.app, d.mainFlex, container.ID, d.containers)
-			return nil
-		case 'L':
-			ShowAdvancedLogs(d.app, d.mainFlex, container.ID, d.containers)
-			return nil
-		case 's', 'S':
-			d.toggleContainer(container)
-			return nil
-		case 'r', 'R':
-			d.restartContainer(container)
-			return nil
-		case 'd', 'D':
-			d.deleteContainer(container)
-			return nil
-		case 't', 'T':
-			showEnhancedStats(d.app, d.mainFlex, container.ID, container.Name)
-			return nil
-		case 'i', 'I':
-			showEnhancedInspect(d.app, d.mainFlex, container.ID, container.Name)
-			return nil
-		case 'e', 'E':
-			ShowShellOptionsMenu(d.app, d.mainFlex, container.ID, d.containers)
-			return nil
-		case 'h', 'H':
-			d.showHealthCheck(container)
+		if recentViews[action] {
+			d.openContainerView(action, container)
 			return nil
-		case 'x', 'X':
+		}
+		switch action {
+		case actionToggle:
+			if container.State != "running" {
+				d.toggleContainer(container)
+				break
+			}
+			d.remindNote(container)
+			guardProtected(d.app, d.mainFlex, "stop", []docker.ContainerInfo{container}, func() { d.toggleContainer(container) })
+		case actionRestart:
+			d.remindNote(container)
+			guardProtected(d.app, d.mainFlex, "restart", []docker.ContainerInfo{container}, func() { d.restartContainer(container) })
+		case actionDelete:
+			d.remindNote(container)
+			guardProtected(d.app, d.mainFlex, "delete", []docker.ContainerInfo{container}, func() { d.deleteContainer(container) })
+		case actionRecreate:
+			d.showRecreateForm(container)
+		case actionClone:
+			d.showCloneForm(container)
+		case actionBlueGreen:
+			if container.State != "running" {
+				d.flashStatus(fmt.Sprintf("[%s]%s is not running[-]", currentTheme().Warning, tview.Escape(container.Name)))
+				break
+			}
+			d.showBlueGreen(container)
+		case actionSecurity:
+			d.showSecurity(container)
+		case actionNote:
+			d.showNoteEditor(container)
+		case actionAttach:
+			d.attachContainer(container)
+		case actionNotifyExit:
+			d.toggleExitWatch(container)
+		case actionExportLogs:
 			d.exportContainerLogs(container)
-			return nil
-		case 'b', 'B':
+		case actionTruncateLogs:
+			d.truncateLogs(container)
+		case actionSupportBundle:
+			d.createSupportBundle(container)
+		case actionPlugins:
+			d.showPlugins(container)
+		case actionCopyID, actionCopyName, actionCopyImage, actionCopyIP:
+			d.copyContainerField(container, action)
+		case actionCopyOwner:
+			d.copyOwner(container)
+		case actionBulkMode:
 			d.bulkMode.Toggle()
 			d.updateList()
 			if d.bulkMode.IsEnabled() {
 				d.showBulkModeInfo()
 			}
-			return nil
-		case 'a', 'A':
+		case actionBulkActions:
 			if d.bulkMode.IsEnabled() {
 				d.mu.RLock()
 				containers := d.containers
 				d.mu.RUnlock()
 				ShowBulkActionsMenu(d.app, d.mainFlex, d.bulkMode, containers, func() { d.updateList() })
 			}
-			return nil
-		case ' ':
+		case actionBulkSelect:
 			if d.bulkMode.IsEnabled() {
 				d.bulkMode.ToggleContainer(container.ID)
 				d.updateList()
 				d.showBulkModeInfo()
 			}
-			return nil
-		}
-
-		if event.Key() == tcell.KeyF5 {
-			d.updateList()
-			return nil
+		default:
+			return event
 		}
-
-		return event
+		return nil
 	})
 }
 
 func (d *Dashboard) showBulkModeInfo() {
+	t := currentTheme()
+	key := func(action string) string {
+		return fmt.Sprintf("[%s]%s[-]", t.Success, tview.Escape(d.keys.KeyLabel(action)))
+	}
 	d.app.QueueUpdateDraw(func() {
-		d.detailsText.SetText(
-			"[::b][yellow]🎯 BULK MODE ACTIVE[-:-:-]\n\n" +
-				"[cyan]Instructions:[-]\n" +
-				"• Press [green]SPACE[-] to select containers\n" +
-				"• Press [green]'a'[-] for bulk actions menu\n" +
-				"• Press [green]'b'[-] or [yellow]Backspace[-] to exit\n\n" +
-				"[white]Selected: [yellow]" + fmt.Sprintf("%d", d.bulkMode.Count()) + "[-] containers")
+		d.detailsText.SetText(fmt.Sprintf(
+			"[%[1]s::b]🎯 BULK MODE ACTIVE[-:-:-]\n\n"+
+				"[%[2]s]Instructions:[-]\n"+
+				"• Press %[3]s to select containers\n"+
+				"• Press %[4]s for bulk actions menu\n"+
+				"• Press %[5]s or [%[1]s]%[6]s[-] to exit\n\n"+
+				"[%[2]s]Quick selection:[-]\n"+
+				"• %[7]s all visible   %[8]s invert\n"+
+				"• %[9]s running   %[10]s stopped\n"+
+				"• %[11]s by name / image regex\n\n"+
+				"Selected: [%[1]s]%[12]d[-] containers",
+			t.Highlight, t.Accent,
+			key(actionBulkSelect), key(actionBulkActions), key(actionBulkMode), d.keys.KeyLabel(actionBack),
+			key(actionBulkSelectAll), key(actionBulkInvert), key(actionBulkSelectRunning),
+			key(actionBulkSelectStopped), key(actionBulkSelectRegex), d.bulkMode.Count()))
 	})
 }
 
@@ -343,25 +501,85 @@ func (d *Dashboard) cleanup() {
 	}
 }
 
+// startStatsWorker streams the stats of the selected container, switching streams when
+// the selection or its state changes
 func (d *Dashboard) startStatsWorker() {
 	go func() {
-		ticker := time.NewTicker(2 * time.Second)
+		ticker := time.NewTicker(500 * time.Millisecond)
 		defer ticker.Stop()
 
+		var (
+			key       string // ID and state of the streamed container
+			container docker.ContainerInfo
+			cancel    context.CancelFunc = func() {}
+			samples   <-chan *docker.ContainerStats
+			errs      <-chan error
+			rendered  time.Time // of the last sample shown
+		)
+		defer func() { cancel() }()
+
 		for {
 			select {
 			case <-d.statsCtx.Done():
 				return
 			case <-ticker.C:
-				d.updateStats()
+				selected, ok := d.selectedContainer()
+				next := ""
+				if ok && connection.Online() {
+					next = selected.ID + "/" + selected.State
+				}
+				if next == key {
+					continue
+				}
+
+				// A stream that ended stays closed until the selection or state changes
+				cancel()
+				cancel, samples, errs, key = func() {}, nil, nil, next
+				if msg := statsUnavailable(selected.ID); next != "" && msg != "" {
+					d.app.QueueUpdateDraw(func() {
+						d.statsText.SetText(msg)
+					})
+				} else if next != "" {
+					ctx, stop := context.WithCancel(d.statsCtx)
+					cancel, container = stop, selected
+					samples, errs = docker.StreamStats(ctx, selected.ID)
+				}
+			case stats, ok := <-samples:
+				if !ok {
+					samples = nil
+					continue
+				}
+				if d.cfg.LowPower && time.Since(rendered) < lowPowerStats {
+					continue
+				}
+				rendered = time.Now()
+				d.updateStats(container, stats)
+			case err := <-errs:
+				errs = nil
+				if err == nil {
+					continue
+				}
+				d.app.QueueUpdateDraw(func() {
+					d.statsText.SetText(fmt.Sprintf("[%s]Stats unavailable[-]", currentTheme().Error))
+				})
 			}
 		}
 	}()
 }
 
+// selectedContainer returns the container under the cursor
+func (d *Dashboard) selectedContainer() (docker.ContainerInfo, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.selectedIndex < 0 || d.selectedIndex >= len(d.containers) {
+		return docker.ContainerInfo{}, false
+	}
+	return d.containers[d.selectedIndex], true
+}
+
 func (d *Dashboard) startRefreshWorker() {
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
+		ticker := time.NewTicker(d.pace(5*time.Second, lowPowerRefresh))
 		defer ticker.Stop()
 
 		for {
@@ -369,120 +587,345 @@ func (d *Dashboard) startRefreshWorker() {
 			case <-d.refreshCtx.Done():
 				return
 			case <-ticker.C:
+				// Keep the last known list while offline; the watchdog refreshes on reconnect.
+				// The all hosts view reports unreachable hosts itself.
+				if !connection.Online() && !d.allHostsMode() {
+					continue
+				}
 				d.app.QueueUpdateDraw(func() {
 					d.updateList()
+					d.updateStatusBar()
 				})
 			}
 		}
 	}()
 }
 
-func (d *Dashboard) updateStats() {
-	d.mu.RLock()
-	if len(d.containers) == 0 || d.selectedIndex < 0 || d.selectedIndex >= len(d.containers) {
-		d.mu.RUnlock()
-		return
+// listChangingActions are the container events that change the container list
+var listChangingActions = map[string]bool{
+	"create": true, "start": true, "die": true, "destroy": true,
+	"pause": true, "unpause": true, "rename": true,
+}
+
+// startEventsWorker follows the daemon event stream of the current host, or of every host
+// in the all hosts view
+func (d *Dashboard) startEventsWorker() {
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	d.eventsCancel = cancel
+
+	endpoints := []docker.Endpoint{docker.CurrentEndpoint()}
+	if d.allHostsMode() {
+		endpoints = d.hostEndpoints()
 	}
-	container := d.containers[d.selectedIndex]
-	d.mu.RUnlock()
+	for _, e := range endpoints {
+		go d.followEvents(ctx, e)
+	}
+}
 
-	stats, err := docker.GetStats(container.ID)
-	if err != nil {
-		d.app.QueueUpdateDraw(func() {
-			d.statsText.SetText("[red]Stats unavailable[-]")
-		})
-		return
+// followEvents streams the events of one host, reconnecting after errors and resuming
+// once the daemon is back if it went offline
+func (d *Dashboard) followEvents(ctx context.Context, e docker.Endpoint) {
+	for {
+		events, errs := docker.StreamEventsFrom(ctx, e)
+		for event := range events {
+			event := event
+			looping := d.crashes.Record(event)
+			d.recordUptime(event)
+			d.recordTimeline(event)
+			// The last stats sample shows how much memory the container had when it was killed
+			var last *docker.ContainerStats
+			if s, ok := d.statsCollector.Get(event.ActorID); ok && event.Action == "oom" {
+				last = s.stats
+			}
+			d.ooms.Record(event, last)
+			d.publishEvent(event)
+			follow := d.follow.Record(event)
+			d.app.QueueUpdateDraw(func() {
+				d.appendEvent(event)
+				if looping {
+					d.crashLoopAlert(event)
+				}
+				if event.Type == "container" && listChangingActions[event.Action] {
+					d.updateList()
+				}
+				if follow != "" {
+					d.followNew(event, follow)
+				}
+			})
+		}
+
+		select {
+		case err := <-errs:
+			d.app.QueueUpdateDraw(func() {
+				fmt.Fprintf(d.eventsView, "[red]Event stream error: %s[-]\n", errorSummary(err))
+			})
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+		// The watchdog only tracks the current host; other hosts simply retry
+		if e == docker.CurrentEndpoint() && connection.WaitOnline(ctx) != nil {
+			return
+		}
 	}
+}
+
+// restartEventsWorker reconnects the event stream, e.g. after switching hosts
+func (d *Dashboard) restartEventsWorker() {
+	if d.eventsCancel != nil {
+		d.eventsCancel()
+	}
+	d.startEventsWorker()
+}
 
+// updateStats records a stats sample of the selected container and renders the side panel
+func (d *Dashboard) updateStats(container docker.ContainerInfo, stats *docker.ContainerStats) {
 	var cpuVal, memVal float64
 	fmt.Sscanf(stats.CPUPerc, "%f%%", &cpuVal)
 	fmt.Sscanf(stats.MemPerc, "%f%%", &memVal)
 
 	d.statsHistory.AddCPU(cpuVal)
 	d.statsHistory.AddMem(memVal)
+	d.ioRates.Add(container.ID, stats)
 
 	d.app.QueueUpdateDraw(func() {
 		if !d.bulkMode.IsEnabled() {
-			cpuGraph := d.statsHistory.GetCPUGraph()
-			memGraph := d.statsHistory.GetMemGraph()
-
+			// Rate lines start with a label and the rate, e.g. "↓    1.50 MB/s "
+			_, _, width, _ := d.statsText.GetInnerRect()
+			graphWidth := fitWidth(width, 10, sidePanelSamples)
+			rateWidth := fitWidth(width-15, 8, sidePanelSamples)
+			cpuGraph := d.statsHistory.GetCPUGraph(graphWidth)
+			memGraph := d.statsHistory.GetMemGraph(graphWidth)
+
+			t := currentTheme()
 			statsDisplay := fmt.Sprintf(
-				"[::b][cyan]CPU Usage:[-:-:-]\n"+
-					"[white]%s[-]\n"+
-					"[cyan]%s[-]\n\n"+
-					"[::b][magenta]Memory:[-:-:-]\n"+
-					"[white]%s (%s)[-]\n"+
-					"[magenta]%s[-]\n\n"+
-					"[::b][lime]Network I/O:[-:-:-]\n[white]%s[-]\n\n"+
-					"[::b][yellow]Block I/O:[-:-:-]\n[white]%s[-]",
+				"[%[1]s::b]CPU Usage:[-:-:-]\n"+
+					"%[5]s\n"+
+					"[%[1]s]%[6]s[-]\n\n"+
+					"[%[2]s::b]Memory:[-:-:-]\n"+
+					"%[7]s (%[8]s)\n"+
+					"[%[2]s]%[9]s[-]\n\n"+
+					"[%[3]s::b]Network I/O:[-:-:-]\n%[10]s\n[%[3]s]%[12]s[-]\n\n"+
+					"[%[4]s::b]Block I/O:[-:-:-]\n%[11]s\n[%[4]s]%[13]s[-]",
+				t.Accent, t.Secondary, t.Success, t.Highlight,
 				stats.CPUPerc, cpuGraph,
 				stats.MemPerc, stats.MemUsage, memGraph,
 				stats.NetIO,
-				stats.BlockIO)
-
+				stats.BlockIO,
+				d.ioRates.NetGraph(rateWidth),
+				d.ioRates.BlockGraph(rateWidth))
+
+			// Low-power mode shows plain numbers instead of redrawing graphs
+			if d.cfg.LowPower {
+				statsDisplay = fmt.Sprintf(
+					"[%[1]s::b]CPU Usage:[-:-:-] %[5]s\n\n"+
+						"[%[2]s::b]Memory:[-:-:-] %[6]s (%[7]s)\n\n"+
+						"[%[3]s::b]Network I/O:[-:-:-]\n%[8]s\n\n"+
+						"[%[4]s::b]Block I/O:[-:-:-]\n%[9]s",
+					t.Accent, t.Secondary, t.Success, t.Highlight,
+					stats.CPUPerc, stats.MemPerc, stats.MemUsage, stats.NetIO, stats.BlockIO)
+			}
 			d.statsText.SetText(statsDisplay)
 
-			d.detailsText.SetText(fmt.Sprintf(
-				"[::b][yellow]Container:[-:-:-]\n[white]%s[-]\n\n"+
-					"[::b][cyan]ID:[-:-:-]\n[white]%s[-]\n\n"+
-					"[::b][lime]Status:[-:-:-]\n[white]%s[-]\n\n"+
-					"[::b][magenta]Image:[-:-:-]\n[white]%s[-]\n\n"+
-					"[::b][orange]Ports:[-:-:-]\n[white]%s[-]",
+			details := fmt.Sprintf(
+				"[%[1]s::b]Container:[-:-:-]\n%[6]s\n\n"+
+					"[%[2]s::b]ID:[-:-:-]\n%[7]s\n\n"+
+					"[%[3]s::b]Status:[-:-:-]\n%[8]s\n\n"+
+					"[%[4]s::b]Image:[-:-:-]\n%[9]s\n\n"+
+					"[%[5]s::b]Ports:[-:-:-]\n%[10]s",
+				t.Highlight, t.Accent, t.Success, t.Secondary, t.Warning,
 				container.Name,
 				container.ID[:12],
 				container.Status,
 				container.Image,
-				container.Ports))
+				container.Ports)
+			if size, ok := d.sizes.Get(container.ID); ok {
+				details += fmt.Sprintf("\n\n[%s::b]Size:[-:-:-]\n[%s]%s[-] writable, %s image",
+					t.Accent, sizeColor(size.RW), docker.FormatBytes(uint64(size.RW)), docker.FormatBytes(uint64(size.Image)))
+			}
+			details += d.noteDetails(container)
+			details += d.ownerDetails(container)
+			details += d.logDetails(container)
+			details += d.probeDetails(container)
+			details += d.remediationDetails(container)
+			details += d.trendDetails(container)
+			if n := d.ooms.Today(container.ID); n > 0 {
+				details += fmt.Sprintf("\n\n[black:%s] OOM x%d today [-:-:-]", t.Error, n)
+			}
+			if codes := d.crashes.ExitCodes(container.ID); codes != "" {
+				details += fmt.Sprintf("\n\n[%s::b]Exit codes:[-:-:-]\n%s", t.Error, codes)
+				if d.crashes.InLoop(container.ID) {
+					details += fmt.Sprintf("\n[%s]🔁 Crash loop: %s[-]", t.Warning, d.crashes.Summary(container.ID))
+				}
+			}
+			d.detailsText.SetText(details)
 		}
 	})
 }
 
 func (d *Dashboard) updateList() error {
+	if d.allHostsMode() {
+		d.updateAllHostsList()
+		return nil
+	}
+
+	listedAt := time.Now()
 	newContainers, err := docker.ListContainers()
 	if err != nil {
 		return err
 	}
+	d.renderList(newContainers)
+	d.mu.Lock()
+	d.listedAt = listedAt
+	d.mu.Unlock()
+	return nil
+}
 
+// renderList fills the container list, keeping the cursor on the selected container
+func (d *Dashboard) renderList(newContainers []docker.ContainerInfo) {
+	// Remember the selected container so refreshes keep the cursor in place
+	selectedID := ""
 	d.mu.Lock()
+	if d.selectedIndex >= 0 && d.selectedIndex < len(d.containers) {
+		selectedID = d.containers[d.selectedIndex].ID
+	}
 	d.containers = newContainers
+	d.rows = nil
 	d.mu.Unlock()
+	if !d.cfg.LowPower {
+		d.statsCollector.Track(newContainers)
+	}
 
+	t := currentTheme()
 	d.list.Clear()
 
 	if len(newContainers) == 0 {
-		d.list.AddItem("[yellow]No containers found[-]",
-			"[gray]Start some Docker containers to manage them[-]", 0, nil)
-		d.detailsText.SetText("[yellow]No containers available[-]\n\nStart Docker containers to manage them here.")
+		d.list.AddItem(fmt.Sprintf("[%s]No containers found[-]", t.Highlight),
+			fmt.Sprintf("[%s]Start some Docker containers to manage them[-]", t.Muted), 0, nil)
+		d.detailsText.SetText(fmt.Sprintf("[%s]No containers available[-]\n\nStart Docker containers to manage them here.", t.Highlight))
 		d.statsText.SetText("")
 		d.updateSystemInfo()
-		return nil
+		return
 	}
 
-	for _, container := range newContainers {
-		statusIcon := "🔴"
-		statusColor := "red"
-		if container.State == "running" {
-			statusIcon = "🟢"
-			statusColor = "lime"
+	var visible []int
+	for i, container := range newContainers {
+		if d.isVisible(container) {
+			visible = append(visible, i)
+		}
+	}
+	d.sortVisible(newContainers, visible)
+	d.follow.PinFirst(newContainers, visible)
+
+	var rows []listRow
+	groupKey := d.grouping.GroupKey()
+	if groupKey == "" {
+		for _, idx := range visible {
+			d.addContainerItem(newContainers[idx], "")
+			rows = append(rows, listRow{containerIndex: idx})
 		}
+	} else {
+		values, groups := groupContainers(newContainers, visible, groupKey)
+		for _, value := range values {
+			members := groups[value]
+			collapsed := d.grouping.IsCollapsed(value)
+			arrow := "▼"
+			if collapsed {
+				arrow = "▶"
+			}
 
-		checkbox := ""
-		if d.bulkMode.IsEnabled() {
-			if d.bulkMode.IsSelected(container.ID) {
-				checkbox = "[lime]☑[-] "
-			} else {
-				checkbox = "[gray]☐[-] "
+			running := 0
+			for _, idx := range members {
+				if newContainers[idx].State == "running" {
+					running++
+				}
+			}
+
+			d.list.AddItem(
+				fmt.Sprintf("[%s::b]%s %s: %s[-:-:-]", t.Highlight, arrow, groupKey, value),
+				fmt.Sprintf("[%s]%d containers, %d running[-]", t.Muted, len(members), running), 0, nil)
+			rows = append(rows, listRow{containerIndex: -1, group: value})
+
+			if collapsed {
+				continue
+			}
+			for _, idx := range members {
+				d.addContainerItem(newContainers[idx], "  ")
+				rows = append(rows, listRow{containerIndex: idx, group: value})
 			}
 		}
+	}
 
-		primaryText := fmt.Sprintf("%s%s [%s]%s[-]", checkbox, statusIcon, statusColor, container.Name)
-		secondaryText := fmt.Sprintf("[gray]%s | %s | %s[-]", container.ID[:12], container.Image, container.Status)
+	if len(rows) == 0 {
+		d.list.AddItem(fmt.Sprintf("[%s]No containers match the current view or filter[-]", t.Highlight),
+			fmt.Sprintf("[%s]Press '0' to show all containers, 'g' then 'c' to clear label filters[-]", t.Muted), 0, nil)
+		rows = append(rows, listRow{containerIndex: -1})
+	}
 
-		d.list.AddItem(primaryText, secondaryText, 0, nil)
+	d.mu.Lock()
+	d.rows = rows
+	d.mu.Unlock()
+
+	for i, row := range rows {
+		if row.containerIndex >= 0 && newContainers[row.containerIndex].ID == selectedID {
+			d.list.SetCurrentItem(i)
+			break
+		}
 	}
 
 	d.updateSystemInfo()
-	return nil
+}
+
+// addContainerItem appends a container to the list, indented when it sits inside a group
+func (d *Dashboard) addContainerItem(container docker.ContainerInfo, indent string) {
+	t := currentTheme()
+	statusIcon := "🔴"
+	statusColor := t.Error
+	if container.State == "running" {
+		statusIcon = "🟢"
+		statusColor = t.Success
+	}
+	if d.crashes.InLoop(container.ID) {
+		statusIcon = "🔁"
+		statusColor = t.Warning
+	}
+
+	checkbox := ""
+	if d.bulkMode.IsEnabled() {
+		if d.bulkMode.IsSelected(container.ID) {
+			checkbox = fmt.Sprintf("[%s]☑[-] ", t.Success)
+		} else {
+			checkbox = fmt.Sprintf("[%s]☐[-] ", t.Muted)
+		}
+	}
+
+	host := ""
+	if d.allHostsMode() {
+		host = fmt.Sprintf("[%s]%-12s[-] ", t.Info, tview.Escape(container.Host))
+	}
+
+	shield := ""
+	if isProtected(container) {
+		shield = fmt.Sprintf(" [%s]🛡[-]", t.Warning)
+	}
+	if d.exitWatches.Watching(container.ID) {
+		shield += " 🔔"
+	}
+	if d.follow.Pinned(container.ID) {
+		shield += " 📍"
+	}
+	if d.noteFor(container) != "" {
+		shield += " 📝"
+	}
+
+	primaryText := fmt.Sprintf("%s%s%s %s %s %s[%s]%s[-]%s%s%s", indent, checkbox, statusIcon, d.statsColumns(container), d.sizeColumn(container), host, statusColor, container.Name, shield, d.trendMarker(container), d.probeBadge(container))
+	secondaryText := fmt.Sprintf("%s[%s]%s | %s | %s[-]", indent, t.Muted, container.ID[:12], container.Image, container.Status)
+
+	d.list.AddItem(primaryText, secondaryText, 0, nil)
 }
 
 func (d *Dashboard) updateSystemInfo() {
@@ -491,19 +934,32 @@ func (d *Dashboard) updateSystemInfo() {
 	running := countRunning(d.containers)
 	d.mu.RUnlock()
 
+	t := currentTheme()
 	bulkStatus := ""
 	if d.bulkMode.IsEnabled() {
-		bulkStatus = fmt.Sprintf("[::b][magenta]Bulk Mode:[-:-:-] [yellow]ON (%d)[-]\n\n", d.bulkMode.Count())
+		bulkStatus = fmt.Sprintf("[%s::b]Bulk Mode:[-:-:-] [%s]ON (%d)[-]\n\n", t.Secondary, t.Highlight, d.bulkMode.Count())
+	}
+	if key := d.grouping.GroupKey(); key != "" {
+		bulkStatus += fmt.Sprintf("[%s::b]Group:[-:-:-] %s\n", t.Accent, key)
+	}
+	if view := d.currentView(); view != nil {
+		bulkStatus += fmt.Sprintf("[%s::b]View:[-:-:-] %s\n", t.Success, view.Name)
 	}
+	if key, value := d.grouping.Filter(); key != "" {
+		bulkStatus += fmt.Sprintf("[%s::b]Filter:[-:-:-] %s=%s\n", t.Highlight, key, value)
+	}
+
+	bulkStatus += rootlessNote()
+	bulkStatus += d.hostSummary()
 
 	info := fmt.Sprintf(
 		"%s"+
-			"[::b][dodgerblue]Total:[-:-:-] [white]%d[-]\n"+
-			"[::b][lime]Running:[-:-:-] [white]%d[-]\n"+
-			"[::b][red]Stopped:[-:-:-] [white]%d[-]\n\n"+
-			"[gray]Updated: %s[-]",
-		bulkStatus, total, running, total-running,
-		time.Now().Format("15:04:05"))
+			"[%s::b]Total:[-:-:-] %d\n"+
+			"[%s::b]Running:[-:-:-] %d\n"+
+			"[%s::b]Stopped:[-:-:-] %d\n\n"+
+			"[%s]Updated: %s[-]",
+		bulkStatus, t.Info, total, t.Success, running, t.Error, total-running,
+		t.Muted, time.Now().Format("15:04:05"))
 
 	d.systemInfo.SetText(info)
 }
@@ -512,16 +968,21 @@ func (d *Dashboard) toggleContainer(container docker.ContainerInfo) {
 	go func() {
 		var err error
 		if container.State == "running" {
-			err = docker.StopContainer(container.ID)
+			err = hooked("stop", container, func() error { return docker.StopContainer(container.ID) })
 		} else {
-			err = docker.StartContainer(container.ID)
+			err = hooked("start", container, func() error { return docker.StartContainer(container.ID) })
 		}
 
 		d.app.QueueUpdateDraw(func() {
 			if err != nil {
-				showMessage(d.app, d.mainFlex, "Error", err.Error())
+				showError(d.app, d.mainFlex, "Error", err)
 			} else {
 				d.updateList()
+				if container.State == "running" {
+					showToast(d.app, toastSuccess, fmt.Sprintf("Container %s stopped", container.Name))
+				} else {
+					showToast(d.app, toastSuccess, fmt.Sprintf("Container %s started", container.Name))
+				}
 			}
 		})
 	}()
@@ -529,13 +990,13 @@ func (d *Dashboard) toggleContainer(container docker.ContainerInfo) {
 
 func (d *Dashboard) restartContainer(container docker.ContainerInfo) {
 	go func() {
-		err := docker.RestartContainer(container.ID)
+		err := hooked("restart", container, func() error { return docker.RestartContainer(container.ID) })
 		d.app.QueueUpdateDraw(func() {
 			if err != nil {
-				showMessage(d.app, d.mainFlex, "Error", err.Error())
+				showError(d.app, d.mainFlex, "Error", err)
 			} else {
-				showMessage(d.app, d.mainFlex, "✅ Success", "Container restarted!")
 				d.updateList()
+				showToast(d.app, toastSuccess, fmt.Sprintf("Container %s restarted", container.Name))
 			}
 		})
 	}()
@@ -543,15 +1004,16 @@ func (d *Dashboard) restartContainer(container docker.ContainerInfo) {
 
 func (d *Dashboard) deleteContainer(container docker.ContainerInfo) {
 	showConfirmation(d.app, d.mainFlex,
-		fmt.Sprintf("Delete container '%s'?\n\nThis action cannot be undone!", container.Name),
+		fmt.Sprintf("Delete container '%s'?\n\n%s", container.Name, deleteWarning()),
 		func() {
 			go func() {
-				err := docker.RemoveContainer(container.ID)
+				err := hooked("delete", container, func() error { return removeContainer(container.ID) })
 				d.app.QueueUpdateDraw(func() {
 					if err != nil {
-						showMessage(d.app, d.mainFlex, "Error", err.Error())
+						showError(d.app, d.mainFlex, "Error", err)
 					} else {
 						d.updateList()
+						showToast(d.app, toastSuccess, fmt.Sprintf("Container %s deleted", container.Name))
 					}
 				})
 			}()
@@ -561,14 +1023,22 @@ func (d *Dashboard) deleteContainer(container docker.ContainerInfo) {
 func (d *Dashboard) showHealthCheck(container docker.ContainerInfo) {
 	modal := tview.NewModal().SetText("🏥 Checking container health...")
 	modal.SetBorder(true).SetTitle(" ⏳ Health Check ")
-	d.app.SetRoot(modal, false)
+	setRoot(d.app, modal)
 
 	go func() {
 		health, err := docker.CheckHealth(container.ID)
+		// The daemon remembers OOM kills from before DockPulse started, but not the memory in use
+		var limit int64
+		var daemonOOMs []time.Time
+		if pressure, perr := docker.GetMemoryPressure(container.ID); perr == nil {
+			limit, daemonOOMs = pressure.Limit, pressure.OOMEvents
+		}
+		kills := mergeOOMKills(d.ooms.Kills(container.ID), daemonOOMs)
+
 		d.app.QueueUpdateDraw(func() {
-			d.app.SetRoot(d.mainFlex, true)
+			setRoot(d.app, d.mainFlex)
 			if err != nil {
-				showMessage(d.app, d.mainFlex, "Error", err.Error())
+				showError(d.app, d.mainFlex, "Error", err)
 				return
 			}
 
@@ -576,10 +1046,12 @@ func (d *Dashboard) showHealthCheck(container docker.ContainerInfo) {
 				"[::b][cyan]🏥 Health Check Results[-:-:-]\n\n"+
 					"[yellow]Responsive:[-] %s\n"+
 					"[yellow]Disk Usage:[-] %s\n"+
-					"[yellow]Memory:[-] %s\n",
+					"[yellow]Memory:[-] %s\n\n"+
+					"[::b][cyan]💥 OOM Kills[-:-:-]\n%s",
 				health["responsive"],
 				health["disk_usage"],
-				health["memory_usage"])
+				health["memory_usage"],
+				renderOOMHistory(kills, limit))
 
 			showMessage(d.app, d.mainFlex, "Health Check", healthText)
 		})
@@ -587,9 +1059,8 @@ func (d *Dashboard) showHealthCheck(container docker.ContainerInfo) {
 }
 
 func (d *Dashboard) exportContainerLogs(container docker.ContainerInfo) {
-	showMessage(d.app, d.mainFlex, "📋 Export Logs",
-		fmt.Sprintf("Exporting logs for: %s\n\nLocation: ./logs/%s_%s.log",
-			container.Name, container.Name, time.Now().Format("20060102_150405")))
+	showToast(d.app, toastInfo, fmt.Sprintf("Exporting logs for %s to ./logs/%s_%s.log",
+		container.Name, container.Name, time.Now().Format("20060102_150405")))
 }
 
 func countRunning(containers []docker.ContainerInfo) int {
@@ -0,0 +1,417 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// deployWatchInterval is how often the deployment watch lists the containers again
+const deployWatchInterval = 2 * time.Second
+
+// deployCrashRestarts is how many restarts of a new container count as a crash loop when
+// the event stream missed them, e.g. for restarts before the watch opened
+const deployCrashRestarts = 3
+
+// showDeployTargets asks which compose project or label selector to watch
+func (d *Dashboard) showDeployTargets() {
+	t := currentTheme()
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true).
+		SetTitle(" 🚀 Watch Deployment (Enter to watch, ESC to cancel) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(d.list)
+	}
+	list.SetDoneFunc(back)
+
+	list.AddItem("Label selector...", "containers with labels, e.g. app=api, tier", 0, func() {
+		input := tview.NewInputField().
+			SetLabel("Labels: ").
+			SetPlaceholder("key=value, key").
+			SetFieldWidth(0)
+		input.SetBorder(true).
+			SetTitle(" 🚀 Watch containers with labels (Enter to watch, ESC to cancel) ").
+			SetBorderPadding(0, 0, 1, 1).
+			SetBorderColor(tcell.GetColor(t.Info))
+		input.SetDoneFunc(func(key tcell.Key) {
+			if key != tcell.KeyEnter {
+				back()
+				return
+			}
+			selector := parseFollowLabels(input.GetText())
+			if len(selector) == 0 {
+				input.SetLabel(fmt.Sprintf("[%s]Labels needed:[-] ", t.Error))
+				return
+			}
+			if _, ok := selector[""]; ok {
+				input.SetLabel(fmt.Sprintf("[%s]Empty key:[-] ", t.Error))
+				return
+			}
+			d.showDeployWatch(strings.TrimSpace(input.GetText()), selector)
+		})
+		showOverlay(d.app, d.mainFlex, input, 70, 3)
+	})
+
+	projects, err := docker.ComposeProjects()
+	if err != nil {
+		list.AddItem(fmt.Sprintf("[%s]Compose projects unavailable[-]", t.Error), errorSummary(err), 0, nil)
+	}
+	for _, p := range projects {
+		list.AddItem("📦 "+p, "compose project", 0, func() {
+			d.showDeployWatch("project "+p, docker.ComposeProjectSelector(p))
+		})
+	}
+	showOverlay(d.app, d.mainFlex, list, 70, min(2*list.GetItemCount()+4, 24))
+}
+
+// deployTransition is a state or health change seen during the watch
+type deployTransition struct {
+	at          time.Time
+	name        string
+	from, to    string
+	newInstance bool
+}
+
+// showDeployWatch follows the containers of a deployment on one screen: the new ones
+// created since the watch opened next to the old ones they replace, health and state
+// changes as they happen, and how far the rollout got. New containers that crash-loop
+// raise an alert.
+func (d *Dashboard) showDeployWatch(target string, selector map[string]string) {
+	t := currentTheme()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🚀 Deployment: %s ", tview.Escape(target))).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	changes := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	changes.SetBorder(true).
+		SetTitle(" Transitions ").
+		SetBorderColor(tcell.GetColor(t.Border)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] l [-:-:-] Logs   [black:green] n [-:-:-] Deploy starts now   [black:green] c [-:-:-] Clear transitions   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 3, 0, false).
+		AddItem(table, 0, 2, true).
+		AddItem(changes, 0, 1, false).
+		AddItem(footer, 1, 0, false)
+
+	var (
+		since     = time.Now()                              // containers created from then on are new
+		old       = make(map[string]docker.DeployContainer) // every old container seen, also removed ones
+		current   []docker.DeployContainer
+		last      = make(map[string]string) // container ID -> state and health last seen
+		alerted   = make(map[string]bool)   // new containers already reported as crash-looping
+		rows      []string                  // container ID per table row
+		scanErr   error
+		firstScan = true
+	)
+
+	// status describes state and health in one word, e.g. "running (healthy)"
+	status := func(c docker.DeployContainer) string {
+		if c.Health != "" && c.State == "running" {
+			return c.State + " (" + c.Health + ")"
+		}
+		if c.State == "exited" {
+			return fmt.Sprintf("exited (%d)", c.ExitCode)
+		}
+		return c.State
+	}
+	ready := func(c docker.DeployContainer) bool {
+		return c.State == "running" && (c.Health == "" || c.Health == "healthy")
+	}
+	crashing := func(c docker.DeployContainer) bool {
+		return d.crashes.InLoop(c.ID) || c.Restarts >= deployCrashRestarts
+	}
+	isNew := func(c docker.DeployContainer) bool {
+		return !c.Created.Before(since)
+	}
+
+	// record notes the transitions between two scans and alerts on crash-looping new
+	// containers; it must be called on the UI goroutine
+	record := func() {
+		present := make(map[string]bool)
+		for _, c := range current {
+			present[c.ID] = true
+			if !isNew(c) {
+				old[c.ID] = c
+			}
+			now := status(c)
+			if prev, ok := last[c.ID]; ok && prev != now && !firstScan {
+				d.writeDeployTransition(changes, deployTransition{time.Now(), c.Name, prev, now, isNew(c)})
+			} else if !ok && !firstScan {
+				d.writeDeployTransition(changes, deployTransition{time.Now(), c.Name, "", now, isNew(c)})
+			}
+			last[c.ID] = now
+
+			if isNew(c) && crashing(c) && !alerted[c.ID] {
+				alerted[c.ID] = true
+				showToast(d.app, toastWarning, fmt.Sprintf("New container %s is crash-looping (%d restarts)", c.Name, c.Restarts))
+				fmt.Fprintf(changes, "[%s]%s[-] [%s::b]crash loop[-:-:-] %s: %d restarts, last exit %d\n",
+					t.Muted, time.Now().Format("15:04:05"), t.Error, tview.Escape(c.Name), c.Restarts, c.ExitCode)
+			}
+		}
+		for id, prev := range last {
+			if !present[id] {
+				name := id[:12]
+				if c, ok := old[id]; ok {
+					name = c.Name
+				}
+				if !firstScan {
+					d.writeDeployTransition(changes, deployTransition{time.Now(), name, prev, "removed", old[id].ID == ""})
+				}
+				delete(last, id)
+			}
+		}
+		firstScan = false
+	}
+
+	// render must be called on the UI goroutine
+	render := func() {
+		table.Clear()
+		for col, h := range []string{"", "SERVICE", "CONTAINER", "IMAGE", "STATE", "RESTARTS", "AGE"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		if scanErr != nil {
+			header.SetText(fmt.Sprintf(" [%s]Listing the containers failed:[-] %s", t.Error, tview.Escape(errorSummary(scanErr))))
+			return
+		}
+
+		var fresh, stale []docker.DeployContainer
+		present := make(map[string]bool)
+		for _, c := range current {
+			present[c.ID] = true
+			if isNew(c) {
+				fresh = append(fresh, c)
+			} else {
+				stale = append(stale, c)
+			}
+		}
+		var removed []docker.DeployContainer
+		for id, c := range old {
+			if !present[id] {
+				removed = append(removed, c)
+			}
+		}
+		sort.Slice(removed, func(i, j int) bool { return removed[i].Name < removed[j].Name })
+
+		// Old images per service show which new containers run a different one
+		oldImages := make(map[string]string)
+		for _, c := range old {
+			oldImages[c.Service] = c.ImageID
+		}
+
+		rows = rows[:0]
+		row := 1
+		add := func(gen, genColor string, c docker.DeployContainer, gone bool) {
+			color := t.Muted
+			state := status(c)
+			switch {
+			case gone:
+				state = "removed"
+			case crashing(c):
+				color, state = t.Error, "🔁 crash loop, "+state
+			case c.Health == "unhealthy" || c.State == "exited" || c.State == "dead":
+				color = t.Error
+			case ready(c):
+				color = t.Success
+			case c.State == "running" || c.State == "restarting" || c.State == "created":
+				color = t.Warning
+			}
+			image := c.Image
+			if id := oldImages[c.Service]; gen == "NEW" && id != "" && id != c.ImageID {
+				image += fmt.Sprintf(" [%s](changed)[-]", t.Accent)
+			}
+			age := "-"
+			if !gone {
+				age = time.Since(c.Created).Round(time.Second).String()
+			}
+			table.SetCell(row, 0, tview.NewTableCell(gen).SetTextColor(tcell.GetColor(genColor)).SetAttributes(tcell.AttrBold))
+			table.SetCell(row, 1, tview.NewTableCell(tview.Escape(c.Service)).SetTextColor(tcell.GetColor(t.Info)))
+			table.SetCell(row, 2, tview.NewTableCell(tview.Escape(c.Name)).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(row, 3, tview.NewTableCell(tview.Escape(image)).SetTextColor(tcell.GetColor(t.Muted)).SetExpansion(1))
+			table.SetCell(row, 4, tview.NewTableCell(state).SetTextColor(tcell.GetColor(color)))
+			table.SetCell(row, 5, tview.NewTableCell(fmt.Sprint(c.Restarts)).SetTextColor(tcell.GetColor(t.Muted)).SetAlign(tview.AlignRight))
+			table.SetCell(row, 6, tview.NewTableCell(age).SetTextColor(tcell.GetColor(t.Muted)).SetAlign(tview.AlignRight))
+			rows = append(rows, c.ID)
+			row++
+		}
+		for _, c := range fresh {
+			add("NEW", t.Success, c, false)
+		}
+		for _, c := range stale {
+			add("OLD", t.Warning, c, false)
+		}
+		for _, c := range removed {
+			add("OLD", t.Muted, c, true)
+		}
+		if row == 1 {
+			table.SetCell(1, 0, tview.NewTableCell("No containers match yet; new ones show up as they are created").
+				SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+
+		// The rollout is done once as many new containers are ready as old ones ran, and
+		// no old one runs any more
+		expected := 0
+		for _, c := range old {
+			if c.State == "running" || !present[c.ID] {
+				expected++
+			}
+		}
+		var readyCount, failing, oldRunning int
+		for _, c := range fresh {
+			if ready(c) {
+				readyCount++
+			}
+			if crashing(c) || c.Health == "unhealthy" || (c.State == "exited" && c.ExitCode != 0) {
+				failing++
+			}
+		}
+		for _, c := range stale {
+			if c.State == "running" {
+				oldRunning++
+			}
+		}
+		expected = max(expected, 1)
+
+		var verdict string
+		switch {
+		case failing > 0:
+			verdict = fmt.Sprintf("[%s::b]❌ %d new containers failing[-:-:-]", t.Error, failing)
+		case len(fresh) == 0:
+			verdict = fmt.Sprintf("[%s::b]⏳ Waiting for new containers[-:-:-]", t.Muted)
+		case readyCount >= expected && oldRunning == 0:
+			verdict = fmt.Sprintf("[%s::b]✅ Rollout complete[-:-:-]", t.Success)
+		default:
+			verdict = fmt.Sprintf("[%s::b]🚀 Rolling out[-:-:-]", t.Warning)
+		}
+		header.SetText(fmt.Sprintf(" %s   %s %d/%d new ready, %d old still running\n [%s]New means created after %s (%s ago); old containers that are gone stay listed as removed[-]",
+			verdict, DrawGraph(100*float64(min(readyCount, expected))/float64(expected), 20), readyCount, expected, oldRunning,
+			t.Muted, since.Format("15:04:05"), time.Since(since).Round(time.Second)))
+	}
+
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	back := func() {
+		cancel()
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(d.list)
+	}
+	scan := func() {
+		found, err := docker.DeployContainers(selector)
+		d.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			scanErr = err
+			if err == nil {
+				current = found
+				record()
+			}
+			render()
+		})
+	}
+	go func() {
+		ticker := time.NewTicker(deployWatchInterval)
+		defer ticker.Stop()
+		for {
+			scan()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			back()
+			return nil
+		case event.Rune() == 'l':
+			row, _ := table.GetSelection()
+			if row < 1 || row > len(rows) {
+				return nil
+			}
+			id := rows[row-1]
+			if !slices.ContainsFunc(current, func(c docker.DeployContainer) bool { return c.ID == id }) {
+				showToast(d.app, toastInfo, "That container was removed")
+				return nil
+			}
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+			showLogs(d.app, flex, id, containers)
+			return nil
+		case event.Rune() == 'n':
+			since = time.Now()
+			clear(old)
+			for _, c := range current {
+				old[c.ID] = c
+			}
+			clear(alerted)
+			render()
+			showToast(d.app, toastInfo, "Containers created from now on count as new")
+			return nil
+		case event.Rune() == 'c':
+			changes.Clear()
+			return nil
+		}
+		return event
+	})
+
+	header.SetText(fmt.Sprintf(" [%s]Listing containers...[-]", t.Muted))
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
+
+// writeDeployTransition appends a state or health change to the transitions pane
+func (d *Dashboard) writeDeployTransition(view *tview.TextView, tr deployTransition) {
+	t := currentTheme()
+	gen := "old"
+	if tr.newInstance {
+		gen = "new"
+	}
+	from := tr.from
+	if from == "" {
+		from = "created"
+	}
+	color := t.Text
+	switch {
+	case strings.Contains(tr.to, "unhealthy") || strings.HasPrefix(tr.to, "exited") || tr.to == "dead":
+		color = t.Error
+	case strings.Contains(tr.to, "(healthy)") || (tr.to == "running" && tr.from != ""):
+		color = t.Success
+	}
+	fmt.Fprintf(view, "[%s]%s[-] %s [%s]%s[-] %s → [%s]%s[-]\n",
+		t.Muted, tr.at.Format("15:04:05"), gen, t.Info, tview.Escape(tr.name), from, color, tr.to)
+	view.ScrollToEnd()
+}
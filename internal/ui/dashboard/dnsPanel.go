@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"devops-dashboard/internal/docker"
+)
+
+// dnsHeaders are the columns of the DNS inspect tab
+var dnsHeaders = []string{"SETTING", "VALUE", "NOTES"}
+
+// dnsLookup is a name looked up from the DNS inspect tab
+type dnsLookup struct {
+	name   string
+	result *docker.Lookup
+	err    error
+}
+
+// dnsRows lists how a container resolves names
+func dnsRows(c *docker.DNSConfig) [][]string {
+	var rows [][]string
+	for _, ns := range c.Nameservers {
+		note := ""
+		if ns == docker.EmbeddedDNS {
+			note = "Docker's embedded DNS: resolves container names on user-defined networks, forwards the rest"
+		}
+		rows = append(rows, []string{"Nameserver", ns, note})
+	}
+	if len(c.Nameservers) == 0 {
+		rows = append(rows, []string{"Nameserver", "-", "none in /etc/resolv.conf, so lookups go to 127.0.0.1"})
+	}
+	for _, s := range c.Search {
+		rows = append(rows, []string{"Search domain", s, ""})
+	}
+	for _, o := range c.Options {
+		note := ""
+		if strings.HasPrefix(o, "ndots:") {
+			note = "names with fewer dots are tried with the search domains first"
+		}
+		rows = append(rows, []string{"Option", o, note})
+	}
+
+	configured := func(label string, values []string) {
+		for _, v := range values {
+			rows = append(rows, []string{label, v, "set when the container was created"})
+		}
+	}
+	configured("--dns", c.ConfiguredServers)
+	configured("--dns-search", c.ConfiguredSearch)
+	configured("--dns-option", c.ConfiguredOptions)
+	return rows
+}
+
+// lookupRow renders a name looked up in the container, and its color
+func lookupRow(l dnsLookup) ([]string, string) {
+	t := currentTheme()
+	switch {
+	case l.err != nil:
+		return []string{"Lookup", l.name, "✗ " + errorSummary(l.err)}, t.Error
+	case l.result == nil:
+		return []string{"Lookup", l.name, "⏳ resolving..."}, t.Warning
+	case l.result.Tool == "":
+		return []string{"Lookup", l.name, "? the image has neither getent nor nslookup"}, t.Warning
+	case len(l.result.Addresses) == 0:
+		return []string{"Lookup", l.name, fmt.Sprintf("✗ doesn't resolve (%s)", l.result.Tool)}, t.Error
+	}
+
+	took := ""
+	if l.result.Took > 0 {
+		took = ", " + l.result.Took.Round(100*time.Microsecond).String()
+	}
+	return []string{"Lookup", l.name, fmt.Sprintf("✓ %s (%s%s)", strings.Join(l.result.Addresses, ", "), l.result.Tool, took)}, t.Success
+}
+
+// fillDNSTab shows the resolver configuration followed by the lookups made so far
+func fillDNSTab(tab *inspectTab, config *docker.DNSConfig, lookups []dnsLookup) {
+	rows := dnsRows(config)
+	base := len(rows)
+	colors := make([]string, len(lookups))
+	for i, l := range lookups {
+		var row []string
+		row, colors[i] = lookupRow(l)
+		rows = append(rows, row)
+	}
+	tab.setRows(dnsHeaders, rows, columnValues(rows, 1))
+	for i, color := range colors {
+		tab.table.GetCell(base+i+1, 2).SetTextColor(tcell.GetColor(color))
+	}
+}
@@ -0,0 +1,94 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// showDiagnostics runs the environment checks and lists them with suggested fixes
+func showDiagnostics(app *tview.Application, mainView tview.Primitive) {
+	t := currentTheme()
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 🩺 Diagnostics (r rerun, ESC back) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	fix := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	fix.SetBorder(true).
+		SetTitle(" 💡 Fix ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Warning))
+
+	var checks []docker.Check
+	table.SetSelectionChangedFunc(func(row, column int) {
+		if row < 1 || row > len(checks) {
+			fix.SetText("")
+			return
+		}
+		if c := checks[row-1]; c.Fix != "" {
+			fix.SetText(tview.Escape(c.Fix))
+		} else {
+			fix.SetText(fmt.Sprintf("[%s]Nothing to do.[-]", t.Muted))
+		}
+	})
+
+	run := func() {
+		table.Clear()
+		for col, header := range []string{"", "CHECK", "DETAILS"} {
+			table.SetCell(0, col, tview.NewTableCell(header).
+				SetTextColor(tcell.GetColor(t.Accent)).
+				SetSelectable(false))
+		}
+		table.SetCell(1, 1, tview.NewTableCell("Running checks...").SetTextColor(tcell.GetColor(t.Muted)))
+
+		go func() {
+			results := docker.RunDiagnostics()
+			app.QueueUpdateDraw(func() {
+				checks = results
+				symbols := map[docker.CheckStatus][2]string{
+					docker.CheckOK:   {"✔", t.Success},
+					docker.CheckWarn: {"⚠", t.Warning},
+					docker.CheckFail: {"✖", t.Error},
+				}
+				for i, c := range checks {
+					symbol := symbols[c.Status]
+					table.SetCell(i+1, 0, tview.NewTableCell(symbol[0]).SetTextColor(tcell.GetColor(symbol[1])))
+					table.SetCell(i+1, 1, tview.NewTableCell(c.Name).SetTextColor(tcell.GetColor(symbol[1])))
+					table.SetCell(i+1, 2, tview.NewTableCell(c.Detail).SetExpansion(1))
+				}
+				table.Select(1, 0)
+			})
+		}()
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyBackspace, event.Key() == tcell.KeyBackspace2,
+			event.Rune() == 'q':
+			setRoot(app, mainView)
+			return nil
+		case event.Rune() == 'r':
+			run()
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(fix, 4, 0, false)
+
+	run()
+	setRoot(app, layout)
+}
@@ -0,0 +1,39 @@
+package dashboard
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// errorSummary describes an error in one line, using the decoded Docker message when
+// there is one and keeping any context the docker package added in front of it
+func errorSummary(err error) string {
+	var de *docker.Error
+	if !errors.As(err, &de) {
+		return err.Error()
+	}
+
+	context := strings.TrimSuffix(strings.TrimSuffix(err.Error(), de.Error()), ": ")
+	if context == "" {
+		return de.Message
+	}
+	return strings.ToUpper(context[:1]) + context[1:] + ": " + de.Message
+}
+
+// errorText describes an error with a suggested fix when one is known
+func errorText(err error) string {
+	var de *docker.Error
+	if errors.As(err, &de) && de.Hint != "" {
+		return errorSummary(err) + "\n\n💡 " + de.Hint
+	}
+	return errorSummary(err)
+}
+
+// showError shows an error in a message box, with an actionable hint for Docker errors
+func showError(app *tview.Application, mainView tview.Primitive, title string, err error) {
+	showMessage(app, mainView, title, errorText(err))
+}
@@ -0,0 +1,104 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// ExitWatches are the containers to raise an alert for when they exit
+type ExitWatches struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc // container ID -> stops its wait
+}
+
+// NewExitWatches returns an empty set of watches
+func NewExitWatches() *ExitWatches {
+	return &ExitWatches{cancels: make(map[string]context.CancelFunc)}
+}
+
+// Watching reports whether a container is watched
+func (w *ExitWatches) Watching(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.cancels[id]
+	return ok
+}
+
+// Start watches a container and returns the context its wait runs in
+func (w *ExitWatches) Start(parent context.Context, id string) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cancels[id] = cancel
+	return ctx
+}
+
+// Stop stops watching a container
+func (w *ExitWatches) Stop(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cancel, ok := w.cancels[id]; ok {
+		cancel()
+		delete(w.cancels, id)
+	}
+}
+
+// toggleExitWatch starts or stops waiting for a container to exit. Watches are one-shot:
+// the alert ends them, also for containers a restart policy brings back.
+func (d *Dashboard) toggleExitWatch(container docker.ContainerInfo) {
+	t := currentTheme()
+	if d.exitWatches.Watching(container.ID) {
+		d.exitWatches.Stop(container.ID)
+		d.flashStatus(fmt.Sprintf("[%s]No longer watching %s for its exit[-]", t.Muted, tview.Escape(container.Name)))
+		d.updateList()
+		return
+	}
+	if container.State != "running" {
+		d.flashStatus(fmt.Sprintf("[%s]%s is not running[-]", t.Warning, tview.Escape(container.Name)))
+		return
+	}
+
+	ctx := d.exitWatches.Start(d.refreshCtx, container.ID)
+	go func() {
+		exit, err := docker.WaitExit(ctx, container.ID)
+		if ctx.Err() != nil {
+			return
+		}
+		d.app.QueueUpdateDraw(func() {
+			d.exitWatches.Stop(container.ID)
+			d.exitAlert(container, exit, err)
+			d.updateList()
+		})
+	}()
+	d.flashStatus(fmt.Sprintf("[%s]🔔 You'll be told when %s exits[-]", t.Success, tview.Escape(container.Name)))
+	d.updateList()
+}
+
+// exitAlert reports that a watched container exited, or that the wait failed; it must
+// be called on the UI goroutine
+func (d *Dashboard) exitAlert(container docker.ContainerInfo, exit *docker.ExitStatus, err error) {
+	at := time.Now()
+	if err != nil {
+		showToast(d.app, toastWarning, fmt.Sprintf("Stopped watching %s: %s", container.Name, errorSummary(err)))
+		d.logAlert(at, history.SeverityWarning, "watch lost", container.Name, errorSummary(err))
+		return
+	}
+
+	reason := fmt.Sprintf("exit code %d", exit.Code)
+	if exit.OOMKilled {
+		reason += ", killed for running out of memory"
+	}
+	kind, severity := toastInfo, history.SeverityInfo
+	if exit.Code != 0 || exit.OOMKilled {
+		kind, severity = toastWarning, history.SeverityCritical
+	}
+	showToast(d.app, kind, fmt.Sprintf("🔔 %s exited: %s", container.Name, reason))
+	d.logAlert(at, severity, "exited", container.Name, reason)
+}
@@ -0,0 +1,198 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+const (
+	forecastEvery   = 10 * time.Minute // between trend computations
+	forecastWindow  = 6 * time.Hour    // of history the trend is fitted to
+	forecastMinSpan = time.Hour        // shorter histories give no trend
+	// forecastMinFit is the least r² of the fitted line: noisy or sawtooth usage, such
+	// as a garbage collected heap, doesn't count as growth
+	forecastMinFit = 0.6
+)
+
+// Trend is how the memory usage of a container grows
+type Trend struct {
+	PerHour float64       // percentage points of the memory limit per hour
+	Full    time.Duration // until the limit is reached at this rate
+}
+
+// memoryTrend fits a line to memory samples and returns the trend when usage grows
+// steadily
+func memoryTrend(samples []history.Sample, now time.Time) (Trend, bool) {
+	if len(samples) < 3 || samples[len(samples)-1].Time.Sub(samples[0].Time) < forecastMinSpan {
+		return Trend{}, false
+	}
+
+	start := samples[0].Time
+	n := float64(len(samples))
+	var sumX, sumY float64
+	for _, s := range samples {
+		sumX += s.Time.Sub(start).Hours()
+		sumY += s.Mem
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var cov, varX, varY float64
+	for _, s := range samples {
+		dx, dy := s.Time.Sub(start).Hours()-meanX, s.Mem-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 || cov <= 0 || cov*cov/(varX*varY) < forecastMinFit {
+		return Trend{}, false
+	}
+
+	slope := cov / varX
+	current := meanY + slope*(now.Sub(start).Hours()-meanX)
+	if current >= 100 {
+		return Trend{}, false // already at the limit, which the OOM warnings cover
+	}
+	hours := (100 - current) / slope
+	return Trend{PerHour: slope, Full: time.Duration(hours * float64(time.Hour))}, true
+}
+
+// TrendCache keeps the memory trend of every container that grows toward its limit
+type TrendCache struct {
+	mu     sync.RWMutex
+	trends map[string]Trend
+	warned map[string]bool // containers already alerted about
+}
+
+func NewTrendCache() *TrendCache {
+	return &TrendCache{trends: make(map[string]Trend), warned: make(map[string]bool)}
+}
+
+// Set stores the trend of a container, or clears it when ok is false, and reports
+// whether the container newly comes within horizon of its limit
+func (c *TrendCache) Set(id string, trend Trend, ok bool, horizon time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !ok {
+		delete(c.trends, id)
+		delete(c.warned, id)
+		return false
+	}
+	c.trends[id] = trend
+	if trend.Full > horizon {
+		delete(c.warned, id)
+		return false
+	}
+	if c.warned[id] {
+		return false
+	}
+	c.warned[id] = true
+	return true
+}
+
+// Get returns the trend of a container
+func (c *TrendCache) Get(id string) (Trend, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	trend, ok := c.trends[id]
+	return trend, ok
+}
+
+// startForecasts fits memory trends to the stats history of running containers and
+// warns about the ones that will reach their limit within the forecast horizon
+func (d *Dashboard) startForecasts() {
+	horizon, enabled := d.cfg.Forecast()
+	if d.history == nil || !enabled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(forecastEvery)
+		defer ticker.Stop()
+
+		for {
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+
+			now := time.Now()
+			for _, c := range containers {
+				if d.refreshCtx.Err() != nil {
+					return
+				}
+				if c.State != "running" {
+					continue
+				}
+				samples, err := d.history.Query(c.ID, now.Add(-forecastWindow), now)
+				if err != nil {
+					continue
+				}
+				trend, ok := memoryTrend(samples, now)
+				if d.trends.Set(c.ID, trend, ok, horizon) {
+					d.app.QueueUpdateDraw(func() {
+						d.forecastAlert(c, trend)
+					})
+				}
+			}
+
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// forecastAlert warns about a container running out of memory; it must be called on
+// the UI goroutine
+func (d *Dashboard) forecastAlert(c docker.ContainerInfo, trend Trend) {
+	name := qualifiedName(c)
+	summary := describeTrend(trend)
+	showToast(d.app, toastWarning, fmt.Sprintf("%s: %s", name, summary))
+	d.logAlert(time.Now(), history.SeverityWarning, "memory trend", name, summary)
+}
+
+// describeTrend summarises a trend, e.g. "memory +5.2%/h, at its limit in ~7h"
+func describeTrend(trend Trend) string {
+	return fmt.Sprintf("memory +%.1f%%/h, at its limit in ~%s", trend.PerHour, formatETA(trend.Full))
+}
+
+// formatETA rounds a forecast to what it is worth: minutes under an hour, hours under
+// two days, days beyond
+func formatETA(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(math.Max(1, d.Minutes())))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(math.Round(d.Hours())))
+	}
+	return fmt.Sprintf("%dd", int(math.Round(d.Hours()/24)))
+}
+
+// trendMarker flags list rows of containers that will reach their memory limit within
+// the forecast horizon
+func (d *Dashboard) trendMarker(container docker.ContainerInfo) string {
+	horizon, _ := d.cfg.Forecast()
+	if trend, ok := d.trends.Get(container.ID); ok && trend.Full <= horizon {
+		return fmt.Sprintf(" [%s]📈[-]", currentTheme().Warning)
+	}
+	return ""
+}
+
+// trendDetails describes the memory trend of a container for the details panel
+func (d *Dashboard) trendDetails(container docker.ContainerInfo) string {
+	trend, ok := d.trends.Get(container.ID)
+	if !ok {
+		return ""
+	}
+	t := currentTheme()
+	color := t.Muted
+	if horizon, _ := d.cfg.Forecast(); trend.Full <= horizon {
+		color = t.Warning
+	}
+	return fmt.Sprintf("\n\n[%s::b]Trend:[-:-:-]\n[%s]📈 %s[-]", t.Accent, color, describeTrend(trend))
+}
@@ -1,6 +1,10 @@
 package dashboard
 
-import "strings"
+import (
+	"fmt"
+	"math"
+	"strings"
+)
 
 // DrawGraph renders a horizontal ASCII graph.
 // value = 0–100 percentage.
@@ -17,3 +21,117 @@ func DrawGraph(value float64, width int) string {
 
 	return strings.Repeat("█", filled) + strings.Repeat("░", empty)
 }
+
+// brailleDots maps a dot inside a braille cell, [column][row], to its bit in the pattern
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// DrawBrailleChart renders data as a line chart of height x width braille cells. Each cell
+// holds 2x4 dots, so the chart shows the last 2*width values at 4*height levels between
+// lo and hi, right aligned like the sparklines. NaN values leave a gap.
+func DrawBrailleChart(data []float64, lo, hi float64, height, width int) []string {
+	if height <= 0 || width <= 0 {
+		return nil
+	}
+	cells := make([][]rune, height)
+	for i := range cells {
+		cells[i] = make([]rune, width)
+	}
+
+	dotsX, dotsY := 2*width, 4*height
+	if len(data) > dotsX {
+		data = data[len(data)-dotsX:]
+	}
+	offset := dotsX - len(data)
+	span := hi - lo
+	if span <= 0 {
+		span = 1
+	}
+
+	// row returns the dot row for a value, 0 being the top
+	row := func(v float64) int {
+		y := int(math.Round((v - lo) / span * float64(dotsY-1)))
+		if y < 0 {
+			y = 0
+		}
+		if y > dotsY-1 {
+			y = dotsY - 1
+		}
+		return dotsY - 1 - y
+	}
+
+	prev := -1
+	for i, v := range data {
+		if math.IsNaN(v) {
+			prev = -1
+			continue
+		}
+		x, y := offset+i, row(v)
+		// Join each point to the previous one so steep changes stay connected
+		from, to := y, y
+		if prev >= 0 {
+			from, to = min(y, prev), max(y, prev)
+		}
+		for dot := from; dot <= to; dot++ {
+			cells[dot/4][x/2] |= brailleDots[x%2][dot%4]
+		}
+		prev = y
+	}
+
+	lines := make([]string, height)
+	for i, cellRow := range cells {
+		var b strings.Builder
+		for _, dots := range cellRow {
+			b.WriteRune(0x2800 + dots)
+		}
+		lines[i] = b.String()
+	}
+	return lines
+}
+
+// seriesRange returns the smallest and largest value of data, ignoring NaN gaps
+func seriesRange(data []float64) (lo, hi float64) {
+	first := true
+	for _, v := range data {
+		if math.IsNaN(v) {
+			continue
+		}
+		if first || v < lo {
+			lo = v
+		}
+		if first || v > hi {
+			hi = v
+		}
+		first = false
+	}
+	return lo, hi
+}
+
+// labeledBrailleChart draws a braille chart scaled to the data with the max and min values
+// on the y axis
+func labeledBrailleChart(data []float64, height, width int) string {
+	lo, hi := seriesRange(data)
+	lines := DrawBrailleChart(data, lo, hi, height, width)
+
+	top, bottom := fmt.Sprintf("%.1f%%", hi), fmt.Sprintf("%.1f%%", lo)
+	labelWidth := max(len(top), len(bottom))
+	for i := range lines {
+		label, tick := "", "│"
+		switch i {
+		case 0:
+			label, tick = top, "┤"
+		case len(lines) - 1:
+			label, tick = bottom, "┤"
+		}
+		lines[i] = fmt.Sprintf("[gray]%*s%s[-]%s", labelWidth, label, tick, lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// seriesLabels summarises a series as min / max / current values
+func seriesLabels(data []float64) string {
+	if len(data) == 0 {
+		return "[gray]no samples yet[-]"
+	}
+	lo, hi := seriesRange(data)
+	return fmt.Sprintf("[gray]min[-] %.1f%%  [gray]max[-] %.1f%%  [gray]now[-] %.1f%%", lo, hi, data[len(data)-1])
+}
@@ -0,0 +1,182 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+	"devops-dashboard/internal/probe"
+)
+
+// probeState is what is known about one configured probe
+type probeState struct {
+	probe    *config.HealthProbe
+	last     probe.Result
+	checked  bool
+	failures int  // checks failed in a row
+	alerted  bool // the failure threshold was reached and not yet recovered from
+}
+
+// ProbeBoard keeps the latest result of every health probe
+type ProbeBoard struct {
+	mu     sync.RWMutex
+	states []probeState
+}
+
+func NewProbeBoard(probes []config.HealthProbe) *ProbeBoard {
+	b := &ProbeBoard{states: make([]probeState, len(probes))}
+	for i := range probes {
+		b.states[i].probe = &probes[i]
+	}
+	return b
+}
+
+// Record stores a result and reports whether the probe just crossed its failure
+// threshold (down) or came back after it had (recovered)
+func (b *ProbeBoard) Record(i int, r probe.Result) (down, recovered bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := &b.states[i]
+	s.last, s.checked = r, true
+	if r.Up {
+		s.failures = 0
+		recovered, s.alerted = s.alerted, false
+		return false, recovered
+	}
+	s.failures++
+	if !s.alerted && s.failures >= s.probe.FailureThreshold() {
+		s.alerted = true
+		return true, false
+	}
+	return false, false
+}
+
+// Failures returns how many checks of a probe failed in a row
+func (b *ProbeBoard) Failures(i int) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.states[i].failures
+}
+
+// For returns the states of the probes of a container
+func (b *ProbeBoard) For(c docker.ContainerInfo) []probeState {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var states []probeState
+	for _, s := range b.states {
+		if namesContainer(s.probe.Container, c) {
+			states = append(states, s)
+		}
+	}
+	return states
+}
+
+// startHealthProbes runs every configured probe on its own interval while the
+// dashboard runs
+func (d *Dashboard) startHealthProbes() {
+	for i := range d.cfg.Probes {
+		go func(i int, p *config.HealthProbe) {
+			ticker := time.NewTicker(p.Every())
+			defer ticker.Stop()
+
+			for {
+				result := probe.Check(d.refreshCtx, p)
+				if d.refreshCtx.Err() != nil {
+					return
+				}
+				if down, recovered := d.probes.Record(i, result); down || recovered {
+					d.app.QueueUpdateDraw(func() {
+						d.probeAlert(p, result)
+					})
+				}
+				if !result.Up {
+					d.remediateProbe(p, d.probes.Failures(i))
+				}
+
+				select {
+				case <-d.refreshCtx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(i, &d.cfg.Probes[i])
+	}
+}
+
+// probeAlert reports a probe that started failing or recovered; it must be called on
+// the UI goroutine
+func (d *Dashboard) probeAlert(p *config.HealthProbe, result probe.Result) {
+	if result.Up {
+		showToast(d.app, toastInfo, fmt.Sprintf("%s is up again (%s)", p.Container, p.Target()))
+		d.logAlert(result.At, history.SeverityInfo, "probe up", p.Container, fmt.Sprintf("%s in %s", p.Target(), formatLatency(result.Latency)))
+	} else {
+		showToast(d.app, toastWarning, fmt.Sprintf("%s is down: %s", p.Container, errorSummary(result.Err)))
+		d.logAlert(result.At, history.SeverityCritical, "probe down", p.Container, fmt.Sprintf("%s: %s", p.Target(), errorSummary(result.Err)))
+	}
+}
+
+// probeBadge renders the probe state of a list row: ▲ and the slowest latency when
+// every probe passes, ▼ when one fails, nothing for containers without probes
+func (d *Dashboard) probeBadge(container docker.ContainerInfo) string {
+	states := d.probes.For(container)
+	if len(states) == 0 {
+		return ""
+	}
+	t := currentTheme()
+	var slowest time.Duration
+	for _, s := range states {
+		switch {
+		case !s.checked:
+			return fmt.Sprintf(" [%s]◆[-]", t.Muted)
+		case !s.last.Up:
+			return fmt.Sprintf(" [%s]▼ down[-]", t.Error)
+		}
+		slowest = max(slowest, s.last.Latency)
+	}
+	return fmt.Sprintf(" [%s]▲ %s[-]", t.Success, formatLatency(slowest))
+}
+
+// probeDetails describes every probe of a container for the details panel
+func (d *Dashboard) probeDetails(container docker.ContainerInfo) string {
+	states := d.probes.For(container)
+	if len(states) == 0 {
+		return ""
+	}
+	t := currentTheme()
+	details := fmt.Sprintf("\n\n[%s::b]Probes:[-:-:-]", t.Accent)
+	for _, s := range states {
+		target := tview.Escape(s.probe.Target())
+		switch {
+		case !s.checked:
+			details += fmt.Sprintf("\n[%s]◆ %s: not checked yet[-]", t.Muted, target)
+		case s.last.Up:
+			status := ""
+			if s.last.Status != 0 {
+				status = fmt.Sprintf(" %d,", s.last.Status)
+			}
+			details += fmt.Sprintf("\n[%s]▲ %s:%s %s[-]", t.Success, target, status, formatLatency(s.last.Latency))
+		default:
+			details += fmt.Sprintf("\n[%s]▼ %s: %s[-]", t.Error, target, tview.Escape(errorSummary(s.last.Err)))
+			if s.failures > 1 {
+				details += fmt.Sprintf(" [%s](%d in a row)[-]", t.Muted, s.failures)
+			}
+		}
+	}
+	return details
+}
+
+// formatLatency rounds a latency for display, e.g. 12ms or 1.2s
+func formatLatency(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(100 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(10 * time.Microsecond).String()
+}
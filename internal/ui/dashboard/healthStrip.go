@@ -0,0 +1,288 @@
+package dashboard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// Segments of the health strip; the first three also filter the container list
+const (
+	healthUnhealthy = "unhealthy"
+	healthCrashLoop = "crash-looping"
+	healthHighCPU   = "high CPU"
+	healthDisk      = "disk"
+	healthDaemon    = "daemon"
+)
+
+// colorTags matches the color tags of a segment, dropped to draw it in the filter colors
+var colorTags = regexp.MustCompile(`\[[a-zA-Z#0-9:-]*\]`)
+
+// healthSegments lists the segments in the order they are shown
+var healthSegments = []string{healthUnhealthy, healthCrashLoop, healthHighCPU, healthDisk, healthDaemon}
+
+const (
+	// highCPUPercent is the CPU usage the strip counts as high, where the list turns red
+	highCPUPercent = 80
+	// diskCheckInterval is how often the strip looks at the free space of the Docker root dir
+	diskCheckInterval = 30 * time.Second
+)
+
+// healthStrip is the one-line fleet health summary above the container list
+type healthStrip struct {
+	view   *tview.TextView
+	cursor int          // segment under the cursor while the strip has the focus
+	disk   docker.Check // last disk check, for local daemons
+	diskOK bool         // whether the disk could be checked at all
+}
+
+// withHealthStrip puts the health strip on top of the containers view
+func (d *Dashboard) withHealthStrip(content tview.Primitive) tview.Primitive {
+	d.health = &healthStrip{
+		view: tview.NewTextView().
+			SetDynamicColors(true).
+			SetRegions(true).
+			SetWrap(false),
+	}
+	d.health.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyRight:
+			d.moveHealthCursor(1)
+		case tcell.KeyLeft:
+			d.moveHealthCursor(-1)
+		case tcell.KeyEnter:
+			d.openHealthSegment(healthSegments[d.health.cursor])
+		case tcell.KeyEscape, tcell.KeyF12:
+			d.health.view.Highlight()
+			d.app.SetFocus(d.list)
+		default:
+			if event.Rune() == ' ' {
+				d.openHealthSegment(healthSegments[d.health.cursor])
+			}
+		}
+		return nil
+	})
+
+	return tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(d.health.view, 1, 0, false).
+		AddItem(content, 0, 1, true)
+}
+
+// startHealthStrip keeps the strip current: counts follow the list stats, the disk is
+// checked less often since it asks the daemon
+func (d *Dashboard) startHealthStrip() {
+	go func() {
+		ticker := time.NewTicker(listStatsRedraw)
+		defer ticker.Stop()
+		var lastDisk time.Time
+		for {
+			if time.Since(lastDisk) >= diskCheckInterval && connection.Online() {
+				lastDisk = time.Now()
+				disk, ok := docker.DiskPressure()
+				d.app.QueueUpdate(func() {
+					d.health.disk, d.health.diskOK = disk, ok
+				})
+			}
+			d.app.QueueUpdateDraw(d.renderHealthStrip)
+
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// unhealthy reports whether Docker's healthcheck or one of the configured probes
+// reports the container down
+func (d *Dashboard) unhealthy(c docker.ContainerInfo) bool {
+	if strings.Contains(c.Status, "(unhealthy)") {
+		return true
+	}
+	for _, s := range d.probes.For(c) {
+		if s.checked && !s.last.Up {
+			return true
+		}
+	}
+	return false
+}
+
+// highCPU reports whether the container's last sampled CPU usage is high
+func (d *Dashboard) highCPU(c docker.ContainerInfo) bool {
+	s, ok := d.statsCollector.Get(c.ID)
+	return ok && s.cpu > highCPUPercent
+}
+
+// healthMatches applies the health filter picked on the strip to the list
+func (d *Dashboard) healthMatches(c docker.ContainerInfo) bool {
+	switch d.currentHealthFilter() {
+	case healthUnhealthy:
+		return d.unhealthy(c)
+	case healthCrashLoop:
+		return d.crashes.InLoop(c.ID)
+	case healthHighCPU:
+		return d.highCPU(c)
+	}
+	return true
+}
+
+// currentHealthFilter returns the health segment filtering the list, "" for none
+func (d *Dashboard) currentHealthFilter() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.healthFilter
+}
+
+// renderHealthStrip must be called on the UI goroutine
+func (d *Dashboard) renderHealthStrip() {
+	d.mu.RLock()
+	containers := d.containers
+	filter := d.healthFilter
+	d.mu.RUnlock()
+
+	t := currentTheme()
+	var unhealthy, looping, busy int
+	for _, c := range containers {
+		if d.unhealthy(c) {
+			unhealthy++
+		}
+		if d.crashes.InLoop(c.ID) {
+			looping++
+		}
+		if d.highCPU(c) {
+			busy++
+		}
+	}
+
+	count := func(n int, bad string) string {
+		if n == 0 {
+			return fmt.Sprintf("[%s]0", t.Success)
+		}
+		return fmt.Sprintf("[%s::b]%d", bad, n)
+	}
+	text := map[string]string{
+		healthUnhealthy: "❤ " + count(unhealthy, t.Error) + " unhealthy",
+		healthCrashLoop: "🔁 " + count(looping, t.Error) + " crash-looping",
+		healthHighCPU:   "🔥 " + count(busy, t.Warning) + " high CPU",
+	}
+	if d.cfg.LowPower {
+		text[healthHighCPU] = fmt.Sprintf("🔥 [%s]CPU n/a in low-power mode", t.Muted)
+	}
+
+	switch {
+	case !d.health.diskOK:
+		text[healthDisk] = fmt.Sprintf("💾 [%s]disk n/a", t.Muted)
+	case d.health.disk.Status == docker.CheckFail:
+		text[healthDisk] = fmt.Sprintf("💾 [%s::b]disk critical", t.Error)
+	case d.health.disk.Status == docker.CheckWarn:
+		text[healthDisk] = fmt.Sprintf("💾 [%s::b]disk low", t.Warning)
+	default:
+		text[healthDisk] = fmt.Sprintf("💾 [%s]disk ok", t.Success)
+	}
+
+	if state := connection.State(); state.online {
+		text[healthDaemon] = fmt.Sprintf("🐳 [%s]daemon up", t.Success)
+	} else {
+		text[healthDaemon] = fmt.Sprintf("🐳 [%s::b]daemon down %s", t.Error, time.Since(state.downSince).Round(time.Second))
+	}
+	if down := d.hostsDown(); down > 0 {
+		text[healthDaemon] += fmt.Sprintf("[-:-:-], [%s::b]%d hosts down", t.Error, down)
+	}
+
+	var b strings.Builder
+	b.WriteString(" ")
+	for i, segment := range healthSegments {
+		if i > 0 {
+			fmt.Fprintf(&b, " [%s]│[-] ", t.Muted)
+		}
+		if segment == filter {
+			fmt.Fprintf(&b, `["%s"][%s:%s] ▾ %s [-:-:-][""]`, segment, t.Background, t.Highlight, colorTags.ReplaceAllString(text[segment], ""))
+			continue
+		}
+		fmt.Fprintf(&b, `["%s"]%s[-:-:-][""]`, segment, text[segment])
+	}
+	fmt.Fprintf(&b, "   [%s]%s jump to[-]", t.Muted, d.keys.KeyLabel(actionFleetHealth))
+	d.health.view.SetText(b.String())
+}
+
+// hostsDown counts the hosts the all hosts view couldn't reach at the last refresh
+func (d *Dashboard) hostsDown() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if !d.allHosts {
+		return 0
+	}
+	down := 0
+	for _, r := range d.hostResults {
+		if r.Err != nil {
+			down++
+		}
+	}
+	return down
+}
+
+// focusHealthStrip moves the focus to the strip, switching to the containers tab
+func (d *Dashboard) focusHealthStrip() {
+	if d.currentTab != 0 {
+		d.switchTab(0)
+	}
+	d.app.SetFocus(d.health.view)
+	d.moveHealthCursor(0)
+}
+
+// moveHealthCursor moves the cursor by delta segments and says what the segment does
+func (d *Dashboard) moveHealthCursor(delta int) {
+	h := d.health
+	h.cursor = (h.cursor + delta + len(healthSegments)) % len(healthSegments)
+	segment := healthSegments[h.cursor]
+	h.view.Highlight(segment)
+
+	t := currentTheme()
+	switch segment {
+	case healthDisk:
+		detail := "free space is only checked for local daemons"
+		if h.diskOK {
+			detail = h.disk.Detail
+		}
+		d.flashStatus(fmt.Sprintf("[%s]%s · Enter opens diagnostics[-]", t.Muted, tview.Escape(detail)))
+	case healthDaemon:
+		d.flashStatus(fmt.Sprintf("[%s]Enter opens diagnostics[-]", t.Muted))
+	default:
+		action := "lists only these containers"
+		if d.currentHealthFilter() == segment {
+			action = "shows every container again"
+		}
+		d.flashStatus(fmt.Sprintf("[%s]Enter %s[-]", t.Muted, action))
+	}
+}
+
+// openHealthSegment filters the list down to the containers of a segment, or clears the
+// filter when it is already applied; disk and daemon open the diagnostics
+func (d *Dashboard) openHealthSegment(segment string) {
+	d.health.view.Highlight()
+	if segment == healthDisk || segment == healthDaemon {
+		showDiagnostics(d.app, d.mainFlex)
+		return
+	}
+
+	d.mu.Lock()
+	if d.healthFilter == segment {
+		d.healthFilter = ""
+	} else {
+		d.healthFilter = segment
+	}
+	d.mu.Unlock()
+
+	d.updateListTitle()
+	d.updateList()
+	d.renderHealthStrip()
+	d.app.SetFocus(d.list)
+}
@@ -0,0 +1,293 @@
+package dashboard
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// screenBinding is a fixed key of one of the secondary screens
+type screenBinding struct {
+	view        string
+	key         string
+	description string
+}
+
+// screenBindings documents the keys of the screens opened from the container list
+var screenBindings = []screenBinding{
+	{"Logs", "↑/↓", "Select line"},
+	{"Logs", "y", "Copy selected line (or last line)"},
+	{"Logs", "/", "Search, matches highlighted as you type"},
+	{"Logs", "n/N", "Next / previous match"},
+	{"Logs", "m", "Bookmark selected line (or last line)"},
+	{"Logs", "M", "List bookmarks"},
+	{"Logs", "t", "Jump to time"},
+	{"Logs", "PgUp/PgDn", "Scroll page"},
+	{"Logs", "Home/g", "Jump to top"},
+	{"Logs", "End", "Jump to bottom and follow"},
+	{"Logs", "Backspace/ESC/q/b", "Back (ESC clears an active search first)"},
+	{"Advanced Logs", "/ or s", "Focus search"},
+	{"Advanced Logs", "Enter", "Apply search"},
+	{"Advanced Logs", "c", "Clear search"},
+	{"Advanced Logs", "F2", "Cycle log level"},
+	{"Advanced Logs", "F3", "Toggle case sensitivity"},
+	{"Advanced Logs", "F4", "Toggle regex"},
+	{"Advanced Logs", "F5", "Toggle filter / highlight only"},
+	{"Advanced Logs", "F6", "Export"},
+	{"Advanced Logs", "F7", "Show JSON lines compact / raw"},
+	{"Advanced Logs", "f", "Focus field filter for JSON lines"},
+	{"Advanced Logs", "↑/↓", "Select line"},
+	{"Advanced Logs", "Enter", "Expand selected line, JSON pretty-printed"},
+	{"Advanced Logs", "m", "Bookmark selected line (or last line)"},
+	{"Advanced Logs", "M", "List bookmarks"},
+	{"Advanced Logs", "t", "Jump to time"},
+	{"Advanced Logs", "Backspace/ESC/q", "Back"},
+	{"Stats", "p", "Pause / resume"},
+	{"Stats", "r", "Reset statistics"},
+	{"Stats", "g", "Switch between sparklines and braille charts"},
+	{"Stats", "Backspace/ESC/q", "Back"},
+	{"Inspect", "←/→ Tab 1-9", "Switch Info / Env / Mounts / Network / Labels / DNS / Run / Users / JSON"},
+	{"Inspect", "l", "DNS: look up names inside the container"},
+	{"Inspect", "o", "Mounts: copy a cd into the mount's host directory"},
+	{"Inspect", "w", "Save the docker run command as a script"},
+	{"Inspect", "y/Enter", "Copy selected field"},
+	{"Inspect", "r", "Reveal / mask secret env values"},
+	{"Inspect", "J", "Run a jq query on the inspect output"},
+	{"Inspect", "Enter/Space", "JSON: fold / unfold the selected node"},
+	{"Inspect", "+/-", "JSON: unfold / fold all"},
+	{"Inspect", "/", "JSON: find a path or text"},
+	{"Inspect", "n", "JSON: next match"},
+	{"Inspect", "p", "JSON: copy the path of the selected node"},
+	{"Inspect", "Backspace/ESC/q", "Back"},
+	{"Shell Menu", "1", "Interactive shell"},
+	{"Shell Menu", "2", "Quick command"},
+	{"Shell Menu", "3", "File browser"},
+	{"Shell Menu", "4", "System info"},
+	{"Shell Menu", "5", "Advanced exec: user, working dir, env, privileged, TTY"},
+	{"Shell Menu", "6", "Choose shell (bash / ash / sh / busybox)"},
+	{"Shell Menu", "7", "Connectivity test to another container or host"},
+	{"Shell Menu", "q", "Cancel"},
+	{"Shell", "Enter", "Execute command"},
+	{"Shell", "↑/↓", "Command history"},
+	{"Shell", "Ctrl-R", "Search command history (again for older matches, ESC cancels)"},
+	{"Shell", "Tab", "Complete program or path (lists the choices when ambiguous)"},
+	{"Shell", "1-9", "Insert quick command"},
+	{"Shell", "Ctrl-C", "Clear output"},
+	{"Shell", "F2", "Show / strip ANSI colors in new output"},
+	{"Shell", "ESC", "Back"},
+	{"Bulk Actions", "1-9", "Start / stop / restart / delete / export / pause / unpause / pull / limits"},
+	{"Bulk Actions", "e", "Run a command in every selected container"},
+	{"Bulk Actions", "c", "Compare stats of 2-5 containers"},
+	{"Bulk Actions", "s", "Send a signal to every selected container"},
+	{"Bulk Actions", "f", "Diff the config of 2 containers"},
+	{"Bulk Command", "Enter", "Show the full output of a container"},
+	{"Bulk Command", "c", "Cancel containers not started yet"},
+	{"Bulk Progress", "c/ESC", "Cancel operations not started yet"},
+	{"Bulk Actions", "q/ESC", "Cancel"},
+	{"Start Profile", "1-9", "Start the profile's groups in order"},
+	{"Start Profile", "c/ESC", "Cancel the groups not started yet"},
+	{"Label Browser", "Enter", "Group by key / filter by value"},
+	{"Label Browser", "Tab", "Switch pane"},
+	{"Label Browser", "c", "Clear grouping and filter"},
+	{"Label Browser", "ESC", "Back"},
+	{"Log Archive", "Enter/space", "Start / stop archiving the selected container"},
+	{"Log Archive", "Backspace/ESC/q", "Back"},
+	{"Recently Deleted", "Enter", "Restore the container from its rescue image"},
+	{"Recently Deleted", "d", "Delete the rescue image"},
+	{"Recently Deleted", "Backspace/ESC/q", "Back"},
+	{"Cleanup", "d", "Remove the selected resource"},
+	{"Cleanup", "+/-", "Change how many days exited containers are kept"},
+	{"Cleanup", "r", "Scan again"},
+	{"Cleanup", "Backspace/ESC/q", "Back"},
+	{"Uptime", "1-3 or ←/→", "Last 24h / 7d / 30d"},
+	{"Uptime", "e", "Export the report as CSV"},
+	{"Uptime", "r", "Reload"},
+	{"Uptime", "Backspace/ESC/q", "Back"},
+	{"Plugins", "1-9", "Run a plugin"},
+	{"Plugins", "r", "Run again"},
+	{"Plugins", "y", "Copy output"},
+	{"Plugins", "Backspace/ESC/q", "Back"},
+	{"Volumes", "Enter", "Browse the files of the selected volume"},
+	{"Volume Browser", "Enter", "Open directory"},
+	{"Volume Browser", "Backspace", "Parent directory"},
+	{"Volume Browser", "s", "Download file (directories as .tar)"},
+	{"Volume Browser", "ESC/q", "Close"},
+	{"Diagnostics", "↑/↓", "Select check to see its fix"},
+	{"Diagnostics", "r", "Run checks again"},
+	{"Diagnostics", "Backspace/ESC/q", "Back"},
+	{"Images", "Space", "Mark / unmark the selected image"},
+	{"Images", "t", "Tag the marked images anew"},
+	{"Images", "d", "Remove the marked images, listed as a dry run first"},
+	{"Images", "p", "Remove all but the newest tags of a repository"},
+	{"Image Batch", "a", "Apply the listed steps"},
+	{"Image Batch", "ESC/q", "Cancel"},
+	{"Images", "s", "Save the marked images to a tar"},
+	{"Images", "l", "Load images from a tar"},
+	{"Image Archive", "ESC", "Cancel a running save or load"},
+	{"Image Archive", "Enter/ESC", "Close when done"},
+	{"Images", "r", "Browse the configured registries"},
+	{"Registry", "Enter", "Show the tags of the repository"},
+	{"Registry", "Tab", "Switch pane"},
+	{"Registry", "Space", "Mark / unmark the selected tag"},
+	{"Registry", "p", "Pull the marked tags"},
+	{"Registry", "c", "Copy the image reference"},
+	{"Registry", "F5", "Reload"},
+	{"Registry", "ESC/q", "Close"},
+	{"Images", "n", "Run a new container from the selected image"},
+	{"Images", "Enter", "Analyze the layers of the selected image"},
+	{"Image Analysis", "Tab", "Switch pane"},
+	{"Image Analysis", "w", "Show wasted files / files of the layer"},
+	{"Image Analysis", "ESC/q", "Back"},
+	{"Capabilities", "Space", "Tick / untick the selected capability"},
+	{"Capabilities", "p", "Toggle privileged"},
+	{"Capabilities", "x", "Reset to the running settings"},
+	{"Capabilities", "a", "Recreate the container with the changes"},
+	{"Capabilities", "ESC/q", "Back"},
+	{"History", "1-9 or ←/→", "Switch range"},
+	{"History", "r", "Reload"},
+	{"History", "Backspace/ESC/q", "Back"},
+	{"Timeline", "1-9 or ←/→", "Switch range"},
+	{"Timeline", "r", "Reload"},
+	{"Timeline", "Backspace/ESC/q", "Back"},
+	{"Deploy Watch", "l", "Open the logs of the selected container"},
+	{"Deploy Watch", "n", "Mark the deploy as starting now"},
+	{"Deploy Watch", "c", "Clear the transitions"},
+	{"Deploy Watch", "ESC/q", "Back"},
+	{"Blue/Green", "1", "Start the green container"},
+	{"Blue/Green", "2", "Check the green container"},
+	{"Blue/Green", "3", "Promote green"},
+	{"Blue/Green", "r", "Roll back to blue"},
+	{"Blue/Green", "ESC/q", "Back"},
+	{"Pinning", "p", "Pin the selected image to its digest"},
+	{"Pinning", "P", "Pin every mutable image"},
+	{"Pinning", "e", "Export the audit as CSV"},
+	{"Pinning", "r", "Rescan"},
+	{"Pinning", "ESC/q", "Back"},
+	{"Auto-follow", "Follow", "Follow containers matching the name and labels"},
+	{"Auto-follow", "Stop", "Pause following"},
+	{"Auto-follow", "ESC", "Cancel"},
+	{"Overview", "ESC/q", "Back"},
+}
+
+// helpRows returns every binding as (view, key, description), main view first
+func (km *KeyMap) helpRows() [][3]string {
+	var rows [][3]string
+	for _, b := range km.bindings {
+		var keys []string
+		for _, key := range b.keys {
+			keys = append(keys, displayKey(key))
+		}
+		rows = append(rows, [3]string{"Containers", strings.Join(keys, " "), b.description})
+		if b.action == actionLabels {
+			rows = append(rows,
+				[3]string{"Containers", "1-9", "Switch to saved view"},
+				[3]string{"Containers", "0", "Show all containers"})
+		}
+	}
+	for _, b := range screenBindings {
+		rows = append(rows, [3]string{b.view, b.key, b.description})
+	}
+	return rows
+}
+
+// showOverlay draws content centered on top of mainView until closed
+func showOverlay(app *tview.Application, mainView tview.Primitive, content tview.Primitive, width, height int) {
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	setRoot(app, mainView)
+	setOverlay(app, centered)
+	app.SetFocus(content)
+}
+
+// ShowHelp displays a searchable overlay with every keybinding, grouped per view
+func ShowHelp(app *tview.Application, mainView tview.Primitive, keys *KeyMap) {
+	t := currentTheme()
+	rows := keys.helpRows()
+
+	search := tview.NewInputField().
+		SetLabel("🔍 ").
+		SetPlaceholder("type to filter keybindings...").
+		SetFieldWidth(0)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	fill := func(filter string) {
+		table.Clear()
+		for col, h := range []string{"VIEW", "KEY", "ACTION"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		filter = strings.ToLower(filter)
+		r := 1
+		for _, row := range rows {
+			if filter != "" && !strings.Contains(strings.ToLower(strings.Join(row[:], " ")), filter) {
+				continue
+			}
+			table.SetCell(r, 0, tview.NewTableCell(row[0]).SetTextColor(tcell.GetColor(t.Accent)))
+			table.SetCell(r, 1, tview.NewTableCell(row[1]).SetTextColor(tcell.GetColor(t.Success)))
+			table.SetCell(r, 2, tview.NewTableCell(row[2]).SetTextColor(tview.Styles.PrimaryTextColor).SetExpansion(1))
+			r++
+		}
+		table.ScrollToBeginning()
+	}
+	fill("")
+
+	search.SetChangedFunc(fill)
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(search, 1, 0, true).
+		AddItem(table, 0, 1, false)
+	layout.SetBorder(true).
+		SetTitle(" ❓ Keybindings (ESC to close, ↓ to browse) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	close := func() {
+		setRoot(app, mainView)
+	}
+
+	search.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			close()
+			return nil
+		case tcell.KeyDown, tcell.KeyEnter:
+			app.SetFocus(table)
+			return nil
+		}
+		return event
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == '?' || event.Rune() == 'q':
+			close()
+			return nil
+		case event.Rune() == '/':
+			app.SetFocus(search)
+			return nil
+		case event.Key() == tcell.KeyUp:
+			if row, _ := table.GetSelection(); row <= 1 {
+				app.SetFocus(search)
+				return nil
+			}
+		}
+		return event
+	})
+
+	showOverlay(app, mainView, layout, 80, 30)
+}
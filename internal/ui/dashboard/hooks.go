@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"slices"
+
+	"devops-dashboard/internal/audit"
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/hooks"
+	"devops-dashboard/internal/plugin"
+)
+
+// lifecycleHooks runs the configured hooks around container actions and keeps the
+// audit log; a nil runner runs the actions alone
+var lifecycleHooks *hooks.Runner
+
+// setHooks loads the hooks and opens the audit log unless the config turns it off
+func setHooks(cfg *config.Config) {
+	var log *audit.Log
+	if cfg.AuditLog != "off" {
+		path := cfg.AuditLog
+		if path == "" {
+			path = audit.DefaultPath()
+		}
+		log = audit.Open(path)
+	}
+	lifecycleHooks = hooks.New(cfg.Hooks, log)
+}
+
+// hooked runs an action on a container between its pre and post hooks
+func hooked(action string, c docker.ContainerInfo, fn func() error) error {
+	return lifecycleHooks.Around(action, pluginTarget(c), fn)
+}
+
+// hookSteps runs the hooks of a bulk action around its steps. A restart is a stop and
+// a start, so its pre hooks run before the stop and its post hooks after the start.
+func hookSteps(phases [][]bulkStep, action string, selected []docker.ContainerInfo) {
+	if !slices.Contains(config.HookActions, action) {
+		return
+	}
+	targets := make(map[string]plugin.Target, len(selected))
+	for _, c := range selected {
+		targets[c.ID] = pluginTarget(c)
+	}
+
+	for _, wave := range phases {
+		for i := range wave {
+			step := &wave[i]
+			run := step.run
+			target, ok := targets[step.id]
+			if !ok {
+				target = pluginTarget(docker.ContainerInfo{ID: step.id, Name: step.id[:12]})
+			}
+
+			switch {
+			case action != "restart":
+				step.run = func(id string) (string, error) {
+					var note string
+					err := lifecycleHooks.Around(action, target, func() error {
+						var err error
+						note, err = run(id)
+						return err
+					})
+					return note, err
+				}
+			case step.op == "stop":
+				step.run = func(id string) (string, error) {
+					if err := lifecycleHooks.Pre(action, target); err != nil {
+						lifecycleHooks.Record(action, target, err)
+						return "", err
+					}
+					note, err := run(id)
+					if err != nil {
+						lifecycleHooks.Record(action, target, err)
+						lifecycleHooks.Post(action, target, err)
+					}
+					return note, err
+				}
+			default:
+				step.run = func(id string) (string, error) {
+					note, err := run(id)
+					lifecycleHooks.Record(action, target, err)
+					lifecycleHooks.Post(action, target, err)
+					return note, err
+				}
+			}
+		}
+	}
+}
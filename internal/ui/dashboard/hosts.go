@@ -0,0 +1,241 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// switchTimeout bounds the first call to a newly selected host
+const switchTimeout = 15 * time.Second
+
+// HostEndpoint converts a configured host into the endpoint the docker package connects to
+func HostEndpoint(h config.Host) docker.Endpoint {
+	return docker.Endpoint{
+		Name:     h.Name,
+		Host:     h.URL,
+		SSHKey:   h.SSHKey,
+		SSHAgent: h.SSHAgent,
+		TLSCA:    h.TLSCA,
+		TLSCert:  h.TLSCert,
+		TLSKey:   h.TLSKey,
+	}
+}
+
+// SelectHost resolves the --host flag: a configured host name, a daemon URL, or empty for
+// the first configured host (or the Docker environment variables when none are configured)
+func SelectHost(cfg *config.Config, name string) (docker.Endpoint, error) {
+	switch {
+	case name == "" && len(cfg.Hosts) > 0:
+		return HostEndpoint(cfg.Hosts[0]), nil
+	case name == "":
+		return docker.Endpoint{}, nil
+	case strings.Contains(name, "://"):
+		return docker.Endpoint{Name: name, Host: name}, nil
+	}
+
+	h, ok := cfg.FindHost(name)
+	if !ok {
+		return docker.Endpoint{}, fmt.Errorf("unknown host %q; add it under \"hosts\" in %s or pass a URL", name, config.Path())
+	}
+	return HostEndpoint(*h), nil
+}
+
+// showHostSwitcher lists the configured hosts and connects to the chosen one
+func (d *Dashboard) showHostSwitcher() {
+	if len(d.cfg.Hosts) == 0 {
+		showMessage(d.app, d.mainFlex, "🖧 Hosts",
+			fmt.Sprintf("No hosts configured.\n\nAdd a \"hosts\" list to %s to switch between Docker daemons.", config.Path()))
+		return
+	}
+
+	t := currentTheme()
+	current := docker.CurrentEndpoint()
+	allHosts := d.allHostsMode()
+	list := tview.NewList().ShowSecondaryText(true)
+	for _, h := range d.cfg.Hosts {
+		h := h
+		name := h.Name
+		if h.Name == current.Name && !allHosts {
+			name = fmt.Sprintf("[%s]● %s[-]", t.Success, h.Name)
+		}
+		url := h.URL
+		switch {
+		case h.TLSCert != "":
+			url += "  🔒 mTLS"
+		case h.TLSCA != "":
+			url += "  🔒 TLS"
+		}
+		list.AddItem(name, url, 0, func() {
+			setRoot(d.app, d.mainFlex)
+			d.switchHost(HostEndpoint(h))
+		})
+	}
+	if len(d.cfg.Hosts) > 1 {
+		name := "⧉ All hosts"
+		if allHosts {
+			name = fmt.Sprintf("[%s]● %s[-]", t.Success, name)
+		}
+		list.AddItem(name, fmt.Sprintf("%d hosts in one list", len(d.cfg.Hosts)), 0, func() {
+			setRoot(d.app, d.mainFlex)
+			d.showAllHosts()
+		})
+	}
+	list.SetBorder(true).
+		SetTitle(" 🖧 Switch Host (Enter to connect, ESC to cancel) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+	list.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+	})
+
+	showOverlay(d.app, d.mainFlex, list, 60, 2*list.GetItemCount()+4)
+}
+
+// switchHost points the dashboard at another daemon and reloads everything host specific
+func (d *Dashboard) switchHost(e docker.Endpoint) {
+	docker.SetEndpoint(e)
+
+	d.mu.Lock()
+	d.allHosts = false
+	d.hostResults = nil
+	d.containers = nil
+	d.selectedIndex = 0
+	d.mu.Unlock()
+	d.bulkMode.Clear()
+
+	d.restartEventsWorker()
+	d.loadDaemonProfiles()
+	d.updateListTitle()
+	d.list.Clear()
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Connecting to %s...[-]", currentTheme().Warning, tview.Escape(e.Name)))
+
+	// ssh hosts can take a while to answer, so don't block the UI on the first call
+	go func() {
+		err := docker.Ping(switchTimeout)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, "❌ "+e.Name, err)
+				return
+			}
+			d.updateList()
+			d.flashStatus(fmt.Sprintf("[%s]Connected to %s[-]", currentTheme().Success, tview.Escape(e.Name)))
+			if tab := d.tabs[d.currentTab]; tab.onShow != nil {
+				tab.onShow()
+			}
+		})
+	}()
+}
+
+// showAllHosts lists the containers of every configured host together. Actions on a
+// container go to the host it was listed on, and bulk actions run across hosts in parallel.
+func (d *Dashboard) showAllHosts() {
+	d.mu.Lock()
+	d.allHosts = true
+	d.hostResults = nil
+	d.containers = nil
+	d.selectedIndex = 0
+	d.mu.Unlock()
+	d.bulkMode.Clear()
+
+	d.restartEventsWorker()
+	d.updateListTitle()
+	d.list.Clear()
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Connecting to %d hosts...[-]", currentTheme().Warning, len(d.cfg.Hosts)))
+	d.updateList()
+}
+
+// allHostsMode reports whether the container list spans every configured host
+func (d *Dashboard) allHostsMode() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.allHosts
+}
+
+// hostEndpoints returns the endpoints of all configured hosts
+func (d *Dashboard) hostEndpoints() []docker.Endpoint {
+	endpoints := make([]docker.Endpoint, len(d.cfg.Hosts))
+	for i, h := range d.cfg.Hosts {
+		endpoints[i] = HostEndpoint(h)
+	}
+	return endpoints
+}
+
+// updateAllHostsList lists every host off the UI goroutine, since a slow or unreachable
+// host would otherwise freeze the dashboard. Unreachable hosts are reported in the system
+// info panel.
+func (d *Dashboard) updateAllHostsList() {
+	go func() {
+		listedAt := time.Now()
+		results := docker.ListContainersOnHosts(d.hostEndpoints())
+		var containers []docker.ContainerInfo
+		for _, r := range results {
+			containers = append(containers, r.Containers...)
+		}
+
+		d.app.QueueUpdateDraw(func() {
+			// The user may have switched to a single host in the meantime
+			if !d.allHostsMode() {
+				return
+			}
+			d.mu.Lock()
+			d.hostResults = results
+			d.mu.Unlock()
+			d.renderList(containers)
+			d.mu.Lock()
+			d.listedAt = listedAt
+			d.mu.Unlock()
+		})
+	}()
+}
+
+// hostSummary renders per-host container counts for the system info panel
+func (d *Dashboard) hostSummary() string {
+	d.mu.RLock()
+	results := d.hostResults
+	allHosts := d.allHosts
+	d.mu.RUnlock()
+	if !allHosts {
+		return ""
+	}
+
+	t := currentTheme()
+	summary := fmt.Sprintf("[%s::b]Hosts:[-:-:-]\n", t.Info)
+	for _, r := range results {
+		name := tview.Escape(r.Endpoint.Name)
+		if r.Err != nil {
+			summary += fmt.Sprintf("  [%s]✖ %s: %s[-]\n", t.Error, name, tview.Escape(errorSummary(r.Err)))
+			continue
+		}
+		summary += fmt.Sprintf("  %s [%s]%d[-]/%d\n", name, t.Success, countRunning(r.Containers), len(r.Containers))
+	}
+	return summary + "\n"
+}
+
+// qualifiedName prefixes a container name with its host in the all hosts view
+func qualifiedName(c docker.ContainerInfo) string {
+	if c.Host == "" {
+		return c.Name
+	}
+	return c.Host + "/" + c.Name
+}
+
+// hostLabel names the host of a container for exported metrics and shipped logs: the
+// endpoint name, or the machine name for an unnamed local daemon
+func hostLabel(c docker.ContainerInfo) string {
+	if c.Host != "" {
+		return c.Host
+	}
+	if name := docker.CurrentEndpoint().Name; name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
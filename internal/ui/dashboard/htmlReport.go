@@ -0,0 +1,284 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// reportAlerts is how many of the recent alerts the HTML report lists
+const reportAlerts = 50
+
+// fleetReport is what the HTML report shows, gathered on the UI goroutine
+type fleetReport struct {
+	Generated  time.Time
+	Host       string
+	Total      int
+	Running    int
+	Unhealthy  int
+	CrashLoops int
+	HostErrors []string
+	Containers []reportContainer
+	Alerts     []reportAlert
+}
+
+// reportContainer is one row of the container table
+type reportContainer struct {
+	Name      string
+	Image     string
+	State     string
+	Status    string
+	Ports     string
+	Team      string
+	Health    string // Docker's healthcheck: healthy, unhealthy, starting or ""
+	Stats     bool   // whether CPU and memory were sampled
+	CPU       float64
+	Mem       float64
+	MemUsage  string
+	CrashLoop bool
+	OOMs      int // OOM kills today
+	Probes    []reportProbe
+}
+
+// reportProbe is the last result of a health probe
+type reportProbe struct {
+	Target  string
+	Checked bool
+	Up      bool
+	Detail  string
+}
+
+// reportAlert is an alert of the Events tab
+type reportAlert struct {
+	At        time.Time
+	Class     string
+	Kind      string
+	Container string
+	Detail    string
+}
+
+// alertClasses maps the severities of alerts to CSS classes
+var alertClasses = map[string]string{history.SeverityCritical: "bad", history.SeverityWarning: "warn", history.SeverityInfo: "ok"}
+
+// exportReport writes a static HTML report of the fleet to the download directory, for
+// people who won't open a terminal
+func (d *Dashboard) exportReport() {
+	report := d.gatherReport()
+	dir := d.downloadDir()
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Writing HTML report...[-]", currentTheme().Warning))
+	go func() {
+		path := filepath.Join(dir, fmt.Sprintf("dockpulse-report-%s.html", report.Generated.Format("20060102-150405")))
+		err := writeReport(path, report)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, "❌ Report Failed", err)
+				return
+			}
+			d.updateStatusBar()
+			showToast(d.app, toastSuccess, fmt.Sprintf("Report of %d containers written to %s", report.Total, path))
+		})
+	}()
+}
+
+// gatherReport collects the list, the stats snapshot, health states and alerts; it
+// must be called on the UI goroutine
+func (d *Dashboard) gatherReport() fleetReport {
+	d.mu.RLock()
+	containers := d.containers
+	hostResults := d.hostResults
+	d.mu.RUnlock()
+
+	report := fleetReport{
+		Generated: time.Now(),
+		Host:      hostLabel(docker.ContainerInfo{}),
+		Total:     len(containers),
+		Running:   countRunning(containers),
+	}
+	if d.allHostsMode() {
+		report.Host = "all hosts"
+		for _, r := range hostResults {
+			if r.Err != nil {
+				report.HostErrors = append(report.HostErrors, fmt.Sprintf("%s: %s", r.Endpoint.Name, errorSummary(r.Err)))
+			}
+		}
+	}
+
+	snapshot := d.statsCollector.Snapshot()
+	for _, c := range containers {
+		row := reportContainer{
+			Name:      qualifiedName(c),
+			Image:     c.Image,
+			State:     c.State,
+			Status:    c.Status,
+			Ports:     c.Ports,
+			CrashLoop: d.crashes.InLoop(c.ID),
+			OOMs:      d.ooms.Today(c.ID),
+		}
+		for _, health := range []string{"unhealthy", "healthy", "starting"} {
+			if strings.Contains(c.Status, health) {
+				row.Health = health
+				break
+			}
+		}
+		for _, e := range ownerOf(c) {
+			if e.field.name == "Team" {
+				row.Team = e.value
+			}
+		}
+		if s, ok := snapshot[c.ID]; ok {
+			row.Stats, row.CPU, row.Mem = true, s.cpu, s.mem
+			if s.stats != nil {
+				row.MemUsage = s.stats.MemUsage
+			}
+		}
+		for _, s := range d.probes.For(c) {
+			p := reportProbe{Target: s.probe.Target(), Checked: s.checked, Up: s.last.Up}
+			switch {
+			case !s.checked:
+			case s.last.Up:
+				p.Detail = formatLatency(s.last.Latency)
+			default:
+				p.Detail = errorSummary(s.last.Err)
+			}
+			row.Probes = append(row.Probes, p)
+		}
+
+		if d.unhealthy(c) {
+			report.Unhealthy++
+		}
+		if row.CrashLoop {
+			report.CrashLoops++
+		}
+		report.Containers = append(report.Containers, row)
+	}
+	// Problems first, then by name
+	slices.SortStableFunc(report.Containers, func(a, b reportContainer) int {
+		if ra, rb := reportRank(a), reportRank(b); ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	alerts := d.alerts[max(0, len(d.alerts)-reportAlerts):]
+	for i := len(alerts) - 1; i >= 0; i-- {
+		a := alerts[i]
+		report.Alerts = append(report.Alerts, reportAlert{a.at, alertClasses[a.severity], a.kind, a.container, a.detail})
+	}
+	return report
+}
+
+// reportRank orders containers in the report: crash loops and failing health first,
+// then the running ones, stopped containers last
+func reportRank(c reportContainer) int {
+	failing := c.CrashLoop || c.Health == "unhealthy" || c.OOMs > 0
+	for _, p := range c.Probes {
+		failing = failing || (p.Checked && !p.Up)
+	}
+	switch {
+	case failing:
+		return 0
+	case c.State == "running":
+		return 1
+	}
+	return 2
+}
+
+// writeReport renders the report to path
+func writeReport(path string, report fleetReport) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, report); err != nil {
+		f.Close()
+		return fmt.Errorf("render report: %w", err)
+	}
+	return f.Close()
+}
+
+// usageClass colors a percentage like the list does
+func usageClass(percent float64) string {
+	switch {
+	case percent > 80:
+		return "bad"
+	case percent > 50:
+		return "warn"
+	}
+	return "ok"
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"usage": usageClass,
+	"pct":   func(v float64) string { return fmt.Sprintf("%.1f%%", v) },
+	"bar":   func(v float64) string { return fmt.Sprintf("%.0f%%", min(max(v, 0), 100)) },
+	"time":  func(t time.Time) string { return t.Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>DockPulse report: {{.Host}}, {{time .Generated}}</title>
+<style>
+body { font: 14px/1.4 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0; }
+.muted { color: #777; }
+.cards { display: flex; gap: 1em; margin: 1.5em 0; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: .8em 1.2em; min-width: 8em; }
+.card b { display: block; font-size: 1.8em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: .4em .6em; border-bottom: 1px solid #eee; vertical-align: top; }
+th { background: #f6f6f6; }
+.ok { color: #1a7f37; }
+.warn { color: #9a6700; }
+.bad { color: #cf222e; font-weight: bold; }
+.bar { background: #eee; border-radius: 3px; height: 6px; width: 8em; }
+.bar span { display: block; height: 6px; border-radius: 3px; background: currentColor; }
+.probe { white-space: nowrap; }
+</style>
+</head>
+<body>
+<h1>🐳 Fleet report</h1>
+<p class="muted">{{.Host}} · generated by DockPulse on {{time .Generated}}</p>
+{{range .HostErrors}}<p class="bad">Unreachable host {{.}}</p>{{end}}
+
+<div class="cards">
+<div class="card"><b>{{.Total}}</b>containers</div>
+<div class="card"><b class="ok">{{.Running}}</b>running</div>
+<div class="card"><b class="{{if .Unhealthy}}bad{{else}}ok{{end}}">{{.Unhealthy}}</b>unhealthy</div>
+<div class="card"><b class="{{if .CrashLoops}}bad{{else}}ok{{end}}">{{.CrashLoops}}</b>crash-looping</div>
+</div>
+
+<h2>Containers</h2>
+<table>
+<tr><th>Name</th><th>State</th><th>Health</th><th>CPU</th><th>Memory</th><th>Image</th><th>Ports</th><th>Team</th></tr>
+{{range .Containers}}<tr>
+<td><b>{{.Name}}</b>{{if .CrashLoop}} <span class="bad">crash loop</span>{{end}}{{if .OOMs}} <span class="bad">OOM ×{{.OOMs}} today</span>{{end}}</td>
+<td class="{{if eq .State "running"}}ok{{else}}muted{{end}}">{{.State}}<br><small class="muted">{{.Status}}</small></td>
+<td>{{if .Health}}<span class="{{if eq .Health "healthy"}}ok{{else if eq .Health "unhealthy"}}bad{{else}}warn{{end}}">{{.Health}}</span><br>{{end}}
+{{range .Probes}}<span class="probe {{if not .Checked}}muted{{else if .Up}}ok{{else}}bad{{end}}">{{if not .Checked}}◆{{else if .Up}}▲{{else}}▼{{end}} {{.Target}} {{.Detail}}</span><br>{{end}}</td>
+<td>{{if .Stats}}<div class="{{usage .CPU}}">{{pct .CPU}}<div class="bar"><span style="width: {{bar .CPU}}"></span></div></div>{{else}}<span class="muted">-</span>{{end}}</td>
+<td>{{if .Stats}}<div class="{{usage .Mem}}">{{pct .Mem}}<div class="bar"><span style="width: {{bar .Mem}}"></span></div></div><small class="muted">{{.MemUsage}}</small>{{else}}<span class="muted">-</span>{{end}}</td>
+<td>{{.Image}}</td>
+<td>{{.Ports}}</td>
+<td>{{.Team}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Recent alerts</h2>
+{{if .Alerts}}<table>
+<tr><th>Time</th><th>Alert</th><th>Container</th><th>Detail</th></tr>
+{{range .Alerts}}<tr><td>{{time .At}}</td><td class="{{.Class}}">{{.Kind}}</td><td>{{.Container}}</td><td>{{.Detail}}</td></tr>
+{{end}}</table>
+{{else}}<p class="muted">No alerts since DockPulse started.</p>{{end}}
+</body>
+</html>
+`))
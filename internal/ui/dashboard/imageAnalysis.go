@@ -0,0 +1,224 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// maxChangesShown caps the file rows of one layer; the largest changes come first
+const maxChangesShown = 1000
+
+// showImageAnalysis shows what every layer of an image adds, modifies and removes, and
+// which files waste space by being stored more than once or removed in a later layer.
+// back is where ESC returns to.
+func (d *Dashboard) showImageAnalysis(imageID, name string, back tview.Primitive) {
+	t := currentTheme()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+	header.SetText(fmt.Sprintf(" [%s]Saving %s to read its layers...[-]", t.Muted, tview.Escape(name)))
+
+	layersTable := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	layersTable.SetBorder(true).
+		SetTitle(" 🧱 Layers ").
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	files := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	files.SetBorder(true).
+		SetBorderColor(tcell.GetColor(t.Muted)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[black:green] Tab [-:-:-] Switch pane   [black:green] w [-:-:-] Wasted files / layer files   [black:red] ESC [-:-:-] Back")
+
+	body := tview.NewFlex().
+		AddItem(layersTable, 0, 2, true).
+		AddItem(files, 0, 3, false)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var (
+		analysis   *docker.ImageAnalysis
+		showWasted bool
+	)
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+
+	headerCell := func(table *tview.Table, col int, text string) {
+		table.SetCell(0, col, tview.NewTableCell(text).
+			SetTextColor(tcell.GetColor(t.Highlight)).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	// renderFiles fills the right pane with the changes of the selected layer or, with
+	// showWasted, the wasted files of the whole image
+	renderFiles := func() {
+		files.Clear()
+		if analysis == nil {
+			return
+		}
+		if showWasted {
+			files.SetTitle(fmt.Sprintf(" ♻️  Wasted space: %s in %d files ", docker.FormatBytes(uint64(analysis.Wasted)), len(analysis.WastedFiles)))
+			for col, h := range []string{"WASTED", "LAYERS", "PATH"} {
+				headerCell(files, col, h)
+			}
+			for i, w := range analysis.WastedFiles[:min(len(analysis.WastedFiles), maxChangesShown)] {
+				files.SetCell(i+1, 0, tview.NewTableCell(docker.FormatBytes(uint64(w.Wasted))).SetTextColor(tcell.GetColor(sizeColor(w.Wasted))).SetAlign(tview.AlignRight))
+				files.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%d", w.Layers)).SetAlign(tview.AlignRight))
+				files.SetCell(i+1, 2, tview.NewTableCell(tview.Escape("/"+w.Path)).SetExpansion(1))
+			}
+			if len(analysis.WastedFiles) == 0 {
+				files.SetCell(1, 2, tview.NewTableCell("(nothing wasted)").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+			}
+			files.ScrollToBeginning()
+			return
+		}
+
+		row, _ := layersTable.GetSelection()
+		if row < 1 || row > len(analysis.Layers) {
+			return
+		}
+		layer := analysis.Layers[row-1]
+		files.SetTitle(fmt.Sprintf(" 📄 Layer %d: %d changes ", row, len(layer.Changes)))
+		for col, h := range []string{"", "SIZE", "PATH"} {
+			headerCell(files, col, h)
+		}
+		colors := map[string]string{docker.ChangeAdded: t.Success, docker.ChangeModified: t.Warning, docker.ChangeRemoved: t.Error}
+		marks := map[string]string{docker.ChangeAdded: "+", docker.ChangeModified: "~", docker.ChangeRemoved: "-"}
+		for i, c := range layer.Changes[:min(len(layer.Changes), maxChangesShown)] {
+			color := tcell.GetColor(colors[c.Kind])
+			files.SetCell(i+1, 0, tview.NewTableCell(marks[c.Kind]).SetTextColor(color))
+			files.SetCell(i+1, 1, tview.NewTableCell(docker.FormatBytes(uint64(c.Size))).SetTextColor(tcell.GetColor(sizeColor(c.Size))).SetAlign(tview.AlignRight))
+			files.SetCell(i+1, 2, tview.NewTableCell(tview.Escape("/"+c.Path)).SetTextColor(color).SetExpansion(1))
+		}
+		if len(layer.Changes) > maxChangesShown {
+			files.SetCell(maxChangesShown+1, 2, tview.NewTableCell(fmt.Sprintf("… %d smaller changes not listed", len(layer.Changes)-maxChangesShown)).
+				SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+		if len(layer.Changes) == 0 {
+			files.SetCell(1, 2, tview.NewTableCell("(no files, e.g. an empty WORKDIR)").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+		files.ScrollToBeginning()
+	}
+
+	render := func() {
+		layersTable.Clear()
+		for col, h := range []string{"#", "SIZE", "COMMAND"} {
+			headerCell(layersTable, col, h)
+		}
+		for i, l := range analysis.Layers {
+			layersTable.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprintf("%d", i+1)).SetTextColor(tcell.GetColor(t.Muted)))
+			layersTable.SetCell(i+1, 1, tview.NewTableCell(docker.FormatBytes(uint64(l.Size))).SetTextColor(tcell.GetColor(sizeColor(l.Size))).SetAlign(tview.AlignRight))
+			command := l.Command
+			if command == "" {
+				command = l.Digest
+			}
+			layersTable.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(command)).SetExpansion(1))
+		}
+		layersTable.Select(1, 0)
+
+		efficiency := analysis.Efficiency() * 100
+		color := t.Success
+		switch {
+		case efficiency < 90:
+			color = t.Error
+		case efficiency < 98:
+			color = t.Warning
+		}
+		header.SetText(fmt.Sprintf(" [%s::b]%s[-:-:-]   %d layers, %s of files\n [%s]Efficiency %.1f%%[-]   wasted %s",
+			t.Accent, tview.Escape(name), len(analysis.Layers), docker.FormatBytes(uint64(analysis.TotalSize)),
+			color, efficiency, docker.FormatBytes(uint64(analysis.Wasted))))
+		renderFiles()
+	}
+
+	layersTable.SetSelectionChangedFunc(func(row, column int) {
+		if !showWasted {
+			renderFiles()
+		}
+	})
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			cancel()
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(back)
+			return nil
+		case analysis == nil:
+			return event
+		case event.Key() == tcell.KeyTab:
+			if layersTable.HasFocus() {
+				d.app.SetFocus(files)
+				files.SetBorderColor(tcell.GetColor(t.Info))
+				layersTable.SetBorderColor(tcell.GetColor(t.Muted))
+			} else {
+				d.app.SetFocus(layersTable)
+				layersTable.SetBorderColor(tcell.GetColor(t.Info))
+				files.SetBorderColor(tcell.GetColor(t.Muted))
+			}
+			return nil
+		case event.Rune() == 'w':
+			showWasted = !showWasted
+			renderFiles()
+			return nil
+		}
+		return event
+	})
+
+	// docker save streams the whole image, which takes a while for big ones
+	var read atomic.Int64
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				n := read.Load()
+				d.app.QueueUpdateDraw(func() {
+					if analysis == nil && ctx.Err() == nil {
+						header.SetText(fmt.Sprintf(" [%s]Reading the layers of %s... %s[-]", t.Muted, tview.Escape(name), docker.FormatBytes(uint64(n))))
+					}
+				})
+			}
+		}
+	}()
+
+	go func() {
+		result, err := docker.AnalyzeImage(ctx, imageID, read.Store)
+		if ctx.Err() != nil {
+			return
+		}
+		cancel()
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				header.SetText(fmt.Sprintf(" [%s]Could not analyze %s: %s[-]", t.Error, tview.Escape(name), tview.Escape(errorSummary(err))))
+				return
+			}
+			analysis = result
+			render()
+		})
+	}()
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(layersTable)
+}
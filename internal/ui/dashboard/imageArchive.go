@@ -0,0 +1,240 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// imageArchiveExts are the file endings docker load reads
+var imageArchiveExts = []string{".tar", ".tar.gz", ".tgz", ".tar.xz", ".tar.bz2"}
+
+// unsafeFileChars are replaced when an image name becomes a file name
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// promptSaveImages asks where to save the marked images, by default a tar named after
+// the image in the download directory, and saves them with their tags
+func (d *Dashboard) promptSaveImages(images *resourceTable) {
+	ids := images.markedKeys()
+	if len(ids) == 0 {
+		return
+	}
+	name := fmt.Sprintf("images-%s.tar", time.Now().Format("20060102-150405"))
+	if len(ids) == 1 {
+		first, _, _ := strings.Cut(images.rows[slices.Index(images.keys, ids[0])][0], ", ")
+		if first == docker.UntaggedImage {
+			first = ids[0][:12]
+		}
+		name = unsafeFileChars.ReplaceAllString(first, "_") + ".tar"
+	}
+
+	form := tview.NewForm().
+		AddInputField("Save to", filepath.Join(d.downloadDir(), name), 70, nil, nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📦 Save %d images to a tar (.gz to compress) ", len(ids))).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+	form.AddButton("Save", func() {
+		path := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if path == "" {
+			return
+		}
+		d.runImageTransfer(images, fmt.Sprintf("Saving %d images to %s", len(ids), path),
+			func(ctx context.Context, read func(int64), total *atomic.Int64) (string, error) {
+				list, err := docker.ListImages()
+				if err != nil {
+					return "", err
+				}
+				var refs []string
+				for _, img := range list {
+					for _, id := range ids {
+						if img.ID == id {
+							refs = append(refs, imageRefs(img)...)
+							total.Add(img.SizeBytes)
+						}
+					}
+				}
+				if err := docker.SaveImage(ctx, refs, path, read); err != nil {
+					return "", err
+				}
+				copyToClipboard(d.app, path)
+				return fmt.Sprintf("Saved %s to %s (path copied); load it elsewhere with l or docker load -i", strings.Join(refs, ", "), path), nil
+			})
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	showOverlay(d.app, d.mainFlex, form, 90, 7)
+}
+
+// promptLoadImages asks for a tar written by docker save, completing the paths of
+// image archives, and loads it
+func (d *Dashboard) promptLoadImages(images *resourceTable) {
+	input := tview.NewInputField().
+		SetLabel("Load from ").
+		SetText(d.downloadDir() + string(filepath.Separator)).
+		SetFieldWidth(70)
+	input.SetAutocompleteFunc(imageArchiveCompletions)
+
+	form := tview.NewForm().AddFormItem(input)
+	form.SetBorder(true).
+		SetTitle(" 📥 Load images from a tar (Tab completes) ").
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+	form.AddButton("Load", func() {
+		path := strings.TrimSpace(input.GetText())
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			form.SetTitle(fmt.Sprintf(" [%s]%s is not a file[-] ", currentTheme().Error, tview.Escape(path)))
+			return
+		}
+		d.runImageTransfer(images, "Loading "+path,
+			func(ctx context.Context, read func(int64), total *atomic.Int64) (string, error) {
+				total.Store(info.Size())
+				loaded, err := docker.LoadImage(ctx, path, read)
+				if err != nil {
+					return "", err
+				}
+				return "Loaded " + strings.Join(loaded, ", "), nil
+			})
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	showOverlay(d.app, d.mainFlex, form, 90, 7)
+}
+
+// imageArchiveCompletions lists the directories and image archives starting with what
+// was typed
+func imageArchiveCompletions(current string) []string {
+	dir, prefix := filepath.Split(current)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var matches []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) || strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		switch {
+		case e.IsDir():
+			matches = append(matches, filepath.Join(dir, name)+string(filepath.Separator))
+		case hasImageArchiveExt(name):
+			matches = append(matches, filepath.Join(dir, name))
+		}
+	}
+	return matches
+}
+
+// hasImageArchiveExt reports whether a file name looks like a saved image
+func hasImageArchiveExt(name string) bool {
+	for _, ext := range imageArchiveExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// runImageTransfer runs a save or load with a progress bar on top of the images tab.
+// run sets total once it knows how many bytes to expect; ESC cancels it.
+func (d *Dashboard) runImageTransfer(images *resourceTable, title string, run func(ctx context.Context, read func(int64), total *atomic.Int64) (string, error)) {
+	t := currentTheme()
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+
+	view := tview.NewTextView().
+		SetDynamicColors(true)
+	view.SetBorder(true).
+		SetTitle(" "+tview.Escape(title)+" ").
+		SetBorderPadding(1, 0, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	var read, total atomic.Int64
+	var done atomic.Bool
+	start := time.Now()
+	draw := func() {
+		n, of := read.Load(), total.Load()
+		rate := float64(n) / max(time.Since(start).Seconds(), 0.001)
+		const width = 50
+		if of <= 0 {
+			view.SetText(fmt.Sprintf("%s  %s/s\n\n[%s]ESC cancels[-]", docker.FormatBytes(uint64(n)), docker.FormatBytes(uint64(rate)), t.Muted))
+			return
+		}
+		// Tar headers make a save a little bigger than the images
+		fraction := min(float64(n)/float64(of), 0.99)
+		bar := overviewBar(fraction, width)
+		view.SetText(fmt.Sprintf("[%s]%s[-]%s %3.0f%%\n%s of %s  %s/s\n\n[%s]ESC cancels[-]",
+			t.Success, bar, strings.Repeat("░", width-len([]rune(bar))), fraction*100,
+			docker.FormatBytes(uint64(n)), docker.FormatBytes(uint64(of)), docker.FormatBytes(uint64(rate)), t.Muted))
+	}
+	draw()
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter && done.Load() {
+			cancel()
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(images.table)
+			return nil
+		}
+		return event
+	})
+
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.app.QueueUpdateDraw(func() {
+					if !done.Load() {
+						draw()
+					}
+				})
+			}
+		}
+	}()
+
+	go func() {
+		result, err := run(ctx, read.Store, &total)
+		if ctx.Err() != nil && err != nil {
+			return // cancelled with ESC
+		}
+		done.Store(true)
+		cancel()
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				view.SetText(fmt.Sprintf("[%s]%s[-]\n\n[%s]Enter or ESC closes[-]", t.Error, tview.Escape(errorSummary(err)), t.Muted))
+				return
+			}
+			view.SetText(fmt.Sprintf("[%s]%s[-] in %s\n\n[%s]Enter or ESC closes[-]",
+				t.Success, tview.Escape(result), time.Since(start).Round(time.Second), t.Muted))
+			images.refresh(d.app)
+		})
+	}()
+
+	showOverlay(d.app, d.mainFlex, view, 90, 7)
+}
@@ -0,0 +1,391 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// Steps of a batch of image changes
+const (
+	imageTag    = "tag"
+	imageUntag  = "untag"
+	imageDelete = "delete"
+	imageKeep   = "keep"
+	imageSkip   = "skip"
+)
+
+// imageStep is one change of a batch of image changes, planned before anything runs
+type imageStep struct {
+	action  string // one of the image step constants
+	ref     string // the reference removed or kept, or tagged from
+	target  string // the new reference of a tag step
+	image   docker.ImageInfo
+	note    string
+	blocked bool // would fail, e.g. deleting an image containers were created from
+}
+
+// imageRefs returns the references of an image that can be removed or tagged: its tags,
+// or its ID when it has none
+func imageRefs(img docker.ImageInfo) []string {
+	if len(img.Tags) == 1 && img.Tags[0] == docker.UntaggedImage {
+		return []string{img.ID}
+	}
+	return img.Tags
+}
+
+// removalPlan removes the references remove picks. Removing the last reference of an
+// image deletes it, which containers created from it prevent.
+func removalPlan(images []docker.ImageInfo, users map[string][]string, remove func(img docker.ImageInfo, ref string) bool) []imageStep {
+	var steps []imageStep
+	for _, img := range images {
+		refs := imageRefs(img)
+		var removed []string
+		for _, ref := range refs {
+			if remove(img, ref) {
+				removed = append(removed, ref)
+			}
+		}
+		for i, ref := range removed {
+			step := imageStep{action: imageUntag, ref: ref, image: img, note: "other tags keep the image"}
+			if len(removed) == len(refs) && i == len(removed)-1 {
+				step.action, step.note = imageDelete, "last reference, frees "+img.Size
+				if names := users[img.ID]; len(names) > 0 {
+					step.blocked = true
+					step.note = "used by " + strings.Join(names, ", ")
+				}
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// pruneRepositoryPlan removes the tags of repository from all but its keep newest images,
+// so tags such as latest that point at a kept image stay; images is expected newest
+// first, as ListImages returns them
+func pruneRepositoryPlan(images []docker.ImageInfo, users map[string][]string, repository string, keep int) []imageStep {
+	inRepository := func(ref string) bool {
+		repo, _ := docker.SplitImageRef(ref)
+		return repo == repository
+	}
+	kept := make(map[string]bool) // image IDs
+	var steps []imageStep
+	for _, img := range images {
+		for _, ref := range img.Tags {
+			if !inRepository(ref) || !kept[img.ID] && len(kept) == keep {
+				continue
+			}
+			kept[img.ID] = true
+			steps = append(steps, imageStep{action: imageKeep, ref: ref, image: img, note: fmt.Sprintf("among the %d newest", keep)})
+		}
+	}
+	return append(steps, removalPlan(images, users, func(img docker.ImageInfo, ref string) bool {
+		return inRepository(ref) && !kept[img.ID]
+	})...)
+}
+
+// retagPlan tags every reference of the picked images with template, in which {repo}
+// and {tag} stand for the repository and tag of the reference, e.g.
+// "registry:5000/{repo}:{tag}" or "{repo}:stable"
+func retagPlan(images []docker.ImageInfo, picked map[string]bool, template string) ([]imageStep, error) {
+	placeholders := strings.Contains(template, "{repo}") || strings.Contains(template, "{tag}")
+	var steps []imageStep
+	targets := make(map[string]string) // target -> source
+	for _, img := range images {
+		if !picked[img.ID] {
+			continue
+		}
+		for _, ref := range imageRefs(img) {
+			repo, tag := docker.SplitImageRef(ref)
+			if ref == img.ID {
+				if placeholders {
+					steps = append(steps, imageStep{action: imageSkip, ref: ref[:12], image: img, note: "untagged, no {repo} or {tag} to fill in"})
+					continue
+				}
+				repo, tag = "", ""
+			}
+			if tag == "" {
+				tag = "latest"
+			}
+			target := strings.NewReplacer("{repo}", repo, "{tag}", tag).Replace(template)
+			if source, ok := targets[target]; ok && source != ref {
+				return nil, fmt.Errorf("%s would be given to both %s and %s; use {repo} or {tag} in the new name", target, source, ref)
+			}
+			targets[target] = ref
+			steps = append(steps, imageStep{action: imageTag, ref: ref, target: target, image: img})
+		}
+	}
+	return steps, nil
+}
+
+// imageKeys handles the batch keys of the images tab: Space marks images, t tags them
+// anew, d removes them and p removes all but the newest tags of a repository, each
+// listed as a dry run first; s saves them to a tar, l loads one, r browses the
+// configured registries and n runs a new container from the image under the cursor.
+func (d *Dashboard) imageKeys(images *resourceTable, event *tcell.EventKey) *tcell.EventKey {
+	if d.cfg.Kiosk {
+		return event
+	}
+	switch event.Rune() {
+	case ' ':
+		images.toggleMark()
+	case 't':
+		d.promptRetag(images)
+	case 'd':
+		picked := images.markedKeys()
+		d.planImages(images, "🗑 Remove images", func(list []docker.ImageInfo, users map[string][]string) ([]imageStep, error) {
+			set := make(map[string]bool)
+			for _, id := range picked {
+				set[id] = true
+			}
+			return removalPlan(list, users, func(img docker.ImageInfo, _ string) bool { return set[img.ID] }), nil
+		})
+	case 'p':
+		d.promptPruneRepository(images)
+	case 's':
+		d.promptSaveImages(images)
+	case 'l':
+		d.promptLoadImages(images)
+	case 'r':
+		d.showRegistries(images)
+	case 'n':
+		d.showRunImageForm(images)
+	default:
+		return event
+	}
+	return nil
+}
+
+// planImages lists the images in the background, plans a batch with them and shows the
+// plan as a dry run
+func (d *Dashboard) planImages(images *resourceTable, title string, plan func([]docker.ImageInfo, map[string][]string) ([]imageStep, error)) {
+	if len(images.keys) == 0 {
+		return
+	}
+	go func() {
+		list, err := docker.ListImages()
+		var users map[string][]string
+		if err == nil {
+			users, err = docker.ImageUsers()
+		}
+		var steps []imageStep
+		if err == nil {
+			steps, err = plan(list, users)
+		}
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, title, err)
+				return
+			}
+			d.showImagePlan(images, title, steps)
+		})
+	}()
+}
+
+// promptRetag asks for the new name of the marked images
+func (d *Dashboard) promptRetag(images *resourceTable) {
+	picked := make(map[string]bool)
+	for _, id := range images.markedKeys() {
+		picked[id] = true
+	}
+	if len(picked) == 0 {
+		return
+	}
+
+	form := tview.NewForm().
+		AddInputField("New name", "{repo}:{tag}", 60, nil, nil)
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🏷 Tag %d images: {repo} and {tag} are filled in ", len(picked))).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+	form.AddButton("Dry run", func() {
+		template := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if template == "" {
+			return
+		}
+		d.planImages(images, "🏷 Tag images", func(list []docker.ImageInfo, _ map[string][]string) ([]imageStep, error) {
+			return retagPlan(list, picked, template)
+		})
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	showOverlay(d.app, d.mainFlex, form, 80, 7)
+}
+
+// promptPruneRepository asks for a repository and how many of its newest tags to keep,
+// starting from the repository of the image under the cursor
+func (d *Dashboard) promptPruneRepository(images *resourceTable) {
+	repository := ""
+	if row, _ := images.table.GetSelection(); row >= 1 && row <= len(images.rows) {
+		first, _, _ := strings.Cut(images.rows[row-1][0], ", ")
+		if first != docker.UntaggedImage {
+			repository, _ = docker.SplitImageRef(first)
+		}
+	}
+
+	form := tview.NewForm().
+		AddInputField("Repository", repository, 50, nil, nil).
+		AddInputField("Keep newest images", "3", 5, tview.InputFieldInteger, nil)
+	form.SetBorder(true).
+		SetTitle(" 🧹 Remove all tags of a repository but the newest ").
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+	form.AddButton("Dry run", func() {
+		repo := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		keep, err := strconv.Atoi(form.GetFormItem(1).(*tview.InputField).GetText())
+		if repo == "" || err != nil || keep < 0 {
+			return
+		}
+		d.planImages(images, "🧹 Prune "+repo, func(list []docker.ImageInfo, users map[string][]string) ([]imageStep, error) {
+			steps := pruneRepositoryPlan(list, users, repo, keep)
+			if len(steps) == 0 {
+				return nil, fmt.Errorf("no local image is tagged in repository %s", repo)
+			}
+			return steps, nil
+		})
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	showOverlay(d.app, d.mainFlex, form, 70, 9)
+}
+
+// showImagePlan lists what a batch would change, and applies it on a
+func (d *Dashboard) showImagePlan(images *resourceTable, title string, steps []imageStep) {
+	t := currentTheme()
+
+	var tags, untags, deletes, blocked int
+	var freed int64
+	for _, s := range steps {
+		switch {
+		case s.blocked:
+			blocked++
+		case s.action == imageTag:
+			tags++
+		case s.action == imageUntag:
+			untags++
+		case s.action == imageDelete:
+			deletes++
+			freed += s.image.SizeBytes
+		}
+	}
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+	header.SetText(fmt.Sprintf(
+		" [%s::b]Dry run[-:-:-], nothing has changed yet: %d to tag, %d to untag, %d images to delete (up to %s freed)\n [%s]%d skipped because containers use them; press a to apply the rest[-]",
+		t.Warning, tags, untags, deletes, docker.FormatBytes(uint64(freed)), t.Muted, blocked))
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" "+title+" ").
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[black:green] a [-:-:-] Apply   [black:red] ESC [-:-:-] Cancel")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 3, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	actionColors := map[string]string{imageTag: t.Success, imageUntag: t.Warning, imageDelete: t.Error, imageKeep: t.Muted, imageSkip: t.Muted}
+	for col, h := range []string{"ACTION", "REFERENCE", "IMAGE", "CREATED", "NOTE"} {
+		table.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(t.Highlight)).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+	for i, s := range steps {
+		ref := s.ref
+		if s.action == imageTag {
+			ref += " → " + s.target
+		}
+		color := actionColors[s.action]
+		if s.blocked {
+			color = t.Muted
+		}
+		table.SetCell(i+1, 0, tview.NewTableCell(s.action).SetTextColor(tcell.GetColor(color)))
+		table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(ref)))
+		table.SetCell(i+1, 2, tview.NewTableCell(s.image.ID[:12]).SetTextColor(tcell.GetColor(t.Muted)))
+		table.SetCell(i+1, 3, tview.NewTableCell(s.image.Created).SetTextColor(tcell.GetColor(t.Muted)))
+		table.SetCell(i+1, 4, tview.NewTableCell(tview.Escape(s.note)).SetTextColor(tcell.GetColor(t.Muted)).SetExpansion(1))
+	}
+
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+
+	applied := false
+	apply := func() {
+		applied = true
+		footer.SetText("[black:red] ESC [-:-:-] Back")
+		header.SetText(fmt.Sprintf(" [%s]Applying...[-]", t.Warning))
+		go func() {
+			var failed int
+			for i, s := range steps {
+				if s.action == imageKeep || s.action == imageSkip || s.blocked {
+					continue
+				}
+				var err error
+				switch s.action {
+				case imageTag:
+					err = docker.TagImage(s.ref, s.target)
+				default:
+					_, err = docker.RemoveImageRef(s.ref)
+				}
+				result, color := "done", t.Success
+				if err != nil {
+					failed++
+					result, color = errorSummary(err), t.Error
+				}
+				d.app.QueueUpdateDraw(func() {
+					table.GetCell(i+1, 4).SetText(tview.Escape(result)).SetTextColor(tcell.GetColor(color))
+				})
+			}
+			d.app.QueueUpdateDraw(func() {
+				clear(images.marked)
+				images.refresh(d.app)
+				if failed > 0 {
+					header.SetText(fmt.Sprintf(" [%s]Finished, %d steps failed[-]", t.Error, failed))
+				} else {
+					header.SetText(fmt.Sprintf(" [%s]Finished[-]", t.Success))
+				}
+			})
+		}()
+	}
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			back()
+			return nil
+		case event.Rune() == 'a' && !applied:
+			apply()
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
@@ -0,0 +1,64 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"devops-dashboard/internal/docker"
+)
+
+// showRunImageForm starts a new container from the image under the cursor, with the
+// form prefilled from the image config: its exposed ports on random host ports and its
+// volumes as named volumes
+func (d *Dashboard) showRunImageForm(images *resourceTable) {
+	id := images.selectedKey()
+	if id == "" {
+		return
+	}
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	}
+
+	go func() {
+		name, spec, err := docker.ImageRunSpec(id)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, "Error", err)
+				return
+			}
+
+			form := newSpecForm(fmt.Sprintf(" ▶️  Run: %s ", spec.Image), name, *spec)
+			form.AddButton("Run", func() {
+				name := strings.TrimSpace(form.name.GetText())
+				newSpec := form.spec()
+				if name == "" || newSpec.Image == "" {
+					showMessage(d.app, form, "Run", "A name and an image are required.")
+					return
+				}
+				back()
+				d.runImage(name, newSpec)
+			})
+			form.AddButton("Cancel", back)
+			form.SetCancelFunc(back)
+
+			setRoot(d.app, form)
+		})
+	}()
+}
+
+// runImage creates and starts the container in the background and reports the result
+func (d *Dashboard) runImage(name string, spec docker.ContainerSpec) {
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Starting %s from %s...[-]", currentTheme().Warning, name, spec.Image))
+	go func() {
+		newID, err := docker.RunImage(name, spec)
+		d.app.QueueUpdateDraw(func() {
+			d.updateList()
+			if err != nil {
+				showError(d.app, d.mainFlex, "❌ Run Failed", err)
+				return
+			}
+			showToast(d.app, toastSuccess, fmt.Sprintf("Started %s from %s, ID %s", name, spec.Image, newID[:12]))
+		})
+	}()
+}
@@ -0,0 +1,682 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// inspectTab is one sub-tab of the inspect screen
+type inspectTab struct {
+	name   string
+	title  string // border title, before any counts
+	table  *tview.Table
+	values []string // text copied to the clipboard for each data row
+}
+
+// setRows replaces the tab contents with a header and data rows
+func (it *inspectTab) setRows(headers []string, rows [][]string, values []string) {
+	t := currentTheme()
+	it.table.Clear()
+	it.values = values
+
+	for col, h := range headers {
+		it.table.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(t.Highlight)).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, row := range rows {
+		for col, value := range row {
+			color := tview.Styles.PrimaryTextColor
+			if col == 0 {
+				color = tcell.GetColor(t.Accent)
+			}
+			cell := tview.NewTableCell(tview.Escape(value)).SetTextColor(color)
+			if col == len(row)-1 {
+				cell.SetExpansion(1)
+			}
+			it.table.SetCell(i+1, col, cell)
+		}
+	}
+
+	if len(rows) == 0 {
+		it.table.SetCell(1, 0, tview.NewTableCell("(none)").
+			SetTextColor(tcell.GetColor(t.Muted)).
+			SetSelectable(false))
+	}
+	it.table.Select(1, 0)
+	it.table.ScrollToBeginning()
+}
+
+// selectedValue returns the clipboard text of the row under the cursor
+func (it *inspectTab) selectedValue() string {
+	row, _ := it.table.GetSelection()
+	if row < 1 || row > len(it.values) {
+		return ""
+	}
+	return it.values[row-1]
+}
+
+// showEnhancedInspect displays the container inspection split into Info, Env, Mounts, Network, Labels,
+// DNS, Run, Users and raw JSON tabs. scriptDir is where the run command is saved as a script, "" where exports
+// are disabled.
+func showEnhancedInspect(app *tview.Application, mainView tview.Primitive, containerID, containerName, scriptDir string) {
+	t := currentTheme()
+	names := []string{"Info", "Env", "Mounts", "Network", "Labels", "DNS", "Run", "Users", "JSON"}
+	jsonTab := slices.Index(names, "JSON")
+
+	pages := tview.NewPages()
+	tabs := make([]*inspectTab, len(names))
+	title := fmt.Sprintf(" 🔍 Inspect: %s ", containerName)
+	for i, name := range names {
+		table := tview.NewTable().
+			SetSelectable(true, false).
+			SetFixed(1, 0)
+		table.SetBorder(true).
+			SetTitle(title).
+			SetBorderPadding(0, 0, 1, 1).
+			SetBorderColor(tcell.ColorDarkMagenta)
+		tabs[i] = &inspectTab{name: name, title: title, table: table}
+		pages.AddPage(name, table, true, i == 0)
+	}
+	tabs[0].table.SetCell(0, 0, tview.NewTableCell("⏳ Loading container details...").
+		SetTextColor(tcell.GetColor(t.Warning)))
+
+	tabBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+
+	statusBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	help := fmt.Sprintf("[%[1]s]←/→[-] Switch tab   [%[1]s]y/Enter[-] Copy field   [%[1]s]o[-] Copy cd to mount   [%[1]s]l[-] DNS lookup   [%[1]s]w[-] Save run script   [%[1]s]r[-] Reveal secrets   [%[1]s]J[-] jq query   [%[1]s]Backspace/ESC[-] Back", t.Highlight)
+	jsonHelp := fmt.Sprintf("[%[1]s]←/→[-] Switch tab   [%[1]s]Enter/Space[-] Fold   [%[1]s]+/-[-] Unfold/fold all   [%[1]s]/[-] Find path or text   [%[1]s]n[-] Next match   [%[1]s]y[-] Copy value   [%[1]s]p[-] Copy path   [%[1]s]J[-] jq query   [%[1]s]Backspace/ESC[-] Back", t.Highlight)
+	statusBar.SetText(help)
+
+	current := 0
+	renderTabBar := func() {
+		var b strings.Builder
+		for i, name := range names {
+			if i == current {
+				fmt.Fprintf(&b, `["%s"][%s:%s:b] %d %s [-:-:-][""] `, name, t.Background, t.Info, i+1, name)
+			} else {
+				fmt.Fprintf(&b, `["%s"][%s] %d %s [-:-:-][""] `, name, t.Muted, i+1, name)
+			}
+		}
+		tabBar.SetText(b.String())
+	}
+
+	switchTo := func(i int) {
+		current = (i + len(tabs)) % len(tabs)
+		pages.SwitchToPage(names[current])
+		renderTabBar()
+		if current == jsonTab {
+			statusBar.SetText(jsonHelp)
+		} else {
+			statusBar.SetText(help)
+		}
+		app.SetFocus(tabs[current].table)
+	}
+
+	// Secret env values are masked until r reveals them, on every tab that shows them
+	var (
+		reveal        bool
+		details       *docker.ContainerDetails
+		envDiff       []docker.EnvDiff
+		runCommand    string
+		maskedCommand string
+	)
+	fillEnv := func() {
+		switch {
+		case envDiff != nil:
+			fillEnvDiff(tabs[1], envDiff, reveal)
+		case details != nil:
+			fillEnvList(tabs[1], details.Env, reveal)
+		}
+	}
+	fillRun := func() {
+		if reveal {
+			fillRunTab(tabs[6], runCommand, runCommand)
+		} else {
+			fillRunTab(tabs[6], runCommand, maskedCommand)
+		}
+	}
+
+	// copyField copies the real value, but the status bar only echoes what the screen shows
+	jsonView := newInspectJSONView(tabs[jsonTab])
+	copyField := func() {
+		if current == jsonTab {
+			if n := jsonView.selected(); n != nil {
+				copyToClipboard(app, n.json())
+				statusBar.SetText(fmt.Sprintf("[%s]✓ Copied the value of:[-] %s", t.Success, tview.Escape(truncateString(n.path, 60))))
+			}
+			return
+		}
+		value := tabs[current].selectedValue()
+		if value == "" {
+			return
+		}
+		copyToClipboard(app, value)
+		shown := value
+		switch row, _ := tabs[current].table.GetSelection(); {
+		case reveal:
+		case current == 1:
+			shown = maskEnv(tabs[1].table.GetCell(row, 0).Text, value, false)
+		case current == 6:
+			shown = maskedCommand
+		}
+		statusBar.SetText(fmt.Sprintf("[%s]✓ Copied:[-] %s", t.Success, tview.Escape(truncateString(shown, 60))))
+	}
+
+	var mounts []docker.MountInfo
+	checks := make(map[int]docker.BindCheck) // mount index -> host path, UI goroutine only
+	// revealMount copies a cd into the host directory of the selected mount, for a look
+	// at it from a shell on the host
+	revealMount := func() {
+		row, _ := tabs[2].table.GetSelection()
+		if current != 2 || row < 1 || row > len(mounts) || mounts[row-1].Source == "" {
+			return
+		}
+		m := mounts[row-1]
+		dir := m.Source
+		if check, ok := checks[row-1]; ok && check.Exists && !check.Dir {
+			dir = filepath.Dir(dir)
+		}
+		command := "cd " + shellQuote(dir)
+		if runtime.GOOS == "windows" {
+			command = `cd /d "` + dir + `"`
+		}
+		copyToClipboard(app, command)
+		statusBar.SetText(fmt.Sprintf("[%s]✓ Copied:[-] %s", t.Success, tview.Escape(truncateString(command, 60))))
+	}
+
+	var (
+		dnsConfig *docker.DNSConfig
+		lookups   []dnsLookup
+		askLookup func() // set once the layout exists
+		askFind   func()
+		askQuery  func()
+		rawJSON   []byte // the inspect output once loaded, for jq
+	)
+	tabs[5].table.SetCell(0, 0, tview.NewTableCell("⏳ Reading /etc/resolv.conf...").
+		SetTextColor(tcell.GetColor(t.Warning)))
+
+	// lookUp resolves names inside the container and adds them to the DNS tab
+	lookUp := func(names []string) {
+		if dnsConfig == nil {
+			return
+		}
+		for _, name := range names {
+			i := len(lookups)
+			lookups = append(lookups, dnsLookup{name: name})
+			go func(name string) {
+				result, err := docker.LookupName(containerID, docker.Shell{}, name)
+				app.QueueUpdateDraw(func() {
+					lookups[i].result, lookups[i].err = result, err
+					fillDNSTab(tabs[5], dnsConfig, lookups)
+				})
+			}(name)
+		}
+		fillDNSTab(tabs[5], dnsConfig, lookups)
+	}
+
+	go func() {
+		config, err := docker.GetDNSConfig(containerID)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				tabs[5].table.SetCell(0, 0, tview.NewTableCell("Error: "+errorSummary(err)).
+					SetTextColor(tcell.GetColor(t.Error)))
+				return
+			}
+			dnsConfig = config
+			fillDNSTab(tabs[5], dnsConfig, lookups)
+		})
+	}()
+
+	tabs[6].table.SetCell(0, 0, tview.NewTableCell("⏳ Working out the docker run command...").
+		SetTextColor(tcell.GetColor(t.Warning)))
+	go func() {
+		mask := func(key, value string) string { return maskEnv(key, value, false) }
+		command, masked, err := docker.RunCommand(containerID, mask)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				tabs[6].table.SetCell(0, 0, tview.NewTableCell("Error: "+errorSummary(err)).
+					SetTextColor(tcell.GetColor(t.Error)))
+				return
+			}
+			runCommand, maskedCommand = command, masked
+			fillRun()
+		})
+	}()
+
+	tabs[7].table.SetCell(0, 0, tview.NewTableCell("⏳ Reading the user namespace...").
+		SetTextColor(tcell.GetColor(t.Warning)))
+	go func() {
+		mapping, err := docker.GetUserMapping(containerID)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				tabs[7].table.SetCell(0, 0, tview.NewTableCell("Error: "+errorSummary(err)).
+					SetTextColor(tcell.GetColor(t.Error)))
+				return
+			}
+			fillUsersTab(tabs[7], mapping)
+		})
+	}()
+
+	tabs[jsonTab].table.SetCell(0, 0, tview.NewTableCell("⏳ Reading the inspect output...").
+		SetTextColor(tcell.GetColor(t.Warning)))
+	go func() {
+		data, err := docker.InspectJSON(containerID)
+		app.QueueUpdateDraw(func() {
+			if err == nil {
+				err = jsonView.load(data)
+			}
+			if err == nil {
+				rawJSON = data
+			}
+			if err != nil {
+				jsonView.showError(err)
+			}
+		})
+	}()
+
+	// writeScript saves the run command as a shell script next to the other exports
+	writeScript := func() {
+		if runCommand == "" {
+			return
+		}
+		if scriptDir == "" {
+			statusBar.SetText(fmt.Sprintf("[%s]Exports are disabled in kiosk mode[-]", t.Warning))
+			return
+		}
+		path := filepath.Join(scriptDir, "run-"+containerName+".sh")
+		script := "#!/bin/sh\n# Recreates " + containerName + "\n" + runCommand + "\n"
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			statusBar.SetText(fmt.Sprintf("[%s]✗ %s[-]", t.Error, tview.Escape(errorSummary(err))))
+			return
+		}
+		copyToClipboard(app, path)
+		statusBar.SetText(fmt.Sprintf("[%s]✓ Saved (path copied):[-] %s", t.Success, tview.Escape(path)))
+	}
+
+	go func() {
+		result, err := docker.InspectStructured(containerID)
+		// Without the image (e.g. deleted) the env tab falls back to the plain list
+		diff, _ := docker.CompareEnvWithImage(containerID)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				tabs[0].table.SetCell(0, 0, tview.NewTableCell("Error: "+errorSummary(err)).
+					SetTextColor(tcell.GetColor(t.Error)))
+				return
+			}
+			details, envDiff = result, diff
+			fillInspectTabs(tabs, details)
+			fillEnv()
+			mounts = details.Mounts
+		})
+		checkBindMounts(app, tabs[2], containerID, result, checks)
+	}()
+
+	for _, tab := range tabs {
+		tab.table.SetSelectedFunc(func(row, column int) { copyField() })
+		tab.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+				setRoot(app, mainView)
+				return nil
+			case tcell.KeyRight, tcell.KeyTab:
+				switchTo(current + 1)
+				return nil
+			case tcell.KeyLeft, tcell.KeyBacktab:
+				switchTo(current - 1)
+				return nil
+			}
+
+			switch r := event.Rune(); {
+			case r == 'q' || r == 'Q':
+				setRoot(app, mainView)
+				return nil
+			case r == 'y' || r == 'Y':
+				copyField()
+				return nil
+			case r == 'o' || r == 'O':
+				revealMount()
+				return nil
+			case (r == 'l' || r == 'L') && current == 5:
+				askLookup()
+				return nil
+			case (r == 'w' || r == 'W') && current == 6:
+				writeScript()
+				return nil
+			case current == jsonTab && r == ' ':
+				jsonView.toggle()
+				return nil
+			case current == jsonTab && (r == '+' || r == '-'):
+				jsonView.foldAll(r == '-')
+				return nil
+			case current == jsonTab && r == '/':
+				askFind()
+				return nil
+			case current == jsonTab && (r == 'n' || r == 'N'):
+				if !jsonView.find(jsonView.query) && jsonView.query != "" {
+					statusBar.SetText(fmt.Sprintf("[%s]No match for[-] %s", t.Warning, tview.Escape(jsonView.query)))
+				}
+				return nil
+			case r == 'J':
+				askQuery()
+				return nil
+			case current == jsonTab && (r == 'p' || r == 'P'):
+				if n := jsonView.selected(); n != nil {
+					copyToClipboard(app, n.path)
+					statusBar.SetText(fmt.Sprintf("[%s]✓ Copied:[-] %s", t.Success, tview.Escape(truncateString(n.path, 60))))
+				}
+				return nil
+			case r == 'r' || r == 'R':
+				reveal = !reveal
+				jsonView.reveal = reveal
+				jsonView.render()
+				row, _ := tabs[1].table.GetSelection()
+				fillEnv()
+				tabs[1].table.Select(row, 0)
+				if runCommand != "" {
+					fillRun()
+				}
+				return nil
+			case r >= '1' && r <= '9':
+				switchTo(int(r - '1'))
+				return nil
+			}
+			return event
+		})
+	}
+	// Enter folds on the JSON tab instead of copying
+	tabs[jsonTab].table.SetSelectedFunc(func(row, column int) { jsonView.toggle() })
+
+	renderTabBar()
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tabBar, 1, 0, false).
+		AddItem(pages, 0, 1, true).
+		AddItem(statusBar, 1, 0, false)
+
+	// The lookup input takes the place of the status bar while names are typed
+	lookupInput := tview.NewInputField().
+		SetLabel(" Look up: ").
+		SetPlaceholder("names separated by spaces, e.g. db api.example.com")
+	lookupInput.SetDoneFunc(func(key tcell.Key) {
+		flex.RemoveItem(lookupInput)
+		flex.AddItem(statusBar, 1, 0, false)
+		app.SetFocus(tabs[current].table)
+		if key == tcell.KeyEnter {
+			lookUp(strings.Fields(lookupInput.GetText()))
+		}
+		lookupInput.SetText("")
+	})
+	askLookup = func() {
+		if dnsConfig == nil {
+			return
+		}
+		flex.RemoveItem(statusBar)
+		flex.AddItem(lookupInput, 1, 0, true)
+		app.SetFocus(lookupInput)
+	}
+
+	// The find input takes the place of the status bar too
+	findInput := tview.NewInputField().
+		SetLabel(" Find: ").
+		SetPlaceholder("a path such as .HostConfig.Binds, or text in keys and values")
+	findInput.SetDoneFunc(func(key tcell.Key) {
+		flex.RemoveItem(findInput)
+		flex.AddItem(statusBar, 1, 0, false)
+		app.SetFocus(tabs[current].table)
+		if query := strings.TrimSpace(findInput.GetText()); key == tcell.KeyEnter && query != "" {
+			if !jsonView.find(query) {
+				statusBar.SetText(fmt.Sprintf("[%s]No match for[-] %s", t.Warning, tview.Escape(query)))
+			}
+		}
+		findInput.SetText("")
+	})
+	askFind = func() {
+		if jsonView.root == nil {
+			return
+		}
+		flex.RemoveItem(statusBar)
+		flex.AddItem(findInput, 1, 0, true)
+		app.SetFocus(findInput)
+	}
+
+	askQuery = func() {
+		if rawJSON == nil {
+			return
+		}
+		showInspectQuery(app, func() {
+			setRoot(app, flex)
+			app.SetFocus(tabs[current].table)
+		}, containerName, rawJSON, reveal)
+	}
+
+	setRoot(app, flex)
+	app.SetFocus(tabs[0].table)
+}
+
+// fillInspectTabs populates the inspect sub-tabs from the structured inspection data
+func fillInspectTabs(tabs []*inspectTab, c *docker.ContainerDetails) {
+	state := c.Status
+	switch {
+	case c.Paused:
+		state += " (paused)"
+	case c.Restarting:
+		state += " (restarting)"
+	}
+
+	memory := "unlimited"
+	if c.MemoryLimit > 0 {
+		memory = fmt.Sprintf("%d MB", c.MemoryLimit/1024/1024)
+	}
+	cpus := "unlimited"
+	if c.NanoCPUs > 0 {
+		cpus = fmt.Sprintf("%.2f", float64(c.NanoCPUs)/1e9)
+	}
+
+	var ports []string
+	for _, p := range c.Ports {
+		if p.HostPort == "" {
+			ports = append(ports, fmt.Sprintf("%s/%s", p.ContainerPort, p.Protocol))
+		} else {
+			ports = append(ports, fmt.Sprintf("%s:%s→%s/%s", p.HostIP, p.HostPort, p.ContainerPort, p.Protocol))
+		}
+	}
+
+	info := [][]string{
+		{"ID", c.ID},
+		{"Name", c.Name},
+		{"Image", c.Image},
+		{"Image ID", c.ImageID},
+		{"Command", c.Command},
+		{"Created", c.Created},
+		{"Status", state},
+		{"PID", fmt.Sprintf("%d", c.PID)},
+		{"Exit Code", fmt.Sprintf("%d", c.ExitCode)},
+		{"OOM Killed", fmt.Sprintf("%v", c.OOMKilled)},
+		{"Started At", c.StartedAt},
+		{"Finished At", c.FinishedAt},
+		{"Restart Policy", fmt.Sprintf("%s (restarted %d times)", c.RestartPolicy, c.RestartCount)},
+		{"Hostname", c.Hostname},
+		{"Working Dir", c.WorkingDir},
+		{"User", c.User},
+		{"Memory Limit", memory},
+		{"CPU Shares", fmt.Sprintf("%d", c.CPUShares)},
+		{"CPUs", cpus},
+		{"Network Mode", c.NetworkMode},
+		{"IP Address", c.IPAddress()},
+		{"Ports", strings.Join(ports, ", ")},
+	}
+	tabs[0].setRows([]string{"FIELD", "VALUE"}, info, columnValues(info, 1))
+
+	var mounts [][]string
+	for _, m := range c.Mounts {
+		mode := "ro"
+		if m.RW {
+			mode = "rw"
+		}
+		host := "-"
+		if m.Type == "bind" {
+			host = "⏳ checking..."
+		}
+		mounts = append(mounts, []string{m.Type, m.Source, m.Destination, mode, host})
+	}
+	tabs[2].setRows([]string{"TYPE", "SOURCE", "DESTINATION", "MODE", "HOST PATH"}, mounts, columnValues(mounts, 1))
+
+	var networks [][]string
+	for _, n := range c.Networks {
+		networks = append(networks, []string{n.Network, n.IPAddress, n.Gateway, n.MacAddress, strings.Join(n.Aliases, ", ")})
+	}
+	tabs[3].setRows([]string{"NETWORK", "IP ADDRESS", "GATEWAY", "MAC", "ALIASES"}, networks, columnValues(networks, 1))
+
+	keys := make([]string, 0, len(c.Labels))
+	for key := range c.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var labels [][]string
+	for _, key := range keys {
+		labels = append(labels, []string{key, c.Labels[key]})
+	}
+	tabs[4].setRows([]string{"LABEL", "VALUE"}, labels, columnValues(labels, 1))
+}
+
+// fillRunTab shows the run command a line at a time, as shown; every line copies the
+// whole command
+func fillRunTab(tab *inspectTab, command, shown string) {
+	lines := strings.Split(shown, "\n")
+	values := make([]string, len(lines))
+	rows := make([][]string, len(lines))
+	for i, line := range lines {
+		rows[i] = []string{line}
+		values[i] = command
+	}
+	tab.setRows([]string{"DOCKER RUN"}, rows, values)
+}
+
+// checkBindMounts looks at the host paths of the bind mounts, one at a time since
+// adding up a large directory takes a moment, fills in the HOST PATH column and records
+// the results in checks by mount index
+func checkBindMounts(app *tview.Application, tab *inspectTab, containerID string, c *docker.ContainerDetails, checks map[int]docker.BindCheck) {
+	if c == nil {
+		return
+	}
+	for i, m := range c.Mounts {
+		if m.Type != "bind" {
+			continue
+		}
+		i, check := i, docker.CheckBindSource(containerID, m.Source)
+		text, color := bindCheckText(check)
+		app.QueueUpdateDraw(func() {
+			checks[i] = check
+			tab.table.SetCell(i+1, 4, tview.NewTableCell(text).SetTextColor(tcell.GetColor(color)).SetExpansion(1))
+		})
+	}
+}
+
+// bindCheckText describes a bind mount host path, e.g. "✓ dir 1.20 GB drwxr-xr-x"
+func bindCheckText(check docker.BindCheck) (string, string) {
+	t := currentTheme()
+	switch {
+	case !check.Checked:
+		return "remote host, not checked", t.Muted
+	case check.Err != nil:
+		return "? " + errorSummary(check.Err), t.Warning
+	case !check.Exists:
+		return "✗ missing on the host", t.Error
+	}
+
+	kind := "file"
+	if check.Dir {
+		kind = "dir"
+	}
+	size := docker.FormatBytes(uint64(check.Size))
+	if check.Partial {
+		size = "≥" + size
+	}
+	return fmt.Sprintf("✓ %s %s %s", kind, size, check.Mode), t.Success
+}
+
+// fillEnvList shows the env vars of a container, secret values masked unless revealed
+func fillEnvList(tab *inspectTab, env []docker.EnvVar, reveal bool) {
+	var rows [][]string
+	values := make([]string, len(env))
+	for i, e := range env {
+		rows = append(rows, []string{e.Key, maskEnv(e.Key, e.Value, reveal)})
+		values[i] = e.Value
+	}
+	tab.setRows([]string{"VARIABLE", "VALUE"}, rows, values)
+	tab.table.SetTitle(tab.title + secretsNote(len(env), func(i int) string { return env[i].Key }, reveal))
+}
+
+// fillEnvDiff shows each env var next to the image default, highlighting overrides.
+// Secret values are masked unless revealed.
+func fillEnvDiff(tab *inspectTab, diff []docker.EnvDiff, reveal bool) {
+	t := currentTheme()
+	var rows [][]string
+	values := make([]string, len(diff))
+	for i, e := range diff {
+		rows = append(rows, []string{e.Key, maskEnv(e.Key, e.Value, reveal), maskEnv(e.Key, e.ImageValue, reveal), e.Origin})
+		values[i] = e.Value
+	}
+	tab.setRows([]string{"VARIABLE", "VALUE", "IMAGE DEFAULT", "ORIGIN"}, rows, values)
+
+	colors := map[string]string{
+		docker.EnvFromImage:  t.Muted,
+		docker.EnvOverridden: t.Warning,
+		docker.EnvAdded:      t.Info,
+	}
+	overridden := 0
+	for i, e := range diff {
+		if e.Origin == docker.EnvOverridden {
+			overridden++
+			tab.table.GetCell(i+1, 1).SetAttributes(tcell.AttrBold)
+		}
+		tab.table.GetCell(i+1, 3).SetTextColor(tcell.GetColor(colors[e.Origin]))
+	}
+	tab.table.SetTitle(fmt.Sprintf("%s(%d overridden) ", tab.title, overridden) +
+		secretsNote(len(diff), func(i int) string { return diff[i].Key }, reveal))
+}
+
+// secretsNote tells in a tab title how many of n env vars are masked, "" for none
+func secretsNote(n int, key func(int) string, reveal bool) string {
+	secrets := 0
+	for i := 0; i < n; i++ {
+		if isSecretEnv(key(i)) {
+			secrets++
+		}
+	}
+	switch {
+	case secrets == 0:
+		return ""
+	case reveal:
+		return fmt.Sprintf("(%d secrets shown, r hides) ", secrets)
+	}
+	return fmt.Sprintf("(%d secrets masked, r reveals) ", secrets)
+}
+
+// columnValues returns one column of a row set
+func columnValues(rows [][]string, col int) []string {
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = row[col]
+	}
+	return values
+}
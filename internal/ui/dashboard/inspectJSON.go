@@ -0,0 +1,420 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// jsonNode is one value of the raw inspect JSON, keeping the daemon's key order
+type jsonNode struct {
+	key      string // object key, "" for array elements and the root
+	path     string // jq style, e.g. .HostConfig.Binds[0]
+	kind     byte   // '{', '[', 's' string, 'n' number, 'l' true/false/null
+	value    string // scalars only; strings unquoted
+	children []*jsonNode
+	parent   *jsonNode
+	folded   bool
+}
+
+// jsonRow is one line of the tree; an unfolded object or array has a closing line too
+type jsonRow struct {
+	node    *jsonNode
+	depth   int
+	closing bool
+	last    bool // no trailing comma
+}
+
+// identPattern matches keys that can follow a dot in a path
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseJSONTree reads a JSON document into a tree, objects and arrays below the top
+// level folded
+func parseJSONTree(data []byte) (*jsonNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := readJSONNode(dec, nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range root.children {
+		child.folded = len(child.children) > 0
+	}
+	return root, nil
+}
+
+// readJSONNode reads the next value from dec
+func readJSONNode(dec *json.Decoder, parent *jsonNode, key, path string) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &jsonNode{key: key, path: path, parent: parent}
+	switch v := tok.(type) {
+	case json.Delim:
+		n.kind = byte(v)
+		for i := 0; dec.More(); i++ {
+			childKey, childPath := "", fmt.Sprintf("%s[%d]", path, i)
+			if n.kind == '{' {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				childKey, _ = tok.(string)
+				childPath = path + "." + childKey
+				if !identPattern.MatchString(childKey) {
+					childPath = fmt.Sprintf("%s[%q]", path, childKey)
+				}
+			}
+			child, err := readJSONNode(dec, n, childKey, childPath)
+			if err != nil {
+				return nil, err
+			}
+			n.children = append(n.children, child)
+		}
+		// The closing bracket
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		n.kind, n.value = 's', v
+	case json.Number:
+		n.kind, n.value = 'n', v.String()
+	case bool:
+		n.kind, n.value = 'l', fmt.Sprint(v)
+	case nil:
+		n.kind, n.value = 'l', "null"
+	}
+	if n.path == "" && parent == nil {
+		n.path = "."
+	}
+	return n, nil
+}
+
+// highlightJSON renders a JSON document unfolded and in the theme colors
+func highlightJSON(data []byte) (string, error) {
+	root, err := parseJSONTree(data)
+	if err != nil {
+		return "", err
+	}
+	root.walk(func(n *jsonNode) { n.folded = false })
+	var b strings.Builder
+	for _, r := range flattenJSON(nil, root, 0, true) {
+		b.WriteString(jsonRowText(r, func(n *jsonNode) string { return n.value }) + "\n")
+	}
+	return b.String(), nil
+}
+
+// walk visits the node and everything below it, in document order
+func (n *jsonNode) walk(visit func(*jsonNode)) {
+	visit(n)
+	for _, child := range n.children {
+		child.walk(visit)
+	}
+}
+
+// folder reports whether the node is an object or array with something to fold
+func (n *jsonNode) folder() bool {
+	return (n.kind == '{' || n.kind == '[') && len(n.children) > 0
+}
+
+// json writes the node back out as indented JSON
+func (n *jsonNode) json() string {
+	var b strings.Builder
+	n.write(&b, 0)
+	return b.String()
+}
+
+func (n *jsonNode) write(b *strings.Builder, depth int) {
+	switch n.kind {
+	case '{', '[':
+		closing := "}"
+		if n.kind == '[' {
+			closing = "]"
+		}
+		if len(n.children) == 0 {
+			b.WriteString(string(n.kind) + closing)
+			return
+		}
+		b.WriteString(string(n.kind) + "\n")
+		for i, child := range n.children {
+			b.WriteString(strings.Repeat("  ", depth+1))
+			if n.kind == '{' {
+				b.WriteString(quoteJSON(child.key) + ": ")
+			}
+			child.write(b, depth+1)
+			if i < len(n.children)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(strings.Repeat("  ", depth) + closing)
+	case 's':
+		b.WriteString(quoteJSON(n.value))
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// quoteJSON quotes a string the way JSON does, without escaping HTML characters
+func quoteJSON(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// normalizePath writes ["key"] as .key so either form finds a node
+func normalizePath(path string) string {
+	path = strings.ReplaceAll(path, `["`, ".")
+	path = strings.ReplaceAll(path, `"]`, "")
+	path = strings.TrimSuffix(path, ".")
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// inspectJSONView is the JSON tab of inspect: the raw inspect output as a foldable,
+// highlighted tree, for fields the other tabs leave out
+type inspectJSONView struct {
+	tab    *inspectTab
+	root   *jsonNode
+	rows   []jsonRow
+	reveal bool
+	query  string // last text search, for n
+}
+
+// newInspectJSONView sets up the JSON tab on tab's table
+func newInspectJSONView(tab *inspectTab) *inspectJSONView {
+	v := &inspectJSONView{tab: tab}
+	tab.table.SetSelectionChangedFunc(func(row, column int) { v.showPath() })
+	return v
+}
+
+// load shows the inspect output, or an error when it isn't valid JSON
+func (v *inspectJSONView) load(data []byte) error {
+	root, err := parseJSONTree(data)
+	if err != nil {
+		return err
+	}
+	v.root = root
+	v.render()
+	v.tab.table.Select(1, 0)
+	v.tab.table.ScrollToBeginning()
+	return nil
+}
+
+// selected returns the node under the cursor, nil before the data is loaded
+func (v *inspectJSONView) selected() *jsonNode {
+	row, _ := v.tab.table.GetSelection()
+	if row < 1 || row > len(v.rows) {
+		return nil
+	}
+	return v.rows[row-1].node
+}
+
+// render lays out the unfolded part of the tree, keeping the cursor on the same node
+func (v *inspectJSONView) render() {
+	if v.root == nil {
+		return
+	}
+	current := v.selected()
+	v.rows = flattenJSON(v.rows[:0], v.root, 0, true)
+
+	table := v.tab.table
+	table.Clear()
+	table.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+	for i, r := range v.rows {
+		table.SetCell(i+1, 0, tview.NewTableCell(jsonRowText(r, v.shown)).SetExpansion(1))
+	}
+	if current != nil {
+		v.selectNode(current)
+	}
+	v.showPath()
+}
+
+// flattenJSON appends the lines of the unfolded part of the tree below n to rows
+func flattenJSON(rows []jsonRow, n *jsonNode, depth int, last bool) []jsonRow {
+	rows = append(rows, jsonRow{node: n, depth: depth, last: last || n.folder() && !n.folded})
+	if !n.folder() || n.folded {
+		return rows
+	}
+	for i, child := range n.children {
+		rows = flattenJSON(rows, child, depth+1, i == len(n.children)-1)
+	}
+	return append(rows, jsonRow{node: n, depth: depth, closing: true, last: last})
+}
+
+// jsonRowText renders a row with its key, value and brackets in the theme colors; shown
+// gives the text of string values
+func jsonRowText(r jsonRow, shown func(*jsonNode) string) string {
+	t := currentTheme()
+	n := r.node
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", r.depth))
+
+	if r.closing {
+		if n.kind == '{' {
+			b.WriteString("}")
+		} else {
+			b.WriteString("]")
+		}
+	} else {
+		if n.parent != nil && n.parent.kind == '{' {
+			fmt.Fprintf(&b, "[%s]%s[-]: ", t.Accent, tview.Escape(quoteJSON(n.key)))
+		}
+		switch n.kind {
+		case '{', '[':
+			closing := "}"
+			if n.kind == '[' {
+				closing = "]"
+			}
+			switch {
+			case len(n.children) == 0:
+				b.WriteString(string(n.kind) + closing)
+			case n.folded:
+				unit := "keys"
+				if n.kind == '[' {
+					unit = "items"
+				}
+				fmt.Fprintf(&b, "%s … %s [%s]%d %s[-]", string(n.kind), closing, t.Muted, len(n.children), unit)
+			default:
+				b.WriteString(string(n.kind))
+			}
+		case 's':
+			fmt.Fprintf(&b, "[%s]%s[-]", t.Success, tview.Escape(quoteJSON(shown(n))))
+		case 'n':
+			fmt.Fprintf(&b, "[%s]%s[-]", t.Info, n.value)
+		default:
+			fmt.Fprintf(&b, "[%s]%s[-]", t.Warning, n.value)
+		}
+	}
+	if !r.last {
+		b.WriteString(",")
+	}
+	return b.String()
+}
+
+// shown is the text of a string node as displayed, with secret env values masked
+// until revealed
+func (v *inspectJSONView) shown(n *jsonNode) string {
+	if n.parent == nil || n.parent.path != ".Config.Env" {
+		return n.value
+	}
+	name, value, _ := strings.Cut(n.value, "=")
+	return name + "=" + maskEnv(name, value, v.reveal)
+}
+
+// showPath puts the path of the node under the cursor in the header row
+func (v *inspectJSONView) showPath() {
+	n := v.selected()
+	if n == nil {
+		return
+	}
+	t := currentTheme()
+	v.tab.table.GetCell(0, 0).
+		SetText(fmt.Sprintf("[%s::b]%s[-:-:-]", t.Highlight, tview.Escape(n.path)))
+}
+
+// selectNode moves the cursor to the opening line of a node
+func (v *inspectJSONView) selectNode(n *jsonNode) {
+	for i, r := range v.rows {
+		if r.node == n && !r.closing {
+			v.tab.table.Select(i+1, 0)
+			return
+		}
+	}
+}
+
+// toggle folds or unfolds the object or array under the cursor
+func (v *inspectJSONView) toggle() {
+	if n := v.selected(); n != nil && n.folder() {
+		n.folded = !n.folded
+		v.render()
+	}
+}
+
+// foldAll folds everything below the top level, or unfolds the whole tree
+func (v *inspectJSONView) foldAll(fold bool) {
+	if v.root == nil {
+		return
+	}
+	v.root.walk(func(n *jsonNode) {
+		n.folded = fold && n.parent != nil && n.folder()
+	})
+	v.render()
+}
+
+// find jumps to a path such as .HostConfig.Binds, or else to the next key or value
+// containing the text; folded parents of the match are unfolded
+func (v *inspectJSONView) find(query string) bool {
+	query = strings.TrimSpace(query)
+	if v.root == nil || query == "" {
+		return false
+	}
+	var match *jsonNode
+	if strings.HasPrefix(query, ".") || strings.HasPrefix(query, "[") {
+		want := normalizePath(query)
+		v.root.walk(func(n *jsonNode) {
+			if match == nil && strings.EqualFold(normalizePath(n.path), want) {
+				match = n
+			}
+		})
+	} else {
+		v.query = query
+		match = v.next()
+	}
+	if match == nil {
+		return false
+	}
+	for p := match.parent; p != nil; p = p.parent {
+		p.folded = false
+	}
+	v.render()
+	v.selectNode(match)
+	return true
+}
+
+// next finds the first node after the cursor whose key or shown value contains the
+// last text searched for, wrapping around at the end
+func (v *inspectJSONView) next() *jsonNode {
+	if v.root == nil || v.query == "" {
+		return nil
+	}
+	query := strings.ToLower(v.query)
+	var nodes []*jsonNode
+	v.root.walk(func(n *jsonNode) { nodes = append(nodes, n) })
+
+	start := 0
+	if current := v.selected(); current != nil {
+		for i, n := range nodes {
+			if n == current {
+				start = i + 1
+				break
+			}
+		}
+	}
+	for i := range nodes {
+		n := nodes[(start+i)%len(nodes)]
+		if strings.Contains(strings.ToLower(n.key), query) ||
+			n.kind == 's' && strings.Contains(strings.ToLower(v.shown(n)), query) ||
+			n.kind != 's' && strings.Contains(strings.ToLower(n.value), query) {
+			return n
+		}
+	}
+	return nil
+}
+
+// showError replaces the tab contents with an error line
+func (v *inspectJSONView) showError(err error) {
+	v.tab.table.Clear()
+	v.tab.table.SetCell(0, 0, tview.NewTableCell("Error: "+errorSummary(err)).
+		SetTextColor(tcell.GetColor(currentTheme().Error)))
+}
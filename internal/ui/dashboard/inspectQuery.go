@@ -0,0 +1,255 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/itchyny/gojq"
+	"github.com/rivo/tview"
+)
+
+// jqHistorySize is how many queries the jq history keeps
+const jqHistorySize = 200
+
+// Queries run on every keystroke, so runaway ones like "repeat(.)" are cut short
+const (
+	jqMaxResults = 1000
+	jqTimeout    = 2 * time.Second
+)
+
+// openJQHistory loads the recent inspect queries, shared by all containers; on error
+// the history starts empty
+func openJQHistory() *CommandHistory {
+	h, _ := loadCommandHistory(filepath.Join(filepath.Dir(shellHistoryDir()), "jq.history"), jqHistorySize)
+	return h
+}
+
+// showInspectQuery runs jq expressions against the inspect output of a container as
+// they are typed, like docker inspect | jq without leaving the dashboard. Secret env
+// values are masked unless reveal is set. back returns to the inspect screen.
+func showInspectQuery(app *tview.Application, back func(), containerName string, data []byte, reveal bool) {
+	t := currentTheme()
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		showToast(app, toastWarning, "Inspect output is not valid JSON: "+errorSummary(err))
+		return
+	}
+	if !reveal {
+		maskInspectEnv(doc)
+	}
+	history := openJQHistory()
+
+	input := tview.NewInputField().
+		SetLabel(" jq ").
+		SetPlaceholder(`e.g. .HostConfig.Binds or .Mounts[] | select(.RW) | .Source`).
+		SetFieldWidth(0)
+
+	result := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	result.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔍 jq: %s ", tview.Escape(containerName))).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	recent := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	recent.SetBorder(true).
+		SetTitle(" Recent ").
+		SetBorderColor(tcell.GetColor(t.Muted))
+
+	status := tview.NewTextView().
+		SetDynamicColors(true)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] Enter [-:-:-] Keep in history   [black:green] ↑/↓ [-:-:-] Recent queries   [black:green] Tab [-:-:-] Results / recent   [black:green] y [-:-:-] Copy output   [black:red] ESC [-:-:-] Back")
+
+	body := tview.NewFlex().
+		AddItem(result, 0, 1, false).
+		AddItem(recent, 40, 0, false)
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(status, 1, 0, false).
+		AddItem(body, 0, 1, false).
+		AddItem(footer, 1, 0, false)
+
+	// output is the plain text of the last successful run, for y
+	var output string
+
+	// run evaluates the query; a query that doesn't compile yet keeps the last results
+	run := func(text string) {
+		if strings.TrimSpace(text) == "" {
+			text = "."
+		}
+		query, err := gojq.Parse(text)
+		if err != nil {
+			status.SetText(fmt.Sprintf(" [%s]%s[-]", t.Muted, tview.Escape(err.Error())))
+			return
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			status.SetText(fmt.Sprintf(" [%s]%s[-]", t.Muted, tview.Escape(err.Error())))
+			return
+		}
+		values, err := runJQ(code, doc)
+
+		var b, plain strings.Builder
+		for _, v := range values {
+			text := formatJQ(v)
+			highlighted, herr := highlightJSON([]byte(text))
+			if herr != nil {
+				highlighted = tview.Escape(text)
+			}
+			b.WriteString(highlighted)
+			plain.WriteString(text)
+		}
+		output = plain.String()
+		result.SetText(b.String())
+		result.ScrollToBeginning()
+
+		switch {
+		case err != nil:
+			status.SetText(fmt.Sprintf(" [%s]%s[-]", t.Error, tview.Escape(err.Error())))
+		case len(values) == 1:
+			status.SetText(fmt.Sprintf(" [%s]1 result[-]", t.Success))
+		default:
+			status.SetText(fmt.Sprintf(" [%s]%d results[-]", t.Success, len(values)))
+		}
+	}
+
+	// fillRecent lists the recent queries, newest first
+	fillRecent := func() {
+		recent.Clear()
+		for i := len(history.commands) - 1; i >= 0; i-- {
+			query := history.commands[i]
+			recent.AddItem(tview.Escape(query), "", 0, func() {
+				input.SetText(query)
+				app.SetFocus(input)
+			})
+		}
+	}
+
+	input.SetChangedFunc(run)
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			back()
+		case tcell.KeyEnter:
+			if text := strings.TrimSpace(input.GetText()); text != "" {
+				history.Add(text)
+				fillRecent()
+			}
+		case tcell.KeyTab:
+			app.SetFocus(result)
+		}
+	})
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if query := history.Previous(); query != "" {
+				input.SetText(query)
+			}
+			return nil
+		case tcell.KeyDown:
+			input.SetText(history.Next())
+			return nil
+		}
+		return event
+	})
+
+	result.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			back()
+			return nil
+		case event.Key() == tcell.KeyTab:
+			app.SetFocus(recent)
+			return nil
+		case event.Rune() == 'y':
+			if output != "" {
+				copyToClipboard(app, output)
+				showToast(app, toastSuccess, "jq output copied")
+			}
+			return nil
+		}
+		return event
+	})
+	recent.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			back()
+			return nil
+		case event.Key() == tcell.KeyTab:
+			app.SetFocus(input)
+			return nil
+		}
+		return event
+	})
+
+	fillRecent()
+	run("")
+	setRoot(app, flex)
+	app.SetFocus(input)
+}
+
+// runJQ collects the outputs of a compiled query up to the first error, at most
+// jqMaxResults of them within jqTimeout
+func runJQ(code *gojq.Code, input any) ([]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+
+	var values []any
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return values, nil
+		}
+		if err, ok := v.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				return values, nil // halt stops without an error
+			}
+			return values, err
+		}
+		if len(values) == jqMaxResults {
+			return values, fmt.Errorf("stopped after %d results", jqMaxResults)
+		}
+		values = append(values, v)
+	}
+}
+
+// formatJQ writes an output as indented JSON, the way jq prints it
+func formatJQ(v any) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(&b, "%v\n", v)
+	}
+	return b.String()
+}
+
+// maskInspectEnv masks secret values in .Config.Env of decoded inspect output
+func maskInspectEnv(doc any) {
+	root, _ := doc.(map[string]any)
+	config, _ := root["Config"].(map[string]any)
+	env, _ := config["Env"].([]any)
+	for i, e := range env {
+		if s, ok := e.(string); ok {
+			name, value, _ := strings.Cut(s, "=")
+			env[i] = name + "=" + maskEnv(name, value, false)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+
+	"devops-dashboard/internal/docker"
+)
+
+// IORates turns the cumulative network and block I/O counters of successive stats
+// samples into per-second rates, keeping a short history of each for sparklines
+type IORates struct {
+	mu     sync.RWMutex
+	id     string
+	prev   *docker.ContainerStats
+	size   int
+	rx, tx []float64 // network bytes per second
+	rd, wr []float64 // block bytes per second
+}
+
+func NewIORates(size int) *IORates {
+	return &IORates{size: size}
+}
+
+// Add records a sample of a container. Switching containers, or counters going backwards
+// after a restart, starts over.
+func (r *IORates) Add(containerID string, s *docker.ContainerStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if containerID != r.id || r.prev == nil || s.NetRx < r.prev.NetRx || s.NetTx < r.prev.NetTx ||
+		s.BlockRead < r.prev.BlockRead || s.BlockWrite < r.prev.BlockWrite {
+		r.reset(containerID)
+		r.prev = s
+		return
+	}
+
+	elapsed := s.Read.Sub(r.prev.Read).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	rate := func(now, before uint64) float64 {
+		return float64(now-before) / elapsed
+	}
+	r.rx = appendSample(r.rx, rate(s.NetRx, r.prev.NetRx), r.size)
+	r.tx = appendSample(r.tx, rate(s.NetTx, r.prev.NetTx), r.size)
+	r.rd = appendSample(r.rd, rate(s.BlockRead, r.prev.BlockRead), r.size)
+	r.wr = appendSample(r.wr, rate(s.BlockWrite, r.prev.BlockWrite), r.size)
+	r.prev = s
+}
+
+// Reset drops the history
+func (r *IORates) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reset("")
+}
+
+func (r *IORates) reset(containerID string) {
+	r.id = containerID
+	r.prev = nil
+	r.rx, r.tx, r.rd, r.wr = nil, nil, nil, nil
+}
+
+// NetGraph renders the RX and TX rates with a sparkline of the given width each
+func (r *IORates) NetGraph(width int) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return rateLine("↓", r.rx, width) + "\n" + rateLine("↑", r.tx, width)
+}
+
+// BlockGraph renders the read and write rates with a sparkline of the given width each
+func (r *IORates) BlockGraph(width int) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return rateLine("R", r.rd, width) + "\n" + rateLine("W", r.wr, width)
+}
+
+// rateLine shows the latest rate followed by its sparkline
+func rateLine(label string, rates []float64, width int) string {
+	if len(rates) == 0 {
+		return fmt.Sprintf("%s [gray]measuring...[-]", label)
+	}
+	if len(rates) > width {
+		rates = rates[len(rates)-width:]
+	}
+	return fmt.Sprintf("%s %12s %s", label, formatRate(rates[len(rates)-1]), createMiniGraph(rates, width))
+}
+
+// formatRate formats bytes per second, e.g. 1.50 MB/s
+func formatRate(bytesPerSec float64) string {
+	return docker.FormatBytes(uint64(bytesPerSec)) + "/s"
+}
+
+func appendSample(history []float64, v float64, size int) []float64 {
+	history = append(history, v)
+	if len(history) > size {
+		history = history[1:]
+	}
+	return history
+}
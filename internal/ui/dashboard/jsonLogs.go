@@ -0,0 +1,292 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Field names structured loggers commonly use for the level, message and time
+var (
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+	messageKeys = []string{"msg", "message"}
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+)
+
+// jsonLine is a log line whose payload is a JSON object. Prefix is whatever came before
+// the object, such as the timestamp added by the daemon.
+type jsonLine struct {
+	prefix string
+	fields map[string]any
+}
+
+// parseJSONLine detects a JSON object in a log line
+func parseJSONLine(line string) (*jsonLine, bool) {
+	start := strings.IndexByte(line, '{')
+	if start < 0 || !strings.HasSuffix(strings.TrimSpace(line), "}") {
+		return nil, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(line[start:]), &fields); err != nil {
+		return nil, false
+	}
+	return &jsonLine{prefix: line[:start], fields: fields}, true
+}
+
+// field looks up a value by name; dots reach into nested objects, e.g. "http.status",
+// unless a flat key such as "log.level" exists
+func (j *jsonLine) field(name string) (any, bool) {
+	if v, ok := j.fields[name]; ok {
+		return v, true
+	}
+	var current any = j.fields
+	for _, part := range strings.Split(name, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// first returns the first of the given fields that is present
+func (j *jsonLine) first(names []string) (string, string, bool) {
+	for _, name := range names {
+		if v, ok := j.fields[name]; ok {
+			return name, fieldString(v), true
+		}
+	}
+	return "", "", false
+}
+
+// level maps the level field to ERROR, WARN, INFO or DEBUG; empty when unknown
+func (j *jsonLine) level() string {
+	for _, name := range levelKeys {
+		v, ok := j.field(name)
+		if !ok {
+			continue
+		}
+		// Numeric levels as written by pino and bunyan
+		if n, ok := v.(float64); ok {
+			switch {
+			case n >= 50:
+				return "ERROR"
+			case n >= 40:
+				return "WARN"
+			case n >= 30:
+				return "INFO"
+			default:
+				return "DEBUG"
+			}
+		}
+		switch strings.ToLower(fieldString(v)) {
+		case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emergency":
+			return "ERROR"
+		case "warn", "warning":
+			return "WARN"
+		case "info", "notice", "information":
+			return "INFO"
+		case "debug", "trace":
+			return "DEBUG"
+		}
+	}
+	return ""
+}
+
+// compact renders the line as level, message and the remaining fields as key=value
+func (j *jsonLine) compact() string {
+	var b strings.Builder
+	b.WriteString(tview.Escape(j.prefix))
+
+	skip := make(map[string]bool)
+	if name, level, ok := j.first(levelKeys); ok {
+		skip[name] = true
+		fmt.Fprintf(&b, "[::b]%-5s[::-] ", tview.Escape(strings.ToUpper(level)))
+	}
+	if name, _, ok := j.first(timeKeys); ok {
+		skip[name] = true
+	}
+	if name, msg, ok := j.first(messageKeys); ok {
+		skip[name] = true
+		b.WriteString(tview.Escape(msg))
+	}
+
+	keys := make([]string, 0, len(j.fields))
+	for k := range j.fields {
+		if !skip[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  [gray]%s=[-]%s", tview.Escape(k), tview.Escape(fieldString(j.fields[k])))
+	}
+	return b.String()
+}
+
+// jsonKeyPattern finds the keys in indented JSON
+var jsonKeyPattern = regexp.MustCompile(`(?m)^(\s*)("(?:[^"\\]|\\.)*")(:)`)
+
+// pretty renders the whole object indented with colored keys, for the expanded view
+func (j *jsonLine) pretty() string {
+	data, err := json.MarshalIndent(j.fields, "", "  ")
+	if err != nil {
+		return tview.Escape(fmt.Sprint(j.fields))
+	}
+	return jsonKeyPattern.ReplaceAllString(tview.Escape(string(data)), `$1[aqua]$2[-]$3`)
+}
+
+// fieldString formats a JSON value for display and comparison
+func fieldString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// fieldCondition is one term of a field filter, e.g. level=error or status>=500
+type fieldCondition struct {
+	field string
+	op    string // "", "=", "!=", "~", ">", ">=", "<", "<="
+	value string
+	re    *regexp.Regexp
+}
+
+// fieldFilter selects and orders JSON log lines by their fields
+type fieldFilter struct {
+	conditions []fieldCondition
+	sortField  string
+	sortDesc   bool
+}
+
+// fieldOperators are tried in this order, so two character operators win
+var fieldOperators = []string{"!=", ">=", "<=", "=", "~", ">", "<"}
+
+// parseFieldFilter parses space separated terms: field (present), field=value,
+// field!=value, field~regex, field>n, field<n and sort:field or sort:-field
+func parseFieldFilter(s string) (fieldFilter, error) {
+	var f fieldFilter
+	for _, term := range strings.Fields(s) {
+		if name, ok := strings.CutPrefix(term, "sort:"); ok {
+			f.sortField, f.sortDesc = strings.TrimPrefix(name, "-"), strings.HasPrefix(name, "-")
+			if f.sortField == "" {
+				return f, fmt.Errorf("sort needs a field name")
+			}
+			continue
+		}
+
+		c := fieldCondition{field: term}
+		for _, op := range fieldOperators {
+			if i := strings.Index(term, op); i > 0 {
+				c = fieldCondition{field: term[:i], op: op, value: term[i+len(op):]}
+				break
+			}
+		}
+		switch c.op {
+		case "~":
+			re, err := regexp.Compile("(?i)" + c.value)
+			if err != nil {
+				return f, fmt.Errorf("invalid regex in %q: %v", term, err)
+			}
+			c.re = re
+		case ">", ">=", "<", "<=":
+			if _, err := strconv.ParseFloat(c.value, 64); err != nil {
+				return f, fmt.Errorf("%q compares with a number", term)
+			}
+		}
+		f.conditions = append(f.conditions, c)
+	}
+	return f, nil
+}
+
+// matches reports whether a line passes all conditions; lines that aren't JSON only
+// pass an empty filter
+func (f fieldFilter) matches(j *jsonLine) bool {
+	if len(f.conditions) == 0 {
+		return true
+	}
+	if j == nil {
+		return false
+	}
+	for _, c := range f.conditions {
+		v, ok := j.field(c.field)
+		if !ok {
+			return false
+		}
+		s := fieldString(v)
+		switch c.op {
+		case "=":
+			if !strings.EqualFold(s, c.value) {
+				return false
+			}
+		case "!=":
+			if strings.EqualFold(s, c.value) {
+				return false
+			}
+		case "~":
+			if !c.re.MatchString(s) {
+				return false
+			}
+		case ">", ">=", "<", "<=":
+			n, err := strconv.ParseFloat(s, 64)
+			want, _ := strconv.ParseFloat(c.value, 64)
+			if err != nil ||
+				(c.op == ">" && !(n > want)) || (c.op == ">=" && !(n >= want)) ||
+				(c.op == "<" && !(n < want)) || (c.op == "<=" && !(n <= want)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// less orders two lines by the sort field, numerically when both values are numbers;
+// lines without the field go last
+func (f fieldFilter) less(a, b *jsonLine) bool {
+	value := func(j *jsonLine) (string, bool) {
+		if j == nil {
+			return "", false
+		}
+		v, ok := j.field(f.sortField)
+		return fieldString(v), ok
+	}
+	va, okA := value(a)
+	vb, okB := value(b)
+	if !okA || !okB {
+		return okA && !okB
+	}
+
+	cmp := strings.Compare(va, vb)
+	na, errA := strconv.ParseFloat(va, 64)
+	nb, errB := strconv.ParseFloat(vb, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			cmp = -1
+		case na > nb:
+			cmp = 1
+		default:
+			cmp = 0
+		}
+	}
+	if f.sortDesc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
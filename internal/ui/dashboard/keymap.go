@@ -0,0 +1,327 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Actions that can be rebound through the "keybindings" section of the config
+const (
+	actionLogs         = "logs"
+	actionAdvancedLogs = "advanced_logs"
+	actionToggle       = "start_stop"
+	actionRestart      = "restart"
+	actionStats        = "stats"
+	actionHistory      = "history"
+	actionTimeline     = "timeline"
+	actionInspect      = "inspect"
+	actionShell        = "shell"
+	actionAttach       = "attach"
+	actionNotifyExit   = "notify_exit"
+	actionHealth       = "health"
+	actionLabels       = "labels"
+	actionDelete       = "delete"
+	actionRecreate     = "recreate"
+	actionClone        = "clone"
+	actionBlueGreen    = "blue_green"
+	actionSecurity     = "security"
+	actionNote         = "note"
+	actionCopyID       = "copy_id"
+	actionCopyName     = "copy_name"
+	actionCopyImage    = "copy_image"
+	actionCopyIP       = "copy_ip"
+	actionCopyOwner    = "copy_owner"
+	actionBulkMode     = "bulk_mode"
+	actionBulkSelect   = "bulk_select"
+
+	actionBulkSelectAll     = "bulk_select_all"
+	actionBulkInvert        = "bulk_invert"
+	actionBulkSelectRunning = "bulk_select_running"
+	actionBulkSelectStopped = "bulk_select_stopped"
+	actionBulkSelectRegex   = "bulk_select_regex"
+
+	actionBulkActions   = "bulk_actions"
+	actionExportLogs    = "export_logs"
+	actionTruncateLogs  = "truncate_logs"
+	actionSupportBundle = "support_bundle"
+	actionStartProfile  = "start_profile"
+	actionPlugins       = "plugins"
+	actionLogArchive    = "log_archive"
+	actionRefresh       = "refresh"
+	actionSort          = "sort"
+	actionTheme         = "theme"
+	actionBack          = "back"
+	actionNextTab       = "next_tab"
+	actionPrevTab       = "prev_tab"
+	actionHelp          = "help"
+	actionDiagnostics   = "diagnostics"
+	actionHosts         = "hosts"
+	actionOverview      = "overview"
+	actionTrash         = "trash"
+	actionCleanup       = "cleanup"
+	actionUptime        = "uptime"
+	actionPinning       = "pinning"
+	actionAutoFollow    = "auto_follow"
+	actionDeployWatch   = "deploy_watch"
+	actionFleetHealth   = "fleet_health"
+	actionRecent        = "recent"
+	actionReport        = "report"
+	actionQuit          = "quit"
+)
+
+// keyBinding describes one action, its default keys and where it is listed in help
+type keyBinding struct {
+	action      string
+	section     string
+	description string
+	keys        []string
+}
+
+// defaultBindings is the built-in key map, in the order shown in the help overlay
+var defaultBindings = []keyBinding{
+	{actionLogs, "Container Actions", "View Logs", []string{"l"}},
+	{actionAdvancedLogs, "Container Actions", "Advanced Logs", []string{"L"}},
+	{actionToggle, "Container Actions", "Start/Stop", []string{"s", "S"}},
+	{actionRestart, "Container Actions", "Restart", []string{"r", "R"}},
+	{actionStats, "Container Actions", "Real-time Stats", []string{"t", "T"}},
+	{actionHistory, "Container Actions", "Stats History", []string{"m", "M"}},
+	{actionTimeline, "Container Actions", "Lifecycle Timeline", []string{"ctrl-e"}},
+	{actionInspect, "Container Actions", "Inspect", []string{"i", "I"}},
+	{actionShell, "Container Actions", "Shell Menu", []string{"e", "E"}},
+	{actionAttach, "Container Actions", "Attach to Main Process", []string{"j", "J"}},
+	{actionNotifyExit, "Container Actions", "Notify on Exit", []string{"f", "F"}},
+	{actionHealth, "Container Actions", "Health Check", []string{"h", "H"}},
+	{actionLabels, "Container Actions", "Labels / Group", []string{"g", "G"}},
+	{actionRecreate, "Container Actions", "Recreate / Edit", []string{"u", "U"}},
+	{actionClone, "Container Actions", "Clone", []string{"n", "N"}},
+	{actionBlueGreen, "Container Actions", "Blue/Green & Canary", []string{"v", "V"}},
+	{actionSecurity, "Container Actions", "Capabilities / Seccomp", []string{"k", "K"}},
+	{actionNote, "Container Actions", "Note", []string{"ctrl-n"}},
+	{actionDelete, "Container Actions", "Delete", []string{"d", "D"}},
+	{actionTruncateLogs, "Container Actions", "Truncate Log File", []string{"z", "Z"}},
+	{actionSupportBundle, "Container Actions", "Support Bundle", []string{"ctrl-b"}},
+	{actionStartProfile, "Container Actions", "Start Profile", []string{"ctrl-p"}},
+	{actionPlugins, "Container Actions", "Plugins", []string{"p", "P"}},
+	{actionCopyID, "Clipboard", "Copy ID", []string{"y"}},
+	{actionCopyName, "Clipboard", "Copy Name", []string{"Y"}},
+	{actionCopyImage, "Clipboard", "Copy Image", []string{"c"}},
+	{actionCopyIP, "Clipboard", "Copy IP Address", []string{"C"}},
+	{actionCopyOwner, "Clipboard", "Copy Owner Contact / Runbook", []string{"@"}},
+	{actionBulkMode, "Bulk Operations", "Bulk Mode", []string{"b", "B"}},
+	{actionBulkSelect, "Bulk Operations", "Select", []string{"space"}},
+	{actionBulkSelectAll, "Bulk Operations", "Select All Visible", []string{"*"}},
+	{actionBulkInvert, "Bulk Operations", "Invert Selection", []string{"~"}},
+	{actionBulkSelectRunning, "Bulk Operations", "Select Running", []string{"+"}},
+	{actionBulkSelectStopped, "Bulk Operations", "Select Stopped", []string{"-"}},
+	{actionBulkSelectRegex, "Bulk Operations", "Select by Regex", []string{"/"}},
+	{actionBulkActions, "Bulk Operations", "Bulk Actions", []string{"a", "A"}},
+	{actionExportLogs, "Bulk Operations", "Export Logs", []string{"x", "X"}},
+	{actionLogArchive, "Navigation", "Log Archive", []string{"w", "W"}},
+	{actionRefresh, "Navigation", "Refresh", []string{"f5"}},
+	{actionSort, "Navigation", "Sort by created / name / CPU / memory / size", []string{"o", "O"}},
+	{actionTheme, "Navigation", "Theme", []string{"ctrl-t"}},
+	{actionNextTab, "Navigation", "Next Tab", []string{"tab"}},
+	{actionPrevTab, "Navigation", "Previous Tab", []string{"backtab"}},
+	{actionBack, "Navigation", "Back", []string{"backspace"}},
+	{actionHelp, "Navigation", "Help", []string{"?"}},
+	{actionDiagnostics, "Navigation", "Diagnostics", []string{"f2"}},
+	{actionHosts, "Navigation", "Switch Host", []string{"f3"}},
+	{actionOverview, "Navigation", "Top CPU / Memory Overview", []string{"f4"}},
+	{actionTrash, "Navigation", "Recently Deleted", []string{"f6"}},
+	{actionCleanup, "Navigation", "Cleanup: Leaked Resources", []string{"f7"}},
+	{actionUptime, "Navigation", "Uptime Report", []string{"f8"}},
+	{actionPinning, "Navigation", "Image Pinning Audit", []string{"f9"}},
+	{actionAutoFollow, "Navigation", "Follow New Containers", []string{"f10"}},
+	{actionDeployWatch, "Navigation", "Deployment Watch", []string{"f11"}},
+	{actionFleetHealth, "Navigation", "Health Summary", []string{"f12"}},
+	{actionRecent, "Navigation", "Recently Viewed Containers", []string{"ctrl-o"}},
+	{actionReport, "Navigation", "Export HTML Report", []string{"ctrl-r"}},
+	{actionQuit, "Navigation", "Quit", []string{"q", "Q"}},
+}
+
+// namedKeys maps key names usable in the config to tcell keys
+var namedKeys = map[string]tcell.Key{
+	"enter":     tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"backtab":   tcell.KeyBacktab,
+	"backspace": tcell.KeyBackspace2,
+	"delete":    tcell.KeyDelete,
+	"insert":    tcell.KeyInsert,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+	"pgup":      tcell.KeyPgUp,
+	"pgdn":      tcell.KeyPgDn,
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+	"f1":        tcell.KeyF1,
+	"f2":        tcell.KeyF2,
+	"f3":        tcell.KeyF3,
+	"f4":        tcell.KeyF4,
+	"f5":        tcell.KeyF5,
+	"f6":        tcell.KeyF6,
+	"f7":        tcell.KeyF7,
+	"f8":        tcell.KeyF8,
+	"f9":        tcell.KeyF9,
+	"f10":       tcell.KeyF10,
+	"f11":       tcell.KeyF11,
+	"f12":       tcell.KeyF12,
+}
+
+var keyNames = func() map[tcell.Key]string {
+	names := make(map[tcell.Key]string, len(namedKeys))
+	for name, key := range namedKeys {
+		names[key] = name
+	}
+	// Terminals report Backspace as either code
+	names[tcell.KeyBackspace] = "backspace"
+	return names
+}()
+
+// KeyMap resolves key presses to actions
+type KeyMap struct {
+	bindings []keyBinding
+	byKey    map[string]string
+}
+
+// NewKeyMap builds the key map from the defaults and the config overrides
+// (action → space separated keys), rejecting unknown actions and conflicts.
+func NewKeyMap(overrides map[string]string) (*KeyMap, error) {
+	bindings := make([]keyBinding, len(defaultBindings))
+	copy(bindings, defaultBindings)
+
+	index := make(map[string]int, len(bindings))
+	for i, b := range bindings {
+		index[b.action] = i
+	}
+
+	// Sort actions so error messages are deterministic
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		i, ok := index[action]
+		if !ok {
+			return nil, fmt.Errorf("keybindings: unknown action %q", action)
+		}
+		fields := strings.Fields(overrides[action])
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("keybindings: no key given for %q", action)
+		}
+
+		var keys []string
+		for _, field := range fields {
+			key, err := normalizeKey(field)
+			if err != nil {
+				return nil, fmt.Errorf("keybindings: %s: %w", action, err)
+			}
+			keys = append(keys, key)
+		}
+		bindings[i].keys = keys
+	}
+
+	km := &KeyMap{bindings: bindings, byKey: make(map[string]string)}
+	for _, b := range bindings {
+		for _, key := range b.keys {
+			if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+				return nil, fmt.Errorf("keybindings: %s: number keys are reserved for saved views", b.action)
+			}
+			if other, taken := km.byKey[key]; taken {
+				return nil, fmt.Errorf("keybindings: key %q is bound to both %q and %q", key, other, b.action)
+			}
+			km.byKey[key] = b.action
+		}
+	}
+
+	return km, nil
+}
+
+// normalizeKey validates a key name from the config and returns its canonical form
+func normalizeKey(name string) (string, error) {
+	if len([]rune(name)) == 1 {
+		return name, nil
+	}
+
+	lower := strings.ToLower(name)
+	if lower == "space" {
+		return lower, nil
+	}
+	if _, ok := namedKeys[lower]; ok {
+		return lower, nil
+	}
+	if strings.HasPrefix(lower, "ctrl-") && len(lower) == 6 && lower[5] >= 'a' && lower[5] <= 'z' {
+		return lower, nil
+	}
+	return "", fmt.Errorf("unknown key %q", name)
+}
+
+// eventKeyName returns the canonical name of a key event
+func eventKeyName(event *tcell.EventKey) string {
+	if event.Key() == tcell.KeyRune {
+		if event.Rune() == ' ' {
+			return "space"
+		}
+		return string(event.Rune())
+	}
+	if name, ok := keyNames[event.Key()]; ok {
+		return name
+	}
+	if event.Key() >= tcell.KeyCtrlA && event.Key() <= tcell.KeyCtrlZ {
+		return "ctrl-" + string(rune('a'+event.Key()-tcell.KeyCtrlA))
+	}
+	return ""
+}
+
+// Action returns the action bound to the event, or "" if none is
+func (km *KeyMap) Action(event *tcell.EventKey) string {
+	return km.byKey[eventKeyName(event)]
+}
+
+// KeyLabel returns the primary key of an action formatted for display
+func (km *KeyMap) KeyLabel(action string) string {
+	for _, b := range km.bindings {
+		if b.action == action && len(b.keys) > 0 {
+			return displayKey(b.keys[0])
+		}
+	}
+	return "?"
+}
+
+// Description returns the help text of an action
+func (km *KeyMap) Description(action string) string {
+	for _, b := range km.bindings {
+		if b.action == action {
+			return b.description
+		}
+	}
+	return action
+}
+
+// displayKey turns a canonical key name into the label shown in the UI
+func displayKey(key string) string {
+	switch {
+	case key == "space":
+		return "SPACE"
+	case key == "backtab":
+		return "Shift-Tab"
+	case key == "pgup":
+		return "PgUp"
+	case key == "pgdn":
+		return "PgDn"
+	case strings.HasPrefix(key, "ctrl-"):
+		return "Ctrl-" + strings.ToUpper(key[5:])
+	case strings.HasPrefix(key, "f") && len(key) > 1:
+		return strings.ToUpper(key)
+	case len([]rune(key)) > 1:
+		return strings.ToUpper(key[:1]) + key[1:]
+	}
+	return key
+}
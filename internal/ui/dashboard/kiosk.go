@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// kioskBlocked are the actions kiosk mode ignores: everything that changes containers,
+// opens a shell or writes files
+var kioskBlocked = map[string]bool{
+	actionToggle:            true,
+	actionRestart:           true,
+	actionDelete:            true,
+	actionRecreate:          true,
+	actionClone:             true,
+	actionBlueGreen:         true,
+	actionShell:             true,
+	actionAttach:            true,
+	actionExportLogs:        true,
+	actionTruncateLogs:      true,
+	actionSupportBundle:     true,
+	actionLogArchive:        true,
+	actionBulkMode:          true,
+	actionBulkSelect:        true,
+	actionBulkSelectAll:     true,
+	actionBulkInvert:        true,
+	actionBulkSelectRunning: true,
+	actionBulkSelectStopped: true,
+	actionBulkSelectRegex:   true,
+	actionBulkActions:       true,
+	actionTrash:             true,
+	actionCleanup:           true,
+	actionStartProfile:      true,
+	actionPlugins:           true,
+	actionNote:              true,
+	actionReport:            true,
+}
+
+// kioskAllows reports whether action may run; in kiosk mode blocked actions only
+// flash a note in the status bar
+func (d *Dashboard) kioskAllows(action string) bool {
+	if !d.cfg.Kiosk || !kioskBlocked[action] {
+		return true
+	}
+	d.flashStatus(fmt.Sprintf("[%s]%s is disabled in kiosk mode[-]", currentTheme().Warning, d.keys.Description(action)))
+	return false
+}
+
+// kioskSpotlight picks the container kiosk mode shows in detail: the one after the
+// previously shown container among the running ones with stats, by name
+func kioskSpotlight(containers []docker.ContainerInfo, stats map[string]listStats, previous string) (docker.ContainerInfo, int, int, bool) {
+	var running []docker.ContainerInfo
+	for _, c := range containers {
+		if _, ok := stats[c.ID]; ok {
+			running = append(running, c)
+		}
+	}
+	if len(running) == 0 {
+		return docker.ContainerInfo{}, 0, 0, false
+	}
+	sort.Slice(running, func(i, j int) bool { return qualifiedName(running[i]) < qualifiedName(running[j]) })
+
+	next := 0
+	for i, c := range running {
+		if c.ID == previous {
+			next = (i + 1) % len(running)
+			break
+		}
+	}
+	return running[next], next + 1, len(running), true
+}
+
+// renderKioskSpotlight describes one container in large type for the kiosk display
+func renderKioskSpotlight(c docker.ContainerInfo, s listStats) string {
+	t := currentTheme()
+	row := func(label, value, color string) string {
+		return fmt.Sprintf("[%s]%-9s[-] [%s::b]%s[-:-:-]", t.Muted, label, color, tview.Escape(value))
+	}
+
+	stats := s.stats
+	lines := []string{
+		row("Image", c.Image, t.Info) + "    " + row("Status", c.Status, t.Success),
+		row("CPU", fmt.Sprintf("%.1f%%", s.cpu), usageColor(s.cpu)) + "    " +
+			row("Memory", fmt.Sprintf("%.1f%% (%s)", s.mem, stats.MemUsage), usageColor(s.mem)),
+		row("Net I/O", stats.NetIO, t.Info) + "    " + row("Block I/O", stats.BlockIO, t.Info) + "    " + row("PIDs", stats.PIDs, t.Info),
+	}
+	return strings.Join(lines, "\n\n")
+}
@@ -0,0 +1,296 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// noLabelValue is the group used for containers that don't carry the label
+const noLabelValue = "(none)"
+
+// LabelGrouping manages grouping and filtering of the container list by label
+type LabelGrouping struct {
+	groupKey    string
+	filterKey   string
+	filterValue string
+	collapsed   map[string]bool
+	mu          sync.RWMutex
+}
+
+func NewLabelGrouping() *LabelGrouping {
+	return &LabelGrouping{
+		collapsed: make(map[string]bool),
+	}
+}
+
+// SetGroupKey groups the list by the given label key ("" disables grouping)
+func (g *LabelGrouping) SetGroupKey(key string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.groupKey != key {
+		g.collapsed = make(map[string]bool)
+	}
+	g.groupKey = key
+}
+
+func (g *LabelGrouping) GroupKey() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.groupKey
+}
+
+// SetFilter only shows containers whose label key equals value
+func (g *LabelGrouping) SetFilter(key, value string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.filterKey = key
+	g.filterValue = value
+}
+
+func (g *LabelGrouping) Filter() (string, string) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.filterKey, g.filterValue
+}
+
+// Matches reports whether a container passes the active label filter
+func (g *LabelGrouping) Matches(c docker.ContainerInfo) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.filterKey == "" {
+		return true
+	}
+	return labelValue(c, g.filterKey) == g.filterValue
+}
+
+func (g *LabelGrouping) ToggleCollapsed(group string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.collapsed[group] = !g.collapsed[group]
+}
+
+func (g *LabelGrouping) IsCollapsed(group string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.collapsed[group]
+}
+
+// Clear removes both grouping and filtering
+func (g *LabelGrouping) Clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.groupKey = ""
+	g.filterKey = ""
+	g.filterValue = ""
+	g.collapsed = make(map[string]bool)
+}
+
+// labelValue returns the value of a label, or noLabelValue when it is missing
+func labelValue(c docker.ContainerInfo, key string) string {
+	if v, ok := c.Labels[key]; ok {
+		return v
+	}
+	return noLabelValue
+}
+
+// groupContainers splits container indexes into label value groups, sorted by value
+func groupContainers(containers []docker.ContainerInfo, indexes []int, key string) ([]string, map[string][]int) {
+	groups := make(map[string][]int)
+	for _, idx := range indexes {
+		value := labelValue(containers[idx], key)
+		groups[value] = append(groups[value], idx)
+	}
+
+	values := make([]string, 0, len(groups))
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		// Keep unlabeled containers at the bottom
+		if values[i] == noLabelValue || values[j] == noLabelValue {
+			return values[j] == noLabelValue && values[i] != noLabelValue
+		}
+		return values[i] < values[j]
+	})
+
+	return values, groups
+}
+
+// collectLabels returns every label key and, per key, the containers carrying each value
+func collectLabels(containers []docker.ContainerInfo) ([]string, map[string]map[string][]string) {
+	labels := make(map[string]map[string][]string)
+	for _, c := range containers {
+		for key, value := range c.Labels {
+			if labels[key] == nil {
+				labels[key] = make(map[string][]string)
+			}
+			labels[key][value] = append(labels[key][value], c.Name)
+		}
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, labels
+}
+
+// ShowLabelBrowser displays all container labels and lets the user group or filter by them
+func ShowLabelBrowser(app *tview.Application, mainView tview.Primitive, containers []docker.ContainerInfo, grouping *LabelGrouping, onChange func()) {
+	keys, labels := collectLabels(containers)
+	t := currentTheme()
+
+	keyList := tview.NewList().ShowSecondaryText(true)
+	keyList.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🏷️  Label Keys (%d) ", len(keys))).
+		SetBorderColor(tcell.GetColor(t.Accent)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	valueTable := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	valueTable.SetBorder(true).
+		SetTitle(" 📋 Values ").
+		SetBorderColor(tcell.GetColor(t.Warning)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	groupKey := grouping.GroupKey()
+	filterKey, filterValue := grouping.Filter()
+
+	statusBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	status := fmt.Sprintf("[%s:%s] No grouping [-:-:-]", t.Background, t.Muted)
+	if groupKey != "" {
+		status = fmt.Sprintf("[%s:%s] Grouped by: %s [-:-:-]", t.Background, t.Accent, groupKey)
+	}
+	if filterKey != "" {
+		status += fmt.Sprintf(" [%s:%s] Filter: %s=%s [-:-:-]", t.Background, t.Highlight, filterKey, filterValue)
+	}
+	statusBar.SetText(status)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText(fmt.Sprintf(
+		"[-][[%s]Enter[-]] Group by key / Filter by value   "+
+			"[-][[%s]Tab[-]] Switch pane   "+
+			"[-][[%s]c[-]] Clear   "+
+			"[-][[%s]ESC[-]] Back", t.Success, t.Accent, t.Highlight, t.Error))
+
+	currentKey := ""
+	showValues := func(key string) {
+		currentKey = key
+		valueTable.Clear()
+		valueTable.SetTitle(fmt.Sprintf(" 📋 Values: %s ", key))
+
+		headers := []string{"VALUE", "COUNT", "CONTAINERS"}
+		for col, h := range headers {
+			valueTable.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetSelectable(false).
+				SetExpansion(1))
+		}
+
+		values := make([]string, 0, len(labels[key]))
+		for value := range labels[key] {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		for row, value := range values {
+			names := labels[key][value]
+			valueTable.SetCell(row+1, 0, tview.NewTableCell(value).SetTextColor(tview.Styles.PrimaryTextColor))
+			valueTable.SetCell(row+1, 1, tview.NewTableCell(fmt.Sprintf("%d", len(names))).SetTextColor(tcell.GetColor(t.Success)))
+			valueTable.SetCell(row+1, 2, tview.NewTableCell(strings.Join(names, ", ")).SetTextColor(tcell.GetColor(t.Muted)))
+		}
+		valueTable.Select(1, 0)
+	}
+
+	if len(keys) == 0 {
+		keyList.AddItem(fmt.Sprintf("[%s]No labels found[-]", t.Warning), fmt.Sprintf("[%s]None of the containers carry labels[-]", t.Muted), 0, nil)
+	}
+	for _, key := range keys {
+		k := key
+		marker := ""
+		if k == groupKey {
+			marker = fmt.Sprintf("[%s]● [-]", t.Success)
+		}
+		keyList.AddItem(marker+k, fmt.Sprintf("[%s]%d distinct values[-]", t.Muted, len(labels[k])), 0, func() {
+			grouping.SetGroupKey(k)
+			onChange()
+			setRoot(app, mainView)
+		})
+	}
+
+	keyList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if index >= 0 && index < len(keys) {
+			showValues(keys[index])
+		}
+	})
+	if len(keys) > 0 {
+		showValues(keys[0])
+	}
+
+	valueTable.SetSelectedFunc(func(row, column int) {
+		if currentKey == "" || row < 1 {
+			return
+		}
+		value := valueTable.GetCell(row, 0).Text
+		grouping.SetFilter(currentKey, value)
+		onChange()
+		setRoot(app, mainView)
+	})
+
+	panes := tview.NewFlex().
+		AddItem(keyList, 0, 1, true).
+		AddItem(valueTable, 0, 2, false)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(statusBar, 1, 0, false).
+		AddItem(panes, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	handleKeys := func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+			setRoot(app, mainView)
+			return nil
+		case tcell.KeyTab:
+			if keyList.HasFocus() {
+				app.SetFocus(valueTable)
+			} else {
+				app.SetFocus(keyList)
+			}
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'c', 'C':
+			grouping.Clear()
+			onChange()
+			setRoot(app, mainView)
+			return nil
+		case 'q', 'Q':
+			setRoot(app, mainView)
+			return nil
+		}
+		return event
+	}
+	keyList.SetInputCapture(handleKeys)
+	valueTable.SetInputCapture(handleKeys)
+
+	setRoot(app, flex)
+	app.SetFocus(keyList)
+}
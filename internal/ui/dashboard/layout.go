@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"sync/atomic"
+
+	"github.com/rivo/tview"
+)
+
+// Terminal widths at which the containers tab changes its layout
+const (
+	wideLayoutWidth    = 140 // from here the side panel has its full fixed width
+	stackedLayoutWidth = 100 // below this the side panel moves under the list
+	sidePanelWidth     = 65
+)
+
+// layoutMode is how the containers tab arranges the list and the side panel
+type layoutMode int
+
+const (
+	layoutUnset   layoutMode = iota
+	layoutWide               // list and a fixed-width side panel
+	layoutCompact            // list and side panel sharing the width
+	layoutStacked            // side panel under the list, without system info
+)
+
+// layoutFor picks the layout for a terminal width
+func layoutFor(width int) layoutMode {
+	switch {
+	case width >= wideLayoutWidth:
+		return layoutWide
+	case width >= stackedLayoutWidth:
+		return layoutCompact
+	}
+	return layoutStacked
+}
+
+// adaptLayout rearranges the containers tab when the terminal crossed a layout width.
+// It runs before every draw, so it only touches the layout when the mode changes.
+func (d *Dashboard) adaptLayout(width int) {
+	mode := layoutFor(width)
+	if mode == d.layout {
+		return
+	}
+	d.layout = mode
+
+	d.containersView.Clear()
+	d.sidePanel.Clear()
+	switch mode {
+	case layoutWide, layoutCompact:
+		d.sidePanel.SetDirection(tview.FlexRow).
+			AddItem(d.detailsText, 0, 1, false).
+			AddItem(d.statsText, 14, 0, false).
+			AddItem(d.systemInfo, 10, 0, false)
+		d.containersView.SetDirection(tview.FlexColumn).
+			AddItem(d.list, 0, 2, true)
+		if mode == layoutWide {
+			d.containersView.AddItem(d.sidePanel, sidePanelWidth, 0, false)
+		} else {
+			d.containersView.AddItem(d.sidePanel, 0, 1, false)
+		}
+	case layoutStacked:
+		// Details and stats side by side under the list; system info is on the System tab
+		d.sidePanel.SetDirection(tview.FlexColumn).
+			AddItem(d.detailsText, 0, 1, false).
+			AddItem(d.statsText, 0, 1, false)
+		d.containersView.SetDirection(tview.FlexRow).
+			AddItem(d.list, 0, 1, true).
+			AddItem(d.sidePanel, 14, 0, false)
+	}
+}
+
+// fitWidth sizes a graph to the space available, between lo and hi columns
+func fitWidth(available, lo, hi int) int {
+	return max(lo, min(available, hi))
+}
+
+// panelWidth remembers the inner width of a panel as of its last update, so text for it
+// can be laid out off the UI goroutine; it is 0 until the panel was drawn
+type panelWidth struct {
+	width atomic.Int32
+}
+
+// update records the width of box; it must be called on the UI goroutine
+func (p *panelWidth) update(box interface{ GetInnerRect() (int, int, int, int) }) {
+	_, _, width, _ := box.GetInnerRect()
+	p.width.Store(int32(width))
+}
+
+// fit sizes a graph to the panel width minus the columns taken by labels, falling back
+// to hi before the first draw
+func (p *panelWidth) fit(labels, lo, hi int) int {
+	width := int(p.width.Load())
+	if width == 0 {
+		return hi
+	}
+	return fitWidth(width-labels, lo, hi)
+}
@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+// listSizesRefresh is how often container sizes are read again; the daemon has to walk
+// every writable layer for them, so far less often than the list itself
+const listSizesRefresh = time.Minute
+
+// SizeCache keeps the latest disk sizes of the listed containers
+type SizeCache struct {
+	mu    sync.RWMutex
+	sizes map[string]docker.ContainerSize
+}
+
+func NewSizeCache() *SizeCache {
+	return &SizeCache{sizes: make(map[string]docker.ContainerSize)}
+}
+
+// Replace swaps in the sizes read from the daemons
+func (s *SizeCache) Replace(sizes map[string]docker.ContainerSize) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sizes = sizes
+}
+
+// Get returns the size of a container
+func (s *SizeCache) Get(id string) (docker.ContainerSize, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	size, ok := s.sizes[id]
+	return size, ok
+}
+
+// refreshSizes reads the sizes of the containers on the listed hosts; hosts that fail
+// keep no sizes until the next round
+func (d *Dashboard) refreshSizes() {
+	endpoints := []docker.Endpoint{docker.CurrentEndpoint()}
+	if d.allHostsMode() {
+		endpoints = d.hostEndpoints()
+	}
+
+	sizes := make(map[string]docker.ContainerSize)
+	for _, e := range endpoints {
+		found, err := docker.ListContainerSizesOn(e)
+		if err != nil {
+			continue
+		}
+		for id, size := range found {
+			sizes[id] = size
+		}
+	}
+	d.sizes.Replace(sizes)
+}
+
+// startSizesWorker keeps the size column current
+func (d *Dashboard) startSizesWorker() {
+	go func() {
+		ticker := time.NewTicker(d.pace(listSizesRefresh, lowPowerSizes))
+		defer ticker.Stop()
+
+		for {
+			d.refreshSizes()
+			d.app.QueueUpdateDraw(func() {
+				if d.app.GetFocus() != d.list {
+					return
+				}
+				d.mu.RLock()
+				containers := d.containers
+				d.mu.RUnlock()
+				d.renderList(containers)
+			})
+
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sizeColumn renders the writable layer size of a list row
+func (d *Dashboard) sizeColumn(container docker.ContainerInfo) string {
+	t := currentTheme()
+	size, ok := d.sizes.Get(container.ID)
+	if !ok {
+		return fmt.Sprintf("[%s]%10s[-]", t.Muted, "-")
+	}
+	return fmt.Sprintf("[%s]%10s[-]", sizeColor(size.RW), docker.FormatBytes(uint64(size.RW)))
+}
+
+// sizeColor flags writable layers that grow large, usually logs or caches written
+// inside the container instead of to a volume
+func sizeColor(bytes int64) string {
+	t := currentTheme()
+	switch {
+	case bytes >= 5<<30:
+		return t.Error
+	case bytes >= 1<<30:
+		return t.Warning
+	}
+	return t.Muted
+}
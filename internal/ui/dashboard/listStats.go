@@ -0,0 +1,231 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+// listStatsRedraw is how often the list columns pick up new samples
+const listStatsRedraw = 2 * time.Second
+
+// listStats holds the latest CPU and memory usage of a container and the sample they
+// were read from
+type listStats struct {
+	cpu   float64
+	mem   float64
+	stats *docker.ContainerStats
+}
+
+// StatsCollector keeps one stats stream open per running container, like docker stats,
+// so the container list can show CPU and memory for all of them
+type StatsCollector struct {
+	ctx     context.Context
+	mu      sync.RWMutex
+	streams map[string]context.CancelFunc
+	latest  map[string]listStats
+}
+
+func NewStatsCollector(ctx context.Context) *StatsCollector {
+	return &StatsCollector{
+		ctx:     ctx,
+		streams: make(map[string]context.CancelFunc),
+		latest:  make(map[string]listStats),
+	}
+}
+
+// Track makes the running containers the monitored set: streams start for new ones and
+// stop for containers that stopped or disappeared
+func (c *StatsCollector) Track(containers []docker.ContainerInfo) {
+	running := make(map[string]bool)
+	for _, container := range containers {
+		// Rootless daemons on cgroup v1 have no stats to stream
+		if p, ok := docker.ProfileOf(container.ID); ok && !p.HasStats() {
+			continue
+		}
+		if container.State == "running" {
+			running[container.ID] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, stop := range c.streams {
+		if !running[id] {
+			stop()
+			delete(c.streams, id)
+			delete(c.latest, id)
+		}
+	}
+	for id := range running {
+		if _, ok := c.streams[id]; !ok {
+			ctx, stop := context.WithCancel(c.ctx)
+			c.streams[id] = stop
+			go c.follow(ctx, id)
+		}
+	}
+}
+
+// follow records the samples of one container until its stream ends; the next Track
+// restarts it if the container is still running
+func (c *StatsCollector) follow(ctx context.Context, id string) {
+	samples, _ := docker.StreamStats(ctx, id)
+	for stats := range samples {
+		s := listStats{stats: stats}
+		fmt.Sscanf(stats.CPUPerc, "%f%%", &s.cpu)
+		fmt.Sscanf(stats.MemPerc, "%f%%", &s.mem)
+		c.mu.Lock()
+		c.latest[id] = s
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ctx.Err() == nil {
+		delete(c.streams, id)
+		delete(c.latest, id)
+	}
+}
+
+// Get returns the latest usage of a container
+func (c *StatsCollector) Get(id string) (listStats, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.latest[id]
+	return s, ok
+}
+
+// Snapshot returns the latest usage of every monitored container
+func (c *StatsCollector) Snapshot() map[string]listStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot := make(map[string]listStats, len(c.latest))
+	for id, s := range c.latest {
+		snapshot[id] = s
+	}
+	return snapshot
+}
+
+// startListStatsWorker redraws the list every few seconds so the CPU and memory columns
+// stay current without asking the daemon for the list again
+func (d *Dashboard) startListStatsWorker() {
+	go func() {
+		ticker := time.NewTicker(listStatsRedraw)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+				d.app.QueueUpdateDraw(func() {
+					// Leave the list alone while a group header or bulk selection is being changed
+					if d.app.GetFocus() != d.list {
+						return
+					}
+					d.mu.RLock()
+					containers := d.containers
+					d.mu.RUnlock()
+					d.renderList(containers)
+				})
+			}
+		}
+	}()
+}
+
+// listSort is the order of the container list
+type listSort int
+
+const (
+	sortDaemon listSort = iota // as the daemon lists them, newest first
+	sortName
+	sortCPU
+	sortMem
+	sortSize
+)
+
+var listSortNames = []string{"created", "name", "CPU", "memory", "size"}
+
+func (s listSort) String() string {
+	return listSortNames[s]
+}
+
+// cycleSort switches to the next list order
+func (d *Dashboard) cycleSort() {
+	d.mu.Lock()
+	d.listSort = (d.listSort + 1) % listSort(len(listSortNames))
+	order := d.listSort
+	d.mu.Unlock()
+
+	d.updateListTitle()
+	d.updateList()
+	d.flashStatus(fmt.Sprintf("[%s]Sorted by %s[-]", currentTheme().Info, order))
+}
+
+// sortVisible orders container indexes by the current sort; containers without stats
+// sort last for CPU and memory, and without a size for size
+func (d *Dashboard) sortVisible(containers []docker.ContainerInfo, visible []int) {
+	d.mu.RLock()
+	order := d.listSort
+	d.mu.RUnlock()
+
+	usage := func(idx int) float64 {
+		s, ok := d.statsCollector.Get(containers[idx].ID)
+		if !ok {
+			return -1
+		}
+		if order == sortMem {
+			return s.mem
+		}
+		return s.cpu
+	}
+
+	switch order {
+	case sortName:
+		sort.SliceStable(visible, func(i, j int) bool {
+			return strings.ToLower(containers[visible[i]].Name) < strings.ToLower(containers[visible[j]].Name)
+		})
+	case sortCPU, sortMem:
+		sort.SliceStable(visible, func(i, j int) bool {
+			return usage(visible[i]) > usage(visible[j])
+		})
+	case sortSize:
+		size := func(idx int) int64 {
+			s, ok := d.sizes.Get(containers[idx].ID)
+			if !ok {
+				return -1
+			}
+			return s.RW
+		}
+		sort.SliceStable(visible, func(i, j int) bool {
+			return size(visible[i]) > size(visible[j])
+		})
+	}
+}
+
+// statsColumns renders the CPU and memory columns of a list row
+func (d *Dashboard) statsColumns(container docker.ContainerInfo) string {
+	t := currentTheme()
+	s, ok := d.statsCollector.Get(container.ID)
+	if !ok {
+		return fmt.Sprintf("[%s]%6s %6s[-]", t.Muted, "-", "-")
+	}
+	return fmt.Sprintf("[%s]%5.1f%%[-] [%s]%5.1f%%[-]", usageColor(s.cpu), s.cpu, usageColor(s.mem), s.mem)
+}
+
+// usageColor picks the theme color for a usage percentage
+func usageColor(perc float64) string {
+	t := currentTheme()
+	switch {
+	case perc > 80:
+		return t.Error
+	case perc > 50:
+		return t.Warning
+	}
+	return t.Muted
+}
@@ -0,0 +1,265 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/logarchive"
+)
+
+const (
+	archiveAutoStart = 10 * time.Second // between checks for configured containers
+	archiveRedraw    = time.Second
+)
+
+// newArchiver creates the log archiver with the settings of the log_archive section
+func newArchiver(ctx context.Context, cfg *config.Config) *logarchive.Archiver {
+	a := cfg.LogArchive
+	dir := logarchive.DefaultDir()
+	if a != nil && a.Dir != "" {
+		dir = a.Dir
+	}
+	return logarchive.New(ctx, logarchive.Options{
+		Dir:     dir,
+		MaxSize: a.RotateSize(),
+		MaxAge:  a.RotateAge(),
+		Keep:    a.KeepFiles(),
+	})
+}
+
+// archiveName names the archive of a container after its host and name, so archives
+// stay the same in the all hosts view and survive the container being recreated
+func archiveName(c docker.ContainerInfo) string {
+	host := c.Host
+	if host == "" {
+		host = docker.CurrentEndpoint().Name
+	}
+	if host == "" {
+		return c.Name
+	}
+	return host + "/" + c.Name
+}
+
+// startLogArchiver starts archiving the containers listed in log_archive.containers as
+// they show up in the list, including replacements created under the same name
+func (d *Dashboard) startLogArchiver() {
+	if d.cfg.LogArchive == nil || len(d.cfg.LogArchive.Containers) == 0 {
+		return
+	}
+	wanted := make(map[string]bool)
+	for _, name := range d.cfg.LogArchive.Containers {
+		wanted[strings.TrimPrefix(name, "/")] = true
+	}
+
+	go func() {
+		ticker := time.NewTicker(archiveAutoStart)
+		defer ticker.Stop()
+
+		for {
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+			for _, c := range containers {
+				if wanted[c.Name] || wanted[qualifiedName(c)] {
+					d.archiver.Start(c.ID, archiveName(c))
+				}
+			}
+
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// archiveRow is a line of the archive screen: a listed container, an archive whose
+// container is no longer listed, or both
+type archiveRow struct {
+	name      string
+	container *docker.ContainerInfo
+	status    *logarchive.Status
+}
+
+// archiveRows joins the container list with the running archives
+func (d *Dashboard) archiveRows() []archiveRow {
+	d.mu.RLock()
+	containers := d.containers
+	d.mu.RUnlock()
+
+	all := d.archiver.Statuses()
+	statuses := make(map[string]logarchive.Status, len(all))
+	for _, s := range all {
+		statuses[s.Name] = s
+	}
+
+	var rows []archiveRow
+	for i := range containers {
+		c := containers[i]
+		row := archiveRow{name: archiveName(c), container: &c}
+		if s, ok := statuses[logarchive.FileName(row.name)]; ok {
+			row.status = &s
+			delete(statuses, s.Name)
+		}
+		rows = append(rows, row)
+	}
+	for _, s := range all {
+		if _, ok := statuses[s.Name]; ok {
+			s := s
+			rows = append(rows, archiveRow{name: s.Name, status: &s})
+		}
+	}
+	return rows
+}
+
+// showLogArchive lists containers with the state of their log archive; Enter starts or
+// stops archiving the selected one
+func (d *Dashboard) showLogArchive() {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 🗄 Log Archive ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.ColorDodgerBlue)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText(fmt.Sprintf("[gray]%s[-]   [yellow]Enter/space[-] Start / stop   [yellow]ESC/q[-] Back",
+		tview.Escape(d.archiver.Dir())))
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var rows []archiveRow
+	render := func() {
+		rows = d.archiveRows()
+		table.Clear()
+		for col, h := range []string{"Container", "State", "Archive", "Lines", "Rotated", "Last line"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.ColorYellow).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false).
+				SetExpansion(1))
+		}
+		for i, r := range rows {
+			cells := archiveCells(r)
+			for col, text := range cells {
+				table.SetCell(i+1, col, tview.NewTableCell(text))
+			}
+		}
+		if len(rows) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("(no containers)").SetTextColor(tcell.ColorGray))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	back := func() {
+		cancel()
+		setRoot(d.app, d.mainFlex)
+	}
+
+	toggle := func() {
+		i, _ := table.GetSelection()
+		if i < 1 || i > len(rows) {
+			return
+		}
+		r := rows[i-1]
+		switch {
+		case r.status != nil:
+			d.archiver.Stop(r.status.Name)
+		case r.container != nil:
+			d.archiver.Start(r.container.ID, r.name)
+		}
+		render()
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+			back()
+			return nil
+		case tcell.KeyEnter:
+			toggle()
+			return nil
+		}
+		switch event.Rune() {
+		case 'q', 'Q':
+			back()
+			return nil
+		case ' ':
+			toggle()
+			return nil
+		}
+		return event
+	})
+
+	go func() {
+		ticker := time.NewTicker(archiveRedraw)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.app.QueueUpdateDraw(render)
+			}
+		}
+	}()
+
+	render()
+	table.Select(1, 0)
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
+
+// archiveCells renders the columns of an archive screen row
+func archiveCells(r archiveRow) []string {
+	t := currentTheme()
+	name := tview.Escape(r.name)
+	if r.container == nil {
+		name += fmt.Sprintf(" [%s](gone)[-]", t.Muted)
+	}
+	if r.status == nil {
+		return []string{name, fmt.Sprintf("[%s]○ off[-]", t.Muted), "", "", "", ""}
+	}
+
+	s := r.status
+	var state string
+	switch s.State {
+	case logarchive.StateArchiving:
+		state = fmt.Sprintf("[%s]● archiving[-]", t.Success)
+	case logarchive.StateWaiting:
+		state = fmt.Sprintf("[%s]◌ waiting[-]", t.Warning)
+		if s.Err != nil {
+			state += fmt.Sprintf(" [%s]%s[-]", t.Muted, tview.Escape(errorSummary(s.Err)))
+		}
+	default:
+		state = fmt.Sprintf("[%s]✖ %s[-]", t.Error, tview.Escape(errorSummary(s.Err)))
+	}
+
+	last := ""
+	if !s.LastLine.IsZero() {
+		last = s.LastLine.Local().Format("Jan 2 15:04:05")
+	}
+	return []string{
+		name,
+		state,
+		fmt.Sprintf("%s.log %s", tview.Escape(s.Name), docker.FormatBytes(uint64(s.Size))),
+		fmt.Sprint(s.Lines),
+		fmt.Sprint(s.Rotations),
+		last,
+	}
+}
@@ -0,0 +1,133 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// bookmarkMarker prefixes bookmarked lines in the log views
+const bookmarkMarker = "[aqua::b]●[-::-] "
+
+// parseJumpTime reads the time to jump to in a log: a full timestamp, a time of day (today,
+// or yesterday if that is still ahead) or a duration back from now like -15m
+func parseJumpTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		if d, err := time.ParseDuration(rest); err == nil {
+			return now.Add(-d), nil
+		}
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+			if t.After(now) {
+				t = t.AddDate(0, 0, -1)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown time %q, use 15:04[:05], 2006-01-02 15:04[:05] or -15m", s)
+}
+
+// firstLineAt returns which of n lines has the earliest timestamp at or after t, or -1.
+// Lines without a Docker timestamp are skipped.
+func firstLineAt(n int, text func(int) string, t time.Time) int {
+	found := -1
+	var foundAt time.Time
+	for i := 0; i < n; i++ {
+		at, _, ok := docker.SplitLogLine(text(i))
+		if !ok || at.Before(t) {
+			continue
+		}
+		if found < 0 || at.Before(foundAt) {
+			found, foundAt = i, at
+		}
+	}
+	return found
+}
+
+// bookmarkLabel is how a bookmarked line is listed: its local time and message
+func bookmarkLabel(text string) string {
+	at, msg, ok := docker.SplitLogLine(text)
+	if !ok {
+		return truncateString(text, 90)
+	}
+	return at.Local().Format("2006-01-02 15:04:05") + "  " + truncateString(msg, 70)
+}
+
+// showBookmarks lists bookmarked lines on top of view. Enter jumps to one, d removes one;
+// both report the position in labels. done restores the view.
+func showBookmarks(app *tview.Application, view tview.Primitive, labels []string, jump, remove func(int), done func()) {
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, label := range labels {
+		list.AddItem(tview.Escape(label), "", 0, nil)
+	}
+	list.SetBorder(true).
+		SetTitle(" 🔖 Bookmarks (Enter jump, d remove, ESC close) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		done()
+		jump(i)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			done()
+			return nil
+		case event.Rune() == 'd':
+			i := list.GetCurrentItem()
+			list.RemoveItem(i)
+			remove(i)
+			if list.GetItemCount() == 0 {
+				done()
+			}
+			return nil
+		}
+		return event
+	})
+
+	showOverlay(app, view, list, 100, min(len(labels)+2, 20))
+}
+
+// promptJumpTime asks for a time on top of view and calls jump with it; done restores the view
+func promptJumpTime(app *tview.Application, view tview.Primitive, jump func(time.Time), done func()) {
+	t := currentTheme()
+	input := tview.NewInputField().
+		SetLabel("⏱ ").
+		SetPlaceholder("15:04, 15:04:05, 2006-01-02 15:04 or -15m").
+		SetFieldWidth(0)
+	input.SetBorder(true).
+		SetTitle(" Jump to time (ESC to cancel) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			done()
+		case tcell.KeyEnter:
+			at, err := parseJumpTime(input.GetText(), time.Now())
+			if err != nil {
+				input.SetTitle(fmt.Sprintf(" [%s]%s[-] ", t.Error, tview.Escape(err.Error())))
+				return
+			}
+			done()
+			jump(at)
+		}
+	})
+
+	showOverlay(app, view, input, 70, 3)
+}
@@ -0,0 +1,54 @@
+package dashboard
+
+// advancedLogLines is how many lines the advanced log viewer keeps; older lines are
+// dropped as new ones arrive
+const advancedLogLines = 10000
+
+// logLine is a log line parsed and classified once when it arrives
+type logLine struct {
+	seq     uint64 // position in the stream, used as the line's region in the view
+	raw     string
+	json    *jsonLine // nil unless the line is a JSON object
+	level   string    // ERROR, WARN, INFO, DEBUG or ""
+	stderr  bool
+	matched bool // matched the search when it was last filtered
+}
+
+// logBuffer is a ring of the most recent lines of a log stream
+type logBuffer struct {
+	lines []*logLine
+	start int // index of the oldest line
+	count int
+	next  uint64
+}
+
+func newLogBuffer(capacity int) *logBuffer {
+	return &logBuffer{lines: make([]*logLine, capacity)}
+}
+
+// add appends a line, numbering it, and returns the line it pushed out, if any
+func (b *logBuffer) add(l *logLine) (evicted *logLine) {
+	l.seq = b.next
+	b.next++
+	if b.count < len(b.lines) {
+		b.lines[(b.start+b.count)%len(b.lines)] = l
+		b.count++
+		return nil
+	}
+	evicted = b.lines[b.start]
+	b.lines[b.start] = l
+	b.start = (b.start + 1) % len(b.lines)
+	return evicted
+}
+
+// len returns the number of lines held
+func (b *logBuffer) len() int {
+	return b.count
+}
+
+// each calls fn for every line, oldest first
+func (b *logBuffer) each(fn func(*logLine)) {
+	for i := 0; i < b.count; i++ {
+		fn(b.lines[(b.start+i)%len(b.lines)])
+	}
+}
@@ -0,0 +1,148 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// logFileRefresh is how long the log file size in the details panel is reused before
+// it is read again
+const logFileRefresh = 30 * time.Second
+
+// LogFileCache keeps the log driver and log file size of containers shown in the
+// details panel, read in the background
+type LogFileCache struct {
+	mu      sync.Mutex
+	files   map[string]logFileEntry
+	loading map[string]bool
+}
+
+type logFileEntry struct {
+	file docker.LogFile
+	read time.Time
+}
+
+func NewLogFileCache() *LogFileCache {
+	return &LogFileCache{files: make(map[string]logFileEntry), loading: make(map[string]bool)}
+}
+
+// Get returns what was last read for a container, starting a read when that is missing
+// or older than logFileRefresh
+func (c *LogFileCache) Get(id string) (docker.LogFile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.files[id]
+	if (!ok || time.Since(entry.read) > logFileRefresh) && !c.loading[id] {
+		c.loading[id] = true
+		go c.load(id)
+	}
+	return entry.file, ok
+}
+
+// Forget drops what was read for a container, e.g. after its log was truncated
+func (c *LogFileCache) Forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.files, id)
+}
+
+func (c *LogFileCache) load(id string) {
+	file, err := docker.ContainerLogFile(id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.loading, id)
+	if err == nil {
+		c.files[id] = logFileEntry{file: file, read: time.Now()}
+	}
+}
+
+// logDetails describes a container's log driver and log file size for the details panel
+func (d *Dashboard) logDetails(container docker.ContainerInfo) string {
+	file, ok := d.logFiles.Get(container.ID)
+	if !ok {
+		return ""
+	}
+	t := currentTheme()
+	details := fmt.Sprintf("\n\n[%s::b]Logs:[-:-:-]\n%s", t.Accent, file.Driver)
+	switch {
+	case file.Err != nil:
+		details += fmt.Sprintf(", [%s]size unknown: %s[-]", t.Muted, file.Err)
+	case file.Files > 1:
+		details += fmt.Sprintf(", [%s]%s[-] in %d files", sizeColor(file.Size), docker.FormatBytes(uint64(file.Size)), file.Files)
+	case file.Files == 1:
+		details += fmt.Sprintf(", [%s]%s[-]", sizeColor(file.Size), docker.FormatBytes(uint64(file.Size)))
+	}
+
+	var opts []string
+	for key, value := range file.Options {
+		opts = append(opts, key+"="+value)
+	}
+	sort.Strings(opts)
+	if len(opts) > 0 {
+		details += fmt.Sprintf("\n[%s]%s[-]", t.Muted, strings.Join(opts, " "))
+	}
+	if src := docker.DescribeLogSource(file.Driver, file.Options, container.Name); !src.Readable || src.Cached {
+		details += fmt.Sprintf("\n[%s]%s[-]", t.Warning, tview.Escape(src.Hint))
+	}
+	if file.Driver == "json-file" && file.Options["max-size"] == "" {
+		details += fmt.Sprintf("\n[%s]No max-size: grows until the disk is full[-]", t.Warning)
+	}
+	return details
+}
+
+// truncateLogs empties the json-file log of a container after a confirmation
+func (d *Dashboard) truncateLogs(container docker.ContainerInfo) {
+	message := fmt.Sprintf("Truncate the log file of '%s'?\n\nIts current log is deleted for good, also for docker logs; rotated files are kept. The container keeps running and logging.",
+		container.Name)
+	showConfirmation(d.app, d.mainFlex, message, func() {
+		d.flashStatus(fmt.Sprintf("[%s]⏳ Truncating the log of %s...[-]", currentTheme().Warning, container.Name))
+		go func() {
+			freed, err := docker.TruncateLogFile(container.ID)
+			d.logFiles.Forget(container.ID)
+			d.app.QueueUpdateDraw(func() {
+				if err != nil {
+					showError(d.app, d.mainFlex, "❌ Truncate Failed", err)
+					return
+				}
+				if freed >= 0 {
+					showToast(d.app, toastSuccess, fmt.Sprintf("Truncated the log of %s, %s freed", container.Name, docker.FormatBytes(uint64(freed))))
+				} else {
+					showToast(d.app, toastSuccess, fmt.Sprintf("Truncated the log of %s", container.Name))
+				}
+			})
+		}()
+	})
+}
+
+// logTitle is the title of a logs view, naming the container's log driver once it is known
+func logTitle(view, containerName string, src docker.LogSource) string {
+	title := fmt.Sprintf(" 📜 %s: %s ", view, containerName)
+	switch {
+	case src.Driver == "":
+	case src.Cached:
+		title += fmt.Sprintf("[%s](%s, local copy)[-] ", currentTheme().Warning, src.Driver)
+	case !src.Readable:
+		title += fmt.Sprintf("[%s](%s)[-] ", currentTheme().Error, src.Driver)
+	default:
+		title += fmt.Sprintf("[%s](%s)[-] ", currentTheme().Muted, src.Driver)
+	}
+	return title
+}
+
+// logSourceNote explains where the logs of a driver docker logs can't read go instead,
+// with the command that reads them there
+func logSourceNote(src docker.LogSource) string {
+	t := currentTheme()
+	note := fmt.Sprintf("[%s::b]Log driver: %s[-:-:-]\n[%s]%s[-]", t.Accent, src.Driver, t.Warning, tview.Escape(src.Hint))
+	if src.Command != "" {
+		note += fmt.Sprintf("\n\nRead them with:\n[%s]%s[-]", t.Info, tview.Escape(src.Command))
+	}
+	return note
+}
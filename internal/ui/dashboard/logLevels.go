@@ -0,0 +1,126 @@
+package dashboard
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"devops-dashboard/internal/config"
+)
+
+// logLevelNames are the levels a line can have, most severe first; the first level
+// whose pattern matches wins
+var logLevelNames = [...]string{"ERROR", "WARN", "INFO", "DEBUG"}
+
+// defaultLevelPatterns match level words as whole words, so "err" matches "err:" but not
+// "transferred" or "stderr"
+var defaultLevelPatterns = config.LevelPatterns{
+	Error: `(?i)\b(err(or)?s?|fatal|panic|crit(ical)?|emerg(ency)?|exception)\b`,
+	Warn:  `(?i)\bwarn(ings?)?\b`,
+	Info:  `(?i)\b(info|notice)\b`,
+	Debug: `(?i)\b(debug|trace)\b`,
+}
+
+// levelMatcher classifies log lines with compiled patterns, one per level
+type levelMatcher struct {
+	patterns [len(logLevelNames)]*regexp.Regexp
+}
+
+// level returns ERROR, WARN, INFO or DEBUG, or "" when no pattern matches
+func (m *levelMatcher) level(line string) string {
+	for i, re := range m.patterns {
+		if re.MatchString(line) {
+			return logLevelNames[i]
+		}
+	}
+	return ""
+}
+
+// levelRules are the configured patterns and the matchers compiled from them per image
+type levelRules struct {
+	global config.LevelPatterns
+	images map[string]config.LevelPatterns
+	globs  []string // image globs, most specific first
+
+	mu       sync.Mutex
+	matchers map[string]*levelMatcher // by image
+}
+
+var logLevelRules = newLevelRules(nil)
+
+func newLevelRules(cfg *config.LogLevels) *levelRules {
+	r := &levelRules{global: defaultLevelPatterns, matchers: make(map[string]*levelMatcher)}
+	if cfg == nil {
+		return r
+	}
+	r.global = mergeLevelPatterns(r.global, cfg.LevelPatterns)
+	r.images = cfg.Images
+	for glob := range cfg.Images {
+		r.globs = append(r.globs, glob)
+	}
+	sort.Slice(r.globs, func(i, j int) bool {
+		if len(r.globs[i]) != len(r.globs[j]) {
+			return len(r.globs[i]) > len(r.globs[j])
+		}
+		return r.globs[i] < r.globs[j]
+	})
+	return r
+}
+
+// setLogLevels installs the level patterns of the config
+func setLogLevels(cfg *config.Config) {
+	logLevelRules = newLevelRules(cfg.LogLevels)
+}
+
+// levelMatcherFor returns the matcher for containers running image, compiling it on
+// first use. The config was validated, so the patterns compile.
+func levelMatcherFor(image string) *levelMatcher {
+	r := logLevelRules
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.matchers[image]; ok {
+		return m
+	}
+
+	patterns := r.global
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	for _, glob := range r.globs {
+		if ok, _ := path.Match(glob, image); ok {
+			patterns = mergeLevelPatterns(patterns, r.images[glob])
+			break
+		}
+		if ok, _ := path.Match(glob, repo); ok {
+			patterns = mergeLevelPatterns(patterns, r.images[glob])
+			break
+		}
+	}
+
+	m := &levelMatcher{}
+	for i, p := range []string{patterns.Error, patterns.Warn, patterns.Info, patterns.Debug} {
+		m.patterns[i] = regexp.MustCompile(p)
+	}
+	r.matchers[image] = m
+	return m
+}
+
+// mergeLevelPatterns overrides the patterns of base that are set in override
+func mergeLevelPatterns(base, override config.LevelPatterns) config.LevelPatterns {
+	if override.Error != "" {
+		base.Error = override.Error
+	}
+	if override.Warn != "" {
+		base.Warn = override.Warn
+	}
+	if override.Info != "" {
+		base.Info = override.Info
+	}
+	if override.Debug != "" {
+		base.Debug = override.Debug
+	}
+	return base
+}
@@ -0,0 +1,68 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/logship"
+)
+
+// shipSyncInterval is how often the shipped containers are matched against the rules
+const shipSyncInterval = 10 * time.Second
+
+// startLogShipping ships the logs of the containers selected by the log_shipping rules
+// to their targets, picking up new and recreated containers as they are listed
+func (d *Dashboard) startLogShipping() {
+	cfg := d.cfg.LogShipping
+	if cfg == nil || len(cfg.Rules) == 0 {
+		return
+	}
+
+	targets := make([]logship.Target, len(cfg.Targets))
+	for i, t := range cfg.Targets {
+		targets[i] = logship.NewTarget(t)
+	}
+	pipeline := logship.NewPipeline(d.refreshCtx, targets, func(target string, err error) {
+		var msg string
+		if err != nil {
+			msg = fmt.Sprintf("[%s]Log shipping to %s failed: %s[-]", currentTheme().Error, tview.Escape(target), tview.Escape(errorSummary(err)))
+		} else {
+			msg = fmt.Sprintf("[%s]Log shipping to %s resumed[-]", currentTheme().Info, tview.Escape(target))
+		}
+		d.app.QueueUpdateDraw(func() {
+			d.flashStatus(msg)
+		})
+	})
+
+	go func() {
+		ticker := time.NewTicker(shipSyncInterval)
+		defer ticker.Stop()
+
+		for {
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+
+			sources := make([]logship.Source, 0, len(containers))
+			for _, c := range containers {
+				sources = append(sources, logship.Source{
+					ID:      c.ID,
+					Name:    strings.TrimPrefix(c.Name, "/"),
+					Image:   c.Image,
+					Host:    hostLabel(c),
+					Targets: cfg.TargetsFor(c.Name, c.Labels),
+				})
+			}
+			pipeline.Sync(sources)
+
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
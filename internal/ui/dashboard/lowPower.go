@@ -0,0 +1,18 @@
+package dashboard
+
+import "time"
+
+// Low-power mode stretches these so the dashboard itself stays idle on small hosts
+const (
+	lowPowerRefresh = 15 * time.Second // container list
+	lowPowerStats   = 5 * time.Second  // side panel samples of the selected container
+	lowPowerSizes   = 5 * time.Minute  // container sizes
+)
+
+// pace returns the interval of a worker: normal, or lowPower in low-power mode
+func (d *Dashboard) pace(normal, lowPower time.Duration) time.Duration {
+	if d.cfg.LowPower {
+		return lowPower
+	}
+	return normal
+}
@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/metrics"
+)
+
+// startMetricsExport pushes the usage of all running containers to the configured
+// InfluxDB or Prometheus remote-write endpoints while the dashboard runs
+func (d *Dashboard) startMetricsExport() {
+	if d.cfg.Export == nil {
+		return
+	}
+	sinks := metrics.NewSinks(d.cfg.Export)
+	interval := d.cfg.Export.PushInterval()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// Only changes are reported so a backend that is down doesn't flash every push
+		failing := make(map[string]bool)
+		for {
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case now := <-ticker.C:
+				points := d.metricPoints(now)
+				if len(points) == 0 {
+					continue
+				}
+				for i, err := range pushAll(d.refreshCtx, sinks, points) {
+					d.reportExport(sinks[i].Name(), err, failing)
+				}
+			}
+		}
+	}()
+}
+
+// pushAll sends points to every sink at once and returns their errors in sink order
+func pushAll(ctx context.Context, sinks []metrics.Sink, points []metrics.Point) []error {
+	errs := make([]error, len(sinks))
+	var wg sync.WaitGroup
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func(i int, sink metrics.Sink) {
+			defer wg.Done()
+			errs[i] = sink.Push(ctx, points)
+		}(i, sink)
+	}
+	wg.Wait()
+	return errs
+}
+
+// reportExport flashes the status bar when a sink starts failing or recovers
+func (d *Dashboard) reportExport(name string, err error, failing map[string]bool) {
+	if (err != nil) == failing[name] || d.refreshCtx.Err() != nil {
+		return
+	}
+	failing[name] = err != nil
+
+	var msg string
+	if err != nil {
+		msg = fmt.Sprintf("[%s]Metrics export to %s failed: %s[-]", currentTheme().Error, name, tview.Escape(errorSummary(err)))
+	} else {
+		msg = fmt.Sprintf("[%s]Metrics export to %s resumed[-]", currentTheme().Info, name)
+	}
+	d.app.QueueUpdateDraw(func() {
+		d.flashStatus(msg)
+	})
+}
+
+// metricPoints turns the latest sample of every monitored container into export points
+func (d *Dashboard) metricPoints(now time.Time) []metrics.Point {
+	latest := d.statsCollector.Snapshot()
+	if len(latest) == 0 {
+		return nil
+	}
+
+	d.mu.RLock()
+	containers := d.containers
+	d.mu.RUnlock()
+
+	var points []metrics.Point
+	for _, c := range containers {
+		s, ok := latest[c.ID]
+		if !ok || s.stats == nil {
+			continue
+		}
+		points = append(points, metrics.Point{
+			Time:       now,
+			ID:         c.ID[:min(12, len(c.ID))],
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			Image:      c.Image,
+			Host:       hostLabel(c),
+			CPUPerc:    s.cpu,
+			MemPerc:    s.mem,
+			MemUsage:   s.stats.MemUsed,
+			MemLimit:   s.stats.MemLimit,
+			NetRx:      s.stats.NetRx,
+			NetTx:      s.stats.NetTx,
+			BlockRead:  s.stats.BlockRead,
+			BlockWrite: s.stats.BlockWrite,
+			Throttled:  s.stats.ThrottledPeriods,
+		})
+	}
+	return points
+}
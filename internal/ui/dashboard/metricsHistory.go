@@ -0,0 +1,260 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+const (
+	historyInterval   = 10 * time.Second // between recorded samples
+	historyPruneEvery = time.Hour
+	historyChartMin   = 20 // braille cells, two samples each; charts grow with the view
+)
+
+// historyRange is a time range the history screen offers
+type historyRange struct {
+	label  string
+	period time.Duration
+}
+
+// historyRanges are selected with 1-4 in the history screen
+var historyRanges = []historyRange{
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"24h", 24 * time.Hour},
+}
+
+// openHistory opens the stats history configured in cfg; it returns nil when recording
+// is disabled
+func openHistory(cfg *config.Config) (*history.Store, error) {
+	retention, enabled := cfg.History()
+	if !enabled {
+		return nil, nil
+	}
+	path := cfg.HistoryFile
+	if path == "" {
+		path = history.DefaultPath()
+	}
+	return history.Open(path, retention)
+}
+
+// startHistoryRecorder writes the usage of all running containers, and the state of
+// every listed one, to the history file and drops samples past the retention period
+func (d *Dashboard) startHistoryRecorder() {
+	if d.history == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(historyInterval)
+		defer ticker.Stop()
+		prune := time.NewTicker(historyPruneEvery)
+		defer prune.Stop()
+
+		d.reconcileRemoved()
+		d.reconcileUptime()
+		for {
+			select {
+			case <-d.refreshCtx.Done():
+				d.history.Close()
+				return
+			case now := <-ticker.C:
+				d.recordHistory(now)
+				d.reconcileUptime()
+			case <-prune.C:
+				d.history.Prune()
+			}
+		}
+	}()
+}
+
+// recordHistory stores the latest sample of every monitored container
+func (d *Dashboard) recordHistory(now time.Time) {
+	latest := d.statsCollector.Snapshot()
+	if len(latest) == 0 {
+		return
+	}
+
+	samples := make(map[string]history.Sample, len(latest))
+	for id, s := range latest {
+		samples[id] = history.Sample{CPU: s.cpu, Mem: s.mem}
+	}
+	if err := d.history.Record(now, samples); err != nil {
+		d.app.QueueUpdateDraw(func() {
+			d.flashStatus(fmt.Sprintf("[%s]History not saved: %s[-]", currentTheme().Error, tview.Escape(err.Error())))
+		})
+	}
+}
+
+// showHistory graphs the recorded CPU and memory usage of a container over a selectable
+// time range
+func (d *Dashboard) showHistory(container docker.ContainerInfo) {
+	if d.history == nil {
+		msg := "Stats history is disabled (history_retention is \"off\")."
+		if d.historyErr != nil {
+			msg = fmt.Sprintf("Stats history is unavailable:\n\n%s", d.historyErr)
+		}
+		showMessage(d.app, d.mainFlex, "🕘 History", msg)
+		return
+	}
+
+	// Ranges longer than the retention period would only show empty space
+	ranges := []historyRange{historyRanges[0]}
+	for _, r := range historyRanges[1:] {
+		if r.period <= d.history.Retention() {
+			ranges = append(ranges, r)
+		}
+	}
+	selected := len(ranges) - 1
+	if selected > 1 {
+		selected = 1
+	}
+	d.showRangeScreen(ranges, selected, func(view *tview.TextView, r historyRange) {
+		view.SetTitle(fmt.Sprintf(" 🕘 History: %s (last %s) ", qualifiedName(container), r.label))
+
+		to := time.Now()
+		from := to.Add(-r.period)
+		samples, err := d.history.Query(container.ID, from, to)
+		if err != nil {
+			view.SetText(fmt.Sprintf("[%s]Error:[-] %s", currentTheme().Error, tview.Escape(err.Error())))
+			return
+		}
+		// The value axis takes up to 7 columns
+		_, _, width, _ := view.GetInnerRect()
+		view.SetText(renderHistory(samples, from, to, max(width-7, historyChartMin)))
+	})
+}
+
+// showRangeScreen opens a view with a picker for ranges below it, starting at selected.
+// render fills the view for the picked range; r renders again and ESC or q goes back.
+func (d *Dashboard) showRangeScreen(ranges []historyRange, selected int, render func(view *tview.TextView, r historyRange)) {
+	t := currentTheme()
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	view.SetBorder(true).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Accent))
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(view, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	draw := func() {
+		tabs := ""
+		for i, rr := range ranges {
+			if i == selected {
+				tabs += fmt.Sprintf("[%s:%s:b] %d %s [-:-:-]  ", t.Background, t.Accent, i+1, rr.label)
+			} else {
+				tabs += fmt.Sprintf("[%s]%d[-] %s  ", t.Accent, i+1, rr.label)
+			}
+		}
+		footer.SetText(tabs + fmt.Sprintf("[%[1]s]←/→[-] Range   [%[1]s]r[-] Reload   [%[1]s]ESC/q[-] Back", t.Highlight))
+		render(view, ranges[selected])
+	}
+
+	back := func() {
+		setRoot(d.app, d.mainFlex)
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
+			back()
+			return nil
+		case tcell.KeyLeft:
+			selected = (selected + len(ranges) - 1) % len(ranges)
+			draw()
+			return nil
+		case tcell.KeyRight:
+			selected = (selected + 1) % len(ranges)
+			draw()
+			return nil
+		}
+		switch r := event.Rune(); {
+		case r == 'q' || r == 'Q':
+			back()
+			return nil
+		case r == 'r' || r == 'R':
+			draw()
+			return nil
+		case r >= '1' && int(r-'1') < len(ranges):
+			selected = int(r - '1')
+			draw()
+			return nil
+		}
+		return event
+	})
+
+	draw()
+	setRoot(d.app, flex)
+	d.app.SetFocus(view)
+	// Once the view has its size, draw again for renders that fill its width
+	d.app.QueueUpdateDraw(draw)
+}
+
+// renderHistory draws CPU and memory charts of samples between from and to, width braille
+// cells wide. Each chart point is the peak of its time slot so short spikes stay visible.
+func renderHistory(samples []history.Sample, from, to time.Time, width int) string {
+	t := currentTheme()
+	if len(samples) == 0 {
+		return fmt.Sprintf("[%s]No samples recorded in this range yet.[-]\n\n", t.Warning) +
+			fmt.Sprintf("[%s]DockPulse records running containers every %s while it is open.[-]", t.Muted, historyInterval)
+	}
+
+	points := 2 * width
+	slot := to.Sub(from) / time.Duration(points)
+	cpu, mem := make([]float64, points), make([]float64, points)
+	for i := range cpu {
+		cpu[i], mem[i] = math.NaN(), math.NaN()
+	}
+
+	var sumCPU, sumMem float64
+	minCPU, maxCPU := samples[0].CPU, samples[0].CPU
+	minMem, maxMem := samples[0].Mem, samples[0].Mem
+	for _, s := range samples {
+		minCPU, maxCPU = min(minCPU, s.CPU), max(maxCPU, s.CPU)
+		minMem, maxMem = min(minMem, s.Mem), max(maxMem, s.Mem)
+
+		i := int(s.Time.Sub(from) / slot)
+		if i < 0 || i >= points {
+			continue
+		}
+		if math.IsNaN(cpu[i]) || s.CPU > cpu[i] {
+			cpu[i] = s.CPU
+		}
+		if math.IsNaN(mem[i]) || s.Mem > mem[i] {
+			mem[i] = s.Mem
+		}
+		sumCPU += s.CPU
+		sumMem += s.Mem
+	}
+
+	n := float64(len(samples))
+	axis := fmt.Sprintf("[%s]%s … %s, one column per %s[-]", t.Muted,
+		from.Format("Jan 2 15:04"), to.Format("15:04"), (2 * slot).Round(time.Second))
+
+	section := func(title string, data []float64, lo, avg, hi float64) string {
+		return fmt.Sprintf("[::b]%s[-:-:-]  [%s]min[-] %.1f%%  [%s]avg[-] %.1f%%  [%s]max[-] %.1f%%\n%s\n%s\n\n",
+			title, t.Muted, lo, t.Muted, avg, t.Muted, hi, labeledBrailleChart(data, 6, width), axis)
+	}
+
+	return section(fmt.Sprintf("[%s]CPU[-]", t.Accent), cpu, minCPU, sumCPU/n, maxCPU) +
+		section(fmt.Sprintf("[%s]Memory[-]", t.Secondary), mem, minMem, sumMem/n, maxMem) +
+		fmt.Sprintf("[%s]%d samples[-]", t.Muted, len(samples))
+}
@@ -0,0 +1,136 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+// oomHistorySize is how many OOM kills are remembered per container
+const oomHistorySize = 50
+
+// oomKill is one OOM kill of a container
+type oomKill struct {
+	at    time.Time
+	used  uint64 // memory in use at the last stats sample before the kill, 0 when unknown
+	limit uint64 // memory limit at that sample, the host memory without a limit
+}
+
+// OOMTracker keeps the OOM kills seen on the event streams, per container
+type OOMTracker struct {
+	mu    sync.Mutex
+	kills map[string][]oomKill // container ID -> oldest first
+}
+
+// NewOOMTracker returns an empty tracker
+func NewOOMTracker() *OOMTracker {
+	return &OOMTracker{kills: make(map[string][]oomKill)}
+}
+
+// Record notes an oom event together with the last memory sample of the container,
+// if it has one
+func (o *OOMTracker) Record(event docker.EventInfo, last *docker.ContainerStats) {
+	if event.Type != "container" {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	switch event.Action {
+	case "destroy":
+		delete(o.kills, event.ActorID)
+		return
+	case "oom":
+	default:
+		return
+	}
+
+	kill := oomKill{at: event.Time}
+	if last != nil {
+		kill.used, kill.limit = last.MemUsed, last.MemLimit
+	}
+	kills := append(o.kills[event.ActorID], kill)
+	if len(kills) > oomHistorySize {
+		kills = kills[len(kills)-oomHistorySize:]
+	}
+	o.kills[event.ActorID] = kills
+}
+
+// Kills returns the OOM kills of a container seen since DockPulse started, oldest first
+func (o *OOMTracker) Kills(id string) []oomKill {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]oomKill(nil), o.kills[id]...)
+}
+
+// Today counts the OOM kills of a container since local midnight
+func (o *OOMTracker) Today(id string) int {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, k := range o.kills[id] {
+		if !k.at.Before(midnight) {
+			n++
+		}
+	}
+	return n
+}
+
+// mergeOOMKills combines the kills seen live with the ones the daemon still remembers,
+// which lack memory samples. Kills less than a second apart are the same kill.
+func mergeOOMKills(seen []oomKill, daemon []time.Time) []oomKill {
+	merged := append([]oomKill(nil), seen...)
+	for _, at := range daemon {
+		known := false
+		for _, k := range seen {
+			if d := k.at.Sub(at); d > -time.Second && d < time.Second {
+				known = true
+				break
+			}
+		}
+		if !known {
+			merged = append(merged, oomKill{at: at})
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].at.Before(merged[j].at) })
+	return merged
+}
+
+// renderOOMHistory lists the OOM kills of a container for the health screen, with a
+// hint for sizing the memory limit
+func renderOOMHistory(kills []oomKill, limit int64) string {
+	t := currentTheme()
+	if len(kills) == 0 {
+		return fmt.Sprintf("[%s]No OOM kills recorded[-]\n", t.Success)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s::b]%d OOM kills[-:-:-]\n", t.Error, len(kills))
+	start := max(0, len(kills)-10)
+	if start > 0 {
+		fmt.Fprintf(&b, "[%s]... %d earlier[-]\n", t.Muted, start)
+	}
+	for _, k := range kills[start:] {
+		memory := "memory at the time unknown"
+		if k.limit > 0 {
+			memory = fmt.Sprintf("%s of %s in use", docker.FormatBytes(k.used), docker.FormatBytes(k.limit))
+		}
+		fmt.Fprintf(&b, "  %s  [%s]%s[-]\n", k.at.Local().Format("2006-01-02 15:04:05"), t.Muted, memory)
+	}
+
+	switch {
+	case limit > 0:
+		fmt.Fprintf(&b, "\n[%s]Limit is %s; raise it (e.g. to %s) or reduce the memory the app uses.[-]\n",
+			t.Warning, docker.FormatBytes(uint64(limit)), docker.FormatBytes(uint64(limit)*3/2))
+	default:
+		fmt.Fprintf(&b, "\n[%s]No memory limit is set, so the host itself ran out of memory.[-]\n", t.Warning)
+	}
+	return b.String()
+}
@@ -0,0 +1,237 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// overviewTop is how many containers each overview chart ranks
+const overviewTop = 10
+
+// overviewEntry is one bar of an overview chart
+type overviewEntry struct {
+	name  string
+	value float64
+}
+
+// showOverview ranks the running containers by CPU and by memory as bar charts that
+// follow the background stats collector, meant to stay open on a wall monitor
+func (d *Dashboard) showOverview() {
+	t := currentTheme()
+
+	newChart := func(title string) *tview.TextView {
+		chart := tview.NewTextView().
+			SetDynamicColors(true).
+			SetScrollable(false).
+			SetWrap(false)
+		chart.SetBorder(true).
+			SetTitle(title).
+			SetBorderPadding(1, 1, 2, 2).
+			SetBorderColor(tcell.GetColor(t.Info))
+		return chart
+	}
+	cpuChart := newChart(fmt.Sprintf(" 🔥 Top %d CPU ", overviewTop))
+	memChart := newChart(fmt.Sprintf(" 🧠 Top %d Memory ", overviewTop))
+
+	status := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+
+	// Kiosk mode adds a panel that shows one container after another in detail
+	kiosk := d.cfg.Kiosk
+	spotlight := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	spotlight.SetBorder(true).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Accent))
+	var spotlightID string
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(cpuChart, 0, 1, true).
+			AddItem(memChart, 0, 1, false), 0, 1, true)
+	if kiosk {
+		flex.AddItem(spotlight, 9, 0, false)
+	}
+	flex.AddItem(status, 1, 0, false)
+
+	var rotate func()
+
+	// render must be called on the UI goroutine
+	render := func() {
+		d.mu.RLock()
+		containers := d.containers
+		d.mu.RUnlock()
+		names := make(map[string]string, len(containers))
+		for _, c := range containers {
+			names[c.ID] = qualifiedName(c)
+		}
+		snapshot := d.statsCollector.Snapshot()
+
+		var cpu, mem []overviewEntry
+		for id, s := range snapshot {
+			name, ok := names[id]
+			if !ok {
+				continue
+			}
+			cpu = append(cpu, overviewEntry{name, s.cpu})
+			mem = append(mem, overviewEntry{name, s.mem})
+		}
+
+		for _, chart := range []struct {
+			view    *tview.TextView
+			entries []overviewEntry
+		}{{cpuChart, cpu}, {memChart, mem}} {
+			_, _, width, _ := chart.view.GetInnerRect()
+			chart.view.SetText(renderOverviewChart(chart.entries, width, kiosk))
+		}
+
+		if !kiosk {
+			status.SetText(fmt.Sprintf("[%s]%d running · updated %s[-]   [[yellow]ESC/q[-]] Back",
+				t.Muted, len(cpu), time.Now().Format("15:04:05")))
+			return
+		}
+		status.SetText(fmt.Sprintf("[%s]KIOSK · %d running · updated %s[-]", t.Muted, len(cpu), time.Now().Format("15:04:05")))
+		if spotlightID == "" {
+			rotate()
+		}
+		for _, c := range containers {
+			if c.ID != spotlightID {
+				continue
+			}
+			if s, ok := snapshot[c.ID]; ok {
+				spotlight.SetText(renderKioskSpotlight(c, s))
+				return
+			}
+		}
+		spotlight.SetTitle(" 🎯 Spotlight ")
+		spotlight.SetText(fmt.Sprintf("[%s]Waiting for running containers...[-]", t.Muted))
+	}
+
+	// rotate moves the spotlight to the next container; it must be called on the UI goroutine
+	rotate = func() {
+		d.mu.RLock()
+		containers := d.containers
+		d.mu.RUnlock()
+		c, n, total, ok := kioskSpotlight(containers, d.statsCollector.Snapshot(), spotlightID)
+		if !ok {
+			spotlightID = ""
+			return
+		}
+		spotlightID = c.ID
+		spotlight.SetTitle(fmt.Sprintf(" 🎯 %s (%d/%d) ", tview.Escape(qualifiedName(c)), n, total))
+	}
+
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	go func() {
+		ticker := time.NewTicker(listStatsRedraw)
+		defer ticker.Stop()
+		rotation := time.NewTicker(d.cfg.KioskRotation())
+		defer rotation.Stop()
+		if !kiosk {
+			rotation.Stop()
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.app.QueueUpdateDraw(render)
+			case <-rotation.C:
+				d.app.QueueUpdateDraw(func() {
+					rotate()
+					render()
+				})
+			}
+		}
+	}()
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'Q' ||
+			d.keys.Action(event) == actionBack {
+			cancel()
+			setRoot(d.app, d.mainFlex)
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(cpuChart)
+	if kiosk {
+		rotate()
+	}
+	render()
+}
+
+// renderOverviewChart draws the overviewTop highest entries as bars of up to width cells.
+// Bars are scaled to 100%, or to the highest value when a container uses more than one CPU.
+// Large charts put the name above a bar of double height and the full width.
+func renderOverviewChart(entries []overviewEntry, width int, large bool) string {
+	if len(entries) == 0 {
+		return fmt.Sprintf("[%s]No running containers with stats yet.[-]", currentTheme().Muted)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].value != entries[j].value {
+			return entries[i].value > entries[j].value
+		}
+		return entries[i].name < entries[j].name
+	})
+	if len(entries) > overviewTop {
+		entries = entries[:overviewTop]
+	}
+	scale := max(100, entries[0].value)
+
+	var b strings.Builder
+	if large {
+		barWidth := max(width, 10)
+		for _, e := range entries {
+			bar := overviewBar(e.value/scale, barWidth)
+			fmt.Fprintf(&b, "[::b]%-*s[-:-:-][%s::b]%6.1f%%[-:-:-]\n", barWidth-7,
+				tview.Escape(truncateString(e.name, barWidth-7)), usageColor(e.value), e.value)
+			fmt.Fprintf(&b, "[%[1]s]%[2]s[-]\n[%[1]s]%[2]s[-]\n", usageColor(e.value), bar)
+		}
+		return b.String()
+	}
+
+	nameWidth := 0
+	for _, e := range entries {
+		nameWidth = max(nameWidth, len([]rune(e.name)))
+	}
+	nameWidth = min(nameWidth, 24)
+	// Name, value and the spaces around them
+	barWidth := max(width-nameWidth-9, 10)
+
+	for _, e := range entries {
+		bar := overviewBar(e.value/scale, barWidth)
+		fmt.Fprintf(&b, "%-*s [%s]%s[-]%s %5.1f%%\n\n",
+			nameWidth, tview.Escape(truncateString(e.name, nameWidth)),
+			usageColor(e.value), bar, strings.Repeat(" ", barWidth-utf8.RuneCountInString(bar)), e.value)
+	}
+	return b.String()
+}
+
+// overviewBar draws a horizontal bar of up to width cells filled to fraction, in eighths
+func overviewBar(fraction float64, width int) string {
+	eighths := []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+	fraction = math.Max(0, math.Min(fraction, 1))
+	cells := fraction * float64(width)
+	full := int(cells)
+
+	bar := strings.Repeat("█", full)
+	if rest := int((cells - float64(full)) * 8); rest > 0 && full < width {
+		bar += string(eighths[rest-1])
+	}
+	return bar
+}
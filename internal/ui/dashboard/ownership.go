@@ -0,0 +1,108 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// ownerField is one line of the Owner section and the labels it is read from, the
+// first one set wins. Container labels include the image's, so OCI image labels count.
+type ownerField struct {
+	name   string
+	labels []string
+	link   bool // a contact or runbook worth copying during an incident
+}
+
+// ownerFields are the conventional ownership labels, most specific first
+var ownerFields = []ownerField{
+	{"Team", []string{"dockpulse.team", "team", "owner.team", "com.docker.team"}, false},
+	{"Owner", []string{"dockpulse.owner", "owner", "maintainer", "org.opencontainers.image.authors", "org.label-schema.vendor"}, true},
+	{"On-call", []string{"dockpulse.oncall", "oncall", "on-call", "oncall.url", "pagerduty", "opsgenie"}, true},
+	{"Runbook", []string{"dockpulse.runbook", "runbook", "runbook.url", "runbook_url", "playbook"}, true},
+	{"Chat", []string{"dockpulse.chat", "slack", "slack.channel", "chat", "contact"}, true},
+	{"Docs", []string{"org.opencontainers.image.documentation", "org.label-schema.usage", "docs"}, true},
+}
+
+// ownerEntry is an ownership field found on a container
+type ownerEntry struct {
+	field ownerField
+	label string
+	value string
+}
+
+// ownerOf reads the ownership fields set on a container
+func ownerOf(c docker.ContainerInfo) []ownerEntry {
+	var entries []ownerEntry
+	for _, f := range ownerFields {
+		for _, label := range f.labels {
+			if value := strings.TrimSpace(c.Labels[label]); value != "" {
+				entries = append(entries, ownerEntry{f, label, value})
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// ownerDetails renders the Owner section of the details panel
+func (d *Dashboard) ownerDetails(c docker.ContainerInfo) string {
+	entries := ownerOf(c)
+	if len(entries) == 0 {
+		return ""
+	}
+	t := currentTheme()
+	details := fmt.Sprintf("\n\n[%s::b]👤 Owner:[-:-:-]", t.Accent)
+	for _, e := range entries {
+		details += fmt.Sprintf("\n[%s]%s:[-] %s", t.Muted, e.field.name, tview.Escape(e.value))
+	}
+	for _, e := range entries {
+		if e.field.link {
+			details += fmt.Sprintf("\n[%s]%s copies a contact[-]", t.Muted, d.keys.KeyLabel(actionCopyOwner))
+			break
+		}
+	}
+	return details
+}
+
+// copyOwner copies the contact or runbook link of a container; with several, they are
+// listed to pick from
+func (d *Dashboard) copyOwner(c docker.ContainerInfo) {
+	var links []ownerEntry
+	for _, e := range ownerOf(c) {
+		if e.field.link {
+			links = append(links, e)
+		}
+	}
+	switch len(links) {
+	case 0:
+		d.flashStatus(fmt.Sprintf("[%s]%s has no owner, on-call or runbook labels[-]", currentTheme().Warning, tview.Escape(c.Name)))
+		return
+	case 1:
+		d.copyValue(strings.ToLower(links[0].field.name), links[0].value)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(true)
+	for i, e := range links {
+		e := e
+		list.AddItem(fmt.Sprintf("%s: %s", e.field.name, tview.Escape(e.value)),
+			fmt.Sprintf("[%s]label %s[-]", currentTheme().Muted, tview.Escape(e.label)), rune('1'+i), func() {
+				setRoot(d.app, d.mainFlex)
+				d.copyValue(strings.ToLower(e.field.name), e.value)
+			})
+	}
+	list.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+	})
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 👤 Copy Contact: %s (Enter copy, ESC cancel) ", tview.Escape(c.Name))).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+
+	showOverlay(d.app, d.mainFlex, list, 80, 2*list.GetItemCount()+4)
+}
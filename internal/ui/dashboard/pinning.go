@@ -0,0 +1,306 @@
+package dashboard
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// defaultMutableTags are the tags the pinning audit reports unless mutable_tags is set
+var defaultMutableTags = []string{"latest", "main", "master", "develop", "dev", "edge", "nightly", "stable"}
+
+// mutableTags returns the tags that count as moving
+func (d *Dashboard) mutableTags() []string {
+	if len(d.cfg.MutableTags) > 0 {
+		return d.cfg.MutableTags
+	}
+	return defaultMutableTags
+}
+
+// pinStatus describes how a container refers to its image
+func pinStatus(p docker.ImagePin) (text, color string) {
+	t := currentTheme()
+	switch {
+	case p.Pinned:
+		return "📌 pinned", t.Success
+	case p.Mutable && p.Digest == "":
+		return "⚠ mutable tag, built locally", t.Error
+	case p.Mutable:
+		return "⚠ mutable tag", t.Warning
+	default:
+		return "versioned tag", t.Muted
+	}
+}
+
+// showPinningAudit lists the running containers with the image reference each was
+// created from, flagging mutable tags such as latest. p recreates the selected
+// container from the digest of the image it runs, P all flagged ones.
+func (d *Dashboard) showPinningAudit() {
+	t := currentTheme()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 📌 Image Pinning Audit ").
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] p [-:-:-] Pin selected   [black:green] P [-:-:-] Pin all mutable   [black:green] e [-:-:-] Export CSV   [black:green] r [-:-:-] Rescan   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var pins []docker.ImagePin
+	notes := make(map[string]string) // container ID -> result of pinning it
+
+	// render must be called on the UI goroutine
+	render := func(err error) {
+		table.Clear()
+		for col, h := range []string{"CONTAINER", "IMAGE REFERENCE", "STATUS", "PINNED REFERENCE"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		if err != nil {
+			header.SetText(fmt.Sprintf(" [%s]Scan failed[-]", t.Error))
+			table.SetCell(1, 0, tview.NewTableCell(errorSummary(err)).SetTextColor(tcell.GetColor(t.Error)).SetSelectable(false))
+			return
+		}
+
+		var mutable, unpinnable, pinned int
+		for i, p := range pins {
+			status, color := pinStatus(p)
+			if note, ok := notes[p.ContainerID]; ok {
+				status = note
+			}
+			switch {
+			case p.Pinned:
+				pinned++
+			case p.Mutable && p.Digest == "":
+				unpinnable++
+			case p.Mutable:
+				mutable++
+			}
+			target := p.PinnedRef()
+			if p.Pinned || target == "" {
+				target = "-"
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(p.Name)).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(p.Ref)).SetTextColor(tcell.GetColor(color)))
+			table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(status)).SetTextColor(tcell.GetColor(color)))
+			table.SetCell(i+1, 3, tview.NewTableCell(tview.Escape(target)).SetTextColor(tcell.GetColor(t.Muted)).SetExpansion(1))
+		}
+		if len(pins) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No running containers").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+
+		summary := fmt.Sprintf("[%s::b]All %d running containers are reproducible[-:-:-]", t.Success, len(pins))
+		if mutable+unpinnable > 0 {
+			summary = fmt.Sprintf("[%s::b]%d of %d running containers use a mutable tag[-:-:-]", t.Warning, mutable+unpinnable, len(pins))
+		}
+		header.SetText(fmt.Sprintf(" %s   %d pinned by digest\n [%s]Mutable tags: %s; images built here have no digest to pin and need a versioned tag[-]",
+			summary, pinned, t.Muted, strings.Join(d.mutableTags(), ", ")))
+	}
+
+	generation := 0
+	scan := func() {
+		header.SetText(fmt.Sprintf(" [%s]Scanning...[-]", t.Muted))
+		generation++
+		current := generation
+		go func() {
+			found, err := docker.ImagePins(d.mutableTags())
+			d.app.QueueUpdateDraw(func() {
+				if current != generation {
+					return
+				}
+				pins = found
+				render(err)
+			})
+		}()
+	}
+
+	// pin recreates the containers from the digests of the images they run, one after
+	// another, like the recreate form
+	pin := func(picked []docker.ImagePin) {
+		var containers []docker.ContainerInfo
+		var lines []string
+		for _, p := range picked {
+			container := docker.ContainerInfo{ID: p.ContainerID, Name: p.Name}
+			d.mu.RLock()
+			for _, c := range d.containers {
+				if c.ID == p.ContainerID {
+					container = c
+				}
+			}
+			d.mu.RUnlock()
+			containers = append(containers, container)
+			lines = append(lines, fmt.Sprintf("%s: %s → %s", p.Name, p.Ref, p.PinnedRef()))
+		}
+		if len(lines) > 8 {
+			lines = append(lines[:8:8], fmt.Sprintf("and %d more", len(picked)-8))
+		}
+
+		run := func() {
+			for _, p := range picked {
+				notes[p.ContainerID] = "waiting"
+			}
+			render(nil)
+			go func() {
+				var failed int
+				for _, p := range picked {
+					d.app.QueueUpdateDraw(func() {
+						notes[p.ContainerID] = "⏳ recreating..."
+						render(nil)
+					})
+					note := "📌 pinned, recreated"
+					spec, err := docker.GetContainerSpec(p.ContainerID)
+					if err == nil {
+						spec.Image = p.PinnedRef()
+						_, err = docker.RecreateContainer(p.ContainerID, *spec)
+					}
+					if err != nil {
+						failed++
+						note = "✗ " + errorSummary(err)
+					}
+					d.app.QueueUpdateDraw(func() {
+						notes[p.ContainerID] = note
+						render(nil)
+					})
+				}
+				d.app.QueueUpdateDraw(func() {
+					d.updateList()
+					if failed > 0 {
+						showToast(d.app, toastWarning, fmt.Sprintf("%d of %d containers could not be pinned", failed, len(picked)))
+						return
+					}
+					clear(notes)
+					showToast(d.app, toastSuccess, fmt.Sprintf("Pinned %d containers to their image digests", len(picked)))
+					scan()
+				})
+			}()
+		}
+		guardProtected(d.app, flex, "recreate", containers, func() {
+			showConfirmation(d.app, flex,
+				fmt.Sprintf("Recreate %d containers from the digests of the images they run?\n\n%s\n\nEach container is stopped and replaced; if the new one fails to start, the old one is restored.",
+					len(picked), strings.Join(lines, "\n")),
+				run)
+		})
+	}
+
+	pinnable := func(p docker.ImagePin) bool {
+		return !p.Pinned && p.Digest != ""
+	}
+
+	export := func() {
+		if d.cfg.Kiosk {
+			d.flashStatus(fmt.Sprintf("[%s]Exports are disabled in kiosk mode[-]", t.Warning))
+			return
+		}
+		path := filepath.Join(d.downloadDir(), fmt.Sprintf("dockpulse-pinning-%s.csv", time.Now().Format("20060102-1504")))
+		if err := writePinningCSV(path, pins); err != nil {
+			showError(d.app, flex, "Export failed", err)
+			return
+		}
+		copyToClipboard(d.app, path)
+		showToast(d.app, toastSuccess, fmt.Sprintf("Exported %d containers to %s (path copied)", len(pins), path))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		case event.Rune() == 'p' || event.Rune() == 'P':
+			if !d.kioskAllows(actionRecreate) {
+				return nil
+			}
+			var picked []docker.ImagePin
+			if event.Rune() == 'P' {
+				for _, p := range pins {
+					if p.Mutable && pinnable(p) {
+						picked = append(picked, p)
+					}
+				}
+			} else if row, _ := table.GetSelection(); row >= 1 && row <= len(pins) {
+				p := pins[row-1]
+				switch {
+				case p.Pinned:
+					showToast(d.app, toastInfo, p.Name+" is already pinned")
+				case p.Digest == "":
+					showToast(d.app, toastWarning, p.Name+" runs an image built here, which has no registry digest to pin")
+				default:
+					picked = []docker.ImagePin{p}
+				}
+			}
+			if len(picked) > 0 {
+				pin(picked)
+			}
+			return nil
+		case event.Rune() == 'e':
+			export()
+			return nil
+		case event.Rune() == 'r':
+			clear(notes)
+			scan()
+			return nil
+		}
+		return event
+	})
+
+	render(nil)
+	scan()
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
+
+// writePinningCSV writes the pinning audit with one row per running container
+func writePinningCSV(path string, pins []docker.ImagePin) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"container", "id", "image", "image_id", "pinned", "mutable", "digest", "pinned_reference"})
+	for _, p := range pins {
+		w.Write([]string{
+			p.Name,
+			p.ContainerID[:12],
+			p.Ref,
+			p.ImageID[:min(12, len(p.ImageID))],
+			fmt.Sprint(p.Pinned),
+			fmt.Sprint(p.Mutable),
+			p.Digest,
+			p.PinnedRef(),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,222 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/plugin"
+)
+
+// showPlugins lists the plugins offered for a container and runs the chosen one
+func (d *Dashboard) showPlugins(container docker.ContainerInfo) {
+	var offered []*config.Plugin
+	for i := range d.cfg.Plugins {
+		if p := &d.cfg.Plugins[i]; p.Matches(container.Name) {
+			offered = append(offered, p)
+		}
+	}
+	if len(offered) == 0 {
+		msg := fmt.Sprintf("No plugins configured.\n\nAdd \"plugins\" to %s to run your own programs for a container.", config.Path())
+		if len(d.cfg.Plugins) > 0 {
+			msg = fmt.Sprintf("None of the %d configured plugins is offered for %s.", len(d.cfg.Plugins), container.Name)
+		}
+		showMessage(d.app, d.mainFlex, "🧩 Plugins", msg)
+		return
+	}
+
+	menu := tview.NewList().ShowSecondaryText(true)
+	menu.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🧩 Plugins: %s ", container.Name)).
+		SetBorderColor(ColorOrange).
+		SetBorderPadding(1, 1, 2, 2)
+
+	for i, p := range offered {
+		var shortcut rune
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+		description := tview.Escape(p.Description)
+		if description == "" {
+			description = tview.Escape(strings.Join(p.Command, " "))
+		}
+		if p.Every() > 0 {
+			description += fmt.Sprintf(" (live, every %s)", p.Every())
+		}
+		if p.Terminal {
+			description += " (opens in the terminal)"
+		}
+		menu.AddItem(p.Name, description, shortcut, func() {
+			if p.Terminal {
+				d.runInTerminal(p, container)
+				return
+			}
+			d.runPlugin(p, container)
+		})
+	}
+	menu.AddItem("Cancel", "", 'q', func() {
+		setRoot(d.app, d.mainFlex)
+	})
+	menu.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+	})
+
+	showOverlay(d.app, d.mainFlex, menu, 70, min(8+2*len(offered), 26))
+}
+
+// runPlugin runs a plugin for a container and shows its output. Plugins with a refresh
+// interval run again until the screen is left.
+func (d *Dashboard) runPlugin(p *config.Plugin, container docker.ContainerInfo) {
+	t := currentTheme()
+	target := pluginTarget(container)
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	output := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+	output.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🧩 %s: %s ", p.Name, container.Name)).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[black:green] r [-:-:-] Run again   [black:green] y [-:-:-] Copy output   [black:red] ESC [-:-:-] Back")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(output, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	runs := make(chan struct{}, 1)
+	var last plugin.Result
+
+	// run starts a run unless one is in progress
+	run := func() {
+		select {
+		case runs <- struct{}{}:
+		default:
+			return
+		}
+		header.SetText(fmt.Sprintf(" [%s]⏳ Running[-] %s\n [%s]%s[-]",
+			t.Warning, tview.Escape(strings.Join(p.Command, " ")), t.Muted, hostLabel(container)))
+		go func() {
+			defer func() { <-runs }()
+			result, err := plugin.Run(ctx, p, target)
+			if ctx.Err() != nil {
+				return
+			}
+			d.app.QueueUpdateDraw(func() {
+				last = result
+				header.SetText(pluginStatus(p, result, err))
+				text := renderANSI(result.Output, true)
+				if result.Truncated {
+					text += fmt.Sprintf("\n[%s]… output cut at 1 MB[-]", t.Muted)
+				}
+				output.SetText(text)
+				if p.Every() == 0 {
+					output.ScrollToBeginning()
+				}
+			})
+		}()
+	}
+
+	if every := p.Every(); every > 0 {
+		go func() {
+			ticker := time.NewTicker(every)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					d.app.QueueUpdateDraw(run)
+				}
+			}
+		}()
+	}
+
+	output.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			cancel()
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		case event.Rune() == 'r':
+			run()
+			return nil
+		case event.Rune() == 'y':
+			copyToClipboard(d.app, last.Output)
+			showToast(d.app, toastSuccess, fmt.Sprintf("Copied the output of %s", p.Name))
+			return nil
+		}
+		return event
+	})
+
+	run()
+	setRoot(d.app, flex)
+	d.app.SetFocus(output)
+}
+
+// runInTerminal suspends the dashboard while a terminal plugin runs. After a failed run
+// it waits for Enter, so what the program printed last can still be read.
+func (d *Dashboard) runInTerminal(p *config.Plugin, container docker.ContainerInfo) {
+	setRoot(d.app, d.mainFlex)
+
+	var err error
+	d.app.Suspend(func() {
+		var code int
+		code, err = plugin.RunInTerminal(p, pluginTarget(container))
+		if err == nil && code != 0 {
+			fmt.Printf("\n%s exited with code %d. Press Enter to return to DockPulse.", p.Name, code)
+			bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+	if err != nil {
+		showError(d.app, d.mainFlex, "🧩 "+p.Name, err)
+	}
+}
+
+// pluginTarget describes a container to the programs DockPulse runs for it
+func pluginTarget(c docker.ContainerInfo) plugin.Target {
+	return plugin.Target{
+		ID:         c.ID,
+		Name:       c.Name,
+		Image:      c.Image,
+		State:      c.State,
+		Host:       hostLabel(c),
+		DockerHost: docker.HostURLFor(c.ID),
+	}
+}
+
+// pluginStatus renders the header line of a finished run, e.g. "✓ exit 0 in 1.2s"
+func pluginStatus(p *config.Plugin, result plugin.Result, err error) string {
+	t := currentTheme()
+	at := time.Now().Format("15:04:05")
+	switch {
+	case err != nil:
+		return fmt.Sprintf(" [%s]✗ %s[-]\n [%s]%s[-]", t.Error, tview.Escape(errorSummary(err)), t.Muted, at)
+	case result.ExitCode != 0:
+		return fmt.Sprintf(" [%s]✗ exit %d[-] in %s\n [%s]%s[-]", t.Error, result.ExitCode, formatLatency(result.Duration), t.Muted, at)
+	}
+	status := fmt.Sprintf(" [%s]✓ exit 0[-] in %s\n [%s]%s", t.Success, formatLatency(result.Duration), t.Muted, at)
+	if p.Every() > 0 {
+		status += fmt.Sprintf(", runs again every %s", p.Every())
+	}
+	return status + "[-]"
+}
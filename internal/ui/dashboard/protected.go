@@ -0,0 +1,95 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// protection selects the protected containers; nil when none are configured
+var protection *config.Protected
+
+// setProtected installs the protected containers from the config
+func setProtected(cfg *config.Config) {
+	protection = cfg.Protected
+}
+
+// isProtected reports whether stopping, restarting or deleting c needs a typed
+// confirmation
+func isProtected(c docker.ContainerInfo) bool {
+	return protection != nil && protection.Matches(c.ID, c.Name, c.Labels)
+}
+
+// overridePhrase is what has to be typed to run action on protected containers,
+// e.g. "delete postgres" or "stop 3 protected"
+func overridePhrase(action string, protected []docker.ContainerInfo) string {
+	if len(protected) == 1 {
+		return action + " " + protected[0].Name
+	}
+	return fmt.Sprintf("%s %d protected", action, len(protected))
+}
+
+// guardProtected runs onConfirm right away unless some of the containers are
+// protected, in which case the override phrase has to be typed first
+func guardProtected(app *tview.Application, mainView tview.Primitive, action string, containers []docker.ContainerInfo, onConfirm func()) {
+	var protected []docker.ContainerInfo
+	var names []string
+	for _, c := range containers {
+		if isProtected(c) {
+			protected = append(protected, c)
+			names = append(names, qualifiedName(c))
+		}
+	}
+	if len(protected) == 0 {
+		onConfirm()
+		return
+	}
+
+	t := currentTheme()
+	phrase := overridePhrase(action, protected)
+	listed := names
+	if len(listed) > 3 {
+		listed = append(listed[:3:3], fmt.Sprintf("and %d more", len(names)-3))
+	}
+
+	message := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	prompt := fmt.Sprintf("[%s::b]Protected:[-:-:-] %s\n\nType [%s::b]%s[-:-:-] to %s anyway.",
+		t.Warning, tview.Escape(strings.Join(listed, ", ")), t.Highlight, tview.Escape(phrase), action)
+	message.SetText(prompt)
+
+	form := tview.NewForm().
+		AddInputField("Phrase", "", 40, nil, nil)
+	form.AddButton("Confirm", func() {
+		typed := strings.TrimSpace(form.GetFormItemByLabel("Phrase").(*tview.InputField).GetText())
+		if typed != phrase {
+			message.SetText(prompt + fmt.Sprintf("\n[%s]The phrase doesn't match.[-]", t.Error))
+			return
+		}
+		setRoot(app, mainView)
+		onConfirm()
+	})
+	form.AddButton("Cancel", func() {
+		setRoot(app, mainView)
+	})
+	form.SetCancelFunc(func() {
+		setRoot(app, mainView)
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(message, 5, 0, false).
+		AddItem(form, 0, 1, true)
+	flex.SetBorder(true).
+		SetTitle(" 🛡 Protected Container ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Warning))
+
+	showOverlay(app, mainView, flex, 70, 14)
+}
@@ -0,0 +1,182 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// recentLimit is how many containers the jump list remembers
+const recentLimit = 10
+
+// recentViews are the container screens that count as viewing a container
+var recentViews = map[string]bool{
+	actionLogs:         true,
+	actionAdvancedLogs: true,
+	actionStats:        true,
+	actionHistory:      true,
+	actionTimeline:     true,
+	actionInspect:      true,
+	actionShell:        true,
+	actionHealth:       true,
+}
+
+// recentEntry is a viewed container and the screen last opened for it
+type recentEntry struct {
+	container docker.ContainerInfo
+	action    string
+	at        time.Time
+}
+
+// RecentContainers remembers the containers viewed this session, newest first. It is
+// only used on the UI goroutine.
+type RecentContainers struct {
+	entries []recentEntry
+}
+
+// Add moves a container to the front of the jump list
+func (r *RecentContainers) Add(c docker.ContainerInfo, action string) {
+	for i, e := range r.entries {
+		if e.container.ID == c.ID && e.container.Host == c.Host {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			break
+		}
+	}
+	r.entries = append([]recentEntry{{container: c, action: action, at: time.Now()}}, r.entries...)
+	if len(r.entries) > recentLimit {
+		r.entries = r.entries[:recentLimit]
+	}
+}
+
+// openContainerView opens one of the recentViews for a container and remembers it
+func (d *Dashboard) openContainerView(action string, container docker.ContainerInfo) {
+	d.recent.Add(container, action)
+
+	d.mu.RLock()
+	containers := d.containers
+	d.mu.RUnlock()
+	switch action {
+	case actionLogs:
+		showLogs(d.app, d.mainFlex, container.ID, containers)
+	case actionAdvancedLogs:
+		ShowAdvancedLogs(d.app, d.mainFlex, container.ID, containers)
+	case actionStats:
+		showEnhancedStats(d.app, d.mainFlex, container.ID, container.Name, d.cfg.GraphStyle == config.GraphBraille && !d.cfg.LowPower)
+	case actionHistory:
+		d.showHistory(container)
+	case actionTimeline:
+		d.showTimeline(container)
+	case actionInspect:
+		scriptDir := d.downloadDir()
+		if d.cfg.Kiosk {
+			scriptDir = ""
+		}
+		showEnhancedInspect(d.app, d.mainFlex, container.ID, container.Name, scriptDir)
+	case actionShell:
+		ShowShellOptionsMenu(d.app, d.mainFlex, container.ID, containers)
+	case actionHealth:
+		d.showHealthCheck(container)
+	}
+}
+
+// showRecent lists the recently viewed containers to jump back to. The second entry is
+// preselected, so the jump key then Enter flips between the last two containers.
+func (d *Dashboard) showRecent() {
+	t := currentTheme()
+	if len(d.recent.entries) == 0 {
+		d.flashStatus(fmt.Sprintf("[%s]No containers viewed yet: logs, stats, inspect and the like add them here[-]", t.Muted))
+		return
+	}
+
+	d.mu.RLock()
+	current := make(map[string]docker.ContainerInfo, len(d.containers))
+	for _, c := range d.containers {
+		current[c.Host+"/"+c.ID] = c
+	}
+	d.mu.RUnlock()
+
+	entries := d.recent.entries
+	list := tview.NewList().ShowSecondaryText(true)
+	for i, e := range entries {
+		c, alive := current[e.container.Host+"/"+e.container.ID]
+		name := tview.Escape(qualifiedName(e.container))
+		detail := fmt.Sprintf("%s · %s ago", d.keys.Description(e.action), formatAge(e.at))
+		switch {
+		case !alive:
+			name = fmt.Sprintf("[%s]%s (gone)[-]", t.Muted, name)
+		case c.State != "running":
+			detail += " · " + c.State
+		}
+
+		var shortcut rune
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+		list.AddItem(name, fmt.Sprintf("[%s]%s[-]", t.Muted, detail), shortcut, nil)
+	}
+	if len(entries) > 1 {
+		list.SetCurrentItem(1)
+	}
+
+	// jump selects the container in the list and, with reopen, its last screen again
+	jump := func(reopen bool) {
+		e := entries[list.GetCurrentItem()]
+		setRoot(d.app, d.mainFlex)
+		c, alive := current[e.container.Host+"/"+e.container.ID]
+		if !alive {
+			showToast(d.app, toastWarning, fmt.Sprintf("%s no longer exists", qualifiedName(e.container)))
+			return
+		}
+		if d.currentTab != 0 {
+			d.switchTab(0)
+		}
+		if !d.selectContainerRow(c) {
+			d.flashStatus(fmt.Sprintf("[%s]%s is hidden by the current view or filter[-]", t.Warning, tview.Escape(qualifiedName(c))))
+		}
+		if reopen && d.kioskAllows(e.action) {
+			d.openContainerView(e.action, c)
+		}
+	}
+
+	list.SetSelectedFunc(func(int, string, string, rune) { jump(true) })
+	list.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == ' ' {
+			jump(false)
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).
+		SetTitle(" 🕘 Recently Viewed (Enter reopen, Space select, ESC cancel) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	showOverlay(d.app, d.mainFlex, list, 70, 2*list.GetItemCount()+4)
+}
+
+// selectContainerRow moves the list cursor to a container; false when it isn't listed
+func (d *Dashboard) selectContainerRow(c docker.ContainerInfo) bool {
+	d.mu.RLock()
+	row := -1
+	for i, r := range d.rows {
+		if r.containerIndex >= 0 && d.containers[r.containerIndex].ID == c.ID && d.containers[r.containerIndex].Host == c.Host {
+			row = i
+			break
+		}
+	}
+	d.mu.RUnlock()
+	if row < 0 {
+		return false
+	}
+	d.list.SetCurrentItem(row)
+	d.app.SetFocus(d.list)
+	return true
+}
@@ -0,0 +1,196 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// specForm edits the image, ports, env and volumes of a container
+type specForm struct {
+	*tview.Form
+	name  *tview.InputField // only set when the form creates a new container
+	image *tview.InputField
+	ports *tview.InputField
+	env   *tview.TextArea
+	binds *tview.TextArea
+}
+
+// newSpecForm builds the form; a non-empty name adds a field for the new container's name
+func newSpecForm(title, name string, spec docker.ContainerSpec) *specForm {
+	f := &specForm{
+		Form: tview.NewForm(),
+		image: tview.NewInputField().
+			SetLabel("Image").
+			SetText(spec.Image),
+		ports: tview.NewInputField().
+			SetLabel("Ports").
+			SetText(strings.Join(spec.Ports, ", ")).
+			SetPlaceholder("8080:80/tcp, 127.0.0.1:5432:5432"),
+		env: tview.NewTextArea().
+			SetLabel("Environment").
+			SetText(strings.Join(spec.Env, "\n"), false).
+			SetPlaceholder("KEY=value, one per line").
+			SetSize(10, 0),
+		binds: tview.NewTextArea().
+			SetLabel("Volumes").
+			SetText(strings.Join(spec.Binds, "\n"), false).
+			SetPlaceholder("volume-or-path:/container/path[:ro], one per line").
+			SetSize(5, 0),
+	}
+
+	if name != "" {
+		f.name = tview.NewInputField().
+			SetLabel("Name").
+			SetText(name)
+		f.AddFormItem(f.name)
+	}
+	f.AddFormItem(f.image).
+		AddFormItem(f.ports).
+		AddFormItem(f.env).
+		AddFormItem(f.binds)
+	f.SetBorder(true).
+		SetTitle(title).
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.ColorOrange)
+	return f
+}
+
+// spec returns the settings currently entered in the form
+func (f *specForm) spec() docker.ContainerSpec {
+	return docker.ContainerSpec{
+		Image: strings.TrimSpace(f.image.GetText()),
+		Ports: splitList(f.ports.GetText(), ", "),
+		// Env values may contain commas and spaces, so only newlines separate them
+		Env:   splitList(f.env.GetText(), "\n"),
+		Binds: splitList(f.binds.GetText(), "\n"),
+	}
+}
+
+// splitList splits form input on any of the separator characters, dropping blanks
+func splitList(text, separators string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(text, func(r rune) bool { return strings.ContainsRune(separators, r) }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// showRecreateForm lets the user edit a container's settings and replace it with a new one
+func (d *Dashboard) showRecreateForm(container docker.ContainerInfo) {
+	go func() {
+		spec, err := docker.GetContainerSpec(container.ID)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, "Error", err)
+				return
+			}
+
+			form := newSpecForm(fmt.Sprintf(" ♻️  Recreate: %s ", container.Name), "", *spec)
+			form.AddButton("Recreate", func() {
+				newSpec := form.spec()
+				if newSpec.Image == "" {
+					showMessage(d.app, form, "Recreate", "An image is required.")
+					return
+				}
+				showConfirmation(d.app, d.mainFlex,
+					fmt.Sprintf("Recreate '%s' from %s?\n\nThe container is stopped and replaced. If the new one fails to start, the old one is restored.",
+						container.Name, newSpec.Image),
+					func() { d.recreateContainer(container, newSpec) })
+			})
+			form.AddButton("Cancel", func() {
+				setRoot(d.app, d.mainFlex)
+			})
+			form.SetCancelFunc(func() {
+				setRoot(d.app, d.mainFlex)
+			})
+
+			setRoot(d.app, form)
+		})
+	}()
+}
+
+// recreateContainer replaces a container with one built from spec; a protected
+// container needs the override phrase first
+func (d *Dashboard) recreateContainer(container docker.ContainerInfo, spec docker.ContainerSpec) {
+	guardProtected(d.app, d.mainFlex, "recreate", []docker.ContainerInfo{container}, func() {
+		d.runRecreate(container, spec)
+	})
+}
+
+// runRecreate replaces the container in the background and reports the result
+func (d *Dashboard) runRecreate(container docker.ContainerInfo, spec docker.ContainerSpec) {
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Recreating %s...[-]", currentTheme().Warning, container.Name))
+	go func() {
+		newID, err := docker.RecreateContainer(container.ID, spec)
+		d.app.QueueUpdateDraw(func() {
+			d.updateList()
+			if err != nil {
+				showError(d.app, d.mainFlex, "❌ Recreate Failed", err)
+				return
+			}
+			showToast(d.app, toastSuccess, fmt.Sprintf("Container %s recreated, new ID %s", container.Name, newID[:12]))
+		})
+	}()
+}
+
+// showCloneForm lets the user start a copy of a container under a new name
+func (d *Dashboard) showCloneForm(container docker.ContainerInfo) {
+	go func() {
+		spec, err := docker.GetContainerSpec(container.ID)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				showError(d.app, d.mainFlex, "Error", err)
+				return
+			}
+
+			// Publish the same container ports on random host ports unless remapped
+			for i, port := range spec.Ports {
+				parts := strings.Split(port, ":")
+				spec.Ports[i] = parts[len(parts)-1]
+			}
+
+			form := newSpecForm(fmt.Sprintf(" 🧬 Clone: %s ", container.Name), container.Name+"-clone", *spec)
+			form.AddButton("Clone", func() {
+				name := strings.TrimSpace(form.name.GetText())
+				newSpec := form.spec()
+				if name == "" || newSpec.Image == "" {
+					showMessage(d.app, form, "Clone", "A name and an image are required.")
+					return
+				}
+				setRoot(d.app, d.mainFlex)
+				d.cloneContainer(container, name, newSpec)
+			})
+			form.AddButton("Cancel", func() {
+				setRoot(d.app, d.mainFlex)
+			})
+			form.SetCancelFunc(func() {
+				setRoot(d.app, d.mainFlex)
+			})
+
+			setRoot(d.app, form)
+		})
+	}()
+}
+
+// cloneContainer creates the clone in the background and reports the result
+func (d *Dashboard) cloneContainer(container docker.ContainerInfo, name string, spec docker.ContainerSpec) {
+	d.flashStatus(fmt.Sprintf("[%s]⏳ Cloning %s as %s...[-]", currentTheme().Warning, container.Name, name))
+	go func() {
+		newID, err := docker.CloneContainer(container.ID, name, spec)
+		d.app.QueueUpdateDraw(func() {
+			d.updateList()
+			if err != nil {
+				showError(d.app, d.mainFlex, "❌ Clone Failed", err)
+				return
+			}
+			showToast(d.app, toastSuccess, fmt.Sprintf("Started %s as a copy of %s, ID %s", name, container.Name, newID[:12]))
+		})
+	}()
+}
@@ -0,0 +1,442 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/registry"
+)
+
+// digestLookups is how many manifest digests the registry browser asks for at once
+const digestLookups = 4
+
+// registryTag is a tag in the registry browser, compared with the local image of the
+// same name
+type registryTag struct {
+	name     string
+	remote   string   // manifest digest in the registry, "" until known
+	err      error    // why the digest couldn't be read
+	local    []string // digests the local image was pulled as
+	present  bool     // the tag exists locally
+	orphaned bool     // the tag exists locally but not in the registry
+	note     string   // progress or result of a pull
+}
+
+// status compares the local and registry digests of a tag
+func (tag registryTag) status() (text, color string) {
+	t := currentTheme()
+	switch {
+	case tag.orphaned:
+		return "only local", t.Muted
+	case tag.err != nil:
+		return errorSummary(tag.err), t.Error
+	case tag.remote == "":
+		return "…", t.Muted
+	case !tag.present:
+		return "not pulled", t.Muted
+	case slices.Contains(tag.local, tag.remote):
+		return "✓ up to date", t.Success
+	case len(tag.local) == 0:
+		return "local image not pulled from here", t.Warning
+	default:
+		return "⟳ differs from the registry", t.Warning
+	}
+}
+
+// shortDigest shortens "sha256:abc..." to 12 hex digits
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	return digest[:min(12, len(digest))]
+}
+
+// showRegistries opens the registry browser, first asking which registry when several
+// are configured
+func (d *Dashboard) showRegistries(images *resourceTable) {
+	if len(d.cfg.Registries) == 0 {
+		showMessage(d.app, d.mainFlex, "📚 Registries",
+			fmt.Sprintf("No registries configured.\n\nAdd a \"registries\" list to %s to browse and pull from private registries.", config.Path()))
+		return
+	}
+	if len(d.cfg.Registries) == 1 {
+		d.showRegistryBrowser(&d.cfg.Registries[0], images)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(true)
+	for i := range d.cfg.Registries {
+		r := &d.cfg.Registries[i]
+		list.AddItem(r.Name, r.URL, 0, func() {
+			d.showRegistryBrowser(r, images)
+		})
+	}
+	list.SetBorder(true).
+		SetTitle(" 📚 Browse Registry (Enter to open, ESC to cancel) ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.GetColor(currentTheme().Info))
+	list.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(images.table)
+	})
+
+	showOverlay(d.app, d.mainFlex, list, 60, 2*list.GetItemCount()+4)
+}
+
+// showRegistryBrowser lists the repositories of a registry on the left and the tags of
+// the opened one on the right, each with its digest in the registry and locally. Space
+// marks tags and p pulls them.
+func (d *Dashboard) showRegistryBrowser(reg *config.Registry, images *resourceTable) {
+	t := currentTheme()
+	client := registry.New(reg)
+	host := reg.Host()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	repos := tview.NewTable().
+		SetSelectable(true, false)
+	repos.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📚 %s ", reg.Name)).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	tagTable := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	tagTable.SetBorder(true).
+		SetTitle(" Tags ").
+		SetBorderColor(tcell.GetColor(t.Border)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[black:green] Enter [-:-:-] Tags   [black:green] Tab [-:-:-] Switch pane   [black:green] Space [-:-:-] Mark   [black:green] p [-:-:-] Pull   [black:green] c [-:-:-] Copy ref   [black:green] F5 [-:-:-] Reload   [black:red] ESC [-:-:-] Close")
+
+	body := tview.NewFlex().
+		AddItem(repos, 40, 0, true).
+		AddItem(tagTable, 0, 1, false)
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+	var (
+		repository string // whose tags are shown
+		tags       []registryTag
+		marked     = make(map[string]bool)
+		pulling    bool
+	)
+	// loads stops the lookups of the shown repository
+	var loads context.CancelFunc = func() {}
+	setHeader := func(format string, args ...any) {
+		header.SetText(fmt.Sprintf(" [%s::b]%s[-:-:-] [%s]%s[-]\n ", t.Title, tview.Escape(reg.URL), t.Muted, tview.Escape(host)) +
+			fmt.Sprintf(format, args...))
+	}
+
+	// renderTags must be called on the UI goroutine
+	renderTags := func() {
+		row, _ := tagTable.GetSelection()
+		tagTable.Clear()
+		for col, h := range []string{" ", "TAG", "REGISTRY", "LOCAL", "STATUS"} {
+			tagTable.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		for i, tag := range tags {
+			mark := " "
+			if marked[tag.name] {
+				mark = "☑"
+			}
+			remote := shortDigest(tag.remote)
+			if tag.orphaned {
+				remote = "-"
+			}
+			var local []string
+			for _, digest := range tag.local {
+				local = append(local, shortDigest(digest))
+			}
+			switch {
+			case !tag.present:
+				local = []string{"-"}
+			case len(local) == 0:
+				local = []string{"no digest"}
+			}
+			status, color := tag.status()
+			if tag.note != "" {
+				status = tag.note
+			}
+			tagTable.SetCell(i+1, 0, tview.NewTableCell(mark).SetTextColor(tcell.GetColor(t.Accent)))
+			tagTable.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(tag.name)).SetTextColor(tcell.GetColor(t.Text)))
+			tagTable.SetCell(i+1, 2, tview.NewTableCell(remote).SetTextColor(tcell.GetColor(t.Muted)))
+			tagTable.SetCell(i+1, 3, tview.NewTableCell(strings.Join(local, ", ")).SetTextColor(tcell.GetColor(t.Muted)))
+			tagTable.SetCell(i+1, 4, tview.NewTableCell(tview.Escape(status)).SetTextColor(tcell.GetColor(color)).SetExpansion(1))
+		}
+		if row > len(tags) {
+			row = len(tags)
+		}
+		tagTable.Select(max(row, 1), 0)
+	}
+
+	// compare reads which tags are present locally and merges them into tags; it must be
+	// called on the UI goroutine, with the result of a docker.LocalDigests in the background
+	compare := func(local map[string][]string) {
+		seen := make(map[string]bool)
+		kept := tags[:0]
+		for _, tag := range tags {
+			if tag.orphaned {
+				continue
+			}
+			tag.local, tag.present = local[tag.name]
+			seen[tag.name] = true
+			kept = append(kept, tag)
+		}
+		tags = kept
+		var orphans []string
+		for name := range local {
+			if !seen[name] {
+				orphans = append(orphans, name)
+			}
+		}
+		slices.Sort(orphans)
+		for _, name := range orphans {
+			tags = append(tags, registryTag{name: name, local: local[name], present: true, orphaned: true})
+		}
+	}
+
+	openRepository := func(name string) {
+		loads()
+		var loadCtx context.Context
+		loadCtx, loads = context.WithCancel(ctx)
+		repository, tags = name, nil
+		clear(marked)
+		tagTable.SetTitle(fmt.Sprintf(" Tags of %s ", name))
+		renderTags()
+		setHeader("[%s]Listing the tags of %s...[-]", t.Muted, tview.Escape(name))
+
+		go func() {
+			names, err := client.Tags(loadCtx, name)
+			local, lerr := docker.LocalDigests(host + "/" + name)
+			if loadCtx.Err() != nil {
+				return
+			}
+			if err != nil {
+				d.app.QueueUpdateDraw(func() {
+					setHeader("[%s]%s[-]", t.Error, tview.Escape(errorSummary(err)))
+				})
+				return
+			}
+			d.app.QueueUpdateDraw(func() {
+				for _, n := range names {
+					tags = append(tags, registryTag{name: n})
+				}
+				if lerr == nil {
+					compare(local)
+				}
+				renderTags()
+				setHeader("[%s]%d tags in %s; comparing digests...[-]", t.Muted, len(names), tview.Escape(name))
+			})
+
+			var wg sync.WaitGroup
+			sem := make(chan struct{}, digestLookups)
+			for i, n := range names {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func() {
+					defer func() { <-sem; wg.Done() }()
+					digest, err := client.Digest(loadCtx, name, n)
+					if loadCtx.Err() != nil {
+						return
+					}
+					d.app.QueueUpdateDraw(func() {
+						if loadCtx.Err() == nil && i < len(tags) && tags[i].name == n {
+							tags[i].remote, tags[i].err = digest, err
+							renderTags()
+						}
+					})
+				}()
+			}
+			wg.Wait()
+			if loadCtx.Err() != nil {
+				return
+			}
+			d.app.QueueUpdateDraw(func() {
+				if loadCtx.Err() != nil {
+					return
+				}
+				var stale int
+				for _, tag := range tags {
+					if text, _ := tag.status(); strings.HasPrefix(text, "⟳") {
+						stale++
+					}
+				}
+				setHeader("[%s]%d tags in %s, %d differ from the local images[-]", t.Muted, len(names), tview.Escape(name), stale)
+			})
+		}()
+	}
+
+	loadCatalog := func() {
+		repos.Clear()
+		setHeader("[%s]Reading the catalog...[-]", t.Muted)
+		go func() {
+			names, err := client.Catalog(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			d.app.QueueUpdateDraw(func() {
+				if err != nil {
+					setHeader("[%s]%s[-]", t.Error, tview.Escape(errorSummary(err)))
+					return
+				}
+				for i, name := range names {
+					repos.SetCell(i, 0, tview.NewTableCell(tview.Escape(name)).SetReference(name).SetExpansion(1))
+				}
+				repos.SetTitle(fmt.Sprintf(" 📚 %s (%d) ", reg.Name, len(names)))
+				setHeader("[%s]%d repositories; Enter lists the tags of one[-]", t.Muted, len(names))
+			})
+		}()
+	}
+
+	// pull pulls the marked tags, or the selected one, one after another
+	pull := func() {
+		if repository == "" || pulling {
+			return
+		}
+		var picked []int
+		for i, tag := range tags {
+			if marked[tag.name] && !tag.orphaned {
+				picked = append(picked, i)
+			}
+		}
+		if len(picked) == 0 {
+			if row, _ := tagTable.GetSelection(); row >= 1 && row <= len(tags) && !tags[row-1].orphaned {
+				picked = []int{row - 1}
+			}
+		}
+		if len(picked) == 0 {
+			return
+		}
+
+		pulling = true
+		repo := repository
+		username, password := client.Credentials()
+		refs := make([]string, len(picked))
+		for j, i := range picked {
+			refs[j] = fmt.Sprintf("%s/%s:%s", host, repo, tags[i].name)
+			tags[i].note = "waiting to pull"
+		}
+		renderTags()
+		go func() {
+			var failed int
+			for j, i := range picked {
+				// Updates are dropped once another repository is shown
+				update := func(note string) {
+					d.app.QueueUpdateDraw(func() {
+						if repository == repo && i < len(tags) {
+							tags[i].note = note
+							renderTags()
+						}
+					})
+				}
+				update("pulling...")
+				err := docker.PullImage(d.refreshCtx, refs[j], username, password, func(current, total int64) {
+					if total > 0 {
+						update(fmt.Sprintf("pulling %3.0f%% of %s", float64(current)/float64(total)*100, docker.FormatBytes(uint64(total))))
+					}
+				})
+				if err != nil {
+					failed++
+					update("✗ " + errorSummary(err))
+				} else {
+					update("")
+				}
+			}
+
+			local, err := docker.LocalDigests(host + "/" + repo)
+			d.app.QueueUpdateDraw(func() {
+				pulling = false
+				images.refresh(d.app)
+				if failed > 0 {
+					showToast(d.app, toastWarning, fmt.Sprintf("%d of %d pulls from %s failed", failed, len(refs), reg.Name))
+				} else {
+					showToast(d.app, toastSuccess, fmt.Sprintf("Pulled %s", strings.Join(refs, ", ")))
+				}
+				if repository == repo && err == nil {
+					clear(marked)
+					compare(local)
+					renderTags()
+				}
+			})
+		}()
+	}
+
+	repos.SetSelectedFunc(func(row, column int) {
+		if name, ok := repos.GetCell(row, 0).GetReference().(string); ok {
+			openRepository(name)
+			d.app.SetFocus(tagTable)
+		}
+	})
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			cancel()
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(images.table)
+			return nil
+		case event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab:
+			if repos.HasFocus() {
+				d.app.SetFocus(tagTable)
+			} else {
+				d.app.SetFocus(repos)
+			}
+			return nil
+		case d.keys.Action(event) == actionRefresh:
+			if tagTable.HasFocus() && repository != "" {
+				openRepository(repository)
+			} else {
+				loadCatalog()
+			}
+			return nil
+		}
+		if !tagTable.HasFocus() {
+			return event
+		}
+		switch event.Rune() {
+		case ' ':
+			if row, _ := tagTable.GetSelection(); row >= 1 && row <= len(tags) && !tags[row-1].orphaned {
+				name := tags[row-1].name
+				marked[name] = !marked[name]
+				if !marked[name] {
+					delete(marked, name)
+				}
+				renderTags()
+				tagTable.Select(min(row+1, len(tags)), 0)
+			}
+		case 'p':
+			pull()
+		case 'c':
+			if row, _ := tagTable.GetSelection(); row >= 1 && row <= len(tags) {
+				copyToClipboard(d.app, fmt.Sprintf("%s/%s:%s", host, repository, tags[row-1].name))
+			}
+		default:
+			return event
+		}
+		return nil
+	})
+
+	renderTags()
+	loadCatalog()
+	setRoot(d.app, flex)
+	d.app.SetFocus(repos)
+}
@@ -0,0 +1,166 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// remediationPoll is how often the healthchecks of the containers that may be
+// restarted are read
+const remediationPoll = 10 * time.Second
+
+// Remediator decides when a failing container is restarted automatically
+type Remediator struct {
+	mu         sync.Mutex
+	containers []string
+	failures   int
+	cooldown   time.Duration
+	last       map[string]time.Time // container ID -> last automatic restart
+}
+
+// NewRemediator follows the remediation policy; a nil policy restarts nothing
+func NewRemediator(cfg *config.Remediation) *Remediator {
+	r := &Remediator{last: make(map[string]time.Time)}
+	r.failures, r.cooldown = cfg.Policy()
+	if cfg != nil {
+		r.containers = cfg.Containers
+	}
+	return r
+}
+
+// Enabled reports whether a container opted in to automatic restarts
+func (r *Remediator) Enabled(c docker.ContainerInfo) bool {
+	for _, name := range r.containers {
+		if namesContainer(name, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Due reports whether failures in a row call for a restart
+func (r *Remediator) Due(failures int) bool {
+	return failures >= r.failures
+}
+
+// Begin claims a restart of a container; it fails while the container is within the
+// cooldown of its previous automatic restart
+func (r *Remediator) Begin(id string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if last, ok := r.last[id]; ok && now.Sub(last) < r.cooldown {
+		return false
+	}
+	r.last[id] = now
+	return true
+}
+
+// Last returns when a container was last restarted automatically
+func (r *Remediator) Last(id string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last, ok := r.last[id]
+	return last, ok
+}
+
+// namesContainer reports whether a configured name, or host/name, is the container
+func namesContainer(name string, c docker.ContainerInfo) bool {
+	return name == c.Name || name == qualifiedName(c)
+}
+
+// startRemediation restarts opted-in containers whose Docker healthcheck keeps failing
+func (d *Dashboard) startRemediation() {
+	if len(d.remediation.containers) == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(remediationPoll)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			d.mu.RLock()
+			containers := d.containers
+			d.mu.RUnlock()
+			for _, c := range containers {
+				if c.State != "running" || !d.remediation.Enabled(c) {
+					continue
+				}
+				health, err := docker.GetHealth(d.refreshCtx, c.ID)
+				if err != nil || health == nil || !d.remediation.Due(health.FailingStreak) {
+					continue
+				}
+				reason := fmt.Sprintf("healthcheck failed %d times", health.FailingStreak)
+				if health.Output != "" {
+					reason += ": " + truncateString(health.Output, 80)
+				}
+				go d.remediate(c, reason)
+			}
+		}
+	}()
+}
+
+// remediateProbe restarts the container of a probe that failed often enough
+func (d *Dashboard) remediateProbe(p *config.HealthProbe, failures int) {
+	if !d.remediation.Due(failures) {
+		return
+	}
+
+	d.mu.RLock()
+	containers := d.containers
+	d.mu.RUnlock()
+	for _, c := range containers {
+		if namesContainer(p.Container, c) && d.remediation.Enabled(c) {
+			d.remediate(c, fmt.Sprintf("probe %s failed %d times", p.Target(), failures))
+			return
+		}
+	}
+}
+
+// remediate restarts a container, unless it was restarted automatically within the
+// cooldown, and reports it as a toast and on the Events tab
+func (d *Dashboard) remediate(c docker.ContainerInfo, reason string) {
+	now := time.Now()
+	if !d.remediation.Begin(c.ID, now) {
+		return
+	}
+	err := hooked("restart", c, func() error { return docker.RestartContainer(c.ID) })
+	if d.refreshCtx.Err() != nil {
+		return
+	}
+
+	name := qualifiedName(c)
+	d.app.QueueUpdateDraw(func() {
+		if err != nil {
+			showToast(d.app, toastWarning, fmt.Sprintf("Automatic restart of %s failed: %s", name, errorSummary(err)))
+			d.logAlert(now, history.SeverityCritical, "auto-restart failed", name, errorSummary(err))
+		} else {
+			showToast(d.app, toastWarning, fmt.Sprintf("Restarted %s: %s", name, reason))
+			d.logAlert(now, history.SeverityWarning, "auto-restart", name, reason)
+		}
+	})
+}
+
+// remediationDetails describes the restart policy of a container for the details panel
+func (d *Dashboard) remediationDetails(c docker.ContainerInfo) string {
+	if !d.remediation.Enabled(c) {
+		return ""
+	}
+	t := currentTheme()
+	details := fmt.Sprintf("\n\n[%s::b]Auto-restart:[-:-:-]\nafter %d failed checks", t.Accent, d.remediation.failures)
+	if last, ok := d.remediation.Last(c.ID); ok {
+		details += fmt.Sprintf("\n[%s]last %s[-]", t.Warning, last.Format("Jan 2 15:04:05"))
+	}
+	return details
+}
@@ -0,0 +1,208 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// resourceTable is a tab listing Docker objects (images, volumes, networks)
+type resourceTable struct {
+	table   *tview.Table
+	headers []string
+	load    func() (keys []string, rows [][]string, err error)
+	keys    []string
+	rows    [][]string
+	marked  map[string]bool // keys picked with toggleMark, for actions on several objects
+}
+
+func newResourceTable(title string, headers []string, load func() ([]string, [][]string, error)) *resourceTable {
+	r := &resourceTable{
+		table: tview.NewTable().
+			SetBorders(false).
+			SetSelectable(true, false).
+			SetFixed(1, 0),
+		headers: headers,
+		load:    load,
+		marked:  make(map[string]bool),
+	}
+	r.table.SetBorder(true).
+		SetTitle(title).
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(currentTheme().Border))
+	r.setHeaders()
+	return r
+}
+
+func (r *resourceTable) setHeaders() {
+	for col, h := range r.headers {
+		r.table.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(currentTheme().Highlight)).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+}
+
+// refresh reloads the table contents in the background
+func (r *resourceTable) refresh(app *tview.Application) {
+	go func() {
+		keys, rows, err := r.load()
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				r.table.Clear()
+				r.setHeaders()
+				r.table.SetCell(1, 0, tview.NewTableCell("Error: "+errorSummary(err)).SetTextColor(tcell.GetColor(currentTheme().Error)))
+				return
+			}
+
+			r.keys, r.rows = keys, rows
+			// Objects that are gone can't stay marked
+			present := make(map[string]bool, len(keys))
+			for _, key := range keys {
+				present[key] = true
+			}
+			for key := range r.marked {
+				if !present[key] {
+					delete(r.marked, key)
+				}
+			}
+			r.render()
+		})
+	}()
+}
+
+// render fills the table with the loaded rows; marked rows get a ☑ while any are marked
+func (r *resourceTable) render() {
+	t := currentTheme()
+	r.table.Clear()
+	r.setHeaders()
+	for i, row := range r.rows {
+		for col, value := range row {
+			color := tcell.GetColor(t.Title)
+			if col > 0 {
+				color = tcell.GetColor(t.Text)
+			}
+			if col == 0 && len(r.marked) > 0 {
+				if r.marked[r.keys[i]] {
+					value, color = "☑ "+value, tcell.GetColor(t.Success)
+				} else {
+					value = "☐ " + value
+				}
+			}
+			r.table.SetCell(i+1, col, tview.NewTableCell(value).SetTextColor(color))
+		}
+	}
+	if len(r.rows) == 0 {
+		r.table.SetCell(1, 0, tview.NewTableCell("(none)").SetTextColor(tcell.GetColor(t.Muted)))
+	}
+}
+
+// toggleMark marks or unmarks the object under the cursor and moves to the next row
+func (r *resourceTable) toggleMark() {
+	key := r.selectedKey()
+	if key == "" {
+		return
+	}
+	if r.marked[key] {
+		delete(r.marked, key)
+	} else {
+		r.marked[key] = true
+	}
+	r.render()
+	if row, _ := r.table.GetSelection(); row < len(r.rows) {
+		r.table.Select(row+1, 0)
+	}
+}
+
+// markedKeys returns the marked objects in table order, or the one under the cursor
+// when none are marked
+func (r *resourceTable) markedKeys() []string {
+	var keys []string
+	for _, key := range r.keys {
+		if r.marked[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		if key := r.selectedKey(); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// selectedKey returns the ID of the object under the cursor
+func (r *resourceTable) selectedKey() string {
+	row, _ := r.table.GetSelection()
+	if row < 1 || row > len(r.keys) {
+		return ""
+	}
+	return r.keys[row-1]
+}
+
+func loadImageRows() ([]string, [][]string, error) {
+	images, err := docker.ListImages()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	var rows [][]string
+	for _, img := range images {
+		keys = append(keys, img.ID)
+		rows = append(rows, []string{
+			strings.Join(img.Tags, ", "),
+			img.ID[:12],
+			img.Size,
+			img.Created,
+			fmt.Sprintf("%d", img.Containers),
+		})
+	}
+	return keys, rows, nil
+}
+
+func loadVolumeRows() ([]string, [][]string, error) {
+	volumes, err := docker.ListVolumes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	var rows [][]string
+	for _, v := range volumes {
+		keys = append(keys, v.Name)
+		rows = append(rows, []string{v.Name, v.Driver, v.Scope, v.Created, v.Mountpoint})
+	}
+	return keys, rows, nil
+}
+
+func loadNetworkRows() ([]string, [][]string, error) {
+	networks, err := docker.ListNetworks()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	var rows [][]string
+	for _, n := range networks {
+		subnets := strings.Join(n.Subnets, ", ")
+		if subnets == "" {
+			subnets = "-"
+		}
+		keys = append(keys, n.ID)
+		rows = append(rows, []string{
+			n.Name,
+			n.ID[:12],
+			n.Driver,
+			n.Scope,
+			subnets,
+			fmt.Sprintf("%d", n.Containers),
+		})
+	}
+	return keys, rows, nil
+}
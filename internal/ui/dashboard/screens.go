@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+// screenStack keeps detail screens as pages of the tabbed layout, so the tab bar and the
+// status bar stay in view while a screen is open
+type screenStack struct {
+	root    tview.Primitive
+	pages   *tview.Pages
+	tab     func() string // page of the current tab, shown when no screen covers it
+	changed func()        // called whenever a screen opens or closes
+	open    []screenPage
+	seq     int
+}
+
+// screenPage is one open screen
+type screenPage struct {
+	name    string
+	item    tview.Primitive
+	overlay bool // drawn over the page below instead of replacing it
+}
+
+// screens is the stack of the running dashboard; until it exists setRoot swaps the root
+var screens *screenStack
+
+// setRoot shows p in place of the current screen. The root layout closes every screen,
+// a screen that is already open closes the ones above it, and anything else opens on top.
+// Modals open over the page below, like overlays.
+func setRoot(app *tview.Application, p tview.Primitive) {
+	s := screens
+	if s == nil {
+		app.SetRoot(p, true)
+		return
+	}
+	switch i := s.index(p); {
+	case p == s.root:
+		s.truncate(0)
+	case i >= 0:
+		s.truncate(i + 1)
+	default:
+		_, modal := p.(*tview.Modal)
+		s.push(p, modal)
+	}
+	app.SetRoot(s.root, true)
+}
+
+// setOverlay opens p over the current screen, leaving it in view
+func setOverlay(app *tview.Application, p tview.Primitive) {
+	if screens == nil {
+		app.SetRoot(p, true)
+		return
+	}
+	screens.push(p, true)
+	app.SetRoot(screens.root, true)
+}
+
+// active reports whether a screen or overlay is open over the tabs
+func (s *screenStack) active() bool {
+	return s != nil && len(s.open) > 0
+}
+
+func (s *screenStack) index(p tview.Primitive) int {
+	for i, sp := range s.open {
+		if sp.item == p {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *screenStack) push(p tview.Primitive, overlay bool) {
+	s.seq++
+	name := fmt.Sprintf("screen-%d", s.seq)
+	s.pages.AddPage(name, p, true, true)
+	s.open = append(s.open, screenPage{name: name, item: p, overlay: overlay})
+	s.show()
+}
+
+// truncate closes every screen from the n-th up
+func (s *screenStack) truncate(n int) {
+	for _, sp := range s.open[n:] {
+		s.pages.RemovePage(sp.name)
+	}
+	s.open = s.open[:n]
+	s.show()
+}
+
+// show hides whatever the topmost full screen covers
+func (s *screenStack) show() {
+	cover := -1
+	for i, sp := range s.open {
+		if !sp.overlay {
+			cover = i
+		}
+	}
+	if cover < 0 {
+		s.pages.ShowPage(s.tab())
+	} else {
+		s.pages.HidePage(s.tab())
+	}
+	for i, sp := range s.open {
+		if i >= cover {
+			s.pages.ShowPage(sp.name)
+		} else {
+			s.pages.HidePage(sp.name)
+		}
+	}
+	if s.changed != nil {
+		s.changed()
+	}
+}
@@ -0,0 +1,51 @@
+package dashboard
+
+import (
+	"strings"
+
+	"devops-dashboard/internal/config"
+)
+
+// secretMask stands in for the value of a secret env var
+const secretMask = "••••••••"
+
+// defaultSecretEnv are the parts of env var names that mark their values as secrets
+var defaultSecretEnv = []string{"PASSWORD", "TOKEN", "KEY", "SECRET"}
+
+// secretEnv is the secret_env setting of the config, upper-cased
+var secretEnv = defaultSecretEnv
+
+// setSecretEnv installs the secret_env setting of the config
+func setSecretEnv(cfg *config.Config) {
+	secretEnv = defaultSecretEnv
+	if len(cfg.SecretEnv) == 0 {
+		return
+	}
+	secretEnv = nil
+	for _, part := range cfg.SecretEnv {
+		if part = strings.ToUpper(strings.TrimSpace(part)); part != "" {
+			secretEnv = append(secretEnv, part)
+		}
+	}
+}
+
+// isSecretEnv reports whether an env var name looks like it holds a credential,
+// e.g. DB_PASSWORD or GITHUB_TOKEN
+func isSecretEnv(name string) bool {
+	name = strings.ToUpper(name)
+	for _, part := range secretEnv {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskEnv returns the value of an env var as shown on screen: masked for secrets
+// unless reveal is set. Empty values stay empty, so unset secrets can be told apart.
+func maskEnv(name, value string, reveal bool) string {
+	if reveal || value == "" || !isSecretEnv(name) {
+		return value
+	}
+	return secretMask
+}
@@ -0,0 +1,270 @@
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// showSecurity shows the capabilities of a container next to its seccomp and AppArmor
+// profiles. Space ticks capabilities on and off as a checklist, and a recreates the
+// container with the ticked ones.
+func (d *Dashboard) showSecurity(container docker.ContainerInfo) {
+	t := currentTheme()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+	header.SetText(fmt.Sprintf(" [%s]Reading how %s is confined...[-]", t.Muted, tview.Escape(container.Name)))
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🔐 Capabilities: %s ", container.Name)).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	help := "[black:green] Space [-:-:-] Tick   [black:green] p [-:-:-] Privileged   [black:green] x [-:-:-] Reset   [black:green] a [-:-:-] Recreate with changes   [black:red] ESC [-:-:-] Back"
+	if d.cfg.Kiosk {
+		help = "[black:red] ESC [-:-:-] Back"
+	}
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(help)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 3, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var (
+		profile    *docker.SecurityProfile
+		ticked     map[string]bool
+		privileged bool
+	)
+
+	// configured is what the cap-add and cap-drop settings grant without privileged mode
+	configured := func() []string {
+		return docker.EffectiveCapabilities(false, profile.CapAdd, profile.CapDrop)
+	}
+
+	// changes lists the capabilities whose tick differs from the configuration
+	changes := func() (added, removed []string) {
+		if privileged {
+			return nil, nil
+		}
+		granted := configured()
+		for _, c := range docker.Capabilities {
+			switch has := slices.Contains(granted, c); {
+			case ticked[c] && !has:
+				added = append(added, c)
+			case !ticked[c] && has:
+				removed = append(removed, c)
+			}
+		}
+		return added, removed
+	}
+
+	render := func() {
+		row, _ := table.GetSelection()
+		table.Clear()
+		for col, h := range []string{"", "CAPABILITY", "CONFIG", "BOUNDING", "PID 1", ""} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		granted := docker.EffectiveCapabilities(profile.Privileged, profile.CapAdd, profile.CapDrop)
+		baseline := configured()
+		for i, c := range docker.Capabilities {
+			box, color := "☐", t.Muted
+			if ticked[c] || privileged {
+				box, color = "☑", t.Success
+			}
+
+			source := ""
+			switch {
+			case profile.Privileged:
+				source = "privileged"
+			case slices.Contains(profile.CapAdd, c) && slices.Contains(granted, c):
+				source = "added"
+			case slices.Contains(granted, c):
+				source = "default"
+			case slices.Contains(docker.DefaultCapabilities, c):
+				source = "dropped"
+			}
+
+			note := ""
+			switch {
+			case privileged:
+			case ticked[c] && !slices.Contains(baseline, c):
+				note, color = "→ add", t.Warning
+			case !ticked[c] && slices.Contains(baseline, c):
+				note, color = "→ drop", t.Warning
+			}
+
+			table.SetCell(i+1, 0, tview.NewTableCell(box).SetTextColor(tcell.GetColor(color)))
+			table.SetCell(i+1, 1, tview.NewTableCell(c).SetTextColor(tcell.GetColor(color)))
+			table.SetCell(i+1, 2, tview.NewTableCell(source).SetTextColor(tcell.GetColor(t.Muted)))
+			table.SetCell(i+1, 3, tview.NewTableCell(procMark(profile.Bounding, c)).SetAlign(tview.AlignCenter))
+			table.SetCell(i+1, 4, tview.NewTableCell(procMark(profile.Running, c)).SetAlign(tview.AlignCenter))
+			table.SetCell(i+1, 5, tview.NewTableCell(note).SetTextColor(tcell.GetColor(t.Warning)).SetExpansion(1))
+		}
+		table.Select(max(row, 1), 0)
+
+		user := profile.User
+		if user == "" {
+			user = "root"
+		}
+		seccomp := profile.Seccomp
+		if profile.RunningSeccomp != "" {
+			seccomp += fmt.Sprintf(" (running: %s)", profile.RunningSeccomp)
+		}
+		apparmor := profile.AppArmor
+		if apparmor == "" {
+			apparmor = "none"
+		}
+		if profile.RunningLSM != "" {
+			apparmor += fmt.Sprintf(" (running: %s)", profile.RunningLSM)
+		}
+		privilegedText := fmt.Sprintf("[%s]no[-]", t.Success)
+		if profile.Privileged {
+			privilegedText = fmt.Sprintf("[%s::b]yes, every capability and no seccomp[-:-:-]", t.Error)
+		}
+		if privileged != profile.Privileged {
+			privilegedText += fmt.Sprintf(" [%s]→ %s[-]", t.Warning, map[bool]string{true: "yes", false: "no"}[privileged])
+		}
+		added, removed := changes()
+		pending := ""
+		if len(added)+len(removed) > 0 || privileged != profile.Privileged {
+			pending = fmt.Sprintf("   [%s]%d to add, %d to drop[-]", t.Warning, len(added), len(removed))
+		}
+		header.SetText(fmt.Sprintf(
+			" [%s::b]%s[-:-:-]   %d of %d capabilities   privileged: %s%s\n"+
+				" Seccomp: %s   AppArmor: %s\n"+
+				" [%s]User %s   read-only rootfs: %v   no-new-privileges: %v[-]",
+			t.Accent, tview.Escape(container.Name), len(granted), len(docker.Capabilities), privilegedText, pending,
+			tview.Escape(seccomp), tview.Escape(apparmor),
+			t.Muted, tview.Escape(user), profile.ReadOnly, profile.NoNewPrivileges))
+	}
+
+	reset := func() {
+		ticked = make(map[string]bool)
+		for _, c := range configured() {
+			ticked[c] = true
+		}
+		privileged = profile.Privileged
+	}
+
+	// apply recreates the container with the ticked capabilities, keeping everything else;
+	// kiosk mode only shows the screen
+	apply := func() {
+		if !d.kioskAllows(actionRecreate) {
+			return
+		}
+		added, removed := changes()
+		if len(added)+len(removed) == 0 && privileged == profile.Privileged {
+			return
+		}
+		var want []string
+		for _, c := range docker.Capabilities {
+			if ticked[c] {
+				want = append(want, c)
+			}
+		}
+		caps := &docker.CapabilitySet{Privileged: privileged}
+		caps.Add, caps.Drop = docker.CapabilityChanges(want)
+
+		var summary []string
+		if privileged != profile.Privileged {
+			summary = append(summary, fmt.Sprintf("privileged: %v", privileged))
+		}
+		for _, c := range added {
+			summary = append(summary, "+"+c)
+		}
+		for _, c := range removed {
+			summary = append(summary, "-"+c)
+		}
+		showConfirmation(d.app, flex,
+			fmt.Sprintf("Recreate '%s' with %s?\n\nThe container is stopped and replaced. If the new one fails to start, the old one is restored.",
+				container.Name, strings.Join(summary, " ")),
+			func() {
+				setRoot(d.app, d.mainFlex)
+				go func() {
+					spec, err := docker.GetContainerSpec(container.ID)
+					d.app.QueueUpdateDraw(func() {
+						if err != nil {
+							showError(d.app, d.mainFlex, "Error", err)
+							return
+						}
+						spec.Capabilities = caps
+						d.recreateContainer(container, *spec)
+					})
+				}()
+			})
+	}
+
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			setRoot(d.app, d.mainFlex)
+			return nil
+		case profile == nil || d.cfg.Kiosk:
+			return event
+		case event.Rune() == ' ':
+			row, _ := table.GetSelection()
+			if c := docker.Capabilities[row-1]; !privileged {
+				ticked[c] = !ticked[c]
+				render()
+			}
+			return nil
+		case event.Rune() == 'p':
+			privileged = !privileged
+			render()
+			return nil
+		case event.Rune() == 'x':
+			reset()
+			render()
+			return nil
+		case event.Rune() == 'a':
+			apply()
+			return nil
+		}
+		return event
+	})
+
+	go func() {
+		result, err := docker.GetSecurityProfile(container.ID)
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				header.SetText(fmt.Sprintf(" [%s]Could not read %s: %s[-]", t.Error, tview.Escape(container.Name), tview.Escape(errorSummary(err))))
+				return
+			}
+			profile = result
+			reset()
+			render()
+		})
+	}()
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
+
+// procMark shows whether a capability read from /proc is set, "?" when it couldn't be read
+func procMark(caps []string, c string) string {
+	switch {
+	case caps == nil:
+		return "?"
+	case slices.Contains(caps, c):
+		return "✓"
+	}
+	return ""
+}
@@ -1,7 +1,11 @@
 package dashboard
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,22 +15,63 @@ import (
 	"devops-dashboard/internal/docker"
 )
 
+// CommandHistory holds the commands run in the shell, oldest first. With a path it is
+// loaded from and saved to that file, keeping at most limit commands.
 type CommandHistory struct {
 	commands []string
 	index    int
+	path     string
+	limit    int
 }
 
-func (h *CommandHistory) Add(cmd string) {
+// loadCommandHistory reads the history saved at path; a missing file starts an empty one
+func loadCommandHistory(path string, limit int) (*CommandHistory, error) {
+	h := &CommandHistory{path: path, limit: limit}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return h, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.commands = append(h.commands, line)
+		}
+	}
+	h.trim()
+	h.index = len(h.commands)
+	return h, nil
+}
+
+// Add appends a command and saves the history if it has a file
+func (h *CommandHistory) Add(cmd string) error {
 	if cmd == "" {
-		return
+		return nil
 	}
 	// Don't add duplicates of last command
 	if len(h.commands) > 0 && h.commands[len(h.commands)-1] == cmd {
 		h.index = len(h.commands)
-		return
+		return nil
 	}
 	h.commands = append(h.commands, cmd)
+	h.trim()
 	h.index = len(h.commands)
+	return h.save()
+}
+
+// trim drops the oldest commands beyond the limit
+func (h *CommandHistory) trim() {
+	if h.limit > 0 && len(h.commands) > h.limit {
+		h.commands = slices.Clone(h.commands[len(h.commands)-h.limit:])
+	}
+}
+
+func (h *CommandHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, []byte(strings.Join(h.commands, "\n")+"\n"), 0o600)
 }
 
 func (h *CommandHistory) Previous() string {
@@ -51,20 +96,34 @@ func (h *CommandHistory) Next() string {
 	return ""
 }
 
+// Search returns the newest command before position before that contains query, and its
+// position; -1 when there is none
+func (h *CommandHistory) Search(query string, before int) (int, string) {
+	for i := min(before, len(h.commands)) - 1; i >= 0; i-- {
+		if strings.Contains(h.commands[i], query) {
+			return i, h.commands[i]
+		}
+	}
+	return -1, ""
+}
+
 func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, containerID string, containers []docker.ContainerInfo) {
+	showShell(app, mainView, containerID, containers, docker.ExecOptions{})
+}
+
+// showShell runs the interactive shell with every command executed with opts
+func showShell(app *tview.Application, mainView tview.Primitive, containerID string, containers []docker.ContainerInfo, opts docker.ExecOptions) {
 	// Get container name
-	containerName := containerID[:12]
+	container := docker.ContainerInfo{ID: containerID, Name: containerID[:12]}
 	for _, c := range containers {
 		if c.ID == containerID {
-			containerName = c.Name
+			container = c
 			break
 		}
 	}
+	containerName := container.Name
 
-	history := &CommandHistory{
-		commands: []string{},
-		index:    0,
-	}
+	history, historyErr := openCommandHistory(container)
 
 	// Output view (terminal-like display)
 	outputView := tview.NewTextView().
@@ -75,8 +134,12 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 			app.Draw()
 		})
 
+	title := fmt.Sprintf(" 🖥️  Shell: %s ", containerName)
+	if label := execOptionsLabel(opts); label != "" {
+		title = fmt.Sprintf(" 🖥️  Shell: %s (%s) ", containerName, label)
+	}
 	outputView.SetBorder(true).
-		SetTitle(fmt.Sprintf(" 🖥️  Shell: %s ", containerName)).
+		SetTitle(title).
 		SetBorderPadding(1, 1, 2, 2).
 		SetBorderColor(tcell.ColorGreen)
 
@@ -120,6 +183,9 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 		statusBar.SetText(fmt.Sprintf("[black:%s] %s [-:-:-]", color, status))
 	}
 	updateStatus("Ready", "green")
+	if historyErr != nil {
+		updateStatus("Command history not loaded: "+tview.Escape(historyErr.Error()), "yellow")
+	}
 
 	// Control bar
 	controlBar := tview.NewTextView().
@@ -128,8 +194,11 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 	controlBar.SetText(
 		"[black:green] Enter [-:-:-] Execute   " +
 			"[black:cyan] ↑/↓ [-:-:-] History   " +
+			"[black:cyan] Ctrl+R [-:-:-] Search history   " +
+			"[black:cyan] Tab [-:-:-] Complete   " +
 			"[black:yellow] 1-9 [-:-:-] Quick Cmd   " +
 			"[black:magenta] Ctrl+C [-:-:-] Clear   " +
+			"[black:aqua] F2 [-:-:-] Colors   " +
 			"[black:red] ESC [-:-:-] Back")
 
 	// Layout
@@ -147,12 +216,15 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 	// Command execution counter
 	commandCount := 0
 
+	// ANSI colors in the output are shown unless switched off with F2
+	colors := true
+
 	// Welcome message
 	welcomeMsg := fmt.Sprintf(
 		"[::b][green]Interactive Shell Session Started[-:-:-]\n"+
-			"[cyan]Container:[-] [white]%s[-]\n"+
-			"[cyan]ID:[-] [white]%s[-]\n"+
-			"[cyan]Time:[-] [white]%s[-]\n\n"+
+			"[cyan]Container:[-] [-]%s[-]\n"+
+			"[cyan]ID:[-] [-]%s[-]\n"+
+			"[cyan]Time:[-] [-]%s[-]\n\n"+
 			"[yellow]Type commands and press Enter to execute[-]\n"+
 			"[gray]Use ↑/↓ for command history[-]\n\n"+
 			"────────────────────────────────────\n\n",
@@ -180,7 +252,7 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 		}
 
 		cmd = strings.TrimSpace(cmd)
-		history.Add(cmd)
+		historyErr := history.Add(cmd)
 		commandCount++
 
 		// Add command to output
@@ -193,22 +265,26 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 
 		// Execute in background
 		go func() {
-			output, err := docker.ExecCommand(containerID, cmd)
+			output, err := docker.ExecCommandWith(containerID, cmd, opts)
 
 			app.QueueUpdateDraw(func() {
 				currentText := outputView.GetText(false)
 
 				if err != nil {
-					currentText += fmt.Sprintf("[red]Error: %s[-]\n\n", err.Error())
+					currentText += fmt.Sprintf("[red]Error: %s[-]\n\n", errorText(err))
 					updateStatus("Error", "red")
 				} else {
-					// Color code output
 					if output == "" {
 						output = "[gray](no output)[-]"
+					} else {
+						output = renderANSI(output, colors)
 					}
-					currentText += fmt.Sprintf("[white]%s[-]\n", output)
+					currentText += fmt.Sprintf("[-]%s[-]\n", output)
 					updateStatus(fmt.Sprintf("✓ Command #%d completed", commandCount), "green")
 				}
+				if historyErr != nil {
+					updateStatus("Command history not saved: "+tview.Escape(historyErr.Error()), "yellow")
+				}
 
 				currentText += "────────────────────────────────────\n\n"
 				outputView.SetText(currentText)
@@ -219,9 +295,102 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 		commandInput.SetText("")
 	}
 
+	// Tab completes programs and paths; completion runs ls in the container, so it
+	// happens in the background and is dropped if the input changed meanwhile
+	completer := newShellCompleter(containerID, opts)
+	completeInput := func() {
+		text := commandInput.GetText()
+		go func() {
+			completed, candidates, err := completer.complete(text)
+			app.QueueUpdateDraw(func() {
+				if commandInput.GetText() != text {
+					return
+				}
+				switch {
+				case err != nil:
+					updateStatus("Completion failed: "+tview.Escape(errorSummary(err)), "yellow")
+				case len(candidates) > 0:
+					shown := candidates
+					if len(shown) > 20 {
+						shown = shown[:20]
+					}
+					list := strings.Join(shown, "  ")
+					if len(candidates) > len(shown) {
+						list += fmt.Sprintf("  … (%d more)", len(candidates)-len(shown))
+					}
+					outputView.SetText(outputView.GetText(false) + fmt.Sprintf("[gray]%s[-]\n", tview.Escape(list)))
+					outputView.ScrollToEnd()
+				default:
+					commandInput.SetText(completed)
+				}
+			})
+		}()
+	}
+
+	// Ctrl-R searches the history backwards for commands containing what is typed, like
+	// bash's reverse-i-search; the match is shown in the input
+	var (
+		searching bool
+		query     string
+		matchAt   int    // position of the shown match in the history
+		saved     string // the input from before the search, restored on cancel
+	)
+	showSearch := func(found bool) {
+		label := "(reverse-i-search)"
+		if !found {
+			label = "(failed reverse-i-search)"
+		}
+		commandInput.SetLabel(fmt.Sprintf("%s`%s': ", label, query))
+	}
+	search := func(before int) {
+		i, cmd := history.Search(query, before)
+		if i >= 0 {
+			matchAt = i
+			commandInput.SetText(cmd)
+		}
+		showSearch(i >= 0)
+	}
+	endSearch := func() {
+		searching = false
+		commandInput.SetLabel("$ ")
+	}
+
 	// Command input handler
 	commandInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if searching {
+			switch event.Key() {
+			case tcell.KeyCtrlR:
+				search(matchAt)
+				return nil
+			case tcell.KeyEscape, tcell.KeyCtrlG:
+				commandInput.SetText(saved)
+				endSearch()
+				return nil
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if r := []rune(query); len(r) > 0 {
+					query = string(r[:len(r)-1])
+					search(len(history.commands))
+				}
+				return nil
+			case tcell.KeyRune:
+				query += string(event.Rune())
+				// The current match stays if it still contains the query
+				search(matchAt + 1)
+				return nil
+			}
+			// Any other key, like Enter or an arrow, takes the match and acts as usual
+			endSearch()
+		}
+
 		switch event.Key() {
+		case tcell.KeyTab:
+			completeInput()
+			return nil
+		case tcell.KeyCtrlR:
+			searching, query, saved = true, "", commandInput.GetText()
+			matchAt = len(history.commands)
+			showSearch(true)
+			return nil
 		case tcell.KeyUp:
 			// Previous command in history
 			if cmd := history.Previous(); cmd != "" {
@@ -233,7 +402,7 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 			commandInput.SetText(history.Next())
 			return nil
 		case tcell.KeyEscape:
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		case tcell.KeyCtrlC:
 			// Clear output
@@ -241,6 +410,14 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 			commandCount = 0
 			updateStatus("Cleared", "green")
 			return nil
+		case tcell.KeyF2:
+			colors = !colors
+			if colors {
+				updateStatus("Colors on for new output", "green")
+			} else {
+				updateStatus("Colors off for new output", "green")
+			}
+			return nil
 		}
 
 		// Quick commands (1-9)
@@ -261,7 +438,7 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 	// Output view key handling
 	outputView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape {
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		}
 		// Focus back to input for typing
@@ -269,7 +446,7 @@ func ShowInteractiveShell(app *tview.Application, mainView tview.Primitive, cont
 		return event
 	})
 
-	app.SetRoot(flex, true)
+	setRoot(app, flex)
 	app.SetFocus(commandInput)
 }
 
@@ -279,9 +456,10 @@ func ShowShellOptionsMenu(app *tview.Application, mainView tview.Primitive, cont
 
 	// Get container name
 	containerName := containerID[:12]
+	container := docker.ContainerInfo{ID: containerID, Name: containerName}
 	for _, c := range containers {
 		if c.ID == containerID {
-			containerName = c.Name
+			containerName, container = c.Name, c
 			break
 		}
 	}
@@ -291,39 +469,150 @@ func ShowShellOptionsMenu(app *tview.Application, mainView tview.Primitive, cont
 		SetBorderColor(tcell.ColorGreen).
 		SetBorderPadding(1, 1, 2, 2)
 
-	menu.AddItem("⚡ Interactive Shell", "Run commands interactively with history", '1', func() {
-		ShowInteractiveShell(app, mainView, containerID, containers)
-	})
+	// The shells are looked up in the background; until then commands run with /bin/sh
+	var (
+		shell    docker.Shell
+		shells   []docker.Shell
+		shellErr error
+	)
+
+	// withShell runs fn unless the container turned out to have no shell at all
+	withShell := func(fn func()) func() {
+		return func() {
+			if errors.Is(shellErr, docker.ErrNoShell) {
+				showError(app, menu, "🐚 No Shell", shellErr)
+				return
+			}
+			fn()
+		}
+	}
 
-	menu.AddItem("📝 Quick Command", "Execute a single command and return", '2', func() {
-		showQuickCommand(app, mainView, containerID, containerName)
-	})
+	builtinShell := func() {
+		showShell(app, mainView, containerID, containers, docker.ExecOptions{Shell: shell})
+	}
+	terminalShell := func() {
+		if err := openTerminalShell(containerID, containerName, shell); err != nil {
+			showError(app, menu, "🪟 Terminal Shell", err)
+			return
+		}
+		setRoot(app, mainView)
+		showToast(app, toastSuccess, fmt.Sprintf("Opened a shell in %s in a new %s", containerName, terminalPlace()))
+	}
+
+	// With shell_terminal set inside tmux or screen, interactive shells get a real terminal
+	inTerminal := shellTerminal != "" && multiplexer() != ""
+	if inTerminal {
+		menu.AddItem("⚡ Interactive Shell", "Full terminal in a new "+terminalPlace(), '1', withShell(terminalShell))
+	} else {
+		menu.AddItem("⚡ Interactive Shell", "Run commands interactively with history", '1', withShell(builtinShell))
+	}
+
+	menu.AddItem("📝 Quick Command", "Execute a single command and return", '2', withShell(func() {
+		showQuickCommand(app, mainView, containerID, containerName, shell)
+	}))
 
 	menu.AddItem("📂 File Browser", "Browse container filesystem", '3', func() {
 		showFileBrowser(app, mainView, containerID, containerName)
 	})
 
-	menu.AddItem("🔧 System Info", "Get container system information", '4', func() {
-		showSystemInfo(app, mainView, containerID, containerName)
+	menu.AddItem("🔧 System Info", "Get container system information", '4', withShell(func() {
+		showSystemInfo(app, mainView, containerID, containerName, shell)
+	}))
+
+	menu.AddItem("🛠  Advanced Exec", "Run as another user, in another directory, with extra env, privileged or with a TTY", '5', withShell(func() {
+		showAdvancedExec(app, mainView, containerID, containerName, containers, shell)
+	}))
+
+	const shellItem = 5
+	menu.AddItem("🐚 Shell: detecting...", "Choose the shell commands run with", '6', withShell(func() {
+		if len(shells) == 0 {
+			return
+		}
+		selectShell(app, menu, shells, func(s docker.Shell) {
+			shell = s
+			menu.SetItemText(shellItem, fmt.Sprintf("🐚 Shell: %s (%s)", s.Name, s.Path), "Choose the shell commands run with")
+		})
+	}))
+
+	menu.AddItem("🔗 Connectivity Test", "Ping or connect to another container or host, with DNS and latency", '7', withShell(func() {
+		showConnectivityTest(app, mainView, containerID, containerName, containers, shell)
+	}))
+
+	// Signals go through the API, so they also reach containers without a shell
+	menu.AddItem("📡 Send Signal", "Send SIGHUP, SIGUSR1 or any signal to the main process, e.g. to reload nginx", '9', func() {
+		showSignalMenu(app, menu, containerName, func(signal string) {
+			signalContainer(app, mainView, container, signal)
+		})
 	})
 
+	// Inside tmux or screen, the other kind of shell stays one key away
+	switch {
+	case inTerminal:
+		menu.AddItem("⚡ Built-in Shell", "Run commands interactively with history, inside DockPulse", '8', withShell(builtinShell))
+	case multiplexer() != "":
+		menu.AddItem("🪟 Terminal Shell", "Full terminal in a new "+terminalPlace()+" while DockPulse stays open", '8', withShell(terminalShell))
+	}
+
 	menu.AddItem("❌ Cancel", "Go back", 'q', func() {
-		app.SetRoot(mainView, true)
+		setRoot(app, mainView)
 	})
 
 	menu.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() == tcell.KeyEscape {
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		}
 		return event
 	})
 
-	app.SetRoot(menu, true)
+	go func() {
+		found, err := docker.DetectShells(containerID)
+		app.QueueUpdateDraw(func() {
+			shells, shellErr = found, err
+			switch {
+			case err != nil && errors.Is(err, docker.ErrNoShell):
+				menu.SetItemText(shellItem, "🐚 Shell: none found", errorSummary(err))
+			case err != nil:
+				// Leave /bin/sh in place; the exec itself reports what is wrong
+				menu.SetItemText(shellItem, "🐚 Shell: /bin/sh", "Shells could not be detected: "+errorSummary(err))
+			default:
+				shell = found[0]
+				menu.SetItemText(shellItem, fmt.Sprintf("🐚 Shell: %s (%s)", shell.Name, shell.Path),
+					fmt.Sprintf("Choose the shell commands run with, %d available", len(found)))
+			}
+		})
+	}()
+
+	setRoot(app, menu)
 	app.SetFocus(menu)
 }
 
-func showQuickCommand(app *tview.Application, mainView tview.Primitive, containerID, containerName string) {
+// selectShell lists the shells found in a container on top of menu and calls choose
+// with the one picked
+func selectShell(app *tview.Application, menu *tview.List, shells []docker.Shell, choose func(docker.Shell)) {
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, s := range shells {
+		list.AddItem(fmt.Sprintf("%s  [gray]%s[-]", s.Name, s.Path), "", 0, nil)
+	}
+	list.SetBorder(true).
+		SetTitle(" 🐚 Shell (Enter select, ESC cancel) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.ColorGreen)
+
+	back := func() {
+		setRoot(app, menu)
+		app.SetFocus(menu)
+	}
+	list.SetSelectedFunc(func(i int, _, _ string, _ rune) {
+		choose(shells[i])
+		back()
+	})
+	list.SetDoneFunc(back)
+
+	showOverlay(app, menu, list, 40, len(shells)+2)
+}
+
+func showQuickCommand(app *tview.Application, mainView tview.Primitive, containerID, containerName string, shell docker.Shell) {
 	cmdInput := tview.NewInputField().
 		SetLabel("Command: ").
 		SetFieldWidth(50)
@@ -340,28 +629,149 @@ func showQuickCommand(app *tview.Application, mainView tview.Primitive, containe
 			modal := tview.NewModal().
 				SetText(fmt.Sprintf("Executing: %s\n\nPlease wait...", cmd))
 			modal.SetBorder(true).SetTitle(" ⏳ Executing ")
-			app.SetRoot(modal, false)
+			setRoot(app, modal)
 
 			go func() {
-				output, err := docker.ExecCommand(containerID, cmd)
+				output, err := docker.ExecCommandWith(containerID, cmd, docker.ExecOptions{Shell: shell})
 				app.QueueUpdateDraw(func() {
-					result := output
+					result := renderANSI(output, true)
 					if err != nil {
-						result = fmt.Sprintf("[red]Error:[-]\n%s", err.Error())
+						result = fmt.Sprintf("[red]Error:[-]\n%s", errorText(err))
 					}
 					showMessage(app, mainView, "Command Output", result)
 				})
 			}()
 		}).
 		AddButton("Cancel", func() {
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 		})
 
 	form.SetBorder(true).
 		SetTitle(fmt.Sprintf(" 📝 Quick Command: %s ", containerName)).
 		SetBorderColor(ColorCyan)
 
-	app.SetRoot(form, true)
+	setRoot(app, form)
+}
+
+// showAdvancedExec asks for a command and the exec options to run it with, either once
+// or for a whole shell session
+func showAdvancedExec(app *tview.Application, mainView tview.Primitive, containerID, containerName string, containers []docker.ContainerInfo, shell docker.Shell) {
+	cmdInput := tview.NewInputField().
+		SetLabel("Command: ").
+		SetFieldWidth(50)
+	userInput := tview.NewInputField().
+		SetLabel("User: ").
+		SetPlaceholder("container default, e.g. root or 1000:1000").
+		SetFieldWidth(50)
+	dirInput := tview.NewInputField().
+		SetLabel("Working dir: ").
+		SetPlaceholder("container default").
+		SetFieldWidth(50)
+	envInput := tview.NewInputField().
+		SetLabel("Env: ").
+		SetPlaceholder("KEY=value OTHER=value").
+		SetFieldWidth(50)
+	privileged := tview.NewCheckbox().SetLabel("Privileged: ")
+	tty := tview.NewCheckbox().SetLabel("TTY: ")
+
+	var form *tview.Form
+
+	// options reads the form; the error names the first malformed env entry
+	options := func() (docker.ExecOptions, error) {
+		env := strings.Fields(envInput.GetText())
+		for _, e := range env {
+			if key, _, ok := strings.Cut(e, "="); !ok || key == "" {
+				return docker.ExecOptions{}, fmt.Errorf("env entry %q is not KEY=value", e)
+			}
+		}
+		return docker.ExecOptions{
+			User:       strings.TrimSpace(userInput.GetText()),
+			WorkingDir: strings.TrimSpace(dirInput.GetText()),
+			Env:        env,
+			Privileged: privileged.IsChecked(),
+			TTY:        tty.IsChecked(),
+			Shell:      shell,
+		}, nil
+	}
+
+	form = tview.NewForm().
+		AddFormItem(cmdInput).
+		AddFormItem(userInput).
+		AddFormItem(dirInput).
+		AddFormItem(envInput).
+		AddFormItem(privileged).
+		AddFormItem(tty).
+		AddButton("Execute", func() {
+			cmd := cmdInput.GetText()
+			if cmd == "" {
+				return
+			}
+			opts, err := options()
+			if err != nil {
+				showMessage(app, form, "Advanced Exec", err.Error())
+				return
+			}
+
+			modal := tview.NewModal().
+				SetText(fmt.Sprintf("Executing: %s\n\nPlease wait...", cmd))
+			modal.SetBorder(true).SetTitle(" ⏳ Executing ")
+			setRoot(app, modal)
+
+			go func() {
+				output, err := docker.ExecCommandWith(containerID, cmd, opts)
+				app.QueueUpdateDraw(func() {
+					result := renderANSI(output, true)
+					if err != nil {
+						result = fmt.Sprintf("[red]Error:[-]\n%s\n\n%s", errorText(err), result)
+					}
+					showMessage(app, form, "Command Output", result)
+				})
+			}()
+		}).
+		AddButton("Open Shell", func() {
+			opts, err := options()
+			if err != nil {
+				showMessage(app, form, "Advanced Exec", err.Error())
+				return
+			}
+			showShell(app, mainView, containerID, containers, opts)
+		}).
+		AddButton("Cancel", func() {
+			setRoot(app, mainView)
+		})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🛠  Advanced Exec: %s ", containerName)).
+		SetBorderColor(ColorCyan)
+	form.SetCancelFunc(func() {
+		setRoot(app, mainView)
+	})
+
+	setRoot(app, form)
+}
+
+// execOptionsLabel summarizes the options that differ from a plain exec
+func execOptionsLabel(opts docker.ExecOptions) string {
+	var parts []string
+	if opts.Shell.Name != "" {
+		parts = append(parts, opts.Shell.Name)
+	}
+	if opts.User != "" {
+		parts = append(parts, "user "+opts.User)
+	}
+	if opts.WorkingDir != "" {
+		parts = append(parts, "in "+opts.WorkingDir)
+	}
+	if len(opts.Env) > 0 {
+		parts = append(parts, fmt.Sprintf("+%d env", len(opts.Env)))
+	}
+	if opts.Privileged {
+		parts = append(parts, "privileged")
+	}
+	if opts.TTY {
+		parts = append(parts, "tty")
+	}
+	return strings.Join(parts, ", ")
 }
 
 func showFileBrowser(app *tview.Application, mainView tview.Primitive, containerID, containerName string) {
@@ -369,11 +779,11 @@ func showFileBrowser(app *tview.Application, mainView tview.Primitive, container
 		"File browser coming soon!\n\nFor now, use the shell to browse:\nls -la /path/to/directory")
 }
 
-func showSystemInfo(app *tview.Application, mainView tview.Primitive, containerID, containerName string) {
+func showSystemInfo(app *tview.Application, mainView tview.Primitive, containerID, containerName string, shell docker.Shell) {
 	modal := tview.NewModal().
 		SetText("Gathering system information...")
 	modal.SetBorder(true).SetTitle(" ⏳ Loading ")
-	app.SetRoot(modal, false)
+	setRoot(app, modal)
 
 	go func() {
 		commands := []string{
@@ -385,8 +795,8 @@ func showSystemInfo(app *tview.Application, mainView tview.Primitive, containerI
 
 		info := ""
 		for _, cmd := range commands {
-			output, _ := docker.ExecCommand(containerID, cmd)
-			info += fmt.Sprintf("[yellow]$ %s[-]\n[white]%s[-]\n\n", cmd, output)
+			output, _ := docker.ExecCommandWith(containerID, cmd, docker.ExecOptions{Shell: shell})
+			info += fmt.Sprintf("[yellow]$ %s[-]\n[-]%s[-]\n\n", cmd, renderANSI(output, true))
 		}
 
 		app.QueueUpdateDraw(func() {
@@ -401,13 +811,13 @@ func showSystemInfo(app *tview.Application, mainView tview.Primitive, containerI
 
 			view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 				if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
-					app.SetRoot(mainView, true)
+					setRoot(app, mainView)
 					return nil
 				}
 				return event
 			})
 
-			app.SetRoot(view, true)
+			setRoot(app, view)
 		})
 	}()
 }
@@ -0,0 +1,155 @@
+package dashboard
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devops-dashboard/internal/docker"
+)
+
+// completionCacheTTL is how long a listed directory is reused for completion
+const completionCacheTTL = 10 * time.Second
+
+// listPathCommand prints the executables on the container's PATH, one per line
+const listPathCommand = `IFS=:; for d in $PATH; do ls -1 "$d" 2>/dev/null; done`
+
+// shellCompleter completes commands and paths in the interactive shell. Everything it
+// knows comes from running ls in the container, cached to keep Tab responsive.
+type shellCompleter struct {
+	containerID string
+	opts        docker.ExecOptions
+
+	mu       sync.Mutex
+	binaries []string // nil until listed
+	dirs     map[string]listedDir
+}
+
+type listedDir struct {
+	entries []string // directories end with a slash
+	at      time.Time
+}
+
+func newShellCompleter(containerID string, opts docker.ExecOptions) *shellCompleter {
+	// ls colors its output on a terminal
+	opts.TTY = false
+	return &shellCompleter{containerID: containerID, opts: opts, dirs: make(map[string]listedDir)}
+}
+
+// complete extends the last word of text. It returns the new text and, when the word is
+// ambiguous and can't be extended any further, the candidates to show.
+func (c *shellCompleter) complete(text string) (string, []string, error) {
+	start := strings.LastIndexAny(text, " \t|;&<>") + 1
+	word := text[start:]
+	// The first word of a command is a program, unless it is a path
+	head := strings.TrimRight(text[:start], " \t")
+	first := head == "" || strings.ContainsAny(head[len(head)-1:], "|;&")
+	if first && !strings.Contains(word, "/") {
+		binaries, err := c.listBinaries()
+		if err != nil {
+			return text, nil, err
+		}
+		return completeWord(text[:start], word, "", binaries)
+	}
+
+	dir, base := path.Split(word)
+	entries, err := c.listDir(dir)
+	if err != nil {
+		return text, nil, err
+	}
+	return completeWord(text[:start]+dir, base, dir, entries)
+}
+
+// completeWord completes word to the candidates starting with it. A single match is
+// finished with a space unless it is a directory; several are extended to their common
+// prefix, and listed when that doesn't add anything.
+func completeWord(before, word, dir string, candidates []string) (string, []string, error) {
+	var matches []string
+	for _, cand := range candidates {
+		// Hidden files only show up when asked for
+		if strings.HasPrefix(cand, word) && (strings.HasPrefix(word, ".") || !strings.HasPrefix(cand, ".")) {
+			matches = append(matches, cand)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return before + word, nil, nil
+	case 1:
+		if strings.HasSuffix(matches[0], "/") {
+			return before + matches[0], nil, nil
+		}
+		return before + matches[0] + " ", nil, nil
+	}
+
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(word) {
+		return before + prefix, nil, nil
+	}
+	return before + word, matches, nil
+}
+
+// listBinaries returns the programs on the container's PATH, listed once per session
+func (c *shellCompleter) listBinaries() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.binaries != nil {
+		return c.binaries, nil
+	}
+
+	out, err := docker.ExecCommandWith(c.containerID, listPathCommand, c.opts)
+	if err != nil && out == "" {
+		return nil, err
+	}
+	c.binaries = uniqueLines(out)
+	return c.binaries, nil
+}
+
+// listDir returns the entries of a directory in the container, relative to the working
+// directory of the shell when dir is empty
+func (c *shellCompleter) listDir(dir string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.dirs[dir]; ok && time.Since(cached.at) < completionCacheTTL {
+		return cached.entries, nil
+	}
+
+	target := dir
+	if target == "" {
+		target = "."
+	}
+	out, err := docker.ExecCommandWith(c.containerID, "ls -1Ap -- "+shellQuote(target), c.opts)
+	if err != nil {
+		// A directory that doesn't exist just has nothing to complete
+		out = ""
+	}
+	entries := uniqueLines(out)
+	c.dirs[dir] = listedDir{entries: entries, at: time.Now()}
+	return entries, nil
+}
+
+// uniqueLines returns the sorted distinct non-empty lines of s
+func uniqueLines(s string) []string {
+	seen := make(map[string]bool)
+	lines := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// shellQuote quotes s for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
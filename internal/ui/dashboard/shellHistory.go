@@ -0,0 +1,52 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/logarchive"
+)
+
+// defaultShellHistorySize is how many commands a history keeps unless configured
+const defaultShellHistorySize = 1000
+
+// Shell history settings of the config
+var (
+	shellHistoryMode = config.ShellHistoryContainer
+	shellHistorySize = defaultShellHistorySize
+)
+
+// setShellHistory installs the shell_history settings of the config
+func setShellHistory(cfg *config.Config) {
+	if cfg.ShellHistory != "" {
+		shellHistoryMode = cfg.ShellHistory
+	}
+	if cfg.ShellHistorySize > 0 {
+		shellHistorySize = cfg.ShellHistorySize
+	}
+}
+
+// shellHistoryDir is where saved shell histories live
+func shellHistoryDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "dockpulse", "shell-history")
+}
+
+// openCommandHistory loads the shell history for a container. Per container histories
+// are named after the host and container name so they survive recreating it. On error
+// the history still works, it just starts empty.
+func openCommandHistory(c docker.ContainerInfo) (*CommandHistory, error) {
+	switch shellHistoryMode {
+	case config.ShellHistoryOff:
+		return &CommandHistory{}, nil
+	case config.ShellHistoryGlobal:
+		return loadCommandHistory(filepath.Join(shellHistoryDir(), "global.history"), shellHistorySize)
+	}
+	name := logarchive.FileName(archiveName(c)) + ".history"
+	return loadCommandHistory(filepath.Join(shellHistoryDir(), "containers", name), shellHistorySize)
+}
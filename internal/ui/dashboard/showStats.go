@@ -12,6 +12,19 @@ import (
 	"devops-dashboard/internal/docker"
 )
 
+// Thresholds for the resource warnings of the statistics screen
+const (
+	throttleWarn    = 5.0  // percent of CPU periods throttled
+	throttleCrit    = 25.0 // percent of CPU periods throttled
+	memLimitWarn    = 80.0 // percent of the memory limit in use
+	memLimitCrit    = 90.0 // percent of the memory limit in use
+	repeatedOOMs    = 2    // OOM events before they count as repeated
+	pressureRefresh = 15 * time.Second
+)
+
+// statsControls is the control bar of the statistics screen
+const statsControls = "[-][[yellow]Backspace/ESC[-]] Back   [[cyan]r[-]] Reset   [[yellow]p[-]] Pause   [[cyan]g[-]] Graph style   [[cyan]c[-]] Cgroup   [[lime]q[-]] Quit"
+
 type StatsViewer struct {
 	cpuHistory    []float64
 	memHistory    []float64
@@ -20,13 +33,19 @@ type StatsViewer struct {
 	maxDataPoints int
 }
 
+// statsSamples is how many samples the statistics screen keeps, enough for the widest graphs
+const statsSamples = 240
+
+// statsTrendSamples is how many of the latest samples the trend graph shows
+const statsTrendSamples = 60
+
 func NewStatsViewer() *StatsViewer {
 	return &StatsViewer{
-		cpuHistory:    make([]float64, 0, 60),
-		memHistory:    make([]float64, 0, 60),
-		netRxHistory:  make([]float64, 0, 60),
-		netTxHistory:  make([]float64, 0, 60),
-		maxDataPoints: 60,
+		cpuHistory:    make([]float64, 0, statsSamples),
+		memHistory:    make([]float64, 0, statsSamples),
+		netRxHistory:  make([]float64, 0, statsSamples),
+		netTxHistory:  make([]float64, 0, statsSamples),
+		maxDataPoints: statsSamples,
 	}
 }
 
@@ -44,31 +63,35 @@ func (sv *StatsViewer) AddMem(value float64) {
 	}
 }
 
-func (sv *StatsViewer) GetCPUBar() string {
+func (sv *StatsViewer) GetCPUBar(width int) string {
 	if len(sv.cpuHistory) == 0 {
-		return DrawGraph(0, 50)
+		return DrawGraph(0, width)
 	}
 	latest := sv.cpuHistory[len(sv.cpuHistory)-1]
-	return DrawGraph(latest, 50)
+	return DrawGraph(latest, width)
 }
 
-func (sv *StatsViewer) GetMemBar() string {
+func (sv *StatsViewer) GetMemBar(width int) string {
 	if len(sv.memHistory) == 0 {
-		return DrawGraph(0, 50)
+		return DrawGraph(0, width)
 	}
 	latest := sv.memHistory[len(sv.memHistory)-1]
-	return DrawGraph(latest, 50)
+	return DrawGraph(latest, width)
 }
 
-func (sv *StatsViewer) GetCPUGraph() string {
-	return sv.createSparkline(sv.cpuHistory, 60)
+func (sv *StatsViewer) GetCPUGraph(width int) string {
+	return sv.createSparkline(sv.cpuHistory, width)
 }
 
-func (sv *StatsViewer) GetMemGraph() string {
-	return sv.createSparkline(sv.memHistory, 60)
+func (sv *StatsViewer) GetMemGraph(width int) string {
+	return sv.createSparkline(sv.memHistory, width)
 }
 
+// createSparkline draws the last width values of data, padded on the left to width
 func (sv *StatsViewer) createSparkline(data []float64, width int) string {
+	if len(data) > width {
+		data = data[len(data)-width:]
+	}
 	if len(data) == 0 {
 		return strings.Repeat("▁", width)
 	}
@@ -103,10 +126,6 @@ func (sv *StatsViewer) createSparkline(data []float64, width int) string {
 		result += string(blocks[index])
 	}
 
-	if len(result) > width {
-		result = result[len(result)-width:]
-	}
-
 	return result
 }
 
@@ -170,8 +189,11 @@ func (sv *StatsViewer) createLineGraph(data []float64, height, width int) string
 	return result.String()
 }
 
-func showEnhancedStats(app *tview.Application, mainView tview.Primitive, containerID, containerName string) {
+// showEnhancedStats shows live statistics for a container; braille selects high-resolution
+// line charts instead of block sparklines, and 'g' switches between them
+func showEnhancedStats(app *tview.Application, mainView tview.Primitive, containerID, containerName string, braille bool) {
 	statsViewer := NewStatsViewer()
+	ioRates := NewIORates(statsSamples)
 
 	statsView := tview.NewTextView().
 		SetDynamicColors(true).
@@ -201,13 +223,20 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 	controlBar := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter).
-		SetText("[white][[yellow]Backspace/ESC[white]] Back   [[cyan]r[white]] Reset   [[yellow]p[white]] Pause   [[lime]q[white]] Quit")
+		SetText(statsControls)
 
 	rightPanel := tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(summaryView, 0, 1, false).
 		AddItem(graphView, 12, 0, false)
 
+	cgroupView := tview.NewTextView().
+		SetDynamicColors(true)
+	cgroupView.SetBorder(true).
+		SetTitle(" 🧬 Cgroup ").
+		SetBorderColor(tcell.ColorDarkCyan).
+		SetBorderPadding(0, 0, 1, 1)
+
 	mainPanel := tview.NewFlex().
 		AddItem(statsView, 0, 2, true).
 		AddItem(rightPanel, 40, 0, false)
@@ -218,19 +247,27 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 		AddItem(controlBar, 1, 0, false)
 
 	ctx, cancel := context.WithCancel(context.Background())
+	var cancelCgroup context.CancelFunc // nil while the summary shows
 	paused := false
 	startTime := time.Now()
 
 	var avgCPU, avgMem, maxCPU, maxMem float64
 	sampleCount := 0
 
-	updateStats := func() {
-		stats, err := docker.GetStats(containerID)
-		if err != nil {
-			app.QueueUpdateDraw(func() {
-				statsView.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
-			})
-			return
+	// Samples are rendered on the stats goroutine, which sizes the graphs by the last draw
+	var mainWidth, trendWidth panelWidth
+
+	// The OOM history needs an inspect and an events query, so it is refreshed less often
+	var pressure *docker.MemoryPressure
+	var pressureAt time.Time
+
+	updateStats := func(stats *docker.ContainerStats) {
+
+		if time.Since(pressureAt) > pressureRefresh {
+			pressureAt = time.Now()
+			if p, err := docker.GetMemoryPressure(containerID); err == nil {
+				pressure = p
+			}
 		}
 
 		var cpuVal, memVal float64
@@ -239,6 +276,7 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 
 		statsViewer.AddCPU(cpuVal)
 		statsViewer.AddMem(memVal)
+		ioRates.Add(containerID, stats)
 
 		sampleCount++
 		avgCPU = ((avgCPU * float64(sampleCount-1)) + cpuVal) / float64(sampleCount)
@@ -251,10 +289,20 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 			maxMem = memVal
 		}
 
-		cpuBar := statsViewer.GetCPUBar()
-		memBar := statsViewer.GetMemBar()
-		cpuGraph := statsViewer.GetCPUGraph()
-		memGraph := statsViewer.GetMemGraph()
+		// Graphs follow the panel widths; braille charts have a value axis of up to 7
+		// columns and rate lines a label and rate of 15
+		barWidth := mainWidth.fit(0, 10, 100)
+		cpuBar := statsViewer.GetCPUBar(barWidth)
+		memBar := statsViewer.GetMemBar(barWidth)
+		sparkWidth := mainWidth.fit(0, 10, statsSamples)
+		cpuGraph := statsViewer.GetCPUGraph(sparkWidth)
+		memGraph := statsViewer.GetMemGraph(sparkWidth)
+		if braille {
+			brailleWidth := mainWidth.fit(7, 10, statsSamples/2)
+			cpuGraph = labeledBrailleChart(statsViewer.cpuHistory, 3, brailleWidth)
+			memGraph = labeledBrailleChart(statsViewer.memHistory, 3, brailleWidth)
+		}
+		rateWidth := mainWidth.fit(15, 8, statsSamples)
 
 		cpuColor := "lime"
 		if cpuVal > 80 {
@@ -270,31 +318,33 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 			memColor = "yellow"
 		}
 
-		mainDisplay := fmt.Sprintf(
+		mainDisplay := resourceWarnings(stats, pressure, memVal) + fmt.Sprintf(
 			"[::b][cyan]CPU Usage:[-:-:-]\n"+
-				"[white]Current: [%s]%.2f%%[-][-]\n"+
+				"[-]Current: [%s]%.2f%%[-][-]\n"+
 				"[%s]%s[-]\n"+
-				"[cyan]%s[-]\n\n"+
+				"[cyan]%s[-]\n%s\n\n"+
 				"[::b][magenta]Memory Usage:[-:-:-]\n"+
-				"[white]Current: [%s]%.2f%%[-] (%s)[-]\n"+
+				"[-]Current: [%s]%.2f%%[-] (%s)[-]\n"+
 				"[%s]%s[-]\n"+
-				"[magenta]%s[-]\n\n"+
-				"[::b][lime]Network I/O:[-:-:-]\n[white]%s[-]\n\n"+
-				"[::b][yellow]Block I/O:[-:-:-]\n[white]%s[-]\n\n"+
-				"[::b][dodgerblue]Process Info:[-:-:-]\n[white]PIDs: %s[-]",
-			cpuColor, cpuVal, cpuColor, cpuBar, cpuGraph,
-			memColor, memVal, stats.MemUsage, memColor, memBar, memGraph,
-			stats.NetIO,
-			stats.BlockIO,
+				"[magenta]%s[-]\n%s\n\n"+
+				"%s"+
+				"[::b][lime]Network I/O:[-:-:-]\n[-]%s[-]\n[lime]%s[-]\n\n"+
+				"[::b][yellow]Block I/O:[-:-:-]\n[-]%s[-]\n[yellow]%s[-]\n\n"+
+				"[::b][dodgerblue]Process Info:[-:-:-]\n[-]PIDs: %s[-]",
+			cpuColor, cpuVal, cpuColor, cpuBar, cpuGraph, seriesLabels(statsViewer.cpuHistory),
+			memColor, memVal, stats.MemUsage, memColor, memBar, memGraph, seriesLabels(statsViewer.memHistory),
+			limitsDisplay(stats, pressure),
+			stats.NetIO, ioRates.NetGraph(rateWidth),
+			stats.BlockIO, ioRates.BlockGraph(rateWidth),
 			stats.PIDs)
 
 		summaryDisplay := fmt.Sprintf(
 			"[::b][yellow]Statistics Summary[-:-:-]\n\n"+
-				"[cyan]Runtime:[-]\n[white]%s[-]\n\n"+
-				"[cyan]Samples:[-]\n[white]%d[-]\n\n"+
-				"[cyan]CPU Avg:[-]\n[white]%.2f%%[-]\n\n"+
+				"[cyan]Runtime:[-]\n[-]%s[-]\n\n"+
+				"[cyan]Samples:[-]\n[-]%d[-]\n\n"+
+				"[cyan]CPU Avg:[-]\n[-]%.2f%%[-]\n\n"+
 				"[cyan]CPU Max:[-]\n[%s]%.2f%%[-]\n\n"+
-				"[cyan]Mem Avg:[-]\n[white]%.2f%%[-]\n\n"+
+				"[cyan]Mem Avg:[-]\n[-]%.2f%%[-]\n\n"+
 				"[cyan]Mem Max:[-]\n[%s]%.2f%%[-]\n\n"+
 				"[gray]Updated:\n%s[-]",
 			time.Since(startTime).Round(time.Second),
@@ -321,7 +371,14 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 			}(), maxMem,
 			time.Now().Format("15:04:05"))
 
-		lineGraph := statsViewer.createLineGraph(statsViewer.cpuHistory, 10, 38)
+		trend := statsViewer.cpuHistory
+		if len(trend) > statsTrendSamples {
+			trend = trend[len(trend)-statsTrendSamples:]
+		}
+		lineGraph := statsViewer.createLineGraph(trend, 10, trendWidth.fit(0, 10, statsTrendSamples))
+		if braille {
+			lineGraph = labeledBrailleChart(trend, 8, trendWidth.fit(7, 10, statsTrendSamples/2))
+		}
 		graphDisplay := fmt.Sprintf(
 			"[cyan]CPU Trend (60s):[-]\n"+
 				"[lime]%s[-]",
@@ -331,104 +388,145 @@ func showEnhancedStats(app *tview.Application, mainView tview.Primitive, contain
 			statsView.SetText(mainDisplay)
 			summaryView.SetText(summaryDisplay)
 			graphView.SetText(graphDisplay)
+			mainWidth.update(statsView)
+			trendWidth.update(graphView)
 		})
 	}
 
+	// One streaming request for the lifetime of the screen; the daemon sends a sample
+	// about every second
 	go func() {
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
-
-		updateStats()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				if !paused {
-					updateStats()
-				}
+		samples, errs := docker.StreamStats(ctx, containerID)
+		for stats := range samples {
+			if !paused {
+				updateStats(stats)
 			}
 		}
+		if err := <-errs; err != nil {
+			app.QueueUpdateDraw(func() {
+				statsView.SetText(fmt.Sprintf("[red]Error: %s[-]", errorText(err)))
+			})
+		}
 	}()
 
 	statsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Rune() {
 		case 'q', 'Q':
 			cancel()
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		case 'r', 'R':
 			avgCPU, avgMem, maxCPU, maxMem = 0, 0, 0, 0
 			sampleCount = 0
 			startTime = time.Now()
 			statsViewer = NewStatsViewer()
+			ioRates.Reset()
+			return nil
+		case 'g', 'G':
+			// Takes effect with the next sample
+			braille = !braille
+			return nil
+		case 'c', 'C':
+			// The cgroup panel takes the place of the summary and polls while it shows
+			rightPanel.Clear()
+			if cancelCgroup != nil {
+				cancelCgroup()
+				cancelCgroup = nil
+				rightPanel.AddItem(summaryView, 0, 1, false)
+			} else {
+				var cgroupCtx context.Context
+				cgroupCtx, cancelCgroup = context.WithCancel(ctx)
+				cgroupView.SetText(" Reading the cgroup...")
+				go pollCgroup(cgroupCtx, app, cgroupView, containerID)
+				rightPanel.AddItem(cgroupView, 0, 1, false)
+			}
+			rightPanel.AddItem(graphView, 12, 0, false)
 			return nil
 		case 'p', 'P':
 			paused = !paused
 			if paused {
-				controlBar.SetText("[white][[red]⏸ PAUSED[white]]   [[yellow]p[white]] Resume   [[yellow]Backspace[white]] Back")
+				controlBar.SetText("[-][[red]⏸ PAUSED[-]]   [[yellow]p[-]] Resume   [[yellow]Backspace[-]] Back")
 			} else {
-				controlBar.SetText("[white][[yellow]Backspace/ESC[white]] Back   [[cyan]r[white]] Reset   [[yellow]p[white]] Pause   [[lime]q[white]] Quit")
+				controlBar.SetText(statsControls)
 			}
 			return nil
 		}
 
 		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
 			cancel()
-			app.SetRoot(mainView, true)
+			setRoot(app, mainView)
 			return nil
 		}
 
 		return event
 	})
 
-	app.SetRoot(flex, true)
+	setRoot(app, flex)
 	app.SetFocus(statsView)
 }
 
-func showEnhancedInspect(app *tview.Application, mainView tview.Primitive, containerID, containerName string) {
-	inspectView := tview.NewTextView().
-		SetDynamicColors(true).
-		SetScrollable(true).
-		SetWordWrap(true)
+// resourceWarnings returns banner lines for CPU throttling, a nearly full memory limit and
+// OOM kills, or nothing when the container is healthy
+func resourceWarnings(stats *docker.ContainerStats, pressure *docker.MemoryPressure, memPerc float64) string {
+	var warnings []string
 
-	inspectView.SetBorder(true).
-		SetTitle(fmt.Sprintf(" 🔍 Inspect: %s ", containerName)).
-		SetBorderPadding(1, 1, 2, 2).
-		SetBorderColor(tcell.ColorDarkMagenta)
+	switch {
+	case stats.ThrottledPerc >= throttleCrit:
+		warnings = append(warnings, fmt.Sprintf("[red::b]⚠ CPU heavily throttled:[-:-:-] %.0f%% of periods hit the CPU limit", stats.ThrottledPerc))
+	case stats.ThrottledPerc >= throttleWarn:
+		warnings = append(warnings, fmt.Sprintf("[yellow::b]⚠ CPU throttled:[-:-:-] %.0f%% of periods hit the CPU limit", stats.ThrottledPerc))
+	}
 
-	buttonBar := tview.NewTextView().
-		SetDynamicColors(true).
-		SetTextAlign(tview.AlignCenter).
-		SetText("[white][[yellow]Backspace/ESC[white]] Back   [[cyan]↑/↓[white]] Scroll   [[lime]q[white]] Quit")
+	if pressure != nil {
+		if pressure.Limit > 0 {
+			switch {
+			case memPerc >= memLimitCrit:
+				warnings = append(warnings, fmt.Sprintf("[red::b]⚠ Memory at %.0f%% of its limit:[-:-:-] an OOM kill is likely", memPerc))
+			case memPerc >= memLimitWarn:
+				warnings = append(warnings, fmt.Sprintf("[yellow::b]⚠ Memory at %.0f%% of its limit[-:-:-]", memPerc))
+			}
+		}
 
-	flex := tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(inspectView, 0, 1, true).
-		AddItem(buttonBar, 1, 0, false)
+		switch n := len(pressure.OOMEvents); {
+		case n >= repeatedOOMs:
+			warnings = append(warnings, fmt.Sprintf("[red::b]⚠ Repeatedly OOM-killed:[-:-:-] %d times, last %s ago",
+				n, time.Since(pressure.OOMEvents[n-1]).Round(time.Second)))
+		case n == 1 || pressure.OOMKilled:
+			warnings = append(warnings, "[yellow::b]⚠ OOM-killed before:[-:-:-] the kernel ran out of memory for this container")
+		}
+	}
 
-	inspectView.SetText("[yellow]⏳ Loading container details...[-]")
+	if len(warnings) == 0 {
+		return ""
+	}
+	return strings.Join(warnings, "\n") + "\n\n"
+}
 
-	go func() {
-		details, err := docker.InspectContainer(containerID)
-		app.QueueUpdateDraw(func() {
-			if err != nil {
-				inspectView.SetText(fmt.Sprintf("[red]Error:[-] %s", err.Error()))
-			} else {
-				inspectView.SetText(details)
-			}
-		})
-	}()
+// limitsDisplay describes CPU throttling and memory limit usage
+func limitsDisplay(stats *docker.ContainerStats, pressure *docker.MemoryPressure) string {
+	throttling := "[gray]no CPU limit[-]"
+	if stats.TotalPeriods > 0 {
+		throttling = fmt.Sprintf("Now: %.1f%% of periods\nTotal: %d / %d periods (%.1f%%), %s throttled",
+			stats.ThrottledPerc, stats.ThrottledPeriods, stats.TotalPeriods,
+			float64(stats.ThrottledPeriods)/float64(stats.TotalPeriods)*100,
+			stats.ThrottledTime.Round(time.Millisecond))
+	}
 
-	inspectView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Rune() == 'q' || event.Rune() == 'Q' || event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
-			app.SetRoot(mainView, true)
-			return nil
+	memory := "[gray]loading...[-]"
+	if pressure != nil {
+		limit := "none (host memory)"
+		if pressure.Limit > 0 {
+			limit = fmt.Sprintf("%s, %s left", docker.FormatBytes(uint64(pressure.Limit)), docker.FormatBytes(stats.MemLimit-min(stats.MemUsed, stats.MemLimit)))
 		}
-		return event
-	})
+		ooms := fmt.Sprintf("%d", len(pressure.OOMEvents))
+		if n := len(pressure.OOMEvents); n > 0 {
+			ooms += fmt.Sprintf(" (last %s)", pressure.OOMEvents[n-1].Format("2006-01-02 15:04:05"))
+		}
+		memory = fmt.Sprintf("Limit: %s\nOOM kills: %s\nRestarts: %d", limit, ooms, pressure.RestartCount)
+	}
 
-	app.SetRoot(flex, true)
-	app.SetFocus(inspectView)
+	return fmt.Sprintf(
+		"[::b][orange]CPU Throttling:[-:-:-]\n[-]%s[-]\n\n"+
+			"[::b][orange]Memory Limit:[-:-:-]\n[-]%s[-]\n\n",
+		throttling, memory)
 }
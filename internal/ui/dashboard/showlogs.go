@@ -1,8 +1,16 @@
 package dashboard
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -21,12 +29,12 @@ func showLogs(app *tview.Application, mainView tview.Primitive, containerID stri
 
 	logView := tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true).
-		SetWrap(false).
-		SetChangedFunc(func() { app.Draw() })
+		SetWrap(false)
 
 	logView.SetBorder(true).
-		SetTitle(fmt.Sprintf(" 📜 Logs: %s ", containerName)).
+		SetTitle(logTitle("Logs", containerName, docker.LogSource{})).
 		SetBorderPadding(1, 1, 2, 2).
 		SetBorderColor(tcell.ColorTeal)
 
@@ -39,61 +47,523 @@ func showLogs(app *tview.Application, mainView tview.Primitive, containerID stri
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 	bottomBar.SetText(
-		"[white][[yellow]Backspace/ESC[white]] Back   " +
-			"[white][[cyan]↑/↓[white]] Scroll   " +
-			"[white][[blue]PgUp/PgDn[white]] Page   " +
-			"[white][[magenta]Home/End[white]] Top/Bottom   " +
-			"[white][[lime]q[white]] Quit")
+		"[-][[yellow]Backspace/ESC[-]] Back   " +
+			"[-][[cyan]↑/↓[-]] Select line   " +
+			"[-][[yellow]/[-]] Search   " +
+			"[-][[yellow]n/N[-]] Next/Prev match   " +
+			"[-][[aqua]m/M[-]] Bookmark/List   " +
+			"[-][[aqua]t[-]] Jump to time   " +
+			"[-][[lime]y[-]] Copy line   " +
+			"[-][[blue]PgUp/PgDn[-]] Page   " +
+			"[-][[magenta]Home/End[-]] Top/Bottom   " +
+			"[-][[lime]q[-]] Quit")
+
+	searchInput := tview.NewInputField().
+		SetLabel("🔍 ").
+		SetPlaceholder("search logs, Enter to keep, ESC to clear").
+		SetFieldWidth(0)
+
+	matchInfo := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight)
+
+	// The search row stays collapsed until '/' is pressed
+	searchRow := tview.NewFlex().
+		AddItem(searchInput, 0, 1, false).
+		AddItem(matchInfo, 20, 0, false)
 
 	flex := tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(statusBar, 1, 0, false).
 		AddItem(logView, 0, 1, true).
+		AddItem(searchRow, 0, 0, false).
 		AddItem(bottomBar, 1, 0, false)
 
+	var (
+		linesMu sync.Mutex
+		lines   []docker.LogLine
+		written int            // lines already in logView
+		queued  bool           // a flush is queued on the UI goroutine
+		cursor  = -1           // selected line, -1 while following the tail
+		search  *regexp.Regexp // current search, nil when there is none
+		matches []int          // indexes of the lines matching search, ascending
+		marks   []int          // bookmarked lines, ascending
+	)
+
+	// renderLine formats line n as its own region so it can be selected and copied,
+	// with the matches of the current search highlighted. linesMu must be held.
+	renderLine := func(n int, line docker.LogLine) string {
+		color := "-"
+		if c := stderrColor(); line.Stream == docker.Stderr && c != "" {
+			color = c
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "[\"%d\"]", n)
+		if _, marked := slices.BinarySearch(marks, n); marked {
+			b.WriteString(bookmarkMarker)
+		}
+		fmt.Fprintf(&b, "[%s]", color)
+		last := 0
+		if search != nil {
+			for _, m := range search.FindAllStringIndex(line.Text, -1) {
+				b.WriteString(tview.Escape(line.Text[last:m[0]]))
+				fmt.Fprintf(&b, "[black:yellow]%s[%s:-]", tview.Escape(line.Text[m[0]:m[1]]), color)
+				last = m[1]
+			}
+		}
+		b.WriteString(tview.Escape(line.Text[last:]))
+		b.WriteString("[-][\"\"]\n")
+		return b.String()
+	}
+
+	// showMatchInfo shows which match the cursor is on and how many there are
+	showMatchInfo := func() {
+		linesMu.Lock()
+		defer linesMu.Unlock()
+
+		switch {
+		case search == nil:
+			matchInfo.SetText("")
+		case len(matches) == 0:
+			matchInfo.SetText("[red]no matches[-] ")
+		default:
+			i := sort.SearchInts(matches, cursor)
+			if cursor >= 0 && i < len(matches) && matches[i] == cursor {
+				matchInfo.SetText(fmt.Sprintf("[yellow]%d/%d[-] ", i+1, len(matches)))
+			} else {
+				matchInfo.SetText(fmt.Sprintf("[yellow]%d matches[-] ", len(matches)))
+			}
+		}
+	}
+
+	// Streaming stops when the view is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	back := func() {
+		cancel()
+		setRoot(app, mainView)
+	}
+
+	// source is how the log driver can be read, set on the UI goroutine once known
+	var source docker.LogSource
+	sourceNote := func(src docker.LogSource) string {
+		if src.Command == "" {
+			return logSourceNote(src)
+		}
+		return logSourceNote(src) + fmt.Sprintf("\n\n[%s]y copies the command[-]", currentTheme().Muted)
+	}
+
 	go func() {
-		reader, err := docker.StreamLogs(containerID)
+		// Drivers that ship logs elsewhere can't be read, or only from the daemon's copy
+		src, srcErr := docker.ContainerLogSource(containerID)
+		if srcErr == nil {
+			app.QueueUpdateDraw(func() {
+				source = src
+				logView.SetTitle(logTitle("Logs", containerName, src))
+			})
+			if !src.Readable {
+				app.QueueUpdateDraw(func() {
+					statusBar.SetText(fmt.Sprintf("[black:yellow] ■ docker logs can't read the %s driver [-:-:-]", src.Driver))
+					logView.SetText(sourceNote(src))
+				})
+				return
+			}
+		}
+
+		err := followLogs(ctx, containerID, func(line docker.LogLine) {
+			linesMu.Lock()
+			if search != nil && search.MatchString(line.Text) {
+				matches = append(matches, len(lines))
+			}
+			lines = append(lines, line)
+			flush := !queued
+			queued = true
+			linesMu.Unlock()
+
+			// New lines are written in batches on the UI goroutine, so they stay in order
+			// with the full redraw of a new search
+			if flush {
+				app.QueueUpdateDraw(func() {
+					linesMu.Lock()
+					for ; written < len(lines); written++ {
+						fmt.Fprint(logView, renderLine(written, lines[written]))
+					}
+					queued = false
+					linesMu.Unlock()
+					showMatchInfo()
+				})
+			}
+		}, func(status string) {
+			app.QueueUpdateDraw(func() {
+				switch status {
+				case logStreaming:
+					statusBar.SetText("[black:lime] ● Live Logs Streaming... [-:-:-]")
+				case logOffline:
+					statusBar.SetText("[black:red] ⚠ Docker offline, logs resume when it is back [-:-:-]")
+				case logEnded:
+					statusBar.SetText("[black:yellow] ■ Log stream ended [-:-:-]")
+				}
+			})
+		})
 		if err != nil {
 			app.QueueUpdateDraw(func() {
 				statusBar.SetText("[black:red] ❌ Error loading logs [-:-:-]")
-				logView.SetText(fmt.Sprintf("[red]Failed to load logs:[-]\n[yellow]%s[-]", err.Error()))
+				text := fmt.Sprintf("[red]Failed to load logs:[-]\n[yellow]%s[-]", errorText(err))
+				if src.Hint != "" {
+					text += "\n\n" + sourceNote(src)
+				}
+				logView.SetText(text)
 			})
 			return
 		}
-		defer reader.Close()
 
-		app.QueueUpdateDraw(func() {
-			statusBar.SetText("[black:lime] ● Live Logs Streaming... [-:-:-]")
-		})
-
-		io.Copy(logView, reader)
+		// An empty log from a driver that ships elsewhere likely means the copy is off
+		linesMu.Lock()
+		empty := len(lines) == 0
+		linesMu.Unlock()
+		if empty && src.Hint != "" && ctx.Err() == nil {
+			app.QueueUpdateDraw(func() {
+				logView.SetText(sourceNote(src))
+			})
+		}
 	}()
 
+	// selectLine moves the line cursor; a negative index follows the tail again
+	selectLine := func(i int) {
+		linesMu.Lock()
+		count := len(lines)
+		linesMu.Unlock()
+
+		if i < 0 || i >= count {
+			cursor = -1
+			logView.Highlight()
+			logView.ScrollToEnd()
+			showMatchInfo()
+			return
+		}
+		cursor = i
+		logView.Highlight(strconv.Itoa(i))
+		logView.ScrollToHighlight()
+		showMatchInfo()
+	}
+
+	// findMatch returns the first matching line at or after from, or with backward the last
+	// one at or before it, wrapping around the log; -1 if nothing matches
+	findMatch := func(from int, backward bool) int {
+		linesMu.Lock()
+		defer linesMu.Unlock()
+
+		if len(matches) == 0 {
+			return -1
+		}
+		if backward {
+			i := sort.SearchInts(matches, from+1) - 1
+			if i < 0 {
+				i = len(matches) - 1
+			}
+			return matches[i]
+		}
+		i := sort.SearchInts(matches, from)
+		if i == len(matches) {
+			i = 0
+		}
+		return matches[i]
+	}
+
+	// jumpMatch moves to the next match after the cursor, or the previous one with backward.
+	// While following the tail the cursor counts as being past the last line.
+	jumpMatch := func(backward bool) {
+		from := cursor
+		if from < 0 {
+			linesMu.Lock()
+			from = len(lines)
+			linesMu.Unlock()
+		}
+		if backward {
+			from--
+		} else {
+			from++
+		}
+		if i := findMatch(from, backward); i >= 0 {
+			selectLine(i)
+		}
+	}
+
+	// redraw renders every line again after the search or the bookmarks changed.
+	// linesMu must be held.
+	redraw := func() {
+		var b strings.Builder
+		for i, line := range lines {
+			b.WriteString(renderLine(i, line))
+		}
+		logView.SetText(b.String())
+		written = len(lines)
+	}
+
+	// setSearch highlights every case-insensitive occurrence of term and jumps to the nearest
+	// match, searching back from the tail while following it
+	setSearch := func(term string) {
+		linesMu.Lock()
+		search, matches = nil, nil
+		if term != "" {
+			search = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+			for i, line := range lines {
+				if search.MatchString(line.Text) {
+					matches = append(matches, i)
+				}
+			}
+		}
+		redraw()
+		last := len(lines) - 1
+		linesMu.Unlock()
+
+		if cursor < 0 {
+			if i := findMatch(last, true); i >= 0 {
+				selectLine(i)
+			} else {
+				selectLine(-1)
+			}
+		} else if i := findMatch(cursor, false); i >= 0 {
+			selectLine(i)
+		} else {
+			selectLine(cursor)
+		}
+	}
+
+	openSearch := func() {
+		flex.ResizeItem(searchRow, 1, 0)
+		app.SetFocus(searchInput)
+	}
+
+	closeSearch := func() {
+		searchInput.SetText("")
+		flex.ResizeItem(searchRow, 0, 0)
+		app.SetFocus(logView)
+	}
+
+	searchInput.SetChangedFunc(setSearch)
+	searchInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeSearch()
+			return nil
+		case tcell.KeyEnter:
+			if searchInput.GetText() == "" {
+				closeSearch()
+			} else {
+				app.SetFocus(logView)
+			}
+			return nil
+		}
+		return event
+	})
+
+	copyLine := func() {
+		linesMu.Lock()
+		line := ""
+		if cursor >= 0 && cursor < len(lines) {
+			line = lines[cursor].Text
+		} else if len(lines) > 0 {
+			line = lines[len(lines)-1].Text
+		}
+		linesMu.Unlock()
+
+		if line == "" {
+			// With nothing to copy, y takes the command reading the logs elsewhere
+			if source.Command != "" {
+				copyToClipboard(app, source.Command)
+				statusBar.SetText(fmt.Sprintf("[black:lime] ✓ Copied: %s [-:-:-]", tview.Escape(truncateString(source.Command, 60))))
+			}
+			return
+		}
+		copyToClipboard(app, line)
+		statusBar.SetText(fmt.Sprintf("[black:lime] ✓ Copied: %s [-:-:-]", tview.Escape(truncateString(line, 60))))
+	}
+
+	// toggleMark bookmarks the selected line, or the last one while following the tail
+	toggleMark := func() {
+		linesMu.Lock()
+		i := cursor
+		if i < 0 {
+			i = len(lines) - 1
+		}
+		if i < 0 {
+			linesMu.Unlock()
+			return
+		}
+		pos, marked := slices.BinarySearch(marks, i)
+		if marked {
+			marks = slices.Delete(marks, pos, pos+1)
+		} else {
+			marks = slices.Insert(marks, pos, i)
+		}
+		redraw()
+		linesMu.Unlock()
+
+		selectLine(cursor)
+		if marked {
+			statusBar.SetText(fmt.Sprintf("[black:aqua] Bookmark removed, %d left [-:-:-]", len(marks)))
+		} else {
+			statusBar.SetText(fmt.Sprintf("[black:aqua] 🔖 Bookmarked line %d (M to list) [-:-:-]", i+1))
+		}
+	}
+
+	restore := func() {
+		setRoot(app, flex)
+		app.SetFocus(logView)
+	}
+
+	listMarks := func() {
+		if len(marks) == 0 {
+			statusBar.SetText("[black:yellow] No bookmarks yet, m marks the selected line [-:-:-]")
+			return
+		}
+		linesMu.Lock()
+		labels := make([]string, len(marks))
+		for i, n := range marks {
+			labels[i] = fmt.Sprintf("%6d  %s", n+1, bookmarkLabel(lines[n].Text))
+		}
+		linesMu.Unlock()
+
+		showBookmarks(app, flex, labels, func(i int) {
+			selectLine(marks[i])
+		}, func(i int) {
+			linesMu.Lock()
+			marks = slices.Delete(marks, i, i+1)
+			redraw()
+			linesMu.Unlock()
+			selectLine(cursor)
+		}, restore)
+	}
+
+	jumpTime := func() {
+		promptJumpTime(app, flex, func(at time.Time) {
+			linesMu.Lock()
+			i := firstLineAt(len(lines), func(i int) string { return lines[i].Text }, at)
+			linesMu.Unlock()
+			if i < 0 {
+				statusBar.SetText(fmt.Sprintf("[black:yellow] No lines at or after %s [-:-:-]", at.Format("2006-01-02 15:04:05")))
+				return
+			}
+			selectLine(i)
+		}, restore)
+	}
+
 	logView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Rune() {
 		case 'b', 'B', 'q', 'Q':
-			app.SetRoot(mainView, true)
+			back()
 			return nil
 		case 'g', 'G':
-			logView.ScrollToBeginning()
+			selectLine(0)
+			return nil
+		case 'y', 'Y':
+			copyLine()
+			return nil
+		case '/':
+			openSearch()
+			return nil
+		case 'n':
+			jumpMatch(false)
+			return nil
+		case 'N':
+			jumpMatch(true)
+			return nil
+		case 'm':
+			toggleMark()
+			return nil
+		case 'M':
+			listMarks()
+			return nil
+		case 't', 'T':
+			jumpTime()
 			return nil
 		}
 
 		switch event.Key() {
-		case tcell.KeyEscape, tcell.KeyBackspace, tcell.KeyBackspace2:
-			app.SetRoot(mainView, true)
+		case tcell.KeyEscape:
+			// ESC clears an active search before leaving the view
+			if searchInput.GetText() != "" {
+				closeSearch()
+			} else {
+				back()
+			}
+			return nil
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			back()
 			return nil
 		case tcell.KeyHome:
-			logView.ScrollToBeginning()
+			selectLine(0)
 			return nil
 		case tcell.KeyEnd:
-			logView.ScrollToEnd()
+			selectLine(-1)
+			return nil
+		case tcell.KeyUp:
+			if cursor < 0 {
+				linesMu.Lock()
+				last := len(lines) - 1
+				linesMu.Unlock()
+				selectLine(last)
+			} else if cursor > 0 {
+				selectLine(cursor - 1)
+			}
+			return nil
+		case tcell.KeyDown:
+			if cursor >= 0 {
+				selectLine(cursor + 1)
+			}
 			return nil
 		}
 
 		return event
 	})
 
-	app.SetRoot(flex, true)
+	setRoot(app, flex)
 	app.SetFocus(logView)
 }
+
+// Log stream states reported by followLogs
+const (
+	logStreaming = "streaming"
+	logOffline   = "offline"
+	logEnded     = "ended"
+)
+
+// followLogs feeds the lines of a container's log to consume until ctx is done or the
+// container stops. If the daemon goes away it waits for the connection watchdog and resumes from
+// the time of the drop. It only returns an error when the logs can't be opened at all.
+func followLogs(ctx context.Context, containerID string, consume func(docker.LogLine), status func(string)) error {
+	var since time.Time
+	for {
+		stream, err := docker.StreamLogsSince(ctx, containerID, since)
+		if err != nil && !errors.Is(err, docker.ErrDaemonUnreachable) {
+			return err
+		}
+
+		if err == nil {
+			status(logStreaming)
+			// A read error ends the stream like the container stopping does
+			stream.Lines(consume)
+			stream.Close()
+		}
+
+		// The stream also ends when the container stops; only resume if the daemon went away
+		if ctx.Err() != nil || docker.Ping(pingTimeout) == nil {
+			status(logEnded)
+			return nil
+		}
+
+		since = time.Now()
+		status(logOffline)
+
+		// Give the watchdog a moment to notice before waiting on it
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+		if connection.WaitOnline(ctx) != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// containerSignal is a signal offered by the signal menu
+type containerSignal struct {
+	name        string
+	description string
+	stops       bool // usually ends the process, so it is confirmed and guarded like stop
+}
+
+// containerSignals are the signals worth sending to a container's main process
+var containerSignals = []containerSignal{
+	{"HUP", "Reload the configuration (nginx, haproxy, postgres, sshd)", false},
+	{"USR1", "Reopen log files (nginx) or application defined", false},
+	{"USR2", "Application defined, e.g. upgrade the binary (nginx, unicorn)", false},
+	{"WINCH", "Stop worker processes gracefully (nginx, apache)", false},
+	{"INT", "Interrupt, like Ctrl-C", true},
+	{"TERM", "Ask the process to stop, what docker stop sends first", true},
+	{"QUIT", "Stop and dump core or stack traces (Go, Java)", true},
+	{"KILL", "Stop at once; can't be caught", true},
+}
+
+// signalStops reports whether a signal usually ends the process. Signals typed in by
+// hand count as stopping, since their effect isn't known.
+func signalStops(name string) bool {
+	for _, s := range containerSignals {
+		if s.name == name {
+			return s.stops
+		}
+	}
+	return true
+}
+
+// normalizeSignal turns "sighup", "SIGHUP" or "hup" into "HUP"; numbers are kept
+func normalizeSignal(name string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "SIG")
+}
+
+// showSignalMenu lets the user pick a signal, or type any other name or number, on top
+// of view and calls send with it
+func showSignalMenu(app *tview.Application, view tview.Primitive, title string, send func(signal string)) {
+	t := currentTheme()
+	list := tview.NewList().ShowSecondaryText(true)
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 📡 Send Signal: %s (ESC cancel) ", title)).
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	back := func() {
+		setRoot(app, view)
+		app.SetFocus(view)
+	}
+	for i, s := range containerSignals {
+		list.AddItem("SIG"+s.name, s.description, rune('1'+i), func() {
+			send(s.name)
+		})
+	}
+	list.AddItem("Other...", "Any signal name or number, e.g. SIGRTMIN+3 or 28", 'o', func() {
+		promptSignal(app, view, send, back)
+	})
+	list.SetDoneFunc(back)
+
+	showOverlay(app, view, list, 70, 2*len(containerSignals)+4)
+}
+
+// promptSignal asks for a signal name or number on top of view and calls send with it;
+// done restores the view
+func promptSignal(app *tview.Application, view tview.Primitive, send func(signal string), done func()) {
+	t := currentTheme()
+	input := tview.NewInputField().
+		SetLabel("📡 SIG").
+		SetPlaceholder("HUP, USR1, RTMIN+3 or a number").
+		SetFieldWidth(0)
+	input.SetBorder(true).
+		SetTitle(" Signal (ESC to cancel) ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.GetColor(t.Info))
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			done()
+		case tcell.KeyEnter:
+			if signal := normalizeSignal(input.GetText()); signal != "" {
+				send(signal)
+			}
+		}
+	})
+
+	showOverlay(app, view, input, 60, 3)
+}
+
+// signalContainer sends a signal to one container after a confirmation for signals that
+// usually stop it, which protected containers also need the override phrase for
+func signalContainer(app *tview.Application, mainView tview.Primitive, container docker.ContainerInfo, signal string) {
+	send := func() {
+		setRoot(app, mainView)
+		go func() {
+			err := docker.SignalContainer(container.ID, signal)
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					showError(app, mainView, "📡 Send Signal", err)
+					return
+				}
+				showToast(app, toastSuccess, fmt.Sprintf("Sent SIG%s to %s", signal, container.Name))
+			})
+		}()
+	}
+	if !signalStops(signal) {
+		send()
+		return
+	}
+	guardProtected(app, mainView, "kill", []docker.ContainerInfo{container}, func() {
+		showConfirmation(app, mainView, fmt.Sprintf("Send SIG%s to '%s'?\n\nThis usually stops the container.", signal, container.Name), send)
+	})
+}
@@ -0,0 +1,289 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// profileItem tracks one container of a running start profile
+type profileItem struct {
+	group  int
+	name   string
+	id     string // empty when no container has the name
+	status string
+	op     string // step in progress
+	err    error
+}
+
+// showStartProfiles lists the configured start profiles and runs the chosen one
+func (d *Dashboard) showStartProfiles() {
+	if len(d.cfg.StartProfiles) == 0 {
+		showMessage(d.app, d.mainFlex, "🚦 Start Profiles",
+			fmt.Sprintf("No start profiles configured.\n\nAdd \"start_profiles\" to %s to start containers group by group.", config.Path()))
+		return
+	}
+
+	menu := tview.NewList().ShowSecondaryText(true)
+	menu.SetBorder(true).
+		SetTitle(" 🚦 Start Profiles ").
+		SetBorderColor(ColorOrange).
+		SetBorderPadding(1, 1, 2, 2)
+
+	for i, p := range d.cfg.StartProfiles {
+		p := p
+		var shortcut rune
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+		var steps string
+		for j, g := range p.Groups {
+			if j > 0 {
+				steps += " → "
+			}
+			steps += groupLabel(g, j)
+		}
+		menu.AddItem(p.Name, steps, shortcut, func() {
+			d.runStartProfile(p)
+		})
+	}
+	menu.AddItem("Cancel", "", 'q', func() {
+		setRoot(d.app, d.mainFlex)
+	})
+	menu.SetDoneFunc(func() {
+		setRoot(d.app, d.mainFlex)
+	})
+
+	showOverlay(d.app, d.mainFlex, menu, 70, min(8+2*len(d.cfg.StartProfiles), 26))
+}
+
+// groupLabel names a start group, falling back to its position
+func groupLabel(g config.StartGroup, index int) string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return fmt.Sprintf("group %d", index+1)
+}
+
+// runStartProfile starts the groups of a profile one after another, waiting for each
+// group's containers before starting the next. The first failure stops the profile.
+func (d *Dashboard) runStartProfile(p config.StartProfile) {
+	t := currentTheme()
+
+	d.mu.RLock()
+	byName := make(map[string]string, 2*len(d.containers))
+	for _, c := range d.containers {
+		byName[c.Name] = c.ID
+		byName[qualifiedName(c)] = c.ID
+	}
+	d.mu.RUnlock()
+
+	var items []*profileItem
+	for gi, g := range p.Groups {
+		for _, name := range g.Containers {
+			items = append(items, &profileItem{group: gi, name: name, id: byName[name], status: bulkPending})
+		}
+	}
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 🚦 Starting: %s ", p.Name)).
+		SetBorderColor(ColorYellow).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:red] c/ESC [-:-:-] Cancel remaining groups")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	statusColors := map[string]string{
+		bulkPending:   t.Muted,
+		bulkRunning:   t.Warning,
+		bulkDone:      t.Success,
+		bulkFailed:    t.Error,
+		bulkCancelled: t.Muted,
+	}
+
+	var mu sync.Mutex
+	current := 0
+
+	// render must be called on the UI goroutine
+	render := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for col, h := range []string{"GROUP", "CONTAINER", "WAIT", "STATUS", "DETAILS"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		counts := make(map[string]int)
+		for i, item := range items {
+			counts[item.status]++
+			g := p.Groups[item.group]
+
+			status := item.status
+			if item.status == bulkRunning {
+				status = "⏳ " + item.op
+			}
+			details := ""
+			if item.err != nil {
+				details = errorSummary(item.err)
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(fmt.Sprintf("%d. %s", item.group+1, groupLabel(g, item.group))).SetTextColor(tcell.GetColor(t.Accent)))
+			table.SetCell(i+1, 1, tview.NewTableCell(item.name).SetTextColor(tview.Styles.PrimaryTextColor))
+			wait := g.WaitCondition()
+			if wait != config.WaitNone {
+				wait += fmt.Sprintf(" ≤%s", g.WaitTimeout())
+			}
+			table.SetCell(i+1, 2, tview.NewTableCell(wait).SetTextColor(tcell.GetColor(t.Muted)))
+			table.SetCell(i+1, 3, tview.NewTableCell(status).SetTextColor(tcell.GetColor(statusColors[item.status])))
+			table.SetCell(i+1, 4, tview.NewTableCell(details).SetTextColor(tcell.GetColor(t.Error)).SetExpansion(1))
+		}
+
+		header.SetText(fmt.Sprintf(
+			" [%s]%s[-]: group %d of %d\n"+
+				" [%s]✓ Ready: %d[-]   [%s]✗ Failed: %d[-]   [%s]⏳ Starting: %d[-]   [%s]Pending: %d[-]   Cancelled: %d",
+			t.Accent, tview.Escape(p.Name), min(current+1, len(p.Groups)), len(p.Groups),
+			t.Success, counts[bulkDone], t.Error, counts[bulkFailed],
+			t.Warning, counts[bulkRunning], t.Muted, counts[bulkPending], counts[bulkCancelled]))
+	}
+	render()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := false
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if finished {
+			d.updateList()
+			setRoot(d.app, d.mainFlex)
+			return nil
+		}
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'c' || event.Rune() == 'C' {
+			cancel()
+			footer.SetText("[black:yellow] Cancelling: waiting for the current group... [-:-:-]")
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+
+	// update changes an item and redraws; it is safe from any goroutine
+	update := func(item *profileItem, status, op string, err error) {
+		mu.Lock()
+		item.status, item.op, item.err = status, op, err
+		mu.Unlock()
+		d.app.QueueUpdateDraw(render)
+	}
+
+	go func() {
+		defer cancel()
+
+		failed := false
+		for gi, g := range p.Groups {
+			if failed || ctx.Err() != nil {
+				break
+			}
+			mu.Lock()
+			current = gi
+			mu.Unlock()
+
+			var wg sync.WaitGroup
+			var groupFailed bool
+			var failedMu sync.Mutex
+			for _, item := range items {
+				if item.group != gi {
+					continue
+				}
+				wg.Add(1)
+				go func(item *profileItem) {
+					defer wg.Done()
+					if err := startProfileItem(ctx, g, item, update); err != nil {
+						if ctx.Err() != nil {
+							update(item, bulkCancelled, "", nil)
+							return
+						}
+						update(item, bulkFailed, "", err)
+						failedMu.Lock()
+						groupFailed = true
+						failedMu.Unlock()
+						return
+					}
+					update(item, bulkDone, "", nil)
+				}(item)
+			}
+			wg.Wait()
+			failed = groupFailed
+		}
+
+		cancelled := false
+		mu.Lock()
+		for _, item := range items {
+			if item.status == bulkPending {
+				item.status = bulkCancelled
+			}
+			cancelled = cancelled || item.status == bulkCancelled
+		}
+		mu.Unlock()
+
+		d.app.QueueUpdateDraw(func() {
+			finished = true
+			render()
+			switch {
+			case failed:
+				table.SetTitle(" ✗ Stopped at a failed group ")
+				table.SetBorderColor(ColorRed)
+			case cancelled:
+				table.SetTitle(" Cancelled ")
+			default:
+				table.SetTitle(" ✅ Complete ")
+				table.SetBorderColor(ColorGreen)
+			}
+			footer.SetText("[black:green] Press any key to continue [-:-:-]")
+		})
+	}()
+}
+
+// startProfileItem starts one container of a start group and waits for it as the
+// group says
+func startProfileItem(ctx context.Context, g config.StartGroup, item *profileItem, update func(*profileItem, string, string, error)) error {
+	if item.id == "" {
+		return fmt.Errorf("no container named %s", item.name)
+	}
+
+	update(item, bulkRunning, "starting", nil)
+	c := docker.ContainerInfo{ID: item.id, Name: item.name}
+	if err := hooked("start", c, func() error { return docker.StartContainer(item.id) }); err != nil {
+		return err
+	}
+
+	wait := g.WaitCondition()
+	if wait == config.WaitNone {
+		return nil
+	}
+	update(item, bulkRunning, "waiting until "+wait, nil)
+	waitCtx, cancel := context.WithTimeout(ctx, g.WaitTimeout())
+	defer cancel()
+	return docker.WaitReady(waitCtx, item.id, wait == config.WaitHealthy)
+}
@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/bundle"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/logarchive"
+)
+
+const (
+	bundleLogLines = 1000      // log lines included in a support bundle
+	bundleHistory  = time.Hour // stats history included in a support bundle
+	bundleTimeout  = time.Minute
+)
+
+// createSupportBundle writes inspect output, recent logs, stats, health data and the
+// process list of a container into one archive for attaching to an incident ticket
+func (d *Dashboard) createSupportBundle(container docker.ContainerInfo) {
+	modal := tview.NewModal().SetText(fmt.Sprintf("📦 Collecting support bundle for %s...", container.Name))
+	modal.SetBorder(true).SetTitle(" ⏳ Support Bundle ")
+	setRoot(d.app, modal)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(d.refreshCtx, bundleTimeout)
+		defer cancel()
+
+		now := time.Now()
+		files := d.supportBundleFiles(ctx, container, now)
+		path, err := bundle.Write(d.bundleDir(), logarchive.FileName(archiveName(container)), now, files)
+
+		d.app.QueueUpdateDraw(func() {
+			setRoot(d.app, d.mainFlex)
+			if err != nil {
+				showError(d.app, d.mainFlex, "📦 Support Bundle", err)
+				return
+			}
+			copyToClipboard(d.app, path)
+
+			if files[0].Name == "errors.txt" {
+				showToast(d.app, toastWarning, fmt.Sprintf("Support bundle written to %s (path copied), some data is missing: see errors.txt", path))
+				return
+			}
+			showToast(d.app, toastSuccess, fmt.Sprintf("Support bundle written to %s (path copied)", path))
+		})
+	}()
+}
+
+// bundleDir is where support bundles are written
+func (d *Dashboard) bundleDir() string {
+	if d.cfg.BundleDir != "" {
+		return d.cfg.BundleDir
+	}
+	return bundle.DefaultDir()
+}
+
+// supportBundleFiles collects the files of a support bundle. Whatever can't be collected
+// is listed in errors.txt, which then comes first, instead of failing the whole bundle.
+func (d *Dashboard) supportBundleFiles(ctx context.Context, c docker.ContainerInfo, now time.Time) []bundle.File {
+	var (
+		files    []bundle.File
+		problems []string
+	)
+	add := func(name string, data []byte, err error) {
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, errorSummary(err)))
+			return
+		}
+		files = append(files, bundle.File{Name: name, Data: data})
+	}
+	addJSON := func(name string, v any) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		add(name, data, err)
+	}
+	running := c.State == "running"
+
+	inspect, err := docker.InspectJSON(c.ID)
+	add("inspect.json", inspect, err)
+
+	lines, err := docker.RecentLogs(ctx, c.ID, bundleLogLines)
+	var logs strings.Builder
+	for _, line := range lines {
+		at, msg, _ := docker.SplitLogLine(line.Text)
+		fmt.Fprintf(&logs, "%s %s %s\n", at.Format(time.RFC3339Nano), line.Stream, msg)
+	}
+	add("logs.txt", []byte(logs.String()), err)
+
+	// The current sample only exists while the container runs; history covers the time before
+	stats := struct {
+		Current *docker.ContainerStats `json:"current,omitempty"`
+		History []historySample        `json:"history,omitempty"`
+	}{}
+	if running {
+		stats.Current, err = docker.GetStats(c.ID)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("stats.json: current sample: %s", errorSummary(err)))
+		}
+	}
+	if d.history != nil {
+		samples, err := d.history.Query(c.ID, now.Add(-bundleHistory), now)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("stats.json: history: %s", err))
+		}
+		for _, s := range samples {
+			stats.History = append(stats.History, historySample{s.Time, s.CPU, s.Mem})
+		}
+	}
+	addJSON("stats.json", stats)
+
+	health := struct {
+		Summary     map[string]string `json:"summary,omitempty"`
+		Healthcheck json.RawMessage   `json:"healthcheck,omitempty"`
+	}{}
+	health.Summary, err = docker.CheckContainerHealth(c.ID)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("health.json: %s", errorSummary(err)))
+	}
+	// The healthcheck probes with their output are part of the inspect data
+	var state struct {
+		State struct {
+			Health json.RawMessage
+		}
+	}
+	if json.Unmarshal(inspect, &state) == nil {
+		health.Healthcheck = state.State.Health
+	}
+	addJSON("health.json", health)
+
+	if running {
+		ps, err := docker.ProcessTable(c.ID)
+		add("processes.txt", []byte(ps), err)
+	} else {
+		problems = append(problems, fmt.Sprintf("processes.txt: container is %s", c.State))
+	}
+
+	summary := fmt.Sprintf("Container: %s\nID:        %s\nImage:     %s\nHost:      %s\nState:     %s\nStatus:    %s\nCollected: %s\n",
+		c.Name, c.ID, c.Image, hostLabel(c), c.State, c.Status, now.Format(time.RFC3339))
+	files = append([]bundle.File{{Name: "summary.txt", Data: []byte(summary)}}, files...)
+	if len(problems) > 0 {
+		files = append([]bundle.File{{Name: "errors.txt", Data: []byte(strings.Join(problems, "\n") + "\n")}}, files...)
+	}
+	return files
+}
+
+// historySample is a stats history sample as written to a support bundle
+type historySample struct {
+	Time time.Time `json:"time"`
+	CPU  float64   `json:"cpu_percent"`
+	Mem  float64   `json:"memory_percent"`
+}
@@ -0,0 +1,285 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// tabPage is one top-level section of the dashboard
+type tabPage struct {
+	name    string
+	title   string
+	content tview.Primitive
+	hints   []string    // actions advertised in the status bar
+	keys    [][2]string // fixed keys of the tab and what they do, added to the hints
+	onShow  func()
+}
+
+// buildLayout wraps the container view and the resource tabs into the tabbed root layout
+func (d *Dashboard) buildLayout(containersView tview.Primitive) *tview.Flex {
+	images := newResourceTable(" 🖼️  Images ", []string{"REPOSITORY:TAG", "ID", "SIZE", "CREATED", "CONTAINERS"}, loadImageRows)
+	images.table.SetSelectedFunc(func(row, column int) {
+		if id := images.selectedKey(); id != "" {
+			d.showImageAnalysis(id, images.rows[row-1][0], images.table)
+		}
+	})
+	images.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return d.imageKeys(images, event)
+	})
+	volumes := newResourceTable(" 💾 Volumes ", []string{"NAME", "DRIVER", "SCOPE", "CREATED", "MOUNTPOINT"}, loadVolumeRows)
+	volumes.table.SetSelectedFunc(func(row, column int) {
+		if name := volumes.selectedKey(); name != "" {
+			d.showVolumeBrowser(name, volumes.table)
+		}
+	})
+	networks := newResourceTable(" 🌐 Networks ", []string{"NAME", "ID", "DRIVER", "SCOPE", "SUBNETS", "CONTAINERS"}, loadNetworkRows)
+
+	d.eventsView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetMaxLines(1000)
+	d.eventsView.SetBorder(true).
+		SetTitle(" 📡 Docker Events ").
+		SetBorderPadding(0, 0, 1, 1).
+		SetBorderColor(tcell.ColorDodgerBlue)
+
+	systemView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	systemView.SetBorder(true).
+		SetTitle(" 💻 System ").
+		SetBorderPadding(1, 1, 2, 2).
+		SetBorderColor(tcell.ColorTeal)
+
+	d.tabs = []tabPage{
+		{name: "containers", title: "🐳 Containers", content: containersView,
+			hints: []string{actionRefresh, actionLabels, actionTheme}},
+		{name: "images", title: "🖼️  Images", content: images.table,
+			hints: []string{actionRefresh}, keys: [][2]string{{"Space", "Mark"}, {"t", "Tag"}, {"d", "Remove"}, {"p", "Prune repo"}, {"s", "Save"}, {"l", "Load"}, {"r", "Registry"}, {"n", "Run"}},
+			onShow: func() { images.refresh(d.app) }},
+		{name: "volumes", title: "💾 Volumes", content: volumes.table,
+			hints: []string{actionRefresh}, onShow: func() { volumes.refresh(d.app) }},
+		{name: "networks", title: "🌐 Networks", content: networks.table,
+			hints: []string{actionRefresh}, onShow: func() { networks.refresh(d.app) }},
+		{name: "events", title: "📡 Events", content: d.eventsView},
+		{name: "system", title: "💻 System", content: systemView,
+			hints: []string{actionRefresh}, onShow: func() { loadSystemInfo(d.app, systemView) }},
+	}
+
+	d.pages = tview.NewPages()
+	for i, tab := range d.tabs {
+		d.pages.AddPage(tab.name, tab.content, true, i == 0)
+	}
+
+	d.tabBar = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+
+	d.statusBar = tview.NewTextView().
+		SetDynamicColors(true)
+
+	// Hidden until the connection watchdog reports the daemon offline
+	d.offlineBanner = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+
+	root := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(d.tabBar, 1, 0, false).
+		AddItem(d.offlineBanner, 0, 0, false).
+		AddItem(d.pages, 0, 1, true).
+		AddItem(d.statusBar, 1, 0, false)
+
+	screens = &screenStack{root: root, pages: d.pages,
+		tab:     func() string { return d.tabs[d.currentTab].name },
+		changed: d.updateStatusBar}
+
+	root.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if screens.active() {
+			return event // the open screen handles its own keys
+		}
+		switch d.keys.Action(event) {
+		case actionNextTab:
+			d.switchTab((d.currentTab + 1) % len(d.tabs))
+			return nil
+		case actionPrevTab:
+			d.switchTab((d.currentTab + len(d.tabs) - 1) % len(d.tabs))
+			return nil
+		case actionRefresh:
+			if tab := d.tabs[d.currentTab]; tab.onShow != nil {
+				tab.onShow()
+				return nil
+			}
+		case actionHelp:
+			ShowHelp(d.app, d.mainFlex, d.keys)
+			return nil
+		case actionDiagnostics:
+			showDiagnostics(d.app, d.mainFlex)
+			return nil
+		case actionHosts:
+			d.showHostSwitcher()
+			return nil
+		case actionOverview:
+			d.showOverview()
+			return nil
+		case actionTrash:
+			if d.kioskAllows(actionTrash) {
+				d.showTrash()
+			}
+			return nil
+		case actionCleanup:
+			if d.kioskAllows(actionCleanup) {
+				d.showCleanup()
+			}
+			return nil
+		case actionUptime:
+			d.showUptime()
+			return nil
+		case actionPinning:
+			d.showPinningAudit()
+			return nil
+		case actionAutoFollow:
+			d.showAutoFollow()
+			return nil
+		case actionDeployWatch:
+			d.showDeployTargets()
+			return nil
+		case actionFleetHealth:
+			d.focusHealthStrip()
+			return nil
+		case actionRecent:
+			d.showRecent()
+			return nil
+		case actionReport:
+			if d.kioskAllows(actionReport) {
+				d.exportReport()
+			}
+			return nil
+		case actionQuit:
+			d.cleanup()
+			d.app.Stop()
+			return nil
+		}
+		return event
+	})
+
+	d.renderTabBar()
+	d.updateStatusBar()
+	return root
+}
+
+// switchTab brings the given tab to the front and refreshes its content
+func (d *Dashboard) switchTab(index int) {
+	if index < 0 || index >= len(d.tabs) {
+		return
+	}
+	d.currentTab = index
+	tab := d.tabs[index]
+
+	screens.truncate(0)
+	d.pages.SwitchToPage(tab.name)
+	d.renderTabBar()
+	d.updateStatusBar()
+	d.app.SetFocus(tab.content)
+
+	if tab.onShow != nil {
+		tab.onShow()
+	}
+}
+
+func (d *Dashboard) renderTabBar() {
+	t := currentTheme()
+	var b strings.Builder
+	for i, tab := range d.tabs {
+		if i == d.currentTab {
+			fmt.Fprintf(&b, `["%s"][%s:%s:b] %s [-:-:-][""] `, tab.name, t.Background, t.Info, tab.title)
+		} else {
+			fmt.Fprintf(&b, `["%s"][%s] %s [-:-:-][""] `, tab.name, t.Muted, tab.title)
+		}
+	}
+	d.tabBar.SetText(b.String())
+}
+
+// updateStatusBar redraws the shared status bar for the current tab
+func (d *Dashboard) updateStatusBar() {
+	t := currentTheme()
+	var hints []string
+	for _, action := range d.tabs[d.currentTab].hints {
+		hints = append(hints, fmt.Sprintf("[%s]%s[-] %s", t.Highlight, d.keys.KeyLabel(action), d.keys.Description(action)))
+	}
+	if len(hints) == 0 {
+		hints = append(hints, fmt.Sprintf("[%s]↑/↓[-] Scroll", t.Highlight))
+	}
+	if !d.cfg.Kiosk {
+		for _, k := range d.tabs[d.currentTab].keys {
+			hints = append(hints, fmt.Sprintf("[%s]%s[-] %s", t.Highlight, k[0], k[1]))
+		}
+	}
+	// Kiosk mode only offers the read-only overview
+	if d.cfg.Kiosk {
+		hints = []string{fmt.Sprintf("[%s]KIOSK[-] read-only  [%s]%s[-] %s", t.Warning,
+			t.Highlight, d.keys.KeyLabel(actionOverview), d.keys.Description(actionOverview))}
+	}
+
+	clock := time.Now().Format("15:04:05")
+	if d.cfg.LowPower {
+		clock = "🔋 low power " + clock
+	}
+
+	// The tab keys wait until the open screen is closed
+	if screens.active() && !d.cfg.Kiosk {
+		d.statusBar.SetText(fmt.Sprintf(" [%s]ESC[-] Back │ [%s]%s[-]", t.Highlight, t.Muted, clock))
+		return
+	}
+
+	d.statusBar.SetText(fmt.Sprintf(
+		" [%[1]s]%[2]s/%[3]s[-] Switch tab │ %[4]s │ [%[1]s]%[5]s[-] Help  [%[1]s]%[6]s[-] Quit │ [%[7]s]%[8]s[-]",
+		t.Highlight, d.keys.KeyLabel(actionNextTab), d.keys.KeyLabel(actionPrevTab), strings.Join(hints, "  "),
+		d.keys.KeyLabel(actionHelp), d.keys.KeyLabel(actionQuit), t.Muted, clock))
+}
+
+// appendEvent writes a daemon event to the Events tab
+func (d *Dashboard) appendEvent(event docker.EventInfo) {
+	color := "white"
+	switch event.Action {
+	case "start", "unpause", "create":
+		color = "lime"
+	case "die", "kill", "oom", "destroy":
+		color = "red"
+	case "stop", "pause":
+		color = "yellow"
+	}
+
+	name := event.Name
+	if name == "" && len(event.ActorID) >= 12 {
+		name = event.ActorID[:12]
+	}
+	if d.allHostsMode() {
+		name = event.Host + "/" + name
+	}
+
+	fmt.Fprintf(d.eventsView, "[gray]%s[-] [cyan]%-9s[-] [%s]%-14s[-] %s\n",
+		event.Time.Format("15:04:05"), event.Type, color, event.Action, name)
+	d.eventsView.ScrollToEnd()
+}
+
+func loadSystemInfo(app *tview.Application, view *tview.TextView) {
+	view.SetText("[yellow]⏳ Loading system information...[-]")
+	go func() {
+		info, err := docker.GetDockerInfo()
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				view.SetText(fmt.Sprintf("[red]Error:[-] %s", errorText(err)))
+				return
+			}
+			view.SetText(info)
+		})
+	}()
+}
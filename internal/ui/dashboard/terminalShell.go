@@ -0,0 +1,71 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// shellTerminal is the shell_terminal setting of the config, empty for the built-in shell
+var shellTerminal string
+
+// setShellTerminal installs the shell_terminal setting of the config
+func setShellTerminal(cfg *config.Config) {
+	shellTerminal = cfg.ShellTerminal
+}
+
+// multiplexer names the terminal multiplexer DockPulse runs in: "tmux", "screen" or ""
+func multiplexer() string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "tmux"
+	case os.Getenv("STY") != "":
+		return "screen"
+	}
+	return ""
+}
+
+// terminalPlace describes where openTerminalShell puts a shell, e.g. "tmux pane"
+func terminalPlace() string {
+	if multiplexer() == "tmux" && shellTerminal == config.ShellTerminalPane {
+		return "tmux pane"
+	}
+	return multiplexer() + " window"
+}
+
+// openTerminalShell opens an interactive shell in a new tmux window or pane, or a new
+// screen window, where it gets a full terminal while DockPulse stays open. screen has
+// no panes that take a command, so it always gets a window.
+func openTerminalShell(containerID, containerName string, shell docker.Shell) error {
+	command, err := docker.TerminalCommand(containerID, shell)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	switch multiplexer() {
+	case "tmux":
+		if shellTerminal == config.ShellTerminalPane {
+			args = append([]string{"tmux", "split-window", "-h"}, command...)
+		} else {
+			args = append([]string{"tmux", "new-window", "-n", containerName}, command...)
+		}
+	case "screen":
+		args = append([]string{"screen", "-X", "screen", "-t", containerName}, command...)
+	default:
+		return errors.New("DockPulse is not running inside tmux or screen")
+	}
+
+	if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", args[0], msg)
+		}
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	return nil
+}
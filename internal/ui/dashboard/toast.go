@@ -0,0 +1,72 @@
+package dashboard
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// toastDuration is how long a toast stays on screen
+const toastDuration = 4 * time.Second
+
+// Toast levels pick the icon and color of a toast
+const (
+	toastSuccess = iota
+	toastInfo
+	toastWarning
+)
+
+// toastState is the toast currently shown, if any
+type toastState struct {
+	mu    sync.Mutex
+	text  string // with color tags
+	until time.Time
+	timer *time.Timer
+}
+
+var toast toastState
+
+// installToasts draws the current toast on the bottom line of whatever screen is open,
+// so success messages don't need a modal that takes the focus
+func installToasts(app *tview.Application) {
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		toast.mu.Lock()
+		text, until := toast.text, toast.until
+		toast.mu.Unlock()
+		if text == "" || time.Now().After(until) {
+			return
+		}
+		width, height := screen.Size()
+		tview.Print(screen, text, 0, height-1, width, tview.AlignRight, tcell.ColorDefault)
+	})
+}
+
+// showToast shows msg for toastDuration, replacing any toast still on screen. It can
+// be called from any goroutine.
+func showToast(app *tview.Application, level int, msg string) {
+	t := currentTheme()
+	icon, color := "✓", t.Success
+	switch level {
+	case toastInfo:
+		icon, color = "ℹ", t.Info
+	case toastWarning:
+		icon, color = "⚠", t.Warning
+	}
+
+	toast.mu.Lock()
+	toast.text = fmt.Sprintf("[black:%s] %s %s [-:-:-]", color, icon, tview.Escape(msg))
+	toast.until = time.Now().Add(toastDuration)
+	if toast.timer != nil {
+		toast.timer.Stop()
+	}
+	// Redraw once more when it expires so it disappears without a key press
+	redraw := func() { app.QueueUpdateDraw(func() {}) }
+	toast.timer = time.AfterFunc(toastDuration, redraw)
+	toast.mu.Unlock()
+
+	// Callers may be on the UI goroutine, where queueing an update would block
+	go redraw()
+}
@@ -0,0 +1,231 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/trash"
+)
+
+// trashStore records deleted containers; nil unless the trash is enabled
+var trashStore *trash.Store
+
+// setTrash opens the trash when the config enables it
+func setTrash(cfg *config.Config) {
+	if !cfg.Trash {
+		return
+	}
+	path := cfg.TrashFile
+	if path == "" {
+		path = trash.DefaultPath()
+	}
+	trashStore = trash.Open(path)
+}
+
+// removeContainer deletes a container. With the trash enabled it is committed to a
+// rescue image and recorded first, and nothing is deleted if that fails.
+func removeContainer(id string) error {
+	if trashStore == nil {
+		return docker.RemoveContainer(id)
+	}
+
+	rescue, err := docker.RescueContainer(id)
+	if err != nil {
+		return fmt.Errorf("not deleted, saving a rescue image failed: %w", err)
+	}
+	err = trashStore.Add(trash.Entry{
+		Name:    rescue.Name,
+		Host:    rescue.Endpoint.Name,
+		Image:   rescue.Image,
+		Rescue:  rescue.Ref,
+		Deleted: time.Now(),
+		Config:  rescue.Config,
+	})
+	if err != nil {
+		docker.RemoveRescueImage(rescue.Endpoint, rescue.Ref)
+		return fmt.Errorf("not deleted: %w", err)
+	}
+
+	if err := docker.RemoveRescuedContainer(id); err != nil {
+		trashStore.Remove(rescue.Ref)
+		docker.RemoveRescueImage(rescue.Endpoint, rescue.Ref)
+		return err
+	}
+	return nil
+}
+
+// deleteWarning tells what deleting a container means for getting it back
+func deleteWarning() string {
+	if trashStore == nil {
+		return "This action cannot be undone!"
+	}
+	return "A rescue image is kept; restore it from Recently Deleted."
+}
+
+// trashEndpoint finds the daemon a container was deleted on by the endpoint name
+// recorded with it
+func (d *Dashboard) trashEndpoint(host string) (docker.Endpoint, error) {
+	if current := docker.CurrentEndpoint(); current.Name == host {
+		return current, nil
+	}
+	if host == "" {
+		return docker.Endpoint{}, nil
+	}
+	return SelectHost(d.cfg, host)
+}
+
+// trashFooter lists the keys of the Recently Deleted screen
+const trashFooter = "[black:green] Enter [-:-:-] Restore   [black:red] d [-:-:-] Delete rescue image   [black:red] ESC [-:-:-] Back"
+
+// showTrash lists the deleted containers that still have a rescue image. Enter
+// restores one, d deletes its rescue image for good.
+func (d *Dashboard) showTrash() {
+	if trashStore == nil {
+		showMessage(d.app, d.mainFlex, "🗑 Recently Deleted",
+			fmt.Sprintf("The trash is off.\n\nSet \"trash\": true in %s to keep a rescue image of deleted containers.", config.Path()))
+		return
+	}
+
+	t := currentTheme()
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(" 🗑 Recently Deleted ").
+		SetBorderColor(tcell.GetColor(t.Warning)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText(trashFooter)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var entries []trash.Entry
+	load := func() {
+		var err error
+		entries, err = trashStore.List()
+		table.Clear()
+		for col, h := range []string{"NAME", "HOST", "IMAGE", "DELETED", "RESCUE IMAGE"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		if err != nil {
+			table.SetCell(1, 0, tview.NewTableCell(errorSummary(err)).SetTextColor(tcell.GetColor(t.Error)).SetSelectable(false))
+			return
+		}
+		if len(entries) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No deleted containers").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+			return
+		}
+		for i, e := range entries {
+			host := e.Host
+			if host == "" {
+				host = "local"
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(e.Name).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 1, tview.NewTableCell(host).SetTextColor(tcell.GetColor(t.Muted)))
+			table.SetCell(i+1, 2, tview.NewTableCell(e.Image).SetTextColor(tcell.GetColor(t.Info)))
+			table.SetCell(i+1, 3, tview.NewTableCell(e.Deleted.Local().Format("2006-01-02 15:04:05")).SetTextColor(tview.Styles.PrimaryTextColor))
+			table.SetCell(i+1, 4, tview.NewTableCell(e.Rescue).SetTextColor(tcell.GetColor(t.Muted)).SetExpansion(1))
+		}
+	}
+	load()
+
+	selected := func() (trash.Entry, bool) {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(entries) {
+			return trash.Entry{}, false
+		}
+		return entries[row-1], true
+	}
+
+	restore := func(e trash.Entry) {
+		showConfirmation(d.app, flex, fmt.Sprintf("Restore container '%s' from %s?", e.Name, e.Rescue), func() {
+			footer.SetText(fmt.Sprintf("[%s]Restoring %s...[-]", t.Warning, tview.Escape(e.Name)))
+			go func() {
+				endpoint, err := d.trashEndpoint(e.Host)
+				var id string
+				if err == nil {
+					id, err = docker.RestoreContainer(endpoint, e.Rescue, e.Config)
+				}
+				// A restored container that failed to start exists all the same
+				if id != "" {
+					trashStore.Remove(e.Rescue)
+				}
+				d.app.QueueUpdateDraw(func() {
+					load()
+					footer.SetText(trashFooter)
+					d.updateList()
+					switch {
+					case err != nil && id == "":
+						showError(d.app, flex, "Restore Failed", err)
+					case err != nil:
+						showError(d.app, flex, "Restored, Not Started", err)
+					default:
+						showToast(d.app, toastSuccess, fmt.Sprintf("Container %s restored", e.Name))
+					}
+				})
+			}()
+		})
+	}
+
+	purge := func(e trash.Entry) {
+		showConfirmation(d.app, flex, fmt.Sprintf("Delete rescue image %s?\n\n%s can no longer be restored.", e.Rescue, e.Name), func() {
+			go func() {
+				endpoint, err := d.trashEndpoint(e.Host)
+				if err == nil {
+					err = docker.RemoveRescueImage(endpoint, e.Rescue)
+				}
+				// An image removed by hand leaves nothing to restore either
+				if err == nil || errors.Is(err, docker.ErrNotFound) {
+					err = trashStore.Remove(e.Rescue)
+				}
+				d.app.QueueUpdateDraw(func() {
+					load()
+					if err != nil {
+						showError(d.app, flex, "Error", err)
+						return
+					}
+					showToast(d.app, toastSuccess, fmt.Sprintf("Rescue image of %s deleted", e.Name))
+				})
+			}()
+		})
+	}
+
+	table.SetSelectedFunc(func(row, _ int) {
+		if e, ok := selected(); ok {
+			restore(e)
+		}
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		case event.Rune() == 'd' || event.Key() == tcell.KeyDelete:
+			if e, ok := selected(); ok {
+				purge(e)
+			}
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
@@ -0,0 +1,322 @@
+package dashboard
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+	"devops-dashboard/internal/history"
+)
+
+// uptimeWindows are selected with 1-3 in the uptime report
+var uptimeWindows = []historyRange{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// uptimeStates maps container events to the state they leave the container in
+var uptimeStates = map[string]history.State{
+	"start":   history.Up,
+	"unpause": history.Up,
+	"die":     history.Down,
+	"pause":   history.Down,
+	"destroy": history.Removed,
+}
+
+// recordUptime stores the state a container event leaves the container in
+func (d *Dashboard) recordUptime(event docker.EventInfo) {
+	state, ok := uptimeStates[event.Action]
+	if d.history == nil || event.Type != "container" || !ok {
+		return
+	}
+	d.history.RecordStates(event.Time, []history.StateChange{{
+		ID:    event.ActorID,
+		Name:  event.Name,
+		Host:  hostLabel(docker.ContainerInfo{Host: event.Host}),
+		State: state,
+	}})
+}
+
+// reconcileUptime records the state of the listed containers as of when they were
+// listed, which catches up on events missed while the event stream was down
+func (d *Dashboard) reconcileUptime() {
+	d.mu.RLock()
+	containers, listedAt := d.containers, d.listedAt
+	d.mu.RUnlock()
+	if listedAt.IsZero() {
+		return
+	}
+
+	changes := make([]history.StateChange, len(containers))
+	for i, c := range containers {
+		state := history.Down
+		if c.State == "running" {
+			state = history.Up
+		}
+		changes[i] = history.StateChange{ID: c.ID, Name: c.Name, Host: hostLabel(c), State: state}
+	}
+	d.history.RecordStates(listedAt, changes)
+}
+
+// reconcileRemoved marks the containers of the current host that were deleted while
+// DockPulse wasn't running. Only a single host listing tells for sure which are gone.
+func (d *Dashboard) reconcileRemoved() {
+	d.mu.RLock()
+	containers, listedAt := d.containers, d.listedAt
+	d.mu.RUnlock()
+	if listedAt.IsZero() || d.allHostsMode() {
+		return
+	}
+
+	tracked, err := d.history.Tracked(hostLabel(docker.ContainerInfo{}))
+	if err != nil {
+		return
+	}
+	listed := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		listed[c.ID] = true
+	}
+	var changes []history.StateChange
+	for id, state := range tracked {
+		if state != history.Removed && !listed[id] {
+			changes = append(changes, history.StateChange{ID: id, State: history.Removed})
+		}
+	}
+	if len(changes) > 0 {
+		d.history.RecordStates(listedAt, changes)
+	}
+}
+
+// uptimeColor colors an uptime percentage against the usual availability targets
+func uptimeColor(percent float64) string {
+	t := currentTheme()
+	switch {
+	case percent >= 99.9:
+		return t.Success
+	case percent >= 99:
+		return t.Warning
+	}
+	return t.Error
+}
+
+// formatDowntime prints a duration in its two largest units, e.g. 3h 5m or 45s
+func formatDowntime(d time.Duration) string {
+	d = d.Round(time.Second)
+	days, hours := int(d.Hours())/24, int(d.Hours())%24
+	minutes, seconds := int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// showUptime reports how long every container was up over the last day, week or month,
+// from the state changes recorded in the history file. e exports the report as CSV.
+func (d *Dashboard) showUptime() {
+	if d.history == nil {
+		msg := "The uptime report needs the stats history, which is disabled (history_retention is \"off\")."
+		if d.historyErr != nil {
+			msg = fmt.Sprintf("The uptime report needs the stats history, which is unavailable:\n\n%s", d.historyErr)
+		}
+		showMessage(d.app, d.mainFlex, "⏱ Uptime", msg)
+		return
+	}
+
+	t := currentTheme()
+	selected := 1
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var report []history.Availability
+	var from, to time.Time
+
+	render := func() {
+		w := uptimeWindows[selected]
+		to = time.Now()
+		from = to.Add(-w.period)
+		table.SetTitle(fmt.Sprintf(" ⏱ Uptime: last %s ", w.label))
+
+		windows := ""
+		for i, ww := range uptimeWindows {
+			if i == selected {
+				windows += fmt.Sprintf("[black:aqua] %d %s [-:-]  ", i+1, ww.label)
+			} else {
+				windows += fmt.Sprintf("[aqua]%d[-] %s  ", i+1, ww.label)
+			}
+		}
+		footer.SetText(windows + "[black:green] e [-:-:-] Export CSV   [black:green] r [-:-:-] Reload   [black:red] ESC [-:-:-] Back")
+
+		table.Clear()
+		var err error
+		report, err = d.history.Availability(from, to)
+		sort.Slice(report, func(i, j int) bool {
+			if pi, pj := report[i].Percent(), report[j].Percent(); pi != pj {
+				return pi < pj
+			}
+			return report[i].Name < report[j].Name
+		})
+
+		hosts := make(map[string]bool)
+		for _, a := range report {
+			hosts[a.Host] = true
+		}
+		headers := []string{"CONTAINER", "UPTIME", "DOWNTIME", "OUTAGES", "TRACKED"}
+		if len(hosts) > 1 {
+			headers = append([]string{"HOST"}, headers...)
+		}
+		for col, h := range headers {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+		if err != nil {
+			header.SetText(fmt.Sprintf(" [%s]Report failed[-]", t.Error))
+			table.SetCell(1, 0, tview.NewTableCell(errorSummary(err)).SetTextColor(tcell.GetColor(t.Error)).SetSelectable(false))
+			return
+		}
+
+		var up, tracked time.Duration
+		for i, a := range report {
+			up += a.Up
+			tracked += a.Tracked
+			col := 0
+			if len(hosts) > 1 {
+				table.SetCell(i+1, 0, tview.NewTableCell(a.Host).SetTextColor(tcell.GetColor(t.Info)))
+				col = 1
+			}
+			table.SetCell(i+1, col, tview.NewTableCell(a.Name).SetTextColor(tview.Styles.PrimaryTextColor).SetExpansion(1))
+			table.SetCell(i+1, col+1, tview.NewTableCell(fmt.Sprintf("%.2f%%", a.Percent())).
+				SetTextColor(tcell.GetColor(uptimeColor(a.Percent()))).SetAlign(tview.AlignRight))
+			table.SetCell(i+1, col+2, tview.NewTableCell(formatDowntime(a.Tracked-a.Up)).SetAlign(tview.AlignRight))
+			table.SetCell(i+1, col+3, tview.NewTableCell(strconv.Itoa(a.Outages)).SetAlign(tview.AlignRight))
+			table.SetCell(i+1, col+4, tview.NewTableCell(formatDowntime(a.Tracked)).SetTextColor(tcell.GetColor(t.Muted)).SetAlign(tview.AlignRight))
+		}
+		if len(report) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No container states recorded in this window yet").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+
+		overall := "-"
+		if tracked > 0 {
+			percent := 100 * float64(up) / float64(tracked)
+			overall = fmt.Sprintf("[%s]%.2f%%[-]", uptimeColor(percent), percent)
+		}
+		header.SetText(fmt.Sprintf(
+			" [%s::b]%d containers[-:-:-]   overall %s   %s … %s\n"+
+				" [%s]Recorded from daemon events while DockPulse runs; between sessions a container keeps its last state[-]",
+			t.Accent, len(report), overall, from.Format("Jan 2 15:04"), to.Format("Jan 2 15:04"), t.Muted))
+		table.ScrollToBeginning()
+	}
+
+	export := func() {
+		if d.cfg.Kiosk {
+			d.flashStatus(fmt.Sprintf("[%s]Exports are disabled in kiosk mode[-]", t.Warning))
+			return
+		}
+		dir := d.downloadDir()
+		path := filepath.Join(dir, fmt.Sprintf("dockpulse-uptime-%s-%s.csv", uptimeWindows[selected].label, to.Format("20060102-1504")))
+		if err := writeUptimeCSV(path, report, from, to); err != nil {
+			showError(d.app, flex, "Export failed", err)
+			return
+		}
+		copyToClipboard(d.app, path)
+		showToast(d.app, toastSuccess, fmt.Sprintf("Exported %d containers to %s (path copied)", len(report), path))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q' || d.keys.Action(event) == actionBack:
+			setRoot(d.app, d.mainFlex)
+			d.app.SetFocus(d.list)
+			return nil
+		case event.Key() == tcell.KeyLeft:
+			selected = (selected + len(uptimeWindows) - 1) % len(uptimeWindows)
+			render()
+			return nil
+		case event.Key() == tcell.KeyRight:
+			selected = (selected + 1) % len(uptimeWindows)
+			render()
+			return nil
+		case event.Rune() >= '1' && int(event.Rune()-'1') < len(uptimeWindows):
+			selected = int(event.Rune() - '1')
+			render()
+			return nil
+		case event.Rune() == 'e':
+			export()
+			return nil
+		case event.Rune() == 'r':
+			render()
+			return nil
+		}
+		return event
+	})
+
+	render()
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+}
+
+// writeUptimeCSV writes an uptime report with one row per container
+func writeUptimeCSV(path string, report []history.Availability, from, to time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"container", "host", "id", "from", "to", "uptime_percent", "downtime_seconds", "outages", "tracked_seconds"})
+	for _, a := range report {
+		w.Write([]string{
+			a.Name,
+			a.Host,
+			a.ID[:min(12, len(a.ID))],
+			from.UTC().Format(time.RFC3339),
+			to.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(a.Percent(), 'f', 3, 64),
+			strconv.FormatInt(int64((a.Tracked - a.Up).Seconds()), 10),
+			strconv.Itoa(a.Outages),
+			strconv.FormatInt(int64(a.Tracked.Seconds()), 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
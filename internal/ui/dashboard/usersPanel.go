@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"devops-dashboard/internal/docker"
+)
+
+// usersHeaders are the columns of the Users inspect tab
+var usersHeaders = []string{"WHAT", "IN THE CONTAINER", "ON THE HOST"}
+
+// fillUsersTab shows which host users and groups the processes of a container run as,
+// and who owns its bind mounts on both sides. Host IDs the container can't map are
+// highlighted, as files owned by them show up as nobody and usually can't be written.
+func fillUsersTab(tab *inspectTab, m *docker.UserMapping) {
+	t := currentTheme()
+	var rows [][]string
+	var unmapped []int // rows to highlight
+
+	switch {
+	case m.UIDMap == nil:
+		rows = append(rows, []string{"User namespace", "unknown", "the image has no shell to read /proc/1/uid_map with"})
+	case m.UIDMap.Identity():
+		rows = append(rows, []string{"User namespace", "none", "container IDs are host IDs: root inside is root on the host"})
+	default:
+		root, _ := m.UIDMap.ToHost(0)
+		rows = append(rows, []string{"User namespace", "remapped", fmt.Sprintf("root inside is UID %d on the host", root)})
+	}
+	if m.UsernsMode != "" {
+		rows = append(rows, []string{"--userns", m.UsernsMode, ""})
+	}
+	user, host := m.User, ""
+	if user == "" {
+		user = "image default"
+	}
+	name, _, _ := strings.Cut(m.User, ":")
+	if uid, err := strconv.ParseInt(name, 10, 64); err == nil && m.UIDMap != nil {
+		if h, ok := m.UIDMap.ToHost(uid); ok {
+			host = fmt.Sprintf("UID %d", h)
+		}
+	}
+	rows = append(rows, []string{"Configured user", user, host})
+	if len(m.GroupAdd) > 0 {
+		rows = append(rows, []string{"Extra groups", strings.Join(m.GroupAdd, ", "), ""})
+	}
+
+	idRanges := func(label string, idMap docker.IDMap) {
+		for _, r := range idMap {
+			rows = append(rows, []string{label, idRange(r.Inside, r.Count), idRange(r.Host, r.Count)})
+		}
+	}
+	idRanges("UID map", m.UIDMap)
+	idRanges("GID map", m.GIDMap)
+
+	// containerIDs shows host IDs as the container sees them
+	containerIDs := func(uid, gid int64) (string, bool) {
+		if m.UIDMap == nil {
+			return "?", true
+		}
+		cuid, uidOK := m.UIDMap.ToContainer(uid)
+		cgid, gidOK := m.GIDMap.ToContainer(gid)
+		text := fmt.Sprintf("%d:%d", cuid, cgid)
+		if !uidOK || !gidOK {
+			text += " (nobody, unmapped)"
+		}
+		return text, uidOK && gidOK
+	}
+
+	for _, p := range m.Processes {
+		inside, ok := containerIDs(p.HostUID, p.HostGID)
+		if !ok {
+			unmapped = append(unmapped, len(rows))
+		}
+		rows = append(rows, []string{fmt.Sprintf("PID %s %s", p.PID, p.Command), inside, fmt.Sprintf("%d:%d", p.HostUID, p.HostGID)})
+	}
+
+	for _, b := range m.Binds {
+		label := "Bind " + b.Destination
+		if !b.Checked {
+			rows = append(rows, []string{label, "?", b.Source + " (not checked: remote daemon or unreadable)"})
+			continue
+		}
+		inside, ok := containerIDs(b.UID, b.GID)
+		if !ok {
+			unmapped = append(unmapped, len(rows))
+		}
+		rows = append(rows, []string{label, "owner " + inside, fmt.Sprintf("%s owner %d:%d", b.Source, b.UID, b.GID)})
+	}
+
+	tab.setRows(usersHeaders, rows, columnValues(rows, 2))
+	for _, row := range unmapped {
+		tab.table.GetCell(row+1, 1).SetTextColor(tcell.GetColor(t.Warning))
+	}
+}
+
+// idRange formats count IDs from first, e.g. "100000-165535"
+func idRange(first, count int64) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", first)
+	}
+	return fmt.Sprintf("%d-%d", first, first+count-1)
+}
@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"devops-dashboard/internal/config"
+	"devops-dashboard/internal/docker"
+)
+
+// viewMatches reports whether a container satisfies every condition of a saved view
+func viewMatches(v *config.View, c docker.ContainerInfo) bool {
+	if v == nil {
+		return true
+	}
+	if v.State != "" && c.State != v.State {
+		return false
+	}
+	if v.Image != "" && !strings.Contains(c.Image, v.Image) {
+		return false
+	}
+	for key, want := range v.Labels {
+		got, ok := c.Labels[key]
+		if !ok {
+			return false
+		}
+		// "*" only requires the label to be present
+		if want != "*" && got != want {
+			return false
+		}
+	}
+	return v.MatchesName(c.Name)
+}
+
+// selectView activates the saved view bound to number key n (0 shows everything,
+// dropping the health strip filter too)
+func (d *Dashboard) selectView(n int) {
+	d.mu.Lock()
+	if n == 0 {
+		d.activeView = nil
+		d.healthFilter = ""
+	} else if n <= len(d.cfg.Views) {
+		d.activeView = &d.cfg.Views[n-1]
+	} else {
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+
+	d.updateListTitle()
+	d.updateList()
+}
+
+// updateListTitle shows the current host and saved view in the container list title
+func (d *Dashboard) updateListTitle() {
+	title := " 🐳 Docker Containers "
+	if d.allHostsMode() {
+		title += "@ all hosts "
+	} else if e := docker.CurrentEndpoint(); e.Name != "" {
+		title += "@ " + e.Name + " "
+	}
+	d.mu.RLock()
+	order := d.listSort
+	d.mu.RUnlock()
+	if order != sortDaemon {
+		title += fmt.Sprintf("↓%s ", order)
+	}
+	if view := d.currentView(); view != nil {
+		for i := range d.cfg.Views {
+			if &d.cfg.Views[i] == view {
+				title += fmt.Sprintf("[%d: %s] ", i+1, view.Name)
+			}
+		}
+	}
+	if filter := d.currentHealthFilter(); filter != "" {
+		title += fmt.Sprintf("[%s only] ", filter)
+	}
+	d.list.SetTitle(title)
+}
+
+// currentView returns the active saved view, or nil when none is selected
+func (d *Dashboard) currentView() *config.View {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.activeView
+}
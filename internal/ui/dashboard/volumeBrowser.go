@@ -0,0 +1,248 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"devops-dashboard/internal/docker"
+)
+
+// downloadDir is where downloaded files and exported reports are written:
+// download_dir, else ~/Downloads, else the working directory
+func (d *Dashboard) downloadDir() string {
+	if d.cfg.DownloadDir != "" {
+		return d.cfg.DownloadDir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		downloads := filepath.Join(home, "Downloads")
+		if info, err := os.Stat(downloads); err == nil && info.IsDir() {
+			return downloads
+		}
+	}
+	return "."
+}
+
+// parentDir returns the directory a volume path sits in, "" for the volume root
+func parentDir(p string) string {
+	if dir := path.Dir(p); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// showVolumeBrowser lists the files of a volume directory by directory. Enter opens a
+// directory, s downloads the selected file or directory; back is where ESC returns to.
+func (d *Dashboard) showVolumeBrowser(name string, back tview.Primitive) {
+	t := currentTheme()
+
+	header := tview.NewTextView().
+		SetDynamicColors(true)
+	header.SetText(fmt.Sprintf(" [%s]Opening volume %s...[-]", t.Muted, tview.Escape(name)))
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" 💾 %s ", name)).
+		SetBorderColor(tcell.GetColor(t.Info)).
+		SetBorderPadding(0, 0, 1, 1)
+
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	footer.SetText("[black:green] Enter [-:-:-] Open   [black:green] Backspace [-:-:-] Up   [black:green] s [-:-:-] Download   [black:red] ESC [-:-:-] Close")
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(header, 2, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	var (
+		browser   *docker.VolumeBrowser
+		files     []docker.VolumeFile
+		sizes     = make(map[string]int64) // directory -> size of everything below it
+		truncated bool
+		dir       string
+		shown     []docker.VolumeFile // rows below the header, after ".." outside the root
+	)
+	ctx, cancel := context.WithCancel(d.refreshCtx)
+
+	// render must be called on the UI goroutine
+	render := func() {
+		table.Clear()
+		for col, h := range []string{"NAME", "SIZE", "MODIFIED", "MODE"} {
+			table.SetCell(0, col, tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(t.Highlight)).
+				SetAttributes(tcell.AttrBold).
+				SetSelectable(false))
+		}
+
+		shown = nil
+		for _, f := range files {
+			if parentDir(f.Path) == dir {
+				shown = append(shown, f)
+			}
+		}
+		sort.SliceStable(shown, func(i, j int) bool {
+			if shown[i].Dir != shown[j].Dir {
+				return shown[i].Dir
+			}
+			return shown[i].Path < shown[j].Path
+		})
+
+		row := 1
+		if dir != "" {
+			table.SetCell(row, 0, tview.NewTableCell("📁 ..").SetTextColor(tcell.GetColor(t.Accent)))
+			row++
+		}
+		for _, f := range shown {
+			icon, color, size := "📄", tview.Styles.PrimaryTextColor, f.Size
+			if f.Dir {
+				icon, color, size = "📁", tcell.GetColor(t.Accent), sizes[f.Path]
+			}
+			table.SetCell(row, 0, tview.NewTableCell(icon+" "+tview.Escape(path.Base(f.Path))).SetTextColor(color).SetExpansion(1))
+			table.SetCell(row, 1, tview.NewTableCell(docker.FormatBytes(uint64(size))).SetTextColor(tcell.GetColor(sizeColor(size))).SetAlign(tview.AlignRight))
+			table.SetCell(row, 2, tview.NewTableCell(f.ModTime.Local().Format("2006-01-02 15:04")).SetTextColor(tcell.GetColor(t.Muted)))
+			table.SetCell(row, 3, tview.NewTableCell(f.Mode.String()).SetTextColor(tcell.GetColor(t.Muted)))
+			row++
+		}
+		if row == 1 {
+			table.SetCell(1, 0, tview.NewTableCell("(empty)").SetTextColor(tcell.GetColor(t.Muted)).SetSelectable(false))
+		}
+		table.Select(1, 0)
+
+		note := ""
+		if truncated {
+			note = fmt.Sprintf("   [%s]only the first %d entries are listed[-]", t.Warning, len(files))
+		}
+		header.SetText(fmt.Sprintf(" [%s::b]%s[-:-:-] via %s   %s total%s\n [%s]/%s[-]",
+			t.Accent, tview.Escape(name), tview.Escape(browser.Via), docker.FormatBytes(uint64(sizes[""])), note,
+			t.Highlight, tview.Escape(dir)))
+	}
+
+	// The loader below removes a helper container once the browser is closed
+	closeBrowser := func() {
+		cancel()
+		setRoot(d.app, d.mainFlex)
+		d.app.SetFocus(back)
+	}
+
+	// selected returns the file under the cursor; up is true on the ".." row
+	selected := func() (file docker.VolumeFile, up, ok bool) {
+		row, _ := table.GetSelection()
+		if dir != "" {
+			if row == 1 {
+				return docker.VolumeFile{}, true, true
+			}
+			row--
+		}
+		if row < 1 || row > len(shown) {
+			return docker.VolumeFile{}, false, false
+		}
+		return shown[row-1], false, true
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			closeBrowser()
+			return nil
+		case browser == nil || files == nil:
+			return event
+		case event.Key() == tcell.KeyEnter:
+			if f, up, ok := selected(); ok {
+				switch {
+				case up:
+					dir = parentDir(dir)
+					render()
+				case f.Dir:
+					dir = f.Path
+					render()
+				}
+			}
+			return nil
+		case event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2:
+			if dir != "" {
+				dir = parentDir(dir)
+				render()
+			}
+			return nil
+		case event.Rune() == 's':
+			f, up, ok := selected()
+			if !ok || up {
+				return nil
+			}
+			if d.cfg.Kiosk {
+				d.flashStatus(fmt.Sprintf("[%s]Downloads are disabled in kiosk mode[-]", t.Warning))
+				return nil
+			}
+			showToast(d.app, toastInfo, fmt.Sprintf("Downloading %s...", path.Base(f.Path)))
+			go func() {
+				dest, err := browser.Download(f, d.downloadDir())
+				d.app.QueueUpdateDraw(func() {
+					if err != nil {
+						showError(d.app, flex, "Download failed", err)
+						return
+					}
+					copyToClipboard(d.app, dest)
+					showToast(d.app, toastSuccess, fmt.Sprintf("Downloaded to %s (path copied)", dest))
+				})
+			}()
+			return nil
+		}
+		return event
+	})
+
+	setRoot(d.app, flex)
+	d.app.SetFocus(table)
+
+	go func() {
+		b, err := docker.OpenVolume(name)
+		if err != nil {
+			d.app.QueueUpdateDraw(func() {
+				header.SetText(fmt.Sprintf(" [%s]Can't open volume %s: %s[-]", t.Error, tview.Escape(name), tview.Escape(errorSummary(err))))
+			})
+			return
+		}
+		defer func() {
+			<-ctx.Done()
+			b.Close()
+		}()
+		d.app.QueueUpdateDraw(func() {
+			browser = b
+			header.SetText(fmt.Sprintf(" [%s]Reading volume %s via %s...[-]", t.Muted, tview.Escape(name), tview.Escape(b.Via)))
+		})
+
+		found, cut, err := b.Files(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		d.app.QueueUpdateDraw(func() {
+			if err != nil {
+				header.SetText(fmt.Sprintf(" [%s]Can't read volume %s: %s[-]", t.Error, tview.Escape(name), tview.Escape(errorSummary(err))))
+				return
+			}
+			files, truncated = found, cut
+			if files == nil {
+				files = []docker.VolumeFile{}
+			}
+			for _, f := range files {
+				for p := parentDir(f.Path); ; p = parentDir(p) {
+					sizes[p] += f.Size
+					if p == "" {
+						break
+					}
+				}
+			}
+			render()
+		})
+	}()
+}